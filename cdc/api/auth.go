@@ -0,0 +1,329 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"database/sql"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/soheilhy/cmux"
+)
+
+// apiRole is the role of a HTTP API user, a role has all the permissions
+// of the roles less than it.
+type apiRole int
+
+const (
+	apiRoleReadOnly apiRole = iota
+	apiRoleOperator
+	apiRoleAdmin
+)
+
+// String implements fmt.Stringer interface.
+func (r apiRole) String() string {
+	switch r {
+	case apiRoleReadOnly:
+		return config.APIRoleReadOnly
+	case apiRoleOperator:
+		return config.APIRoleOperator
+	default:
+		return config.APIRoleAdmin
+	}
+}
+
+func parseAPIRole(role string) apiRole {
+	switch role {
+	case config.APIRoleAdmin:
+		return apiRoleAdmin
+	case config.APIRoleOperator:
+		return apiRoleOperator
+	default:
+		return apiRoleReadOnly
+	}
+}
+
+const (
+	authMethodToken = "token"
+	authMethodCert  = "cert"
+	authMethodTiDB  = "tidb"
+	// authMethodForwarded means the caller is authenticated by the capture
+	// which forwards the request.
+	authMethodForwarded = "forwarded"
+
+	// forwardedUserHeader and forwardedRoleHeader carry the caller verified
+	// by the capture which forwards the request to another capture, they are
+	// only trusted if the request is sent with the certificate of a capture.
+	forwardedUserHeader = "TiCDC-Forwarded-User"
+	forwardedRoleHeader = "TiCDC-Forwarded-Role"
+
+	// apiUserKey is the key of the caller in the gin context.
+	apiUserKey = "ticdc-api-user"
+
+	// tidbAuthCacheTTL is how long a verified TiDB user and password is
+	// trusted without connecting to TiDB again.
+	tidbAuthCacheTTL = time.Minute
+	tidbAuthTimeout  = 5 * time.Second
+)
+
+// apiUser is an authenticated caller of the HTTP API.
+type apiUser struct {
+	name   string
+	role   apiRole
+	method string
+}
+
+// publicRoutes can be accessed without authentication,
+// they are used by health checks and monitoring.
+var publicRoutes = map[string]struct{}{
+	"/status":        {},
+	"/metrics":       {},
+	"/api/v1/health": {},
+	"/api/v1/status": {},
+	"/api/v2/health": {},
+	"/api/v2/status": {},
+}
+
+// adminRoutes are the route groups that only admins can access.
+var adminRoutes = []string{
+	"/api/v1/log",
+	"/api/v2/log",
+	"/api/v2/unsafe/",
+	"/admin/",
+	"/debug/",
+}
+
+// requiredRole returns the role required by a route,
+// false is returned if the route is public.
+func requiredRole(method, path string) (apiRole, bool) {
+	if _, ok := publicRoutes[path]; ok || strings.HasPrefix(path, "/swagger/") {
+		return apiRoleReadOnly, false
+	}
+	for _, prefix := range adminRoutes {
+		if strings.HasPrefix(path, prefix) {
+			return apiRoleAdmin, true
+		}
+	}
+	// it is a POST request but queries the changefeed only
+	if path == "/capture/owner/changefeed/query" {
+		return apiRoleReadOnly, true
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return apiRoleReadOnly, true
+	}
+	return apiRoleOperator, true
+}
+
+// tidbVerifier verifies the user and password by TiDB.
+type tidbVerifier func(ctx context.Context, user, password string) error
+
+// authenticator authenticates the callers of the HTTP API.
+type authenticator struct {
+	tokens          []*config.APITokenConfig
+	certCNRoles     map[string]apiRole
+	tidbUserRoles   map[string]apiRole
+	tidbDefaultRole apiRole
+	// verifyTiDBUser is nil if the TiDB authentication is disabled.
+	verifyTiDBUser tidbVerifier
+	// captureCN is the common name of the certificate of the captures, the
+	// callers forwarded by the requests with it are trusted. It is empty if
+	// TLS is disabled, in which case the callers are not forwarded.
+	captureCN string
+
+	mu sync.Mutex
+	// tidbAuthCache maps the user and the hash of the password to the
+	// expire time of the verification.
+	tidbAuthCache map[string]time.Time
+}
+
+// newAuthenticator creates an authenticator, the config must be validated.
+func newAuthenticator(cfg *config.APIAuthConfig, captureCN string) *authenticator {
+	a := &authenticator{
+		captureCN:       captureCN,
+		tokens:          cfg.Tokens,
+		certCNRoles:     make(map[string]apiRole, len(cfg.CertCNRoles)),
+		tidbUserRoles:   make(map[string]apiRole, len(cfg.TiDBUserRoles)),
+		tidbDefaultRole: parseAPIRole(cfg.TiDBDefaultRole),
+		tidbAuthCache:   make(map[string]time.Time),
+	}
+	for cn, role := range cfg.CertCNRoles {
+		a.certCNRoles[cn] = parseAPIRole(role)
+	}
+	for user, role := range cfg.TiDBUserRoles {
+		a.tidbUserRoles[user] = parseAPIRole(role)
+	}
+	if cfg.TiDBAddr != "" {
+		a.verifyTiDBUser = newTiDBVerifier(cfg.TiDBAddr)
+	}
+	return a
+}
+
+// authenticate returns the caller of the request. The requests forwarded by
+// the other captures are authorized by them, so the callers they verified are
+// returned as is.
+func (a *authenticator) authenticate(req *http.Request) (*apiUser, error) {
+	if user := a.forwardedUser(req); user != nil {
+		return user, nil
+	}
+
+	auth := req.Header.Get("Authorization")
+	if token := strings.TrimPrefix(auth, "Bearer "); token != auth {
+		for _, t := range a.tokens {
+			if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+				return &apiUser{name: t.User, role: parseAPIRole(t.Role), method: authMethodToken}, nil
+			}
+		}
+		return nil, cerror.ErrAPIUnauthorized.GenWithStackByArgs("invalid token")
+	}
+
+	if user, password, ok := req.BasicAuth(); ok {
+		if a.verifyTiDBUser == nil {
+			return nil, cerror.ErrAPIUnauthorized.GenWithStackByArgs("TiDB user authentication is disabled")
+		}
+		if err := a.verifyTiDBUserWithCache(req.Context(), user, password); err != nil {
+			return nil, cerror.ErrAPIUnauthorized.Wrap(err).GenWithStackByArgs("invalid TiDB user or password")
+		}
+		role, ok := a.tidbUserRoles[user]
+		if !ok {
+			role = a.tidbDefaultRole
+		}
+		return &apiUser{name: user, role: role, method: authMethodTiDB}, nil
+	}
+
+	if state := tlsConnectionState(req); state != nil && len(state.PeerCertificates) > 0 {
+		cn := state.PeerCertificates[0].Subject.CommonName
+		if role, ok := a.certCNRoles[cn]; ok {
+			return &apiUser{name: cn, role: role, method: authMethodCert}, nil
+		}
+	}
+	return nil, cerror.ErrAPIUnauthorized.GenWithStackByArgs("no valid credential is provided")
+}
+
+// forwardedUser returns the caller verified by the capture which forwards
+// the request, nil is returned if the request is not sent by a capture.
+func (a *authenticator) forwardedUser(req *http.Request) *apiUser {
+	name := req.Header.Get(forwardedUserHeader)
+	if name == "" || a.captureCN == "" {
+		return nil
+	}
+	state := tlsConnectionState(req)
+	if state == nil || len(state.PeerCertificates) == 0 ||
+		state.PeerCertificates[0].Subject.CommonName != a.captureCN {
+		return nil
+	}
+	return &apiUser{
+		name:   name,
+		role:   parseAPIRole(req.Header.Get(forwardedRoleHeader)),
+		method: authMethodForwarded,
+	}
+}
+
+// setForwardedUser sets the caller of the request in the headers of the
+// request forwarded to another capture, the headers sent by the caller are
+// removed since they are not verified.
+func setForwardedUser(c *gin.Context, header http.Header) {
+	header.Del(forwardedUserHeader)
+	header.Del(forwardedRoleHeader)
+	if value, ok := c.Get(apiUserKey); ok {
+		user := value.(*apiUser)
+		header.Set(forwardedUserHeader, user.name)
+		header.Set(forwardedRoleHeader, user.role.String())
+	}
+}
+
+func (a *authenticator) verifyTiDBUserWithCache(ctx context.Context, user, password string) error {
+	hash := sha256.Sum256([]byte(password))
+	key := user + "\x00" + string(hash[:])
+
+	a.mu.Lock()
+	expire, ok := a.tidbAuthCache[key]
+	a.mu.Unlock()
+	if ok && time.Now().Before(expire) {
+		return nil
+	}
+
+	if err := a.verifyTiDBUser(ctx, user, password); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	now := time.Now()
+	for k, expire := range a.tidbAuthCache {
+		if now.After(expire) {
+			delete(a.tidbAuthCache, k)
+		}
+	}
+	a.tidbAuthCache[key] = now.Add(tidbAuthCacheTTL)
+	a.mu.Unlock()
+	return nil
+}
+
+// newTiDBVerifier returns a tidbVerifier which connects to the TiDB
+// with the user and password.
+func newTiDBVerifier(addr string) tidbVerifier {
+	return func(ctx context.Context, user, password string) error {
+		dsn := dmysql.NewConfig()
+		dsn.User = user
+		dsn.Passwd = password
+		dsn.Net = "tcp"
+		dsn.Addr = addr
+		dsn.Timeout = tidbAuthTimeout
+		db, err := sql.Open("mysql", dsn.FormatDSN())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer db.Close()
+
+		ctx, cancel := context.WithTimeout(ctx, tidbAuthTimeout)
+		defer cancel()
+		return errors.Trace(db.PingContext(ctx))
+	}
+}
+
+type tlsConnKey struct{}
+
+// ConnContextWithTLS saves the TLS connection in the context, so that the
+// client certificate can be found even if the connection is wrapped by cmux.
+// It is used as the ConnContext of http.Server.
+func ConnContextWithTLS(ctx context.Context, c net.Conn) context.Context {
+	if mc, ok := c.(*cmux.MuxConn); ok {
+		c = mc.Conn
+	}
+	if tc, ok := c.(*tls.Conn); ok {
+		return context.WithValue(ctx, tlsConnKey{}, tc)
+	}
+	return ctx
+}
+
+func tlsConnectionState(req *http.Request) *tls.ConnectionState {
+	if req.TLS != nil {
+		return req.TLS
+	}
+	if tc, ok := req.Context().Value(tlsConnKey{}).(*tls.Conn); ok {
+		state := tc.ConnectionState()
+		return &state
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthRouter(auth *authenticator) *gin.Engine {
+	router := gin.New()
+	router.Use(authMiddleware(auth))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/status", ok)
+	router.GET("/api/v1/changefeeds", ok)
+	router.POST("/api/v1/changefeeds", ok)
+	router.POST("/capture/owner/changefeed/query", ok)
+	router.POST("/api/v2/unsafe/reset", ok)
+	return router
+}
+
+func TestRequiredRole(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		method string
+		path   string
+		role   apiRole
+		public bool
+	}{
+		{"GET", "/status", apiRoleReadOnly, true},
+		{"GET", "/metrics", apiRoleReadOnly, true},
+		{"GET", "/api/v2/health", apiRoleReadOnly, true},
+		{"GET", "/swagger/index.html", apiRoleReadOnly, true},
+		{"GET", "/api/v1/changefeeds", apiRoleReadOnly, false},
+		{"POST", "/capture/owner/changefeed/query", apiRoleReadOnly, false},
+		{"POST", "/api/v1/changefeeds", apiRoleOperator, false},
+		{"DELETE", "/api/v2/changefeeds/test", apiRoleOperator, false},
+		{"POST", "/api/v1/log", apiRoleAdmin, false},
+		{"GET", "/api/v2/unsafe/metadata", apiRoleAdmin, false},
+		{"POST", "/admin/log", apiRoleAdmin, false},
+		{"GET", "/debug/pprof/heap", apiRoleAdmin, false},
+	}
+	for _, tc := range testCases {
+		role, needAuth := requiredRole(tc.method, tc.path)
+		require.Equal(t, tc.public, !needAuth, tc.path)
+		require.Equal(t, tc.role, role, tc.path)
+	}
+}
+
+func TestAuthMiddlewareToken(t *testing.T) {
+	t.Parallel()
+	router := newAuthRouter(newAuthenticator(&config.APIAuthConfig{
+		Enable: true,
+		Tokens: []*config.APITokenConfig{
+			{Token: "reader-token", User: "reader", Role: config.APIRoleReadOnly},
+			{Token: "operator-token", User: "operator", Role: config.APIRoleOperator},
+		},
+	}, ""))
+
+	testCases := []struct {
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		{"GET", "/status", "", http.StatusOK},
+		{"GET", "/api/v1/changefeeds", "", http.StatusUnauthorized},
+		{"GET", "/api/v1/changefeeds", "invalid", http.StatusUnauthorized},
+		{"GET", "/api/v1/changefeeds", "reader-token", http.StatusOK},
+		{"POST", "/capture/owner/changefeed/query", "reader-token", http.StatusOK},
+		{"POST", "/api/v1/changefeeds", "reader-token", http.StatusForbidden},
+		{"POST", "/api/v1/changefeeds", "operator-token", http.StatusOK},
+		{"POST", "/api/v2/unsafe/reset", "operator-token", http.StatusForbidden},
+	}
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		router.ServeHTTP(w, req)
+		require.Equal(t, tc.code, w.Code, "%s %s %s", tc.method, tc.path, tc.token)
+
+		if tc.code == http.StatusUnauthorized || tc.code == http.StatusForbidden {
+			respErr := model.HTTPError{}
+			require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+			if tc.code == http.StatusUnauthorized {
+				require.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+				require.Equal(t, string(cerror.ErrAPIUnauthorized.RFCCode()), respErr.Code)
+			} else {
+				require.Equal(t, string(cerror.ErrAPIForbidden.RFCCode()), respErr.Code)
+			}
+		}
+	}
+}
+
+func TestAuthMiddlewareTiDBUser(t *testing.T) {
+	t.Parallel()
+	auth := newAuthenticator(&config.APIAuthConfig{
+		Enable:          true,
+		TiDBUserRoles:   map[string]string{"root": config.APIRoleAdmin},
+		TiDBDefaultRole: config.APIRoleReadOnly,
+	}, "")
+	router := newAuthRouter(auth)
+
+	// the TiDB authentication is disabled
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/changefeeds", nil)
+	req.SetBasicAuth("root", "123456")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	verified := 0
+	auth.verifyTiDBUser = func(_ context.Context, user, password string) error {
+		verified++
+		if password != "123456" {
+			return errors.New("access denied")
+		}
+		return nil
+	}
+	testCases := []struct {
+		method   string
+		path     string
+		user     string
+		password string
+		code     int
+	}{
+		{"GET", "/api/v1/changefeeds", "root", "wrong", http.StatusUnauthorized},
+		{"POST", "/api/v2/unsafe/reset", "root", "123456", http.StatusOK},
+		{"GET", "/api/v1/changefeeds", "test", "123456", http.StatusOK},
+		{"POST", "/api/v1/changefeeds", "test", "123456", http.StatusForbidden},
+	}
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+		req.SetBasicAuth(tc.user, tc.password)
+		router.ServeHTTP(w, req)
+		require.Equal(t, tc.code, w.Code, "%s %s %s", tc.method, tc.path, tc.user)
+	}
+	// the verified users are cached
+	require.Equal(t, 3, verified)
+}
+
+func TestAuthMiddlewareCert(t *testing.T) {
+	t.Parallel()
+	router := newAuthRouter(newAuthenticator(&config.APIAuthConfig{
+		Enable:      true,
+		CertCNRoles: map[string]string{"ticdc": config.APIRoleAdmin},
+	}, ""))
+
+	testCases := []struct {
+		cn   string
+		code int
+	}{
+		{"ticdc", http.StatusOK},
+		{"unknown", http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/v2/unsafe/reset", nil)
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: tc.cn}}},
+		}
+		router.ServeHTTP(w, req)
+		require.Equal(t, tc.code, w.Code, tc.cn)
+	}
+}
+
+func newCertState(cn string) *tls.ConnectionState {
+	return &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: cn}}},
+	}
+}
+
+func TestAuthMiddlewareForwarded(t *testing.T) {
+	t.Parallel()
+	cfg := &config.APIAuthConfig{
+		Enable: true,
+		CertCNRoles: map[string]string{
+			"viewer":   config.APIRoleReadOnly,
+			"operator": config.APIRoleOperator,
+		},
+	}
+
+	// The owner records the callers of the requests.
+	var ownerUser *apiUser
+	owner := gin.New()
+	owner.Use(authMiddleware(newAuthenticator(cfg, "ticdc")))
+	record := func(c *gin.Context) {
+		value, _ := c.Get(apiUserKey)
+		ownerUser = value.(*apiUser)
+		c.Status(http.StatusOK)
+	}
+	owner.GET("/api/v1/changefeeds", record)
+	owner.POST("/api/v1/changefeeds", record)
+	owner.POST("/api/v2/unsafe/reset", record)
+
+	// The capture forwards the requests to the owner with its certificate.
+	capture := gin.New()
+	capture.Use(authMiddleware(newAuthenticator(cfg, "ticdc")))
+	forward := func(c *gin.Context) {
+		req, _ := http.NewRequest(c.Request.Method, c.Request.URL.Path, nil)
+		req.Header = c.Request.Header.Clone()
+		setForwardedUser(c, req.Header)
+		req.TLS = newCertState("ticdc")
+		w := httptest.NewRecorder()
+		owner.ServeHTTP(w, req)
+		c.Status(w.Code)
+	}
+	capture.GET("/api/v1/changefeeds", forward)
+	capture.POST("/api/v1/changefeeds", forward)
+
+	testCases := []struct {
+		cn     string
+		method string
+		code   int
+		role   apiRole
+	}{
+		{"operator", "POST", http.StatusOK, apiRoleOperator},
+		{"viewer", "GET", http.StatusOK, apiRoleReadOnly},
+		{"viewer", "POST", http.StatusForbidden, 0},
+	}
+	for _, tc := range testCases {
+		ownerUser = nil
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, "/api/v1/changefeeds", nil)
+		req.TLS = newCertState(tc.cn)
+		// the forwarded caller sent by the caller itself is not trusted
+		req.Header.Set(forwardedUserHeader, "root")
+		req.Header.Set(forwardedRoleHeader, config.APIRoleAdmin)
+		capture.ServeHTTP(w, req)
+		require.Equal(t, tc.code, w.Code, tc.cn)
+		if tc.code != http.StatusOK {
+			require.Nil(t, ownerUser)
+			continue
+		}
+		require.Equal(t, &apiUser{name: tc.cn, role: tc.role, method: authMethodForwarded}, ownerUser)
+	}
+
+	// The forwarded callers keep their roles at the owner.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v2/unsafe/reset", nil)
+	req.TLS = newCertState("ticdc")
+	req.Header.Set(forwardedUserHeader, "operator")
+	req.Header.Set(forwardedRoleHeader, config.APIRoleOperator)
+	owner.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// The forwarded callers are only trusted from the captures,
+	// and the certificate of the captures has no role itself.
+	for _, cn := range []string{"viewer", "ticdc"} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/api/v1/changefeeds", nil)
+		req.TLS = newCertState(cn)
+		if cn == "viewer" {
+			req.Header.Set(forwardedUserHeader, "root")
+			req.Header.Set(forwardedRoleHeader, config.APIRoleAdmin)
+		}
+		owner.ServeHTTP(w, req)
+		require.NotEqual(t, http.StatusOK, w.Code, cn)
+	}
+}
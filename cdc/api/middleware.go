@@ -20,6 +20,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
 	"go.uber.org/zap"
 )
 
@@ -70,3 +73,91 @@ func errorHandleMiddleware() gin.HandlerFunc {
 		}
 	}
 }
+
+// errorHandleMiddlewareV2 puts the error into the response of the v2 APIs.
+// Unlike the v1 APIs, the error code is always one of the codes in
+// errors.toml, and the not found errors are distinguished.
+func errorHandleMiddlewareV2() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		lastError := c.Errors.Last()
+		if lastError != nil {
+			err := lastError.Err
+			httpErr := model.NewHTTPError(err)
+			if httpErr.Code == "" {
+				httpErr.Code = string(cerror.ErrInternalServerError.RFCCode())
+			}
+			c.IndentedJSON(httpStatusCodeV2(err), httpErr)
+			c.Abort()
+			return
+		}
+	}
+}
+
+// AuthMiddleware authenticates the caller of every request and checks whether
+// its role is allowed to access the route. The mutating calls are recorded in
+// the audit log with the caller identity. The callers of the requests
+// forwarded by the other captures, which are sent with the certificate in
+// credential, are authorized by the forwarding capture only.
+func AuthMiddleware(cfg *config.APIAuthConfig, credential *security.Credential) gin.HandlerFunc {
+	captureCN, err := credential.SelfCommonName()
+	if err != nil {
+		// The forwarded requests are rejected unless their callers can be
+		// authenticated by their own credentials.
+		log.Warn("failed to read the common name of the capture certificate, "+
+			"the callers of the forwarded requests are not trusted", zap.Error(err))
+	}
+	return authMiddleware(newAuthenticator(cfg, captureCN))
+}
+
+func authMiddleware(auth *authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		required, ok := requiredRole(c.Request.Method, path)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		user, err := auth.authenticate(c.Request)
+		if err != nil {
+			log.Warn("unauthorized HTTP API request",
+				zap.String("method", c.Request.Method),
+				zap.String("path", path),
+				zap.String("ip", c.ClientIP()),
+				zap.Error(err))
+			c.Header("WWW-Authenticate", `Bearer, Basic realm="TiCDC"`)
+			c.IndentedJSON(http.StatusUnauthorized, model.NewHTTPError(err))
+			c.Abort()
+			return
+		}
+		if user.role < required {
+			err := cerror.ErrAPIForbidden.GenWithStackByArgs(
+				user.name, user.role, c.Request.Method, path)
+			log.Warn("forbidden HTTP API request",
+				zap.String("user", user.name),
+				zap.Stringer("role", user.role),
+				zap.String("method", c.Request.Method),
+				zap.String("path", path),
+				zap.String("ip", c.ClientIP()))
+			c.IndentedJSON(http.StatusForbidden, model.NewHTTPError(err))
+			c.Abort()
+			return
+		}
+
+		c.Set(apiUserKey, user)
+		c.Next()
+
+		if required > apiRoleReadOnly {
+			log.Info("[audit] HTTP API request",
+				zap.String("user", user.name),
+				zap.Stringer("role", user.role),
+				zap.String("authMethod", user.method),
+				zap.String("method", c.Request.Method),
+				zap.String("path", path),
+				zap.String("query", c.Request.URL.RawQuery),
+				zap.String("ip", c.ClientIP()),
+				zap.Int("status", c.Writer.Status()))
+		}
+	}
+}
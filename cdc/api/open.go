@@ -15,10 +15,18 @@ package api
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/httputil"
 	"github.com/pingcap/tiflow/cdc/capture"
@@ -39,8 +47,14 @@ const (
 	apiOpVarChangefeedID = "changefeed_id"
 	// apiOpVarCaptureID is the key of capture ID in HTTP API
 	apiOpVarCaptureID = "capture_id"
+	// apiOpVarLocal is the key of querying the local status of a capture in HTTP API
+	apiOpVarLocal = "local"
 	// forWardFromCapture is a header to be set when a request is forwarded from another capture
 	forWardFromCapture = "TiCDC-ForwardFromCapture"
+
+	// queryTableStatusTimeout is the timeout of querying the tables
+	// replicated by a capture.
+	queryTableStatusTimeout = 10 * time.Second
 )
 
 // openAPI provides capture APIs.
@@ -88,8 +102,10 @@ func RegisterOpenAPIRoutes(router *gin.Engine, api openAPI) {
 	changefeedGroup.POST("/:changefeed_id/pause", api.PauseChangefeed)
 	changefeedGroup.POST("/:changefeed_id/resume", api.ResumeChangefeed)
 	changefeedGroup.DELETE("/:changefeed_id", api.RemoveChangefeed)
+	changefeedGroup.GET("/:changefeed_id/tables", api.ListTables)
 	changefeedGroup.POST("/:changefeed_id/tables/rebalance_table", api.RebalanceTables)
 	changefeedGroup.POST("/:changefeed_id/tables/move_table", api.MoveTable)
+	changefeedGroup.POST("/:changefeed_id/schema_bootstrap", api.BootstrapSchema)
 
 	// owner API
 	ownerGroup := v1.Group("/owner")
@@ -292,6 +308,7 @@ func (h *openAPI) CreateChangefeed(c *gin.Context) {
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router /api/v1/changefeeds/{changefeed_id}/pause [post]
+// @Router /api/v2/changefeeds/{changefeed_id}/pause [post]
 func (h *openAPI) PauseChangefeed(c *gin.Context) {
 	if !h.capture.IsOwner() {
 		h.forwardToOwner(c)
@@ -334,6 +351,7 @@ func (h *openAPI) PauseChangefeed(c *gin.Context) {
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v1/changefeeds/{changefeed_id}/resume [post]
+// @Router	/api/v2/changefeeds/{changefeed_id}/resume [post]
 func (h *openAPI) ResumeChangefeed(c *gin.Context) {
 	if !h.capture.IsOwner() {
 		h.forwardToOwner(c)
@@ -417,6 +435,12 @@ func (h *openAPI) UpdateChangefeed(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
+	if !reflect.DeepEqual(info.Config.Sink, newInfo.Config.Sink) {
+		if err := VerifyDispatchColumns(newInfo.Config, h.capture.Storage); err != nil {
+			_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err))
+			return
+		}
+	}
 
 	err = h.capture.EtcdClient.SaveChangeFeedInfo(ctx, newInfo, changefeedID)
 	if err != nil {
@@ -478,6 +502,7 @@ func (h *openAPI) RemoveChangefeed(c *gin.Context) {
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router /api/v1/changefeeds/{changefeed_id}/tables/rebalance_table [post]
+// @Router /api/v2/changefeeds/{changefeed_id}/tables/rebalance_table [post]
 func (h *openAPI) RebalanceTables(c *gin.Context) {
 	if !h.capture.IsOwner() {
 		h.forwardToOwner(c)
@@ -505,6 +530,44 @@ func (h *openAPI) RebalanceTables(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
+// BootstrapSchema publishes the schemas of the tables of a changefeed
+// @Summary Publish schema bootstrap
+// @Description publish a CREATE TABLE DDL event for every table of a changefeed at its checkpoint, the events are published once the changefeed is running
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Success 202
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/schema_bootstrap [post]
+// @Router /api/v2/changefeeds/{changefeed_id}/schema_bootstrap [post]
+func (h *openAPI) BootstrapSchema(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+	// check if the changefeed exists
+	_, err := h.statusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := handleOwnerSchemaBootstrap(ctx, h.capture, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
 // MoveTable moves a table to target capture
 // @Summary move table
 // @Description move one table to the target capture
@@ -517,6 +580,7 @@ func (h *openAPI) RebalanceTables(c *gin.Context) {
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router /api/v1/changefeeds/{changefeed_id}/tables/move_table [post]
+// @Router /api/v2/changefeeds/{changefeed_id}/tables/move_table [post]
 func (h *openAPI) MoveTable(c *gin.Context) {
 	if !h.capture.IsOwner() {
 		h.forwardToOwner(c)
@@ -560,6 +624,149 @@ func (h *openAPI) MoveTable(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
+// ListTables lists the replication status of all tables of a changefeed
+// @Summary List tables of a changefeed
+// @Description list the replication status of all tables of a changefeed, including the owning capture, the resolved ts of every stage in the table pipeline, the checkpoint ts and the lag
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Success 200 {object} model.TableStatusList
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/tables [get]
+// @Router /api/v2/changefeeds/{changefeed_id}/tables [get]
+func (h *openAPI) ListTables(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	// The owner collects the tables from every capture by a local query,
+	// which only returns the tables replicated by the capture itself.
+	if c.Query(apiOpVarLocal) == "true" {
+		statuses, err := h.capture.GetTableStatuses(ctx, changefeedID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		if statuses == nil {
+			statuses = make([]*model.TableStatus, 0)
+		}
+		c.IndentedJSON(http.StatusOK, statuses)
+		return
+	}
+
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	_, err := h.statusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	captures, err := h.statusProvider().GetCaptures(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Query the captures concurrently, a capture which fails or does not
+	// respond in time is reported in the result instead of failing the
+	// whole request.
+	results := make([][]*model.TableStatus, len(captures))
+	errs := make([]error, len(captures))
+	selfID := h.capture.Info().ID
+	var wg sync.WaitGroup
+	for i, capture := range captures {
+		wg.Add(1)
+		go func(i int, capture *model.CaptureInfo) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, queryTableStatusTimeout)
+			defer cancel()
+			if capture.ID == selfID {
+				results[i], errs[i] = h.capture.GetTableStatuses(ctx, changefeedID)
+			} else {
+				results[i], errs[i] = queryCaptureTableStatuses(ctx, c, capture, changefeedID)
+			}
+		}(i, capture)
+	}
+	wg.Wait()
+
+	resp := &model.TableStatusList{Tables: make([]*model.TableStatus, 0)}
+	for i, capture := range captures {
+		if errs[i] != nil {
+			log.Warn("query table statuses from capture failed",
+				zap.String("changefeed", changefeedID),
+				zap.String("capture", capture.ID), zap.Error(errs[i]))
+			resp.CaptureErrors = append(resp.CaptureErrors, &model.CaptureError{
+				CaptureID: capture.ID,
+				Error:     errs[i].Error(),
+			})
+			continue
+		}
+		resp.Tables = append(resp.Tables, results[i]...)
+	}
+
+	physical, _, err := h.capture.PDClient.GetTS(ctx)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrPDEtcdAPIError, err))
+		return
+	}
+	for _, status := range resp.Tables {
+		status.Lag = physical - oracle.ExtractPhysical(status.CheckpointTs)
+	}
+	sort.Slice(resp.Tables, func(i, j int) bool {
+		return resp.Tables[i].TableID < resp.Tables[j].TableID
+	})
+	c.IndentedJSON(http.StatusOK, resp)
+}
+
+// queryCaptureTableStatuses queries the tables of a changefeed replicated
+// by the given capture.
+func queryCaptureTableStatuses(
+	ctx context.Context, c *gin.Context, capture *model.CaptureInfo, changefeedID model.ChangeFeedID,
+) ([]*model.TableStatus, error) {
+	tlsConfig, err := config.GetGlobalServerConfig().Security.ToTLSConfigWithVerify()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s/api/v1/changefeeds/%s/tables?%s=true",
+		scheme, capture.AdvertiseAddr, changefeedID, apiOpVarLocal)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// keep the credential of the caller
+	req.Header = c.Request.Header.Clone()
+	setForwardedUser(c, req.Header)
+
+	resp, err := httputil.NewClient(tlsConfig).Do(req)
+	if err != nil {
+		return nil, cerror.ErrQueryTableStatus.Wrap(err).GenWithStackByArgs(capture.ID, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var httpErr model.HTTPError
+		if err := json.NewDecoder(resp.Body).Decode(&httpErr); err != nil {
+			httpErr.Error = resp.Status
+		}
+		return nil, cerror.ErrQueryTableStatus.GenWithStackByArgs(capture.ID, httpErr.Error)
+	}
+	var statuses []*model.TableStatus
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, cerror.ErrQueryTableStatus.Wrap(err).GenWithStackByArgs(capture.ID, err.Error())
+	}
+	return statuses, nil
+}
+
 // ResignOwner makes the current owner resign
 // @Summary notify the owner to resign
 // @Description notify the current owner to resign
@@ -569,6 +776,7 @@ func (h *openAPI) MoveTable(c *gin.Context) {
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v1/owner/resign [post]
+// @Router	/api/v2/owner/resign [post]
 func (h *openAPI) ResignOwner(c *gin.Context) {
 	if !h.capture.IsOwner() {
 		h.forwardToOwner(c)
@@ -592,6 +800,7 @@ func (h *openAPI) ResignOwner(c *gin.Context) {
 // @Success 200 {object} model.ProcessorDetail
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v1/processors/{changefeed_id}/{capture_id} [get]
+// @Router	/api/v2/processors/{changefeed_id}/{capture_id} [get]
 func (h *openAPI) GetProcessor(c *gin.Context) {
 	if !h.capture.IsOwner() {
 		h.forwardToOwner(c)
@@ -719,7 +928,9 @@ func (h *openAPI) ListCapture(c *gin.Context) {
 	for _, c := range captureInfos {
 		isOwner := c.ID == ownerID
 		captures = append(captures,
-			&model.Capture{ID: c.ID, IsOwner: isOwner, AdvertiseAddr: c.AdvertiseAddr})
+			&model.Capture{
+				ID: c.ID, IsOwner: isOwner, AdvertiseAddr: c.AdvertiseAddr, Labels: c.Labels,
+			})
 	}
 
 	c.IndentedJSON(http.StatusOK, captures)
@@ -734,6 +945,7 @@ func (h *openAPI) ListCapture(c *gin.Context) {
 // @Success 200 {object} model.ServerStatus
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v1/status [get]
+// @Router	/api/v2/status [get]
 func (h *openAPI) ServerStatus(c *gin.Context) {
 	status := model.ServerStatus{
 		Version: version.ReleaseVersion,
@@ -754,6 +966,7 @@ func (h *openAPI) ServerStatus(c *gin.Context) {
 // @Success 200
 // @Failure 500 {object} model.HTTPError
 // @Router	/api/v1/health [get]
+// @Router	/api/v2/health [get]
 func (h *openAPI) Health(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -774,6 +987,7 @@ func (h *openAPI) Health(c *gin.Context) {
 // @Success 200
 // @Failure 400 {object} model.HTTPError
 // @Router	/api/v1/log [post]
+// @Router	/api/v2/log [post]
 func SetLogLevel(c *gin.Context) {
 	// get json data from request body
 	data := struct {
@@ -832,6 +1046,7 @@ func (h *openAPI) forwardToOwner(c *gin.Context) {
 			req.Header.Add(k, vv)
 		}
 	}
+	setForwardedUser(c, req.Header)
 
 	// forward to owner
 	cli := httputil.NewClient(tslConfig)
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -31,6 +32,8 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+	pd "github.com/tikv/pd/client"
 	"go.uber.org/zap"
 )
 
@@ -121,8 +124,8 @@ func newStatusProvider() *mockStatusProvider {
 
 	statusProvider.On("GetAllChangeFeedInfo", mock.Anything).
 		Return(map[model.ChangeFeedID]*model.ChangeFeedInfo{
-			changeFeedID + "1": {State: model.StateNormal},
-			changeFeedID + "2": {State: model.StateStopped},
+			changeFeedID + "1": {State: model.StateNormal, Labels: map[string]string{"env": "prod"}},
+			changeFeedID + "2": {State: model.StateStopped, Labels: map[string]string{"env": "test"}},
 		}, nil)
 
 	statusProvider.On("GetAllTaskStatuses", mock.Anything).
@@ -419,6 +422,44 @@ func TestRebalanceTables(t *testing.T) {
 	require.Contains(t, respErr.Error, "changefeed not exists")
 }
 
+func TestBootstrapSchema(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouter(cp, newStatusProvider())
+
+	// test schema bootstrap succeeded
+	mo.EXPECT().
+		BootstrapSchema(gomock.Any(), gomock.Any()).
+		Do(func(cfID model.ChangeFeedID, done chan<- error) {
+			require.EqualValues(t, cfID, changeFeedID)
+			close(done)
+		})
+	api := testCase{
+		url:    fmt.Sprintf("/api/v1/changefeeds/%s/schema_bootstrap", changeFeedID),
+		method: "POST",
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 202, w.Code)
+
+	// test schema bootstrap of a non-existent changefeed
+	api = testCase{
+		url:    fmt.Sprintf("/api/v1/changefeeds/%s/schema_bootstrap", nonExistChangefeedID),
+		method: "POST",
+	}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	respErr := model.HTTPError{}
+	err := json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Error, "changefeed not exists")
+}
+
 func TestMoveTable(t *testing.T) {
 	t.Parallel()
 
@@ -664,3 +705,90 @@ func TestSetLogLevel(t *testing.T) {
 func TestCreateChangefeed(t *testing.T) {}
 func TestUpdateChangefeed(t *testing.T) {}
 func TestHealth(t *testing.T)           {}
+
+type mockPDClient struct {
+	pd.Client
+	physical int64
+}
+
+func (c *mockPDClient) GetTS(ctx context.Context) (int64, int64, error) {
+	return c.physical, 0, nil
+}
+
+func TestListTables(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	cp.PDClient = &mockPDClient{physical: 1000}
+
+	// a remote capture replicating two tables
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, fmt.Sprintf("/api/v1/changefeeds/%s/tables", changeFeedID), r.URL.Path)
+		require.Equal(t, "true", r.URL.Query().Get("local"))
+		require.Equal(t, "Bearer test", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode([]*model.TableStatus{
+			{TableID: 3, CaptureID: captureID, CheckpointTs: oracle.ComposeTS(400, 0)},
+			{TableID: 1, CaptureID: captureID, CheckpointTs: oracle.ComposeTS(900, 0)},
+		})
+	}))
+	defer remote.Close()
+	// a capture which is unreachable
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	statusProvider := newStatusProvider()
+	statusProvider.ExpectedCalls = nil
+	statusProvider.On("GetChangeFeedStatus", mock.Anything, changeFeedID).
+		Return(&model.ChangeFeedStatus{CheckpointTs: 1}, nil)
+	statusProvider.On("GetChangeFeedStatus", mock.Anything, nonExistChangefeedID).
+		Return(new(model.ChangeFeedStatus),
+			cerror.ErrChangeFeedNotExists.GenWithStackByArgs(nonExistChangefeedID))
+	statusProvider.On("GetCaptures", mock.Anything).Return([]*model.CaptureInfo{
+		{ID: cp.Info().ID},
+		{ID: captureID, AdvertiseAddr: strings.TrimPrefix(remote.URL, "http://")},
+		{ID: "unreachable-capture", AdvertiseAddr: strings.TrimPrefix(unreachable.URL, "http://")},
+	}, nil)
+	router := newRouter(cp, statusProvider)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/v1/changefeeds/%s/tables", changeFeedID), nil)
+	req.Header.Set("Authorization", "Bearer test")
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	// the tables of the reachable captures are returned, and the
+	// unreachable capture is reported
+	var resp model.TableStatusList
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Tables, 2)
+	require.Equal(t, int64(1), resp.Tables[0].TableID)
+	require.Equal(t, int64(100), resp.Tables[0].Lag)
+	require.Equal(t, int64(3), resp.Tables[1].TableID)
+	require.Equal(t, int64(600), resp.Tables[1].Lag)
+	require.Len(t, resp.CaptureErrors, 1)
+	require.Equal(t, "unreachable-capture", resp.CaptureErrors[0].CaptureID)
+	require.Contains(t, resp.CaptureErrors[0].Error, "unreachable-capture")
+
+	// test list the tables replicated by the capture itself
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/api/v1/changefeeds/%s/tables?local=true", changeFeedID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	var statuses []model.TableStatus
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&statuses))
+	require.Len(t, statuses, 0)
+
+	// test list the tables of a changefeed that does not exist
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/api/v1/changefeeds/%s/tables", nonExistChangefeedID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Error, "changefeed not exists")
+
+	// test list the tables with an invalid changefeed id
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/changefeeds/a@b/tables", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+}
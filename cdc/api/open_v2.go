@@ -0,0 +1,893 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/owner"
+	"github.com/pingcap/tiflow/cdc/redo"
+	"github.com/pingcap/tiflow/pkg/applier"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const (
+	// apiOpVarPage is the key of the page number in HTTP API v2, starting from 1.
+	apiOpVarPage = "page"
+	// apiOpVarPageSize is the key of the page size in HTTP API v2.
+	apiOpVarPageSize = "page_size"
+	// apiOpVarDryRun is the key of the dry run flag in HTTP API v2,
+	// the changefeed config is only verified if it is true.
+	apiOpVarDryRun = "dry_run"
+	// apiOpVarForce is the key of the force remove flag in HTTP API v2.
+	apiOpVarForce = "force"
+	// apiOpVarLabelSelector is the key of the label selector in HTTP API v2.
+	apiOpVarLabelSelector = "label_selector"
+
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// openAPIV2 provides the v2 capture APIs.
+type openAPIV2 struct {
+	openAPI
+}
+
+// NewOpenAPIV2 creates a new openAPIV2.
+func NewOpenAPIV2(c *capture.Capture) openAPIV2 {
+	return openAPIV2{openAPI: NewOpenAPI(c)}
+}
+
+// NewOpenAPIV2ForTest return a openAPIV2 for test
+func NewOpenAPIV2ForTest(c *capture.Capture, p owner.StatusProvider) openAPIV2 {
+	return openAPIV2{openAPI: NewOpenAPI4Test(c, p)}
+}
+
+// RegisterOpenAPIV2Routes registers routes for OpenAPI v2
+func RegisterOpenAPIV2Routes(router *gin.Engine, api openAPIV2) {
+	v2 := router.Group("/api/v2")
+
+	v2.Use(logMiddleware())
+	v2.Use(errorHandleMiddlewareV2())
+
+	// common API
+	v2.GET("/status", api.ServerStatus)
+	v2.GET("/health", api.Health)
+	v2.POST("/log", SetLogLevel)
+	v2.GET("/tso", api.QueryTso)
+
+	// changefeed API
+	changefeedGroup := v2.Group("/changefeeds")
+	changefeedGroup.GET("", api.ListChangefeeds)
+	changefeedGroup.GET("/:changefeed_id", api.GetChangefeedV2)
+	changefeedGroup.POST("", api.CreateChangefeedV2)
+	changefeedGroup.PUT("/:changefeed_id", api.UpdateChangefeedV2)
+	changefeedGroup.POST("/:changefeed_id/pause", api.PauseChangefeed)
+	changefeedGroup.POST("/:changefeed_id/resume", api.ResumeChangefeed)
+	changefeedGroup.DELETE("/:changefeed_id", api.RemoveChangefeedV2)
+	changefeedGroup.GET("/:changefeed_id/statistics", api.GetChangefeedStatistics)
+	changefeedGroup.GET("/:changefeed_id/redo_meta", api.GetRedoMeta)
+	changefeedGroup.GET("/:changefeed_id/tables", api.ListTables)
+	changefeedGroup.POST("/:changefeed_id/tables/rebalance_table", api.RebalanceTables)
+	changefeedGroup.POST("/:changefeed_id/tables/move_table", api.MoveTable)
+	changefeedGroup.POST("/:changefeed_id/schema_bootstrap", api.BootstrapSchema)
+
+	// changefeed template API
+	templateGroup := v2.Group("/changefeed_templates")
+	templateGroup.GET("", api.ListChangefeedTemplates)
+	templateGroup.GET("/:template_name", api.GetChangefeedTemplate)
+	templateGroup.POST("", api.CreateChangefeedTemplate)
+	templateGroup.PUT("/:template_name", api.UpdateChangefeedTemplate)
+	templateGroup.DELETE("/:template_name", api.DeleteChangefeedTemplate)
+
+	// batch changefeed API
+	batchGroup := v2.Group("/changefeed_batch")
+	batchGroup.POST("/pause", api.BatchPauseChangefeeds)
+	batchGroup.POST("/resume", api.BatchResumeChangefeeds)
+	batchGroup.POST("/remove", api.BatchRemoveChangefeeds)
+
+	// owner API
+	ownerGroup := v2.Group("/owner")
+	ownerGroup.POST("/resign", api.ResignOwner)
+
+	// processor API
+	processorGroup := v2.Group("/processors")
+	processorGroup.GET("", api.ListProcessors)
+	processorGroup.GET("/:changefeed_id/:capture_id", api.GetProcessor)
+
+	// capture API
+	captureGroup := v2.Group("/captures")
+	captureGroup.GET("", api.ListCaptures)
+	captureGroup.PUT("/:capture_id/drain", api.DrainCapture)
+
+	// unsafe API
+	unsafeGroup := v2.Group("/unsafe")
+	unsafeGroup.GET("/metadata", api.ShowMetadata)
+	unsafeGroup.POST("/reset", api.ResetMetadata)
+	unsafeGroup.DELETE("/service_gc_safepoint", api.DeleteServiceGCSafepoint)
+	unsafeGroup.POST("/resolve_lock", api.ResolveLock)
+}
+
+// ListChangefeeds lists the changefeeds in cdc cluster page by page
+// @Summary List changefeeds
+// @Description list the changefeeds in cdc cluster page by page, ordered by the changefeed id
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param state query string false "state"
+// @Param label_selector query string false "only list the changefeeds whose labels match the selector, such as env=prod,team!=dba"
+// @Param page query integer false "page number, starting from 1"
+// @Param page_size query integer false "page size, 100 by default"
+// @Success 200 {object} model.ChangefeedListV2
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds [get]
+func (h *openAPIV2) ListChangefeeds(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	state := c.Query(apiOpVarChangefeedState)
+	start, limit, err := getPagination(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var selector model.LabelSelector
+	if value := c.Query(apiOpVarLabelSelector); value != "" {
+		if selector, err = model.ParseLabelSelector(value); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	statuses, err := h.statusProvider().GetAllChangeFeedStatuses(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	infos, err := h.statusProvider().GetAllChangeFeedInfo(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	items := make([]model.ChangefeedCommonInfo, 0, len(statuses))
+	for cfID, cfStatus := range statuses {
+		cfInfo, exist := infos[cfID]
+		if !exist || !cfInfo.State.IsNeeded(state) || !selector.Matches(cfInfo.Labels) {
+			continue
+		}
+		item := model.ChangefeedCommonInfo{
+			ID:           cfID,
+			FeedState:    cfInfo.State,
+			RunningError: cfInfo.Error,
+			Labels:       cfInfo.Labels,
+		}
+		if cfStatus != nil {
+			item.CheckpointTSO = cfStatus.CheckpointTs
+			item.CheckpointTime = model.JSONTime(oracle.GetTimeFromTS(cfStatus.CheckpointTs))
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	from, to := paginate(len(items), start, limit)
+	c.IndentedJSON(http.StatusOK, &model.ChangefeedListV2{
+		Total: len(items),
+		Items: items[from:to],
+	})
+}
+
+// GetChangefeedV2 gets the full information of a changefeed
+// @Summary Get changefeed
+// @Description get the info, status and task status of a changefeed
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Success 200 {object} model.ChangefeedDetailV2
+// @Failure 500,404,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id} [get]
+func (h *openAPIV2) GetChangefeedV2(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID, err := getChangefeedIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	info, err := h.statusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	status, err := h.statusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	positions, err := h.statusProvider().GetTaskPositions(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	taskStatuses, err := h.statusProvider().GetAllTaskStatuses(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	detail := &model.ChangefeedDetailV2{
+		ID:         changefeedID,
+		Info:       info,
+		Status:     status,
+		TaskStatus: make([]model.CaptureTaskStatus, 0, len(taskStatuses)),
+	}
+	for _, position := range positions {
+		detail.Count += position.Count
+	}
+	for captureID, taskStatus := range taskStatuses {
+		tables := make([]int64, 0, len(taskStatus.Tables))
+		for tableID := range taskStatus.Tables {
+			tables = append(tables, tableID)
+		}
+		detail.TaskStatus = append(detail.TaskStatus, model.CaptureTaskStatus{
+			CaptureID: captureID, Tables: tables, Operation: taskStatus.Operation,
+		})
+	}
+	sort.Slice(detail.TaskStatus, func(i, j int) bool {
+		return detail.TaskStatus[i].CaptureID < detail.TaskStatus[j].CaptureID
+	})
+	c.IndentedJSON(http.StatusOK, detail)
+}
+
+// CreateChangefeedV2 creates a changefeed
+// @Summary Create changefeed
+// @Description create a new changefeed, or only verify the config if dry_run is true.
+// @Description If a template is given, the non-zero fields of the config override the ones of the template.
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed body model.ChangefeedConfigV2 true "changefeed config"
+// @Param dry_run query boolean false "only verify the changefeed config"
+// @Success 200,201 {object} model.ChangefeedDetailV2
+// @Failure 500,409,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds [post]
+func (h *openAPIV2) CreateChangefeedV2(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	dryRun, err := getBoolQuery(c, apiOpVarDryRun)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// The body is decoded twice, the first time is to find the template.
+	var changefeedConfig model.ChangefeedConfigV2
+	if err := c.ShouldBindBodyWith(&changefeedConfig, binding.JSON); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+	// Fill the replica config with the one of the template or the default
+	// values, so that the request only needs to carry the changed fields.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	var tpl *model.ChangefeedTemplate
+	if changefeedConfig.Template != "" {
+		tpl, err = h.capture.EtcdClient.GetChangefeedTemplate(ctx, changefeedConfig.Template)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		if tpl.ReplicaConfig != nil {
+			replicaConfig = tpl.ReplicaConfig.Clone()
+		}
+	}
+	changefeedConfig = model.ChangefeedConfigV2{ReplicaConfig: replicaConfig}
+	if err := c.ShouldBindBodyWith(&changefeedConfig, binding.JSON); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+	if tpl != nil {
+		tpl.ApplyTo(&changefeedConfig)
+	}
+
+	info, ineligibleTables, err := verifyCreateChangefeedConfigV2(ctx, &changefeedConfig, h.capture, dryRun)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	detail := &model.ChangefeedDetailV2{
+		ID:               changefeedConfig.ID,
+		Info:             info,
+		IneligibleTables: ineligibleTables,
+	}
+	if dryRun {
+		c.IndentedJSON(http.StatusOK, detail)
+		return
+	}
+
+	err = h.capture.EtcdClient.CreateChangefeedInfo(ctx, info, changefeedConfig.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	infoStr, err := info.Marshal()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("Create changefeed successfully!",
+		zap.String("id", changefeedConfig.ID), zap.String("changefeed", infoStr))
+	c.IndentedJSON(http.StatusCreated, detail)
+}
+
+// UpdateChangefeedV2 updates a changefeed
+// @Summary Update a changefeed
+// @Description update a stopped changefeed, or only verify the config if dry_run is true
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param changefeed body model.ChangefeedConfigV2 true "changefeed config"
+// @Param dry_run query boolean false "only verify the changefeed config"
+// @Success 200 {object} model.ChangefeedDetailV2
+// @Failure 500,404,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id} [put]
+func (h *openAPIV2) UpdateChangefeedV2(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID, err := getChangefeedIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	dryRun, err := getBoolQuery(c, apiOpVarDryRun)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	info, err := h.statusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if info.State != model.StateStopped {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			"can only update changefeed config when it is stopped"))
+		return
+	}
+
+	// Fill the replica config with the old one,
+	// so that the request only needs to carry the changed fields.
+	changefeedConfig := model.ChangefeedConfigV2{
+		ReplicaConfig: info.Config.Clone(),
+	}
+	if err := c.BindJSON(&changefeedConfig); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+
+	newInfo, err := verifyUpdateChangefeedConfigV2(ctx, &changefeedConfig, info)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(info.Config.Sink, newInfo.Config.Sink) {
+		if err := VerifyDispatchColumns(newInfo.Config, h.capture.Storage); err != nil {
+			_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err))
+			return
+		}
+	}
+	detail := &model.ChangefeedDetailV2{ID: changefeedID, Info: newInfo}
+	if dryRun {
+		c.IndentedJSON(http.StatusOK, detail)
+		return
+	}
+
+	err = h.capture.EtcdClient.SaveChangeFeedInfo(ctx, newInfo, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, detail)
+}
+
+// RemoveChangefeedV2 removes a changefeed
+// @Summary Remove a changefeed
+// @Description remove a changefeed, all the information of it is removed if force is true
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param force query boolean false "remove all information of the changefeed"
+// @Success 202
+// @Failure 500,404,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id} [delete]
+func (h *openAPIV2) RemoveChangefeedV2(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID, err := getChangefeedIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	force, err := getBoolQuery(c, apiOpVarForce)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// check if the changefeed exists
+	if _, err := h.statusProvider().GetChangeFeedStatus(ctx, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	job := model.AdminJob{
+		CfID: changefeedID,
+		Type: model.AdminRemove,
+		Opts: &model.AdminJobOption{ForceRemove: force},
+	}
+	if err := handleOwnerJob(ctx, h.capture, job); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// BatchPauseChangefeeds pauses the changefeeds selected by labels
+// @Summary Pause changefeeds by labels
+// @Description pause all the changefeeds whose labels match the selector
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param label_selector query string true "label selector, such as env=prod,team!=dba"
+// @Success 202 {object} model.BatchChangefeedResultV2
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeed_batch/pause [post]
+func (h *openAPIV2) BatchPauseChangefeeds(c *gin.Context) {
+	h.batchChangefeedJob(c, model.AdminStop)
+}
+
+// BatchResumeChangefeeds resumes the changefeeds selected by labels
+// @Summary Resume changefeeds by labels
+// @Description resume all the changefeeds whose labels match the selector
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param label_selector query string true "label selector, such as env=prod,team!=dba"
+// @Success 202 {object} model.BatchChangefeedResultV2
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeed_batch/resume [post]
+func (h *openAPIV2) BatchResumeChangefeeds(c *gin.Context) {
+	h.batchChangefeedJob(c, model.AdminResume)
+}
+
+// BatchRemoveChangefeeds removes the changefeeds selected by labels
+// @Summary Remove changefeeds by labels
+// @Description remove all the changefeeds whose labels match the selector
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param label_selector query string true "label selector, such as env=prod,team!=dba"
+// @Param force query boolean false "remove all information of the changefeeds"
+// @Success 202 {object} model.BatchChangefeedResultV2
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeed_batch/remove [post]
+func (h *openAPIV2) BatchRemoveChangefeeds(c *gin.Context) {
+	h.batchChangefeedJob(c, model.AdminRemove)
+}
+
+// batchChangefeedJob applies an admin job to all the changefeeds selected by
+// the label selector. A failed changefeed does not stop the others, the
+// failures are reported in the response.
+func (h *openAPIV2) batchChangefeedJob(c *gin.Context, tp model.AdminJobType) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	selector, err := model.ParseLabelSelector(c.Query(apiOpVarLabelSelector))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	force, err := getBoolQuery(c, apiOpVarForce)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	infos, err := h.statusProvider().GetAllChangeFeedInfo(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	changefeedIDs := make([]model.ChangeFeedID, 0)
+	for cfID, cfInfo := range infos {
+		if selector.Matches(cfInfo.Labels) {
+			changefeedIDs = append(changefeedIDs, cfID)
+		}
+	}
+	sort.Strings(changefeedIDs)
+
+	result := &model.BatchChangefeedResultV2{Succeeded: make([]string, 0, len(changefeedIDs))}
+	for _, cfID := range changefeedIDs {
+		job := model.AdminJob{CfID: cfID, Type: tp}
+		if tp == model.AdminRemove {
+			job.Opts = &model.AdminJobOption{ForceRemove: force}
+		}
+		if err := handleOwnerJob(ctx, h.capture, job); err != nil {
+			log.Warn("batch changefeed job failed",
+				zap.String("changefeed", cfID), zap.String("job", tp.String()), zap.Error(err))
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[cfID] = err.Error()
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, cfID)
+	}
+	log.Info("batch changefeed job done", zap.String("job", tp.String()),
+		zap.String("selector", c.Query(apiOpVarLabelSelector)),
+		zap.Strings("succeeded", result.Succeeded), zap.Any("failed", result.Failed))
+	c.IndentedJSON(http.StatusAccepted, result)
+}
+
+// GetChangefeedStatistics gets the replication statistics of a changefeed
+// @Summary Get changefeed statistics
+// @Description get the replication statistics of a changefeed
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Success 200 {object} model.ChangefeedStatisticsV2
+// @Failure 500,404,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/statistics [get]
+func (h *openAPIV2) GetChangefeedStatistics(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID, err := getChangefeedIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	status, err := h.statusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	positions, err := h.statusProvider().GetTaskPositions(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	physical, logical, err := h.capture.PDClient.GetTS(ctx)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrPDEtcdAPIError, err))
+		return
+	}
+
+	statistics := &model.ChangefeedStatisticsV2{
+		CheckpointTs: status.CheckpointTs,
+		ResolvedTs:   status.ResolvedTs,
+		CurrentTs:    oracle.ComposeTS(physical, logical),
+		SinkGap: oracle.ExtractPhysical(status.ResolvedTs) -
+			oracle.ExtractPhysical(status.CheckpointTs),
+		ReplicationGap: physical - oracle.ExtractPhysical(status.CheckpointTs),
+	}
+	for _, position := range positions {
+		statistics.Count += position.Count
+	}
+	c.IndentedJSON(http.StatusOK, statistics)
+}
+
+// GetRedoMeta gets the meta of the redo logs of a changefeed
+// @Summary Get redo log meta
+// @Description get the checkpoint ts and resolved ts stored in the redo log meta of a changefeed
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Success 200 {object} model.RedoMetaV2
+// @Failure 500,404,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/redo_meta [get]
+func (h *openAPIV2) GetRedoMeta(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID, err := getChangefeedIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	info, err := h.statusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if info.Config.Consistent == nil || !redo.IsConsistentEnabled(info.Config.Consistent.Level) {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"redo log is not enabled for changefeed %s", changefeedID))
+		return
+	}
+
+	ap := applier.NewRedoApplier(&applier.RedoApplierConfig{
+		Storage: info.Config.Consistent.Storage,
+	})
+	checkpointTs, resolvedTs, err := ap.ReadMeta(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, &model.RedoMetaV2{
+		CheckpointTs: checkpointTs,
+		ResolvedTs:   resolvedTs,
+	})
+}
+
+// ListProcessors lists the processors in the TiCDC cluster page by page
+// @Summary List processors
+// @Description list the processors in the TiCDC cluster page by page
+// @Tags processor
+// @Accept json
+// @Produce json
+// @Param page query integer false "page number, starting from 1"
+// @Param page_size query integer false "page size, 100 by default"
+// @Success 200 {object} model.ProcessorListV2
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/processors [get]
+func (h *openAPIV2) ListProcessors(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	start, limit, err := getPagination(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	infos, err := h.statusProvider().GetProcessors(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	items := make([]model.ProcessorCommonInfo, 0, len(infos))
+	for _, info := range infos {
+		items = append(items, model.ProcessorCommonInfo{CfID: info.CfID, CaptureID: info.CaptureID})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CfID != items[j].CfID {
+			return items[i].CfID < items[j].CfID
+		}
+		return items[i].CaptureID < items[j].CaptureID
+	})
+
+	from, to := paginate(len(items), start, limit)
+	c.IndentedJSON(http.StatusOK, &model.ProcessorListV2{
+		Total: len(items),
+		Items: items[from:to],
+	})
+}
+
+// ListCaptures lists the captures page by page
+// @Summary List captures
+// @Description list the captures in cdc cluster page by page
+// @Tags capture
+// @Accept json
+// @Produce json
+// @Param page query integer false "page number, starting from 1"
+// @Param page_size query integer false "page size, 100 by default"
+// @Success 200 {object} model.CaptureListV2
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/captures [get]
+func (h *openAPIV2) ListCaptures(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	start, limit, err := getPagination(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	captureInfos, err := h.statusProvider().GetCaptures(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	ownerID := h.capture.Info().ID
+	items := make([]model.Capture, 0, len(captureInfos))
+	for _, info := range captureInfos {
+		items = append(items, model.Capture{
+			ID: info.ID, IsOwner: info.ID == ownerID, AdvertiseAddr: info.AdvertiseAddr,
+			Labels: info.Labels,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	from, to := paginate(len(items), start, limit)
+	c.IndentedJSON(http.StatusOK, &model.CaptureListV2{
+		Total: len(items),
+		Items: items[from:to],
+	})
+}
+
+// DrainCapture moves all tables off a capture
+// @Summary Drain a capture
+// @Description move all tables off a capture and keep new tables off it, send the request repeatedly until no table is left, then the capture is safe to stop
+// @Tags capture
+// @Accept json
+// @Produce json
+// @Param capture_id path string true "capture_id"
+// @Success 200 {object} model.DrainCaptureResp
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/captures/{capture_id}/drain [put]
+func (h *openAPIV2) DrainCapture(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	captureID := c.Param(apiOpVarCaptureID)
+	if err := model.ValidateCaptureID(captureID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid capture_id: %s", captureID))
+		return
+	}
+	tableCount, err := handleOwnerDrainCapture(ctx, h.capture, captureID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// Persist the drain state in the capture info, so that the next owner
+	// keeps the tables off the capture, and the capture stops campaigning.
+	if err := h.capture.EtcdClient.SetCaptureDraining(ctx, captureID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if tableCount == 0 && captureID == h.capture.Info().ID {
+		// All tables have been moved off the owner, resign the owner so
+		// that the capture can be stopped.
+		h.capture.MarkDrained()
+	}
+	c.IndentedJSON(http.StatusOK, &model.DrainCaptureResp{CurrentTableCount: tableCount})
+}
+
+// QueryTso gets a timestamp from PD
+// @Summary Get tso
+// @Description get a timestamp from PD
+// @Tags common
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.TSOV2
+// @Failure 500 {object} model.HTTPError
+// @Router /api/v2/tso [get]
+func (h *openAPIV2) QueryTso(c *gin.Context) {
+	ctx := c.Request.Context()
+	physical, logical, err := h.capture.PDClient.GetTS(ctx)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrPDEtcdAPIError, err))
+		return
+	}
+	c.IndentedJSON(http.StatusOK, &model.TSOV2{
+		Timestamp:    oracle.ComposeTS(physical, logical),
+		PhysicalTime: physical,
+		LogicalTime:  logical,
+	})
+}
+
+// getChangefeedIDParam returns the changefeed id in the path.
+func getChangefeedIDParam(c *gin.Context) (model.ChangeFeedID, error) {
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		return "", cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID)
+	}
+	return changefeedID, nil
+}
+
+// getBoolQuery returns the boolean value of a query parameter,
+// false is returned if the parameter is absent.
+func getBoolQuery(c *gin.Context, key string) (bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, cerror.ErrAPIInvalidParam.GenWithStack("invalid %s: %s", key, value)
+	}
+	return b, nil
+}
+
+// getPagination returns the offset of the first item and the number of
+// items of the requested page.
+func getPagination(c *gin.Context) (int, int, error) {
+	page, pageSize := 1, defaultPageSize
+	var err error
+	if value := c.Query(apiOpVarPage); value != "" {
+		page, err = strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return 0, 0, cerror.ErrAPIInvalidParam.GenWithStack("invalid %s: %s", apiOpVarPage, value)
+		}
+	}
+	if value := c.Query(apiOpVarPageSize); value != "" {
+		pageSize, err = strconv.Atoi(value)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			return 0, 0, cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid %s: %s, it must be in [1, %d]", apiOpVarPageSize, value, maxPageSize)
+		}
+	}
+	// The pages beyond the last one are empty, the start is capped so that
+	// it never overflows.
+	if page-1 > math.MaxInt32/pageSize {
+		return math.MaxInt32, pageSize, nil
+	}
+	return (page - 1) * pageSize, pageSize, nil
+}
+
+// paginate returns the range [from, to) of the items in the page.
+func paginate(total, start, limit int) (int, int) {
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
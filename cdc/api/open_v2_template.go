@@ -0,0 +1,187 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// apiOpVarTemplateName is the key of the changefeed template name in HTTP API v2.
+const apiOpVarTemplateName = "template_name"
+
+// ListChangefeedTemplates lists the changefeed templates page by page
+// @Summary List changefeed templates
+// @Description list the changefeed templates page by page, ordered by the name
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param page query integer false "page number, starting from 1"
+// @Param page_size query integer false "page size, 100 by default"
+// @Success 200 {object} model.ChangefeedTemplateListV2
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeed_templates [get]
+func (h *openAPIV2) ListChangefeedTemplates(c *gin.Context) {
+	start, limit, err := getPagination(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	templates, err := h.capture.EtcdClient.GetChangefeedTemplates(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	from, to := paginate(len(templates), start, limit)
+	items := make([]model.ChangefeedTemplate, 0, to-from)
+	for _, tpl := range templates[from:to] {
+		items = append(items, *tpl)
+	}
+	c.IndentedJSON(http.StatusOK, &model.ChangefeedTemplateListV2{
+		Total: len(templates),
+		Items: items,
+	})
+}
+
+// GetChangefeedTemplate gets a changefeed template
+// @Summary Get changefeed template
+// @Description get a changefeed template by its name
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param template_name path string true "template_name"
+// @Success 200 {object} model.ChangefeedTemplate
+// @Failure 500,404,400 {object} model.HTTPError
+// @Router /api/v2/changefeed_templates/{template_name} [get]
+func (h *openAPIV2) GetChangefeedTemplate(c *gin.Context) {
+	name, err := getTemplateNameParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tpl, err := h.capture.EtcdClient.GetChangefeedTemplate(c.Request.Context(), name)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, tpl)
+}
+
+// CreateChangefeedTemplate creates a changefeed template
+// @Summary Create changefeed template
+// @Description create a named changefeed config that changefeeds can be created from
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param template body model.ChangefeedTemplate true "changefeed template"
+// @Success 201 {object} model.ChangefeedTemplate
+// @Failure 500,409,400 {object} model.HTTPError
+// @Router /api/v2/changefeed_templates [post]
+func (h *openAPIV2) CreateChangefeedTemplate(c *gin.Context) {
+	// Fill the replica config with the default values,
+	// so that the request only needs to carry the changed fields.
+	tpl := &model.ChangefeedTemplate{ReplicaConfig: config.GetDefaultReplicaConfig()}
+	if err := c.BindJSON(tpl); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+	if err := verifyChangefeedTemplate(tpl); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := h.capture.EtcdClient.CreateChangefeedTemplate(c.Request.Context(), tpl); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("Create changefeed template successfully!", zap.String("template", tpl.Name))
+	c.IndentedJSON(http.StatusCreated, tpl)
+}
+
+// UpdateChangefeedTemplate updates a changefeed template
+// @Summary Update changefeed template
+// @Description replace a changefeed template, the changefeeds created from it are not affected
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param template_name path string true "template_name"
+// @Param template body model.ChangefeedTemplate true "changefeed template"
+// @Success 200 {object} model.ChangefeedTemplate
+// @Failure 500,404,400 {object} model.HTTPError
+// @Router /api/v2/changefeed_templates/{template_name} [put]
+func (h *openAPIV2) UpdateChangefeedTemplate(c *gin.Context) {
+	name, err := getTemplateNameParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tpl := &model.ChangefeedTemplate{ReplicaConfig: config.GetDefaultReplicaConfig()}
+	if err := c.BindJSON(tpl); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+	if tpl.Name != "" && tpl.Name != name {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"template name %s in the body does not match %s", tpl.Name, name))
+		return
+	}
+	tpl.Name = name
+	if err := verifyChangefeedTemplate(tpl); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := h.capture.EtcdClient.UpdateChangefeedTemplate(c.Request.Context(), tpl); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, tpl)
+}
+
+// DeleteChangefeedTemplate deletes a changefeed template
+// @Summary Delete changefeed template
+// @Description delete a changefeed template, the changefeeds created from it are not affected
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param template_name path string true "template_name"
+// @Success 200
+// @Failure 500,404,400 {object} model.HTTPError
+// @Router /api/v2/changefeed_templates/{template_name} [delete]
+func (h *openAPIV2) DeleteChangefeedTemplate(c *gin.Context) {
+	name, err := getTemplateNameParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := h.capture.EtcdClient.DeleteChangefeedTemplate(c.Request.Context(), name); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// getTemplateNameParam returns the changefeed template name in the path.
+func getTemplateNameParam(c *gin.Context) (string, error) {
+	name := c.Param(apiOpVarTemplateName)
+	if err := model.ValidateChangefeedID(name); err != nil {
+		return "", cerror.ErrAPIInvalidParam.GenWithStack("invalid template_name: %s", name)
+	}
+	return name, nil
+}
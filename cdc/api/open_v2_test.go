@@ -0,0 +1,395 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/owner"
+	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func newRouterV2(c *capture.Capture, p *mockStatusProvider) *gin.Engine {
+	router := gin.New()
+	RegisterOpenAPIV2Routes(router, NewOpenAPIV2ForTest(c, p))
+	return router
+}
+
+func TestListChangefeedsV2(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouterV2(cp, newStatusProvider())
+
+	testCases := []struct {
+		url   string
+		total int
+		ids   []string
+	}{
+		{"/api/v2/changefeeds", 2, []string{changeFeedID + "1", changeFeedID + "2"}},
+		{"/api/v2/changefeeds?page=2&page_size=1", 2, []string{changeFeedID + "2"}},
+		{"/api/v2/changefeeds?page=3&page_size=1", 2, []string{}},
+		{"/api/v2/changefeeds?page=9223372036854775807&page_size=2", 2, []string{}},
+		{"/api/v2/changefeeds?state=stopped", 1, []string{changeFeedID + "2"}},
+		{"/api/v2/changefeeds?label_selector=env=prod", 1, []string{changeFeedID + "1"}},
+		{"/api/v2/changefeeds?label_selector=env!=prod,env", 1, []string{changeFeedID + "2"}},
+		{"/api/v2/changefeeds?label_selector=!env", 0, []string{}},
+	}
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tc.url, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, 200, w.Code)
+		var resp model.ChangefeedListV2
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Equal(t, tc.total, resp.Total)
+		ids := make([]string, 0, len(resp.Items))
+		for _, item := range resp.Items {
+			ids = append(ids, item.ID)
+		}
+		require.Equal(t, tc.ids, ids, tc.url)
+	}
+
+	// test list changefeeds with invalid pagination
+	for _, url := range []string{
+		"/api/v2/changefeeds?page=0",
+		"/api/v2/changefeeds?page_size=abc",
+		"/api/v2/changefeeds?page_size=100000",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, 400, w.Code)
+		respErr := model.HTTPError{}
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+		require.Equal(t, string(cerror.ErrAPIInvalidParam.RFCCode()), respErr.Code)
+	}
+}
+
+func TestGetChangefeedV2(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouterV2(cp, newStatusProvider())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/v2/changefeeds/%s", changeFeedID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	var resp model.ChangefeedDetailV2
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, changeFeedID, resp.ID)
+	require.Equal(t, model.StateNormal, resp.Info.State)
+	require.Equal(t, uint64(1), resp.Status.CheckpointTs)
+	require.Len(t, resp.TaskStatus, 1)
+	require.Equal(t, captureID, resp.TaskStatus[0].CaptureID)
+
+	// test get a changefeed that does not exist
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", fmt.Sprintf("/api/v2/changefeeds/%s", nonExistChangefeedID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 404, w.Code)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Equal(t, string(cerror.ErrChangeFeedNotExists.RFCCode()), respErr.Code)
+	require.Contains(t, respErr.Error, "changefeed not exists")
+}
+
+func TestUpdateChangefeedV2(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouterV2(cp, newStatusProvider())
+
+	// the changefeed can not be updated since it is not stopped
+	body, err := json.Marshal(&model.ChangefeedConfigV2{TargetTs: 10})
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT",
+		fmt.Sprintf("/api/v2/changefeeds/%s?dry_run=true", changeFeedID), bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Equal(t, string(cerror.ErrChangefeedUpdateRefused.RFCCode()), respErr.Code)
+
+	// test update with an invalid dry_run parameter
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT",
+		fmt.Sprintf("/api/v2/changefeeds/%s?dry_run=abc", changeFeedID), bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+}
+
+func TestRemoveChangefeedV2(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouterV2(cp, newStatusProvider())
+
+	mo.EXPECT().
+		EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(adminJob model.AdminJob, done chan<- error) {
+			require.EqualValues(t, changeFeedID, adminJob.CfID)
+			require.EqualValues(t, model.AdminRemove, adminJob.Type)
+			require.True(t, adminJob.Opts.ForceRemove)
+			close(done)
+		})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE",
+		fmt.Sprintf("/api/v2/changefeeds/%s?force=true", changeFeedID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 202, w.Code)
+
+	// test remove a changefeed that does not exist
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE",
+		fmt.Sprintf("/api/v2/changefeeds/%s", nonExistChangefeedID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 404, w.Code)
+}
+
+func TestBatchChangefeedsV2(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouterV2(cp, newStatusProvider())
+
+	// pause the changefeeds in production
+	mo.EXPECT().
+		EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(adminJob model.AdminJob, done chan<- error) {
+			require.EqualValues(t, changeFeedID+"1", adminJob.CfID)
+			require.EqualValues(t, model.AdminStop, adminJob.Type)
+			close(done)
+		})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v2/changefeed_batch/pause?label_selector=env=prod", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 202, w.Code)
+	var resp model.BatchChangefeedResultV2
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, []string{changeFeedID + "1"}, resp.Succeeded)
+	require.Empty(t, resp.Failed)
+
+	// remove all the labeled changefeeds, one of them fails
+	mo.EXPECT().
+		EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(adminJob model.AdminJob, done chan<- error) {
+			require.EqualValues(t, model.AdminRemove, adminJob.Type)
+			require.True(t, adminJob.Opts.ForceRemove)
+			if adminJob.CfID == changeFeedID+"2" {
+				done <- cerror.ErrChangeFeedNotExists.GenWithStackByArgs(adminJob.CfID)
+			}
+			close(done)
+		}).Times(2)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v2/changefeed_batch/remove?label_selector=env&force=true", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 202, w.Code)
+	resp = model.BatchChangefeedResultV2{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, []string{changeFeedID + "1"}, resp.Succeeded)
+	require.Len(t, resp.Failed, 1)
+	require.Contains(t, resp.Failed[changeFeedID+"2"], "changefeed not exists")
+
+	// the selector is required
+	for _, url := range []string{
+		"/api/v2/changefeed_batch/resume",
+		"/api/v2/changefeed_batch/resume?label_selector==prod",
+	} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", url, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, 400, w.Code)
+		respErr := model.HTTPError{}
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+		require.Equal(t, string(cerror.ErrInvalidLabelSelector.RFCCode()), respErr.Code)
+	}
+}
+
+func TestChangefeedTemplateV2InvalidParam(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouterV2(cp, newStatusProvider())
+
+	body, err := json.Marshal(&model.ChangefeedTemplate{Name: "bad_name"})
+	require.Nil(t, err)
+	testCases := []struct {
+		method string
+		url    string
+		body   []byte
+	}{
+		{"POST", "/api/v2/changefeed_templates", body},
+		{"POST", "/api/v2/changefeed_templates", []byte(`{"name": "kafka", "labels": {"env": "a b"}}`)},
+		{"POST", "/api/v2/changefeed_templates", []byte(`{"name": "kafka", "sort_engine": "abc"}`)},
+		{"PUT", "/api/v2/changefeed_templates/kafka", []byte(`{"name": "mysql"}`)},
+		{"GET", "/api/v2/changefeed_templates/bad_name", nil},
+		{"DELETE", "/api/v2/changefeed_templates/bad_name", nil},
+	}
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, tc.url, bytes.NewReader(tc.body))
+		router.ServeHTTP(w, req)
+		require.Equal(t, 400, w.Code, "%s %s", tc.method, tc.url)
+	}
+}
+
+func TestListCapturesAndProcessorsV2(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouterV2(cp, newStatusProvider())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v2/captures", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	var captures model.CaptureListV2
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&captures))
+	require.Equal(t, 1, captures.Total)
+	require.Equal(t, captureID, captures.Items[0].ID)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v2/processors?page=1&page_size=10", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	var processors model.ProcessorListV2
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&processors))
+	require.Equal(t, 1, processors.Total)
+	require.Equal(t, changeFeedID, processors.Items[0].CfID)
+	require.Equal(t, captureID, processors.Items[0].CaptureID)
+}
+
+func TestHTTPStatusCodeV2(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, http.StatusNotFound,
+		httpStatusCodeV2(cerror.ErrChangeFeedNotExists.GenWithStackByArgs("test")))
+	require.Equal(t, http.StatusNotFound,
+		httpStatusCodeV2(cerror.ErrCaptureNotExist.GenWithStackByArgs("test")))
+	require.Equal(t, http.StatusConflict,
+		httpStatusCodeV2(cerror.ErrChangeFeedAlreadyExists.GenWithStackByArgs("test")))
+	require.Equal(t, http.StatusNotFound,
+		httpStatusCodeV2(cerror.ErrChangefeedTemplateNotExists.GenWithStackByArgs("test")))
+	require.Equal(t, http.StatusConflict,
+		httpStatusCodeV2(cerror.ErrChangefeedTemplateAlreadyExists.GenWithStackByArgs("test")))
+	require.Equal(t, http.StatusBadRequest,
+		httpStatusCodeV2(cerror.ErrInvalidLabelSelector.GenWithStackByArgs("a=b=c", "test")))
+	require.Equal(t, http.StatusBadRequest,
+		httpStatusCodeV2(cerror.ErrAPIInvalidParam.GenWithStackByArgs()))
+	require.Equal(t, http.StatusInternalServerError,
+		httpStatusCodeV2(cerror.ErrPDEtcdAPIError.GenWithStackByArgs("test")))
+}
+
+func TestDrainCaptureV2(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouterV2(cp, newStatusProvider())
+	info := model.CaptureInfo{ID: "0b6f9c1d-2e3a-4f5b-8c7d-9e0a1b2c3d4e", AdvertiseAddr: "127.0.0.1:8301"}
+	drainURL := fmt.Sprintf("/api/v2/captures/%s/drain", info.ID)
+
+	// The drain state is persisted in the capture info.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clientURL, etcdServer, err := etcd.SetupEmbedEtcd(t.TempDir())
+	require.Nil(t, err)
+	defer etcdServer.Close()
+	etcdCli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{clientURL.String()},
+		Context:     ctx,
+		DialTimeout: 3 * time.Second,
+	})
+	require.Nil(t, err)
+	client := etcd.NewCDCEtcdClient(ctx, etcdCli)
+	defer client.Close()
+	cp.EtcdClient = &client
+	require.Nil(t, client.PutCaptureInfo(ctx, &info, clientv3.NoLease))
+
+	// tables are still being moved off the capture.
+	mo.EXPECT().
+		DrainCapture(gomock.Any(), gomock.Any()).
+		Do(func(request *owner.DrainCaptureRequest, done chan<- error) {
+			require.Equal(t, info.ID, request.CaptureID)
+			request.TableCount = 2
+			close(done)
+		})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", drainURL, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	var resp model.DrainCaptureResp
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, 2, resp.CurrentTableCount)
+	obtained, err := client.GetCaptureInfo(ctx, info.ID)
+	require.Nil(t, err)
+	require.True(t, obtained.Draining)
+
+	// the capture is drained, the owner runs on another capture so it
+	// does not resign.
+	mo.EXPECT().
+		DrainCapture(gomock.Any(), gomock.Any()).
+		Do(func(request *owner.DrainCaptureRequest, done chan<- error) {
+			close(done)
+		})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", drainURL, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, 0, resp.CurrentTableCount)
+
+	// the last capture can not be drained.
+	mo.EXPECT().
+		DrainCapture(gomock.Any(), gomock.Any()).
+		Do(func(request *owner.DrainCaptureRequest, done chan<- error) {
+			done <- cerror.ErrDrainCaptureRefused.GenWithStackByArgs(request.CaptureID, "test")
+			close(done)
+		})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", drainURL, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the capture ID must be a UUID.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/api/v2/captures/capture-2/drain", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/txnutil"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/tikv/client-go/v2/oracle"
+	"github.com/tikv/client-go/v2/tikv"
+	"go.uber.org/zap"
+)
+
+// ShowMetadata shows the metadata stored in etcd
+// @Summary Show metadata
+// @Description show all the metadata of TiCDC stored in etcd
+// @Tags unsafe
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.EtcdDataV2
+// @Failure 500 {object} model.HTTPError
+// @Router /api/v2/unsafe/metadata [get]
+func (h *openAPIV2) ShowMetadata(c *gin.Context) {
+	kvs, err := h.capture.EtcdClient.GetAllCDCInfo(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resps := make([]model.EtcdDataV2, 0, len(kvs))
+	for _, kv := range kvs {
+		resps = append(resps, model.EtcdDataV2{Key: string(kv.Key), Value: string(kv.Value)})
+	}
+	c.IndentedJSON(http.StatusOK, resps)
+}
+
+// ResetMetadata resets the TiCDC cluster. The leases of all the captures are
+// revoked, the capture serving the request revokes its own lease at last, so
+// that the reset is done before it loses its session and re-registers itself
+// like the others.
+// @Summary Reset the TiCDC cluster
+// @Description delete all the metadata in etcd and the service GC safepoint of TiCDC, use it at your own risk. All the captures, including the one serving the request, lose their sessions and re-register themselves
+// @Tags unsafe
+// @Accept json
+// @Produce json
+// @Success 200
+// @Failure 500 {object} model.HTTPError
+// @Router /api/v2/unsafe/reset [post]
+func (h *openAPIV2) ResetMetadata(c *gin.Context) {
+	ctx := c.Request.Context()
+	leases, err := h.capture.EtcdClient.GetCaptureLeases(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := h.capture.EtcdClient.ClearAllCDCInfo(ctx); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	selfID := h.capture.Info().ID
+	selfLease, hasSelfLease := leases[selfID]
+	delete(leases, selfID)
+	if err := h.capture.EtcdClient.RevokeAllLeases(ctx, leases); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err = gc.RemoveServiceGCSafepoint(ctx, h.capture.PDClient, gc.CDCServiceSafePointID)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrPDEtcdAPIError, err))
+		return
+	}
+	if hasSelfLease {
+		err := h.capture.EtcdClient.RevokeAllLeases(ctx, map[string]int64{selfID: selfLease})
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	log.Warn("reset and all metadata truncated in PD")
+	c.Status(http.StatusOK)
+}
+
+// DeleteServiceGCSafepoint deletes the service GC safepoint of TiCDC
+// @Summary Delete service GC safepoint
+// @Description delete the service GC safepoint of TiCDC in PD, use it at your own risk
+// @Tags unsafe
+// @Accept json
+// @Produce json
+// @Success 200
+// @Failure 500 {object} model.HTTPError
+// @Router /api/v2/unsafe/service_gc_safepoint [delete]
+func (h *openAPIV2) DeleteServiceGCSafepoint(c *gin.Context) {
+	err := gc.RemoveServiceGCSafepoint(
+		c.Request.Context(), h.capture.PDClient, gc.CDCServiceSafePointID)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrPDEtcdAPIError, err))
+		return
+	}
+	log.Warn("CDC service GC safepoint truncated in PD")
+	c.Status(http.StatusOK)
+}
+
+// ResolveLock resolves the locks in a region
+// @Summary Resolve locks
+// @Description resolve the locks in a region before a timestamp, which is 1 minute ago from now by default
+// @Tags unsafe
+// @Accept json
+// @Produce json
+// @Param resolve_lock body model.ResolveLockReqV2 true "region id and timestamp"
+// @Success 200
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/unsafe/resolve_lock [post]
+func (h *openAPIV2) ResolveLock(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req model.ResolveLockReqV2
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+	if req.RegionID == 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("region_id is required"))
+		return
+	}
+	if req.Ts == 0 {
+		physical, logical, err := h.capture.PDClient.GetTS(ctx)
+		if err != nil {
+			_ = c.Error(cerror.WrapError(cerror.ErrPDEtcdAPIError, err))
+			return
+		}
+		// Try not kill active transaction, we only resolves lock 1 minute ago.
+		now := oracle.GetTimeFromTS(oracle.ComposeTS(physical, logical))
+		req.Ts = oracle.GoTimeToTS(now.Add(-time.Minute))
+	}
+
+	txnResolver := txnutil.NewLockerResolver(h.capture.Storage.(tikv.Storage),
+		"changefeed-client", util.RoleClient)
+	if err := txnResolver.Resolve(ctx, req.RegionID, req.Ts); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("locks resolved", zap.Uint64("regionID", req.RegionID), zap.Uint64("ts", req.Ts))
+	c.Status(http.StatusOK)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/owner"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -32,7 +33,8 @@ var httpBadRequestError = []*errors.Error{
 	cerror.ErrAPIInvalidParam, cerror.ErrSinkURIInvalid, cerror.ErrStartTsBeforeGC,
 	cerror.ErrChangeFeedNotExists, cerror.ErrTargetTsBeforeStartTs, cerror.ErrTableIneligible,
 	cerror.ErrFilterRuleInvalid, cerror.ErrChangefeedUpdateRefused, cerror.ErrMySQLConnectionError,
-	cerror.ErrMySQLInvalidConfig, cerror.ErrCaptureNotExist,
+	cerror.ErrMySQLInvalidConfig, cerror.ErrCaptureNotExist, cerror.ErrDrainCaptureRefused,
+	cerror.ErrInvalidChangefeedLabel, cerror.ErrInvalidLabelSelector,
 }
 
 // IsHTTPBadRequestError check if a error is a http bad request error
@@ -57,6 +59,31 @@ func IsHTTPBadRequestError(err error) bool {
 	return false
 }
 
+// httpNotFoundError is some errors that will cause a NotFound error in http handler v2
+var httpNotFoundError = []*errors.Error{
+	cerror.ErrChangeFeedNotExists, cerror.ErrCaptureNotExist, cerror.ErrChangefeedTemplateNotExists,
+}
+
+// httpStatusCodeV2 returns the http status code of an error for the v2 APIs.
+func httpStatusCodeV2(err error) int {
+	for _, e := range httpNotFoundError {
+		if e.Equal(err) {
+			return http.StatusNotFound
+		}
+		if rfcCode, ok := cerror.RFCCode(err); ok && e.RFCCode() == rfcCode {
+			return http.StatusNotFound
+		}
+	}
+	if cerror.ErrChangeFeedAlreadyExists.Equal(err) ||
+		cerror.ErrChangefeedTemplateAlreadyExists.Equal(err) {
+		return http.StatusConflict
+	}
+	if IsHTTPBadRequestError(err) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 func writeError(w http.ResponseWriter, statusCode int, err error) {
 	w.WriteHeader(statusCode)
 	_, err = w.Write([]byte(err.Error()))
@@ -116,6 +143,24 @@ func handleOwnerRebalance(
 	}
 }
 
+func handleOwnerSchemaBootstrap(
+	ctx context.Context, capture *capture.Capture, changefeedID string,
+) error {
+	// Use buffered channel to prevernt blocking owner.
+	done := make(chan error, 1)
+	o, err := capture.GetOwner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.BootstrapSchema(changefeedID, done)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-done:
+		return errors.Trace(err)
+	}
+}
+
 func handleOwnerScheduleTable(
 	ctx context.Context, capture *capture.Capture,
 	changefeedID string, captureID string, tableID int64,
@@ -134,3 +179,25 @@ func handleOwnerScheduleTable(
 		return errors.Trace(err)
 	}
 }
+
+func handleOwnerDrainCapture(
+	ctx context.Context, capture *capture.Capture, captureID string,
+) (tableCount int, err error) {
+	// Use buffered channel to prevernt blocking owner.
+	done := make(chan error, 1)
+	o, err := capture.GetOwner()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	request := &owner.DrainCaptureRequest{CaptureID: captureID}
+	o.DrainCapture(request, done)
+	select {
+	case <-ctx.Done():
+		return 0, errors.Trace(ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		return request.TableCount, nil
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	tidbkv "github.com/pingcap/tidb/kv"
@@ -25,7 +26,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/cyclic"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
@@ -132,14 +135,14 @@ func verifyCreateChangefeedConfig(
 		CreatorVersion:    version.ReleaseVersion,
 	}
 
-	if !replicaConfig.ForceReplicate && !changefeedConfig.IgnoreIneligibleTable {
-		ineligibleTables, _, err := VerifyTables(replicaConfig, capture.Storage, changefeedConfig.StartTS)
-		if err != nil {
-			return nil, err
-		}
-		if len(ineligibleTables) != 0 {
-			return nil, cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables)
-		}
+	// The tables are always verified, since the columns used by
+	// the dispatch rules must exist even if the ineligible tables are ignored.
+	ineligibleTables, _, err := VerifyTables(replicaConfig, capture.Storage, changefeedConfig.StartTS)
+	if err != nil {
+		return nil, err
+	}
+	if len(ineligibleTables) != 0 && !replicaConfig.ForceReplicate && !changefeedConfig.IgnoreIneligibleTable {
+		return nil, cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables)
 	}
 
 	tz, err := util.GetTimezone(changefeedConfig.TimeZone)
@@ -219,6 +222,12 @@ func VerifyTables(replicaConfig *config.ReplicaConfig, storage tidbkv.Storage, s
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
+	// The dispatch rules of MQ sinks may dispatch the rows by column values,
+	// make sure the columns exist.
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, "")
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 
 	for _, tableInfo := range snap.Tables() {
 		if filter.ShouldIgnoreTable(tableInfo.TableName.Schema, tableInfo.TableName.Table) {
@@ -229,6 +238,9 @@ func VerifyTables(replicaConfig *config.ReplicaConfig, storage tidbkv.Storage, s
 		if tableInfo.IsSequence() {
 			continue
 		}
+		if err := eventRouter.VerifyColumns(tableInfo); err != nil {
+			return nil, nil, err
+		}
 		if !tableInfo.IsEligible(false /* forceReplicate */) {
 			ineligibleTables = append(ineligibleTables, tableInfo.TableName)
 		} else {
@@ -237,3 +249,279 @@ func VerifyTables(replicaConfig *config.ReplicaConfig, storage tidbkv.Storage, s
 	}
 	return
 }
+
+// VerifyDispatchColumns checks whether the columns used by the dispatch rules
+// exist in the tables at the current version of the storage, it is used when
+// the sink config of an existing changefeed is updated.
+func VerifyDispatchColumns(replicaConfig *config.ReplicaConfig, storage tidbkv.Storage) error {
+	ver, err := storage.CurrentVersion(oracle.GlobalTxnScope)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, _, err = VerifyTables(replicaConfig, storage, ver.Ver)
+	return err
+}
+
+// verifyCreateChangefeedConfigV2 verifies ChangefeedConfigV2 for creating
+// a changefeed, and returns the changefeed info to be created along with the
+// ineligible tables that are ignored. The GC safepoint is only read if dryRun is true.
+func verifyCreateChangefeedConfigV2(
+	ctx context.Context,
+	changefeedConfig *model.ChangefeedConfigV2,
+	capture *capture.Capture,
+	dryRun bool,
+) (*model.ChangeFeedInfo, []model.TableName, error) {
+	if changefeedConfig.SinkURI == "" {
+		return nil, nil, cerror.ErrSinkURIInvalid.GenWithStackByArgs("sink-uri is empty, can't not create a changefeed without sink-uri")
+	}
+	if changefeedConfig.ID == "" {
+		changefeedConfig.ID = uuid.New().String()
+	}
+	if err := model.ValidateChangefeedID(changefeedConfig.ID); err != nil {
+		return nil, nil, cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedConfig.ID)
+	}
+	// check if the changefeed exists
+	cfStatus, err := capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedConfig.ID)
+	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
+		return nil, nil, err
+	}
+	if cfStatus != nil {
+		return nil, nil, cerror.ErrChangeFeedAlreadyExists.GenWithStackByArgs(changefeedConfig.ID)
+	}
+
+	replicaConfig := changefeedConfig.ReplicaConfig
+	if replicaConfig == nil {
+		replicaConfig = config.GetDefaultReplicaConfig()
+	}
+	if changefeedConfig.DisableGCCheck {
+		replicaConfig.CheckGCSafePoint = false
+	}
+	if err := replicaConfig.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := filter.VerifyRules(replicaConfig); err != nil {
+		return nil, nil, err
+	}
+
+	// verify start-ts
+	if changefeedConfig.StartTs == 0 {
+		ts, logical, err := capture.PDClient.GetTS(ctx)
+		if err != nil {
+			return nil, nil, cerror.ErrPDEtcdAPIError.GenWithStackByArgs("fail to get ts from pd client")
+		}
+		changefeedConfig.StartTs = oracle.ComposeTS(ts, logical)
+	}
+	if !changefeedConfig.DisableGCCheck {
+		var err error
+		if dryRun {
+			// A dry run only reads the GC safepoint.
+			err = gc.CheckChangefeedStartTsSafety(
+				ctx, capture.PDClient, changefeedConfig.ID, changefeedConfig.StartTs)
+		} else {
+			// Ensure the start ts is valid in the next 1 hour.
+			const ensureTTL = 60 * 60
+			err = gc.EnsureChangefeedStartTsSafety(
+				ctx, capture.PDClient, changefeedConfig.ID, ensureTTL, changefeedConfig.StartTs)
+		}
+		if err != nil {
+			if !cerror.ErrStartTsBeforeGC.Equal(err) {
+				return nil, nil, cerror.ErrPDEtcdAPIError.Wrap(err)
+			}
+			return nil, nil, err
+		}
+	}
+
+	// verify target-ts
+	if changefeedConfig.TargetTs > 0 && changefeedConfig.TargetTs <= changefeedConfig.StartTs {
+		return nil, nil, cerror.ErrTargetTsBeforeStartTs.GenWithStackByArgs(changefeedConfig.TargetTs, changefeedConfig.StartTs)
+	}
+
+	// set sortEngine and EnableOldValue
+	captureInfos, err := capture.StatusProvider().GetCaptures(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	cdcClusterVer, err := version.GetTiCDCClusterVersion(model.ListVersionsFromCaptureInfos(captureInfos))
+	if err != nil {
+		return nil, nil, err
+	}
+	sortEngine := changefeedConfig.Engine
+	if sortEngine == "" {
+		sortEngine = model.SortUnified
+	}
+	if err := verifySortEngine(sortEngine); err != nil {
+		return nil, nil, err
+	}
+	if !cdcClusterVer.ShouldEnableOldValueByDefault() {
+		replicaConfig.EnableOldValue = false
+		log.Warn("The TiCDC cluster is built from unknown branch or less than 5.0.0-rc, the old-value are disabled by default.")
+		if sortEngine == model.SortUnified && !cdcClusterVer.ShouldEnableUnifiedSorterByDefault() {
+			sortEngine = model.SortInMemory
+		}
+	}
+
+	info := &model.ChangeFeedInfo{
+		SinkURI:           changefeedConfig.SinkURI,
+		Opts:              make(map[string]string),
+		CreateTime:        time.Now(),
+		StartTs:           changefeedConfig.StartTs,
+		TargetTs:          changefeedConfig.TargetTs,
+		Config:            replicaConfig,
+		Engine:            sortEngine,
+		State:             model.StateNormal,
+		SyncPointEnabled:  false,
+		SyncPointInterval: 10 * time.Minute,
+		CreatorVersion:    version.ReleaseVersion,
+	}
+	for k, v := range changefeedConfig.Opts {
+		info.Opts[k] = v
+	}
+	if changefeedConfig.SyncPointEnabled != nil {
+		info.SyncPointEnabled = *changefeedConfig.SyncPointEnabled
+	}
+	if changefeedConfig.SyncPointInterval != 0 {
+		info.SyncPointInterval = changefeedConfig.SyncPointInterval
+	}
+	if changefeedConfig.Priority != nil {
+		info.Priority = *changefeedConfig.Priority
+	}
+	if err := model.ValidateLabels(changefeedConfig.Labels); err != nil {
+		return nil, nil, err
+	}
+	if len(changefeedConfig.Labels) != 0 {
+		info.Labels = changefeedConfig.Labels
+	}
+	if changefeedConfig.Paused {
+		info.State = model.StateStopped
+		info.AdminJobType = model.AdminStop
+	}
+
+	ineligibleTables, eligibleTables, err := VerifyTables(replicaConfig, capture.Storage, changefeedConfig.StartTs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ineligibleTables) != 0 && !replicaConfig.ForceReplicate && !changefeedConfig.IgnoreIneligibleTable {
+		return nil, nil, cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables)
+	}
+	if replicaConfig.Cyclic.IsEnabled() && !cyclic.IsTablesPaired(eligibleTables) {
+		return nil, nil, cerror.ErrAPIInvalidParam.GenWithStack("normal tables and mark tables are not paired, " +
+			"please run `cdc cli changefeed cyclic create-marktables`")
+	}
+
+	tz, err := util.GetTimezone(changefeedConfig.TimeZone)
+	if err != nil {
+		return nil, nil, cerror.ErrAPIInvalidParam.Wrap(errors.Annotatef(err, "invalid timezone:%s", changefeedConfig.TimeZone))
+	}
+	ctx = util.PutTimezoneInCtx(ctx, tz)
+	if err := sink.Validate(ctx, info.SinkURI, info.Config, info.Opts); err != nil {
+		return nil, nil, err
+	}
+
+	return info, ineligibleTables, nil
+}
+
+// verifyUpdateChangefeedConfigV2 verifies ChangefeedConfigV2 for updating
+// a changefeed, and returns the updated changefeed info.
+func verifyUpdateChangefeedConfigV2(
+	ctx context.Context, changefeedConfig *model.ChangefeedConfigV2, oldInfo *model.ChangeFeedInfo,
+) (*model.ChangeFeedInfo, error) {
+	newInfo, err := oldInfo.Clone()
+	if err != nil {
+		return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(err.Error())
+	}
+
+	if changefeedConfig.TargetTs != 0 {
+		if changefeedConfig.TargetTs <= newInfo.StartTs {
+			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStack("can not update target-ts:%d less than start-ts:%d", changefeedConfig.TargetTs, newInfo.StartTs)
+		}
+		newInfo.TargetTs = changefeedConfig.TargetTs
+	}
+	if changefeedConfig.SinkURI != "" {
+		newInfo.SinkURI = changefeedConfig.SinkURI
+	}
+	if changefeedConfig.Engine != "" {
+		if err := verifySortEngine(changefeedConfig.Engine); err != nil {
+			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+		}
+		newInfo.Engine = changefeedConfig.Engine
+	}
+	for k, v := range changefeedConfig.Opts {
+		if newInfo.Opts == nil {
+			newInfo.Opts = make(map[string]string)
+		}
+		newInfo.Opts[k] = v
+	}
+	if changefeedConfig.SyncPointEnabled != nil {
+		newInfo.SyncPointEnabled = *changefeedConfig.SyncPointEnabled
+	}
+	if changefeedConfig.SyncPointInterval != 0 {
+		newInfo.SyncPointInterval = changefeedConfig.SyncPointInterval
+	}
+	if changefeedConfig.Priority != nil {
+		newInfo.Priority = *changefeedConfig.Priority
+	}
+	if changefeedConfig.Labels != nil {
+		if err := model.ValidateLabels(changefeedConfig.Labels); err != nil {
+			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+		}
+		newInfo.Labels = nil
+		if len(changefeedConfig.Labels) != 0 {
+			newInfo.Labels = changefeedConfig.Labels
+		}
+	}
+	if changefeedConfig.ReplicaConfig != nil {
+		newInfo.Config = changefeedConfig.ReplicaConfig
+		if err := newInfo.Config.Validate(); err != nil {
+			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+		}
+		if _, err := filter.VerifyRules(newInfo.Config); err != nil {
+			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(err.Error())
+		}
+	}
+
+	if !diff.Changed(oldInfo, newInfo) {
+		return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs("changefeed config is the same with the old one, do nothing")
+	}
+	if err := sink.Validate(ctx, newInfo.SinkURI, newInfo.Config, newInfo.Opts); err != nil {
+		return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+	}
+
+	return newInfo, nil
+}
+
+// verifyChangefeedTemplate verifies a changefeed template before saving it.
+// The sink URI is verified when a changefeed is created from the template,
+// since it may be overridden.
+func verifyChangefeedTemplate(tpl *model.ChangefeedTemplate) error {
+	if err := model.ValidateChangefeedID(tpl.Name); err != nil {
+		return cerror.ErrAPIInvalidParam.GenWithStack("invalid template name: %s", tpl.Name)
+	}
+	if tpl.Engine != "" {
+		if err := verifySortEngine(tpl.Engine); err != nil {
+			return err
+		}
+	}
+	if tpl.ReplicaConfig != nil {
+		if err := tpl.ReplicaConfig.Validate(); err != nil {
+			return err
+		}
+		if _, err := filter.VerifyRules(tpl.ReplicaConfig); err != nil {
+			return err
+		}
+	}
+	return model.ValidateLabels(tpl.Labels)
+}
+
+// verifySortEngine checks whether the sort engine is supported.
+func verifySortEngine(engine model.SortEngine) error {
+	switch engine {
+	case model.SortUnified, model.SortInDB, model.SortInMemory:
+	case model.SortInFile:
+		// obsolete. But we keep silent here. We create a Unified Sorter when the owner/processor sees this option
+		// for backward-compatibility.
+	default:
+		return cerror.ErrAPIInvalidParam.GenWithStack("invalid sort engine %s, `%s`, `%s` and `%s` are the only valid options",
+			engine, model.SortUnified, model.SortInDB, model.SortInMemory)
+	}
+	return nil
+}
@@ -38,13 +38,16 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/leveldb/system"
+	"github.com/pingcap/tiflow/cdc/sorter/unified"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/pdtime"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
 )
 
@@ -69,6 +72,9 @@ type Capture struct {
 	regionCache  *tikv.RegionCache
 	pdClock      *pdtime.PDClock
 	sorterSystem *ssystem.System
+	// memoryGovernor is shared by all changefeeds, it lasts for the whole
+	// life of the server.
+	memoryGovernor *memquota.Governor
 
 	enableNewScheduler bool
 	tableActorSystem   *system.System
@@ -103,6 +109,8 @@ func NewCapture(pdClient pd.Client, kvStorage tidbkv.Storage, etcdClient *etcd.C
 		grpcService: grpcService,
 		cancel:      func() {},
 
+		memoryGovernor: memquota.NewGovernor(conf.MemoryQuota),
+
 		enableNewScheduler:  conf.Debug.EnableNewScheduler,
 		newProcessorManager: processor.NewManager,
 		newOwner:            owner.NewOwner,
@@ -134,6 +142,7 @@ func (c *Capture) reset(ctx context.Context) error {
 		ID:            uuid.New().String(),
 		AdvertiseAddr: conf.AdvertiseAddr,
 		Version:       version.ReleaseVersion,
+		Labels:        conf.Labels,
 	}
 	c.processorManager = c.newProcessorManager()
 	if c.session != nil {
@@ -164,7 +173,7 @@ func (c *Capture) reset(ctx context.Context) error {
 				"create table actor system")
 		}
 	}
-	if conf.Debug.EnableDBSorter {
+	if conf.Sorter.Engine == config.SorterEngineDB {
 		if c.sorterSystem != nil {
 			err := c.sorterSystem.Stop()
 			if err != nil {
@@ -174,6 +183,12 @@ func (c *Capture) reset(ctx context.Context) error {
 		// Sorter dir has been set and checked when server starts.
 		// See https://github.com/pingcap/tiflow/blob/9dad09/cdc/server.go#L275
 		sortDir := config.GetGlobalServerConfig().Sorter.SortDir
+		// Remove files left by unified sorter, in case the server is
+		// switched from unified sorter to db sorter.
+		if err := unified.CleanUpStaleFiles(sortDir); err != nil {
+			log.Warn("clean up unified sorter files failed",
+				zap.String("dir", sortDir), zap.Error(err))
+		}
 		memPercentage := float64(config.GetGlobalServerConfig().Sorter.MaxMemoryPercentage) / 100
 		c.sorterSystem = ssystem.NewSystem(sortDir, memPercentage, conf.Debug.DB)
 		err = c.sorterSystem.Start(ctx)
@@ -264,6 +279,8 @@ func (c *Capture) Run(ctx context.Context) error {
 }
 
 func (c *Capture) run(stdCtx context.Context) error {
+	// The upstream cluster ID is attached to the MQ messages as a header.
+	stdCtx = util.PutClusterIDInCtx(stdCtx, c.PDClient.GetClusterID(stdCtx))
 	ctx := cdcContext.NewContext(stdCtx, &cdcContext.GlobalVars{
 		PDClient:         c.PDClient,
 		KVStorage:        c.Storage,
@@ -274,6 +291,7 @@ func (c *Capture) run(stdCtx context.Context) error {
 		PDClock:          c.pdClock,
 		TableActorSystem: c.tableActorSystem,
 		SorterSystem:     c.sorterSystem,
+		MemoryGovernor:   c.memoryGovernor,
 		MessageServer:    c.MessageServer,
 		MessageRouter:    c.MessageRouter,
 	})
@@ -386,6 +404,20 @@ func (c *Capture) campaignOwner(ctx cdcContext.Context) error {
 			}
 			return errors.Trace(err)
 		}
+		// A draining capture is going to be stopped, it does not campaign.
+		draining, err := c.isDraining(ctx)
+		if err != nil {
+			if errors.Cause(err) == context.Canceled {
+				return nil
+			}
+			return errors.Trace(err)
+		}
+		if draining {
+			log.Info("capture is draining, stop campaigning owner",
+				zap.String("captureID", c.info.ID))
+			<-ctx.Done()
+			return nil
+		}
 		// Campaign to be an owner, it blocks until it becomes the owner
 		if err := c.campaign(ctx); err != nil {
 			switch errors.Cause(err) {
@@ -399,6 +431,21 @@ func (c *Capture) campaignOwner(ctx cdcContext.Context) error {
 			// if campaign owner failed, restart capture
 			return cerror.ErrCaptureSuicide.GenWithStackByArgs()
 		}
+		// The capture may be drained while it is campaigning,
+		// resign the owner and check it again in the next loop.
+		draining, err = c.isDraining(ctx)
+		if err != nil {
+			if errors.Cause(err) == context.Canceled {
+				return nil
+			}
+			return errors.Trace(err)
+		}
+		if draining {
+			if err := c.resign(ctx); err != nil {
+				return errors.Annotatef(err, "resign owner failed, capture: %s", c.info.ID)
+			}
+			continue
+		}
 
 		ownerRev, err := c.EtcdClient.GetOwnerRevision(ctx, c.info.ID)
 		if err != nil {
@@ -501,6 +548,26 @@ func (c *Capture) GetOwner() (owner.Owner, error) {
 	return c.owner, nil
 }
 
+// MarkDrained is called once all tables have been moved off the draining
+// capture, it resigns the owner, and the capture does not campaign for the
+// owner any more since it is draining, so it is safe to stop the capture.
+func (c *Capture) MarkDrained() {
+	o, _ := c.GetOwner()
+	if o != nil {
+		o.AsyncStop()
+	}
+}
+
+// isDraining returns true if the capture is marked draining in its capture
+// info, the drain state is persisted by the owner.
+func (c *Capture) isDraining(ctx context.Context) (bool, error) {
+	info, err := c.EtcdClient.GetCaptureInfo(ctx, c.info.ID)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return info.Draining, nil
+}
+
 // campaign to be an owner.
 func (c *Capture) campaign(ctx cdcContext.Context) error {
 	failpoint.Inject("capture-campaign-compacted-error", func() {
@@ -594,6 +661,11 @@ func (c *Capture) WriteDebugInfo(ctx context.Context, w io.Writer) {
 		wait(doneOwner)
 	}
 
+	if c.memoryGovernor != nil {
+		fmt.Fprintf(w, "\n\n*** memory quota info ***:\n\n")
+		c.memoryGovernor.WriteDebugInfo(w)
+	}
+
 	doneM := make(chan error, 1)
 	c.captureMu.Lock()
 	if c.processorManager != nil {
@@ -608,6 +680,20 @@ func (c *Capture) WriteDebugInfo(ctx context.Context, w io.Writer) {
 	wait(doneM)
 }
 
+// GetTableStatuses returns the replication status of the tables of the
+// changefeed replicated by this capture.
+func (c *Capture) GetTableStatuses(
+	ctx context.Context, changefeedID model.ChangeFeedID,
+) ([]*model.TableStatus, error) {
+	c.captureMu.Lock()
+	processorManager := c.processorManager
+	c.captureMu.Unlock()
+	if processorManager == nil {
+		return nil, nil
+	}
+	return processorManager.QueryTableStatuses(ctx, changefeedID)
+}
+
 // IsOwner returns whether the capture is an owner
 func (c *Capture) IsOwner() bool {
 	c.ownerMu.Lock()
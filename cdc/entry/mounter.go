@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -70,7 +71,9 @@ type mounterImpl struct {
 	tz             *time.Location
 	workerNum      int
 	enableOldValue bool
-	changefeedID   string
+	// rowImage is nil if all the tables use the full row image.
+	rowImage     RowImageSelector
+	changefeedID string
 
 	// index is an atomic variable to dispatch input events to workers.
 	index int64
@@ -84,11 +87,13 @@ func NewMounter(schemaStorage SchemaStorage,
 	changefeedID string,
 	tz *time.Location,
 	enableOldValue bool,
+	rowImage RowImageSelector,
 ) Mounter {
 	return &mounterImpl{
 		schemaStorage:       schemaStorage,
 		changefeedID:        changefeedID,
 		enableOldValue:      enableOldValue,
+		rowImage:            rowImage,
 		metricMountDuration: mountDuration.WithLabelValues(changefeedID),
 		metricTotalRows:     totalRowsCountGauge.WithLabelValues(changefeedID),
 		tz:                  tz,
@@ -107,6 +112,9 @@ func (m *mounterImpl) DecodeEvent(ctx context.Context, pEvent *model.Polymorphic
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if rowEvent != nil {
+		rowEvent.Trace = tracing.Stage(pEvent.RawKV.Trace, tracing.StageMounter)
+	}
 	pEvent.Row = rowEvent
 	pEvent.RawKV.Value = nil
 	pEvent.RawKV.OldValue = nil
@@ -321,6 +329,9 @@ func (m *mounterImpl) mountRowKVEntry(tableInfo *model.TableInfo, row *rowKVEntr
 
 	schemaName := tableInfo.TableName.Schema
 	tableName := tableInfo.TableName.Table
+	if m.enableOldValue && m.rowImage != nil {
+		applyRowImage(m.rowImage(schemaName, tableName), preCols, cols)
+	}
 	var intRowID int64
 	if row.RecordID.IsInt() {
 		intRowID = row.RecordID.IntValue()
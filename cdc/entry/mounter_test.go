@@ -291,7 +291,7 @@ func testMounterDisableOldValue(t *testing.T, tc struct {
 	ver, err := store.CurrentVersion(oracle.GlobalTxnScope)
 	require.Nil(t, err)
 	scheamStorage.AdvanceResolvedTs(ver.Ver)
-	mounter := NewMounter(scheamStorage, "c1", time.UTC, false).(*mounterImpl)
+	mounter := NewMounter(scheamStorage, "c1", time.UTC, false, nil).(*mounterImpl)
 	mounter.tz = time.Local
 	ctx := context.Background()
 
@@ -0,0 +1,110 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	filter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// RowImageSelector returns the row image mode of a table.
+type RowImageSelector func(schema, table string) config.RowImage
+
+// NewRowImageSelector creates a RowImageSelector from the row image rules of
+// a changefeed, nil is returned if all the tables use the full row image.
+func NewRowImageSelector(cfg *config.ReplicaConfig) (RowImageSelector, error) {
+	if !cfg.HasPartialRowImage() {
+		return nil, nil
+	}
+	type rule struct {
+		filter.Filter
+		image config.RowImage
+	}
+	rules := make([]rule, 0, len(cfg.RowImages))
+	for _, ruleConfig := range cfg.RowImages {
+		f, err := filter.Parse(ruleConfig.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+		}
+		if !cfg.CaseSensitive {
+			f = filter.CaseInsensitive(f)
+		}
+		rules = append(rules, rule{Filter: f, image: ruleConfig.Image})
+	}
+	return func(schema, table string) config.RowImage {
+		for _, r := range rules {
+			if r.MatchTable(schema, table) {
+				return r.image
+			}
+		}
+		return config.RowImageFull
+	}, nil
+}
+
+// applyRowImage removes the columns that are not in the given row image
+// from the before and the after images of a row. The before image is kept
+// as is if the table has no handle key, since all the columns are needed to
+// identify the row.
+func applyRowImage(image config.RowImage, preCols, cols []*model.Column) {
+	if image == config.RowImageFull || len(preCols) == 0 {
+		return
+	}
+	hasHandleKey := false
+	for _, col := range preCols {
+		if col != nil && col.Flag.IsHandleKey() {
+			hasHandleKey = true
+			break
+		}
+	}
+	if !hasHandleKey {
+		return
+	}
+
+	isUpdate := len(cols) == len(preCols)
+	for i, preCol := range preCols {
+		if preCol == nil || preCol.Flag.IsHandleKey() {
+			continue
+		}
+		changed := true
+		if isUpdate && cols[i] != nil {
+			changed = model.ColumnValueString(cols[i].Value) != model.ColumnValueString(preCol.Value)
+		}
+		switch image {
+		case config.RowImageMinimal:
+			preCols[i] = nil
+			if isUpdate && !changed {
+				cols[i] = nil
+			}
+		case config.RowImageNoBlob:
+			if !isBlobColumn(preCol) {
+				continue
+			}
+			preCols[i] = nil
+			if isUpdate && !changed {
+				cols[i] = nil
+			}
+		}
+	}
+}
+
+func isBlobColumn(col *model.Column) bool {
+	switch col.Type {
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRowImageSelector(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	selector, err := NewRowImageSelector(cfg)
+	require.Nil(t, err)
+	require.Nil(t, selector)
+
+	cfg.RowImages = []*config.RowImageRule{
+		{Matcher: []string{"test.wide"}, Image: config.RowImageMinimal},
+		{Matcher: []string{"test.*"}, Image: config.RowImageNoBlob},
+	}
+	selector, err = NewRowImageSelector(cfg)
+	require.Nil(t, err)
+	require.Equal(t, config.RowImageMinimal, selector("test", "wide"))
+	require.Equal(t, config.RowImageNoBlob, selector("test", "t1"))
+	require.Equal(t, config.RowImageNoBlob, selector("test", "WIDE"))
+	require.Equal(t, config.RowImageFull, selector("other", "t1"))
+
+	cfg.CaseSensitive = false
+	selector, err = NewRowImageSelector(cfg)
+	require.Nil(t, err)
+	require.Equal(t, config.RowImageMinimal, selector("test", "WIDE"))
+
+	cfg.RowImages[0].Matcher = []string{"["}
+	_, err = NewRowImageSelector(cfg)
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", err)
+}
+
+func TestApplyRowImage(t *testing.T) {
+	t.Parallel()
+
+	newCols := func(id int, name string, text string) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: id, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Type: mysql.TypeVarchar, Value: name},
+			{Name: "text", Type: mysql.TypeBlob, Value: []byte(text)},
+		}
+	}
+	names := func(cols []*model.Column) []string {
+		var res []string
+		for _, col := range cols {
+			if col != nil {
+				res = append(res, col.Name)
+			}
+		}
+		return res
+	}
+
+	testCases := []struct {
+		image         config.RowImage
+		delete        bool
+		expectPreCols []string
+		expectCols    []string
+	}{
+		{image: config.RowImageFull, expectPreCols: []string{"id", "name", "text"}, expectCols: []string{"id", "name", "text"}},
+		{image: config.RowImageMinimal, expectPreCols: []string{"id"}, expectCols: []string{"id", "name"}},
+		{image: config.RowImageNoBlob, expectPreCols: []string{"id", "name"}, expectCols: []string{"id", "name"}},
+		{image: config.RowImageFull, delete: true, expectPreCols: []string{"id", "name", "text"}},
+		{image: config.RowImageMinimal, delete: true, expectPreCols: []string{"id"}},
+		{image: config.RowImageNoBlob, delete: true, expectPreCols: []string{"id", "name"}},
+	}
+	for _, tc := range testCases {
+		preCols := newCols(1, "a", "blob")
+		var cols []*model.Column
+		if !tc.delete {
+			cols = newCols(1, "b", "blob")
+		}
+		applyRowImage(tc.image, preCols, cols)
+		require.Equal(t, tc.expectPreCols, names(preCols), tc.image)
+		require.Equal(t, tc.expectCols, names(cols), tc.image)
+	}
+
+	// Changed BLOB columns are kept in the after image.
+	preCols := newCols(1, "a", "blob")
+	cols := newCols(1, "a", "new blob")
+	applyRowImage(config.RowImageNoBlob, preCols, cols)
+	require.Equal(t, []string{"id", "name"}, names(preCols))
+	require.Equal(t, []string{"id", "name", "text"}, names(cols))
+
+	// Inserts are never changed.
+	cols = newCols(1, "a", "blob")
+	applyRowImage(config.RowImageMinimal, nil, cols)
+	require.Equal(t, []string{"id", "name", "text"}, names(cols))
+
+	// All the columns are kept if there is no handle key.
+	preCols = newCols(1, "a", "blob")
+	preCols[0].Flag = 0
+	cols = newCols(1, "b", "blob")
+	cols[0].Flag = 0
+	applyRowImage(config.RowImageMinimal, preCols, cols)
+	require.Equal(t, []string{"id", "name", "text"}, names(preCols))
+	require.Equal(t, []string{"id", "name", "text"}, names(cols))
+}
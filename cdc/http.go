@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -37,11 +38,18 @@ func RegisterRoutes(
 	capture *capture.Capture,
 	registry prometheus.Gatherer,
 ) {
+	// authentication must be installed before any routes are registered
+	serverConfig := config.GetGlobalServerConfig()
+	if conf := serverConfig.APIAuth; conf != nil && conf.Enable {
+		router.Use(api.AuthMiddleware(conf, serverConfig.Security))
+	}
+
 	// online docs
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Open API
 	api.RegisterOpenAPIRoutes(router, api.NewOpenAPI(capture))
+	api.RegisterOpenAPIV2Routes(router, api.NewOpenAPIV2(capture))
 
 	// Owner API
 	api.RegisterOwnerAPIRoutes(router, capture)
@@ -32,9 +32,12 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/pdtime"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/pingcap/tiflow/pkg/regionspan"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/pingcap/tiflow/pkg/txnutil"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
@@ -177,22 +180,24 @@ type regionFeedState struct {
 	lock           sync.RWMutex
 	initialized    bool
 	matcher        *matcher
+	requestedAt    time.Time
 	startFeedTime  time.Time
 	lastResolvedTs uint64
 }
 
 func newRegionFeedState(sri singleRegionInfo, requestID uint64) *regionFeedState {
 	return &regionFeedState{
-		sri:       sri,
-		requestID: requestID,
-		stopped:   0,
+		sri:         sri,
+		requestID:   requestID,
+		stopped:     0,
+		requestedAt: time.Now(),
 	}
 }
 
-func (s *regionFeedState) start() {
+func (s *regionFeedState) start(quota *memquota.Quota) {
 	s.startFeedTime = time.Now()
 	s.lastResolvedTs = s.sri.ts
-	s.matcher = newMatcher()
+	s.matcher = newMatcher(quota)
 }
 
 func (s *regionFeedState) markStopped() {
@@ -282,9 +287,10 @@ func (rl *regionEventFeedLimiters) getLimiter(regionID uint64) *rate.Limiter {
 	return limiter
 }
 
-// eventFeedStream stores an EventFeed stream and pointer to the underlying gRPC connection
+// eventFeedStream stores an EventFeed stream and pointer to the underlying gRPC connection,
+// conn is nil if the stream is multiplexed over the shared streams.
 type eventFeedStream struct {
-	client cdcpb.ChangeData_EventFeedClient
+	client eventFeedClient
 	conn   *sharedConn
 }
 
@@ -318,6 +324,10 @@ type CDCClient struct {
 	changefeed  string
 
 	regionLimiters *regionEventFeedLimiters
+	// storeLimiters and sharedStreams are shared by all clients of a
+	// capture, they are nil if the gRPC pool does not share them.
+	storeLimiters *storeLimiters
+	sharedStreams *sharedStreams
 }
 
 // NewCDCClient creates a CDCClient instance
@@ -332,7 +342,7 @@ func NewCDCClient(
 ) (c CDCKVClient) {
 	clusterID := pd.GetClusterID(ctx)
 
-	c = &CDCClient{
+	client := &CDCClient{
 		clusterID:      clusterID,
 		pd:             pd,
 		kvStorage:      kvStorage,
@@ -342,6 +352,13 @@ func NewCDCClient(
 		changefeed:     changefeed,
 		regionLimiters: defaultRegionEventFeedLimiters,
 	}
+	if sharer, ok := grpcPool.(eventFeedSharer); ok {
+		client.storeLimiters = sharer.storeLimiters()
+		if config.GetGlobalServerConfig().KVClient.EnableMultiplexing {
+			client.sharedStreams = sharer.sharedStreams()
+		}
+	}
+	c = client
 	return
 }
 
@@ -393,6 +410,21 @@ func (c *CDCClient) newStream(ctx context.Context, addr string, storeID uint64)
 	return
 }
 
+// newEventFeedStream creates a stream to the store for a session, the stream is
+// multiplexed over the shared streams if multiplexing is enabled.
+func (c *CDCClient) newEventFeedStream(
+	ctx context.Context, addr string, storeID uint64,
+) (*eventFeedStream, error) {
+	if c.sharedStreams == nil {
+		return c.newStream(ctx, addr, storeID)
+	}
+	stream, err := c.sharedStreams.open(ctx, c, addr, storeID)
+	if err != nil {
+		return nil, err
+	}
+	return &eventFeedStream{client: stream}, nil
+}
+
 // PullerInitialization is a workaround to solved cyclic import.
 type PullerInitialization interface {
 	IsInitialized() bool
@@ -452,6 +484,11 @@ type eventFeedSession struct {
 
 	rangeLock      *regionspan.RegionRangeLock
 	enableOldValue bool
+	// quota accounts the memory of cached prewrite rows of all regions.
+	quota *memquota.Quota
+	// priority is the priority of the changefeed, events of changefeeds
+	// with higher priorities are processed first by the shared workers.
+	priority priority.Priority
 
 	// To identify metrics of different eventFeedSession
 	id                string
@@ -487,13 +524,15 @@ func newEventFeedSession(
 		client:            client,
 		totalSpan:         totalSpan,
 		eventCh:           eventCh,
-		regionRouter:      NewSizedRegionRouter(ctx, kvClientCfg.RegionScanLimit),
+		regionRouter:      NewSizedRegionRouter(ctx, kvClientCfg.RegionScanLimit, client.storeLimiters),
 		regionCh:          make(chan singleRegionInfo, defaultRegionChanSize),
 		errCh:             make(chan regionErrorInfo, defaultRegionChanSize),
 		requestRangeCh:    make(chan rangeRequestTask, defaultRegionChanSize),
 		rateLimitQueue:    make([]regionErrorInfo, 0, defaultRegionRateLimitQueueSize),
 		rangeLock:         rangeLock,
 		enableOldValue:    enableOldValue,
+		quota:             memquota.QuotaFromCtx(ctx, memquota.ComponentKVClient),
+		priority:          util.ChangefeedPriorityFromCtx(ctx),
 		lockResolver:      lockResolver,
 		isPullerInit:      isPullerInit,
 		id:                id,
@@ -760,7 +799,7 @@ func (s *eventFeedSession) requestRegionToStore(
 				zap.String("addr", rpcCtx.Addr))
 			streamCtx, streamCancel := context.WithCancel(ctx)
 			_ = streamCancel // to avoid possible context leak warning from govet
-			stream, err = s.client.newStream(streamCtx, rpcCtx.Addr, storeID)
+			stream, err = s.client.newEventFeedStream(streamCtx, rpcCtx.Addr, storeID)
 			if err != nil {
 				// if get stream failed, maybe the store is down permanently, we should try to relocate the active store
 				log.Warn("get grpc stream client failed",
@@ -779,6 +818,8 @@ func (s *eventFeedSession) requestRegionToStore(
 				}
 				bo := tikv.NewBackoffer(ctx, tikvRequestMaxBackoff)
 				s.client.regionCache.OnSendFail(bo, rpcCtx, regionScheduleReload, err)
+				s.regionRouter.Revoke(rpcCtx.Addr)
+				s.onStoreError(rpcCtx.Addr)
 				errInfo := newRegionErrorInfo(sri, &connectToStoreErr{})
 				s.onRegionFail(ctx, errInfo, false /* revokeToken */)
 				continue
@@ -838,6 +879,8 @@ func (s *eventFeedSession) requestRegionToStore(
 				continue
 			}
 
+			s.regionRouter.Revoke(rpcCtx.Addr)
+			s.onStoreError(rpcCtx.Addr)
 			errInfo := newRegionErrorInfo(sri, &sendRequestToStoreErr{})
 			s.onRegionFail(ctx, errInfo, false /* revokeToken */)
 		} else {
@@ -1130,7 +1173,7 @@ func (s *eventFeedSession) receiveFromStream(
 	g *errgroup.Group,
 	addr string,
 	storeID uint64,
-	stream cdcpb.ChangeData_EventFeedClient,
+	stream eventFeedClient,
 	pendingRegions *syncRegionFeedStateMap,
 ) error {
 	// Cancel the pending regions if the stream failed. Otherwise it will remain unhandled in the pendingRegions list
@@ -1194,6 +1237,11 @@ func (s *eventFeedSession) receiveFromStream(
 					zap.Uint64("storeID", storeID),
 					zap.Error(err),
 				)
+				// A session sharing a stream is closed with ResourceExhausted
+				// if it is too slow, which is not an error of the store.
+				if status.Code(errors.Cause(err)) != codes.ResourceExhausted {
+					s.onStoreError(addr)
+				}
 				// Note that pd need at lease 10s+ to tag a kv node as disconnect if kv node down
 				// tikv raft need wait (raft-base-tick-interval * raft-election-timeout-ticks) 10s to start a new
 				// election
@@ -1297,7 +1345,7 @@ func (s *eventFeedSession) sendRegionChangeEvent(
 			return nil
 		}
 
-		state.start()
+		state.start(s.quota)
 		worker.setRegionState(event.RegionId, state)
 	} else if state.isStopped() {
 		log.Warn("drop event due to region feed stopped",
@@ -1362,7 +1410,9 @@ func (s *eventFeedSession) deleteStream(storeAddr string) {
 	s.streamsLock.Lock()
 	defer s.streamsLock.Unlock()
 	if stream, ok := s.streams[storeAddr]; ok {
-		s.client.grpcPool.ReleaseConn(stream.conn, storeAddr)
+		if stream.conn != nil {
+			s.client.grpcPool.ReleaseConn(stream.conn, storeAddr)
+		}
 		delete(s.streams, storeAddr)
 	}
 	if cancel, ok := s.streamsCanceller[storeAddr]; ok {
@@ -1378,6 +1428,24 @@ func (s *eventFeedSession) getStream(storeAddr string) (stream *eventFeedStream,
 	return
 }
 
+// onStoreError decreases the region scan limit of the store when the store
+// fails a request or a stream.
+func (s *eventFeedSession) onStoreError(storeAddr string) {
+	if s.client.storeLimiters != nil {
+		s.client.storeLimiters.get(storeAddr).onError()
+	}
+}
+
+// onRegionInitialized increases the region scan limit of the store if it
+// responds to the request of the region in time.
+func (s *eventFeedSession) onRegionInitialized(state *regionFeedState) {
+	if s.client.storeLimiters == nil || state.requestedAt.IsZero() {
+		return
+	}
+	s.client.storeLimiters.get(state.sri.rpcCtx.Addr).onSuccess(
+		state.startFeedTime.Sub(state.requestedAt))
+}
+
 func (s *eventFeedSession) getStreamCancel(storeAddr string) (cancel context.CancelFunc, ok bool) {
 	s.streamsLock.RLock()
 	defer s.streamsLock.RUnlock()
@@ -1405,6 +1473,7 @@ func assembleRowEvent(regionID uint64, entry *cdcpb.Event_Row, enableOldValue bo
 			StartTs:  entry.StartTs,
 			CRTs:     entry.CommitTs,
 			RegionID: regionID,
+			Trace:    tracing.NewTrace(entry.StartTs, entry.CommitTs, regionID),
 		},
 	}
 
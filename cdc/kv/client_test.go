@@ -3576,3 +3576,105 @@ func TestRegionErrorInfoLogRateLimitedHint(t *testing.T) {
 	require.True(t, errInfo.logRateLimitedHint())
 	require.False(t, errInfo.logRateLimitedHint())
 }
+
+// TestMultiplexEventFeeds tests that the event feeds of different changefeeds
+// share the same EventFeed stream when multiplexing is enabled.
+func TestMultiplexEventFeeds(t *testing.T) {
+	conf := config.GetDefaultServerConfig()
+	conf.KVClient.EnableMultiplexing = true
+	config.StoreGlobalServerConfig(conf)
+	defer config.StoreGlobalServerConfig(config.GetDefaultServerConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	ch1 := make(chan *cdcpb.ChangeDataEvent, 10)
+	srv1 := newMockChangeDataService(t, ch1)
+	requestCh := make(chan *cdcpb.ChangeDataRequest, 10)
+	srv1.recvLoop = func(server cdcpb.ChangeData_EventFeedServer) {
+		for {
+			req, err := server.Recv()
+			if err != nil {
+				return
+			}
+			requestCh <- req
+		}
+	}
+	server1, addr1 := newMockService(ctx, t, srv1, wg)
+	defer func() {
+		cancel()
+		close(ch1)
+		server1.Stop()
+		wg.Wait()
+	}()
+
+	rpcClient, cluster, pdClient, err := testutils.NewMockTiKV("", mockcopr.NewCoprRPCHandler())
+	require.Nil(t, err)
+	pdClient = &mockPDClient{Client: pdClient, versionGen: defaultVersionGen}
+	kvStorage, err := tikv.NewTestTiKVStore(rpcClient, pdClient, nil, nil, 0)
+	require.Nil(t, err)
+	defer kvStorage.Close() //nolint:errcheck
+
+	cluster.AddStore(1, addr1)
+	cluster.Bootstrap(3, []uint64{1}, []uint64{4}, 4)
+	cluster.SplitRaw(3, 5, []byte("b"), []uint64{6}, 6)
+
+	lockResolver := txnutil.NewLockerResolver(kvStorage, "changefeed-test", util.RoleTester)
+	isPullInit := &mockPullerInit{}
+	grpcPool := NewGrpcPoolImpl(ctx, &security.Credential{})
+	defer grpcPool.Close()
+	regionCache := tikv.NewRegionCache(pdClient)
+	defer regionCache.Close()
+
+	spans := []regionspan.ComparableSpan{
+		{Start: []byte("a"), End: []byte("b")},
+		{Start: []byte("b"), End: []byte("c")},
+	}
+	eventChs := make([]chan model.RegionFeedEvent, 0, len(spans))
+	for i, span := range spans {
+		cdcClient := NewCDCClient(ctx, pdClient, kvStorage, grpcPool, regionCache,
+			pdtime.NewClock4Test(), fmt.Sprintf("changefeed-%d", i))
+		eventCh := make(chan model.RegionFeedEvent, 50)
+		eventChs = append(eventChs, eventCh)
+		span := span
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := cdcClient.EventFeed(ctx, span, 100, false, lockResolver, isPullInit, eventCh)
+			require.Equal(t, context.Canceled, errors.Cause(err))
+		}()
+	}
+
+	requests := make(map[uint64]uint64)
+	for len(requests) < len(spans) {
+		select {
+		case req := <-requestCh:
+			requests[req.RegionId] = req.RequestId
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "region requests are not received")
+		}
+	}
+	// the regions of both changefeeds are requested over one stream.
+	require.Equal(t, uint64(1), atomic.LoadUint64(&srv1.eventFeedID))
+
+	events := &cdcpb.ChangeDataEvent{ResolvedTs: &cdcpb.ResolvedTs{Regions: []uint64{3, 5}, Ts: 120}}
+	for regionID, requestID := range requests {
+		events.Events = append(events.Events, mockInitializedEvent(regionID, requestID).Events...)
+	}
+	ch1 <- events
+
+	for i, eventCh := range eventChs {
+	loop:
+		for {
+			select {
+			case event := <-eventCh:
+				require.NotNil(t, event.Resolved)
+				require.Equal(t, spans[i], event.Resolved.Span)
+				if event.Resolved.ResolvedTs == 120 {
+					break loop
+				}
+			case <-time.After(5 * time.Second):
+				require.FailNow(t, "resolved ts is not received")
+			}
+		}
+	}
+}
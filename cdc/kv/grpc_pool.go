@@ -40,3 +40,12 @@ type GrpcPool interface {
 	// Close tears down all ClientConns maintained in pool
 	Close()
 }
+
+// eventFeedSharer is implemented by the pools that share the per-store
+// resources of event feeds among all kv clients of a capture.
+type eventFeedSharer interface {
+	// storeLimiters returns the limiters of region scans in each store
+	storeLimiters() *storeLimiters
+	// sharedStreams returns the EventFeed streams shared by kv clients
+	sharedStreams() *sharedStreams
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
 	"go.uber.org/zap"
@@ -192,15 +193,33 @@ type GrpcPoolImpl struct {
 
 	// lifecycles of all gPRC connections are bounded to this context
 	ctx context.Context
+
+	// limiters and streams are shared by all kv clients using the pool.
+	limiters *storeLimiters
+	streams  *sharedStreams
 }
 
 // NewGrpcPoolImpl creates a new GrpcPoolImpl instance
 func NewGrpcPoolImpl(ctx context.Context, credential *security.Credential) *GrpcPoolImpl {
-	return &GrpcPoolImpl{
+	pool := &GrpcPoolImpl{
 		credential:  credential,
 		bucketConns: make(map[string]*connArray),
 		ctx:         ctx,
+		limiters: newStoreLimiters(
+			config.GetGlobalServerConfig().KVClient.StoreRegionScanLimit),
 	}
+	pool.streams = newSharedStreams(ctx, pool)
+	return pool
+}
+
+// storeLimiters implements eventFeedSharer.storeLimiters
+func (pool *GrpcPoolImpl) storeLimiters() *storeLimiters {
+	return pool.limiters
+}
+
+// sharedStreams implements eventFeedSharer.sharedStreams
+func (pool *GrpcPoolImpl) sharedStreams() *sharedStreams {
+	return pool.streams
 }
 
 // GetConn implements GrpcPool.GetConn
@@ -259,6 +278,7 @@ func (pool *GrpcPoolImpl) RecycleConn(ctx context.Context) {
 
 // Close implements GrpcPool.Close
 func (pool *GrpcPoolImpl) Close() {
+	pool.streams.close()
 	pool.poolMu.Lock()
 	defer pool.poolMu.Unlock()
 	for _, bucket := range pool.bucketConns {
@@ -16,6 +16,7 @@ package kv
 import (
 	"github.com/pingcap/kvproto/pkg/cdcpb"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"go.uber.org/zap"
 )
 
@@ -32,14 +33,21 @@ type matcher struct {
 	// TODO : clear the single prewrite
 	unmatchedValue map[matchKey]*cdcpb.Event_Row
 	cachedCommit   []*cdcpb.Event_Row
+	// quota accounts the memory of unmatched prewrite rows.
+	quota *memquota.Quota
 }
 
-func newMatcher() *matcher {
+func newMatcher(quota *memquota.Quota) *matcher {
 	return &matcher{
 		unmatchedValue: make(map[matchKey]*cdcpb.Event_Row),
+		quota:          quota,
 	}
 }
 
+func rowSize(row *cdcpb.Event_Row) uint64 {
+	return uint64(len(row.GetKey()) + len(row.GetValue()) + len(row.GetOldValue()))
+}
+
 func (m *matcher) putPrewriteRow(row *cdcpb.Event_Row) {
 	key := newMatchKey(row)
 	// tikv may send a fake prewrite event with empty value caused by txn heartbeat.
@@ -50,9 +58,15 @@ func (m *matcher) putPrewriteRow(row *cdcpb.Event_Row) {
 	// but the old value of the fake prewrite event is not empty.
 	// We can distinguish fake prewrite events by whether the value is empty,
 	// no matter the old-value is enabled or disabled
-	if _, exist := m.unmatchedValue[key]; exist && len(row.GetValue()) == 0 {
+	old, exist := m.unmatchedValue[key]
+	if exist && len(row.GetValue()) == 0 {
 		return
 	}
+	if exist {
+		m.quota.Release(rowSize(old))
+	}
+	// Rows must be cached to make progress, so never block here.
+	m.quota.ForceReserve(rowSize(row))
 	m.unmatchedValue[key] = row
 }
 
@@ -63,6 +77,7 @@ func (m *matcher) matchRow(row *cdcpb.Event_Row) bool {
 		row.Value = value.GetValue()
 		row.OldValue = value.GetOldValue()
 		delete(m.unmatchedValue, newMatchKey(row))
+		m.quota.Release(rowSize(value))
 		return true
 	}
 	return false
@@ -95,5 +110,18 @@ func (m *matcher) matchCachedRow() []*cdcpb.Event_Row {
 }
 
 func (m *matcher) rollbackRow(row *cdcpb.Event_Row) {
-	delete(m.unmatchedValue, newMatchKey(row))
+	key := newMatchKey(row)
+	if value, exist := m.unmatchedValue[key]; exist {
+		delete(m.unmatchedValue, key)
+		m.quota.Release(rowSize(value))
+	}
+}
+
+// clear drops all cached rows, it is called when the region is stopped.
+func (m *matcher) clear() {
+	for _, value := range m.unmatchedValue {
+		m.quota.Release(rowSize(value))
+	}
+	m.unmatchedValue = make(map[matchKey]*cdcpb.Event_Row)
+	m.cachedCommit = nil
 }
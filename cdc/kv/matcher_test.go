@@ -17,12 +17,13 @@ import (
 	"testing"
 
 	"github.com/pingcap/kvproto/pkg/cdcpb"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/stretchr/testify/require"
 )
 
 func TestMatchRow(t *testing.T) {
 	t.Parallel()
-	matcher := newMatcher()
+	matcher := newMatcher(nil)
 	matcher.putPrewriteRow(&cdcpb.Event_Row{
 		StartTs: 1,
 		Key:     []byte("k1"),
@@ -70,7 +71,7 @@ func TestMatchRow(t *testing.T) {
 
 func TestMatchFakePrewrite(t *testing.T) {
 	t.Parallel()
-	matcher := newMatcher()
+	matcher := newMatcher(nil)
 	matcher.putPrewriteRow(&cdcpb.Event_Row{
 		StartTs:  1,
 		Key:      []byte("k1"),
@@ -102,7 +103,7 @@ func TestMatchFakePrewrite(t *testing.T) {
 
 func TestMatchMatchCachedRow(t *testing.T) {
 	t.Parallel()
-	matcher := newMatcher()
+	matcher := newMatcher(nil)
 	require.Equal(t, 0, len(matcher.matchCachedRow()))
 	matcher.cacheCommitRow(&cdcpb.Event_Row{
 		StartTs:  1,
@@ -170,3 +171,32 @@ func TestMatchMatchCachedRow(t *testing.T) {
 		OldValue: []byte("ov2"),
 	}}, matcher.matchCachedRow())
 }
+
+func TestMatcherQuota(t *testing.T) {
+	t.Parallel()
+	governor := memquota.NewGovernor(0)
+	quota := governor.NewQuota("test-matcher-quota", memquota.ComponentKVClient)
+	defer quota.Close()
+	matcher := newMatcher(quota)
+
+	matcher.putPrewriteRow(&cdcpb.Event_Row{StartTs: 1, Key: []byte("k1"), Value: []byte("v1")})
+	matcher.putPrewriteRow(&cdcpb.Event_Row{StartTs: 2, Key: []byte("k2"), Value: []byte("v2")})
+	require.Equal(t, uint64(8), quota.Used())
+	// A fake prewrite does not overwrite the cached row.
+	matcher.putPrewriteRow(&cdcpb.Event_Row{StartTs: 1, Key: []byte("k1"), OldValue: []byte("ov1")})
+	require.Equal(t, uint64(8), quota.Used())
+	// The replaced row is released.
+	matcher.putPrewriteRow(&cdcpb.Event_Row{StartTs: 1, Key: []byte("k1"), Value: []byte("v11")})
+	require.Equal(t, uint64(9), quota.Used())
+
+	require.True(t, matcher.matchRow(&cdcpb.Event_Row{StartTs: 1, Key: []byte("k1")}))
+	require.Equal(t, uint64(4), quota.Used())
+	matcher.rollbackRow(&cdcpb.Event_Row{StartTs: 3, Key: []byte("k2")})
+	require.Equal(t, uint64(4), quota.Used())
+
+	matcher.putPrewriteRow(&cdcpb.Event_Row{StartTs: 3, Key: []byte("k3"), Value: []byte("v3")})
+	matcher.clear()
+	require.Equal(t, uint64(0), quota.Used())
+	require.Equal(t, uint64(0), governor.Used())
+	require.False(t, matcher.matchRow(&cdcpb.Event_Row{StartTs: 2, Key: []byte("k2")}))
+}
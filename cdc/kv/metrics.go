@@ -101,6 +101,27 @@ var (
 			Name:      "grpc_stream_count",
 			Help:      "active stream count of each gRPC connection",
 		}, []string{"store"})
+	storeRegionScanLimitGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "store_region_scan_limit",
+			Help:      "adaptive limit of region incremental scans of all tables in each store",
+		}, []string{"store"})
+	storeRegionScanInflightGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "store_region_scan_inflight",
+			Help:      "region incremental scans in flight of all tables in each store",
+		}, []string{"store"})
+	sharedStreamGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "shared_stream_count",
+			Help:      "EventFeed streams to each store shared by all tables",
+		}, []string{"store"})
 )
 
 // InitMetrics registers all metrics in the kv package
@@ -116,6 +137,9 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(cachedRegionSize)
 	registry.MustRegister(batchResolvedEventSize)
 	registry.MustRegister(grpcPoolStreamGauge)
+	registry.MustRegister(storeRegionScanLimitGauge)
+	registry.MustRegister(storeRegionScanInflightGauge)
+	registry.MustRegister(sharedStreamGauge)
 
 	// Register client metrics to registry.
 	registry.MustRegister(grpcMetrics)
@@ -253,6 +253,7 @@ func (w *regionWorker) handleSingleRegionError(err error, state *regionFeedState
 	}
 	// We need to ensure when the error is handled, `isStopped` must be set. So set it before sending the error.
 	state.markStopped()
+	state.matcher.clear()
 	w.delRegionState(regionID)
 	failpoint.Inject("kvClientSingleFeedProcessDelay", nil)
 
@@ -420,10 +421,10 @@ func (w *regionWorker) processEvent(ctx context.Context, event *regionStatefulEv
 func (w *regionWorker) initPoolHandles(handleCount int) {
 	handles := make([]workerpool.EventHandle, 0, handleCount)
 	for i := 0; i < handleCount; i++ {
-		poolHandle := regionWorkerPool.RegisterEvent(func(ctx context.Context, eventI interface{}) error {
+		poolHandle := regionWorkerPool.RegisterEventWithPriority(func(ctx context.Context, eventI interface{}) error {
 			event := eventI.(*regionStatefulEvent)
 			return w.processEvent(ctx, event)
-		}).OnExit(func(err error) {
+		}, w.session.priority).OnExit(func(err error) {
 			w.onHandleExit(err)
 		})
 		handles = append(handles, poolHandle)
@@ -662,6 +663,7 @@ func (w *regionWorker) handleEventEntry(
 
 			state.initialized = true
 			w.session.regionRouter.Release(state.sri.rpcCtx.Addr)
+			w.session.onRegionInitialized(state)
 			cachedEvents := state.matcher.matchCachedRow()
 			for _, cachedEvent := range cachedEvents {
 				revent, err := assembleRowEvent(regionID, cachedEvent, w.enableOldValue)
@@ -800,6 +802,7 @@ func (w *regionWorker) evictAllRegions() {
 				return true
 			}
 			state.markStopped()
+			state.matcher.clear()
 			w.delRegionState(state.sri.verID.GetID())
 			if state.lastResolvedTs > state.sri.ts {
 				state.sri.ts = state.lastResolvedTs
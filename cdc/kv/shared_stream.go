@@ -0,0 +1,473 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/kvproto/pkg/cdcpb"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// muxStreamChanSize is the channel size of the events routed to a
+	// multiplexed stream. A shared stream never waits for a session, the
+	// session is closed once its channel is full, so that a slow session
+	// does not stall the other sessions sharing the stream.
+	muxStreamChanSize = 256
+	// TiKV does not support deregistering a region, so the regions of the
+	// sessions that are gone keep pushing events to a shared stream until
+	// they fail or the stream is closed. A shared stream is retired, i.e. it
+	// takes no new region, once such regions outnumber the used ones, and it
+	// is closed after all the sessions using it are gone.
+	minUnusedRegionsToRetire = 128
+)
+
+// eventFeedClient is the client side of an EventFeed stream, which is either
+// a dedicated gRPC stream or a stream multiplexed over the shared ones.
+type eventFeedClient interface {
+	Send(*cdcpb.ChangeDataRequest) error
+	Recv() (*cdcpb.ChangeDataEvent, error)
+	CloseSend() error
+}
+
+// sharedStreams multiplexes the region requests of all the event feed
+// sessions of a capture over a few EventFeed streams to each store, instead
+// of opening a stream per session per store.
+//
+// TiKV rejects a region requested twice on the same stream, so a request is
+// sent over a shared stream that does not have the region yet, and a new
+// shared stream is created if there is no such stream.
+type sharedStreams struct {
+	// lifecycles of all shared streams are bounded to this context
+	ctx      context.Context
+	grpcPool GrpcPool
+	// newStream creates a gRPC stream to the store, it can be replaced in
+	// tests.
+	newStream func(
+		ctx context.Context, client *CDCClient, addr string, storeID uint64,
+	) (*eventFeedStream, error)
+	// dialMu serializes the creation of the first shared stream to a store,
+	// so that sessions started together do not open a stream each.
+	dialMu sync.Mutex
+
+	mu     sync.Mutex
+	stores map[string][]*sharedStream
+}
+
+// sharedStream is an EventFeed stream shared by sessions, all fields except
+// stream, cancel and sendMu are protected by sharedStreams.mu.
+type sharedStream struct {
+	addr   string
+	stream *eventFeedStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	// sendMu serializes the requests, a gRPC stream is not safe for
+	// concurrent sends.
+	sendMu sync.Mutex
+
+	// regions maps region IDs to their subscriptions, the subscription of a
+	// region whose session is gone is kept with a nil stream until TiKV
+	// reports an error of the region or the shared stream is closed.
+	regions map[uint64]*muxSubscription
+	// users maps the sessions using the stream to their region counts.
+	users   map[*muxStream]int
+	used    int
+	retired bool
+	closed  bool
+}
+
+type muxSubscription struct {
+	requestID uint64
+	stream    *muxStream
+}
+
+func newSharedStreams(ctx context.Context, grpcPool GrpcPool) *sharedStreams {
+	return &sharedStreams{
+		ctx:      ctx,
+		grpcPool: grpcPool,
+		newStream: func(
+			ctx context.Context, client *CDCClient, addr string, storeID uint64,
+		) (*eventFeedStream, error) {
+			return client.newStream(ctx, addr, storeID)
+		},
+		stores: make(map[string][]*sharedStream),
+	}
+}
+
+// open creates the stream of a session to a store, the stream is closed when
+// ctx is done or CloseSend is called.
+func (p *sharedStreams) open(
+	ctx context.Context, client *CDCClient, addr string, storeID uint64,
+) (*muxStream, error) {
+	// Make sure that the store is reachable, so that a session handles an
+	// unreachable store the same way as it does with a dedicated stream.
+	p.dialMu.Lock()
+	p.mu.Lock()
+	reachable := len(p.stores[addr]) > 0
+	p.mu.Unlock()
+	if !reachable {
+		if err := p.newSharedStream(client, addr, storeID); err != nil {
+			p.dialMu.Unlock()
+			return nil, err
+		}
+	}
+	p.dialMu.Unlock()
+
+	m := &muxStream{
+		pool:    p,
+		client:  client,
+		addr:    addr,
+		storeID: storeID,
+		eventCh: make(chan *cdcpb.ChangeDataEvent, muxStreamChanSize),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.close(status.FromContextError(ctx.Err()).Err())
+		case <-m.done:
+		}
+	}()
+	return m, nil
+}
+
+func (p *sharedStreams) newSharedStream(
+	client *CDCClient, addr string, storeID uint64,
+) error {
+	ctx, cancel := context.WithCancel(p.ctx)
+	stream, err := p.newStream(ctx, client, addr, storeID)
+	if err != nil {
+		cancel()
+		return err
+	}
+	s := &sharedStream{
+		addr:    addr,
+		stream:  stream,
+		ctx:     ctx,
+		cancel:  cancel,
+		regions: make(map[uint64]*muxSubscription),
+		users:   make(map[*muxStream]int),
+	}
+	p.mu.Lock()
+	p.stores[addr] = append(p.stores[addr], s)
+	p.mu.Unlock()
+	sharedStreamGauge.WithLabelValues(addr).Inc()
+	log.Info("created shared stream to store",
+		zap.String("addr", addr), zap.Uint64("storeID", storeID))
+
+	go p.receive(s)
+	return nil
+}
+
+// subscribe picks a shared stream for the region request of the session.
+func (p *sharedStreams) subscribe(
+	m *muxStream, req *cdcpb.ChangeDataRequest,
+) (*sharedStream, error) {
+	for {
+		p.mu.Lock()
+		select {
+		case <-m.done:
+			p.mu.Unlock()
+			return nil, m.err
+		default:
+		}
+		for _, s := range p.stores[m.addr] {
+			if s.retired {
+				continue
+			}
+			if _, ok := s.regions[req.RegionId]; ok {
+				continue
+			}
+			s.regions[req.RegionId] = &muxSubscription{requestID: req.RequestId, stream: m}
+			s.users[m]++
+			s.used++
+			p.mu.Unlock()
+			return s, nil
+		}
+		p.mu.Unlock()
+		if err := p.newSharedStream(m.client, m.addr, m.storeID); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// unsubscribe removes the region of the session whose request is not sent.
+func (p *sharedStreams) unsubscribe(s *sharedStream, m *muxStream, req *cdcpb.ChangeDataRequest) {
+	p.mu.Lock()
+	var closing []*muxStream
+	if sub, ok := s.regions[req.RegionId]; ok && sub.requestID == req.RequestId {
+		delete(s.regions, req.RegionId)
+		if sub.stream != nil {
+			closing = p.releaseRegionLocked(s, m)
+		}
+	}
+	p.mu.Unlock()
+	if closing != nil {
+		p.shutdown(s, closing, nil)
+	}
+}
+
+// releaseRegionLocked decreases the region count of a user, and returns a
+// non-nil slice if the stream should be closed.
+func (p *sharedStreams) releaseRegionLocked(s *sharedStream, m *muxStream) []*muxStream {
+	s.used--
+	s.users[m]--
+	if s.retired && s.users[m] <= 0 {
+		delete(s.users, m)
+		if len(s.users) == 0 {
+			return p.removeLocked(s)
+		}
+	}
+	return nil
+}
+
+// removeLocked removes the stream from the store, and returns the sessions
+// still using it.
+func (p *sharedStreams) removeLocked(s *sharedStream) []*muxStream {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	streams := p.stores[s.addr]
+	for i := range streams {
+		if streams[i] == s {
+			p.stores[s.addr] = append(streams[:i:i], streams[i+1:]...)
+			break
+		}
+	}
+	if len(p.stores[s.addr]) == 0 {
+		delete(p.stores, s.addr)
+	}
+	users := make([]*muxStream, 0, len(s.users))
+	for m := range s.users {
+		users = append(users, m)
+	}
+	s.users = nil
+	return users
+}
+
+// shutdown closes a removed stream and the sessions using it.
+func (p *sharedStreams) shutdown(s *sharedStream, users []*muxStream, err error) {
+	s.cancel()
+	if s.stream.conn != nil {
+		p.grpcPool.ReleaseConn(s.stream.conn, s.addr)
+	}
+	sharedStreamGauge.WithLabelValues(s.addr).Dec()
+	log.Info("shared stream to store closed",
+		zap.String("addr", s.addr), zap.Error(err))
+	if err == nil {
+		err = status.Error(codes.Canceled, "shared stream closed")
+	}
+	for _, m := range users {
+		m.close(err)
+	}
+}
+
+// receive routes the events from a shared stream to the sessions.
+func (p *sharedStreams) receive(s *sharedStream) {
+	for {
+		cevent, err := s.stream.client.Recv()
+		if err != nil {
+			p.mu.Lock()
+			closed := s.closed
+			users := p.removeLocked(s)
+			p.mu.Unlock()
+			if !closed {
+				p.shutdown(s, users, err)
+			}
+			return
+		}
+		batches, closing := p.route(s, cevent)
+		for m, event := range batches {
+			select {
+			case m.eventCh <- event:
+			case <-m.done:
+			default:
+				// The session fails with a retryable error, so that the kv
+				// client requests its regions again over a new session.
+				log.Warn("session is too slow to receive events from shared stream",
+					zap.String("addr", s.addr), zap.Int("bufferedEvents", len(m.eventCh)))
+				m.close(status.Error(codes.ResourceExhausted,
+					"session is too slow to receive events from shared stream"))
+			}
+		}
+		if closing != nil {
+			p.shutdown(s, closing, nil)
+		}
+	}
+}
+
+// route splits an event of a shared stream by sessions, it also returns a
+// non-nil slice if the stream should be closed.
+func (p *sharedStreams) route(
+	s *sharedStream, cevent *cdcpb.ChangeDataEvent,
+) (map[*muxStream]*cdcpb.ChangeDataEvent, []*muxStream) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var closing []*muxStream
+	batches := make(map[*muxStream]*cdcpb.ChangeDataEvent)
+	batch := func(m *muxStream) *cdcpb.ChangeDataEvent {
+		event, ok := batches[m]
+		if !ok {
+			event = &cdcpb.ChangeDataEvent{}
+			batches[m] = event
+		}
+		return event
+	}
+	for _, event := range cevent.Events {
+		sub, ok := s.regions[event.RegionId]
+		if !ok || sub.requestID != event.RequestId {
+			continue
+		}
+		if event.GetError() != nil {
+			// TiKV stops pushing the events of a region after an error.
+			delete(s.regions, event.RegionId)
+			if sub.stream != nil {
+				if c := p.releaseRegionLocked(s, sub.stream); c != nil {
+					closing = c
+				}
+			}
+		}
+		if sub.stream == nil {
+			continue
+		}
+		b := batch(sub.stream)
+		b.Events = append(b.Events, event)
+	}
+	if cevent.ResolvedTs != nil {
+		for _, regionID := range cevent.ResolvedTs.Regions {
+			sub, ok := s.regions[regionID]
+			if !ok || sub.stream == nil {
+				continue
+			}
+			b := batch(sub.stream)
+			if b.ResolvedTs == nil {
+				b.ResolvedTs = &cdcpb.ResolvedTs{Ts: cevent.ResolvedTs.Ts}
+			}
+			b.ResolvedTs.Regions = append(b.ResolvedTs.Regions, regionID)
+		}
+	}
+	return batches, closing
+}
+
+// detach removes a closed session from all the shared streams of its store.
+func (p *sharedStreams) detach(m *muxStream) {
+	type closingStream struct {
+		stream *sharedStream
+		users  []*muxStream
+	}
+	var closing []closingStream
+
+	p.mu.Lock()
+	for _, s := range p.stores[m.addr] {
+		if _, ok := s.users[m]; !ok {
+			continue
+		}
+		delete(s.users, m)
+		for _, sub := range s.regions {
+			if sub.stream == m {
+				sub.stream = nil
+				s.used--
+			}
+		}
+		unused := len(s.regions) - s.used
+		if !s.retired && unused >= minUnusedRegionsToRetire && unused > s.used {
+			s.retired = true
+			log.Info("retire shared stream to store",
+				zap.String("addr", s.addr),
+				zap.Int("usedRegions", s.used), zap.Int("unusedRegions", unused))
+		}
+		if len(s.users) == 0 {
+			closing = append(closing, closingStream{stream: s, users: p.removeLocked(s)})
+		}
+	}
+	p.mu.Unlock()
+
+	for _, c := range closing {
+		p.shutdown(c.stream, c.users, nil)
+	}
+}
+
+// close closes all the shared streams.
+func (p *sharedStreams) close() {
+	p.mu.Lock()
+	var streams []*sharedStream
+	for _, ss := range p.stores {
+		streams = append(streams, ss...)
+	}
+	p.mu.Unlock()
+	for _, s := range streams {
+		s.cancel()
+	}
+}
+
+// muxStream is the stream of a session to a store, the requests are sent
+// over the shared streams to the store, and only the events of the regions
+// of the session are received.
+type muxStream struct {
+	pool    *sharedStreams
+	client  *CDCClient
+	addr    string
+	storeID uint64
+
+	eventCh   chan *cdcpb.ChangeDataEvent
+	done      chan struct{}
+	closeOnce sync.Once
+	err       error
+}
+
+// Send implements eventFeedClient.Send
+func (m *muxStream) Send(req *cdcpb.ChangeDataRequest) error {
+	s, err := m.pool.subscribe(m, req)
+	if err != nil {
+		return err
+	}
+	s.sendMu.Lock()
+	err = s.stream.client.Send(req)
+	s.sendMu.Unlock()
+	if err != nil {
+		m.pool.unsubscribe(s, m, req)
+		return err
+	}
+	return nil
+}
+
+// Recv implements eventFeedClient.Recv
+func (m *muxStream) Recv() (*cdcpb.ChangeDataEvent, error) {
+	select {
+	case event := <-m.eventCh:
+		return event, nil
+	case <-m.done:
+		return nil, m.err
+	}
+}
+
+// CloseSend implements eventFeedClient.CloseSend
+func (m *muxStream) CloseSend() error {
+	m.close(status.Error(codes.Canceled, "stream closed"))
+	return nil
+}
+
+func (m *muxStream) close(err error) {
+	m.closeOnce.Do(func() {
+		m.err = err
+		close(m.done)
+		m.pool.detach(m)
+	})
+}
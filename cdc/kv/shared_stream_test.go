@@ -0,0 +1,244 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/cdcpb"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockEventFeedClient is an EventFeed stream whose requests and events are
+// passed through channels.
+type mockEventFeedClient struct {
+	ctx      context.Context
+	requests chan *cdcpb.ChangeDataRequest
+	events   chan *cdcpb.ChangeDataEvent
+	errCh    chan error
+}
+
+func (c *mockEventFeedClient) Send(req *cdcpb.ChangeDataRequest) error {
+	c.requests <- req
+	return nil
+}
+
+func (c *mockEventFeedClient) Recv() (*cdcpb.ChangeDataEvent, error) {
+	select {
+	case event := <-c.events:
+		return event, nil
+	case err := <-c.errCh:
+		return nil, err
+	case <-c.ctx.Done():
+		return nil, status.FromContextError(c.ctx.Err()).Err()
+	}
+}
+
+func (c *mockEventFeedClient) CloseSend() error {
+	return nil
+}
+
+func newSharedStreams4Test(
+	ctx context.Context, t *testing.T,
+) (*sharedStreams, chan *mockEventFeedClient) {
+	grpcPool := NewGrpcPoolImpl(ctx, &security.Credential{})
+	t.Cleanup(grpcPool.Close)
+	created := make(chan *mockEventFeedClient, 16)
+	p := grpcPool.sharedStreams()
+	p.newStream = func(
+		ctx context.Context, _ *CDCClient, _ string, _ uint64,
+	) (*eventFeedStream, error) {
+		client := &mockEventFeedClient{
+			ctx:      ctx,
+			requests: make(chan *cdcpb.ChangeDataRequest, 16),
+			events:   make(chan *cdcpb.ChangeDataEvent, 16),
+			errCh:    make(chan error, 1),
+		}
+		created <- client
+		return &eventFeedStream{client: client}, nil
+	}
+	return p, created
+}
+
+func newRegionRequest(regionID, requestID uint64) *cdcpb.ChangeDataRequest {
+	return &cdcpb.ChangeDataRequest{RegionId: regionID, RequestId: requestID}
+}
+
+func newRegionEvent(regionID, requestID uint64) *cdcpb.Event {
+	return &cdcpb.Event{
+		RegionId:  regionID,
+		RequestId: requestID,
+		Event:     &cdcpb.Event_Entries_{Entries: &cdcpb.Event_Entries{}},
+	}
+}
+
+func TestSharedStreamsMultiplex(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p, created := newSharedStreams4Test(ctx, t)
+
+	ctx1, cancel1 := context.WithCancel(ctx)
+	defer cancel1()
+	m1, err := p.open(ctx1, nil, "store-1", 1)
+	require.Nil(t, err)
+	ctx2, cancel2 := context.WithCancel(ctx)
+	m2, err := p.open(ctx2, nil, "store-1", 1)
+	require.Nil(t, err)
+	stream1 := <-created
+	require.Len(t, created, 0)
+
+	// requests of different sessions share a stream.
+	require.Nil(t, m1.Send(newRegionRequest(1, 11)))
+	require.Nil(t, m2.Send(newRegionRequest(2, 12)))
+	require.Equal(t, uint64(11), (<-stream1.requests).RequestId)
+	require.Equal(t, uint64(12), (<-stream1.requests).RequestId)
+
+	// the same region is requested over another stream.
+	require.Nil(t, m2.Send(newRegionRequest(1, 13)))
+	stream2 := <-created
+	require.Equal(t, uint64(13), (<-stream2.requests).RequestId)
+
+	// events are routed by regions and requests.
+	stream1.events <- &cdcpb.ChangeDataEvent{
+		Events: []*cdcpb.Event{
+			newRegionEvent(1, 11), newRegionEvent(2, 12), newRegionEvent(2, 10),
+		},
+		ResolvedTs: &cdcpb.ResolvedTs{Regions: []uint64{1, 2, 3}, Ts: 5},
+	}
+	event, err := m1.Recv()
+	require.Nil(t, err)
+	require.Equal(t, []*cdcpb.Event{newRegionEvent(1, 11)}, event.Events)
+	require.Equal(t, &cdcpb.ResolvedTs{Regions: []uint64{1}, Ts: 5}, event.ResolvedTs)
+	event, err = m2.Recv()
+	require.Nil(t, err)
+	require.Equal(t, []*cdcpb.Event{newRegionEvent(2, 12)}, event.Events)
+	require.Equal(t, &cdcpb.ResolvedTs{Regions: []uint64{2}, Ts: 5}, event.ResolvedTs)
+
+	// a failed region can be requested over the same stream again.
+	regionErr := &cdcpb.Event{
+		RegionId: 1, RequestId: 11,
+		Event: &cdcpb.Event_Error{Error: &cdcpb.Error{}},
+	}
+	stream1.events <- &cdcpb.ChangeDataEvent{Events: []*cdcpb.Event{regionErr}}
+	event, err = m1.Recv()
+	require.Nil(t, err)
+	require.Equal(t, []*cdcpb.Event{regionErr}, event.Events)
+	require.Nil(t, m1.Send(newRegionRequest(1, 14)))
+	require.Equal(t, uint64(14), (<-stream1.requests).RequestId)
+
+	// a stream is closed after all sessions using it are gone.
+	cancel2()
+	_, err = m2.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+	require.Eventually(t, func() bool {
+		return stream2.ctx.Err() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, stream1.ctx.Err())
+
+	// the sessions are closed if the shared stream fails.
+	stream1.errCh <- io.EOF
+	_, err = m1.Recv()
+	require.Equal(t, io.EOF, err)
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return len(p.stores) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSharedStreamsRetire(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p, created := newSharedStreams4Test(ctx, t)
+
+	m1, err := p.open(ctx, nil, "store-1", 1)
+	require.Nil(t, err)
+	ctx2, cancel2 := context.WithCancel(ctx)
+	m2, err := p.open(ctx2, nil, "store-1", 1)
+	require.Nil(t, err)
+	stream1 := <-created
+
+	require.Nil(t, m1.Send(newRegionRequest(1, 1)))
+	<-stream1.requests
+	for i := 0; i < minUnusedRegionsToRetire; i++ {
+		regionID := uint64(i + 2)
+		require.Nil(t, m2.Send(newRegionRequest(regionID, regionID)))
+		<-stream1.requests
+	}
+
+	// the regions of m2 are still pushed by TiKV after it is gone, so the
+	// stream takes no new region.
+	cancel2()
+	_, err = m2.Recv()
+	require.NotNil(t, err)
+	require.Nil(t, m1.Send(newRegionRequest(1000, 1000)))
+	stream2 := <-created
+	require.Equal(t, uint64(1000), (<-stream2.requests).RegionId)
+
+	// the retired stream is closed once m1 has no region on it.
+	stream1.events <- &cdcpb.ChangeDataEvent{Events: []*cdcpb.Event{{
+		RegionId: 1, RequestId: 1,
+		Event: &cdcpb.Event_Error{Error: &cdcpb.Error{}},
+	}}}
+	_, err = m1.Recv()
+	require.Nil(t, err)
+	require.Eventually(t, func() bool {
+		return stream1.ctx.Err() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, stream2.ctx.Err())
+}
+
+func TestSharedStreamsSlowSession(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p, created := newSharedStreams4Test(ctx, t)
+
+	slow, err := p.open(ctx, nil, "store-1", 1)
+	require.Nil(t, err)
+	fast, err := p.open(ctx, nil, "store-1", 1)
+	require.Nil(t, err)
+	stream := <-created
+	require.Nil(t, slow.Send(newRegionRequest(1, 1)))
+	require.Nil(t, fast.Send(newRegionRequest(2, 2)))
+	<-stream.requests
+	<-stream.requests
+
+	// the slow session never receives, it is closed once its buffer is full,
+	// and the fast session keeps receiving.
+	for i := 0; i < muxStreamChanSize+1; i++ {
+		stream.events <- &cdcpb.ChangeDataEvent{
+			Events: []*cdcpb.Event{newRegionEvent(1, 1), newRegionEvent(2, 2)},
+		}
+		event, err := fast.Recv()
+		require.Nil(t, err)
+		require.Equal(t, []*cdcpb.Event{newRegionEvent(2, 2)}, event.Events)
+	}
+	<-slow.done
+	require.Equal(t, codes.ResourceExhausted, status.Code(slow.err))
+
+	// the region of the slow session can be requested again.
+	slow, err = p.open(ctx, nil, "store-1", 1)
+	require.Nil(t, err)
+	require.Nil(t, slow.Send(newRegionRequest(1, 3)))
+	require.Equal(t, uint64(3), (<-(<-created).requests).RequestId)
+}
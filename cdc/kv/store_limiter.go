@@ -0,0 +1,179 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// minStoreRegionScanLimit is the lower bound of the adaptive limit.
+	minStoreRegionScanLimit = 8
+	// the adaptive limit is decreased at most once in the interval, so that
+	// a burst of errors caused by one incident only counts once.
+	storeLimitDecreaseInterval = time.Second
+	// a response slower than both the threshold and twice the average
+	// response latency of the store is a sign of congestion.
+	storeCongestedLatencyThreshold = time.Second
+	// decrease factors of the adaptive limit.
+	storeErrorDecreaseFactor   = 0.5
+	storeLatencyDecreaseFactor = 0.8
+	// the weight of a new sample in the average response latency.
+	storeLatencyEWMAWeight = 0.2
+)
+
+// storeLimiter limits the number of the region requests that are sent to a
+// store but not initialized yet, i.e. the incremental scans in flight, of
+// all the event feed sessions of a capture.
+//
+// The limit adapts to the store in an AIMD way. It grows by one for every
+// region initialized in time, and shrinks multiplicatively when the store
+// fails the requests or responds much slower than usual.
+type storeLimiter struct {
+	mu   sync.Mutex
+	addr string
+
+	maxLimit float64
+	limit    float64
+	inflight int
+
+	avgLatency   time.Duration
+	lastDecrease time.Time
+
+	limitGauge    prometheus.Gauge
+	inflightGauge prometheus.Gauge
+}
+
+func newStoreLimiter(addr string, maxLimit int) *storeLimiter {
+	initial := maxLimit / 4
+	if initial < minStoreRegionScanLimit {
+		initial = minStoreRegionScanLimit
+	}
+	if initial > maxLimit {
+		initial = maxLimit
+	}
+	l := &storeLimiter{
+		addr:          addr,
+		maxLimit:      float64(maxLimit),
+		limit:         float64(initial),
+		limitGauge:    storeRegionScanLimitGauge.WithLabelValues(addr),
+		inflightGauge: storeRegionScanInflightGauge.WithLabelValues(addr),
+	}
+	l.limitGauge.Set(l.limit)
+	return l
+}
+
+// tryAcquire takes a slot of the store, it returns false if the store
+// reaches the limit.
+func (l *storeLimiter) tryAcquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inflight >= int(l.limit) {
+		return false
+	}
+	l.inflight++
+	l.inflightGauge.Inc()
+	return true
+}
+
+// release gives back a slot of the store.
+func (l *storeLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inflight > 0 {
+		l.inflight--
+		l.inflightGauge.Dec()
+	}
+}
+
+// onSuccess is called when a region is initialized, latency is the time
+// between sending the request and receiving the first response.
+func (l *storeLimiter) onSuccess(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	congested := l.avgLatency > 0 &&
+		latency > storeCongestedLatencyThreshold && latency > 2*l.avgLatency
+	if l.avgLatency == 0 {
+		l.avgLatency = latency
+	} else {
+		l.avgLatency += time.Duration(
+			storeLatencyEWMAWeight * float64(latency-l.avgLatency))
+	}
+	if congested {
+		l.decrease(storeLatencyDecreaseFactor)
+		return
+	}
+	if l.limit < l.maxLimit {
+		l.limit++
+		if l.limit > l.maxLimit {
+			l.limit = l.maxLimit
+		}
+		l.limitGauge.Set(l.limit)
+	}
+}
+
+// onError is called when the store fails a request or a stream to it.
+func (l *storeLimiter) onError() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decrease(storeErrorDecreaseFactor)
+}
+
+func (l *storeLimiter) decrease(factor float64) {
+	now := time.Now()
+	if now.Sub(l.lastDecrease) < storeLimitDecreaseInterval {
+		return
+	}
+	l.lastDecrease = now
+	l.limit *= factor
+	if l.limit < minStoreRegionScanLimit {
+		l.limit = minStoreRegionScanLimit
+	}
+	l.limitGauge.Set(l.limit)
+	log.Info("decrease region scan limit of store",
+		zap.String("addr", l.addr), zap.Float64("limit", l.limit), zap.Int("inflight", l.inflight),
+		zap.Duration("avgLatency", l.avgLatency))
+}
+
+// storeLimiters holds the limiters of all stores, it is shared by all the
+// kv clients of a capture.
+type storeLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*storeLimiter
+	maxLimit int
+}
+
+func newStoreLimiters(maxLimit int) *storeLimiters {
+	return &storeLimiters{
+		limiters: make(map[string]*storeLimiter),
+		maxLimit: maxLimit,
+	}
+}
+
+// get returns the limiter of the store.
+func (ls *storeLimiters) get(addr string) *storeLimiter {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	l, ok := ls.limiters[addr]
+	if !ok {
+		l = newStoreLimiter(addr, ls.maxLimit)
+		ls.limiters[addr] = l
+	}
+	return l
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreLimiterAcquire(t *testing.T) {
+	t.Parallel()
+
+	l := newStoreLimiter("store-1", 64)
+	require.Equal(t, float64(16), l.limit)
+	for i := 0; i < 16; i++ {
+		require.True(t, l.tryAcquire())
+	}
+	require.False(t, l.tryAcquire())
+	l.release()
+	require.True(t, l.tryAcquire())
+
+	// the limit never goes below the minimum or above the maximum.
+	l = newStoreLimiter("store-1", 4)
+	require.Equal(t, float64(4), l.limit)
+	l = newStoreLimiter("store-1", 16)
+	require.Equal(t, float64(minStoreRegionScanLimit), l.limit)
+}
+
+func TestStoreLimiterAdapt(t *testing.T) {
+	t.Parallel()
+
+	l := newStoreLimiter("store-1", 64)
+	l.onSuccess(10 * time.Millisecond)
+	require.Equal(t, float64(17), l.limit)
+	for i := 0; i < 100; i++ {
+		l.onSuccess(10 * time.Millisecond)
+	}
+	require.Equal(t, float64(64), l.limit)
+
+	// errors halve the limit, at most once in an interval.
+	l.onError()
+	require.Equal(t, float64(32), l.limit)
+	l.onError()
+	require.Equal(t, float64(32), l.limit)
+
+	// a response much slower than usual is a sign of congestion.
+	l.lastDecrease = time.Time{}
+	l.onSuccess(10 * time.Second)
+	require.Equal(t, 32*storeLatencyDecreaseFactor, l.limit)
+
+	// the limit never goes below the minimum.
+	for i := 0; i < 10; i++ {
+		l.lastDecrease = time.Time{}
+		l.onError()
+	}
+	require.Equal(t, float64(minStoreRegionScanLimit), l.limit)
+}
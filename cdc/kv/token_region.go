@@ -41,6 +41,9 @@ type LimitRegionRouter interface {
 	Acquire(id string)
 	// Release gives back one token, this function is thread-safe
 	Release(id string)
+	// Revoke gives back the store slot of a region that is consumed from
+	// Chan but not sent to the store, this function is thread-safe
+	Revoke(id string)
 	// Run runs in background and does some logic work
 	Run(ctx context.Context) error
 }
@@ -71,16 +74,29 @@ type sizedRegionRouter struct {
 	metrics   *srrMetrics
 	tokens    map[string]int
 	sizeLimit int
+
+	// limiters limits the regions in flight of all routers in each store,
+	// a region takes a slot of its store before it is put to the output
+	// channel, and gives it back when it is initialized or failed.
+	limiters *storeLimiters
+	// slots is the number of store slots taken by the router.
+	slots  map[string]int
+	closed bool
 }
 
-// NewSizedRegionRouter creates a new sizedRegionRouter
-func NewSizedRegionRouter(ctx context.Context, sizeLimit int) *sizedRegionRouter {
+// NewSizedRegionRouter creates a new sizedRegionRouter, limiters can be nil
+// if there is no limit across routers.
+func NewSizedRegionRouter(
+	ctx context.Context, sizeLimit int, limiters *storeLimiters,
+) *sizedRegionRouter {
 	return &sizedRegionRouter{
 		buffer:    make(map[string][]singleRegionInfo),
 		output:    make(chan singleRegionInfo, regionRouterChanSize),
 		sizeLimit: sizeLimit,
 		tokens:    make(map[string]int),
 		metrics:   newSrrMetrics(ctx),
+		limiters:  limiters,
+		slots:     make(map[string]int),
 	}
 }
 
@@ -95,7 +111,7 @@ func (r *sizedRegionRouter) AddRegion(sri singleRegionInfo) {
 	if sri.rpcCtx != nil {
 		id = sri.rpcCtx.Addr
 	}
-	if r.sizeLimit > r.tokens[id] && len(r.output) < regionRouterChanSize {
+	if r.sizeLimit > r.tokens[id] && len(r.output) < regionRouterChanSize && r.takeSlot(id) {
 		r.output <- sri
 	} else {
 		r.buffer[id] = append(r.buffer[id], sri)
@@ -129,6 +145,36 @@ func (r *sizedRegionRouter) Release(id string) {
 		r.metrics.tokens[id] = clientRegionTokenSize.WithLabelValues(id, r.metrics.changefeed)
 	}
 	r.metrics.tokens[id].Dec()
+	r.releaseSlot(id)
+}
+
+// Revoke implements LimitRegionRouter.Revoke
+// param: id is TiKV store address
+func (r *sizedRegionRouter) Revoke(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.releaseSlot(id)
+}
+
+// takeSlot takes a slot of the store, the lock must be held.
+func (r *sizedRegionRouter) takeSlot(id string) bool {
+	if r.limiters == nil || id == "" {
+		return true
+	}
+	if r.closed || !r.limiters.get(id).tryAcquire() {
+		return false
+	}
+	r.slots[id]++
+	return true
+}
+
+// releaseSlot gives back a slot of the store, the lock must be held.
+func (r *sizedRegionRouter) releaseSlot(id string) {
+	if r.slots[id] <= 0 {
+		return
+	}
+	r.slots[id]--
+	r.limiters.get(id).release()
 }
 
 func (r *sizedRegionRouter) Run(ctx context.Context) error {
@@ -140,6 +186,15 @@ func (r *sizedRegionRouter) Run(ctx context.Context) error {
 		for id, buf := range r.buffer {
 			r.metrics.cachedRegions[id].Sub(float64(len(buf)))
 		}
+		// Give back the slots of the regions that are still in flight, the
+		// router is not used after it exits.
+		r.closed = true
+		for id, n := range r.slots {
+			for i := 0; i < n; i++ {
+				r.limiters.get(id).release()
+			}
+			delete(r.slots, id)
+		}
 	}()
 	for {
 		select {
@@ -165,16 +220,20 @@ func (r *sizedRegionRouter) Run(ctx context.Context) error {
 				if available == 0 {
 					continue
 				}
-				for i := 0; i < available; i++ {
+				sent := 0
+				for ; sent < available; sent++ {
+					if !r.takeSlot(id) {
+						break
+					}
 					select {
 					case <-ctx.Done():
 						r.lock.Unlock()
 						return errors.Trace(ctx.Err())
-					case r.output <- buf[i]:
+					case r.output <- buf[sent]:
 					}
 				}
-				r.buffer[id] = r.buffer[id][available:]
-				r.metrics.cachedRegions[id].Sub(float64(available))
+				r.buffer[id] = r.buffer[id][sent:]
+				r.metrics.cachedRegions[id].Sub(float64(sent))
 			}
 			r.lock.Unlock()
 		}
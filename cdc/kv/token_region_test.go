@@ -30,7 +30,7 @@ func TestRouter(t *testing.T) {
 	t.Parallel()
 	store := "store-1"
 	limit := 10
-	r := NewSizedRegionRouter(context.Background(), limit)
+	r := NewSizedRegionRouter(context.Background(), limit, nil)
 	for i := 0; i < limit; i++ {
 		r.AddRegion(singleRegionInfo{ts: uint64(i), rpcCtx: &tikv.RPCContext{Addr: store}})
 	}
@@ -69,7 +69,7 @@ func testRouterWithConsumer(t *testing.T, funcDoSth func()) {
 
 	store := "store-1"
 	limit := 20
-	r := NewSizedRegionRouter(context.Background(), limit)
+	r := NewSizedRegionRouter(context.Background(), limit, nil)
 	for i := 0; i < limit*2; i++ {
 		r.AddRegion(singleRegionInfo{ts: uint64(i), rpcCtx: &tikv.RPCContext{Addr: store}})
 	}
@@ -129,7 +129,7 @@ func TestRouterWithMultiStores(t *testing.T) {
 		stores = append(stores, fmt.Sprintf("store-%d", i))
 	}
 	limit := 20
-	r := NewSizedRegionRouter(context.Background(), limit)
+	r := NewSizedRegionRouter(context.Background(), limit, nil)
 
 	for _, store := range stores {
 		for j := 0; j < limit*2; j++ {
@@ -173,3 +173,38 @@ func TestRouterWithMultiStores(t *testing.T) {
 		require.Equal(t, 0, r.tokens[store])
 	}
 }
+
+func TestRouterWithStoreLimiters(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	store := "store-1"
+	// both routers allow 10 regions, but the store only allows 8.
+	limiters := newStoreLimiters(minStoreRegionScanLimit)
+	r1 := NewSizedRegionRouter(context.Background(), 10, limiters)
+	r2 := NewSizedRegionRouter(context.Background(), 10, limiters)
+	for i := 0; i < 6; i++ {
+		r1.AddRegion(singleRegionInfo{ts: uint64(i), rpcCtx: &tikv.RPCContext{Addr: store}})
+	}
+	for i := 0; i < 6; i++ {
+		r2.AddRegion(singleRegionInfo{ts: uint64(i), rpcCtx: &tikv.RPCContext{Addr: store}})
+	}
+	require.Len(t, r1.Chan(), 6)
+	require.Len(t, r2.Chan(), 2)
+	require.Len(t, r2.buffer[store], 4)
+
+	// a region consumed but not sent gives back the slot.
+	<-r1.Chan()
+	r1.Revoke(store)
+	require.Equal(t, 5, r1.slots[store])
+
+	// the slots held by a router are given back when it exits.
+	errg, ctx1 := errgroup.WithContext(ctx)
+	errg.Go(func() error { return r1.Run(ctx1) })
+	errg.Go(func() error { return r2.Run(ctx1) })
+	require.Eventually(t, func() bool {
+		return len(r2.Chan()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.Equal(t, context.Canceled, errors.Cause(errg.Wait()))
+	require.Equal(t, 0, limiters.get(store).inflight)
+}
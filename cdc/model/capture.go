@@ -16,6 +16,7 @@ package model
 import (
 	"encoding/json"
 
+	"github.com/google/uuid"
 	"github.com/pingcap/errors"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -25,6 +26,32 @@ type CaptureInfo struct {
 	ID            CaptureID `json:"id"`
 	AdvertiseAddr string    `json:"address"`
 	Version       string    `json:"version"`
+	// Labels are used to place changefeeds and tables on specific captures.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Draining is set by the owner when the tables are being moved off the
+	// capture, no table is placed on a draining capture, and it does not
+	// campaign for the owner.
+	Draining bool `json:"draining,omitempty"`
+}
+
+// ValidateCaptureID checks that the capture ID is a UUID in the canonical
+// form, which is how the captures are identified.
+func ValidateCaptureID(captureID CaptureID) error {
+	id, err := uuid.Parse(captureID)
+	if err != nil || id.String() != captureID {
+		return cerror.ErrInvalidCaptureID.GenWithStackByArgs(captureID)
+	}
+	return nil
+}
+
+// MatchLabels returns true if the capture has all the given labels.
+func (c *CaptureInfo) MatchLabels(labels map[string]string) bool {
+	for key, value := range labels {
+		if v, ok := c.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
 }
 
 // Marshal using json.Marshal.
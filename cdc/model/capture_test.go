@@ -37,6 +37,44 @@ func TestMarshalUnmarshal(t *testing.T) {
 	require.Equal(t, info, decodedInfo)
 }
 
+func TestMatchLabels(t *testing.T) {
+	t.Parallel()
+
+	info := &CaptureInfo{
+		ID:     "9ff52aca-aea6-4022-8ec4-fbee3f2c7890",
+		Labels: map[string]string{"zone": "us-east-1a", "rack": "r1"},
+	}
+	require.True(t, info.MatchLabels(nil))
+	require.True(t, info.MatchLabels(map[string]string{"zone": "us-east-1a"}))
+	require.True(t, info.MatchLabels(map[string]string{"zone": "us-east-1a", "rack": "r1"}))
+	require.False(t, info.MatchLabels(map[string]string{"zone": "us-east-1b"}))
+	require.False(t, info.MatchLabels(map[string]string{"zone": "us-east-1a", "host": "h1"}))
+
+	info.Labels = nil
+	require.True(t, info.MatchLabels(nil))
+	require.False(t, info.MatchLabels(map[string]string{"zone": "us-east-1a"}))
+
+	data, err := (&CaptureInfo{ID: "capture", Labels: map[string]string{"zone": "z1"}}).Marshal()
+	require.Nil(t, err)
+	require.Equal(t, `{"id":"capture","address":"","version":"","labels":{"zone":"z1"}}`, string(data))
+}
+
+func TestValidateCaptureID(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, ValidateCaptureID("9ff52aca-aea6-4022-8ec4-fbee3f2c7890"))
+	for _, id := range []string{
+		"", "capture", "9FF52ACA-AEA6-4022-8EC4-FBEE3F2C7890",
+		"{9ff52aca-aea6-4022-8ec4-fbee3f2c7890}", "9ff52aca-aea6-4022-8ec4-fbee3f2c7890/t",
+	} {
+		require.Regexp(t, ".*ErrInvalidCaptureID.*", ValidateCaptureID(id))
+	}
+
+	data, err := (&CaptureInfo{ID: "capture", Draining: true}).Marshal()
+	require.Nil(t, err)
+	require.Equal(t, `{"id":"capture","address":"","version":"","draining":true}`, string(data))
+}
+
 func TestListVersionsFromCaptureInfos(t *testing.T) {
 	infos := []*CaptureInfo{
 		{
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/cyclic/mark"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
@@ -39,6 +40,7 @@ const (
 	SortInMemory SortEngine = "memory"
 	SortInFile   SortEngine = "file"
 	SortUnified  SortEngine = "unified"
+	SortInDB     SortEngine = "db"
 )
 
 // FeedState represents the running state of a changefeed
@@ -116,6 +118,11 @@ type ChangeFeedInfo struct {
 	SyncPointEnabled  bool          `json:"sync-point-enabled"`
 	SyncPointInterval time.Duration `json:"sync-point-interval"`
 	CreatorVersion    string        `json:"creator-version"`
+	// Priority decides how resources shared with other changefeeds, such as
+	// workers, memory and captures, are allocated to the changefeed.
+	Priority priority.Priority `json:"priority,omitempty"`
+	// Labels are used to select changefeeds in batch operations.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 const changeFeedIDMaxLen = 128
@@ -189,6 +196,15 @@ func (info *ChangeFeedInfo) GetTargetTs() uint64 {
 	return uint64(math.MaxUint64)
 }
 
+// GetPriority returns the priority of the changefeed, it returns
+// priority.Normal if the info is nil.
+func (info *ChangeFeedInfo) GetPriority() priority.Priority {
+	if info == nil {
+		return priority.Normal
+	}
+	return info.Priority
+}
+
 // Marshal returns the json marshal format of a ChangeFeedInfo
 func (info *ChangeFeedInfo) Marshal() (string, error) {
 	data, err := json.Marshal(info)
@@ -0,0 +1,141 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/version"
+)
+
+// ChangefeedBundleVersion is the version of the changefeed bundle format,
+// it is increased when the format is changed incompatibly.
+const ChangefeedBundleVersion = 1
+
+// ChangefeedBundle is a portable bundle of changefeeds, it is exported from
+// a TiCDC cluster and imported into another one to migrate the changefeeds.
+type ChangefeedBundle struct {
+	Version     int                   `json:"version"`
+	ExportTime  time.Time             `json:"export_time"`
+	Changefeeds []*ExportedChangefeed `json:"changefeeds"`
+}
+
+// ExportedChangefeed is a changefeed in a changefeed bundle.
+type ExportedChangefeed struct {
+	ID string `json:"id"`
+	// Info carries the replica config and the syncpoint config of the changefeed.
+	Info *ChangeFeedInfo `json:"info"`
+	// CheckpointTs is the checkpoint ts of the changefeed when it is exported,
+	// the imported changefeed starts from it.
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+}
+
+// NewChangefeedBundle creates an empty changefeed bundle of the current version.
+func NewChangefeedBundle() *ChangefeedBundle {
+	return &ChangefeedBundle{
+		Version:     ChangefeedBundleVersion,
+		ExportTime:  time.Now(),
+		Changefeeds: make([]*ExportedChangefeed, 0),
+	}
+}
+
+// Add adds a changefeed to the bundle, the checkpoint ts is the start ts
+// of the changefeed if it has no status yet.
+func (b *ChangefeedBundle) Add(id ChangeFeedID, info *ChangeFeedInfo, status *ChangeFeedStatus) {
+	b.Changefeeds = append(b.Changefeeds, &ExportedChangefeed{
+		ID:           id,
+		Info:         info,
+		CheckpointTs: info.GetCheckpointTs(status),
+	})
+}
+
+// Marshal returns the json marshal format of a ChangefeedBundle
+func (b *ChangefeedBundle) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	return data, cerror.WrapError(cerror.ErrMarshalFailed, err)
+}
+
+// Unmarshal unmarshals into *ChangefeedBundle from json marshal byte slice,
+// and checks that the bundle can be imported.
+func (b *ChangefeedBundle) Unmarshal(data []byte) error {
+	if err := json.Unmarshal(data, b); err != nil {
+		return errors.Annotatef(
+			cerror.WrapError(cerror.ErrUnmarshalFailed, err), "Unmarshal data: %v", data)
+	}
+	if b.Version != ChangefeedBundleVersion {
+		return cerror.ErrInvalidChangefeedBundle.GenWithStackByArgs(
+			fmt.Sprintf("unsupported version %d", b.Version))
+	}
+	ids := make(map[ChangeFeedID]struct{}, len(b.Changefeeds))
+	for _, cf := range b.Changefeeds {
+		if err := ValidateChangefeedID(cf.ID); err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := ids[cf.ID]; ok {
+			return cerror.ErrInvalidChangefeedBundle.GenWithStackByArgs("duplicated changefeed " + cf.ID)
+		}
+		ids[cf.ID] = struct{}{}
+		if cf.Info == nil || cf.Info.Config == nil {
+			return cerror.ErrInvalidChangefeedBundle.GenWithStackByArgs("no info of changefeed " + cf.ID)
+		}
+		if cf.CheckpointTs == 0 {
+			return cerror.ErrInvalidChangefeedBundle.GenWithStackByArgs("no checkpoint ts of changefeed " + cf.ID)
+		}
+	}
+	return nil
+}
+
+// ImportInfo returns the info of the changefeed to be created when importing.
+// The changefeed starts from the exported checkpoint ts and is created in the
+// stopped state, so that it is resumed only after the old one is stopped.
+func (c *ExportedChangefeed) ImportInfo() (*ChangeFeedInfo, error) {
+	info, err := c.Info.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info.StartTs = c.CheckpointTs
+	info.CreateTime = time.Now()
+	info.AdminJobType = AdminStop
+	info.State = StateStopped
+	info.Error = nil
+	info.SortDir = ""
+	info.CreatorVersion = version.ReleaseVersion
+	return info, nil
+}
+
+// ImportConfigV2 returns the config to create the changefeed by the open API
+// when importing, the changefeed is created as ImportInfo describes.
+func (c *ExportedChangefeed) ImportConfigV2() *ChangefeedConfigV2 {
+	info := c.Info
+	return &ChangefeedConfigV2{
+		ID:                c.ID,
+		StartTs:           c.CheckpointTs,
+		TargetTs:          info.TargetTs,
+		SinkURI:           info.SinkURI,
+		Engine:            info.Engine,
+		Opts:              info.Opts,
+		ReplicaConfig:     info.Config,
+		SyncPointEnabled:  &info.SyncPointEnabled,
+		SyncPointInterval: info.SyncPointInterval,
+		Priority:          &info.Priority,
+		Labels:            info.Labels,
+		// The ineligible tables have been confirmed when the changefeed was created.
+		IgnoreIneligibleTable: true,
+		Paused:                true,
+	}
+}
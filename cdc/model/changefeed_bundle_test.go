@@ -0,0 +1,119 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/priority"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedBundleMarshal(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.CaseSensitive = false
+	bundle := NewChangefeedBundle()
+	bundle.Add("cf1", &ChangeFeedInfo{
+		SinkURI:           "kafka://127.0.0.1:9092/topic",
+		StartTs:           100,
+		Config:            replicaConfig,
+		SyncPointEnabled:  true,
+		SyncPointInterval: time.Minute,
+	}, &ChangeFeedStatus{CheckpointTs: 200})
+	// The checkpoint ts is the start ts if the changefeed has no status.
+	bundle.Add("cf2", &ChangeFeedInfo{StartTs: 300, Config: replicaConfig}, nil)
+	require.Equal(t, uint64(200), bundle.Changefeeds[0].CheckpointTs)
+	require.Equal(t, uint64(300), bundle.Changefeeds[1].CheckpointTs)
+
+	data, err := bundle.Marshal()
+	require.Nil(t, err)
+	decoded := new(ChangefeedBundle)
+	require.Nil(t, decoded.Unmarshal(data))
+	require.Equal(t, ChangefeedBundleVersion, decoded.Version)
+	require.Len(t, decoded.Changefeeds, 2)
+	require.Equal(t, "cf1", decoded.Changefeeds[0].ID)
+	require.Equal(t, uint64(200), decoded.Changefeeds[0].CheckpointTs)
+	require.False(t, decoded.Changefeeds[0].Info.Config.CaseSensitive)
+	require.True(t, decoded.Changefeeds[0].Info.SyncPointEnabled)
+	require.Equal(t, time.Minute, decoded.Changefeeds[0].Info.SyncPointInterval)
+
+	testCases := []struct {
+		data string
+		msg  string
+	}{
+		{`{"version":2,"changefeeds":[]}`, "unsupported version 2"},
+		{`{"version":1,"changefeeds":[{"id":"cf1","info":{"config":{}},"checkpoint_ts":1},` +
+			`{"id":"cf1","info":{"config":{}},"checkpoint_ts":1}]}`, "duplicated changefeed cf1"},
+		{`{"version":1,"changefeeds":[{"id":"cf1","checkpoint_ts":1}]}`, "no info of changefeed cf1"},
+		{`{"version":1,"changefeeds":[{"id":"cf1","info":{"config":{}}}]}`, "no checkpoint ts of changefeed cf1"},
+	}
+	for _, tc := range testCases {
+		err := new(ChangefeedBundle).Unmarshal([]byte(tc.data))
+		require.True(t, cerror.ErrInvalidChangefeedBundle.Equal(err), tc.data)
+		require.Contains(t, err.Error(), tc.msg)
+	}
+	err = new(ChangefeedBundle).Unmarshal([]byte(`{"version":1,"changefeeds":[{"id":"a_b"}]}`))
+	require.True(t, cerror.ErrInvalidChangefeedID.Equal(err))
+}
+
+func TestExportedChangefeedImport(t *testing.T) {
+	t.Parallel()
+
+	cf := &ExportedChangefeed{
+		ID: "cf1",
+		Info: &ChangeFeedInfo{
+			SinkURI:           "kafka://127.0.0.1:9092/topic",
+			StartTs:           100,
+			TargetTs:          1000,
+			Config:            config.GetDefaultReplicaConfig(),
+			State:             StateError,
+			AdminJobType:      AdminStop,
+			Error:             &RunningError{Code: "CDC:ErrSinkURIInvalid"},
+			SyncPointEnabled:  true,
+			SyncPointInterval: time.Minute,
+			Priority:          priority.High,
+			Labels:            map[string]string{"env": "prod"},
+		},
+		CheckpointTs: 200,
+	}
+
+	info, err := cf.ImportInfo()
+	require.Nil(t, err)
+	require.Equal(t, uint64(200), info.StartTs)
+	require.Equal(t, uint64(1000), info.TargetTs)
+	require.Equal(t, StateStopped, info.State)
+	require.Equal(t, AdminStop, info.AdminJobType)
+	require.Nil(t, info.Error)
+	require.True(t, info.SyncPointEnabled)
+	require.Equal(t, priority.High, info.Priority)
+	require.Equal(t, map[string]string{"env": "prod"}, info.Labels)
+	// The exported info is not changed.
+	require.Equal(t, uint64(100), cf.Info.StartTs)
+	require.NotNil(t, cf.Info.Error)
+
+	cfg := cf.ImportConfigV2()
+	require.Equal(t, "cf1", cfg.ID)
+	require.Equal(t, uint64(200), cfg.StartTs)
+	require.Equal(t, uint64(1000), cfg.TargetTs)
+	require.True(t, cfg.Paused)
+	require.True(t, cfg.IgnoreIneligibleTable)
+	require.True(t, *cfg.SyncPointEnabled)
+	require.Equal(t, time.Minute, cfg.SyncPointInterval)
+	require.Equal(t, priority.High, *cfg.Priority)
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/priority"
+)
+
+// ChangefeedTemplate is a named changefeed config stored in etcd.
+// A changefeed created from a template copies the config of the template,
+// later changes of the template do not affect the existing changefeeds.
+type ChangefeedTemplate struct {
+	Name    string `json:"name"`
+	SinkURI string `json:"sink_uri"`
+	// Engine is the sort engine, the unified sorter is used if it is empty.
+	Engine            SortEngine            `json:"sort_engine"`
+	Opts              map[string]string     `json:"opts"`
+	ReplicaConfig     *config.ReplicaConfig `json:"replica_config"`
+	SyncPointEnabled  *bool                 `json:"sync_point_enabled"`
+	SyncPointInterval time.Duration         `json:"sync_point_interval"`
+	Priority          *priority.Priority    `json:"priority"`
+	Labels            map[string]string     `json:"labels"`
+}
+
+// Marshal returns the json marshal format of a ChangefeedTemplate
+func (t *ChangefeedTemplate) Marshal() (string, error) {
+	data, err := json.Marshal(t)
+	return string(data), cerror.WrapError(cerror.ErrMarshalFailed, err)
+}
+
+// Unmarshal unmarshals into *ChangefeedTemplate from json marshal byte slice
+func (t *ChangefeedTemplate) Unmarshal(data []byte) error {
+	err := json.Unmarshal(data, t)
+	return errors.Annotatef(
+		cerror.WrapError(cerror.ErrUnmarshalFailed, err), "Unmarshal data: %v", data)
+}
+
+// ApplyTo fills the zero fields of the changefeed config with the template.
+// The opts and the labels are merged, the ones of the config take precedence.
+func (t *ChangefeedTemplate) ApplyTo(cfg *ChangefeedConfigV2) {
+	if cfg.SinkURI == "" {
+		cfg.SinkURI = t.SinkURI
+	}
+	if cfg.Engine == "" {
+		cfg.Engine = t.Engine
+	}
+	if cfg.ReplicaConfig == nil && t.ReplicaConfig != nil {
+		cfg.ReplicaConfig = t.ReplicaConfig.Clone()
+	}
+	if cfg.SyncPointEnabled == nil && t.SyncPointEnabled != nil {
+		enabled := *t.SyncPointEnabled
+		cfg.SyncPointEnabled = &enabled
+	}
+	if cfg.SyncPointInterval == 0 {
+		cfg.SyncPointInterval = t.SyncPointInterval
+	}
+	if cfg.Priority == nil && t.Priority != nil {
+		pri := *t.Priority
+		cfg.Priority = &pri
+	}
+	cfg.Opts = mergeStringMap(t.Opts, cfg.Opts)
+	cfg.Labels = mergeStringMap(t.Labels, cfg.Labels)
+}
+
+// mergeStringMap returns a new map holding the entries of both maps,
+// the ones of override take precedence. nil is returned if both are empty.
+func mergeStringMap(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return override
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
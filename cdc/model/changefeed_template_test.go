@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/priority"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedTemplateApplyTo(t *testing.T) {
+	t.Parallel()
+
+	enabled := true
+	high := priority.High
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.CaseSensitive = false
+	tpl := &ChangefeedTemplate{
+		Name:              "kafka",
+		SinkURI:           "kafka://127.0.0.1:9092/topic",
+		Engine:            SortInMemory,
+		Opts:              map[string]string{"a": "1", "b": "2"},
+		ReplicaConfig:     replicaConfig,
+		SyncPointEnabled:  &enabled,
+		SyncPointInterval: time.Minute,
+		Priority:          &high,
+		Labels:            map[string]string{"env": "prod", "team": "dba"},
+	}
+
+	// an empty config inherits everything
+	cfg := &ChangefeedConfigV2{}
+	tpl.ApplyTo(cfg)
+	require.Equal(t, tpl.SinkURI, cfg.SinkURI)
+	require.Equal(t, SortInMemory, cfg.Engine)
+	require.Equal(t, tpl.Opts, cfg.Opts)
+	require.Equal(t, replicaConfig, cfg.ReplicaConfig)
+	require.True(t, *cfg.SyncPointEnabled)
+	require.Equal(t, time.Minute, cfg.SyncPointInterval)
+	require.Equal(t, priority.High, *cfg.Priority)
+	require.Equal(t, tpl.Labels, cfg.Labels)
+	// the template is not shared with the config
+	cfg.ReplicaConfig.CaseSensitive = true
+	*cfg.Priority = priority.Low
+	cfg.Labels["env"] = "test"
+	require.False(t, tpl.ReplicaConfig.CaseSensitive)
+	require.Equal(t, priority.High, *tpl.Priority)
+	require.Equal(t, "prod", tpl.Labels["env"])
+
+	// the non-zero fields override the template
+	disabled := false
+	cfg = &ChangefeedConfigV2{
+		SinkURI:          "blackhole://",
+		Opts:             map[string]string{"b": "3"},
+		SyncPointEnabled: &disabled,
+		Labels:           map[string]string{"env": "test"},
+	}
+	tpl.ApplyTo(cfg)
+	require.Equal(t, "blackhole://", cfg.SinkURI)
+	require.Equal(t, map[string]string{"a": "1", "b": "3"}, cfg.Opts)
+	require.False(t, *cfg.SyncPointEnabled)
+	require.Equal(t, map[string]string{"env": "test", "team": "dba"}, cfg.Labels)
+
+	// empty maps stay empty
+	cfg = &ChangefeedConfigV2{}
+	(&ChangefeedTemplate{}).ApplyTo(cfg)
+	require.Nil(t, cfg.Opts)
+	require.Nil(t, cfg.Labels)
+	require.Nil(t, cfg.ReplicaConfig)
+}
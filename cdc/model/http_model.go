@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/priority"
 )
 
 const timeFormat = `"2006-01-02 15:04:05.000"`
@@ -70,11 +71,12 @@ type ServerStatus struct {
 
 // ChangefeedCommonInfo holds some common usage information of a changefeed
 type ChangefeedCommonInfo struct {
-	ID             string        `json:"id"`
-	FeedState      FeedState     `json:"state"`
-	CheckpointTSO  uint64        `json:"checkpoint_tso"`
-	CheckpointTime JSONTime      `json:"checkpoint_time"`
-	RunningError   *RunningError `json:"error"`
+	ID             string            `json:"id"`
+	FeedState      FeedState         `json:"state"`
+	CheckpointTSO  uint64            `json:"checkpoint_tso"`
+	CheckpointTime JSONTime          `json:"checkpoint_time"`
+	RunningError   *RunningError     `json:"error"`
+	Labels         map[string]string `json:"labels,omitempty"`
 }
 
 // MarshalJSON use to marshal ChangefeedCommonInfo
@@ -160,6 +162,44 @@ type ProcessorDetail struct {
 	Error *RunningError `json:"error"`
 }
 
+// TableStatus holds the replication status of a table
+type TableStatus struct {
+	TableID   int64  `json:"table_id"`
+	TableName string `json:"table_name"`
+	// CaptureID is the capture that replicates the table.
+	CaptureID string `json:"capture_id"`
+	// State is the state of the table pipeline.
+	State string `json:"state"`
+	// PullerResolvedTs is the resolved ts that has been pulled from TiKV.
+	PullerResolvedTs uint64 `json:"puller_resolved_ts"`
+	// SorterResolvedTs is the resolved ts that has been sorted.
+	SorterResolvedTs uint64 `json:"sorter_resolved_ts"`
+	// SinkResolvedTs is the resolved ts that has been sent to the sink.
+	SinkResolvedTs uint64 `json:"sink_resolved_ts"`
+	// CheckpointTs is the ts that has been flushed to the downstream.
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	// Lag is the gap between the current ts and the checkpoint ts in milliseconds.
+	Lag int64 `json:"lag"`
+	// ReceivedEvents is the number of events that have been pulled.
+	ReceivedEvents uint64 `json:"received_events"`
+	// EmittedEvents is the number of events that have been sent to the sink.
+	EmittedEvents uint64 `json:"emitted_events"`
+}
+
+// TableStatusList holds the replication status of the tables of a changefeed
+type TableStatusList struct {
+	Tables []*TableStatus `json:"tables"`
+	// CaptureErrors holds the captures whose tables can not be queried,
+	// the tables replicated by them are missing in Tables.
+	CaptureErrors []*CaptureError `json:"capture_errors,omitempty"`
+}
+
+// CaptureError holds the error of a request sent to a capture
+type CaptureError struct {
+	CaptureID string `json:"capture_id"`
+	Error     string `json:"error"`
+}
+
 // CaptureTaskStatus holds TaskStatus of a capture
 type CaptureTaskStatus struct {
 	CaptureID string `json:"capture_id"`
@@ -170,7 +210,130 @@ type CaptureTaskStatus struct {
 
 // Capture holds common information of a capture in cdc
 type Capture struct {
-	ID            string `json:"id"`
-	IsOwner       bool   `json:"is_owner"`
-	AdvertiseAddr string `json:"address"`
+	ID            string            `json:"id"`
+	IsOwner       bool              `json:"is_owner"`
+	AdvertiseAddr string            `json:"address"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+// ChangefeedConfigV2 is used to create or update a changefeed by the v2 open API.
+// When updating a changefeed, only the non-zero fields are applied.
+type ChangefeedConfigV2 struct {
+	ID       string `json:"changefeed_id"`
+	StartTs  uint64 `json:"start_ts"`
+	TargetTs uint64 `json:"target_ts"`
+	SinkURI  string `json:"sink_uri"`
+	// Engine is the sort engine, the unified sorter is used if it is empty.
+	Engine            SortEngine            `json:"sort_engine"`
+	Opts              map[string]string     `json:"opts"`
+	ReplicaConfig     *config.ReplicaConfig `json:"replica_config"`
+	SyncPointEnabled  *bool                 `json:"sync_point_enabled"`
+	SyncPointInterval time.Duration         `json:"sync_point_interval"`
+	// Priority is the priority of the changefeed, it is normal if it is nil.
+	Priority *priority.Priority `json:"priority"`
+	// timezone used when checking sink uri
+	TimeZone string `json:"timezone" default:"system"`
+	// DisableGCCheck skips checking whether the start ts is before the GC safe point.
+	DisableGCCheck        bool `json:"disable_gc_check"`
+	IgnoreIneligibleTable bool `json:"ignore_ineligible_table"`
+	// Labels are used to select changefeeds in batch operations, they are
+	// replaced as a whole when updating a changefeed.
+	Labels map[string]string `json:"labels"`
+	// Template is the name of the changefeed template that the changefeed is
+	// created from, the non-zero fields of the config override the template.
+	Template string `json:"template"`
+	// Paused creates the changefeed in the stopped state, it is
+	// ignored when updating a changefeed.
+	Paused bool `json:"paused"`
+}
+
+// ChangefeedDetailV2 holds the full information of a changefeed.
+type ChangefeedDetailV2 struct {
+	ID         string              `json:"id"`
+	Info       *ChangeFeedInfo     `json:"info"`
+	Status     *ChangeFeedStatus   `json:"status"`
+	Count      uint64              `json:"count"`
+	TaskStatus []CaptureTaskStatus `json:"task_status"`
+	// IneligibleTables are the tables that can not be replicated,
+	// it is only returned when verifying a changefeed config.
+	IneligibleTables []TableName `json:"ineligible_tables,omitempty"`
+}
+
+// ChangefeedListV2 is a page of changefeeds.
+type ChangefeedListV2 struct {
+	Total int                    `json:"total"`
+	Items []ChangefeedCommonInfo `json:"items"`
+}
+
+// CaptureListV2 is a page of captures.
+type CaptureListV2 struct {
+	Total int       `json:"total"`
+	Items []Capture `json:"items"`
+}
+
+// DrainCaptureResp is the response of draining a capture.
+type DrainCaptureResp struct {
+	// The number of tables still on the capture, it is safe to stop the capture when it is zero.
+	CurrentTableCount int `json:"current_table_count"`
+}
+
+// ChangefeedTemplateListV2 is a page of changefeed templates.
+type ChangefeedTemplateListV2 struct {
+	Total int                  `json:"total"`
+	Items []ChangefeedTemplate `json:"items"`
+}
+
+// BatchChangefeedResultV2 is the result of pausing, resuming or removing
+// the changefeeds selected by labels.
+type BatchChangefeedResultV2 struct {
+	// Succeeded are the changefeeds that the operation is applied to.
+	Succeeded []string `json:"succeeded"`
+	// Failed maps the changefeeds that the operation fails on to the errors.
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+// ProcessorListV2 is a page of processors.
+type ProcessorListV2 struct {
+	Total int                   `json:"total"`
+	Items []ProcessorCommonInfo `json:"items"`
+}
+
+// ChangefeedStatisticsV2 holds the replication statistics of a changefeed.
+type ChangefeedStatisticsV2 struct {
+	// Count is the number of the events that have been replicated.
+	Count        uint64 `json:"count"`
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	ResolvedTs   uint64 `json:"resolved_ts"`
+	CurrentTs    uint64 `json:"current_ts"`
+	// SinkGap is the gap between the resolved ts and the checkpoint ts in milliseconds.
+	SinkGap int64 `json:"sink_gap"`
+	// ReplicationGap is the gap between the current ts and the checkpoint ts in milliseconds.
+	ReplicationGap int64 `json:"replication_gap"`
+}
+
+// RedoMetaV2 holds the meta of the redo logs of a changefeed.
+type RedoMetaV2 struct {
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	ResolvedTs   uint64 `json:"resolved_ts"`
+}
+
+// TSOV2 is a timestamp allocated by PD.
+type TSOV2 struct {
+	Timestamp    uint64 `json:"timestamp"`
+	PhysicalTime int64  `json:"physical_time"`
+	LogicalTime  int64  `json:"logical_time"`
+}
+
+// ResolveLockReqV2 is used to resolve the locks in a region.
+type ResolveLockReqV2 struct {
+	RegionID uint64 `json:"region_id"`
+	// Ts is the timestamp that the locks before it are resolved,
+	// default to 1 minute ago from now.
+	Ts uint64 `json:"ts"`
+}
+
+// EtcdDataV2 is a key-value pair stored in etcd.
+type EtcdDataV2 struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
@@ -81,6 +81,19 @@ type RawKVEntry struct {
 
 	// Additional debug info
 	RegionID uint64 `msg:"region_id"`
+
+	// Trace is only set for the events of sampled transactions.
+	Trace *TraceContext `msg:"trace,omitempty"`
+}
+
+// TraceContext is the trace context carried by the events of a sampled
+// transaction through the pipeline of the processor.
+type TraceContext struct {
+	// Parent is the W3C traceparent of the span of the previous stage.
+	Parent string `msg:"parent"`
+	// HandoffTime is the unix time in nanoseconds when the previous stage
+	// handed the event off.
+	HandoffTime int64 `msg:"handoff_time"`
 }
 
 func (v *RawKVEntry) String() string {
@@ -122,6 +122,53 @@ func (z *RawKVEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "RegionID")
 				return
 			}
+		case "trace":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					err = msgp.WrapError(err, "Trace")
+					return
+				}
+				z.Trace = nil
+			} else {
+				if z.Trace == nil {
+					z.Trace = new(TraceContext)
+				}
+				var zb0003 uint32
+				zb0003, err = dc.ReadMapHeader()
+				if err != nil {
+					err = msgp.WrapError(err, "Trace")
+					return
+				}
+				for zb0003 > 0 {
+					zb0003--
+					field, err = dc.ReadMapKeyPtr()
+					if err != nil {
+						err = msgp.WrapError(err, "Trace")
+						return
+					}
+					switch msgp.UnsafeString(field) {
+					case "parent":
+						z.Trace.Parent, err = dc.ReadString()
+						if err != nil {
+							err = msgp.WrapError(err, "Trace", "Parent")
+							return
+						}
+					case "handoff_time":
+						z.Trace.HandoffTime, err = dc.ReadInt64()
+						if err != nil {
+							err = msgp.WrapError(err, "Trace", "HandoffTime")
+							return
+						}
+					default:
+						err = dc.Skip()
+						if err != nil {
+							err = msgp.WrapError(err, "Trace")
+							return
+						}
+					}
+				}
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -135,9 +182,23 @@ func (z *RawKVEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *RawKVEntry) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 7
+	// omitempty: check for empty values
+	zb0001Len := uint32(8)
+	var zb0001Mask uint8 /* 8 bits */
+	if z.Trace == nil {
+		zb0001Len--
+		zb0001Mask |= 0x80
+	}
+	// variable map header, size zb0001Len
+	err = en.Append(0x80 | uint8(zb0001Len))
+	if err != nil {
+		return
+	}
+	if zb0001Len == 0 {
+		return
+	}
 	// write "op_type"
-	err = en.Append(0x87, 0xa7, 0x6f, 0x70, 0x5f, 0x74, 0x79, 0x70, 0x65)
+	err = en.Append(0xa7, 0x6f, 0x70, 0x5f, 0x74, 0x79, 0x70, 0x65)
 	if err != nil {
 		return
 	}
@@ -206,15 +267,61 @@ func (z *RawKVEntry) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "RegionID")
 		return
 	}
+	if (zb0001Mask & 0x80) == 0 { // if not empty
+		// write "trace"
+		err = en.Append(0xa5, 0x74, 0x72, 0x61, 0x63, 0x65)
+		if err != nil {
+			return
+		}
+		if z.Trace == nil {
+			err = en.WriteNil()
+			if err != nil {
+				return
+			}
+		} else {
+			// map header, size 2
+			// write "parent"
+			err = en.Append(0x82, 0xa6, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74)
+			if err != nil {
+				return
+			}
+			err = en.WriteString(z.Trace.Parent)
+			if err != nil {
+				err = msgp.WrapError(err, "Trace", "Parent")
+				return
+			}
+			// write "handoff_time"
+			err = en.Append(0xac, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x66, 0x66, 0x5f, 0x74, 0x69, 0x6d, 0x65)
+			if err != nil {
+				return
+			}
+			err = en.WriteInt64(z.Trace.HandoffTime)
+			if err != nil {
+				err = msgp.WrapError(err, "Trace", "HandoffTime")
+				return
+			}
+		}
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *RawKVEntry) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 7
+	// omitempty: check for empty values
+	zb0001Len := uint32(8)
+	var zb0001Mask uint8 /* 8 bits */
+	if z.Trace == nil {
+		zb0001Len--
+		zb0001Mask |= 0x80
+	}
+	// variable map header, size zb0001Len
+	o = append(o, 0x80|uint8(zb0001Len))
+	if zb0001Len == 0 {
+		return
+	}
 	// string "op_type"
-	o = append(o, 0x87, 0xa7, 0x6f, 0x70, 0x5f, 0x74, 0x79, 0x70, 0x65)
+	o = append(o, 0xa7, 0x6f, 0x70, 0x5f, 0x74, 0x79, 0x70, 0x65)
 	o = msgp.AppendInt(o, int(z.OpType))
 	// string "key"
 	o = append(o, 0xa3, 0x6b, 0x65, 0x79)
@@ -234,6 +341,21 @@ func (z *RawKVEntry) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "region_id"
 	o = append(o, 0xa9, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64)
 	o = msgp.AppendUint64(o, z.RegionID)
+	if (zb0001Mask & 0x80) == 0 { // if not empty
+		// string "trace"
+		o = append(o, 0xa5, 0x74, 0x72, 0x61, 0x63, 0x65)
+		if z.Trace == nil {
+			o = msgp.AppendNil(o)
+		} else {
+			// map header, size 2
+			// string "parent"
+			o = append(o, 0x82, 0xa6, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74)
+			o = msgp.AppendString(o, z.Trace.Parent)
+			// string "handoff_time"
+			o = append(o, 0xac, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x66, 0x66, 0x5f, 0x74, 0x69, 0x6d, 0x65)
+			o = msgp.AppendInt64(o, z.Trace.HandoffTime)
+		}
+	}
 	return
 }
 
@@ -301,6 +423,52 @@ func (z *RawKVEntry) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "RegionID")
 				return
 			}
+		case "trace":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Trace = nil
+			} else {
+				if z.Trace == nil {
+					z.Trace = new(TraceContext)
+				}
+				var zb0003 uint32
+				zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Trace")
+					return
+				}
+				for zb0003 > 0 {
+					zb0003--
+					field, bts, err = msgp.ReadMapKeyZC(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "Trace")
+						return
+					}
+					switch msgp.UnsafeString(field) {
+					case "parent":
+						z.Trace.Parent, bts, err = msgp.ReadStringBytes(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "Trace", "Parent")
+							return
+						}
+					case "handoff_time":
+						z.Trace.HandoffTime, bts, err = msgp.ReadInt64Bytes(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "Trace", "HandoffTime")
+							return
+						}
+					default:
+						bts, err = msgp.Skip(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "Trace")
+							return
+						}
+					}
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -315,6 +483,139 @@ func (z *RawKVEntry) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *RawKVEntry) Msgsize() (s int) {
-	s = 1 + 8 + msgp.IntSize + 4 + msgp.BytesPrefixSize + len(z.Key) + 6 + msgp.BytesPrefixSize + len(z.Value) + 10 + msgp.BytesPrefixSize + len(z.OldValue) + 9 + msgp.Uint64Size + 5 + msgp.Uint64Size + 10 + msgp.Uint64Size
+	s = 1 + 8 + msgp.IntSize + 4 + msgp.BytesPrefixSize + len(z.Key) + 6 + msgp.BytesPrefixSize + len(z.Value) + 10 + msgp.BytesPrefixSize + len(z.OldValue) + 9 + msgp.Uint64Size + 5 + msgp.Uint64Size + 10 + msgp.Uint64Size + 6
+	if z.Trace == nil {
+		s += msgp.NilSize
+	} else {
+		s += 1 + 7 + msgp.StringPrefixSize + len(z.Trace.Parent) + 13 + msgp.Int64Size
+	}
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *TraceContext) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "parent":
+			z.Parent, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Parent")
+				return
+			}
+		case "handoff_time":
+			z.HandoffTime, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "HandoffTime")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z TraceContext) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "parent"
+	err = en.Append(0x82, 0xa6, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Parent)
+	if err != nil {
+		err = msgp.WrapError(err, "Parent")
+		return
+	}
+	// write "handoff_time"
+	err = en.Append(0xac, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x66, 0x66, 0x5f, 0x74, 0x69, 0x6d, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.HandoffTime)
+	if err != nil {
+		err = msgp.WrapError(err, "HandoffTime")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z TraceContext) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "parent"
+	o = append(o, 0x82, 0xa6, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74)
+	o = msgp.AppendString(o, z.Parent)
+	// string "handoff_time"
+	o = append(o, 0xac, 0x68, 0x61, 0x6e, 0x64, 0x6f, 0x66, 0x66, 0x5f, 0x74, 0x69, 0x6d, 0x65)
+	o = msgp.AppendInt64(o, z.HandoffTime)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *TraceContext) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "parent":
+			z.Parent, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Parent")
+				return
+			}
+		case "handoff_time":
+			z.HandoffTime, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "HandoffTime")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z TraceContext) Msgsize() (s int) {
+	s = 1 + 7 + msgp.StringPrefixSize + len(z.Parent) + 13 + msgp.Int64Size
 	return
 }
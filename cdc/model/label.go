@@ -0,0 +1,116 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const labelMaxLen = 63
+
+var labelRe = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?$`)
+
+// ValidateLabels checks the labels of a changefeed. Keys and non-empty values
+// must start and end with an alphanumeric character, may contain '-', '_'
+// and '.' in between, and can not be longer than 63 characters.
+func ValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if !isValidLabel(k) {
+			return cerror.ErrInvalidChangefeedLabel.GenWithStackByArgs(
+				fmt.Sprintf("bad key %q", k))
+		}
+		if v != "" && !isValidLabel(v) {
+			return cerror.ErrInvalidChangefeedLabel.GenWithStackByArgs(
+				fmt.Sprintf("bad value %q of key %q", v, k))
+		}
+	}
+	return nil
+}
+
+func isValidLabel(s string) bool {
+	return len(s) <= labelMaxLen && labelRe.MatchString(s)
+}
+
+// labelRequirement is a single requirement of a LabelSelector.
+type labelRequirement struct {
+	key string
+	// op is one of "=", "!=", "exists" and "!exists".
+	op    string
+	value string
+}
+
+func (r labelRequirement) matches(labels map[string]string) bool {
+	v, ok := labels[r.key]
+	switch r.op {
+	case "=":
+		return ok && v == r.value
+	case "!=":
+		return !ok || v != r.value
+	case "exists":
+		return ok
+	default:
+		return !ok
+	}
+}
+
+// LabelSelector selects changefeeds by their labels, a changefeed is
+// selected only if its labels match all the requirements.
+type LabelSelector []labelRequirement
+
+// ParseLabelSelector parses a comma separated list of requirements, each of
+// them is one of `key=value`, `key==value`, `key!=value`, `key` (the key
+// exists) and `!key` (the key does not exist).
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, cerror.ErrInvalidLabelSelector.GenWithStackByArgs(selector, "empty selector")
+	}
+	var s LabelSelector
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		var r labelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			kv := strings.SplitN(part, "!=", 2)
+			r = labelRequirement{key: kv[0], op: "!=", value: kv[1]}
+		case strings.Contains(part, "="):
+			kv := strings.SplitN(strings.Replace(part, "==", "=", 1), "=", 2)
+			r = labelRequirement{key: kv[0], op: "=", value: kv[1]}
+		case strings.HasPrefix(part, "!"):
+			r = labelRequirement{key: part[1:], op: "!exists"}
+		default:
+			r = labelRequirement{key: part, op: "exists"}
+		}
+		r.key, r.value = strings.TrimSpace(r.key), strings.TrimSpace(r.value)
+		if !isValidLabel(r.key) || (r.value != "" && !isValidLabel(r.value)) {
+			return nil, cerror.ErrInvalidLabelSelector.GenWithStackByArgs(
+				selector, fmt.Sprintf("bad requirement %q", part))
+		}
+		s = append(s, r)
+	}
+	return s, nil
+}
+
+// Matches returns whether the labels satisfy all the requirements.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLabels(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, ValidateLabels(nil))
+	require.Nil(t, ValidateLabels(map[string]string{
+		"env": "prod", "team.name": "dba_1", "tier": "",
+	}))
+	for _, labels := range []map[string]string{
+		{"": "prod"},
+		{"-env": "prod"},
+		{"env": "prod test"},
+		{"env": "prod/"},
+		{"env": string(make([]byte, 64))},
+	} {
+		err := ValidateLabels(labels)
+		require.True(t, cerror.ErrInvalidChangefeedLabel.Equal(err), "%v", labels)
+	}
+}
+
+func TestLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"env": "prod", "team": "dba"}
+	testCases := []struct {
+		selector string
+		matches  bool
+	}{
+		{"env=prod", true},
+		{"env==prod", true},
+		{" env = prod , team = dba ", true},
+		{"env=prod,team=sre", false},
+		{"env!=test", true},
+		{"region!=us", true},
+		{"env!=prod", false},
+		{"team", true},
+		{"region", false},
+		{"!region", true},
+		{"!env", false},
+		{"region=", false},
+	}
+	for _, tc := range testCases {
+		s, err := ParseLabelSelector(tc.selector)
+		require.Nil(t, err, tc.selector)
+		require.Equal(t, tc.matches, s.Matches(labels), tc.selector)
+	}
+
+	for _, selector := range []string{"", " ", "=prod", "env=prod,", "env=a b", "!"} {
+		_, err := ParseLabelSelector(selector)
+		require.True(t, cerror.ErrInvalidLabelSelector.Equal(err), selector)
+	}
+}
@@ -16,6 +16,7 @@ package model
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"unsafe"
 
@@ -41,8 +42,26 @@ const (
 	MqMessageTypeDDL
 	// MqMessageTypeResolved is resolved type of message key
 	MqMessageTypeResolved
+	// MqMessageTypeTxn is transaction marker type of message key
+	MqMessageTypeTxn
 )
 
+// String implements fmt.Stringer interface.
+func (t MqMessageType) String() string {
+	switch t {
+	case MqMessageTypeRow:
+		return "row"
+	case MqMessageTypeDDL:
+		return "ddl"
+	case MqMessageTypeResolved:
+		return "resolved"
+	case MqMessageTypeTxn:
+		return "txn"
+	default:
+		return "unknown"
+	}
+}
+
 // ColumnFlagType is for encapsulating the flag operations for different flags.
 type ColumnFlagType util.Flag
 
@@ -267,6 +286,13 @@ type RowChangedEvent struct {
 	// ApproximateDataSize is the approximate size of protobuf binary
 	// representation of this event.
 	ApproximateDataSize int64 `json:"-" msg:"-"`
+
+	// Trace is only set for the rows of sampled transactions.
+	Trace *TraceContext `json:"-" msg:"-"`
+
+	// TxnIndex is the 1-based position of the row in its transaction. It is
+	// only set by the MQ sinks which enable the transaction metadata.
+	TxnIndex int64 `json:"-" msg:"-"`
 }
 
 // IsDelete returns true if the row is a delete event
@@ -330,6 +356,21 @@ func (r *RowChangedEvent) HandleKeyColumns() []*Column {
 	return pkeyCols
 }
 
+// GetColumn returns the column with the given name case-insensitively, or
+// nil if it doesn't exist. The pre columns are looked up for a deleted row.
+func (r *RowChangedEvent) GetColumn(name string) *Column {
+	cols := r.Columns
+	if r.IsDelete() {
+		cols = r.PreColumns
+	}
+	for _, col := range cols {
+		if col != nil && strings.EqualFold(col.Name, name) {
+			return col
+		}
+	}
+	return nil
+}
+
 // WithHandlePrimaryFlag set `HandleKeyFlag` and `PrimaryKeyFlag`
 func (r *RowChangedEvent) WithHandlePrimaryFlag(colNames map[string]struct{}) {
 	for _, col := range r.Columns {
@@ -553,3 +594,32 @@ func (t *SingleTableTxn) Append(row *RowChangedEvent) {
 	}
 	t.Rows = append(t.Rows, row)
 }
+
+// TxnEventType is the type of a TxnEvent.
+//msgp:ignore TxnEventType
+type TxnEventType string
+
+// The types of TxnEvent.
+const (
+	TxnEventTypeBegin  TxnEventType = "BEGIN"
+	TxnEventTypeCommit TxnEventType = "COMMIT"
+)
+
+// TxnEvent marks the beginning or the end of a transaction, it is sent to
+// the transaction topic by the MQ sinks which enable the transaction metadata.
+// The markers only cover the tables replicated by a capture, a transaction
+// whose tables are replicated by several captures has a pair of markers from
+// each of them.
+//msgp:ignore TxnEvent
+type TxnEvent struct {
+	Type     TxnEventType
+	StartTs  uint64
+	CommitTs uint64
+	// RowCount is the number of rows in the transaction,
+	// it is only set for the COMMIT events.
+	RowCount int64
+	// TableRowCounts is the number of rows of every table in the transaction,
+	// keyed by the routed `schema.table` name, it is only set for the COMMIT
+	// events.
+	TableRowCounts map[string]int64
+}
@@ -69,6 +69,16 @@ func TestFlagValue(t *testing.T) {
 	require.Equal(t, ColumnFlagType(0b1000000), NullableFlag)
 }
 
+func TestMqMessageTypeString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "row", MqMessageTypeRow.String())
+	require.Equal(t, "ddl", MqMessageTypeDDL.String())
+	require.Equal(t, "resolved", MqMessageTypeResolved.String())
+	require.Equal(t, "txn", MqMessageTypeTxn.String())
+	require.Equal(t, "unknown", MqMessageTypeUnknown.String())
+}
+
 func TestTableNameFuncs(t *testing.T) {
 	t.Parallel()
 	tbl := &TableName{
@@ -141,6 +151,10 @@ func TestRowChangedEventFuncs(t *testing.T) {
 	require.False(t, insertRow.IsDelete())
 	require.Equal(t, expectedPrimaryKeyCols, insertRow.PrimaryKeyColumns())
 	require.Equal(t, expectedHandleKeyCols, insertRow.HandleKeyColumns())
+
+	require.Equal(t, deleteRow.PreColumns[1], deleteRow.GetColumn("B"))
+	require.Equal(t, insertRow.Columns[0], insertRow.GetColumn("a"))
+	require.Nil(t, insertRow.GetColumn("c"))
 }
 
 func TestColumnValueString(t *testing.T) {
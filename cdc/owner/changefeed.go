@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	schedulerv2 "github.com/pingcap/tiflow/cdc/scheduler"
+	schedulerutil "github.com/pingcap/tiflow/cdc/scheduler/util"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
@@ -54,6 +55,15 @@ type changefeed struct {
 	initialized bool
 	// isRemoved is true if the changefeed is removed
 	isRemoved bool
+	// externalWorkloads is the number of tables of other changefeeds
+	// on each capture, whose priorities are not lower than this one.
+	externalWorkloads map[model.CaptureID]int
+	// drainingCaptures are the captures that no table should be placed on,
+	// it is shared with the owner.
+	drainingCaptures map[model.CaptureID]struct{}
+	// captureFilter decides which captures a table can be placed on
+	// according to the placement config.
+	captureFilter schedulerutil.CaptureFilter
 
 	// only used for asyncExecDDL function
 	// ddlEventCache is not nil when the changefeed is executing a DDL event asynchronously
@@ -64,6 +74,11 @@ type changefeed struct {
 	// The ones that have not been executed yet do not have.
 	currentTableNames []model.TableName
 
+	// schemaBootstrapRequested is true if a schema bootstrap should be
+	// published, bootstrapEvents are the DDL events of it being emitted.
+	schemaBootstrapRequested bool
+	bootstrapEvents          []*model.DDLEvent
+
 	errCh chan error
 	// cancel the running goroutine start by `DDLPuller`
 	cancel context.CancelFunc
@@ -205,6 +220,13 @@ func (c *changefeed) tick(ctx cdcContext.Context, state *orchestrator.Changefeed
 	}
 	c.sink.emitCheckpointTs(checkpointTs, c.currentTableNames)
 
+	// No table is scheduled until the schema bootstrap is emitted, so that
+	// the rows of the tables are always sent after their schemas.
+	done, err := c.handleSchemaBootstrap(ctx, checkpointTs)
+	if err != nil || !done {
+		return errors.Trace(err)
+	}
+
 	barrierTs, err := c.handleBarrier(ctx)
 	if err != nil {
 		return errors.Trace(err)
@@ -219,6 +241,7 @@ func (c *changefeed) tick(ctx cdcContext.Context, state *orchestrator.Changefeed
 		return nil
 	}
 
+	c.scheduler.SetExternalWorkloads(c.externalWorkloads)
 	startTime := time.Now()
 	newCheckpointTs, newResolvedTs, err := c.scheduler.Tick(ctx, c.state, c.schema.AllPhysicalTables(), captures)
 	costTime := time.Since(startTime)
@@ -302,6 +325,9 @@ LOOP:
 	// the DDL barrier to the correct start point.
 	c.barriers.Update(ddlJobBarrier, checkpointTs-1)
 	c.barriers.Update(finishBarrier, c.state.Info.GetTargetTs())
+	if c.state.Info.Config.Sink.SchemaBootstrap && checkpointTs == c.state.Info.StartTs {
+		c.schemaBootstrapRequested = true
+	}
 	var err error
 	// Note that (checkpointTs == ddl.FinishedTs) DOES NOT imply that the DDL has been completed executed.
 	// So we need to process all DDLs from the range [checkpointTs, ...), but since the semantics of start-ts requires
@@ -351,6 +377,16 @@ LOOP:
 	if err != nil {
 		return errors.Trace(err)
 	}
+	c.captureFilter, err = newCaptureFilter(c.state.Info.Config, c.schema.TableNameByPhysicalID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.scheduler.SetCaptureFilter(func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		if _, ok := c.drainingCaptures[capture.ID]; ok {
+			return false
+		}
+		return c.captureFilter == nil || c.captureFilter(tableID, capture)
+	})
 
 	c.initialized = true
 	return nil
@@ -367,6 +403,7 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 	c.cancel = func() {}
 	c.ddlPuller.Close()
 	c.schema = nil
+	c.bootstrapEvents = nil
 	c.redoManagerCleanup(ctx)
 	canceledCtx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -534,6 +571,43 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 	return barrierTs, nil
 }
 
+// requestSchemaBootstrap requests to publish a schema bootstrap, it is
+// published once the changefeed is running and no DDL is being executed.
+func (c *changefeed) requestSchemaBootstrap() {
+	c.schemaBootstrapRequested = true
+}
+
+// handleSchemaBootstrap emits the CREATE TABLE DDL events of all the tables
+// at checkpointTs if a schema bootstrap is requested, it returns true if no
+// schema bootstrap is being emitted.
+func (c *changefeed) handleSchemaBootstrap(ctx cdcContext.Context, checkpointTs model.Ts) (bool, error) {
+	if !c.schemaBootstrapRequested || c.ddlEventCache != nil {
+		return true, nil
+	}
+	if c.bootstrapEvents == nil {
+		events, err := c.schema.BuildSchemaBootstrapEvents(checkpointTs)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if len(events) == 0 {
+			c.schemaBootstrapRequested = false
+			return true, nil
+		}
+		log.Info("publish schema bootstrap", zap.String("changefeed", c.id),
+			zap.Uint64("checkpointTs", checkpointTs), zap.Int("tableCount", len(events)))
+		c.bootstrapEvents = events
+	}
+	done, err := c.sink.emitBootstrapEvents(ctx, c.bootstrapEvents)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if done {
+		c.schemaBootstrapRequested = false
+		c.bootstrapEvents = nil
+	}
+	return done, nil
+}
+
 func (c *changefeed) asyncExecDDL(ctx cdcContext.Context, job *timodel.Job) (done bool, err error) {
 	if job.BinlogInfo == nil {
 		log.Warn("ignore the invalid DDL job", zap.String("changefeed", c.id),
@@ -633,6 +707,90 @@ func (c *changefeed) Close(ctx cdcContext.Context) {
 	changefeedCloseDuration.Observe(costTime.Seconds())
 }
 
+// drainCapture moves the tables of the changefeed off the given capture, and
+// returns the number of tables that are still on it.
+func (c *changefeed) drainCapture(
+	captureID model.CaptureID,
+	captures map[model.CaptureID]*model.CaptureInfo,
+	drainingCaptures map[model.CaptureID]struct{},
+) int {
+	if !c.initialized {
+		return 0
+	}
+	statuses, err := c.taskStatuses()
+	if err != nil {
+		log.Warn("get task statuses failed", zap.String("changefeed", c.id), zap.Error(err))
+		return 0
+	}
+	status, ok := statuses[captureID]
+	if !ok {
+		return 0
+	}
+
+	targets := make(map[model.CaptureID]*model.CaptureInfo, len(captures))
+	for id, info := range captures {
+		if _, ok := drainingCaptures[id]; !ok {
+			targets[id] = info
+		}
+	}
+	tableCounts := c.tableCounts()
+	remaining := 0
+	for tableID := range status.Tables {
+		remaining++
+		if status.Operation[tableID] != nil {
+			// Wait for the ongoing operation to finish.
+			continue
+		}
+		target := ""
+		for id := range schedulerutil.FilterCaptures(c.captureFilter, tableID, targets) {
+			if target == "" || tableCounts[id] < tableCounts[target] {
+				target = id
+			}
+		}
+		if target == "" {
+			continue
+		}
+		tableCounts[target]++
+		log.Info("move table off draining capture",
+			zap.String("changefeed", c.id), zap.Int64("tableID", tableID),
+			zap.String("source", captureID), zap.String("target", target))
+		c.scheduler.MoveTable(tableID, target)
+	}
+	for tableID, op := range status.Operation {
+		if _, ok := status.Tables[tableID]; !ok && op.Status != model.OperFinished {
+			// The table is being removed from the capture.
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// taskStatuses returns the task statuses of the changefeed on all captures.
+func (c *changefeed) taskStatuses() (map[model.CaptureID]*model.TaskStatus, error) {
+	if provider := c.GetInfoProvider(); provider != nil {
+		return provider.GetTaskStatuses()
+	}
+	if c.state == nil {
+		return nil, nil
+	}
+	return c.state.TaskStatuses, nil
+}
+
+// tableCounts returns the number of tables of the changefeed on each capture.
+func (c *changefeed) tableCounts() map[model.CaptureID]int {
+	if provider := c.GetInfoProvider(); provider != nil {
+		return provider.GetTotalTableCounts()
+	}
+	ret := make(map[model.CaptureID]int)
+	if c.state == nil {
+		return ret
+	}
+	for captureID, taskStatus := range c.state.TaskStatuses {
+		ret[captureID] = len(taskStatus.Tables)
+	}
+	return ret
+}
+
 // GetInfoProvider returns an InfoProvider if one is available.
 func (c *changefeed) GetInfoProvider() schedulerv2.InfoProvider {
 	if provider, ok := c.scheduler.(schedulerv2.InfoProvider); ok {
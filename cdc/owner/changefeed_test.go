@@ -69,7 +69,11 @@ type mockDDLSink struct {
 	// DDLSink
 	ddlExecuting *model.DDLEvent
 	ddlDone      bool
-	mu           struct {
+	// bootstrapEvents are the DDL events of the schema bootstrap being
+	// emitted, bootstrapDone is returned by emitBootstrapEvents.
+	bootstrapEvents []*model.DDLEvent
+	bootstrapDone   bool
+	mu              struct {
 		sync.Mutex
 		checkpointTs      model.Ts
 		currentTableNames []model.TableName
@@ -94,6 +98,11 @@ func (m *mockDDLSink) emitDDLEvent(ctx cdcContext.Context, ddl *model.DDLEvent)
 	return m.ddlDone, nil
 }
 
+func (m *mockDDLSink) emitBootstrapEvents(ctx cdcContext.Context, events []*model.DDLEvent) (bool, error) {
+	m.bootstrapEvents = events
+	return m.bootstrapDone, nil
+}
+
 func (m *mockDDLSink) emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64) error {
 	if checkpointTs == m.syncPoint {
 		return nil
@@ -313,6 +322,76 @@ func TestExecDDL(t *testing.T) {
 	require.Contains(t, state.TaskStatuses[ctx.GlobalVars().CaptureInfo.ID].Tables, job.TableID)
 }
 
+func TestSchemaBootstrap(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.DDL2Job("create database test0")
+	job := helper.DDL2Job("create table test0.table0(id int primary key)")
+	tableID := job.TableID
+	startTs := job.BinlogInfo.FinishedTS + 1000
+
+	ctx := cdcContext.NewContext(context.Background(), &cdcContext.GlobalVars{
+		KVStorage: helper.Storage(),
+		CaptureInfo: &model.CaptureInfo{
+			ID:            "capture-id-test",
+			AdvertiseAddr: "127.0.0.1:0000",
+			Version:       version.ReleaseVersion,
+		},
+		PDClock: pdtime.NewClock4Test(),
+	})
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.SchemaBootstrap = true
+	ctx = cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
+		ID: "changefeed-id-test",
+		Info: &model.ChangeFeedInfo{
+			StartTs: startTs,
+			Config:  cfg,
+		},
+	})
+
+	cf, state, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+	tickThreeTime := func() {
+		cf.Tick(ctx, state, captures)
+		tester.MustApplyPatches()
+		cf.Tick(ctx, state, captures)
+		tester.MustApplyPatches()
+		cf.Tick(ctx, state, captures)
+		tester.MustApplyPatches()
+	}
+	// pre check and initialize
+	tickThreeTime()
+	mockDDLPuller := cf.ddlPuller.(*mockDDLPuller)
+	mockDDLPuller.resolvedTs = startTs
+	mockDDLSink := cf.sink.(*mockDDLSink)
+	tickThreeTime()
+	// no table is scheduled until the schema bootstrap is emitted
+	require.Len(t, mockDDLSink.bootstrapEvents, 1)
+	require.Equal(t, startTs, mockDDLSink.bootstrapEvents[0].CommitTs)
+	require.Equal(t, "table0", mockDDLSink.bootstrapEvents[0].TableInfo.Table)
+	require.Len(t, state.TaskStatuses[ctx.GlobalVars().CaptureInfo.ID].Tables, 0)
+
+	mockDDLSink.bootstrapDone = true
+	tickThreeTime()
+	require.False(t, cf.schemaBootstrapRequested)
+	require.Contains(t, state.TaskStatuses[ctx.GlobalVars().CaptureInfo.ID].Tables, tableID)
+
+	// publish the schema bootstrap on demand
+	mockDDLSink.bootstrapDone = false
+	mockDDLSink.bootstrapEvents = nil
+	mockDDLPuller.resolvedTs += 1000
+	tickThreeTime()
+	require.Nil(t, mockDDLSink.bootstrapEvents)
+	cf.requestSchemaBootstrap()
+	tickThreeTime()
+	require.Len(t, mockDDLSink.bootstrapEvents, 1)
+	require.Equal(t, state.Status.CheckpointTs, mockDDLSink.bootstrapEvents[0].CommitTs)
+	require.True(t, cf.schemaBootstrapRequested)
+	mockDDLSink.bootstrapDone = true
+	tickThreeTime()
+	require.False(t, cf.schemaBootstrapRequested)
+}
+
 func TestEmitCheckpointTs(t *testing.T) {
 	helper := entry.NewSchemaTestHelper(t)
 	defer helper.Close()
@@ -50,6 +50,10 @@ type DDLSink interface {
 	// the DDL event will be sent to another goroutine and execute to downstream
 	// the caller of this function can call again and again until a true returned
 	emitDDLEvent(ctx cdcContext.Context, ddl *model.DDLEvent) (bool, error)
+	// emitBootstrapEvents emits the DDL events of a schema bootstrap and
+	// returns true if all of them are sent to downstream, the caller of this
+	// function can call again and again until a true returned
+	emitBootstrapEvents(ctx cdcContext.Context, events []*model.DDLEvent) (bool, error)
 	emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64) error
 	// close the sink, cancel running goroutine.
 	close(ctx context.Context) error
@@ -67,9 +71,14 @@ type ddlSinkImpl struct {
 	}
 	ddlFinishedTs model.Ts
 	ddlSentTs     model.Ts
+	// bootstrapSent is true if a schema bootstrap is sent to bootstrapCh,
+	// bootstrapFinished is set to 1 after all the events of it are emitted.
+	bootstrapSent     bool
+	bootstrapFinished int32
 
-	ddlCh chan *model.DDLEvent
-	errCh chan error
+	ddlCh       chan *model.DDLEvent
+	bootstrapCh chan []*model.DDLEvent
+	errCh       chan error
 
 	sink sink.Sink
 	// `sinkInitHandler` can be helpful in unit testing.
@@ -83,6 +92,7 @@ type ddlSinkImpl struct {
 func newDDLSink() DDLSink {
 	return &ddlSinkImpl{
 		ddlCh:           make(chan *model.DDLEvent, 1),
+		bootstrapCh:     make(chan []*model.DDLEvent, 1),
 		errCh:           make(chan error, defaultErrChSize),
 		sinkInitHandler: ddlSinkInitializer,
 		cancel:          func() {},
@@ -200,6 +210,22 @@ func (s *ddlSinkImpl) run(ctx cdcContext.Context, id model.ChangeFeedID, info *m
 					zap.Any("ddl", ddl))
 				ctx.Throw(errors.Trace(err))
 				return
+			case events := <-s.bootstrapCh:
+				log.Info("begin emit schema bootstrap",
+					zap.String("changefeed", ctx.ChangefeedVars().ID),
+					zap.Int("tableCount", len(events)))
+				for _, ddl := range events {
+					err := s.sink.EmitDDLEvent(ctx, ddl)
+					if err != nil && !cerror.ErrDDLEventIgnored.Equal(errors.Cause(err)) {
+						log.Error("Emit schema bootstrap failed",
+							zap.String("changefeed", ctx.ChangefeedVars().ID),
+							zap.Error(err),
+							zap.Any("ddl", ddl))
+						ctx.Throw(errors.Trace(err))
+						return
+					}
+				}
+				atomic.StoreInt32(&s.bootstrapFinished, 1)
 			}
 		}
 	}()
@@ -247,6 +273,27 @@ func (s *ddlSinkImpl) emitDDLEvent(ctx cdcContext.Context, ddl *model.DDLEvent)
 	return false, nil
 }
 
+func (s *ddlSinkImpl) emitBootstrapEvents(ctx cdcContext.Context, events []*model.DDLEvent) (bool, error) {
+	if atomic.CompareAndSwapInt32(&s.bootstrapFinished, 1, 0) {
+		s.bootstrapSent = false
+		log.Info("schema bootstrap emitted",
+			zap.String("changefeed", ctx.ChangefeedVars().ID),
+			zap.Int("tableCount", len(events)))
+		return true, nil
+	}
+	if s.bootstrapSent {
+		return false, nil
+	}
+	select {
+	case <-ctx.Done():
+		return false, errors.Trace(ctx.Err())
+	case s.bootstrapCh <- events:
+		s.bootstrapSent = true
+	default:
+	}
+	return false, nil
+}
+
 func (s *ddlSinkImpl) emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64) error {
 	if checkpointTs == s.lastSyncPoint {
 		return nil
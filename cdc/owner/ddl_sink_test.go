@@ -126,6 +126,35 @@ func TestExecDDLEvents(t *testing.T) {
 	}
 }
 
+func TestEmitBootstrapEvents(t *testing.T) {
+	ddlSink, mSink := newDDLSink4Test()
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx, cancel := cdcContext.WithCancel(ctx)
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+	ddlSink.run(ctx, ctx.ChangefeedVars().ID, ctx.ChangefeedVars().Info)
+
+	emit := func(events []*model.DDLEvent) {
+		for {
+			done, err := ddlSink.emitBootstrapEvents(ctx, events)
+			require.Nil(t, err)
+			if done {
+				require.Equal(t, mSink.GetDDL(), events[len(events)-1])
+				return
+			}
+		}
+	}
+	// all the events share the same commit ts
+	emit([]*model.DDLEvent{
+		{CommitTs: 1, TableInfo: &model.SimpleTableInfo{Table: "t1"}},
+		{CommitTs: 1, TableInfo: &model.SimpleTableInfo{Table: "t2"}},
+	})
+	// the schema bootstrap can be emitted again
+	emit([]*model.DDLEvent{{CommitTs: 2, TableInfo: &model.SimpleTableInfo{Table: "t1"}}})
+}
+
 func TestExecDDLError(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 
@@ -50,6 +50,30 @@ func (mr *MockOwnerMockRecorder) AsyncStop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncStop", reflect.TypeOf((*MockOwner)(nil).AsyncStop))
 }
 
+// BootstrapSchema mocks base method.
+func (m *MockOwner) BootstrapSchema(cfID model.ChangeFeedID, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BootstrapSchema", cfID, done)
+}
+
+// BootstrapSchema indicates an expected call of BootstrapSchema.
+func (mr *MockOwnerMockRecorder) BootstrapSchema(cfID, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapSchema", reflect.TypeOf((*MockOwner)(nil).BootstrapSchema), cfID, done)
+}
+
+// DrainCapture mocks base method.
+func (m *MockOwner) DrainCapture(request *owner.DrainCaptureRequest, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DrainCapture", request, done)
+}
+
+// DrainCapture indicates an expected call of DrainCapture.
+func (mr *MockOwnerMockRecorder) DrainCapture(request, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainCapture", reflect.TypeOf((*MockOwner)(nil).DrainCapture), request, done)
+}
+
 // EnqueueJob mocks base method.
 func (m *MockOwner) EnqueueJob(adminJob model.AdminJob, done chan<- error) {
 	m.ctrl.T.Helper()
@@ -29,6 +29,7 @@ import (
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/version"
 	pd "github.com/tikv/pd/client"
@@ -45,6 +46,8 @@ const (
 	ownerJobTypeAdminJob
 	ownerJobTypeDebugInfo
 	ownerJobTypeQuery
+	ownerJobTypeDrainCapture
+	ownerJobTypeSchemaBootstrap
 )
 
 // versionInconsistentLogRate represents the rate of log output when there are
@@ -70,9 +73,19 @@ type ownerJob struct {
 	// for status provider
 	query *Query
 
+	// for DrainCapture only
+	drainCapture *DrainCaptureRequest
+
 	done chan<- error
 }
 
+// DrainCaptureRequest is a request to move all tables off a capture,
+// the owner fills TableCount with the number of tables still on it.
+type DrainCaptureRequest struct {
+	CaptureID  model.CaptureID
+	TableCount int
+}
+
 // Owner managers TiCDC cluster.
 //
 // The interface is thread-safe, except for Tick, it's only used by etcd worker.
@@ -86,12 +99,18 @@ type Owner interface {
 	)
 	WriteDebugInfo(w io.Writer, done chan<- error)
 	Query(query *Query, done chan<- error)
+	DrainCapture(request *DrainCaptureRequest, done chan<- error)
+	BootstrapSchema(cfID model.ChangeFeedID, done chan<- error)
 	AsyncStop()
 }
 
 type ownerImpl struct {
 	changefeeds map[model.ChangeFeedID]*changefeed
 	captures    map[model.CaptureID]*model.CaptureInfo
+	// drainingCaptures are the captures that tables are being moved off,
+	// no table is placed on them until they are gone. They are the captures
+	// requested to drain and the ones marked draining in the capture infos.
+	drainingCaptures map[model.CaptureID]struct{}
 
 	gcManager gc.Manager
 
@@ -115,11 +134,12 @@ type ownerImpl struct {
 // NewOwner creates a new Owner
 func NewOwner(pdClient pd.Client) Owner {
 	return &ownerImpl{
-		changefeeds:   make(map[model.ChangeFeedID]*changefeed),
-		gcManager:     gc.NewManager(pdClient),
-		lastTickTime:  time.Now(),
-		newChangefeed: newChangefeed,
-		logLimiter:    rate.NewLimiter(versionInconsistentLogRate, versionInconsistentLogRate),
+		changefeeds:      make(map[model.ChangeFeedID]*changefeed),
+		drainingCaptures: make(map[model.CaptureID]struct{}),
+		gcManager:        gc.NewManager(pdClient),
+		lastTickTime:     time.Now(),
+		newChangefeed:    newChangefeed,
+		logLimiter:       rate.NewLimiter(versionInconsistentLogRate, versionInconsistentLogRate),
 	}
 }
 
@@ -138,6 +158,49 @@ func NewOwner4Test(
 	return o
 }
 
+// updateExternalWorkloads tells each changefeed how many tables of the other
+// changefeeds are on each capture. Only changefeeds whose priorities are not
+// lower are counted, so a changefeed with a higher priority places its tables
+// regardless of the changefeeds with lower priorities, while the latter keep
+// away from the captures that are busy with the former.
+func (o *ownerImpl) updateExternalWorkloads(state *orchestrator.GlobalReactorState) {
+	tableCounts := make(map[model.ChangeFeedID]map[model.CaptureID]int, len(o.changefeeds))
+	// priorityCounts[i] is the number of tables on each capture of the
+	// changefeeds whose priority index is i.
+	var priorityCounts [priority.Num]map[model.CaptureID]int
+	for i := range priorityCounts {
+		priorityCounts[i] = make(map[model.CaptureID]int)
+	}
+	for changefeedID, cfReactor := range o.changefeeds {
+		changefeedState, ok := state.Changefeeds[changefeedID]
+		if !ok || changefeedState.Info == nil {
+			continue
+		}
+		counts := cfReactor.tableCounts()
+		tableCounts[changefeedID] = counts
+		idx := changefeedState.Info.GetPriority().Index()
+		for captureID, count := range counts {
+			priorityCounts[idx][captureID] += count
+		}
+	}
+	for changefeedID, cfReactor := range o.changefeeds {
+		changefeedState, ok := state.Changefeeds[changefeedID]
+		if !ok || changefeedState.Info == nil {
+			continue
+		}
+		idx := changefeedState.Info.GetPriority().Index()
+		workloads := make(map[model.CaptureID]int, len(state.Captures))
+		for captureID := range state.Captures {
+			// A higher priority has a smaller index.
+			for i := 0; i <= idx; i++ {
+				workloads[captureID] += priorityCounts[i][captureID]
+			}
+			workloads[captureID] -= tableCounts[changefeedID][captureID]
+		}
+		cfReactor.externalWorkloads = workloads
+	}
+}
+
 // Tick implements the Reactor interface
 func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorState) (nextState orchestrator.ReactorState, err error) {
 	failpoint.Inject("owner-run-with-error", func() {
@@ -154,6 +217,7 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 	}
 
 	o.captures = state.Captures
+	o.updateDrainingCaptures()
 	o.updateMetrics(state)
 
 	// handleJobs() should be called before clusterVersionConsistent(), because
@@ -174,6 +238,8 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 		return nil, errors.Trace(err)
 	}
 
+	o.updateExternalWorkloads(state)
+
 	// Tick all changefeeds.
 	ctx := stdCtx.(cdcContext.Context)
 	for changefeedID, changefeedState := range state.Changefeeds {
@@ -193,6 +259,7 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 			cfReactor = o.newChangefeed(changefeedID, o.gcManager)
 			o.changefeeds[changefeedID] = cfReactor
 		}
+		cfReactor.drainingCaptures = o.drainingCaptures
 		cfReactor.Tick(ctx, changefeedState, state.Captures)
 	}
 
@@ -278,6 +345,28 @@ func (o *ownerImpl) Query(query *Query, done chan<- error) {
 	})
 }
 
+// DrainCapture moves all tables off a capture, and prevents new tables from
+// being placed on it. It can be called repeatedly until no table is left.
+// `done` must be buffered to prevent blocking owner.
+func (o *ownerImpl) DrainCapture(request *DrainCaptureRequest, done chan<- error) {
+	o.pushOwnerJob(&ownerJob{
+		Tp:           ownerJobTypeDrainCapture,
+		drainCapture: request,
+		done:         done,
+	})
+}
+
+// BootstrapSchema publishes the CREATE TABLE DDL events of all the tables of
+// the specified changefeed.
+// `done` must be buffered to prevent blocking owner.
+func (o *ownerImpl) BootstrapSchema(cfID model.ChangeFeedID, done chan<- error) {
+	o.pushOwnerJob(&ownerJob{
+		Tp:           ownerJobTypeSchemaBootstrap,
+		ChangefeedID: cfID,
+		done:         done,
+	})
+}
+
 // AsyncStop stops the owner asynchronously
 func (o *ownerImpl) AsyncStop() {
 	atomic.StoreInt32(&o.closed, 1)
@@ -420,7 +509,7 @@ func (o *ownerImpl) handleJobs() {
 	for _, job := range jobs {
 		changefeedID := job.ChangefeedID
 		cfReactor, exist := o.changefeeds[changefeedID]
-		if !exist && job.Tp != ownerJobTypeQuery {
+		if !exist && job.Tp != ownerJobTypeQuery && job.Tp != ownerJobTypeDrainCapture {
 			log.Warn("changefeed not found when handle a job", zap.Reflect("job", job))
 			job.done <- cerror.ErrChangeFeedNotExists.FastGenByArgs(job.ChangefeedID)
 			close(job.done)
@@ -435,6 +524,10 @@ func (o *ownerImpl) handleJobs() {
 			cfReactor.scheduler.Rebalance()
 		case ownerJobTypeQuery:
 			job.done <- o.handleQueries(job.query)
+		case ownerJobTypeDrainCapture:
+			job.done <- o.handleDrainCapture(job.drainCapture)
+		case ownerJobTypeSchemaBootstrap:
+			cfReactor.requestSchemaBootstrap()
 		case ownerJobTypeDebugInfo:
 			// TODO: implement this function
 		}
@@ -442,6 +535,52 @@ func (o *ownerImpl) handleJobs() {
 	}
 }
 
+// updateDrainingCaptures collects the captures marked draining in their
+// capture infos, which are written after the drain requests are handled,
+// so the drain state is kept after the owner is changed. A capture is never
+// undrained, it leaves the set when it is gone.
+func (o *ownerImpl) updateDrainingCaptures() {
+	for captureID := range o.drainingCaptures {
+		if _, ok := o.captures[captureID]; !ok {
+			log.Info("draining capture is gone", zap.String("captureID", captureID))
+			delete(o.drainingCaptures, captureID)
+		}
+	}
+	for captureID, info := range o.captures {
+		if _, ok := o.drainingCaptures[captureID]; !ok && info.Draining {
+			log.Info("capture is draining", zap.String("captureID", captureID))
+			o.drainingCaptures[captureID] = struct{}{}
+		}
+	}
+}
+
+func (o *ownerImpl) handleDrainCapture(request *DrainCaptureRequest) error {
+	captureID := request.CaptureID
+	if _, ok := o.captures[captureID]; !ok {
+		return cerror.ErrCaptureNotExist.GenWithStackByArgs(captureID)
+	}
+	available := 0
+	for id := range o.captures {
+		if _, ok := o.drainingCaptures[id]; !ok && id != captureID {
+			available++
+		}
+	}
+	if available == 0 {
+		return cerror.ErrDrainCaptureRefused.GenWithStackByArgs(
+			captureID, "no other capture can take over its tables")
+	}
+	if _, ok := o.drainingCaptures[captureID]; !ok {
+		log.Info("start draining capture", zap.String("captureID", captureID))
+		o.drainingCaptures[captureID] = struct{}{}
+	}
+
+	request.TableCount = 0
+	for _, cfReactor := range o.changefeeds {
+		request.TableCount += cfReactor.drainCapture(captureID, o.captures, o.drainingCaptures)
+	}
+	return nil
+}
+
 func (o *ownerImpl) handleQueries(query *Query) error {
 	switch query.Tp {
 	case QueryAllChangeFeedStatuses:
@@ -27,6 +27,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
@@ -319,6 +320,8 @@ func TestAdminJob(t *testing.T) {
 	done4 := make(chan error, 1)
 	var buf bytes.Buffer
 	owner.WriteDebugInfo(&buf, done4)
+	done5 := make(chan error, 1)
+	owner.BootstrapSchema("test-changefeed5", done5)
 
 	// remove job.done, it's hard to check deep equals
 	jobs := owner.takeOwnerJobs()
@@ -346,6 +349,9 @@ func TestAdminJob(t *testing.T) {
 		}, {
 			Tp:              ownerJobTypeDebugInfo,
 			debugInfoWriter: &buf,
+		}, {
+			Tp:           ownerJobTypeSchemaBootstrap,
+			ChangefeedID: "test-changefeed5",
 		},
 	})
 	require.Len(t, owner.takeOwnerJobs(), 0)
@@ -548,3 +554,112 @@ WorkLoop:
 	require.NotNil(t, infos[cf1])
 	require.Nil(t, infos[cf2])
 }
+
+func TestUpdateExternalWorkloads(t *testing.T) {
+	t.Parallel()
+
+	state := orchestrator.NewGlobalState()
+	state.Captures["capture-1"] = &model.CaptureInfo{ID: "capture-1"}
+	state.Captures["capture-2"] = &model.CaptureInfo{ID: "capture-2"}
+	owner := &ownerImpl{changefeeds: make(map[model.ChangeFeedID]*changefeed)}
+
+	addChangefeed := func(id model.ChangeFeedID, pri priority.Priority, tables map[model.CaptureID]int) {
+		cfState := orchestrator.NewChangefeedReactorState(id)
+		cfState.Info = &model.ChangeFeedInfo{Priority: pri}
+		for captureID, n := range tables {
+			status := &model.TaskStatus{Tables: make(map[model.TableID]*model.TableReplicaInfo)}
+			for i := 0; i < n; i++ {
+				status.Tables[model.TableID(i)] = &model.TableReplicaInfo{}
+			}
+			cfState.TaskStatuses[captureID] = status
+		}
+		state.Changefeeds[id] = cfState
+		owner.changefeeds[id] = &changefeed{id: id, state: cfState}
+	}
+	addChangefeed("high", priority.High, map[model.CaptureID]int{"capture-1": 4})
+	addChangefeed("normal", priority.Normal, map[model.CaptureID]int{"capture-1": 1, "capture-2": 2})
+	addChangefeed("low", priority.Low, map[model.CaptureID]int{"capture-2": 8})
+
+	owner.updateExternalWorkloads(state)
+	require.Equal(t, map[model.CaptureID]int{"capture-1": 0, "capture-2": 0},
+		owner.changefeeds["high"].externalWorkloads)
+	require.Equal(t, map[model.CaptureID]int{"capture-1": 4, "capture-2": 0},
+		owner.changefeeds["normal"].externalWorkloads)
+	require.Equal(t, map[model.CaptureID]int{"capture-1": 5, "capture-2": 2},
+		owner.changefeeds["low"].externalWorkloads)
+}
+
+// moveRecordingScheduler records the manual table moves.
+type moveRecordingScheduler struct {
+	scheduler
+	moves map[model.TableID]model.CaptureID
+}
+
+func (s *moveRecordingScheduler) MoveTable(tableID model.TableID, target model.CaptureID) {
+	s.moves[tableID] = target
+}
+
+func TestHandleDrainCapture(t *testing.T) {
+	t.Parallel()
+
+	owner := &ownerImpl{
+		changefeeds:      make(map[model.ChangeFeedID]*changefeed),
+		drainingCaptures: make(map[model.CaptureID]struct{}),
+		captures: map[model.CaptureID]*model.CaptureInfo{
+			"capture-1": {ID: "capture-1"},
+			"capture-2": {ID: "capture-2"},
+			"capture-3": {ID: "capture-3"},
+		},
+	}
+	cfState := orchestrator.NewChangefeedReactorState("test-changefeed")
+	cfState.TaskStatuses["capture-1"] = &model.TaskStatus{
+		Tables: map[model.TableID]*model.TableReplicaInfo{1: {}, 2: {}, 3: {}},
+		Operation: map[model.TableID]*model.TableOperation{
+			// table 3 is being added, table 4 is being removed.
+			3: {Status: model.OperDispatched},
+			4: {Delete: true, Status: model.OperProcessed},
+		},
+	}
+	cfState.TaskStatuses["capture-2"] = &model.TaskStatus{
+		Tables: map[model.TableID]*model.TableReplicaInfo{5: {}},
+	}
+	sched := &moveRecordingScheduler{moves: make(map[model.TableID]model.CaptureID)}
+	owner.changefeeds["test-changefeed"] = &changefeed{
+		id: "test-changefeed", state: cfState, scheduler: sched, initialized: true,
+	}
+
+	// capture-3 is drained first, it holds no table.
+	request := &DrainCaptureRequest{CaptureID: "capture-3"}
+	require.Nil(t, owner.handleDrainCapture(request))
+	require.Equal(t, 0, request.TableCount)
+	require.Empty(t, sched.moves)
+
+	// Tables are moved to the least loaded capture that is not draining.
+	request = &DrainCaptureRequest{CaptureID: "capture-1"}
+	require.Nil(t, owner.handleDrainCapture(request))
+	require.Equal(t, 4, request.TableCount)
+	require.Equal(t, map[model.TableID]model.CaptureID{1: "capture-2", 2: "capture-2"}, sched.moves)
+	require.Contains(t, owner.drainingCaptures, "capture-1")
+
+	// The last capture can not be drained.
+	request = &DrainCaptureRequest{CaptureID: "capture-2"}
+	err := owner.handleDrainCapture(request)
+	require.True(t, cerror.ErrDrainCaptureRefused.Equal(err))
+
+	request = &DrainCaptureRequest{CaptureID: "capture-4"}
+	err = owner.handleDrainCapture(request)
+	require.True(t, cerror.ErrCaptureNotExist.Equal(err))
+
+	// The drain state persisted in the capture infos is kept by a new owner,
+	// and the gone captures are forgotten.
+	owner.drainingCaptures = make(map[model.CaptureID]struct{})
+	owner.captures = map[model.CaptureID]*model.CaptureInfo{
+		"capture-1": {ID: "capture-1", Draining: true},
+		"capture-2": {ID: "capture-2"},
+	}
+	owner.updateDrainingCaptures()
+	require.Equal(t, map[model.CaptureID]struct{}{"capture-1": {}}, owner.drainingCaptures)
+	owner.drainingCaptures["capture-3"] = struct{}{}
+	owner.updateDrainingCaptures()
+	require.Equal(t, map[model.CaptureID]struct{}{"capture-1": {}}, owner.drainingCaptures)
+}
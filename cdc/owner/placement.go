@@ -0,0 +1,65 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	filter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	schedulerutil "github.com/pingcap/tiflow/cdc/scheduler/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// newCaptureFilter creates a capture filter from the placement config of a
+// changefeed, nil is returned if the tables can be placed on any capture.
+// tableName returns the name of a physical table.
+func newCaptureFilter(
+	cfg *config.ReplicaConfig,
+	tableName func(tableID model.TableID) (model.TableName, bool),
+) (schedulerutil.CaptureFilter, error) {
+	if cfg.Placement == nil ||
+		(len(cfg.Placement.CaptureLabels) == 0 && len(cfg.Placement.Rules) == 0) {
+		return nil, nil
+	}
+
+	type rule struct {
+		filter.Filter
+		labels map[string]string
+	}
+	rules := make([]rule, 0, len(cfg.Placement.Rules))
+	for _, ruleConfig := range cfg.Placement.Rules {
+		f, err := filter.Parse(ruleConfig.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+		}
+		if !cfg.CaseSensitive {
+			f = filter.CaseInsensitive(f)
+		}
+		rules = append(rules, rule{Filter: f, labels: ruleConfig.CaptureLabels})
+	}
+	defaultLabels := cfg.Placement.CaptureLabels
+
+	return func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		labels := defaultLabels
+		if name, ok := tableName(tableID); ok {
+			for _, r := range rules {
+				if r.MatchTable(name.Schema, name.Table) {
+					labels = r.labels
+					break
+				}
+			}
+		}
+		return capture.MatchLabels(labels)
+	}, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCaptureFilter(t *testing.T) {
+	t.Parallel()
+
+	names := map[model.TableID]model.TableName{
+		1: {Schema: "test", Table: "t1"},
+		2: {Schema: "analytics", Table: "T2"},
+	}
+	tableName := func(tableID model.TableID) (model.TableName, bool) {
+		name, ok := names[tableID]
+		return name, ok
+	}
+
+	cfg := config.GetDefaultReplicaConfig()
+	f, err := newCaptureFilter(cfg, tableName)
+	require.Nil(t, err)
+	require.Nil(t, f)
+
+	zone1 := &model.CaptureInfo{ID: "capture-1", Labels: map[string]string{"zone": "z1"}}
+	zone2 := &model.CaptureInfo{ID: "capture-2", Labels: map[string]string{"zone": "z2"}}
+	noLabel := &model.CaptureInfo{ID: "capture-3"}
+
+	cfg.CaseSensitive = false
+	cfg.Placement = &config.PlacementConfig{
+		CaptureLabels: map[string]string{"zone": "z1"},
+		Rules: []*config.PlacementRule{{
+			Matcher:       []string{"analytics.t2"},
+			CaptureLabels: map[string]string{"zone": "z2"},
+		}},
+	}
+	f, err = newCaptureFilter(cfg, tableName)
+	require.Nil(t, err)
+	require.True(t, f(1, zone1))
+	require.False(t, f(1, zone2))
+	require.False(t, f(1, noLabel))
+	require.False(t, f(2, zone1))
+	require.True(t, f(2, zone2))
+	// Unknown tables use the labels of the changefeed.
+	require.True(t, f(3, zone1))
+	require.False(t, f(3, zone2))
+
+	cfg.Placement.Rules[0].Matcher = []string{"["}
+	_, err = newCaptureFilter(cfg, tableName)
+	require.Regexp(t, "ErrFilterRuleInvalid", err)
+}
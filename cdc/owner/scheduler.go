@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	pscheduler "github.com/pingcap/tiflow/cdc/scheduler"
+	schedulerutil "github.com/pingcap/tiflow/cdc/scheduler/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -50,6 +51,15 @@ type scheduler interface {
 	// Rebalance is used to trigger manual workload rebalances.
 	Rebalance()
 
+	// SetExternalWorkloads sets the number of tables of other changefeeds
+	// on each capture, which are taken into account when choosing the
+	// captures for new tables.
+	SetExternalWorkloads(workloads map[model.CaptureID]int)
+
+	// SetCaptureFilter sets the filter that decides which captures a table
+	// can be placed on.
+	SetCaptureFilter(filter schedulerutil.CaptureFilter)
+
 	// Close closes the scheduler and releases resources.
 	Close(ctx context.Context)
 }
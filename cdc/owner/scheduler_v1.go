@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	schedulerv2 "github.com/pingcap/tiflow/cdc/scheduler"
+	schedulerutil "github.com/pingcap/tiflow/cdc/scheduler/util"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
@@ -56,7 +57,13 @@ type oldScheduler struct {
 	moveTableTargets      map[model.TableID]model.CaptureID
 	moveTableJobQueue     []*moveTableJob
 	needRebalanceNextTick bool
-	lastTickCaptureCount  int
+	lastTickCaptures      map[model.CaptureID]*model.CaptureInfo
+
+	// externalWorkloads is the number of tables of other changefeeds
+	// on each capture.
+	externalWorkloads map[model.CaptureID]int
+	// captureFilter decides which captures a table can be placed on.
+	captureFilter schedulerutil.CaptureFilter
 }
 
 func newSchedulerV1() scheduler {
@@ -97,7 +104,11 @@ func (s *oldScheduler) Tick(
 		return false, errors.Trace(err)
 	}
 	shouldUpdateState = shouldUpdateStateInMoveTable && shouldUpdateState
-	s.lastTickCaptureCount = len(captures)
+	// the caller may update the map in place, so a copy is kept
+	s.lastTickCaptures = make(map[model.CaptureID]*model.CaptureInfo, len(captures))
+	for captureID, info := range captures {
+		s.lastTickCaptures[captureID] = info
+	}
 	return shouldUpdateState, nil
 }
 
@@ -175,7 +186,7 @@ func (s *oldScheduler) dispatchToTargetCaptures(pendingJobs []*schedulerJob) {
 	workloads := make(map[model.CaptureID]uint64)
 
 	for captureID := range s.captures {
-		workloads[captureID] = 0
+		workloads[captureID] = uint64(s.externalWorkloads[captureID])
 		taskWorkload := s.state.Workloads[captureID]
 		if taskWorkload == nil {
 			continue
@@ -206,10 +217,14 @@ func (s *oldScheduler) dispatchToTargetCaptures(pendingJobs []*schedulerJob) {
 		}
 	}
 
-	getMinWorkloadCapture := func() model.CaptureID {
+	getMinWorkloadCapture := func(tableID model.TableID) model.CaptureID {
+		captures := schedulerutil.FilterCaptures(s.captureFilter, tableID, s.captures)
 		minCapture := ""
 		minWorkLoad := uint64(math.MaxUint64)
 		for captureID, workload := range workloads {
+			if _, ok := captures[captureID]; !ok {
+				continue
+			}
 			if workload < minWorkLoad {
 				minCapture = captureID
 				minWorkLoad = workload
@@ -226,7 +241,7 @@ func (s *oldScheduler) dispatchToTargetCaptures(pendingJobs []*schedulerJob) {
 		if pendingJob.TargetCapture != "" {
 			continue
 		}
-		minCapture := getMinWorkloadCapture()
+		minCapture := getMinWorkloadCapture(pendingJob.TableID)
 		pendingJob.TargetCapture = minCapture
 		workloads[minCapture] += 1
 	}
@@ -342,16 +357,18 @@ func (s *oldScheduler) shouldRebalance() bool {
 		s.needRebalanceNextTick = false
 		return true
 	}
-	if s.lastTickCaptureCount != len(s.captures) {
+	if schedulerutil.CapturesChanged(s.lastTickCaptures, s.captures) {
 		// a new capture online and no table distributed to the capture
-		// or some captures offline
+		// or some captures offline, the tables fell back to ineligible
+		// captures are also moved to the eligible ones that are up again
 		return true
 	}
 	// TODO periodic trigger rebalance
 	return false
 }
 
-// rebalanceByTableNum removes tables from captures replicating an above-average number of tables.
+// rebalanceByTableNum removes tables from captures replicating an above-average number of tables,
+// and the tables on the captures rejected by the capture filter.
 // the removed table will be dispatched again by syncTablesWithCurrentTables function
 func (s *oldScheduler) rebalanceByTableNum() (shouldUpdateState bool) {
 	totalTableNum := len(s.currentTables)
@@ -367,16 +384,25 @@ func (s *oldScheduler) rebalanceByTableNum() (shouldUpdateState bool) {
 
 	for captureID, taskStatus := range s.state.TaskStatuses {
 		tableNum2Remove := len(taskStatus.Tables) - upperLimitPerCapture
-		if tableNum2Remove <= 0 {
+		if tableNum2Remove <= 0 && s.captureFilter == nil {
 			continue
 		}
 
-		// here we pick `tableNum2Remove` tables to delete,
+		// here we pick the misplaced tables and `tableNum2Remove` tables to delete,
 		// and then the removed tables will be dispatched by `syncTablesWithCurrentTables` function in the next tick
 		for tableID := range taskStatus.Tables {
 			tableID := tableID
-			if tableNum2Remove <= 0 {
-				break
+			if !schedulerutil.IsMisplaced(s.captureFilter, tableID, captureID, s.captures) {
+				if tableNum2Remove <= 0 {
+					continue
+				}
+				if s.captureFilter != nil {
+					// the table would be dispatched to the same capture again
+					eligible := schedulerutil.FilterCaptures(s.captureFilter, tableID, s.captures)
+					if _, ok := eligible[captureID]; ok && len(eligible) == 1 {
+						continue
+					}
+				}
 			}
 			shouldUpdateState = false
 			s.state.PatchTaskStatus(captureID, func(status *model.TaskStatus) (*model.TaskStatus, bool, error) {
@@ -436,6 +462,14 @@ func (w *schedulerV1CompatWrapper) Rebalance() {
 	w.inner.Rebalance()
 }
 
+func (w *schedulerV1CompatWrapper) SetExternalWorkloads(workloads map[model.CaptureID]int) {
+	w.inner.externalWorkloads = workloads
+}
+
+func (w *schedulerV1CompatWrapper) SetCaptureFilter(filter schedulerutil.CaptureFilter) {
+	w.inner.captureFilter = filter
+}
+
 func (w *schedulerV1CompatWrapper) Close(_ cdcContext.Context) {
 	// No-op for the old scheduler
 }
@@ -193,6 +193,78 @@ func TestScheduleOneCapture(t *testing.T) {
 	})
 }
 
+func TestScheduleWithCaptureFilter(t *testing.T) {
+	s := &schedulerTester{}
+	s.reset(t)
+	captureID1 := "test-capture-1"
+	captureID2 := "test-capture-2"
+	s.addCapture(captureID1)
+	s.addCapture(captureID2)
+
+	// Tables with odd IDs can only be placed on capture 2.
+	s.scheduler.captureFilter = func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		return tableID%2 == 0 || capture.ID == captureID2
+	}
+	shouldUpdateState, err := s.scheduler.Tick(s.state, []model.TableID{1, 3, 5, 7}, s.captures)
+	require.Nil(t, err)
+	require.False(t, shouldUpdateState)
+	s.tester.MustApplyPatches()
+	require.Empty(t, s.state.TaskStatuses[captureID1].Tables)
+	require.Len(t, s.state.TaskStatuses[captureID2].Tables, 4)
+	s.finishTableOperation(captureID2, 1, 3, 5, 7)
+
+	// Even tables are balanced.
+	shouldUpdateState, err = s.scheduler.Tick(s.state, []model.TableID{1, 2, 3, 5, 7}, s.captures)
+	require.Nil(t, err)
+	require.False(t, shouldUpdateState)
+	s.tester.MustApplyPatches()
+	require.Equal(t, map[model.TableID]*model.TableReplicaInfo{2: {StartTs: 0}},
+		s.state.TaskStatuses[captureID1].Tables)
+	s.finishTableOperation(captureID1, 2)
+
+	// Tables are placed on any capture if no capture is eligible.
+	s.scheduler.captureFilter = func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		return false
+	}
+	shouldUpdateState, err = s.scheduler.Tick(s.state, []model.TableID{1, 2, 3, 5, 7, 9}, s.captures)
+	require.Nil(t, err)
+	require.False(t, shouldUpdateState)
+	s.tester.MustApplyPatches()
+	require.Contains(t, s.state.TaskStatuses[captureID1].Tables, model.TableID(9))
+}
+
+func TestScheduleMoveBackFallbackPlacement(t *testing.T) {
+	s := &schedulerTester{}
+	s.reset(t)
+	captureID1 := "test-capture-1"
+	captureID2 := "test-capture-2"
+	s.addCapture(captureID1)
+
+	// The table can only be placed on capture 2, it falls back to capture 1
+	// while capture 2 is down.
+	s.scheduler.captureFilter = func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		return capture.ID == captureID2
+	}
+	shouldUpdateState, err := s.scheduler.Tick(s.state, []model.TableID{1}, s.captures)
+	require.Nil(t, err)
+	require.False(t, shouldUpdateState)
+	s.tester.MustApplyPatches()
+	require.Contains(t, s.state.TaskStatuses[captureID1].Tables, model.TableID(1))
+	s.finishTableOperation(captureID1, 1)
+	shouldUpdateState, err = s.scheduler.Tick(s.state, []model.TableID{1}, s.captures)
+	require.Nil(t, err)
+	require.True(t, shouldUpdateState)
+	s.tester.MustApplyPatches()
+
+	// The table is moved back once capture 2 is up.
+	s.addCapture(captureID2)
+	shouldUpdateState, err = s.scheduler.Tick(s.state, []model.TableID{1}, s.captures)
+	require.Nil(t, err)
+	require.False(t, shouldUpdateState)
+	s.tester.MustApplyPatches()
+	require.True(t, s.state.TaskStatuses[captureID1].Operation[1].Delete)
+}
+
 func TestScheduleMoveTable(t *testing.T) {
 	s := &schedulerTester{}
 	s.reset(t)
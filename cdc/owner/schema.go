@@ -14,11 +14,17 @@
 package owner
 
 import (
+	"bytes"
+	"sort"
+	"strings"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/executor"
 	tidbkv "github.com/pingcap/tidb/kv"
 	timeta "github.com/pingcap/tidb/meta"
 	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -107,6 +113,15 @@ func (s *schemaWrap4Owner) AllTableNames() []model.TableName {
 	return names
 }
 
+// TableNameByPhysicalID returns the name of a table or a partition table.
+func (s *schemaWrap4Owner) TableNameByPhysicalID(tableID model.TableID) (model.TableName, bool) {
+	tblInfo, ok := s.schemaSnapshot.PhysicalTableByID(tableID)
+	if !ok {
+		return model.TableName{}, false
+	}
+	return tblInfo.TableName, true
+}
+
 func (s *schemaWrap4Owner) HandleDDL(job *timodel.Job) error {
 	if job.BinlogInfo.FinishedTS <= s.ddlHandledTs {
 		log.Warn("job finishTs is less than schema handleTs, discard invalid job",
@@ -150,6 +165,56 @@ func (s *schemaWrap4Owner) BuildDDLEvent(job *timodel.Job) (*model.DDLEvent, err
 	return ddlEvent, nil
 }
 
+// BuildSchemaBootstrapEvents builds a CREATE TABLE IF NOT EXISTS DDL event
+// for each table that is being replicated, the events are sorted by the
+// table names. The events are committed at ts, or at the commit ts of the
+// last handled DDL if it's greater, so that they never fall behind a DDL
+// that has been emitted. All the events share the same commit ts, the
+// consumers must not treat them as duplicates of each other.
+func (s *schemaWrap4Owner) BuildSchemaBootstrapEvents(ts model.Ts) ([]*model.DDLEvent, error) {
+	if ts < s.ddlHandledTs {
+		ts = s.ddlHandledTs
+	}
+	tables := s.schemaSnapshot.Tables()
+	events := make([]*model.DDLEvent, 0, len(tables))
+	for _, tblInfo := range tables {
+		if s.shouldIgnoreTable(tblInfo) {
+			continue
+		}
+		var buf bytes.Buffer
+		err := executor.ConstructResultOfShowCreateTable(mock.NewContext(), tblInfo.TableInfo, nil, &buf)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		columns := make([]*model.ColumnInfo, len(tblInfo.Columns))
+		for i, colInfo := range tblInfo.Columns {
+			columns[i] = new(model.ColumnInfo)
+			columns[i].FromTiColumnInfo(colInfo)
+		}
+		events = append(events, &model.DDLEvent{
+			StartTs:  ts,
+			CommitTs: ts,
+			TableInfo: &model.SimpleTableInfo{
+				Schema:     tblInfo.TableName.Schema,
+				Table:      tblInfo.TableName.Table,
+				TableID:    tblInfo.ID,
+				ColumnInfo: columns,
+			},
+			// The bootstrap can be published on demand when the tables
+			// already exist in the downstream.
+			Query: strings.Replace(buf.String(), "CREATE TABLE ", "CREATE TABLE IF NOT EXISTS ", 1),
+			Type:  timodel.ActionCreateTable,
+		})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].TableInfo.Schema != events[j].TableInfo.Schema {
+			return events[i].TableInfo.Schema < events[j].TableInfo.Schema
+		}
+		return events[i].TableInfo.Table < events[j].TableInfo.Table
+	})
+	return events, nil
+}
+
 func (s *schemaWrap4Owner) shouldIgnoreTable(t *model.TableInfo) bool {
 	schemaName := t.TableName.Schema
 	tableName := t.TableName.Table
@@ -164,3 +164,49 @@ func TestBuildDDLEvent(t *testing.T) {
 		},
 	})
 }
+
+func TestBuildSchemaBootstrapEvents(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	schema, err := newSchemaWrap4Owner(helper.Storage(), ver.Ver,
+		config.GetDefaultReplicaConfig(), dummyChangeFeedID)
+	require.Nil(t, err)
+	events, err := schema.BuildSchemaBootstrapEvents(ver.Ver)
+	require.Nil(t, err)
+	require.Len(t, events, 0)
+
+	job := helper.DDL2Job("create table test.t2(id int primary key, name varchar(16))")
+	tableIDT2 := job.BinlogInfo.TableInfo.ID
+	require.Nil(t, schema.HandleDDL(job))
+	require.Nil(t, schema.HandleDDL(helper.DDL2Job("create table test.t1(id int primary key)")))
+	// ineligible tables are skipped
+	job = helper.DDL2Job("create table test.t3(id int)")
+	require.Nil(t, schema.HandleDDL(job))
+	lastDDLTs := job.BinlogInfo.FinishedTS
+
+	events, err = schema.BuildSchemaBootstrapEvents(lastDDLTs + 1)
+	require.Nil(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "t1", events[0].TableInfo.Table)
+	event := events[1]
+	require.Equal(t, lastDDLTs+1, event.StartTs)
+	require.Equal(t, lastDDLTs+1, event.CommitTs)
+	require.Equal(t, timodel.ActionCreateTable, event.Type)
+	require.Equal(t, "test", event.TableInfo.Schema)
+	require.Equal(t, "t2", event.TableInfo.Table)
+	require.Equal(t, tableIDT2, event.TableInfo.TableID)
+	require.Equal(t, []*model.ColumnInfo{
+		{Name: "id", Type: mysql.TypeLong},
+		{Name: "name", Type: mysql.TypeVarchar},
+	}, event.TableInfo.ColumnInfo)
+	require.Regexp(t, "^CREATE TABLE IF NOT EXISTS `t2` \\(\n  `id` int\\(11\\) NOT NULL,\n  `name` varchar\\(16\\) DEFAULT NULL,", event.Query)
+
+	// The events never fall behind the last handled DDL.
+	events, err = schema.BuildSchemaBootstrapEvents(ver.Ver)
+	require.Nil(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, lastDDLTs, events[0].CommitTs)
+	require.Equal(t, lastDDLTs, events[1].CommitTs)
+}
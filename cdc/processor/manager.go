@@ -37,6 +37,7 @@ const (
 	commandTpUnknow commandTp = iota //nolint:varcheck,deadcode
 	commandTpClose
 	commandTpWriteDebugInfo
+	commandTpQueryTableStatuses
 	processorLogsWarnDuration = 1 * time.Second
 )
 
@@ -46,6 +47,12 @@ type command struct {
 	done    chan<- error
 }
 
+// tableStatusesQuery is the payload of commandTpQueryTableStatuses.
+type tableStatusesQuery struct {
+	changefeedID model.ChangeFeedID
+	statuses     []*model.TableStatus
+}
+
 // Manager is a manager of processor, which maintains the state and behavior of processors
 type Manager struct {
 	processors map[model.ChangeFeedID]*processor
@@ -174,6 +181,27 @@ func (m *Manager) WriteDebugInfo(
 	}
 }
 
+// QueryTableStatuses returns the replication status of the tables of
+// the changefeed in this capture.
+func (m *Manager) QueryTableStatuses(
+	ctx context.Context, changefeedID model.ChangeFeedID,
+) ([]*model.TableStatus, error) {
+	query := &tableStatusesQuery{changefeedID: changefeedID}
+	done := make(chan error, 1)
+	if err := m.sendCommand(ctx, commandTpQueryTableStatuses, query, done); err != nil {
+		return nil, errors.Trace(err)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, errors.Trace(ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return query.statuses, nil
+}
+
 // sendCommands sends command to manager.
 // `done` is closed upon command completion or sendCommand returns error.
 func (m *Manager) sendCommand(
@@ -208,6 +236,11 @@ func (m *Manager) handleCommand() error {
 	case commandTpWriteDebugInfo:
 		w := cmd.payload.(io.Writer)
 		m.writeDebugInfo(w)
+	case commandTpQueryTableStatuses:
+		query := cmd.payload.(*tableStatusesQuery)
+		if processor, exist := m.processors[query.changefeedID]; exist {
+			query.statuses = processor.tableStatuses()
+		}
 	default:
 		log.Warn("Unknown command in processor manager", zap.Any("command", cmd))
 	}
@@ -176,6 +176,15 @@ func TestDebugInfo(t *testing.T) {
 	s.manager.WriteDebugInfo(ctx, buf, doneM)
 	<-doneM
 	require.Greater(t, len(buf.String()), 0)
+
+	statuses, queryErr := s.manager.QueryTableStatuses(ctx, "test-changefeed")
+	require.Nil(t, queryErr)
+	require.Len(t, statuses, 1)
+	require.Equal(t, int64(1), statuses[0].TableID)
+	require.Equal(t, ctx.GlobalVars().CaptureInfo.ID, statuses[0].CaptureID)
+	statuses, queryErr = s.manager.QueryTableStatuses(ctx, "unknown-changefeed")
+	require.Nil(t, queryErr)
+	require.Len(t, statuses, 0)
 	s.manager.AsyncClose()
 	<-done
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/cyclic/mark"
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -173,6 +174,7 @@ func (n *cyclicMarkNode) sendNormalRowEventToNextNode(ctx pipeline.NodeContext,
 	}
 	for _, event := range events {
 		event.Row.ReplicaID = replicaID
+		event.Row.Trace = tracing.Stage(event.Row.Trace, tracing.StageCyclicMark)
 		ctx.SendToNextNode(pmessage.PolymorphicEventMessage(event))
 	}
 }
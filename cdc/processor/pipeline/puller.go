@@ -20,10 +20,13 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/puller"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"github.com/pingcap/tiflow/pkg/regionspan"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/pingcap/tiflow/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -71,8 +74,12 @@ func (n *pullerNode) start(ctx pipeline.NodeContext, wg *errgroup.Group, isActor
 	ctxC = util.PutTableInfoInCtx(ctxC, n.tableID, n.tableName)
 	ctxC = util.PutCaptureAddrInCtx(ctxC, ctx.GlobalVars().CaptureInfo.AdvertiseAddr)
 	ctxC = util.PutChangefeedIDInCtx(ctxC, ctx.ChangefeedVars().ID)
+	ctxC = util.PutChangefeedPriorityInCtx(ctxC, ctx.ChangefeedVars().Info.GetPriority())
 	ctxC = util.PutRoleInCtx(ctxC, util.RoleProcessor)
-	// NOTICE: always pull the old value internally
+	// NOTICE: always pull the old value internally, the minimal and noblob
+	// row images are also computed from the whole old value by the mounter.
+	// TiKV returns the old value as a whole row, so the row image modes
+	// can't reduce the cost of reading it.
 	// See also: https://github.com/pingcap/tiflow/issues/2301.
 	plr := puller.NewPuller(
 		ctxC,
@@ -83,8 +90,18 @@ func (n *pullerNode) start(ctx pipeline.NodeContext, wg *errgroup.Group, isActor
 		ctx.GlobalVars().PDClock,
 		n.changefeed,
 		n.replicaInfo.StartTs, n.tableSpan(ctx), true)
+	governor := ctx.GlobalVars().MemoryGovernor
+	cfPriority := ctx.ChangefeedVars().Info.GetPriority()
+	pullerQuota := governor.NewQuotaWithPriority(
+		n.changefeed, memquota.ComponentPuller, cfPriority)
+	kvClientQuota := governor.NewQuotaWithPriority(
+		n.changefeed, memquota.ComponentKVClient, cfPriority)
 	n.wg.Go(func() error {
-		ctx.Throw(errors.Trace(plr.Run(ctxC)))
+		defer pullerQuota.Close()
+		defer kvClientQuota.Close()
+		runCtx := memquota.PutQuotaInCtx(ctxC, pullerQuota)
+		runCtx = memquota.PutQuotaInCtx(runCtx, kvClientQuota)
+		ctx.Throw(errors.Trace(plr.Run(runCtx)))
 		return nil
 	})
 	n.wg.Go(func() error {
@@ -96,6 +113,8 @@ func (n *pullerNode) start(ctx pipeline.NodeContext, wg *errgroup.Group, isActor
 				if rawKV == nil {
 					continue
 				}
+				rawKV.Trace = tracing.Stage(rawKV.Trace, tracing.StagePuller,
+					attribute.Int64("table_id", n.tableID))
 				pEvent := model.NewPolymorphicEvent(rawKV)
 				if isActorMode {
 					sorter.handleRawEvent(ctx, pEvent)
@@ -27,6 +27,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -76,6 +77,9 @@ type sinkNode struct {
 	targetTs     model.Ts
 	barrierTs    model.Ts
 
+	// the number of row changed events that have been emitted to the sink
+	emittedEvents uint64
+
 	rowBuffer []*model.RowChangedEvent
 
 	flowController tableFlowController
@@ -102,6 +106,7 @@ func (n *sinkNode) ResolvedTs() model.Ts   { return atomic.LoadUint64(&n.resolve
 func (n *sinkNode) CheckpointTs() model.Ts { return atomic.LoadUint64(&n.checkpointTs) }
 func (n *sinkNode) BarrierTs() model.Ts    { return atomic.LoadUint64(&n.barrierTs) }
 func (n *sinkNode) Status() TableStatus    { return n.status.Load() }
+func (n *sinkNode) EmittedEvents() uint64  { return atomic.LoadUint64(&n.emittedEvents) }
 
 func (n *sinkNode) Init(ctx pipeline.NodeContext) error {
 	n.replicaConfig = ctx.ChangefeedVars().Info.Config
@@ -307,10 +312,14 @@ func (n *sinkNode) emitRowToSink(ctx context.Context) error {
 		time.Sleep(10 * time.Second)
 		panic("ProcessorSyncResolvedPreEmit")
 	})
+	for _, row := range n.rowBuffer {
+		row.Trace = tracing.Stage(row.Trace, tracing.StageSink)
+	}
 	err := n.sink.EmitRowChangedEvents(ctx, n.rowBuffer...)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	atomic.AddUint64(&n.emittedEvents, uint64(len(n.rowBuffer)))
 	n.clearBuffers()
 	return nil
 }
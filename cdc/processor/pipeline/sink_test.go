@@ -385,6 +385,7 @@ func TestManyTs(t *testing.T) {
 	sink.Reset()
 	require.Equal(t, uint64(2), node.ResolvedTs())
 	require.Equal(t, uint64(1), node.CheckpointTs())
+	require.Equal(t, uint64(2), node.EmittedEvents())
 
 	require.Nil(t, node.Receive(
 		pipeline.MockNodeContext4Test(ctx, pmessage.BarrierMessage(5), nil)))
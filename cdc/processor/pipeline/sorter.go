@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sorter"
+	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb"
 	"github.com/pingcap/tiflow/cdc/sorter/memory"
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
@@ -32,8 +33,11 @@ import (
 	"github.com/pingcap/tiflow/pkg/actor/message"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/pingcap/tiflow/pkg/tracing"
+	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -59,6 +63,12 @@ type sorterNode struct {
 	// The latest resolved ts that sorter has received.
 	resolvedTs model.Ts
 
+	// The latest resolved ts that sorter has outputted.
+	outputResolvedTs model.Ts
+
+	// The number of row changed events that sorter has received.
+	receivedEvents uint64
+
 	// The latest barrier ts that sorter has received.
 	barrierTs model.Ts
 
@@ -74,13 +84,14 @@ func newSorterNode(
 	replConfig *config.ReplicaConfig,
 ) *sorterNode {
 	return &sorterNode{
-		tableName:      tableName,
-		tableID:        tableID,
-		flowController: flowController,
-		mounter:        mounter,
-		resolvedTs:     startTs,
-		barrierTs:      startTs,
-		replConfig:     replConfig,
+		tableName:        tableName,
+		tableID:          tableID,
+		flowController:   flowController,
+		mounter:          mounter,
+		resolvedTs:       startTs,
+		outputResolvedTs: startTs,
+		barrierTs:        startTs,
+		replConfig:       replConfig,
 	}
 }
 
@@ -94,31 +105,52 @@ func createSorter(ctx pipeline.NodeContext, tableName string, tableID model.Tabl
 	switch sortEngine {
 	case model.SortInMemory:
 		return memory.NewEntrySorter(), nil
-	case model.SortUnified, model.SortInFile /* `file` becomes an alias of `unified` for backward compatibility */ :
+	case model.SortUnified, model.SortInDB, model.SortInFile /* `file` becomes an alias of `unified` for backward compatibility */ :
 		if sortEngine == model.SortInFile {
 			log.Warn("File sorter is obsolete and replaced by unified sorter. Please revise your changefeed settings",
 				zap.String("changefeed", ctx.ChangefeedVars().ID), zap.String("tableName", tableName))
 		}
 
-		if config.GetGlobalServerConfig().Debug.EnableDBSorter {
+		// Changefeeds use the compression of the server by default.
+		compression := config.GetGlobalServerConfig().Sorter.Compression
+		if cfg := ctx.ChangefeedVars().Info.Config; cfg != nil && cfg.SortCompression != "" {
+			compression = cfg.SortCompression
+		}
+		codec, err := encoding.ParseCodec(compression)
+		if err != nil {
+			return nil, err
+		}
+
+		// Disk based sorters are chosen by the server config, changefeeds
+		// can not run both db sorter and unified sorter on the same server.
+		if config.GetGlobalServerConfig().Sorter.Engine == config.SorterEngineDB {
 			startTs := ctx.ChangefeedVars().Info.StartTs
 			ssystem := ctx.GlobalVars().SorterSystem
 			dbActorID := ssystem.DBActorID(uint64(tableID))
 			compactScheduler := ctx.GlobalVars().SorterSystem.CompactScheduler()
+			// Actors of the sorter are polled according to the priority
+			// of the changefeed.
+			sorterCtx := util.PutChangefeedPriorityInCtx(
+				ctx, ctx.ChangefeedVars().Info.GetPriority())
 			levelSorter, err := leveldb.NewSorter(
-				ctx, tableID, startTs, ssystem.DBRouter, dbActorID,
+				sorterCtx, tableID, startTs, ssystem.DBRouter, dbActorID,
 				ssystem.WriterSystem, ssystem.WriterRouter,
 				ssystem.ReaderSystem, ssystem.ReaderRouter,
-				compactScheduler, config.GetGlobalServerConfig().Debug.DB)
+				compactScheduler, config.GetGlobalServerConfig().Debug.DB, codec)
 			if err != nil {
 				return nil, err
 			}
 			return levelSorter, nil
 		}
+		if sortEngine == model.SortInDB {
+			log.Warn("db sorter is disabled by server config, use unified sorter instead",
+				zap.String("changefeed", ctx.ChangefeedVars().ID), zap.String("tableName", tableName))
+		}
 		// Sorter dir has been set and checked when server starts.
 		// See https://github.com/pingcap/tiflow/blob/9dad09/cdc/server.go#L275
 		sortDir := config.GetGlobalServerConfig().Sorter.SortDir
-		unifiedSorter, err := unified.NewUnifiedSorter(sortDir, ctx.ChangefeedVars().ID, tableName, tableID)
+		unifiedSorter, err := unified.NewUnifiedSorter(
+			sortDir, ctx.ChangefeedVars().ID, tableName, tableID, codec)
 		if err != nil {
 			return nil, err
 		}
@@ -145,11 +177,24 @@ func (n *sorterNode) start(
 	failpoint.Inject("ProcessorAddTableError", func() {
 		failpoint.Return(errors.New("processor add table injected error"))
 	})
+	// Events sorted in memory are accounted by the quota, the unified sorter
+	// spills events to disk if the memory is tight.
+	cfPriority := ctx.ChangefeedVars().Info.GetPriority()
+	sorterQuota := ctx.GlobalVars().MemoryGovernor.NewQuotaWithPriority(
+		ctx.ChangefeedVars().ID, memquota.ComponentSorter, cfPriority)
 	n.eg.Go(func() error {
-		ctx.Throw(errors.Trace(eventSorter.Run(stdCtx)))
+		defer sorterQuota.Close()
+		runCtx := memquota.PutQuotaInCtx(stdCtx, sorterQuota)
+		runCtx = util.PutChangefeedPriorityInCtx(runCtx, cfPriority)
+		ctx.Throw(errors.Trace(eventSorter.Run(runCtx)))
 		return nil
 	})
+	// The events being mounted are accounted by the mounter quota until they
+	// are consumed by the flow controller, which accounts them to the sink.
+	mounterQuota := ctx.GlobalVars().MemoryGovernor.NewQuotaWithPriority(
+		ctx.ChangefeedVars().ID, memquota.ComponentMounter, cfPriority)
 	n.eg.Go(func() error {
+		defer mounterQuota.Close()
 		lastSentResolvedTs := uint64(0)
 		lastSendResolvedTsTime := time.Now() // the time at which we last sent a resolved-ts.
 		lastCRTs := uint64(0)                // the commit-ts of the last row changed we sent.
@@ -177,6 +222,11 @@ func (n *sorterNode) start(
 					log.Panic("unexpected empty msg", zap.Reflect("msg", msg))
 				}
 				if msg.RawKV.OpType != model.OpTypeResolved {
+					msg.RawKV.Trace = tracing.Stage(msg.RawKV.Trace, tracing.StageSorter)
+					// Only one event is mounted at a time, blocking here can't
+					// reclaim any memory.
+					mountingSize := uint64(msg.RawKV.ApproximateDataSize())
+					mounterQuota.ForceReserve(mountingSize)
 					err := n.mounter.DecodeEvent(ctx, msg)
 					if err != nil {
 						return errors.Trace(err)
@@ -214,6 +264,7 @@ func (n *sorterNode) start(
 						}
 						return nil
 					})
+					mounterQuota.Release(mountingSize)
 					if err != nil {
 						if cerror.ErrFlowControllerAborted.Equal(err) {
 							log.Info("flow control cancelled for table",
@@ -236,6 +287,7 @@ func (n *sorterNode) start(
 					}
 					lastSentResolvedTs = msg.CRTs
 					lastSendResolvedTsTime = time.Now()
+					atomic.StoreUint64(&n.outputResolvedTs, msg.CRTs)
 				}
 				ctx.SendToNextNode(pmessage.PolymorphicEventMessage(msg))
 			}
@@ -279,6 +331,8 @@ func (n *sorterNode) handleRawEvent(ctx context.Context, event *model.Polymorphi
 			//       resolved ts.
 			event = model.NewResolvedPolymorphicEvent(0, n.BarrierTs())
 		}
+	} else if rawKV != nil {
+		atomic.AddUint64(&n.receivedEvents, 1)
 	}
 	n.sorter.AddEntry(ctx, event)
 }
@@ -323,6 +377,16 @@ func (n *sorterNode) ResolvedTs() model.Ts {
 	return atomic.LoadUint64(&n.resolvedTs)
 }
 
+// OutputResolvedTs returns the latest resolved ts that sorter has outputted
+func (n *sorterNode) OutputResolvedTs() model.Ts {
+	return atomic.LoadUint64(&n.outputResolvedTs)
+}
+
+// ReceivedEvents returns the number of row changed events that sorter has received
+func (n *sorterNode) ReceivedEvents() uint64 {
+	return atomic.LoadUint64(&n.receivedEvents)
+}
+
 // BarrierTs returns the sorter barrierTs
 func (n *sorterNode) BarrierTs() model.Ts {
 	return atomic.LoadUint64(&n.barrierTs)
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sorter"
+	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/cdc/sorter/memory"
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -35,13 +36,13 @@ func TestUnifiedSorterFileLockConflict(t *testing.T) {
 
 	// GlobalServerConfig overrides dir parameter in NewUnifiedSorter.
 	config.GetGlobalServerConfig().Sorter.SortDir = dir
-	config.GetGlobalServerConfig().Debug.EnableDBSorter = false
+	config.GetGlobalServerConfig().Sorter.Engine = config.SorterEngineUnified
 	defer func() {
 		// Restore default server config.
-		config.GetGlobalServerConfig().Debug.EnableDBSorter = true
+		config.GetGlobalServerConfig().Sorter.Engine = config.SorterEngineDB
 	}()
 
-	_, err := unified.NewUnifiedSorter(dir, "test-cf", "test", 0)
+	_, err := unified.NewUnifiedSorter(dir, "test-cf", "test", 0, encoding.CodecNone)
 	require.Nil(t, err)
 
 	unified.ResetGlobalPoolWithoutCleanup()
@@ -68,6 +69,19 @@ func TestSorterResolvedTs(t *testing.T) {
 	err := sn.Receive(nctx)
 	require.Nil(t, err)
 	require.EqualValues(t, 2, sn.ResolvedTs())
+	require.EqualValues(t, 0, sn.ReceivedEvents())
+
+	nctx = pipeline.NewNodeContext(
+		cdcContext.NewContext(context.Background(), nil),
+		pmessage.PolymorphicEventMessage(model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType: model.OpTypePut, CRTs: 3,
+		})),
+		nil,
+	)
+	err = sn.Receive(nctx)
+	require.Nil(t, err)
+	require.EqualValues(t, 2, sn.ResolvedTs())
+	require.EqualValues(t, 1, sn.ReceivedEvents())
 }
 
 type checkSorter struct {
@@ -26,6 +26,7 @@ import (
 	serverConfig "github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"go.uber.org/zap"
@@ -55,6 +56,8 @@ type TablePipeline interface {
 	Workload() model.WorkloadInfo
 	// Status returns the status of this table pipeline
 	Status() TableStatus
+	// Stats returns the replication statistics of this table pipeline
+	Stats() TableStats
 	// Cancel stops this table pipeline immediately and destroy all resources created by this table pipeline
 	Cancel()
 	// Wait waits for table pipeline destroyed
@@ -75,6 +78,32 @@ type tablePipelineImpl struct {
 	replConfig *serverConfig.ReplicaConfig
 }
 
+// TableStats is the replication statistics of a table pipeline.
+type TableStats struct {
+	// PullerResolvedTs is the resolved ts that sorter has received from puller.
+	PullerResolvedTs model.Ts
+	// SorterResolvedTs is the resolved ts that sorter has outputted.
+	SorterResolvedTs model.Ts
+	// SinkResolvedTs is the resolved ts that sink has received.
+	SinkResolvedTs model.Ts
+	CheckpointTs   model.Ts
+	// ReceivedEvents is the number of row changed events received by sorter.
+	ReceivedEvents uint64
+	// EmittedEvents is the number of row changed events emitted to sink.
+	EmittedEvents uint64
+}
+
+func newTableStats(sorter *sorterNode, sink *sinkNode) TableStats {
+	return TableStats{
+		PullerResolvedTs: sorter.ResolvedTs(),
+		SorterResolvedTs: sorter.OutputResolvedTs(),
+		SinkResolvedTs:   sink.ResolvedTs(),
+		CheckpointTs:     sink.CheckpointTs(),
+		ReceivedEvents:   sorter.ReceivedEvents(),
+		EmittedEvents:    sink.EmittedEvents(),
+	}
+}
+
 // TODO find a better name or avoid using an interface
 // We use an interface here for ease in unit testing.
 type tableFlowController interface {
@@ -141,6 +170,11 @@ func (t *tablePipelineImpl) Status() TableStatus {
 	return t.sinkNode.Status()
 }
 
+// Stats returns the replication statistics of this table pipeline
+func (t *tablePipelineImpl) Stats() TableStats {
+	return newTableStats(t.sorterNode, t.sinkNode)
+}
+
 // ID returns the ID of source table and mark table
 func (t *tablePipelineImpl) ID() (tableID, markTableID int64) {
 	return t.tableID, t.markTableID
@@ -196,7 +230,10 @@ func NewTablePipeline(ctx cdcContext.Context,
 		zap.String("tableName", tableName),
 		zap.Int64("tableID", tableID),
 		zap.Uint64("quota", perTableMemoryQuota))
-	flowController := flowcontrol.NewTableFlowController(perTableMemoryQuota)
+	flowController := flowcontrol.NewTableFlowController(perTableMemoryQuota,
+		ctx.GlobalVars().MemoryGovernor.NewQuotaWithPriority(
+			ctx.ChangefeedVars().ID, memquota.ComponentSink,
+			ctx.ChangefeedVars().Info.GetPriority()))
 	config := ctx.ChangefeedVars().Info.Config
 	cyclicEnabled := config.Cyclic != nil && config.Cyclic.IsEnabled()
 	runnerSize := defaultRunnersSize
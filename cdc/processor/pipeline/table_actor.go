@@ -31,6 +31,7 @@ import (
 	serverConfig "github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -147,7 +148,8 @@ func NewTableActor(cdcCtx cdcContext.Context,
 		table.stop(err)
 		return nil, errors.Trace(err)
 	}
-	err := globalVars.TableActorSystem.System().Spawn(mb, table)
+	err := globalVars.TableActorSystem.System().SpawnWithPriority(
+		mb, table, changefeedVars.Info.GetPriority())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -263,7 +265,9 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 		zap.String("tableName", t.tableName),
 		zap.Uint64("quota", t.memoryQuota))
 
-	flowController := flowcontrol.NewTableFlowController(t.memoryQuota)
+	flowController := flowcontrol.NewTableFlowController(t.memoryQuota,
+		t.globalVars.MemoryGovernor.NewQuotaWithPriority(t.changefeedID,
+			memquota.ComponentSink, t.changefeedVars.Info.GetPriority()))
 	sorterNode := newSorterNode(t.tableName, t.tableID,
 		t.replicaInfo.StartTs, flowController,
 		t.mounter, t.replicaConfig,
@@ -465,6 +469,11 @@ func (t *tableActor) Status() TableStatus {
 	return t.sinkNode.Status()
 }
 
+// Stats returns the replication statistics of this table pipeline
+func (t *tableActor) Stats() TableStats {
+	return newTableStats(t.sortNode, t.sinkNode)
+}
+
 // ID returns the ID of source table and mark table
 func (t *tableActor) ID() (tableID, markTableID int64) {
 	return t.tableID, t.markTableID
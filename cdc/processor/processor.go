@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/cdc/sorter/memory"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
@@ -463,10 +464,15 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 	stdCtx := util.PutChangefeedIDInCtx(ctx, p.changefeed.ID)
 	stdCtx = util.PutRoleInCtx(stdCtx, util.RoleProcessor)
 
+	rowImage, err := entry.NewRowImageSelector(p.changefeed.Info.Config)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	p.mounter = entry.NewMounter(p.schemaStorage,
 		p.changefeedID,
 		util.TimezoneFromCtx(ctx),
-		p.changefeed.Info.Config.EnableOldValue)
+		p.changefeed.Info.Config.EnableOldValue,
+		rowImage)
 
 	opts := make(map[string]string, len(p.changefeed.Info.Opts)+2)
 	for k, v := range p.changefeed.Info.Opts {
@@ -675,6 +681,7 @@ func (p *processor) createAndDriveSchemaStorage(ctx cdcContext.Context) (entry.S
 	checkpointTs := p.changefeed.Info.GetCheckpointTs(p.changefeed.Status)
 	stdCtx := util.PutTableInfoInCtx(ctx, -1, puller.DDLPullerTableName)
 	stdCtx = util.PutChangefeedIDInCtx(stdCtx, ctx.ChangefeedVars().ID)
+	stdCtx = util.PutChangefeedPriorityInCtx(stdCtx, ctx.ChangefeedVars().Info.GetPriority())
 	stdCtx = util.PutRoleInCtx(stdCtx, util.RoleProcessor)
 	ddlPuller := puller.NewPuller(
 		stdCtx,
@@ -1147,10 +1154,32 @@ func (p *processor) Close() error {
 	syncTableNumGauge.DeleteLabelValues(p.changefeedID)
 	processorErrorCounter.DeleteLabelValues(p.changefeedID)
 	processorSchemaStorageGcTsGauge.DeleteLabelValues(p.changefeedID)
+	encoding.DeleteMetrics(p.changefeedID)
 
 	return nil
 }
 
+// tableStatuses returns the replication status of the tables in this processor.
+func (p *processor) tableStatuses() []*model.TableStatus {
+	statuses := make([]*model.TableStatus, 0, len(p.tables))
+	for tableID, table := range p.tables {
+		stats := table.Stats()
+		statuses = append(statuses, &model.TableStatus{
+			TableID:          tableID,
+			TableName:        table.Name(),
+			CaptureID:        p.captureInfo.ID,
+			State:            table.Status().String(),
+			PullerResolvedTs: stats.PullerResolvedTs,
+			SorterResolvedTs: stats.SorterResolvedTs,
+			SinkResolvedTs:   stats.SinkResolvedTs,
+			CheckpointTs:     stats.CheckpointTs,
+			ReceivedEvents:   stats.ReceivedEvents,
+			EmittedEvents:    stats.EmittedEvents,
+		})
+	}
+	return statuses
+}
+
 // WriteDebugInfo write the debug info to Writer
 func (p *processor) WriteDebugInfo(w io.Writer) {
 	fmt.Fprintf(w, "%+v\n", *p.changefeed)
@@ -169,6 +169,15 @@ func (m *mockTablePipeline) Status() tablepipeline.TableStatus {
 	return m.status
 }
 
+func (m *mockTablePipeline) Stats() tablepipeline.TableStats {
+	return tablepipeline.TableStats{
+		PullerResolvedTs: m.resolvedTs,
+		SorterResolvedTs: m.resolvedTs,
+		SinkResolvedTs:   m.resolvedTs,
+		CheckpointTs:     m.checkpointTs,
+	}
+}
+
 func (m *mockTablePipeline) Cancel() {
 	if m.canceled {
 		log.Panic("cancel a canceled table pipeline")
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/puller/frontier"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/pdtime"
 	"github.com/pingcap/tiflow/pkg/regionspan"
 	"github.com/pingcap/tiflow/pkg/txnutil"
@@ -41,6 +42,10 @@ const DDLPullerTableName = "DDL_PULLER"
 const (
 	defaultPullerEventChanSize  = 128
 	defaultPullerOutputChanSize = 128
+	// pullerThrottleTimeout bounds the time a puller is throttled, memory of
+	// sorter is freed only if resolved ts advances, so a puller must not be
+	// blocked forever.
+	pullerThrottleTimeout = time.Second
 )
 
 // Puller pull data from tikv and push changes into a buffer.
@@ -141,6 +146,7 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 		txnCollectCounter.DeleteLabelValues(changefeedID, "resolved")
 	}()
 
+	quota := memquota.QuotaFromCtx(ctx, memquota.ComponentPuller)
 	lastResolvedTs := p.checkpointTs
 	g.Go(func() error {
 		metricsTicker := time.NewTicker(15 * time.Second)
@@ -185,6 +191,10 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 
 			if e.Val != nil {
 				metricTxnCollectCounterKv.Inc()
+				// Slow down pulling if the memory of the capture is tight.
+				if err := quota.Throttle(ctx, pullerThrottleTimeout); err != nil {
+					return errors.Trace(err)
+				}
 				if err := output(e.Val); err != nil {
 					return errors.Trace(err)
 				}
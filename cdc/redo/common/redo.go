@@ -21,6 +21,15 @@ const (
 	MinSectorSize = 512
 )
 
+const (
+	// EncryptedLogMagic is the first 8 bytes of an encrypted log file, it is
+	// followed by the encryption key id in 8 bytes. Since the magic is larger
+	// than any valid frame length, it is not ambiguous with plaintext files.
+	EncryptedLogMagic uint64 = 0x7469636463656e63
+	// EncryptedLogHeaderSize is the size of the header of an encrypted log file.
+	EncryptedLogHeaderSize = 16
+)
+
 const (
 	// TmpEXT is the file ext of log file before safely wrote to disk
 	TmpEXT = ".tmp"
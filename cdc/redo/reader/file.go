@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -74,6 +75,10 @@ type reader struct {
 	closer   io.Closer
 	// lastValidOff file offset following the last valid decoded record
 	lastValidOff int64
+
+	headerRead bool
+	// cipher is not nil if the file is encrypted.
+	cipher *encrypt.Cipher
 }
 
 func newReader(ctx context.Context, cfg *readerConfig) ([]fileReader, error) {
@@ -372,6 +377,13 @@ func (r *reader) Read(redoLog *model.RedoLog) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if !r.headerRead {
+		if err := r.readHeader(); err != nil {
+			return err
+		}
+		r.headerRead = true
+	}
+
 	lenField, err := readInt64(r.br)
 	if err != nil {
 		if err == io.EOF {
@@ -393,7 +405,18 @@ func (r *reader) Read(redoLog *model.RedoLog) error {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
 	}
 
-	_, err = redoLog.UnmarshalMsg(data[:recBytes])
+	payload := data[:recBytes]
+	if r.cipher != nil {
+		payload, err = r.cipher.Open(nil, payload)
+		if err != nil {
+			if r.isTornEntry(data) {
+				return io.EOF
+			}
+			return errors.Trace(err)
+		}
+	}
+
+	_, err = redoLog.UnmarshalMsg(payload)
 	if err != nil {
 		if r.isTornEntry(data) {
 			// just return io.EOF, since if torn write it is the last redoLog entry
@@ -407,6 +430,28 @@ func (r *reader) Read(redoLog *model.RedoLog) error {
 	return nil
 }
 
+// readHeader reads the header of encrypted log files, plaintext log files
+// written by older versions have no header.
+func (r *reader) readHeader() error {
+	magic, err := r.br.Peek(8)
+	if err != nil || binary.LittleEndian.Uint64(magic) != common.EncryptedLogMagic {
+		// Empty or plaintext file.
+		return nil
+	}
+	header := make([]byte, common.EncryptedLogHeaderSize)
+	if _, err := io.ReadFull(r.br, header); err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	keyID := uint32(binary.LittleEndian.Uint64(header[8:]))
+	cipher := encrypt.GetGlobalCipher()
+	if err := encrypt.VerifyKeyID(cipher, keyID); err != nil {
+		return errors.Annotatef(err, "read redo log %s", r.fileName)
+	}
+	r.cipher = cipher
+	r.lastValidOff += common.EncryptedLogHeaderSize
+	return nil
+}
+
 func readInt64(r io.Reader) (int64, error) {
 	var n int64
 	err := binary.Read(r, binary.LittleEndian, &n)
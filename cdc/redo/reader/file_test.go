@@ -15,6 +15,7 @@ package reader
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -23,9 +24,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/encrypt"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/leakutil"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
@@ -97,6 +101,67 @@ func TestReaderRead(t *testing.T) {
 	time.Sleep(1001 * time.Millisecond)
 }
 
+func TestReaderReadEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	cipher, err := encrypt.NewCipher(bytes.Repeat([]byte{1}, 16))
+	require.Nil(t, err)
+	encrypt.StoreGlobalCipher(cipher)
+	defer encrypt.StoreGlobalCipher(nil)
+
+	cfg := &writer.FileWriterConfig{
+		MaxLogSize:   100000,
+		Dir:          dir,
+		ChangeFeedID: "test-cf",
+		CaptureID:    "cp",
+		FileType:     common.DefaultRowLogFileType,
+		CreateTime:   time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := writer.NewWriter(ctx, cfg)
+	require.Nil(t, err)
+	log := &model.RedoLog{
+		RedoRow: &model.RedoRowChangedEvent{Row: &model.RowChangedEvent{
+			CommitTs: 1123,
+			Table:    &model.TableName{Schema: "sensitive-schema", Table: "t"},
+		}},
+	}
+	data, err := log.MarshalMsg(nil)
+	require.Nil(t, err)
+	w.AdvanceTs(11)
+	_, err = w.Write(data)
+	require.Nil(t, err)
+	require.Nil(t, w.Close())
+	fileName := fmt.Sprintf("%s_%s_%d_%s_%d%s", cfg.CaptureID, cfg.ChangeFeedID, cfg.CreateTime.Unix(), cfg.FileType, 11, common.LogEXT)
+	content, err := os.ReadFile(filepath.Join(dir, fileName))
+	require.Nil(t, err)
+	require.False(t, bytes.Contains(content, []byte("sensitive-schema")))
+
+	r, err := newReader(ctx, &readerConfig{
+		dir:      dir,
+		startTs:  1,
+		endTs:    12,
+		fileType: common.DefaultRowLogFileType,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(r))
+	log = &model.RedoLog{}
+	require.Nil(t, r[0].Read(log))
+	require.EqualValues(t, 1123, log.RedoRow.Row.CommitTs)
+	require.Equal(t, "sensitive-schema", log.RedoRow.Row.Table.Schema)
+	require.Equal(t, io.EOF, r[0].Read(log))
+	require.Nil(t, r[0].Close())
+
+	// Encrypted files can not be read without the key.
+	encrypt.StoreGlobalCipher(nil)
+	file, err := openReadFile(filepath.Join(dir, fileName))
+	require.Nil(t, err)
+	_, err = readFile(file)
+	require.True(t, cerror.ErrEncryptionKeyMissing.Equal(errors.Cause(err)))
+	time.Sleep(1001 * time.Millisecond)
+}
+
 func TestReaderOpenSelectedFiles(t *testing.T) {
 	dir, err := ioutil.TempDir("", "redo-openSelectedFiles")
 	require.Nil(t, err)
@@ -36,6 +36,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
@@ -121,6 +122,8 @@ type Writer struct {
 	bw            *pioutil.PageWriter
 	uint64buf     []byte
 	storage       storage.ExternalStorage
+	// cipher is not nil if spill-to-disk encryption is enabled.
+	cipher *encrypt.Cipher
 	sync.RWMutex
 
 	metricFsyncDuration    prometheus.Observer
@@ -159,6 +162,7 @@ func NewWriter(ctx context.Context, cfg *FileWriterConfig, opts ...Option) (*Wri
 		op:        op,
 		uint64buf: make([]byte, 8),
 		storage:   s3storage,
+		cipher:    encrypt.GetGlobalCipher(),
 
 		metricFsyncDuration:    redoFsyncDurationHistogram.WithLabelValues(cfg.ChangeFeedID),
 		metricFlushAllDuration: redoFlushAllDurationHistogram.WithLabelValues(cfg.ChangeFeedID),
@@ -201,6 +205,9 @@ func (w *Writer) Write(rawData []byte) (int, error) {
 	w.Lock()
 	defer w.Unlock()
 
+	if w.cipher != nil {
+		rawData = w.cipher.Seal(nil, rawData)
+	}
 	writeLen := int64(len(rawData))
 	if writeLen > w.cfg.MaxLogSize {
 		return 0, cerror.ErrFileSizeExceed.GenWithStackByArgs(writeLen, w.cfg.MaxLogSize)
@@ -363,9 +370,47 @@ func (w *Writer) openNew() error {
 	if err != nil {
 		return err
 	}
+	return w.writeFileHeader()
+}
+
+// writeFileHeader writes the header of encrypted log files, plaintext log
+// files have no header.
+func (w *Writer) writeFileHeader() error {
+	if w.cipher == nil {
+		return nil
+	}
+	if err := w.writeUint64(common.EncryptedLogMagic, w.uint64buf); err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	if err := w.writeUint64(uint64(w.cipher.KeyID()), w.uint64buf); err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	w.size += common.EncryptedLogHeaderSize
 	return nil
 }
 
+// isFileHeaderMatched checks whether an existing log file is encrypted by
+// the current key, or is in plaintext if encryption is disabled.
+func (w *Writer) isFileHeaderMatched(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	header := make([]byte, common.EncryptedLogHeaderSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	encrypted := n >= 8 && binary.LittleEndian.Uint64(header) == common.EncryptedLogMagic
+	if w.cipher == nil {
+		return !encrypted, nil
+	}
+	return encrypted && n == len(header) &&
+		binary.LittleEndian.Uint64(header[8:]) == uint64(w.cipher.KeyID()), nil
+}
+
 func (w *Writer) openOrNew(writeLen int) error {
 	path := w.filePath()
 	info, err := os.Stat(path)
@@ -379,6 +424,14 @@ func (w *Writer) openOrNew(writeLen int) error {
 	if info.Size()+int64(writeLen) >= w.cfg.MaxLogSize {
 		return w.rotate()
 	}
+	matched, err := w.isFileHeaderMatched(path)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		// Do not mix encrypted and plaintext data in one file.
+		return w.rotate()
+	}
 
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, common.DefaultFileMode)
 	if err != nil {
@@ -14,7 +14,9 @@
 package writer
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io/ioutil"
 	"net/url"
@@ -27,6 +29,7 @@ import (
 	"github.com/pingcap/errors"
 	mockstorage "github.com/pingcap/tidb/br/pkg/mock/storage"
 	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	"github.com/pingcap/tiflow/pkg/leakutil"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/atomic"
@@ -136,6 +139,50 @@ func TestWriterWrite(t *testing.T) {
 	require.False(t, w1.IsRunning())
 }
 
+func TestWriterWriteEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	cipher, err := encrypt.NewCipher(bytes.Repeat([]byte{1}, 16))
+	require.Nil(t, err)
+
+	w := &Writer{
+		cfg: &FileWriterConfig{
+			MaxLogSize:   1000,
+			Dir:          dir,
+			ChangeFeedID: "test-cf",
+			CaptureID:    "cp",
+			FileType:     common.DefaultRowLogFileType,
+			CreateTime:   time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+		},
+		uint64buf:              make([]byte, 8),
+		running:                *atomic.NewBool(true),
+		cipher:                 cipher,
+		metricWriteBytes:       redoWriteBytesGauge.WithLabelValues("test-cf"),
+		metricFsyncDuration:    redoFsyncDurationHistogram.WithLabelValues("test-cf"),
+		metricFlushAllDuration: redoFlushAllDurationHistogram.WithLabelValues("test-cf"),
+	}
+	w.eventCommitTS.Store(1)
+	_, err = w.Write([]byte("sensitive"))
+	require.Nil(t, err)
+	require.Nil(t, w.Close())
+
+	fileName := fmt.Sprintf("%s_%s_%d_%s_%d%s", w.cfg.CaptureID, w.cfg.ChangeFeedID, w.cfg.CreateTime.Unix(), w.cfg.FileType, 1, common.LogEXT)
+	path := filepath.Join(w.cfg.Dir, fileName)
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.False(t, bytes.Contains(content, []byte("sensitive")))
+	require.Equal(t, common.EncryptedLogMagic, binary.LittleEndian.Uint64(content))
+	require.EqualValues(t, cipher.KeyID(), binary.LittleEndian.Uint64(content[8:]))
+
+	// Encrypted and plaintext data must not be mixed in one file.
+	matched, err := w.isFileHeaderMatched(path)
+	require.Nil(t, err)
+	require.True(t, matched)
+	w.cipher = nil
+	matched, err = w.isFileHeaderMatched(path)
+	require.Nil(t, err)
+	require.False(t, matched)
+}
+
 func TestWriterGC(t *testing.T) {
 	dir, err := ioutil.TempDir("", "redo-GC")
 	require.Nil(t, err)
@@ -33,9 +33,12 @@ import (
 type balancer interface {
 	// FindVictims returns a set of possible victim tables.
 	// Removing these tables will make the workload more balanced.
+	// The tables rejected by the filter on their captures are always
+	// victims, and the filter can be nil.
 	FindVictims(
 		tables *util.TableSet,
 		captures map[model.CaptureID]*model.CaptureInfo,
+		filter util.CaptureFilter,
 	) (tablesToRemove []*util.TableRecord)
 
 	// FindTarget returns a target capture to add a table to.
@@ -43,6 +46,10 @@ type balancer interface {
 		tables *util.TableSet,
 		captures map[model.CaptureID]*model.CaptureInfo,
 	) (minLoadCapture model.CaptureID, ok bool)
+
+	// SetExternalWorkloads sets the workloads of other changefeeds on
+	// each capture, which are added to the workloads in FindTarget.
+	SetExternalWorkloads(workloads map[model.CaptureID]int)
 }
 
 // tableNumberBalancer implements a balance strategy based on the
@@ -54,6 +61,10 @@ type tableNumberBalancer struct {
 
 	// random is used to provide some randomness in the schedule.
 	random *rand.Rand
+
+	// externalWorkloads is the number of tables of other changefeeds
+	// on each capture.
+	externalWorkloads map[model.CaptureID]int
 }
 
 func newTableNumberRebalancer(logger *zap.Logger) balancer {
@@ -89,7 +100,8 @@ func (r *tableNumberBalancer) FindTarget(
 
 	captureWorkload := make(map[model.CaptureID]int)
 	for captureID := range captures {
-		captureWorkload[captureID] = r.randomizeWorkload(tables.CountTableByCaptureID(captureID))
+		workload := tables.CountTableByCaptureID(captureID) + r.externalWorkloads[captureID]
+		captureWorkload[captureID] = r.randomizeWorkload(workload)
 	}
 
 	candidate := ""
@@ -109,6 +121,11 @@ func (r *tableNumberBalancer) FindTarget(
 	return candidate, true
 }
 
+// SetExternalWorkloads implements the balancer interface.
+func (r *tableNumberBalancer) SetExternalWorkloads(workloads map[model.CaptureID]int) {
+	r.externalWorkloads = workloads
+}
+
 const (
 	randomPartBitSize = 8
 	randomPartMask    = (1 << randomPartBitSize) - 1
@@ -134,10 +151,11 @@ func (r *tableNumberBalancer) randomizeWorkload(input int) int {
 // Read the comment in the function body on the details of the victim selection.
 // Complexity note: This function is called O(c) times during one scheduler tick,
 // where `c` is the number of TiCDC nodes. The function itself has complexity O(n),
-// where `n` is the number of tables.
+// where `n` is the number of tables, or O(n*c) if the filter is set.
 func (r *tableNumberBalancer) FindVictims(
 	tables *util.TableSet,
 	captures map[model.CaptureID]*model.CaptureInfo,
+	filter util.CaptureFilter,
 ) []*util.TableRecord {
 	// Algorithm overview: We try to remove some tables as the victims so that
 	// no captures are assigned more tables than the average workload measured in table number,
@@ -146,6 +164,10 @@ func (r *tableNumberBalancer) FindVictims(
 	// In formula, we try to maintain the invariant:
 	//
 	// num(tables assigned to any capture) < num(tables) / num(captures) + 1
+	//
+	// The tables on the captures rejected by the filter are always removed,
+	// so that they are added to the eligible captures again, and the tables
+	// that can only be placed on their current captures are never removed.
 
 	totalTableNum := len(tables.GetAllTables())
 	captureNum := len(captures)
@@ -163,6 +185,11 @@ func (r *tableNumberBalancer) FindVictims(
 
 	var victims []*util.TableRecord
 	for _, tables := range tables.GetAllTablesGroupedByCaptures() {
+		tableNum2Remove := len(tables) - upperLimitPerCapture
+		if tableNum2Remove <= 0 && filter == nil {
+			continue
+		}
+
 		var tableList []model.TableID
 		for tableID := range tables {
 			tableList = append(tableList, tableID)
@@ -184,22 +211,30 @@ func (r *tableNumberBalancer) FindVictims(
 			util.SortTableIDs(tableList)
 		}
 
-		tableNum2Remove := len(tables) - upperLimitPerCapture
-		if tableNum2Remove <= 0 {
-			continue
-		}
-
-		// here we pick `tableNum2Remove` tables to delete,
+		// here we pick the misplaced tables and `tableNum2Remove` tables to delete,
 		for _, tableID := range tableList {
-			if tableNum2Remove <= 0 {
-				break
-			}
-
 			record := tables[tableID]
 			if record == nil {
 				panic("unreachable")
 			}
 
+			if util.IsMisplaced(filter, tableID, record.CaptureID, captures) {
+				r.logger.Info("Rebalance: find misplaced victim table",
+					zap.Any("tableRecord", record))
+				victims = append(victims, record)
+				tableNum2Remove--
+				continue
+			}
+			if tableNum2Remove <= 0 {
+				continue
+			}
+			if filter != nil {
+				eligible := util.FilterCaptures(filter, tableID, captures)
+				if _, ok := eligible[record.CaptureID]; ok && len(eligible) == 1 {
+					continue
+				}
+			}
+
 			r.logger.Info("Rebalance: find victim table",
 				zap.Any("tableRecord", record))
 			victims = append(victims, record)
@@ -69,7 +69,7 @@ func TestBalancerFindVictimsDeterministic(t *testing.T) {
 		},
 	}
 
-	victims := balancer.FindVictims(tables, mockCaptureInfos)
+	victims := balancer.FindVictims(tables, mockCaptureInfos, nil)
 	require.Len(t, victims, 2)
 	require.Contains(t, victims, &util.TableRecord{
 		TableID:   1,
@@ -122,7 +122,7 @@ func TestBalancerFindVictimsRandomized(t *testing.T) {
 		},
 	}
 
-	victims := balancer.FindVictims(tables, mockCaptureInfos)
+	victims := balancer.FindVictims(tables, mockCaptureInfos, nil)
 	// We expect exactly 2 victims from "capture-1".
 	require.Len(t, victims, 2)
 	for _, record := range victims {
@@ -132,7 +132,7 @@ func TestBalancerFindVictimsRandomized(t *testing.T) {
 	// Retry for at most 10 times to see if FindVictims can return
 	// a different set of victims.
 	for i := 0; i < 10; i++ {
-		newVictims := balancer.FindVictims(tables, mockCaptureInfos)
+		newVictims := balancer.FindVictims(tables, mockCaptureInfos, nil)
 		if !subset.Check(newVictims, victims) && !subset.Check(victims, newVictims) {
 			return
 		}
@@ -140,6 +140,35 @@ func TestBalancerFindVictimsRandomized(t *testing.T) {
 	require.Fail(t, "randomness test failed")
 }
 
+func TestBalancerFindVictimsWithFilter(t *testing.T) {
+	balancer := newDeterministicTableNumberRebalancer(zap.L())
+	tables := util.NewTableSet()
+	for tableID := model.TableID(1); tableID <= 4; tableID++ {
+		tables.AddTableRecord(&util.TableRecord{TableID: tableID, CaptureID: "capture-1"})
+	}
+	tables.AddTableRecord(&util.TableRecord{TableID: 5, CaptureID: "capture-2"})
+	tables.AddTableRecord(&util.TableRecord{TableID: 6, CaptureID: "capture-2"})
+
+	mockCaptureInfos := map[model.CaptureID]*model.CaptureInfo{
+		"capture-1": {ID: "capture-1"},
+		"capture-2": {ID: "capture-2"},
+		"capture-3": {ID: "capture-3"},
+	}
+	// Tables 1, 2 and 5 can only be placed on capture-1.
+	filter := func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		return tableID > 2 && tableID != 5 || capture.ID == "capture-1"
+	}
+
+	// Tables 1 and 2 stay on capture-1, and table 5 fell back to capture-2
+	// is moved back even if capture-2 is not overloaded.
+	victims := balancer.FindVictims(tables, mockCaptureInfos, filter)
+	require.ElementsMatch(t, []*util.TableRecord{
+		{TableID: 3, CaptureID: "capture-1"},
+		{TableID: 4, CaptureID: "capture-1"},
+		{TableID: 5, CaptureID: "capture-2"},
+	}, victims)
+}
+
 func TestBalancerFindTarget(t *testing.T) {
 	balancer := newTableNumberRebalancerWithRandomSeed(zap.L(), randomSeedForTestingBalancer)
 	tables := util.NewTableSet()
@@ -186,6 +215,45 @@ func TestBalancerFindTarget(t *testing.T) {
 	require.Equal(t, "capture-3", target)
 }
 
+func TestBalancerFindTargetExternalWorkloads(t *testing.T) {
+	balancer := newTableNumberRebalancerWithRandomSeed(zap.L(), randomSeedForTestingBalancer)
+	tables := util.NewTableSet()
+
+	tables.AddTableRecord(&util.TableRecord{
+		TableID:   1,
+		CaptureID: "capture-1",
+	})
+	tables.AddTableRecord(&util.TableRecord{
+		TableID:   2,
+		CaptureID: "capture-2",
+	})
+
+	mockCaptureInfos := map[model.CaptureID]*model.CaptureInfo{
+		"capture-1": {
+			ID: "capture-1",
+		},
+		"capture-2": {
+			ID: "capture-2",
+		},
+		"capture-3": {
+			ID: "capture-3",
+		},
+	}
+
+	balancer.SetExternalWorkloads(map[model.CaptureID]int{
+		"capture-2": 1,
+		"capture-3": 3,
+	})
+	target, ok := balancer.FindTarget(tables, mockCaptureInfos)
+	require.True(t, ok)
+	require.Equal(t, "capture-1", target)
+
+	balancer.SetExternalWorkloads(nil)
+	target, ok = balancer.FindTarget(tables, mockCaptureInfos)
+	require.True(t, ok)
+	require.Equal(t, "capture-3", target)
+}
+
 func TestBalancerFindTargetTied(t *testing.T) {
 	balancer := newTableNumberRebalancerWithRandomSeed(zap.L(), randomSeedForTestingBalancer)
 	tables := util.NewTableSet()
@@ -49,6 +49,17 @@ type ScheduleDispatcher interface {
 	// Rebalance triggers a rebalance operation.
 	// It should be thread-safe
 	Rebalance()
+
+	// SetExternalWorkloads sets the number of tables of other changefeeds
+	// on each capture, which are taken into account when choosing the
+	// captures for new tables.
+	// It should be thread-safe.
+	SetExternalWorkloads(workloads map[model.CaptureID]int)
+
+	// SetCaptureFilter sets the filter that decides which captures a table
+	// can be placed on.
+	// It should be thread-safe.
+	SetCaptureFilter(filter util.CaptureFilter)
 }
 
 // ScheduleDispatcherCommunicator is an interface for the BaseScheduleDispatcher to
@@ -71,11 +82,6 @@ type ScheduleDispatcherCommunicator interface {
 		captureID model.CaptureID) (done bool, err error)
 }
 
-const (
-	// captureCountUninitialized is a placeholder for an unknown total capture count.
-	captureCountUninitialized = -1
-)
-
 // BaseScheduleDispatcher implements the basic logic of a ScheduleDispatcher.
 // For it to be directly useful to the Owner, the Owner should implement it own
 // ScheduleDispatcherCommunicator.
@@ -88,9 +94,11 @@ type BaseScheduleDispatcher struct {
 
 	moveTableManager moveTableManager
 	balancer         balancer
+	captureFilter    util.CaptureFilter
 
-	lastTickCaptureCount int
-	needRebalance        bool
+	// lastTickCaptures is nil before the first tick.
+	lastTickCaptures map[model.CaptureID]*model.CaptureInfo
+	needRebalance    bool
 
 	// read only fields
 	changeFeedID model.ChangeFeedID
@@ -108,15 +116,14 @@ func NewBaseScheduleDispatcher(
 	logger := log.L().With(zap.String("changefeed", changeFeedID))
 
 	return &BaseScheduleDispatcher{
-		tables:               util.NewTableSet(),
-		captureStatus:        map[model.CaptureID]*captureStatus{},
-		moveTableManager:     newMoveTableManager(),
-		balancer:             newTableNumberRebalancer(logger),
-		changeFeedID:         changeFeedID,
-		logger:               logger,
-		communicator:         communicator,
-		checkpointTs:         checkpointTs,
-		lastTickCaptureCount: captureCountUninitialized,
+		tables:           util.NewTableSet(),
+		captureStatus:    map[model.CaptureID]*captureStatus{},
+		moveTableManager: newMoveTableManager(),
+		balancer:         newTableNumberRebalancer(logger),
+		changeFeedID:     changeFeedID,
+		logger:           logger,
+		communicator:     communicator,
+		checkpointTs:     checkpointTs,
 	}
 }
 
@@ -162,22 +169,28 @@ func (s *BaseScheduleDispatcher) Tick(
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Update the internal capture list with information from the Owner
-	// (from Etcd in the current implementation).
-	s.captures = captures
-
-	// We trigger an automatic rebalance if the capture count has changed.
+	// We trigger an automatic rebalance if the captures have changed, which
+	// also moves the tables that fell back to ineligible captures back to
+	// the eligible ones that are up again.
 	// This logic is the same as in the older implementation of scheduler.
 	// TODO a better criterion is needed.
-	// NOTE: We need to check whether the capture count has changed in every tick,
-	// and set needRebalance to true if it has. If we miss a capture count change,
+	// NOTE: We need to check whether the captures have changed in every tick,
+	// and set needRebalance to true if they have. If we miss a capture change,
 	// the workload may never be balanced until user manually triggers a rebalance.
-	if s.lastTickCaptureCount != captureCountUninitialized &&
-		s.lastTickCaptureCount != len(captures) {
+	if s.lastTickCaptures != nil &&
+		util.CapturesChanged(s.lastTickCaptures, captures) {
 
 		s.needRebalance = true
 	}
-	s.lastTickCaptureCount = len(captures)
+	// The caller may update the map in place, so a copy is kept.
+	s.lastTickCaptures = make(map[model.CaptureID]*model.CaptureInfo, len(captures))
+	for captureID, info := range captures {
+		s.lastTickCaptures[captureID] = info
+	}
+
+	// Update the internal capture list with information from the Owner
+	// (from Etcd in the current implementation).
+	s.captures = captures
 
 	// Checks for checkpoint regression as a safety measure.
 	if s.checkpointTs > checkpointTs {
@@ -398,7 +411,8 @@ func (s *BaseScheduleDispatcher) addTable(
 	target, ok := s.moveTableManager.GetTargetByTableID(tableID)
 	isManualMove := ok
 	if !ok {
-		target, ok = s.balancer.FindTarget(s.tables, s.captures)
+		captures := util.FilterCaptures(s.captureFilter, tableID, s.captures)
+		target, ok = s.balancer.FindTarget(s.tables, captures)
 		if !ok {
 			s.logger.Warn("no active capture")
 			return true, nil
@@ -500,8 +514,26 @@ func (s *BaseScheduleDispatcher) Rebalance() {
 	s.needRebalance = true
 }
 
+// SetExternalWorkloads implements the interface ScheduleDispatcher.
+func (s *BaseScheduleDispatcher) SetExternalWorkloads(workloads map[model.CaptureID]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.balancer.SetExternalWorkloads(workloads)
+}
+
+// SetCaptureFilter implements the interface ScheduleDispatcher.
+func (s *BaseScheduleDispatcher) SetCaptureFilter(filter util.CaptureFilter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.captureFilter = filter
+	// The tables placed by the previous filter may be misplaced now.
+	s.needRebalance = true
+}
+
 func (s *BaseScheduleDispatcher) rebalance(ctx context.Context) (done bool, err error) {
-	tablesToRemove := s.balancer.FindVictims(s.tables, s.captures)
+	tablesToRemove := s.balancer.FindVictims(s.tables, s.captures, s.captureFilter)
 	for _, record := range tablesToRemove {
 		if record.Status != util.RunningTable {
 			s.logger.DPanic("unexpected table status",
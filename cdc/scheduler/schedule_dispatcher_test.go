@@ -178,6 +178,35 @@ func TestDispatchTable(t *testing.T) {
 	require.Equal(t, model.Ts(1300), resolvedTs)
 }
 
+func TestDispatchTableWithCaptureFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := cdcContext.NewBackendContext4Test(false)
+	communicator := NewMockScheduleDispatcherCommunicator()
+	dispatcher := NewBaseScheduleDispatcher("cf-1", communicator, 1000)
+	dispatcher.SetCaptureFilter(func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		return capture.ID == "capture-2"
+	})
+
+	communicator.On("Announce", mock.Anything, "cf-1", "capture-1").Return(true, nil)
+	communicator.On("Announce", mock.Anything, "cf-1", "capture-2").Return(true, nil)
+	_, _, err := dispatcher.Tick(ctx, 1000, []model.TableID{1, 2, 3}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	communicator.AssertExpectations(t)
+
+	dispatcher.OnAgentSyncTaskStatuses("capture-1", defaultEpoch, []model.TableID{}, []model.TableID{}, []model.TableID{})
+	dispatcher.OnAgentSyncTaskStatuses("capture-2", defaultEpoch, []model.TableID{}, []model.TableID{}, []model.TableID{})
+
+	communicator.Reset()
+	communicator.On("DispatchTable", mock.Anything, "cf-1", mock.Anything, "capture-2", false, defaultEpoch).
+		Return(true, nil)
+	_, _, err = dispatcher.Tick(ctx, 1000, []model.TableID{1, 2, 3}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	communicator.AssertExpectations(t)
+	require.ElementsMatch(t, []model.TableID{1, 2, 3}, communicator.addTableRecords["capture-2"])
+	require.Empty(t, communicator.addTableRecords["capture-1"])
+}
+
 func TestSyncCaptures(t *testing.T) {
 	t.Parallel()
 
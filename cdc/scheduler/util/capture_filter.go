@@ -0,0 +1,82 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+// CaptureFilter returns whether a table can be placed on a capture.
+type CaptureFilter func(tableID model.TableID, capture *model.CaptureInfo) bool
+
+// FilterCaptures returns the captures that the table can be placed on.
+// All captures are returned if the filter is nil or none of them is
+// eligible, so that the table is still replicated when all the eligible
+// captures are down.
+func FilterCaptures(
+	filter CaptureFilter,
+	tableID model.TableID,
+	captures map[model.CaptureID]*model.CaptureInfo,
+) map[model.CaptureID]*model.CaptureInfo {
+	if filter == nil {
+		return captures
+	}
+	eligible := make(map[model.CaptureID]*model.CaptureInfo, len(captures))
+	for captureID, info := range captures {
+		if filter(tableID, info) {
+			eligible[captureID] = info
+		}
+	}
+	if len(eligible) == 0 {
+		return captures
+	}
+	return eligible
+}
+
+// IsMisplaced returns whether the table is on a capture that it can't be
+// placed on while some other capture is eligible, e.g. the table fell back
+// to an ineligible capture when all the eligible captures were down, and one
+// of them is up again.
+func IsMisplaced(
+	filter CaptureFilter,
+	tableID model.TableID,
+	captureID model.CaptureID,
+	captures map[model.CaptureID]*model.CaptureInfo,
+) bool {
+	if filter == nil {
+		return false
+	}
+	if info, ok := captures[captureID]; !ok || filter(tableID, info) {
+		return false
+	}
+	for _, info := range captures {
+		if filter(tableID, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// CapturesChanged returns whether a capture is added or removed.
+func CapturesChanged(old, captures map[model.CaptureID]*model.CaptureInfo) bool {
+	if len(old) != len(captures) {
+		return true
+	}
+	for captureID := range captures {
+		if _, ok := old[captureID]; !ok {
+			return true
+		}
+	}
+	return false
+}
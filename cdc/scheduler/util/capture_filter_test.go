@@ -0,0 +1,61 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterCaptures(t *testing.T) {
+	t.Parallel()
+
+	captures := map[model.CaptureID]*model.CaptureInfo{
+		"capture-1": {ID: "capture-1", Labels: map[string]string{"zone": "z1"}},
+		"capture-2": {ID: "capture-2", Labels: map[string]string{"zone": "z2"}},
+	}
+	require.Equal(t, captures, FilterCaptures(nil, 1, captures))
+
+	filter := func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		return tableID == 1 && capture.MatchLabels(map[string]string{"zone": "z2"})
+	}
+	require.Equal(t, map[model.CaptureID]*model.CaptureInfo{
+		"capture-2": captures["capture-2"],
+	}, FilterCaptures(filter, 1, captures))
+	// All captures are returned if none of them is eligible.
+	require.Equal(t, captures, FilterCaptures(filter, 2, captures))
+}
+
+func TestIsMisplaced(t *testing.T) {
+	t.Parallel()
+
+	captures := map[model.CaptureID]*model.CaptureInfo{
+		"capture-1": {ID: "capture-1", Labels: map[string]string{"zone": "z1"}},
+		"capture-2": {ID: "capture-2", Labels: map[string]string{"zone": "z2"}},
+	}
+	require.False(t, IsMisplaced(nil, 1, "capture-1", captures))
+
+	filter := func(tableID model.TableID, capture *model.CaptureInfo) bool {
+		return tableID == 1 && capture.MatchLabels(map[string]string{"zone": "z2"})
+	}
+	require.True(t, IsMisplaced(filter, 1, "capture-1", captures))
+	require.False(t, IsMisplaced(filter, 1, "capture-2", captures))
+	// A table is not misplaced if no capture is eligible.
+	require.False(t, IsMisplaced(filter, 2, "capture-1", captures))
+	// The fallback placement is kept while the eligible capture is down.
+	delete(captures, "capture-2")
+	require.False(t, IsMisplaced(filter, 1, "capture-1", captures))
+}
@@ -38,16 +38,19 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
 
+	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/fsutil"
 	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/tcpserver"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
 	p2pProto "github.com/pingcap/tiflow/proto/p2p"
@@ -91,6 +94,11 @@ func NewServer(pdEndpoints []string) (*Server, error) {
 		}
 	}
 
+	// Load the key before any data is spilled to disk.
+	if err := encrypt.InitGlobalCipher(conf.Encryption); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// tcpServer is the unified frontend of the CDC server that serves
 	// both RESTful APIs and gRPC APIs.
 	// Note that we pass the TLS config to the tcpServer, so there is no need to
@@ -223,7 +231,7 @@ func (s *Server) startStatusHTTP(lis net.Listener) error {
 	RegisterRoutes(router, s.capture, registry)
 
 	// No need to configure TLS because it is already handled by `s.tcpServer`.
-	s.statusServer = &http.Server{Handler: router}
+	s.statusServer = &http.Server{Handler: router, ConnContext: api.ConnContextWithTLS}
 
 	go func() {
 		log.Info("http server is running", zap.String("addr", conf.Addr))
@@ -281,6 +289,18 @@ func (s *Server) run(ctx context.Context) (err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	conf := config.GetGlobalServerConfig()
+	shutdownTracing, err := tracing.Init(ctx, conf.Tracing, conf.AdvertiseAddr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		// Flush the pending spans before exiting.
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Warn("shutdown tracing failed", zap.Error(err))
+		}
+	}()
+
 	wg, cctx := errgroup.WithContext(ctx)
 
 	wg.Go(func() error {
@@ -303,7 +323,6 @@ func (s *Server) run(ctx context.Context) (err error) {
 		return s.tcpServer.Run(cctx)
 	})
 
-	conf := config.GetGlobalServerConfig()
 	if conf.Debug.EnableNewScheduler {
 		grpcServer := grpc.NewServer()
 		p2pProto.RegisterCDCPeerToPeerServer(grpcServer, s.grpcService)
@@ -44,6 +44,8 @@ type AvroEventBatchEncoder struct {
 	resultBuf          []*MQMessage
 
 	tz *time.Location
+	// enableTxnMetadata appends the transaction metadata to the values.
+	enableTxnMetadata bool
 }
 
 type avroEncodeResult struct {
@@ -92,7 +94,11 @@ func (a *AvroEventBatchEncoder) AppendRowChangedEvent(e *model.RowChangedEvent)
 	mqMessage := NewMQMessage(config.ProtocolAvro, nil, nil, e.CommitTs, model.MqMessageTypeRow, &e.Table.Schema, &e.Table.Table)
 
 	if !e.IsDelete() {
-		res, err := avroEncode(e.Table, a.valueSchemaManager, e.TableInfoVersion, e.Columns, e.ColInfos, a.tz)
+		var txn *avroTxnMetadata
+		if a.enableTxnMetadata {
+			txn = &avroTxnMetadata{startTs: e.StartTs, commitTs: e.CommitTs, txnIndex: e.TxnIndex}
+		}
+		res, err := avroEncode(e.Table, a.valueSchemaManager, e.TableInfoVersion, e.Columns, e.ColInfos, a.tz, txn)
 		if err != nil {
 			log.Warn("AppendRowChangedEvent: avro encoding failed", zap.String("table", e.Table.String()))
 			return errors.Annotate(err, "AppendRowChangedEvent could not encode to Avro")
@@ -111,7 +117,7 @@ func (a *AvroEventBatchEncoder) AppendRowChangedEvent(e *model.RowChangedEvent)
 
 	pkeyCols := e.HandleKeyColumns()
 
-	res, err := avroEncode(e.Table, a.keySchemaManager, e.TableInfoVersion, pkeyCols, e.ColInfos, a.tz)
+	res, err := avroEncode(e.Table, a.keySchemaManager, e.TableInfoVersion, pkeyCols, e.ColInfos, a.tz, nil)
 	if err != nil {
 		log.Warn("AppendRowChangedEvent: avro encoding failed", zap.String("table", e.Table.String()))
 		return errors.Annotate(err, "AppendRowChangedEvent could not encode to Avro")
@@ -160,9 +166,25 @@ func (a *AvroEventBatchEncoder) Size() int {
 	return sum
 }
 
-func avroEncode(table *model.TableName, manager *AvroSchemaManager, tableVersion uint64, cols []*model.Column, colInfos []rowcodec.ColInfo, tz *time.Location) (*avroEncodeResult, error) {
+// The fields of the transaction metadata appended to the value records.
+const (
+	avroStartTsField  = "_tidb_start_ts"
+	avroCommitTsField = "_tidb_commit_ts"
+	avroTxnIndexField = "_tidb_txn_index"
+)
+
+// avroTxnMetadata is the transaction metadata of a row.
+type avroTxnMetadata struct {
+	startTs  uint64
+	commitTs uint64
+	txnIndex int64
+}
+
+// avroEncode encodes the columns into an Avro record, the transaction
+// metadata is appended to the record if txn is not nil.
+func avroEncode(table *model.TableName, manager *AvroSchemaManager, tableVersion uint64, cols []*model.Column, colInfos []rowcodec.ColInfo, tz *time.Location, txn *avroTxnMetadata) (*avroEncodeResult, error) {
 	schemaGen := func() (string, error) {
-		schema, err := ColumnInfoToAvroSchema(table.Table, cols)
+		schema, err := columnInfoToAvroSchema(table.Table, cols, txn != nil)
 		if err != nil {
 			return "", errors.Annotate(err, "AvroEventBatchEncoder: generating schema failed")
 		}
@@ -179,6 +201,12 @@ func avroEncode(table *model.TableName, manager *AvroSchemaManager, tableVersion
 	if err != nil {
 		return nil, errors.Annotate(err, "AvroEventBatchEncoder: converting to native failed")
 	}
+	if txn != nil {
+		record := native.(map[string]interface{})
+		record[avroStartTsField] = int64(txn.startTs)
+		record[avroCommitTsField] = int64(txn.commitTs)
+		record[avroTxnIndexField] = txn.txnIndex
+	}
 
 	bin, err := avroCodec.BinaryFromNative(nil, native)
 	if err != nil {
@@ -215,6 +243,10 @@ const (
 
 // ColumnInfoToAvroSchema generates the Avro schema JSON for the corresponding columns
 func ColumnInfoToAvroSchema(name string, columnInfo []*model.Column) (string, error) {
+	return columnInfoToAvroSchema(name, columnInfo, false)
+}
+
+func columnInfoToAvroSchema(name string, columnInfo []*model.Column, withTxnMetadata bool) (string, error) {
 	top := avroSchemaTop{
 		Tp:     "record",
 		Name:   name,
@@ -237,6 +269,11 @@ func ColumnInfoToAvroSchema(name string, columnInfo []*model.Column) (string, er
 
 		top.Fields = append(top.Fields, field)
 	}
+	if withTxnMetadata {
+		for _, name := range []string{avroStartTsField, avroCommitTsField, avroTxnIndexField} {
+			top.Fields = append(top.Fields, map[string]interface{}{"name": name, "type": "long"})
+		}
+	}
 
 	str, err := json.Marshal(&top)
 	if err != nil {
@@ -557,6 +594,7 @@ func (b *avroEventBatchEncoderBuilder) Build() EventBatchEncoder {
 	encoder.SetKeySchemaManager(b.keySchemaManager)
 	encoder.SetValueSchemaManager(b.valueSchemaManager)
 	encoder.SetTimeZone(b.tz)
+	encoder.enableTxnMetadata = b.config.enableTxnMetadata
 
 	return encoder
 }
@@ -138,7 +138,7 @@ func (s *avroBatchEncoderSuite) TestAvroEncodeOnly(c *check.C) {
 	avroCodec, err := goavro.NewCodec(schema)
 	c.Assert(err, check.IsNil)
 
-	r, err := avroEncode(&table, s.encoder.valueSchemaManager, 1, cols, colInfos, time.Local)
+	r, err := avroEncode(&table, s.encoder.valueSchemaManager, 1, cols, colInfos, time.Local, nil)
 	c.Assert(err, check.IsNil)
 
 	res, _, err := avroCodec.NativeFromBinary(r.data)
@@ -213,7 +213,7 @@ func (s *avroBatchEncoderSuite) TestAvroNull(c *check.C) {
 
 	avroCodec, err := goavro.NewCodec(schema)
 	c.Assert(err, check.IsNil)
-	r, err := avroEncode(&table, s.encoder.valueSchemaManager, 1, cols, colInfos, time.Local)
+	r, err := avroEncode(&table, s.encoder.valueSchemaManager, 1, cols, colInfos, time.Local, nil)
 	c.Assert(err, check.IsNil)
 
 	native, _, err = avroCodec.NativeFromBinary(r.data)
@@ -232,6 +232,38 @@ func (s *avroBatchEncoderSuite) TestAvroNull(c *check.C) {
 	}
 }
 
+func (s *avroBatchEncoderSuite) TestAvroTxnMetadata(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	table := model.TableName{
+		Schema: "testdb",
+		Table:  "TestAvroTxnMetadata",
+	}
+	cols := []*model.Column{
+		{Name: "id", Value: int64(1), Flag: model.HandleKeyFlag, Type: mysql.TypeLong},
+	}
+	colInfos := []rowcodec.ColInfo{
+		{ID: 1, IsPKHandle: true, VirtualGenCol: false, Ft: types.NewFieldType(mysql.TypeLong)},
+	}
+
+	schema, err := columnInfoToAvroSchema(table.Table, cols, true)
+	c.Assert(err, check.IsNil)
+	avroCodec, err := goavro.NewCodec(schema)
+	c.Assert(err, check.IsNil)
+
+	txn := &avroTxnMetadata{startTs: 100, commitTs: 110, txnIndex: 2}
+	r, err := avroEncode(&table, s.encoder.valueSchemaManager, 1, cols, colInfos, time.Local, txn)
+	c.Assert(err, check.IsNil)
+	native, _, err := avroCodec.NativeFromBinary(r.data)
+	c.Assert(err, check.IsNil)
+	c.Assert(native, check.DeepEquals, map[string]interface{}{
+		"id":              int32(1),
+		avroStartTsField:  int64(100),
+		avroCommitTsField: int64(110),
+		avroTxnIndexField: int64(2),
+	})
+}
+
 func (s *avroBatchEncoderSuite) TestAvroTimeZone(c *check.C) {
 	defer testleak.AfterTest(c)()
 
@@ -267,7 +299,7 @@ func (s *avroBatchEncoderSuite) TestAvroTimeZone(c *check.C) {
 	avroCodec, err := goavro.NewCodec(schema)
 	c.Assert(err, check.IsNil)
 
-	r, err := avroEncode(&table, s.encoder.valueSchemaManager, 1, cols, colInfos, location)
+	r, err := avroEncode(&table, s.encoder.valueSchemaManager, 1, cols, colInfos, location, nil)
 	c.Assert(err, check.IsNil)
 
 	res, _, err := avroCodec.NativeFromBinary(r.data)
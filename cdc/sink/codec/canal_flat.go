@@ -179,6 +179,10 @@ func (c *canalFlatMessage) pkNameSet() map[string]struct{} {
 type tidbExtension struct {
 	CommitTs    uint64 `json:"commitTs,omitempty"`
 	WatermarkTs uint64 `json:"watermarkTs,omitempty"`
+	// StartTs and TxnIndex are the transaction metadata of a row,
+	// they are only set if the transaction metadata is enabled.
+	StartTs  uint64 `json:"startTs,omitempty"`
+	TxnIndex int64  `json:"txnIndex,omitempty"`
 }
 
 type canalFlatMessageWithTiDBExtension struct {
@@ -281,9 +285,14 @@ func (c *CanalFlatEventBatchEncoder) newFlatMessageForDML(e *model.RowChangedEve
 		return flatMessage, nil
 	}
 
+	extensions := &tidbExtension{CommitTs: e.CommitTs}
+	if e.TxnIndex != 0 {
+		extensions.StartTs = e.StartTs
+		extensions.TxnIndex = e.TxnIndex
+	}
 	return &canalFlatMessageWithTiDBExtension{
 		canalFlatMessage: flatMessage,
-		Extensions:       &tidbExtension{CommitTs: e.CommitTs},
+		Extensions:       extensions,
 	}, nil
 }
 
@@ -472,6 +481,10 @@ func (b *CanalFlatEventBatchDecoder) NextResolvedEvent() (uint64, error) {
 func canalFlatMessage2RowChangedEvent(flatMessage canalFlatMessageInterface) (*model.RowChangedEvent, error) {
 	result := new(model.RowChangedEvent)
 	result.CommitTs = flatMessage.getCommitTs()
+	if withExtension, ok := flatMessage.(*canalFlatMessageWithTiDBExtension); ok {
+		result.StartTs = withExtension.Extensions.StartTs
+		result.TxnIndex = withExtension.Extensions.TxnIndex
+	}
 	result.Table = &model.TableName{
 		Schema: *flatMessage.getSchema(),
 		Table:  *flatMessage.getTable(),
@@ -217,6 +217,30 @@ func (s *canalFlatSuite) TestNewCanalFlatEventBatchDecoder4RowMessage(c *check.C
 	}
 }
 
+func (s *canalFlatSuite) TestTxnMetadata(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	encoder := &CanalFlatEventBatchEncoder{builder: NewCanalEntryBuilder(), enableTiDBExtension: true}
+	row := *testCaseInsert
+	row.StartTs = 417318403368288250
+	row.TxnIndex = 2
+	c.Assert(encoder.AppendRowChangedEvent(&row), check.IsNil)
+	mqMessages := encoder.Build()
+	c.Assert(mqMessages, check.HasLen, 1)
+	c.Assert(string(mqMessages[0].Value), check.Matches,
+		`.*"_tidb":{"commitTs":417318403368288260,"startTs":417318403368288250,"txnIndex":2}.*`)
+
+	decoder := NewCanalFlatEventBatchDecoder(mqMessages[0].Value, true)
+	_, hasNext, err := decoder.HasNext()
+	c.Assert(err, check.IsNil)
+	c.Assert(hasNext, check.IsTrue)
+	consumed, err := decoder.NextRowChangedEvent()
+	c.Assert(err, check.IsNil)
+	c.Assert(consumed.CommitTs, check.Equals, row.CommitTs)
+	c.Assert(consumed.StartTs, check.Equals, row.StartTs)
+	c.Assert(consumed.TxnIndex, check.Equals, row.TxnIndex)
+}
+
 func (s *canalFlatSuite) TestNewCanalFlatMessageFromDDL(c *check.C) {
 	defer testleak.AfterTest(c)()
 	encoder := &CanalFlatEventBatchEncoder{builder: NewCanalEntryBuilder()}
@@ -37,6 +37,9 @@ type Config struct {
 	// canal-json only
 	enableTiDBExtension bool
 
+	// only for `open-protocol`, `canal-json` and `avro` at the moment.
+	enableTxnMetadata bool
+
 	// avro only
 	avroRegistry string
 	tz           *time.Location
@@ -58,6 +61,7 @@ func NewConfig(protocol config.Protocol, tz *time.Location) *Config {
 
 const (
 	codecOPTEnableTiDBExtension = "enable-tidb-extension"
+	codecOPTEnableTxnMetadata   = "enable-txn-metadata"
 	codecOPTMaxBatchSize        = "max-batch-size"
 	codecOPTMaxMessageBytes     = "max-message-bytes"
 	codecAvroRegistry           = "registry"
@@ -74,6 +78,14 @@ func (c *Config) Apply(sinkURI *url.URL, opts map[string]string) error {
 		c.enableTiDBExtension = b
 	}
 
+	if s := params.Get(codecOPTEnableTxnMetadata); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		c.enableTxnMetadata = b
+	}
+
 	if s := params.Get(codecOPTMaxBatchSize); s != "" {
 		a, err := strconv.Atoi(s)
 		if err != nil {
@@ -109,6 +121,20 @@ func (c *Config) Validate() error {
 		return cerror.ErrMQCodecInvalidConfig.GenWithStack(`enable-tidb-extension only support canal-json protocol`)
 	}
 
+	if c.enableTxnMetadata {
+		switch c.protocol {
+		case config.ProtocolDefault, config.ProtocolOpen, config.ProtocolAvro:
+		case config.ProtocolCanalJSON:
+			if !c.enableTiDBExtension {
+				return cerror.ErrMQCodecInvalidConfig.GenWithStack(
+					`enable-txn-metadata requires enable-tidb-extension for canal-json protocol`)
+			}
+		default:
+			return cerror.ErrMQCodecInvalidConfig.GenWithStack(
+				`enable-txn-metadata only support open-protocol, canal-json and avro protocol`)
+		}
+	}
+
 	if c.protocol == config.ProtocolAvro {
 		if c.avroRegistry == "" {
 			return cerror.ErrMQCodecInvalidConfig.GenWithStack(`Avro protocol requires parameter "registry"`)
@@ -135,6 +161,11 @@ func (c *Config) MaxMessageBytes() int {
 	return c.maxMessageBytes
 }
 
+// EnableTxnMetadata returns whether the rows carry the transaction metadata
+func (c *Config) EnableTxnMetadata() bool {
+	return c.enableTxnMetadata
+}
+
 // Protocol return the protocol for the codec
 func (c *Config) Protocol() config.Protocol {
 	return c.protocol
@@ -139,3 +139,42 @@ func TestConfigApplyValidate(t *testing.T) {
 	err = c.Validate()
 	require.Error(t, err, cerror.ErrMQCodecInvalidConfig)
 }
+
+func TestConfigEnableTxnMetadata(t *testing.T) {
+	testCases := []struct {
+		uri         string
+		expectedErr string
+	}{
+		{uri: "kafka://127.0.0.1:9092/abc?protocol=open-protocol&enable-txn-metadata=true"},
+		{uri: "kafka://127.0.0.1:9092/abc?protocol=canal-json&enable-txn-metadata=true&enable-tidb-extension=true"},
+		{
+			uri:         "kafka://127.0.0.1:9092/abc?protocol=canal-json&enable-txn-metadata=true",
+			expectedErr: ".*enable-txn-metadata requires enable-tidb-extension for canal-json protocol.*",
+		},
+		{
+			uri:         "kafka://127.0.0.1:9092/abc?protocol=maxwell&enable-txn-metadata=true",
+			expectedErr: ".*enable-txn-metadata only support open-protocol, canal-json and avro protocol.*",
+		},
+		{uri: "kafka://127.0.0.1:9092/abc?protocol=maxwell&enable-txn-metadata=false"},
+	}
+	for _, tc := range testCases {
+		sinkURI, err := url.Parse(tc.uri)
+		require.Nil(t, err)
+		var p config.Protocol
+		require.Nil(t, p.FromString(sinkURI.Query().Get("protocol")))
+		c := NewConfig(p, timeutil.SystemLocation())
+		require.Nil(t, c.Apply(sinkURI, map[string]string{}))
+		err = c.Validate()
+		if tc.expectedErr == "" {
+			require.Nil(t, err, tc.uri)
+			continue
+		}
+		require.Regexp(t, tc.expectedErr, err, tc.uri)
+	}
+
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?enable-txn-metadata=a")
+	require.Nil(t, err)
+	c := NewConfig(config.ProtocolOpen, timeutil.SystemLocation())
+	require.Error(t, c.Apply(sinkURI, map[string]string{}), "invalid syntax")
+	require.False(t, c.EnableTxnMetadata())
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
@@ -50,6 +51,18 @@ type MQMessage struct {
 	Type      model.MqMessageType // type
 	Protocol  config.Protocol     // protocol
 	rowsCount int                 // rows in one MQ Message
+
+	// Headers are sent as Kafka record headers or Pulsar message properties.
+	Headers []MessageHeader
+	// TraceParent is the W3C traceparent of a sampled row in the message,
+	// it is sent as a message header if it is not empty.
+	TraceParent string
+}
+
+// MessageHeader is a key-value pair attached to an MQ message.
+type MessageHeader struct {
+	Key   string
+	Value string
 }
 
 // maximumRecordOverhead is used to calculate ProducerMessage's byteSize by sarama kafka client.
@@ -57,11 +70,24 @@ type MQMessage struct {
 // for TiCDC, minimum supported kafka version is `0.11.0.2`, which will be treated as `version = 2` by sarama producer.
 const maximumRecordOverhead = 5*binary.MaxVarintLen32 + binary.MaxVarintLen64 + 1
 
-// Length returns the expected size of the Kafka message
-// We didn't append any `Headers` when send the message, so ignore the calculations related to it.
-// If `ProducerMessage` Headers fields used, this method should also adjust.
+// headerOverhead is the size of a record header except the key and value.
+const headerOverhead = 2 * binary.MaxVarintLen32
+
+// Length returns the expected size of the header in a Kafka record.
+func (h MessageHeader) Length() int {
+	return headerOverhead + len(h.Key) + len(h.Value)
+}
+
+// Length returns the expected size of the Kafka message, including the headers.
 func (m *MQMessage) Length() int {
-	return len(m.Key) + len(m.Value) + maximumRecordOverhead
+	length := len(m.Key) + len(m.Value) + maximumRecordOverhead
+	for _, header := range m.Headers {
+		length += header.Length()
+	}
+	if m.TraceParent != "" {
+		length += headerOverhead + len(tracing.TraceParentHeader) + len(m.TraceParent)
+	}
+	return length
 }
 
 // PhysicalTime returns physical time part of Ts in time.Time
@@ -164,6 +190,8 @@ func NewEventBatchEncoderBuilder(c *Config, credential *security.Credential) (En
 		return newCanalFlatEventBatchEncoderBuilder(c), nil
 	case config.ProtocolCraft:
 		return newCraftEventBatchEncoderBuilder(c), nil
+	case config.ProtocolState:
+		return newStateEventBatchEncoderBuilder(c), nil
 	default:
 		log.Warn("unknown codec protocol value of EventBatchEncoder, use open-protocol as the default", zap.Any("protocolValue", int(c.protocol)))
 		return newJSONEventBatchEncoderBuilder(c), nil
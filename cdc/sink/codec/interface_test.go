@@ -119,3 +119,13 @@ func (s *codecInterfaceSuite) TestCreate(c *check.C) {
 	c.Assert(msg.Table, check.IsNil)
 	c.Assert(msg.Protocol, check.Equals, config.ProtocolCanal)
 }
+
+func (s *codecInterfaceSuite) TestLengthWithHeaders(c *check.C) {
+	defer testleak.AfterTest(c)()
+	msg := NewMQMessage(config.ProtocolOpen, []byte("key1"), []byte("value1"), 1234, model.MqMessageTypeRow, nil, nil)
+	length := msg.Length()
+	c.Assert(length, check.Equals, len("key1")+len("value1")+maximumRecordOverhead)
+
+	msg.Headers = []MessageHeader{{Key: "ticdc-commit-ts", Value: "1234"}}
+	c.Assert(msg.Length(), check.Equals, length+headerOverhead+len("ticdc-commit-ts")+len("1234"))
+}
@@ -210,6 +210,10 @@ type mqMessageKey struct {
 	RowID     int64               `json:"rid,omitempty"`
 	Partition *int64              `json:"ptn,omitempty"`
 	Type      model.MqMessageType `json:"t"`
+	// StartTs and TxnIndex are the transaction metadata of a row,
+	// they are only set if the transaction metadata is enabled.
+	StartTs  uint64 `json:"sts,omitempty"`
+	TxnIndex int64  `json:"tidx,omitempty"`
 }
 
 func (m *mqMessageKey) Encode() ([]byte, error) {
@@ -285,6 +289,10 @@ func rowEventToMqMessage(e *model.RowChangedEvent) (*mqMessageKey, *mqMessageRow
 		Partition: partition,
 		Type:      model.MqMessageTypeRow,
 	}
+	if e.TxnIndex != 0 {
+		key.StartTs = e.StartTs
+		key.TxnIndex = e.TxnIndex
+	}
 	value := &mqMessageRow{}
 	if e.IsDelete() {
 		value.Delete = sinkColumns2JsonColumns(e.PreColumns)
@@ -328,8 +336,10 @@ func jsonColumns2SinkColumns(cols map[string]column) []*model.Column {
 
 func mqMessageToRowEvent(key *mqMessageKey, value *mqMessageRow) *model.RowChangedEvent {
 	e := new(model.RowChangedEvent)
-	// TODO: we lost the startTs from kafka message
-	// startTs-based txn filter is out of work
+	// TODO: we lost the startTs from kafka message unless the transaction
+	// metadata is enabled, startTs-based txn filter is out of work
+	e.StartTs = key.StartTs
+	e.TxnIndex = key.TxnIndex
 	e.CommitTs = key.Ts
 	e.Table = &model.TableName{
 		Schema: key.Schema,
@@ -249,6 +249,41 @@ func (s *batchSuite) TestDefaultEventBatchCodec(c *check.C) {
 	s.testBatchCodec(c, newJSONEventBatchEncoderBuilder(config), NewJSONEventBatchDecoder)
 }
 
+func (s *batchSuite) TestTxnMetadata(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	encoder := newJSONEventBatchEncoderBuilder(NewConfig(config.ProtocolOpen, timeutil.SystemLocation())).Build()
+	row := &model.RowChangedEvent{
+		StartTs:  1,
+		CommitTs: 2,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+	}
+	c.Assert(encoder.AppendRowChangedEvent(row), check.IsNil)
+	withTxn := *row
+	withTxn.TxnIndex = 3
+	c.Assert(encoder.AppendRowChangedEvent(&withTxn), check.IsNil)
+
+	messages := encoder.Build()
+	c.Assert(messages, check.HasLen, 1)
+	decoder, err := NewJSONEventBatchDecoder(messages[0].Key, messages[0].Value)
+	c.Assert(err, check.IsNil)
+	// The start ts is only sent with the transaction metadata.
+	for _, expected := range []struct {
+		startTs  uint64
+		txnIndex int64
+	}{{0, 0}, {1, 3}} {
+		_, hasNext, err := decoder.HasNext()
+		c.Assert(err, check.IsNil)
+		c.Assert(hasNext, check.IsTrue)
+		decoded, err := decoder.NextRowChangedEvent()
+		c.Assert(err, check.IsNil)
+		c.Assert(decoded.CommitTs, check.Equals, uint64(2))
+		c.Assert(decoded.StartTs, check.Equals, expected.startTs)
+		c.Assert(decoded.TxnIndex, check.Equals, expected.txnIndex)
+	}
+}
+
 var _ = check.Suite(&columnSuite{})
 
 type columnSuite struct{}
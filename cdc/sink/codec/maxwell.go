@@ -102,6 +102,9 @@ func rowEventToMaxwellMessage(e *model.RowChangedEvent) (*mqMessageKey, *maxwell
 	if e.IsDelete() {
 		value.Type = "delete"
 		for _, v := range e.PreColumns {
+			if v == nil {
+				continue
+			}
 			switch v.Type {
 			case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
 				if v.Value == nil {
@@ -117,6 +120,9 @@ func rowEventToMaxwellMessage(e *model.RowChangedEvent) (*mqMessageKey, *maxwell
 		}
 	} else {
 		for _, v := range e.Columns {
+			if v == nil {
+				continue
+			}
 			switch v.Type {
 			case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
 				if v.Value == nil {
@@ -135,6 +141,9 @@ func rowEventToMaxwellMessage(e *model.RowChangedEvent) (*mqMessageKey, *maxwell
 		} else {
 			value.Type = "update"
 			for _, v := range e.PreColumns {
+				if v == nil {
+					continue
+				}
 				switch v.Type {
 				case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
 					if v.Value == nil {
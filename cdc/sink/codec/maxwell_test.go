@@ -29,6 +29,15 @@ var _ = check.Suite(&maxwellbatchSuite{
 		CommitTs: 1,
 		Table:    &model.TableName{Schema: "a", Table: "b"},
 		Columns:  []*model.Column{{Name: "col1", Type: 3, Value: 10}},
+	}}, {{
+		CommitTs:   1,
+		Table:      &model.TableName{Schema: "a", Table: "b"},
+		PreColumns: []*model.Column{{Name: "col1", Type: 3, Value: 10}, nil},
+		Columns:    []*model.Column{{Name: "col1", Type: 3, Value: 10}, {Name: "col2", Type: 3, Value: 11}},
+	}, {
+		CommitTs:   1,
+		Table:      &model.TableName{Schema: "a", Table: "b"},
+		PreColumns: []*model.Column{{Name: "col1", Type: 3, Value: 10}, nil},
 	}}, {}},
 	ddlCases: [][]*model.DDLEvent{{{
 		CommitTs: 1,
@@ -0,0 +1,232 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// The types of the control messages of the state protocol.
+const (
+	stateControlTypeResolved = "resolved"
+	stateControlTypeDDL      = "ddl"
+)
+
+// stateRowKey is the key of a row message of the state protocol, which is a
+// canonical encoding of the handle key of the row, so the messages of a row
+// always have the same key and a log compacted topic keeps the latest one.
+type stateRowKey struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	// Handle maps the names of the handle key columns to the string
+	// representations of their values, the binary values are encoded
+	// in base64. The names are sorted by encoding/json.
+	Handle map[string]string `json:"handle"`
+}
+
+// stateRowValue is the full after-image of a row, the value of a deleted row
+// is null, which is a tombstone in a log compacted topic.
+type stateRowValue struct {
+	Schema   string             `json:"schema"`
+	Table    string             `json:"table"`
+	CommitTs uint64             `json:"commit-ts"`
+	Columns  map[string]*column `json:"columns"`
+}
+
+// stateControlKey is the key of a control message of the state protocol,
+// which is sent to the control topic instead of the topics of the rows.
+// The resolved messages share the same key, so a log compacted control
+// topic keeps the latest one, while the DDL messages are all kept.
+type stateControlKey struct {
+	Type     string `json:"type"`
+	CommitTs uint64 `json:"commit-ts,omitempty"`
+	Schema   string `json:"schema,omitempty"`
+	Table    string `json:"table,omitempty"`
+}
+
+type stateResolvedValue struct {
+	ResolvedTs uint64 `json:"resolved-ts"`
+}
+
+type stateDDLValue struct {
+	Query string             `json:"query"`
+	Type  timodel.ActionType `json:"type"`
+}
+
+func encodeState(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	return data, cerror.WrapError(cerror.ErrStateEncodeFailed, err)
+}
+
+func newStateRowKey(e *model.RowChangedEvent) (*stateRowKey, error) {
+	cols := e.Columns
+	if e.IsDelete() {
+		cols = e.PreColumns
+	}
+	handle := make(map[string]string)
+	for _, col := range cols {
+		if col == nil || !col.Flag.IsHandleKey() {
+			continue
+		}
+		if b, ok := col.Value.([]byte); ok && col.Flag.IsBinary() {
+			handle[col.Name] = base64.StdEncoding.EncodeToString(b)
+		} else {
+			handle[col.Name] = model.ColumnValueString(col.Value)
+		}
+	}
+	if len(handle) == 0 {
+		return nil, cerror.ErrStateEncodeFailed.GenWithStack(
+			"table %s has no handle key, which is required by the state protocol", e.Table)
+	}
+	return &stateRowKey{Schema: e.Table.Schema, Table: e.Table.Table, Handle: handle}, nil
+}
+
+func newStateRowValue(e *model.RowChangedEvent) *stateRowValue {
+	if e.IsDelete() {
+		return nil
+	}
+	value := &stateRowValue{
+		Schema:   e.Table.Schema,
+		Table:    e.Table.Table,
+		CommitTs: e.CommitTs,
+		Columns:  make(map[string]*column, len(e.Columns)),
+	}
+	for _, col := range e.Columns {
+		if col == nil {
+			continue
+		}
+		c := &column{}
+		c.FromSinkColumn(col)
+		value.Columns[col.Name] = c
+	}
+	return value
+}
+
+// StateEventBatchEncoder encodes the events into the state protocol, which
+// outputs the latest state of the rows for log compacted topics. Every row is
+// encoded into a message whose key is the handle key of the row and whose
+// value is the full after-image of the row, or null if the row is deleted.
+// The resolved ts and the DDLs are encoded into the control messages.
+type StateEventBatchEncoder struct {
+	messageBuf      []*MQMessage
+	maxMessageBytes int
+}
+
+// NewStateEventBatchEncoder creates a new StateEventBatchEncoder.
+func NewStateEventBatchEncoder() EventBatchEncoder {
+	return &StateEventBatchEncoder{maxMessageBytes: config.DefaultMaxMessageBytes}
+}
+
+// EncodeCheckpointEvent implements the EventBatchEncoder interface
+func (d *StateEventBatchEncoder) EncodeCheckpointEvent(ts uint64) (*MQMessage, error) {
+	key, err := encodeState(&stateControlKey{Type: stateControlTypeResolved})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	value, err := encodeState(&stateResolvedValue{ResolvedTs: ts})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newResolvedMQMessage(config.ProtocolState, key, value, ts), nil
+}
+
+// AppendRowChangedEvent implements the EventBatchEncoder interface
+func (d *StateEventBatchEncoder) AppendRowChangedEvent(e *model.RowChangedEvent) error {
+	keyMsg, err := newStateRowKey(e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key, err := encodeState(keyMsg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var value []byte
+	if valueMsg := newStateRowValue(e); valueMsg != nil {
+		value, err = encodeState(valueMsg)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	message := NewMQMessage(config.ProtocolState, key, value, e.CommitTs,
+		model.MqMessageTypeRow, &e.Table.Schema, &e.Table.Table)
+	message.IncRowsCount()
+	// The rows can't be split into smaller messages.
+	if message.Length() > d.maxMessageBytes {
+		log.Warn("Single message too large",
+			zap.Int("max-message-size", d.maxMessageBytes),
+			zap.Int("length", message.Length()), zap.Any("table", e.Table))
+		return cerror.ErrJSONCodecRowTooLarge.GenWithStackByArgs()
+	}
+	d.messageBuf = append(d.messageBuf, message)
+	return nil
+}
+
+// EncodeDDLEvent implements the EventBatchEncoder interface
+func (d *StateEventBatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*MQMessage, error) {
+	key, err := encodeState(&stateControlKey{
+		Type:     stateControlTypeDDL,
+		CommitTs: e.CommitTs,
+		Schema:   e.TableInfo.Schema,
+		Table:    e.TableInfo.Table,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	value, err := encodeState(&stateDDLValue{Query: e.Query, Type: e.Type})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newDDLMQMessage(config.ProtocolState, key, value, e), nil
+}
+
+// Build implements the EventBatchEncoder interface
+func (d *StateEventBatchEncoder) Build() []*MQMessage {
+	ret := d.messageBuf
+	d.messageBuf = nil
+	return ret
+}
+
+// Size implements the EventBatchEncoder interface
+func (d *StateEventBatchEncoder) Size() int {
+	size := 0
+	for _, message := range d.messageBuf {
+		size += message.Length()
+	}
+	return size
+}
+
+type stateEventBatchEncoderBuilder struct {
+	config *Config
+}
+
+// Build a StateEventBatchEncoder
+func (b *stateEventBatchEncoderBuilder) Build() EventBatchEncoder {
+	encoder := NewStateEventBatchEncoder()
+	encoder.(*StateEventBatchEncoder).maxMessageBytes = b.config.maxMessageBytes
+	return encoder
+}
+
+func newStateEventBatchEncoderBuilder(config *Config) EncoderBuilder {
+	return &stateEventBatchEncoderBuilder{config: config}
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"github.com/pingcap/check"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util/testleak"
+)
+
+type stateSuite struct{}
+
+var _ = check.Suite(&stateSuite{})
+
+func newStateTestRow(pre, post []interface{}) *model.RowChangedEvent {
+	buildColumns := func(values []interface{}) []*model.Column {
+		if values == nil {
+			return nil
+		}
+		return []*model.Column{
+			{
+				Name: "id", Type: mysql.TypeLong,
+				Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: values[0],
+			},
+			{Name: "name", Type: mysql.TypeVarchar, Value: values[1]},
+		}
+	}
+	return &model.RowChangedEvent{
+		CommitTs:   10,
+		Table:      &model.TableName{Schema: "a", Table: "b"},
+		PreColumns: buildColumns(pre),
+		Columns:    buildColumns(post),
+	}
+}
+
+func (s *stateSuite) TestStateRowMessages(c *check.C) {
+	defer testleak.AfterTest(c)()
+	encoder := NewStateEventBatchEncoder()
+
+	rows := []*model.RowChangedEvent{
+		newStateTestRow(nil, []interface{}{1, []byte("a")}),
+		newStateTestRow([]interface{}{1, []byte("a")}, []interface{}{1, []byte("b")}),
+		newStateTestRow([]interface{}{1, []byte("b")}, nil),
+	}
+	for _, row := range rows {
+		c.Assert(encoder.AppendRowChangedEvent(row), check.IsNil)
+	}
+	c.Assert(encoder.Size(), check.Greater, 0)
+	messages := encoder.Build()
+	c.Assert(messages, check.HasLen, 3)
+	c.Assert(encoder.Build(), check.HasLen, 0)
+
+	expectedKey := `{"schema":"a","table":"b","handle":{"id":"1"}}`
+	for _, message := range messages {
+		// All the messages of a row have the same key.
+		c.Assert(string(message.Key), check.Equals, expectedKey)
+		c.Assert(message.GetRowsCount(), check.Equals, 1)
+		c.Assert(message.Type, check.Equals, model.MqMessageTypeRow)
+		c.Assert(message.Protocol, check.Equals, config.ProtocolState)
+	}
+	c.Assert(string(messages[0].Value), check.Equals, `{"schema":"a","table":"b","commit-ts":10,"columns":`+
+		`{"id":{"t":3,"h":true,"f":10,"v":1},"name":{"t":15,"f":0,"v":"a"}}}`)
+	c.Assert(string(messages[1].Value), check.Equals, `{"schema":"a","table":"b","commit-ts":10,"columns":`+
+		`{"id":{"t":3,"h":true,"f":10,"v":1},"name":{"t":15,"f":0,"v":"b"}}}`)
+	// The deleted row is a tombstone.
+	c.Assert(messages[2].Value, check.IsNil)
+
+	// The binary values of the handle key are encoded in base64.
+	row := newStateTestRow(nil, []interface{}{[]byte{0xff}, []byte("a")})
+	row.Columns[0].Flag |= model.BinaryFlag
+	c.Assert(encoder.AppendRowChangedEvent(row), check.IsNil)
+	messages = encoder.Build()
+	c.Assert(messages, check.HasLen, 1)
+	c.Assert(string(messages[0].Key), check.Equals, `{"schema":"a","table":"b","handle":{"id":"/w=="}}`)
+
+	// The rows without handle key can't be encoded.
+	row = newStateTestRow(nil, []interface{}{1, []byte("a")})
+	row.Columns[0].Flag = 0
+	c.Assert(encoder.AppendRowChangedEvent(row), check.ErrorMatches, ".*has no handle key.*")
+
+	// The rows larger than max-message-bytes can't be encoded.
+	encoder = newStateEventBatchEncoderBuilder(
+		NewConfig(config.ProtocolState, nil).WithMaxMessageBytes(100)).Build()
+	err := encoder.AppendRowChangedEvent(newStateTestRow(nil, []interface{}{1, []byte("a")}))
+	c.Assert(err, check.ErrorMatches, ".*single row too large.*")
+}
+
+func (s *stateSuite) TestStateControlMessages(c *check.C) {
+	defer testleak.AfterTest(c)()
+	encoder := NewStateEventBatchEncoder()
+
+	message, err := encoder.EncodeCheckpointEvent(100)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(message.Key), check.Equals, `{"type":"resolved"}`)
+	c.Assert(string(message.Value), check.Equals, `{"resolved-ts":100}`)
+	c.Assert(message.Type, check.Equals, model.MqMessageTypeResolved)
+
+	message, err = encoder.EncodeDDLEvent(&model.DDLEvent{
+		CommitTs:  200,
+		TableInfo: &model.SimpleTableInfo{Schema: "a", Table: "b"},
+		Query:     "create table a.b(id int primary key)",
+		Type:      timodel.ActionCreateTable,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(string(message.Key), check.Equals,
+		`{"type":"ddl","commit-ts":200,"schema":"a","table":"b"}`)
+	c.Assert(string(message.Value), check.Equals,
+		`{"query":"create table a.b(id int primary key)","type":3}`)
+	c.Assert(message.Type, check.Equals, model.MqMessageTypeDDL)
+}
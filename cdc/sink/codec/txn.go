@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// txnEventKey is the key of a transaction marker, the markers of a
+// transaction share the same key.
+type txnEventKey struct {
+	StartTs uint64 `json:"start-ts"`
+}
+
+// txnEventValue is the value of a transaction marker, it is encoded in JSON
+// whatever the protocol of the rows is.
+type txnEventValue struct {
+	Status         model.TxnEventType `json:"status"`
+	StartTs        uint64             `json:"start-ts"`
+	CommitTs       uint64             `json:"commit-ts"`
+	RowCount       int64              `json:"row-count,omitempty"`
+	TableRowCounts map[string]int64   `json:"table-row-counts,omitempty"`
+}
+
+// EncodeTxnEvent encodes a BEGIN or COMMIT marker of a transaction, which is
+// sent to the transaction topic.
+func EncodeTxnEvent(protocol config.Protocol, e *model.TxnEvent) (*MQMessage, error) {
+	key, err := json.Marshal(&txnEventKey{StartTs: e.StartTs})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	value, err := json.Marshal(&txnEventValue{
+		Status:         e.Type,
+		StartTs:        e.StartTs,
+		CommitTs:       e.CommitTs,
+		RowCount:       e.RowCount,
+		TableRowCounts: e.TableRowCounts,
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return NewMQMessage(protocol, key, value, e.CommitTs, model.MqMessageTypeTxn, nil, nil), nil
+}
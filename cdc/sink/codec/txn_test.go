@@ -0,0 +1,44 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeTxnEvent(t *testing.T) {
+	msg, err := EncodeTxnEvent(config.ProtocolOpen, &model.TxnEvent{
+		Type: model.TxnEventTypeBegin, StartTs: 100, CommitTs: 110,
+	})
+	require.Nil(t, err)
+	require.Equal(t, `{"start-ts":100}`, string(msg.Key))
+	require.Equal(t, `{"status":"BEGIN","start-ts":100,"commit-ts":110}`, string(msg.Value))
+	require.Equal(t, model.MqMessageTypeTxn, msg.Type)
+	require.Equal(t, uint64(110), msg.Ts)
+	require.Nil(t, msg.Schema)
+	require.Nil(t, msg.Table)
+
+	msg, err = EncodeTxnEvent(config.ProtocolOpen, &model.TxnEvent{
+		Type: model.TxnEventTypeCommit, StartTs: 100, CommitTs: 110, RowCount: 3,
+		TableRowCounts: map[string]int64{"a.b": 2, "a.c": 1},
+	})
+	require.Nil(t, err)
+	require.Equal(t, `{"start-ts":100}`, string(msg.Key))
+	require.Equal(t, `{"status":"COMMIT","start-ts":100,"commit-ts":110,"row-count":3,`+
+		`"table-row-counts":{"a.b":2,"a.c":1}}`, string(msg.Value))
+}
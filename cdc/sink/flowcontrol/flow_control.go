@@ -14,6 +14,7 @@
 package flowcontrol
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"go.uber.org/zap"
 )
 
@@ -141,6 +143,9 @@ func (c *TableMemoryQuota) GetConsumption() uint64 {
 // TableFlowController provides a convenient interface to control the memory consumption of a per table event stream
 type TableFlowController struct {
 	memoryQuota *TableMemoryQuota
+	// governedQuota reserves memory from the capture wide memory governor,
+	// it may be nil.
+	governedQuota *memquota.Quota
 
 	mu    sync.Mutex
 	queue deque.Deque
@@ -154,10 +159,11 @@ type commitTsSizeEntry struct {
 }
 
 // NewTableFlowController creates a new TableFlowController
-func NewTableFlowController(quota uint64) *TableFlowController {
+func NewTableFlowController(quota uint64, governedQuota *memquota.Quota) *TableFlowController {
 	return &TableFlowController{
-		memoryQuota: NewTableMemoryQuota(quota),
-		queue:       deque.NewDeque(),
+		memoryQuota:   NewTableMemoryQuota(quota),
+		governedQuota: governedQuota,
+		queue:         deque.NewDeque(),
 	}
 }
 
@@ -178,6 +184,16 @@ func (c *TableFlowController) Consume(commitTs uint64, size uint64, blockCallBac
 		if err != nil {
 			return errors.Trace(err)
 		}
+		// The governed quota is closed by Abort, there is no need to
+		// cancel it with a context.
+		err = c.governedQuota.Reserve(context.Background(), size, blockCallBack)
+		if err != nil {
+			c.memoryQuota.Release(size)
+			if cerrors.ErrMemoryQuotaClosed.Equal(err) {
+				return cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
+			}
+			return errors.Trace(err)
+		}
 	} else {
 		// Here commitTs == lastCommitTs, which means that we are not crossing
 		// a transaction boundary. In this situation, we use `ForceConsume` because
@@ -188,6 +204,7 @@ func (c *TableFlowController) Consume(commitTs uint64, size uint64, blockCallBac
 		if err != nil {
 			return errors.Trace(err)
 		}
+		c.governedQuota.ForceReserve(size)
 	}
 
 	c.mu.Lock()
@@ -216,11 +233,13 @@ func (c *TableFlowController) Release(resolvedTs uint64) {
 	c.mu.Unlock()
 
 	c.memoryQuota.Release(nBytesToRelease)
+	c.governedQuota.Release(nBytesToRelease)
 }
 
 // Abort interrupts any ongoing Consume call
 func (c *TableFlowController) Abort() {
 	c.memoryQuota.Abort()
+	c.governedQuota.Close()
 }
 
 // GetConsumption returns the current memory consumption
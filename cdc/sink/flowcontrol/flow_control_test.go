@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pingcap/check"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/util/testleak"
 	"golang.org/x/sync/errgroup"
 )
@@ -178,7 +179,7 @@ func (s *flowControlSuite) TestFlowControlBasic(c *check.C) {
 	defer cancel()
 	errg, ctx := errgroup.WithContext(ctx)
 	mockedRowsCh := make(chan *commitTsSizeEntry, 1024)
-	flowController := NewTableFlowController(2048)
+	flowController := NewTableFlowController(2048, nil)
 
 	errg.Go(func() error {
 		lastCommitTs := uint64(1)
@@ -288,7 +289,7 @@ func (s *flowControlSuite) TestFlowControlAbort(c *check.C) {
 	defer testleak.AfterTest(c)()
 
 	callBacker := &mockCallBacker{}
-	controller := NewTableFlowController(1024)
+	controller := NewTableFlowController(1024, nil)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -318,7 +319,7 @@ func (s *flowControlSuite) TestFlowControlCallBack(c *check.C) {
 	defer cancel()
 	errg, ctx := errgroup.WithContext(ctx)
 	mockedRowsCh := make(chan *commitTsSizeEntry, 1024)
-	flowController := NewTableFlowController(512)
+	flowController := NewTableFlowController(512, nil)
 
 	errg.Go(func() error {
 		lastCommitTs := uint64(1)
@@ -421,7 +422,7 @@ func (s *flowControlSuite) TestFlowControlCallBackNotBlockingRelease(c *check.C)
 	defer testleak.AfterTest(c)()
 
 	var wg sync.WaitGroup
-	controller := NewTableFlowController(512)
+	controller := NewTableFlowController(512, nil)
 	wg.Add(1)
 
 	ctx, cancel := context.WithCancel(context.TODO())
@@ -463,7 +464,7 @@ func (s *flowControlSuite) TestFlowControlCallBackError(c *check.C) {
 	defer testleak.AfterTest(c)()
 
 	var wg sync.WaitGroup
-	controller := NewTableFlowController(512)
+	controller := NewTableFlowController(512, nil)
 	wg.Add(1)
 
 	ctx, cancel := context.WithCancel(context.TODO())
@@ -492,7 +493,7 @@ func (s *flowControlSuite) TestFlowControlCallBackError(c *check.C) {
 func (s *flowControlSuite) TestFlowControlConsumeLargerThanQuota(c *check.C) {
 	defer testleak.AfterTest(c)()
 
-	controller := NewTableFlowController(1024)
+	controller := NewTableFlowController(1024, nil)
 	err := controller.Consume(1, 2048, func() error {
 		c.Fatalf("unreachable")
 		return nil
@@ -500,12 +501,47 @@ func (s *flowControlSuite) TestFlowControlConsumeLargerThanQuota(c *check.C) {
 	c.Assert(err, check.ErrorMatches, ".*ErrFlowControllerEventLargerThanQuota.*")
 }
 
+func (s *flowControlSuite) TestFlowControlWithGovernedQuota(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	governor := memquota.NewGovernor(1024)
+	quota := governor.NewQuota("test-governed-quota", memquota.ComponentSink)
+	controller := NewTableFlowController(2048, quota)
+	c.Assert(controller.Consume(1, 512, dummyCallBack), check.IsNil)
+	c.Assert(controller.Consume(1, 1024, dummyCallBack), check.IsNil)
+	c.Assert(governor.Used(), check.Equals, uint64(1536))
+
+	controller.Release(1)
+	c.Assert(governor.Used(), check.Equals, uint64(0))
+	c.Assert(controller.GetConsumption(), check.Equals, uint64(0))
+
+	// The capture wide memory is exhausted by another table.
+	other := governor.NewQuota("test-governed-quota-other", memquota.ComponentSink)
+	defer other.Close()
+	other.ForceReserve(1024)
+	c.Assert(controller.Consume(2, 512, dummyCallBack), check.IsNil)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- controller.Consume(3, 512, dummyCallBack)
+	}()
+	select {
+	case <-errCh:
+		c.Fatal("consume must be blocked")
+	case <-time.After(100 * time.Millisecond):
+	}
+	controller.Abort()
+	c.Assert(<-errCh, check.ErrorMatches, ".*ErrFlowControllerAborted.*")
+	c.Assert(controller.GetConsumption(), check.Equals, uint64(512))
+	c.Assert(governor.Used(), check.Equals, uint64(1024))
+}
+
 func BenchmarkTableFlowController(B *testing.B) {
 	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*5)
 	defer cancel()
 	errg, ctx := errgroup.WithContext(ctx)
 	mockedRowsCh := make(chan *commitTsSizeEntry, 102400)
-	flowController := NewTableFlowController(20 * 1024 * 1024) // 20M
+	flowController := NewTableFlowController(20*1024*1024, nil) // 20M
 
 	errg.Go(func() error {
 		lastCommitTs := uint64(1)
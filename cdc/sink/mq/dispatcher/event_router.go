@@ -14,13 +14,19 @@
 package dispatcher
 
 import (
+	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/parser/model"
 	filter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher/partition"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher/topic"
+	"github.com/pingcap/tiflow/cdc/sink/transform"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
@@ -45,6 +51,7 @@ const (
 	partitionDispatchRuleTS
 	partitionDispatchRuleTable
 	partitionDispatchRuleIndexValue
+	partitionDispatchRuleColumns
 )
 
 func (r *partitionDispatchRule) fromString(rule string) {
@@ -59,21 +66,48 @@ func (r *partitionDispatchRule) fromString(rule string) {
 		*r = partitionDispatchRuleTable
 	case "index-value":
 		*r = partitionDispatchRuleIndexValue
+	case config.PartitionRuleColumns:
+		*r = partitionDispatchRuleColumns
 	default:
 		*r = partitionDispatchRuleDefault
 		log.Warn("can't support dispatch rule, using default rule", zap.String("rule", rule))
 	}
 }
 
+// columnTopicTTL is how long a topic which the rows are dispatched to by the
+// column values stays active after the last row is dispatched to it.
+const columnTopicTTL = 10 * time.Minute
+
 // EventRouter is a router, it determines which topic and which partition
 // an event should be dispatched to.
+// The dispatch rules are matched against the source table names, while the
+// topic expressions and the partition dispatchers see the routed ones.
 type EventRouter struct {
 	defaultTopic string
-	rules        []struct {
-		partitionDispatcher partition.Dispatcher
-		topicDispatcher     topic.Dispatcher
-		filter.Filter
-	}
+	// router routes the table names according to the route rules,
+	// it is nil if no route rule is configured.
+	router         *transform.Router
+	ddlTransformer *transform.DDLTransformer
+	rules          []dispatchRule
+	// columnTopics records the last time when the rows are dispatched to the
+	// topics by the column values, they are active topics as well until they
+	// have been idle for columnTopicTTL, so that the number of the recorded
+	// topics is bounded by the topics used in a TTL.
+	columnTopicsMu sync.Mutex
+	columnTopics   map[string]time.Time
+	lastExpireTime time.Time
+	// We use a `clock.Clock` here to make time mockable in unit tests.
+	clock clock.Clock
+}
+
+type dispatchRule struct {
+	partitionDispatcher partition.Dispatcher
+	topicDispatcher     topic.Dispatcher
+	// columns are the columns used by the dispatchers.
+	columns []string
+	// topicConfig is used to create the topics of the rule, it may be nil.
+	topicConfig *config.TopicConfig
+	filter.Filter
 }
 
 // NewEventRouter creates a new EventRouter
@@ -86,11 +120,7 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 		PartitionRule: "default",
 		TopicRule:     "",
 	})
-	rules := make([]struct {
-		partitionDispatcher partition.Dispatcher
-		topicDispatcher     topic.Dispatcher
-		filter.Filter
-	}, 0, len(ruleConfigs))
+	rules := make([]dispatchRule, 0, len(ruleConfigs))
 
 	for _, ruleConfig := range ruleConfigs {
 		f, err := filter.Parse(ruleConfig.Matcher)
@@ -106,25 +136,89 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 		if err != nil {
 			return nil, err
 		}
-		rules = append(rules, struct {
-			partitionDispatcher partition.Dispatcher
-			topicDispatcher     topic.Dispatcher
-			filter.Filter
-		}{partitionDispatcher: d, topicDispatcher: t, Filter: f})
+		columns := append([]string{}, ruleConfig.Columns...)
+		if columnDispatcher, ok := t.(*topic.ColumnTopicDispatcher); ok {
+			columns = append(columns, columnDispatcher.Columns()...)
+		}
+		rules = append(rules, dispatchRule{
+			partitionDispatcher: d, topicDispatcher: t, columns: columns,
+			topicConfig: ruleConfig.TopicConfig, Filter: f,
+		})
+	}
+
+	router, err := transform.NewRouter(cfg.CaseSensitive, cfg.Sink.Routes)
+	if err != nil {
+		return nil, err
+	}
+	ddlTransformer, err := transform.NewDDLTransformer(router, nil)
+	if err != nil {
+		return nil, err
 	}
 	return &EventRouter{
-		defaultTopic: defaultTopic,
-		rules:        rules,
+		defaultTopic:   defaultTopic,
+		router:         router,
+		ddlTransformer: ddlTransformer,
+		rules:          rules,
+		columnTopics:   make(map[string]time.Time),
+		clock:          clock.New(),
 	}, nil
 }
 
+// RouteRowChange returns the row changed event with the routed table name,
+// which should be used to encode the messages.
+func (s *EventRouter) RouteRowChange(row *model.RowChangedEvent) *model.RowChangedEvent {
+	return s.router.RouteRowChangedEvent(row)
+}
+
+// RouteDDL returns the DDL event with the routed table names,
+// which should be used to encode the messages.
+func (s *EventRouter) RouteDDL(ddl *model.DDLEvent) (*model.DDLEvent, error) {
+	routed, _, err := s.ddlTransformer.Transform(ddl)
+	return routed, err
+}
+
 // GetTopicForRowChange returns the target topic for row changes.
-func (s *EventRouter) GetTopicForRowChange(row *model.RowChangedEvent) string {
+// An error is returned if the topic name converted from the column values
+// of the row is invalid.
+func (s *EventRouter) GetTopicForRowChange(row *model.RowChangedEvent) (string, error) {
 	topicDispatcher, _ := s.matchDispatcher(row.Table.Schema, row.Table.Table)
-	return topicDispatcher.Substitute(row.Table.Schema, row.Table.Table)
+	schema, table := s.router.Route(row.Table.Schema, row.Table.Table)
+	if columnDispatcher, ok := topicDispatcher.(*topic.ColumnTopicDispatcher); ok {
+		topicName, err := columnDispatcher.SubstituteRow(schema, table, row)
+		if err != nil {
+			return "", err
+		}
+		s.touchColumnTopic(topicName)
+		return topicName, nil
+	}
+	return topicDispatcher.Substitute(schema, table), nil
+}
+
+// touchColumnTopic records that a row is dispatched to the topic by the
+// column values, the idle topics are expired at most once per TTL.
+func (s *EventRouter) touchColumnTopic(topicName string) {
+	now := s.clock.Now()
+	s.columnTopicsMu.Lock()
+	defer s.columnTopicsMu.Unlock()
+	s.columnTopics[topicName] = now
+	if now.Sub(s.lastExpireTime) >= columnTopicTTL {
+		s.expireColumnTopics(now)
+	}
+}
+
+// expireColumnTopics removes the topics which have been idle for
+// columnTopicTTL, it must be called with columnTopicsMu held.
+func (s *EventRouter) expireColumnTopics(now time.Time) {
+	for topicName, lastUsed := range s.columnTopics {
+		if now.Sub(lastUsed) >= columnTopicTTL {
+			delete(s.columnTopics, topicName)
+		}
+	}
+	s.lastExpireTime = now
 }
 
 // GetTopicForDDL returns the target topic for DDL.
+// The DDLs of the tables dispatched by column values go to the default topic.
 // FIXME: Now we can't handle rename tables because
 // we are missing the old and new tables information.
 func (s *EventRouter) GetTopicForDDL(ddl *model.DDLEvent) string {
@@ -144,7 +238,7 @@ func (s *EventRouter) GetTopicForDDL(ddl *model.DDLEvent) string {
 	}
 
 	topicDispatcher, _ := s.matchDispatcher(schema, table)
-	return topicDispatcher.Substitute(schema, table)
+	return topicDispatcher.Substitute(s.router.Route(schema, table))
 }
 
 // GetPartitionForRowChange returns the target partition for row changes.
@@ -152,13 +246,22 @@ func (s *EventRouter) GetPartitionForRowChange(
 	row *model.RowChangedEvent,
 	partitionNum int32,
 ) int32 {
+	_, partition := s.RouteRowChangeToPartition(row, partitionNum)
+	return partition
+}
+
+// RouteRowChangeToPartition returns the row changed event with the routed
+// table name along with its target partition, the row is routed only once
+// for both of them.
+func (s *EventRouter) RouteRowChangeToPartition(
+	row *model.RowChangedEvent,
+	partitionNum int32,
+) (*model.RowChangedEvent, int32) {
 	_, partitionDispatcher := s.matchDispatcher(
 		row.Table.Schema, row.Table.Table,
 	)
-
-	return partitionDispatcher.DispatchRowChangedEvent(
-		row, partitionNum,
-	)
+	routed := s.router.RouteRowChangedEvent(row)
+	return routed, partitionDispatcher.DispatchRowChangedEvent(routed, partitionNum)
 }
 
 // GetDLLDispatchRuleByProtocol returns the DDL
@@ -173,13 +276,15 @@ func (s *EventRouter) GetDLLDispatchRuleByProtocol(
 }
 
 // GetActiveTopics returns a list of the corresponding topics
-// for the tables that are actively synchronized.
+// for the tables that are actively synchronized, including the topics
+// which the rows have been dispatched to by the column values in the
+// last columnTopicTTL.
 func (s *EventRouter) GetActiveTopics(activeTables []model.TableName) []string {
 	topics := make([]string, 0)
 	topicsMap := make(map[string]bool, len(activeTables))
 	for _, table := range activeTables {
 		topicDispatcher, _ := s.matchDispatcher(table.Schema, table.Table)
-		topicName := topicDispatcher.Substitute(table.Schema, table.Table)
+		topicName := topicDispatcher.Substitute(s.router.Route(table.Schema, table.Table))
 		if topicName == s.defaultTopic {
 			log.Debug("topic name corresponding to the table is the same as the default topic name",
 				zap.String("table", table.String()),
@@ -193,6 +298,16 @@ func (s *EventRouter) GetActiveTopics(activeTables []model.TableName) []string {
 		}
 	}
 
+	s.columnTopicsMu.Lock()
+	s.expireColumnTopics(s.clock.Now())
+	for topicName := range s.columnTopics {
+		if !topicsMap[topicName] {
+			topicsMap[topicName] = true
+			topics = append(topics, topicName)
+		}
+	}
+	s.columnTopicsMu.Unlock()
+
 	// We also need to add the default topic.
 	if !topicsMap[s.defaultTopic] {
 		topics = append(topics, s.defaultTopic)
@@ -206,19 +321,70 @@ func (s *EventRouter) GetDefaultTopic() string {
 	return s.defaultTopic
 }
 
+// GetTopicConfig returns the configurations used to create a topic, which
+// are the ones of the first dispatch rule whose topics may include the topic.
+// It returns nil if no such rule has topic configurations.
+func (s *EventRouter) GetTopicConfig(topicName string) *config.TopicConfig {
+	for _, rule := range s.rules {
+		if rule.topicDispatcher.Match(topicName) {
+			if rule.topicConfig != nil {
+				return rule.topicConfig
+			}
+			// The rules without topic configurations use the default ones,
+			// rather than the ones of the latter rules.
+			return nil
+		}
+	}
+	return nil
+}
+
+// VerifyColumns checks whether the columns used by the dispatch rule of a
+// table exist. A warning is logged if a column isn't a part of the handle
+// key, since its value may be updated, in which case the changes of a row
+// may be dispatched to different partitions or topics and lose their order.
+func (s *EventRouter) VerifyColumns(tableInfo *model.TableInfo) error {
+	rule := s.matchRule(tableInfo.TableName.Schema, tableInfo.TableName.Table)
+	for _, name := range rule.columns {
+		var colInfo *timodel.ColumnInfo
+		for _, col := range tableInfo.Columns {
+			if col.Name.L == strings.ToLower(name) {
+				colInfo = col
+				break
+			}
+		}
+		if colInfo == nil {
+			return cerror.ErrDispatchRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("column %s doesn't exist in table %s", name, tableInfo.TableName))
+		}
+		flag := tableInfo.ColumnsFlag[colInfo.ID]
+		if !flag.IsHandleKey() {
+			log.Warn("the column used by the dispatch rule isn't a part of the handle key, "+
+				"the changes of a row may be dispatched to different partitions or topics "+
+				"and lose their order if the column is updated",
+				zap.String("table", tableInfo.TableName.String()),
+				zap.String("column", name))
+		}
+	}
+	return nil
+}
+
 // matchDispatcher returns the target topic dispatcher and partition dispatcher if a
 // row changed event matches a specific table filter.
 func (s *EventRouter) matchDispatcher(
 	schema, table string,
 ) (topic.Dispatcher, partition.Dispatcher) {
-	for _, rule := range s.rules {
-		if !rule.MatchTable(schema, table) {
-			continue
+	rule := s.matchRule(schema, table)
+	return rule.topicDispatcher, rule.partitionDispatcher
+}
+
+func (s *EventRouter) matchRule(schema, table string) *dispatchRule {
+	for i := range s.rules {
+		if s.rules[i].MatchTable(schema, table) {
+			return &s.rules[i]
 		}
-		return rule.topicDispatcher, rule.partitionDispatcher
 	}
 	log.Panic("the dispatch rule must cover all tables")
-	return nil, nil
+	return nil
 }
 
 // getPartitionDispatcher returns the partition dispatcher for a specific partition rule.
@@ -238,6 +404,8 @@ func getPartitionDispatcher(
 				"switching on the old value, so please use caution!")
 		}
 		d = partition.NewIndexValueDispatcher()
+	case partitionDispatchRuleColumns:
+		d = partition.NewColumnsDispatcher(ruleConfig.Columns)
 	case partitionDispatchRuleTS:
 		d = partition.NewTsDispatcher()
 	case partitionDispatchRuleTable:
@@ -263,5 +431,8 @@ func getTopicDispatcher(
 	if err != nil {
 		return nil, err
 	}
+	if topicExpr.HasColumns() {
+		return topic.NewColumnTopicDispatcher(topicExpr, defaultTopic), nil
+	}
 	return topic.NewDynamicTopicDispatcher(topicExpr), nil
 }
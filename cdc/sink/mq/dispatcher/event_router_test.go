@@ -16,6 +16,7 @@ package dispatcher
 import (
 	"testing"
 
+	"github.com/benbjohnson/clock"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher/partition"
@@ -187,25 +188,30 @@ func TestGetTopicForRowChange(t *testing.T) {
 	}, "test")
 	require.Nil(t, err)
 
-	topicName := d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err := d.GetTopicForRowChange(&model.RowChangedEvent{
 		Table: &model.TableName{Schema: "test_default1", Table: "table"},
 	})
+	require.Nil(t, err)
 	require.Equal(t, "test", topicName)
-	topicName = d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
 		Table: &model.TableName{Schema: "test_default2", Table: "table"},
 	})
+	require.Nil(t, err)
 	require.Equal(t, "test", topicName)
-	topicName = d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
 		Table: &model.TableName{Schema: "test_table", Table: "table"},
 	})
+	require.Nil(t, err)
 	require.Equal(t, "hello_test_table_world", topicName)
-	topicName = d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
 		Table: &model.TableName{Schema: "test_index_value", Table: "table"},
 	})
+	require.Nil(t, err)
 	require.Equal(t, "test_index_value_world", topicName)
-	topicName = d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
 		Table: &model.TableName{Schema: "a", Table: "table"},
 	})
+	require.Nil(t, err)
 	require.Equal(t, "a_table", topicName)
 }
 
@@ -459,3 +465,174 @@ func TestGetTopicForDDL(t *testing.T) {
 		require.Equal(t, test.expectedTopic, d.GetTopicForDDL(test.ddl))
 	}
 }
+
+func TestEventRouterWithRoutes(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:       []string{"sharding_*.*"},
+					PartitionRule: "table",
+					TopicRule:     "{schema}_{table}",
+				},
+			},
+			Routes: []*config.RouteRule{
+				{
+					Matcher:      []string{"sharding_*.t_*"},
+					TargetSchema: "merged",
+					TargetTable:  "t",
+				},
+			},
+		},
+	}, "test")
+	require.Nil(t, err)
+
+	// The dispatch rules match the source table names,
+	// while the topic expressions use the routed ones.
+	row := &model.RowChangedEvent{
+		Table: &model.TableName{Schema: "sharding_1", Table: "t_1"},
+	}
+	topicName, err := d.GetTopicForRowChange(row)
+	require.Nil(t, err)
+	require.Equal(t, "merged_t", topicName)
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
+		Table: &model.TableName{Schema: "sharding_1", Table: "other"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "sharding_1_other", topicName)
+	require.Equal(t, []string{"merged_t", "test"},
+		d.GetActiveTopics([]model.TableName{
+			{Schema: "sharding_1", Table: "t_1"},
+			{Schema: "sharding_2", Table: "t_2"},
+		}))
+
+	// The rows of the merged tables are dispatched by the routed table name.
+	p1 := d.GetPartitionForRowChange(row, 16)
+	p2 := d.GetPartitionForRowChange(&model.RowChangedEvent{
+		Table: &model.TableName{Schema: "sharding_2", Table: "t_2"},
+	}, 16)
+	require.Equal(t, p1, p2)
+
+	routedRow := d.RouteRowChange(row)
+	require.Equal(t, &model.TableName{Schema: "merged", Table: "t"}, routedRow.Table)
+	require.Equal(t, "sharding_1", row.Table.Schema)
+	routedRow, p := d.RouteRowChangeToPartition(row, 16)
+	require.Equal(t, &model.TableName{Schema: "merged", Table: "t"}, routedRow.Table)
+	require.Equal(t, p1, p)
+	require.Equal(t, "sharding_1", row.Table.Schema)
+
+	ddl := &model.DDLEvent{
+		Query:     "ALTER TABLE `sharding_1`.`t_1` ADD COLUMN `a` INT",
+		Type:      timodel.ActionAddColumn,
+		TableInfo: &model.SimpleTableInfo{Schema: "sharding_1", Table: "t_1"},
+	}
+	require.Equal(t, "merged_t", d.GetTopicForDDL(ddl))
+	routedDDL, err := d.RouteDDL(ddl)
+	require.Nil(t, err)
+	require.Equal(t, "ALTER TABLE `merged`.`t` ADD COLUMN `a` INT", routedDDL.Query)
+	require.Equal(t, "merged", routedDDL.TableInfo.Schema)
+	require.Equal(t, "t", routedDDL.TableInfo.Table)
+}
+
+func TestEventRouterWithColumns(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:       []string{"test.*"},
+					PartitionRule: "columns",
+					Columns:       []string{"tenant"},
+					TopicRule:     "{schema}_{col:region}",
+				},
+			},
+		},
+	}, "test")
+	require.Nil(t, err)
+	mockClock := clock.NewMock()
+	d.clock = mockClock
+
+	newRow := func(table, tenant, region string) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: table},
+			Columns: []*model.Column{
+				{Name: "id", Value: 1, Flag: model.HandleKeyFlag},
+				{Name: "tenant", Value: tenant},
+				{Name: "region", Value: region},
+			},
+		}
+	}
+	topicName, err := d.GetTopicForRowChange(newRow("t1", "a", "eu"))
+	require.Nil(t, err)
+	require.Equal(t, "test_eu", topicName)
+	topicName, err = d.GetTopicForRowChange(newRow("t2", "b", "us"))
+	require.Nil(t, err)
+	require.Equal(t, "test_us", topicName)
+	require.Equal(t, d.GetPartitionForRowChange(newRow("t1", "a", "eu"), 16),
+		d.GetPartitionForRowChange(newRow("t2", "a", "us"), 16))
+
+	// The DDLs go to the default topic, and the checkpoints go to all the
+	// topics which the rows have been dispatched to.
+	ddl := &model.DDLEvent{
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+	}
+	require.Equal(t, "test", d.GetTopicForDDL(ddl))
+	require.ElementsMatch(t, []string{"test", "test_eu", "test_us"},
+		d.GetActiveTopics([]model.TableName{{Schema: "test", Table: "t1"}}))
+
+	// The topics expire once they have been idle for the TTL.
+	_, err = d.GetTopicForRowChange(newRow("t1", "a", "eu"))
+	require.Nil(t, err)
+	mockClock.Add(columnTopicTTL / 2)
+	_, err = d.GetTopicForRowChange(newRow("t1", "a", "ap"))
+	require.Nil(t, err)
+	mockClock.Add(columnTopicTTL / 2)
+	require.ElementsMatch(t, []string{"test", "test_ap"},
+		d.GetActiveTopics([]model.TableName{{Schema: "test", Table: "t1"}}))
+	require.Len(t, d.columnTopics, 1)
+
+	tableInfo := model.WrapTableInfo(1, "test", 1, &timodel.TableInfo{
+		Name: timodel.NewCIStr("t1"),
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("id"), Offset: 0},
+			{ID: 2, Name: timodel.NewCIStr("Tenant"), Offset: 1},
+			{ID: 3, Name: timodel.NewCIStr("region"), Offset: 2},
+		},
+	})
+	require.Nil(t, d.VerifyColumns(tableInfo))
+	tableInfo.Columns = tableInfo.Columns[:2]
+	require.Regexp(t, ".*column region doesn't exist in table test.t1.*",
+		d.VerifyColumns(tableInfo))
+	// The other tables don't use any column.
+	tableInfo.TableName.Schema = "other"
+	require.Nil(t, d.VerifyColumns(tableInfo))
+}
+
+func TestGetTopicConfig(t *testing.T) {
+	t.Parallel()
+
+	helloConfig := &config.TopicConfig{PartitionNum: 6, CleanupPolicy: "compact"}
+	defaultConfig := &config.TopicConfig{RetentionMs: 3600000}
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{Matcher: []string{"test1.*"}, TopicRule: "{schema}_world"},
+				{Matcher: []string{"test2.*"}, TopicRule: "hello_{schema}", TopicConfig: helloConfig},
+				{Matcher: []string{"test3.*"}, TopicRule: "{schema}_{table}"},
+				{Matcher: []string{"test4.*"}, TopicConfig: defaultConfig},
+			},
+		},
+	}, "test")
+	require.Nil(t, err)
+
+	require.Equal(t, helloConfig, d.GetTopicConfig("hello_test2"))
+	require.Nil(t, d.GetTopicConfig("test3_t1"))
+	// The first rule whose topics may include the topic is used.
+	require.Nil(t, d.GetTopicConfig("test1_world"))
+	require.Nil(t, d.GetTopicConfig("hello_world"))
+	require.Equal(t, defaultConfig, d.GetTopicConfig("test"))
+	require.Nil(t, d.GetTopicConfig("unknown"))
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/hash"
+)
+
+// ColumnsDispatcher is a partition dispatcher which dispatches rows by the
+// hash of the values of the specified columns.
+type ColumnsDispatcher struct {
+	hasher  *hash.PositionInertia
+	columns []string
+}
+
+// NewColumnsDispatcher creates a ColumnsDispatcher.
+func NewColumnsDispatcher(columns []string) *ColumnsDispatcher {
+	return &ColumnsDispatcher{
+		hasher:  hash.NewPositionInertia(),
+		columns: columns,
+	}
+}
+
+// DispatchRowChangedEvent returns the target partition to which
+// a row changed event should be dispatched.
+// The table name is not hashed, so that the rows of different tables with
+// the same column values are dispatched to the same partition. The rows
+// without any of the columns are dispatched by the table name.
+func (r *ColumnsDispatcher) DispatchRowChangedEvent(row *model.RowChangedEvent, partitionNum int32) int32 {
+	r.hasher.Reset()
+	found := false
+	for _, name := range r.columns {
+		col := row.GetColumn(name)
+		if col == nil {
+			continue
+		}
+		found = true
+		r.hasher.Write([]byte(name), []byte(model.ColumnValueString(col.Value)))
+	}
+	if !found {
+		r.hasher.Write([]byte(row.Table.Schema), []byte(row.Table.Table))
+	}
+	return int32(r.hasher.Sum32() % uint32(partitionNum))
+}
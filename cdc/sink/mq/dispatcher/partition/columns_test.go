@@ -0,0 +1,55 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnsDispatcher(t *testing.T) {
+	t.Parallel()
+
+	newRow := func(table string, id int, tenant interface{}) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: table},
+			Columns: []*model.Column{
+				{Name: "id", Value: id, Flag: model.HandleKeyFlag},
+				{Name: "Tenant", Value: tenant},
+			},
+		}
+	}
+	p := NewColumnsDispatcher([]string{"tenant"})
+	partition := p.DispatchRowChangedEvent(newRow("t1", 1, "a"), 16)
+	// the rows of the same tenant are dispatched to the same partition
+	require.Equal(t, partition, p.DispatchRowChangedEvent(newRow("t1", 2, "a"), 16))
+	require.Equal(t, partition, p.DispatchRowChangedEvent(newRow("t2", 3, "a"), 16))
+	deleteRow := newRow("t2", 3, "a")
+	deleteRow.PreColumns, deleteRow.Columns = deleteRow.Columns, nil
+	require.Equal(t, partition, p.DispatchRowChangedEvent(deleteRow, 16))
+
+	tenants := make(map[int32]struct{})
+	for _, tenant := range []string{"a", "b", "c", "d", "e", "f"} {
+		tenants[p.DispatchRowChangedEvent(newRow("t1", 1, tenant), 16)] = struct{}{}
+	}
+	require.Greater(t, len(tenants), 1)
+
+	// the rows without the columns are dispatched by the table name
+	table := NewTableDispatcher()
+	p = NewColumnsDispatcher([]string{"region"})
+	require.Equal(t, table.DispatchRowChangedEvent(newRow("t1", 1, "a"), 16),
+		p.DispatchRowChangedEvent(newRow("t1", 1, "a"), 16))
+}
@@ -15,12 +15,16 @@ package topic
 
 import (
 	"fmt"
+
+	"github.com/pingcap/tiflow/cdc/model"
 )
 
 // Dispatcher is an abstraction for dispatching rows and ddls into different topics.
 type Dispatcher interface {
 	fmt.Stringer
 	Substitute(schema, table string) string
+	// Match returns true if the rows may be dispatched to the topic.
+	Match(topicName string) bool
 }
 
 // StaticTopicDispatcher is a topic dispatcher which dispatches rows and ddls to the default topic.
@@ -40,6 +44,11 @@ func (s *StaticTopicDispatcher) Substitute(schema, table string) string {
 	return s.defaultTopic
 }
 
+// Match returns true if the topic is the default topic.
+func (s *StaticTopicDispatcher) Match(topicName string) bool {
+	return topicName == s.defaultTopic
+}
+
 func (s *StaticTopicDispatcher) String() string {
 	return s.defaultTopic
 }
@@ -62,6 +71,61 @@ func (d *DynamicTopicDispatcher) Substitute(schema, table string) string {
 	return d.expression.Substitute(schema, table)
 }
 
+// Match returns true if the topic name may be converted from the topic expression.
+func (d *DynamicTopicDispatcher) Match(topicName string) bool {
+	return d.expression.Match(topicName)
+}
+
 func (d *DynamicTopicDispatcher) String() string {
 	return string(d.expression)
 }
+
+// ColumnTopicDispatcher is a topic dispatcher which dispatches rows into
+// topics by the values of their columns, and dispatches DDLs into the
+// default topic since they don't have column values.
+type ColumnTopicDispatcher struct {
+	expression   Expression
+	defaultTopic string
+}
+
+// NewColumnTopicDispatcher creates a ColumnTopicDispatcher.
+func NewColumnTopicDispatcher(topicExpr Expression, defaultTopic string) *ColumnTopicDispatcher {
+	return &ColumnTopicDispatcher{
+		expression:   topicExpr,
+		defaultTopic: defaultTopic,
+	}
+}
+
+// Substitute returns the default topic.
+func (d *ColumnTopicDispatcher) Substitute(schema, table string) string {
+	return d.defaultTopic
+}
+
+// SubstituteRow converts the topic expression to the topic name of a row,
+// the values of the missing columns are substituted as "null".
+func (d *ColumnTopicDispatcher) SubstituteRow(
+	schema, table string, row *model.RowChangedEvent,
+) (string, error) {
+	return d.expression.SubstituteColumns(schema, table, func(name string) string {
+		col := row.GetColumn(name)
+		if col == nil {
+			return model.ColumnValueString(nil)
+		}
+		return model.ColumnValueString(col.Value)
+	})
+}
+
+// Columns returns the names of the columns in the topic expression.
+func (d *ColumnTopicDispatcher) Columns() []string {
+	return d.expression.Columns()
+}
+
+// Match returns true if the topic name may be converted from the topic
+// expression, the default topic is not matched since only DDLs are sent to it.
+func (d *ColumnTopicDispatcher) Match(topicName string) bool {
+	return d.expression.Match(topicName)
+}
+
+func (d *ColumnTopicDispatcher) String() string {
+	return string(d.expression)
+}
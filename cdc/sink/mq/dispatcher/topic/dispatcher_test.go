@@ -14,14 +14,18 @@
 package topic
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/stretchr/testify/require"
 )
 
 func TestStaticTopicDispatcher(t *testing.T) {
 	p := NewStaticTopicDispatcher("cdctest")
 	require.Equal(t, p.Substitute("db1", "tbl1"), "cdctest")
+	require.True(t, p.Match("cdctest"))
+	require.False(t, p.Match("db1"))
 }
 
 func TestDynamicTopicDispatcherForSchema(t *testing.T) {
@@ -100,3 +104,39 @@ func TestDynamicTopicDispatcherForTable(t *testing.T) {
 		require.Equal(t, tc.expectedTopic, p.Substitute(tc.schema, tc.table))
 	}
 }
+
+func TestColumnTopicDispatcher(t *testing.T) {
+	t.Parallel()
+
+	topicExpr := Expression("{schema}_{col:region}")
+	require.Nil(t, topicExpr.Validate())
+	p := NewColumnTopicDispatcher(topicExpr, "cdctest")
+	require.Equal(t, "cdctest", p.Substitute("test", "t1"))
+	require.Equal(t, []string{"region"}, p.Columns())
+	require.Equal(t, "{schema}_{col:region}", p.String())
+
+	row := &model.RowChangedEvent{
+		Table: &model.TableName{Schema: "test", Table: "t1"},
+		Columns: []*model.Column{
+			{Name: "id", Value: 1, Flag: model.HandleKeyFlag},
+			{Name: "region", Value: "eu"},
+		},
+	}
+	topicName, err := p.SubstituteRow("test", "t1", row)
+	require.Nil(t, err)
+	require.Equal(t, "test_eu", topicName)
+	row.PreColumns, row.Columns = row.Columns, nil
+	topicName, err = p.SubstituteRow("test", "t1", row)
+	require.Nil(t, err)
+	require.Equal(t, "test_eu", topicName)
+	row.PreColumns = row.PreColumns[:1]
+	topicName, err = p.SubstituteRow("test", "t1", row)
+	require.Nil(t, err)
+	require.Equal(t, "test_null", topicName)
+
+	// The topic name is not truncated, since the rows with different values
+	// could be dispatched to the same topic.
+	row.PreColumns = append(row.PreColumns, &model.Column{Name: "region", Value: strings.Repeat("a", 250)})
+	_, err = p.SubstituteRow("test", "t1", row)
+	require.Regexp(t, ".*invalid topic name.*exceeds the limit 249.*", err)
+}
@@ -14,6 +14,7 @@
 package topic
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -29,6 +30,13 @@ var (
 	schemaRE = regexp.MustCompile(`\{schema\}`)
 	// tableRE is used to match substring '{table}' in topic expression
 	tableRE = regexp.MustCompile(`\{table\}`)
+	// columnRE is used to match substring '{col:name}' in topic expression
+	columnRE = regexp.MustCompile(`\{col:([^{}]+)\}`)
+	// columnTopicNameRE is used to match a valid topic expression once the
+	// '{col:name}' substrings are removed
+	columnTopicNameRE = regexp.MustCompile(`^([A-Za-z0-9\._\-]|\{schema\}|\{table\})*$`)
+	// placeholderRE is used to match all the substrings replaced in topic expression
+	placeholderRE = regexp.MustCompile(`\{schema\}|\{table\}|\{col:[^{}]+\}`)
 )
 
 // The max length of kafka topic name is 249.
@@ -36,13 +44,21 @@ var (
 const kafkaTopicNameMaxLength = 249
 
 // Expression represent a kafka topic expression.
-// Only two types of expression are allowed:
+// Only three types of expression are allowed:
 //   1. [prefix]{schema}[suffix], the prefix/suffix is optional and matches [A-Za-z0-9\._\-]*
 //   2. {schema}_{table}
+//   3. any combination of [A-Za-z0-9\._\-], {schema} and {table} with at
+//      least one {col:name}, which is replaced by the value of the column
 type Expression string
 
 // Validate checks whether a kafka topic name is valid or not.
 func (e Expression) Validate() error {
+	if e.HasColumns() {
+		if ok := columnTopicNameRE.MatchString(columnRE.ReplaceAllString(string(e), "")); !ok {
+			return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs()
+		}
+		return nil
+	}
 	// validate the topic expression
 	if ok := topicNameRE.MatchString(string(e)); !ok {
 		return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs()
@@ -51,10 +67,53 @@ func (e Expression) Validate() error {
 	return nil
 }
 
+// HasColumns returns true if the expression contains '{col:name}'.
+func (e Expression) HasColumns() bool {
+	return columnRE.MatchString(string(e))
+}
+
+// Columns returns the names of the columns in the expression.
+func (e Expression) Columns() []string {
+	var columns []string
+	for _, match := range columnRE.FindAllStringSubmatch(string(e), -1) {
+		columns = append(columns, match[1])
+	}
+	return columns
+}
+
 // Substitute converts schema/table name in a topic expression to kafka topic name.
 // When doing conversion, the special characters other than [A-Za-z0-9\._\-] in schema/table
 // will be substituted for underscore '_'.
 func (e Expression) Substitute(schema, table string) string {
+	topicName := e.substitute(schema, table, nil)
+	// topicName will be truncated if it exceed the limit.
+	if len(topicName) > kafkaTopicNameMaxLength {
+		return topicName[:kafkaTopicNameMaxLength]
+	}
+	return topicName
+}
+
+// SubstituteColumns converts a expression to a topic name like Substitute,
+// the '{col:name}' substrings are replaced by the values returned by
+// columnValue, which are converted in the same way as schema and table.
+// An error is returned if the topic name exceeds the length limit, rather
+// than truncating it, since the rows with different column values could be
+// dispatched to the same topic.
+func (e Expression) SubstituteColumns(
+	schema, table string, columnValue func(name string) string,
+) (string, error) {
+	topicName := e.substitute(schema, table, columnValue)
+	if len(topicName) > kafkaTopicNameMaxLength {
+		return "", errors.ErrKafkaInvalidTopicName.GenWithStackByArgs(
+			topicName[:kafkaTopicNameMaxLength]+"...",
+			fmt.Sprintf("its length %d exceeds the limit %d", len(topicName), kafkaTopicNameMaxLength))
+	}
+	return topicName, nil
+}
+
+func (e Expression) substitute(
+	schema, table string, columnValue func(name string) string,
+) string {
 	// the upper case letters in schema/table will be converted to lower case,
 	// and some of the special characters will be replaced with '_'
 	replacedSchema := kafkaForbidRE.ReplaceAllString(strings.ToLower(schema), "_")
@@ -64,17 +123,44 @@ func (e Expression) Substitute(schema, table string) string {
 	// doing the real conversion things
 	topicName := schemaRE.ReplaceAllString(topicExpr, replacedSchema)
 	topicName = tableRE.ReplaceAllString(topicName, replacedTable)
+	if columnValue != nil {
+		topicName = columnRE.ReplaceAllStringFunc(topicName, func(placeholder string) string {
+			value := columnValue(columnRE.FindStringSubmatch(placeholder)[1])
+			return kafkaForbidRE.ReplaceAllString(strings.ToLower(value), "_")
+		})
+	}
 
-	// topicName will be truncated if it exceed the limit.
-	// And topicName '.' and '..' are also invalid, replace them with '_'.
+	// topicName '.' and '..' are invalid, replace them with '_'.
 	//    See https://github.com/apache/kafka/blob/trunk/clients/src/main/java/org/apache/kafka/common/internals/Topic.java#L46
-	if len(topicName) > kafkaTopicNameMaxLength {
-		return topicName[:kafkaTopicNameMaxLength]
-	} else if topicName == "." {
+	if topicName == "." {
 		return "_"
 	} else if topicName == ".." {
 		return "__"
-	} else {
-		return topicName
 	}
+	return topicName
+}
+
+// Match returns true if the topic name may be converted from the expression.
+// The topic names which are truncated for exceeding the length limit are
+// not matched.
+func (e Expression) Match(topicName string) bool {
+	expr := string(e)
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, loc := range placeholderRE.FindAllStringIndex(expr, -1) {
+		pattern.WriteString(regexp.QuoteMeta(expr[last:loc[0]]))
+		// The substituted names are converted to lower case without the
+		// forbidden characters, and the values of the columns may be empty.
+		if strings.HasPrefix(expr[loc[0]:loc[1]], "{col:") {
+			pattern.WriteString(`[a-z0-9\._\-]*`)
+		} else {
+			pattern.WriteString(`[a-z0-9\._\-]+`)
+		}
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(expr[last:]))
+	pattern.WriteString("$")
+	matched, err := regexp.MatchString(pattern.String(), topicName)
+	return err == nil && matched
 }
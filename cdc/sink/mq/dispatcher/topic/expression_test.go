@@ -197,6 +197,79 @@ func TestSubstituteTopicExpression(t *testing.T) {
 	}
 }
 
+func TestSubstituteColumnTopicExpression(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]string{"region": "EU-West", "tenant": "t!1"}
+	columnValue := func(name string) string {
+		return values[name]
+	}
+	cases := []struct {
+		expression string
+		wantErr    bool
+		columns    []string
+		expected   string
+	}{
+		{expression: "{schema}_{col:region}", columns: []string{"region"}, expected: "db_eu-west"},
+		{
+			expression: "{schema}_{table}_{col:region}.{col:tenant}",
+			columns:    []string{"region", "tenant"},
+			expected:   "db_tbl_eu-west.t_1",
+		},
+		{expression: "tenant-{col:tenant}", columns: []string{"tenant"}, expected: "tenant-t_1"},
+		{expression: "{schema}_{col:region", wantErr: true},
+		{expression: "{schema}_{col:region}_{xxx}", wantErr: true},
+		{expression: "你好{col:region}", wantErr: true},
+	}
+	for _, tc := range cases {
+		topicExpr := Expression(tc.expression)
+		err := topicExpr.Validate()
+		if tc.wantErr {
+			require.Regexp(t, "invalid topic expression", err, tc.expression)
+			continue
+		}
+		require.Nil(t, err, tc.expression)
+		require.True(t, topicExpr.HasColumns())
+		require.Equal(t, tc.columns, topicExpr.Columns())
+		topicName, err := topicExpr.SubstituteColumns("DB", "tbl", columnValue)
+		require.Nil(t, err, tc.expression)
+		require.Equal(t, tc.expected, topicName)
+	}
+	require.False(t, Expression("{schema}_{table}").HasColumns())
+}
+
+func TestMatchTopicExpression(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expression string
+		topicName  string
+		expected   bool
+	}{
+		{expression: "hello_{schema}", topicName: "hello_db", expected: true},
+		{expression: "hello_{schema}", topicName: "hello_", expected: false},
+		{expression: "hello_{schema}", topicName: "world_db", expected: false},
+		{expression: "{schema}_{table}", topicName: "db_tbl", expected: true},
+		{expression: "{schema}_{table}", topicName: "db", expected: false},
+		{expression: "a.{schema}", topicName: "abdb", expected: false},
+		{expression: "a.{schema}", topicName: "a.db", expected: true},
+		{expression: "{schema}_{col:region}", topicName: "db_eu-west", expected: true},
+		{expression: "{schema}_{col:region}", topicName: "db_", expected: true},
+	}
+	for _, tc := range cases {
+		topicExpr := Expression(tc.expression)
+		require.Equal(t, tc.expected, topicExpr.Match(tc.topicName),
+			fmt.Sprintf("%s %s", tc.expression, tc.topicName))
+	}
+	// the substituted topic names are always matched
+	topicExpr := Expression("{schema}_{table}_{col:region}")
+	topicName, err := topicExpr.SubstituteColumns("Hello!", "World", func(string) string {
+		return "EU West"
+	})
+	require.Nil(t, err)
+	require.True(t, topicExpr.Match(topicName))
+}
+
 // cmd: go test -run='^$' -bench '^(BenchmarkSubstitute)$' github.com/pingcap/tiflow/cdc/sink/dispatcher/topic
 // goos: linux
 // goarch: amd64
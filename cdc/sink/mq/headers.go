@@ -0,0 +1,145 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/mq/producer"
+	"github.com/pingcap/tiflow/pkg/config"
+)
+
+// headerProducer attaches the configured headers to every message before
+// handing it to the underlying producer.
+type headerProducer struct {
+	producer.Producer
+
+	builtins     []string
+	static       []codec.MessageHeader
+	changefeedID string
+	clusterID    uint64
+}
+
+// newHeaderProducer wraps the producer with a headerProducer, it returns the
+// producer as is if no header is configured.
+func newHeaderProducer(
+	p producer.Producer, cfg *config.HeadersConfig, changefeedID string, clusterID uint64,
+) producer.Producer {
+	if cfg == nil || (len(cfg.Include) == 0 && len(cfg.Static) == 0) {
+		return p
+	}
+	static := make([]codec.MessageHeader, 0, len(cfg.Static))
+	for key, value := range cfg.Static {
+		static = append(static, codec.MessageHeader{Key: key, Value: value})
+	}
+	sort.Slice(static, func(i, j int) bool { return static[i].Key < static[j].Key })
+	return &headerProducer{
+		Producer:     p,
+		builtins:     cfg.Include,
+		static:       static,
+		changefeedID: changefeedID,
+		clusterID:    clusterID,
+	}
+}
+
+// maxBuiltinHeaderValueBytes is the max size of the values of the built-in
+// headers, the schema and table names are up to 64 characters of 4 bytes.
+var maxBuiltinHeaderValueBytes = map[string]int{
+	config.HeaderCommitTs:  20,
+	config.HeaderSchema:    256,
+	config.HeaderTable:     256,
+	config.HeaderProtocol:  16,
+	config.HeaderType:      16,
+	config.HeaderClusterID: 20,
+}
+
+// maxHeadersLength returns the max size of the headers attached to a
+// message, the encoders must leave the space for them in every message.
+func maxHeadersLength(cfg *config.HeadersConfig, changefeedID string) int {
+	if cfg == nil {
+		return 0
+	}
+	length := 0
+	for _, name := range cfg.Include {
+		valueBytes, ok := maxBuiltinHeaderValueBytes[name]
+		if name == config.HeaderChangefeedID {
+			valueBytes, ok = len(changefeedID), true
+		}
+		if !ok {
+			continue
+		}
+		length += codec.MessageHeader{Key: config.HeaderPrefix + name}.Length() + valueBytes
+	}
+	for key, value := range cfg.Static {
+		length += codec.MessageHeader{Key: key, Value: value}.Length()
+	}
+	return length
+}
+
+// AsyncSendMessage implements producer.Producer.
+func (h *headerProducer) AsyncSendMessage(
+	ctx context.Context, topic string, partition int32, message *codec.MQMessage,
+) error {
+	h.attach(message)
+	return h.Producer.AsyncSendMessage(ctx, topic, partition, message)
+}
+
+// SyncBroadcastMessage implements producer.Producer.
+func (h *headerProducer) SyncBroadcastMessage(
+	ctx context.Context, topic string, partitionsNum int32, message *codec.MQMessage,
+) error {
+	h.attach(message)
+	return h.Producer.SyncBroadcastMessage(ctx, topic, partitionsNum, message)
+}
+
+// attach replaces the headers of the message, so that it is safe to send
+// the same message more than once.
+func (h *headerProducer) attach(message *codec.MQMessage) {
+	headers := make([]codec.MessageHeader, 0, len(h.builtins)+len(h.static))
+	for _, name := range h.builtins {
+		var value string
+		switch name {
+		case config.HeaderCommitTs:
+			value = strconv.FormatUint(message.Ts, 10)
+		case config.HeaderSchema:
+			if message.Schema == nil {
+				continue
+			}
+			value = *message.Schema
+		case config.HeaderTable:
+			if message.Table == nil {
+				continue
+			}
+			value = *message.Table
+		case config.HeaderProtocol:
+			value = message.Protocol.String()
+		case config.HeaderType:
+			value = message.Type.String()
+		case config.HeaderChangefeedID:
+			value = h.changefeedID
+		case config.HeaderClusterID:
+			if h.clusterID == 0 {
+				continue
+			}
+			value = strconv.FormatUint(h.clusterID, 10)
+		default:
+			continue
+		}
+		headers = append(headers, codec.MessageHeader{Key: config.HeaderPrefix + name, Value: value})
+	}
+	message.Headers = append(headers, h.static...)
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderProducer(t *testing.T) {
+	t.Parallel()
+
+	mp := &mockProducer{mqEvent: make(map[topicPartitionKey][]*codec.MQMessage)}
+	require.Same(t, mp, newHeaderProducer(mp, nil, "cf", 1))
+	require.Same(t, mp, newHeaderProducer(mp, &config.HeadersConfig{}, "cf", 1))
+
+	p := newHeaderProducer(mp, &config.HeadersConfig{
+		Include: []string{
+			config.HeaderCommitTs, config.HeaderSchema, config.HeaderTable,
+			config.HeaderProtocol, config.HeaderType, config.HeaderChangefeedID,
+			config.HeaderClusterID,
+		},
+		Static: map[string]string{"region": "us-east", "env": "prod"},
+	}, "cf", 7071458326478937088)
+
+	schema, table := "test", "t1"
+	msg := codec.NewMQMessage(config.ProtocolCanalJSON, []byte("k"), []byte("v"),
+		420536581131337731, model.MqMessageTypeRow, &schema, &table)
+	require.Nil(t, p.AsyncSendMessage(context.Background(), "topic", 1, msg))
+	expected := []codec.MessageHeader{
+		{Key: "ticdc-commit-ts", Value: "420536581131337731"},
+		{Key: "ticdc-schema", Value: "test"},
+		{Key: "ticdc-table", Value: "t1"},
+		{Key: "ticdc-protocol", Value: "canal-json"},
+		{Key: "ticdc-type", Value: "row"},
+		{Key: "ticdc-changefeed-id", Value: "cf"},
+		{Key: "ticdc-cluster-id", Value: "7071458326478937088"},
+		{Key: "env", Value: "prod"},
+		{Key: "region", Value: "us-east"},
+	}
+	sent := mp.mqEvent[topicPartitionKey{topic: "topic", partition: 1}]
+	require.Len(t, sent, 1)
+	require.Equal(t, expected, sent[0].Headers)
+
+	// Headers are replaced rather than appended when a message is sent again,
+	// and headers without values are skipped.
+	msg = codec.NewMQMessage(config.ProtocolCanalJSON, nil, []byte("v"),
+		420536581131337731, model.MqMessageTypeResolved, nil, nil)
+	require.Nil(t, p.AsyncSendMessage(context.Background(), "topic", 1, msg))
+	require.Nil(t, p.AsyncSendMessage(context.Background(), "topic", 1, msg))
+	require.Equal(t, []codec.MessageHeader{
+		{Key: "ticdc-commit-ts", Value: "420536581131337731"},
+		{Key: "ticdc-protocol", Value: "canal-json"},
+		{Key: "ticdc-type", Value: "resolved"},
+		{Key: "ticdc-changefeed-id", Value: "cf"},
+		{Key: "ticdc-cluster-id", Value: "7071458326478937088"},
+		{Key: "env", Value: "prod"},
+		{Key: "region", Value: "us-east"},
+	}, msg.Headers)
+}
+
+func TestMaxHeadersLength(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 0, maxHeadersLength(nil, "cf"))
+	cfg := &config.HeadersConfig{
+		Include: []string{
+			config.HeaderCommitTs, config.HeaderSchema, config.HeaderTable,
+			config.HeaderProtocol, config.HeaderType, config.HeaderChangefeedID,
+			config.HeaderClusterID,
+		},
+		Static: map[string]string{"region": "us-east"},
+	}
+	mp := &mockProducer{mqEvent: make(map[topicPartitionKey][]*codec.MQMessage)}
+	p := newHeaderProducer(mp, cfg, "changefeed-1", 7071458326478937088)
+	schema, table := strings.Repeat("库", 64), strings.Repeat("t", 64)
+	msg := codec.NewMQMessage(config.ProtocolCanalJSON, nil, nil,
+		math.MaxUint64, model.MqMessageTypeResolved, &schema, &table)
+	require.Nil(t, p.AsyncSendMessage(context.Background(), "topic", 1, msg))
+	// The headers of a message never exceed the max length.
+	headersLength := 0
+	for _, header := range msg.Headers {
+		headersLength += header.Length()
+	}
+	require.LessOrEqual(t, headersLength, maxHeadersLength(cfg, "changefeed-1"))
+}
@@ -152,18 +152,19 @@ func (m *TopicManager) CreateTopic(topicName string) (int32, error) {
 				"and %s not found", topicName))
 	}
 
+	cfg := m.cfg.ForTopic(topicName)
 	start = time.Now()
-	err = m.admin.CreateTopic(topicName, &sarama.TopicDetail{
-		NumPartitions:     m.cfg.PartitionNum,
-		ReplicationFactor: m.cfg.ReplicationFactor,
-	}, false)
+	err = m.admin.CreateTopic(topicName, cfg.TopicDetail(), false)
 	// Ignore topic already exists error.
 	if err != nil && errors.Cause(err) != sarama.ErrTopicAlreadyExists {
 		log.Error(
 			"Kafka admin client create the topic failed",
 			zap.String("topic", topicName),
-			zap.Int32("partitionNumber", m.cfg.PartitionNum),
-			zap.Int16("replicationFactor", m.cfg.ReplicationFactor),
+			zap.Int32("partitionNumber", cfg.PartitionNum),
+			zap.Int16("replicationFactor", cfg.ReplicationFactor),
+			zap.Int64("retentionMs", cfg.RetentionMs),
+			zap.String("cleanupPolicy", cfg.CleanupPolicy),
+			zap.Int("minInsyncReplicas", cfg.MinInsyncReplicas),
 			zap.Error(err),
 			zap.Duration("duration", time.Since(start)),
 		)
@@ -172,11 +173,14 @@ func (m *TopicManager) CreateTopic(topicName string) (int32, error) {
 	log.Info(
 		"Kafka admin client create the topic success",
 		zap.String("topic", topicName),
-		zap.Int32("partitionNumber", m.cfg.PartitionNum),
-		zap.Int16("replicationFactor", m.cfg.ReplicationFactor),
+		zap.Int32("partitionNumber", cfg.PartitionNum),
+		zap.Int16("replicationFactor", cfg.ReplicationFactor),
+		zap.Int64("retentionMs", cfg.RetentionMs),
+		zap.String("cleanupPolicy", cfg.CleanupPolicy),
+		zap.Int("minInsyncReplicas", cfg.MinInsyncReplicas),
 		zap.Duration("duration", time.Since(start)),
 	)
-	m.tryUpdatePartitionsAndLogging(topicName, m.cfg.PartitionNum)
+	m.tryUpdatePartitionsAndLogging(topicName, cfg.PartitionNum)
 
-	return m.cfg.PartitionNum, nil
+	return cfg.PartitionNum, nil
 }
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	kafkaconfig "github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
+	"github.com/pingcap/tiflow/pkg/config"
 	kafkamock "github.com/pingcap/tiflow/pkg/kafka"
 	"github.com/stretchr/testify/require"
 )
@@ -112,3 +113,45 @@ func TestCreateTopic(t *testing.T) {
 		err,
 	)
 }
+
+func TestCreateTopicWithTopicConfigs(t *testing.T) {
+	t.Parallel()
+
+	client := kafkamock.NewClientMockImpl()
+	adminClient := kafkamock.NewClusterAdminClientMockImpl()
+	defer func(adminClient *kafkamock.ClusterAdminClientMockImpl) {
+		_ = adminClient.Close()
+	}(adminClient)
+	cfg := &kafkaconfig.AutoCreateTopicConfig{
+		AutoCreate:        true,
+		PartitionNum:      2,
+		ReplicationFactor: 1,
+		RetentionMs:       3600000,
+		TopicConfigs: func(topic string) *config.TopicConfig {
+			if topic == "compacted" {
+				return &config.TopicConfig{PartitionNum: 4, CleanupPolicy: "compact"}
+			}
+			return nil
+		},
+	}
+
+	manager := NewTopicManager(client, adminClient, cfg)
+	partitionNum, err := manager.CreateTopic("new-topic")
+	require.Nil(t, err)
+	require.Equal(t, int32(2), partitionNum)
+	partitionNum, err = manager.Partitions("compacted")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionNum)
+
+	topics, err := adminClient.ListTopics()
+	require.Nil(t, err)
+	detail := topics["new-topic"]
+	require.Equal(t, int32(2), detail.NumPartitions)
+	require.Equal(t, "3600000", *detail.ConfigEntries[kafkamock.RetentionMsConfigName])
+	require.NotContains(t, detail.ConfigEntries, kafkamock.CleanupPolicyConfigName)
+	detail = topics["compacted"]
+	require.Equal(t, int32(4), detail.NumPartitions)
+	require.Equal(t, int16(1), detail.ReplicationFactor)
+	require.Equal(t, "3600000", *detail.ConfigEntries[kafkamock.RetentionMsConfigName])
+	require.Equal(t, "compact", *detail.ConfigEntries[kafkamock.CleanupPolicyConfigName])
+}
@@ -13,28 +13,74 @@
 
 package pulsar
 
-// TopicManager is the interface
-// that wraps the basic Pulsar topic management operations.
-// Right now it doesn't have any implementation,
-// Pulsar doesn't support multiple topics yet.
-// So it now just returns a fixed number of partitions for a fixed topic.
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+)
+
+// partitionsRefreshInterval is the interval to refresh the number of
+// partitions of a topic, which may be increased by the admin.
+const partitionsRefreshInterval = time.Minute
+
+// TopicClient gets the number of partitions of the topics.
+type TopicClient interface {
+	// TopicPartitions returns the number of partitions of the topic.
+	TopicPartitions(topic string) (int32, error)
+}
+
+type topicPartitions struct {
+	partitions  int32
+	lastRefresh time.Time
+}
+
+// TopicManager is a manager for pulsar topics. Pulsar creates the topics on
+// their first use if the broker allows it, so the manager only discovers
+// and refreshes the number of partitions of the topics.
 type TopicManager struct {
-	partitionNum int32
+	client TopicClient
+
+	mu     sync.Mutex
+	topics map[string]topicPartitions
 }
 
 // NewTopicManager creates a new TopicManager.
-func NewTopicManager(partitionNum int32) *TopicManager {
+func NewTopicManager(client TopicClient) *TopicManager {
 	return &TopicManager{
-		partitionNum: partitionNum,
+		client: client,
+		topics: make(map[string]topicPartitions),
 	}
 }
 
 // Partitions returns the number of partitions of the topic.
-func (m *TopicManager) Partitions(_ string) (int32, error) {
-	return m.partitionNum, nil
+func (m *TopicManager) Partitions(topic string) (int32, error) {
+	m.mu.Lock()
+	cached, ok := m.topics[topic]
+	m.mu.Unlock()
+	if ok && time.Since(cached.lastRefresh) < partitionsRefreshInterval {
+		return cached.partitions, nil
+	}
+	return m.CreateTopic(topic)
 }
 
-// CreateTopic do nothing.
-func (m *TopicManager) CreateTopic(_ string) (int32, error) {
-	return m.partitionNum, nil
+// CreateTopic gets the number of partitions of the topic, which is created
+// by the broker if it doesn't exist.
+func (m *TopicManager) CreateTopic(topicName string) (int32, error) {
+	partitions, err := m.client.TopicPartitions(topicName)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cached, ok := m.topics[topicName]; !ok || cached.partitions != partitions {
+		log.Info("update pulsar topic partition number",
+			zap.String("topic", topicName),
+			zap.Int32("partitionNumber", partitions))
+	}
+	m.topics[topicName] = topicPartitions{partitions: partitions, lastRefresh: time.Now()}
+	return partitions, nil
 }
@@ -0,0 +1,67 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTopicClient struct {
+	partitions map[string]int32
+	calls      int
+}
+
+func (c *mockTopicClient) TopicPartitions(topic string) (int32, error) {
+	c.calls++
+	partitions, ok := c.partitions[topic]
+	if !ok {
+		return 0, errors.New("topic not found")
+	}
+	return partitions, nil
+}
+
+func TestPartitions(t *testing.T) {
+	t.Parallel()
+
+	client := &mockTopicClient{partitions: map[string]int32{"t1": 3, "t2": 1}}
+	manager := NewTopicManager(client)
+	partitions, err := manager.Partitions("t1")
+	require.Nil(t, err)
+	require.Equal(t, int32(3), partitions)
+	partitions, err = manager.Partitions("t2")
+	require.Nil(t, err)
+	require.Equal(t, int32(1), partitions)
+	_, err = manager.Partitions("t3")
+	require.Regexp(t, "topic not found", err)
+
+	// The cached number of partitions is used until it expires.
+	client.partitions["t1"] = 6
+	calls := client.calls
+	partitions, err = manager.Partitions("t1")
+	require.Nil(t, err)
+	require.Equal(t, int32(3), partitions)
+	require.Equal(t, calls, client.calls)
+
+	manager.topics["t1"] = topicPartitions{
+		partitions:  3,
+		lastRefresh: time.Now().Add(-partitionsRefreshInterval),
+	}
+	partitions, err = manager.Partitions("t1")
+	require.Nil(t, err)
+	require.Equal(t, int32(6), partitions)
+}
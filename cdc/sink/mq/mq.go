@@ -58,6 +58,13 @@ type mqSink struct {
 	encoderBuilder codec.EncoderBuilder
 	filter         *filter.Filter
 	protocol       config.Protocol
+	// controlTopic is the topic which the resolved ts and DDL events are sent
+	// to by the state protocol, it is empty for the other protocols.
+	controlTopic string
+	// txnTopic is the topic which the transaction markers are sent to, it is
+	// empty if the transaction metadata is disabled.
+	txnTopic   string
+	txnTracker *txnTracker
 
 	topicManager         manager.TopicManager
 	flushWorker          *flushWorker
@@ -76,7 +83,7 @@ func newMqSink(
 	topicManager manager.TopicManager,
 	mqProducer producer.Producer,
 	filter *filter.Filter,
-	defaultTopic string,
+	defaultTopic, controlTopic, txnTopic string,
 	replicaConfig *config.ReplicaConfig, encoderConfig *codec.Config,
 	errCh chan error,
 ) (*mqSink, error) {
@@ -92,10 +99,13 @@ func newMqSink(
 
 	changefeedID := util.ChangefeedIDFromCtx(ctx)
 	role := util.RoleFromCtx(ctx)
+	mqProducer = newHeaderProducer(
+		mqProducer, replicaConfig.Sink.Headers, changefeedID, util.ClusterIDFromCtx(ctx))
 
 	encoder := encoderBuilder.Build()
 	statistics := metrics.NewStatistics(ctx, metrics.SinkTypeMQ)
-	flushWorker := newFlushWorker(encoder, mqProducer, statistics)
+	flushWorker := newFlushWorker(encoder, mqProducer, statistics,
+		newRowPartitioner(eventRouter, topicManager, encoderConfig.Protocol() == config.ProtocolState))
 
 	s := &mqSink{
 		mqProducer:     mqProducer,
@@ -103,6 +113,8 @@ func newMqSink(
 		encoderBuilder: encoderBuilder,
 		filter:         filter,
 		protocol:       encoderConfig.Protocol(),
+		controlTopic:   controlTopic,
+		txnTopic:       txnTopic,
 		topicManager:   topicManager,
 		flushWorker:    flushWorker,
 		resolvedBuffer: make(chan resolvedTsEvent, defaultResolvedTsEventBufferSize),
@@ -110,6 +122,9 @@ func newMqSink(
 		role:           role,
 		id:             changefeedID,
 	}
+	if txnTopic != "" {
+		s.txnTracker = newTxnTracker()
+	}
 
 	go func() {
 		if err := s.run(ctx); err != nil && errors.Cause(err) != context.Canceled {
@@ -139,11 +154,15 @@ func (k *mqSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.Row
 
 // Init table sink resources
 func (k *mqSink) Init(tableID model.TableID) error {
+	if k.txnTracker != nil {
+		k.txnTracker.addTable(tableID)
+	}
 	return nil
 }
 
 func (k *mqSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
 	rowsCount := 0
+	emitRows := make([]*model.RowChangedEvent, 0, len(rows))
 	for _, row := range rows {
 		if k.filter.ShouldIgnoreDMLEvent(row.StartTs, row.Table.Schema, row.Table.Table) {
 			log.Info("Row changed event ignored",
@@ -152,29 +171,108 @@ func (k *mqSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowCha
 				zap.Any("role", k.role))
 			continue
 		}
-		topic := k.eventRouter.GetTopicForRowChange(row)
-		partitionNum, err := k.topicManager.Partitions(topic)
+		if k.protocol == config.ProtocolState && shouldSplitHandleKeyUpdate(row) {
+			// The state of a row is keyed by its handle key, so the old key
+			// must be deleted, or the compacted topic keeps a stale row.
+			emitRows = append(emitRows, splitHandleKeyUpdate(row)...)
+		} else {
+			emitRows = append(emitRows, row)
+		}
+		rowsCount++
+	}
+	if k.txnTopic == "" {
+		if err := k.addRows(ctx, emitRows); err != nil {
+			return err
+		}
+		k.statistics.AddRowsCount(rowsCount)
+		return nil
+	}
+
+	// The rows of a table in a transaction are emitted together, because the
+	// buffer sink emits all the rows of a table before a resolved ts at once.
+	for start := 0; start < len(emitRows); {
+		end := start + 1
+		for end < len(emitRows) && isSameTxn(emitRows[start], emitRows[end]) {
+			end++
+		}
+		if err := k.emitTxn(ctx, emitRows[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	k.statistics.AddRowsCount(rowsCount)
+	return nil
+}
+
+func (k *mqSink) addRows(ctx context.Context, rows []*model.RowChangedEvent) error {
+	for _, row := range rows {
+		topic, err := k.eventRouter.GetTopicForRowChange(row)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		partition := k.eventRouter.GetPartitionForRowChange(row, partitionNum)
+		// The partition is decided by the flush worker, so that the number of
+		// partitions of the topic is only changed at a resolved ts barrier.
 		err = k.flushWorker.addEvent(ctx, mqEvent{
 			row: row,
 			key: topicPartitionKey{
-				topic: topic, partition: partition,
+				topic: topic,
 			},
 		})
 		if err != nil {
 			return err
 		}
-		rowsCount++
 	}
-	k.statistics.AddRowsCount(rowsCount)
 	return nil
 }
 
+// emitTxn emits the rows of a table in a transaction with their positions in
+// the transaction, the BEGIN marker is sent to the transaction topic before
+// the first rows of the transaction. The COMMIT marker with the number of rows
+// of every table is sent once all the tables are resolved to its commit ts.
+// The markers are sent in order with the rows, but they are in a different
+// topic, so the consumers should wait for the number of rows in the COMMIT
+// marker.
+func (k *mqSink) emitTxn(ctx context.Context, rows []*model.RowChangedEvent) error {
+	table := k.eventRouter.RouteRowChange(rows[0]).Table
+	if begin := k.txnTracker.addRows(table.String(), rows); begin != nil {
+		if err := k.emitTxnEvent(ctx, begin); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return k.addRows(ctx, rows)
+}
+
+func (k *mqSink) emitTxnEvent(ctx context.Context, e *model.TxnEvent) error {
+	msg, err := codec.EncodeTxnEvent(k.protocol, e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Partitions creates the transaction topic if it doesn't exist.
+	if _, err := k.topicManager.Partitions(k.txnTopic); err != nil {
+		return errors.Trace(err)
+	}
+	return k.flushWorker.addEvent(ctx, mqEvent{
+		key:     topicPartitionKey{topic: k.txnTopic, partition: dispatcher.PartitionZero},
+		message: msg,
+	})
+}
+
+// isSameTxn returns whether two rows belong to the same transaction and table.
+func isSameTxn(a, b *model.RowChangedEvent) bool {
+	return a.StartTs == b.StartTs && a.CommitTs == b.CommitTs && *a.Table == *b.Table
+}
+
 // FlushRowChangedEvents is thread-safety
 func (k *mqSink) FlushRowChangedEvents(ctx context.Context, tableID model.TableID, resolvedTs uint64) (uint64, error) {
+	if k.txnTracker != nil {
+		// The COMMIT markers are sent before the resolved ts event, so they
+		// are flushed with the rows of their transactions.
+		for _, commit := range k.txnTracker.resolve(tableID, resolvedTs) {
+			if err := k.emitTxnEvent(ctx, commit); err != nil {
+				return 0, errors.Trace(err)
+			}
+		}
+	}
 	var checkpointTs uint64
 	v, ok := k.tableCheckpointTsMap.Load(tableID)
 	if ok {
@@ -236,6 +334,11 @@ func (k *mqSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model
 	if msg == nil {
 		return nil
 	}
+	if k.controlTopic != "" {
+		log.Debug("emit checkpointTs to control topic",
+			zap.String("topic", k.controlTopic), zap.Uint64("checkpointTs", ts))
+		return errors.Trace(k.flushToControlTopic(ctx, msg))
+	}
 	// NOTICE: When there is no table sync,
 	// we need to send checkpoint ts to the default topic. T
 	// This will be compatible with the old behavior.
@@ -280,14 +383,26 @@ func (k *mqSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 		return cerror.ErrDDLEventIgnored.GenWithStackByArgs()
 	}
 
+	routed, err := k.eventRouter.RouteDDL(ddl)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	encoder := k.encoderBuilder.Build()
-	msg, err := encoder.EncodeDDLEvent(ddl)
+	msg, err := encoder.EncodeDDLEvent(routed)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	if msg == nil {
 		return nil
 	}
+	if k.controlTopic != "" {
+		k.statistics.AddDDLCount()
+		log.Debug("emit ddl event to control topic",
+			zap.String("topic", k.controlTopic),
+			zap.Uint64("commitTs", ddl.CommitTs), zap.String("query", ddl.Query),
+			zap.String("changefeed", k.id), zap.Any("role", k.role))
+		return errors.Trace(k.flushToControlTopic(ctx, msg))
+	}
 
 	topic := k.eventRouter.GetTopicForDDL(ddl)
 	partitionRule := k.eventRouter.GetDLLDispatchRuleByProtocol(k.protocol)
@@ -315,6 +430,13 @@ func (k *mqSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 	return errors.Trace(err)
 }
 
+// RoutesTableNames implements the selfRoutingSink interface of the sink package.
+// The dispatch rules are matched against the source table names, so the
+// events must not be routed before they reach the MQ sink.
+func (k *mqSink) RoutesTableNames() bool {
+	return true
+}
+
 // Close the producer asynchronously, does not care closed successfully or not.
 func (k *mqSink) Close(ctx context.Context) error {
 	go k.mqProducer.Close()
@@ -322,8 +444,12 @@ func (k *mqSink) Close(ctx context.Context) error {
 }
 
 func (k *mqSink) Barrier(cxt context.Context, tableID model.TableID) error {
-	// Barrier does nothing because FlushRowChangedEvents in mq sink has flushed
-	// all buffered events by force.
+	// Barrier does not flush because FlushRowChangedEvents in mq sink has
+	// flushed all buffered events by force. The table is removed, so the
+	// transactions no longer wait for it to be resolved.
+	if k.txnTracker != nil {
+		k.txnTracker.removeTable(tableID)
+	}
 	return nil
 }
 
@@ -350,6 +476,45 @@ func (k *mqSink) asyncFlushToPartitionZero(
 	return k.mqProducer.Flush(ctx)
 }
 
+// flushToControlTopic writes message to partition zero of the control topic
+// and flush it immediately.
+func (k *mqSink) flushToControlTopic(ctx context.Context, message *codec.MQMessage) error {
+	// Partitions creates the control topic if it doesn't exist.
+	if _, err := k.topicManager.Partitions(k.controlTopic); err != nil {
+		return errors.Trace(err)
+	}
+	return k.asyncFlushToPartitionZero(ctx, k.controlTopic, message)
+}
+
+// shouldSplitHandleKeyUpdate returns whether an update event changes the
+// values of the handle key columns.
+func shouldSplitHandleKeyUpdate(row *model.RowChangedEvent) bool {
+	if !row.IsUpdate() || len(row.PreColumns) != len(row.Columns) {
+		return false
+	}
+	for i, col := range row.Columns {
+		preCol := row.PreColumns[i]
+		if col == nil || preCol == nil || !col.Flag.IsHandleKey() || !preCol.Flag.IsHandleKey() {
+			continue
+		}
+		if model.ColumnValueString(col.Value) != model.ColumnValueString(preCol.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitHandleKeyUpdate splits an update event into a delete event of the old
+// handle key and an insert event of the new one.
+func splitHandleKeyUpdate(row *model.RowChangedEvent) []*model.RowChangedEvent {
+	deleteRow := *row
+	deleteRow.Columns = nil
+	insertRow := *row
+	insertRow.PreColumns = nil
+	// NOTICE: The delete event must be sent before the insert event.
+	return []*model.RowChangedEvent{&deleteRow, &insertRow}
+}
+
 // NewKafkaSaramaSink creates a new Kafka mqSink.
 func NewKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 	filter *filter.Filter, replicaConfig *config.ReplicaConfig,
@@ -395,8 +560,15 @@ func NewKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
 	}
 	// always set encoder's `MaxMessageBytes` equal to producer's `MaxMessageBytes`
-	// to prevent that the encoder generate batched message too large then cause producer meet `message too large`
-	encoderConfig = encoderConfig.WithMaxMessageBytes(saramaConfig.Producer.MaxMessageBytes)
+	// to prevent that the encoder generate batched message too large then cause producer meet `message too large`,
+	// the space of the headers attached after encoding is left.
+	maxMessageBytes := saramaConfig.Producer.MaxMessageBytes
+	headersLength := maxHeadersLength(replicaConfig.Sink.Headers, util.ChangefeedIDFromCtx(ctx))
+	if headersLength >= maxMessageBytes {
+		return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"the headers may take %d bytes, which exceeds max-message-bytes %d", headersLength, maxMessageBytes)
+	}
+	encoderConfig = encoderConfig.WithMaxMessageBytes(maxMessageBytes - headersLength)
 
 	if err := encoderConfig.Validate(); err != nil {
 		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
@@ -407,14 +579,47 @@ func NewKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 		return nil, cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
 	}
 
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	controlTopic, err := getControlTopic(sinkURI, protocol, topic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	txnTopic, err := getTxnTopic(sinkURI, encoderConfig, topic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	topicConfig := baseConfig.DeriveTopicConfig()
+	// The topics of the dispatch rules may be created with their own configurations.
+	topicConfig.TopicConfigs = func(topicName string) *config.TopicConfig {
+		if cfg := eventRouter.GetTopicConfig(topicName); cfg != nil {
+			return cfg
+		}
+		// The events in the control topic and the transaction topic are
+		// ordered only if they have a single partition.
+		if topicName == controlTopic || topicName == txnTopic {
+			return &config.TopicConfig{PartitionNum: 1}
+		}
+		return nil
+	}
 	topicManager := kafkamanager.NewTopicManager(
 		client,
 		adminClient,
-		baseConfig.DeriveTopicConfig(),
+		topicConfig,
 	)
 	if _, err := topicManager.CreateTopic(topic); err != nil {
 		return nil, cerror.WrapError(cerror.ErrKafkaCreateTopic, err)
 	}
+	for _, internalTopic := range []string{controlTopic, txnTopic} {
+		if internalTopic == "" {
+			continue
+		}
+		if _, err := topicManager.CreateTopic(internalTopic); err != nil {
+			return nil, cerror.WrapError(cerror.ErrKafkaCreateTopic, err)
+		}
+	}
 
 	sProducer, err := kafka.NewKafkaSaramaProducer(
 		ctx,
@@ -435,6 +640,8 @@ func NewKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 		sProducer,
 		filter,
 		topic,
+		controlTopic,
+		txnTopic,
 		replicaConfig,
 		encoderConfig,
 		errCh,
@@ -462,6 +669,13 @@ func NewPulsarSink(ctx context.Context, sinkURI *url.URL, filter *filter.Filter,
 	if err := protocol.FromString(replicaConfig.Sink.Protocol); err != nil {
 		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
 	}
+	if protocol == config.ProtocolState {
+		return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"the state protocol is only supported by the Kafka sink")
+	}
+	if err := pulsar.CheckRoutingMode(sinkURI, protocol); err != nil {
+		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+	}
 
 	encoderConfig := codec.NewConfig(protocol, util.TimezoneFromCtx(ctx))
 	if err := encoderConfig.Apply(sinkURI, opts); err != nil {
@@ -472,6 +686,12 @@ func NewPulsarSink(ctx context.Context, sinkURI *url.URL, filter *filter.Filter,
 	if err := encoderConfig.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	// The default topic of the pulsar sink is resolved by the producer,
+	// so the transaction topic must be set explicitly.
+	txnTopic, err := getTxnTopic(sinkURI, encoderConfig, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	producer, err := pulsar.NewProducer(sinkURI, errCh)
 	if err != nil {
@@ -480,16 +700,16 @@ func NewPulsarSink(ctx context.Context, sinkURI *url.URL, filter *filter.Filter,
 	// For now, it's a placeholder. Avro format have to make connection to Schema Registry,
 	// and it may need credential.
 	credential := &security.Credential{}
-	fakeTopicManager := pulsarmanager.NewTopicManager(
-		producer.GetPartitionNum(),
-	)
+	topicManager := pulsarmanager.NewTopicManager(producer)
 	sink, err := newMqSink(
 		ctx,
 		credential,
-		fakeTopicManager,
+		topicManager,
 		producer,
 		filter,
 		"",
+		"",
+		txnTopic,
 		replicaConfig,
 		encoderConfig,
 		errCh,
@@ -499,3 +719,44 @@ func NewPulsarSink(ctx context.Context, sinkURI *url.URL, filter *filter.Filter,
 	}
 	return sink, nil
 }
+
+// getControlTopic returns the control topic of the state protocol, which is
+// set by the `control-topic` parameter of the sink URI, or `<topic>_control`
+// by default. It returns an empty string for the other protocols.
+func getControlTopic(sinkURI *url.URL, protocol config.Protocol, topic string) (string, error) {
+	if protocol != config.ProtocolState {
+		return "", nil
+	}
+	controlTopic := sinkURI.Query().Get("control-topic")
+	if controlTopic == "" {
+		controlTopic = topic + "_control"
+	}
+	if controlTopic == topic {
+		return "", cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"the control topic %s can't be the same as the topic of the rows", controlTopic)
+	}
+	return controlTopic, nil
+}
+
+// getTxnTopic returns the topic which the transaction markers are sent to,
+// which is set by the `transaction-topic` parameter of the sink URI, or
+// `<topic>_transaction` by default. It returns an empty string if the
+// transaction metadata is disabled.
+func getTxnTopic(sinkURI *url.URL, encoderConfig *codec.Config, topic string) (string, error) {
+	if !encoderConfig.EnableTxnMetadata() {
+		return "", nil
+	}
+	txnTopic := sinkURI.Query().Get("transaction-topic")
+	if txnTopic == "" {
+		if topic == "" {
+			return "", cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"transaction-topic is required by enable-txn-metadata")
+		}
+		txnTopic = topic + "_transaction"
+	}
+	if txnTopic == topic {
+		return "", cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"the transaction topic %s can't be the same as the topic of the rows", txnTopic)
+	}
+	return txnTopic, nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/mq/producer"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -46,6 +47,9 @@ type mqEvent struct {
 	key        topicPartitionKey
 	row        *model.RowChangedEvent
 	resolvedTs model.Ts
+	// message is an encoded transaction marker, it is sent in order with
+	// the rows, to the topic and partition of the key.
+	message *codec.MQMessage
 }
 
 // flushWorker is responsible for sending messages to the Kafka producer on a batch basis.
@@ -63,6 +67,9 @@ type flushWorker struct {
 	encoder    codec.EventBatchEncoder
 	producer   producer.Producer
 	statistics *metrics.Statistics
+	// partitioner decides the partitions of the rows. If it is nil, the
+	// partitions of the events are decided before they are added.
+	partitioner *rowPartitioner
 }
 
 // newFlushWorker creates a new flush worker.
@@ -70,16 +77,18 @@ func newFlushWorker(
 	encoder codec.EventBatchEncoder,
 	producer producer.Producer,
 	statistics *metrics.Statistics,
+	partitioner *rowPartitioner,
 ) *flushWorker {
 	w := &flushWorker{
 		msgChan: make(chan mqEvent),
 		ticker:  time.NewTicker(flushInterval),
 		// errCh must be a buffered channel, or otherwise sending error to it will
 		// almost certainly go to the default branch, making errCh useless.
-		errCh:      make(chan error, 1),
-		encoder:    encoder,
-		producer:   producer,
-		statistics: statistics,
+		errCh:       make(chan error, 1),
+		encoder:     encoder,
+		producer:    producer,
+		statistics:  statistics,
+		partitioner: partitioner,
 	}
 	return w
 }
@@ -103,7 +112,7 @@ func (w *flushWorker) batch(
 			return index, nil
 		}
 
-		if msg.row != nil {
+		if msg.row != nil || msg.message != nil {
 			events[index] = msg
 			index++
 		}
@@ -121,7 +130,7 @@ func (w *flushWorker) batch(
 				return index, nil
 			}
 
-			if msg.row != nil {
+			if msg.row != nil || msg.message != nil {
 				events[index] = msg
 				index++
 			}
@@ -135,6 +144,22 @@ func (w *flushWorker) batch(
 	}
 }
 
+// partition decides the partitions of the rows of the events.
+func (w *flushWorker) partition(events []mqEvent) error {
+	if w.partitioner == nil {
+		return nil
+	}
+	for i := range events {
+		if events[i].row == nil {
+			continue
+		}
+		if err := w.partitioner.partition(&events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // group is responsible for grouping messages by the partition.
 func (w *flushWorker) group(events []mqEvent) map[topicPartitionKey][]*model.RowChangedEvent {
 	partitionedRows := make(map[topicPartitionKey][]*model.RowChangedEvent)
@@ -147,13 +172,49 @@ func (w *flushWorker) group(events []mqEvent) map[topicPartitionKey][]*model.Row
 	return partitionedRows
 }
 
+// send sends the rows and the transaction markers of the events in order,
+// the rows between two markers are grouped by the partition.
+func (w *flushWorker) send(ctx context.Context, events []mqEvent) error {
+	start := 0
+	for i, event := range events {
+		if event.message == nil {
+			continue
+		}
+		if err := w.sendRows(ctx, w.group(events[start:i])); err != nil {
+			return err
+		}
+		err := w.producer.AsyncSendMessage(ctx, event.key.topic, event.key.partition, event.message)
+		if err != nil {
+			return err
+		}
+		start = i + 1
+	}
+	return w.asyncSend(ctx, w.group(events[start:]))
+}
+
 // asyncSend is responsible for sending messages to the Kafka producer.
 func (w *flushWorker) asyncSend(
 	ctx context.Context,
 	partitionedRows map[topicPartitionKey][]*model.RowChangedEvent,
+) error {
+	if err := w.sendRows(ctx, partitionedRows); err != nil {
+		return err
+	}
+	return w.flush(ctx)
+}
+
+func (w *flushWorker) sendRows(
+	ctx context.Context,
+	partitionedRows map[topicPartitionKey][]*model.RowChangedEvent,
 ) error {
 	for key, events := range partitionedRows {
+		// The messages of a batch carry the trace of its first sampled row.
+		traceParent := ""
 		for _, event := range events {
+			event.Trace = tracing.Stage(event.Trace, tracing.StageMQSink)
+			if traceParent == "" && event.Trace != nil {
+				traceParent = event.Trace.Parent
+			}
 			err := w.encoder.AppendRowChangedEvent(event)
 			if err != nil {
 				return err
@@ -163,6 +224,7 @@ func (w *flushWorker) asyncSend(
 		err := w.statistics.RecordBatchExecution(func() (int, error) {
 			thisBatchSize := 0
 			for _, message := range w.encoder.Build() {
+				message.TraceParent = traceParent
 				err := w.producer.AsyncSendMessage(ctx, key.topic, key.partition, message)
 				if err != nil {
 					return 0, err
@@ -177,17 +239,27 @@ func (w *flushWorker) asyncSend(
 		}
 		w.statistics.ObserveRows(events...)
 	}
+	return nil
+}
 
-	if w.needSyncFlush {
-		start := time.Now()
-		err := w.producer.Flush(ctx)
-		if err != nil {
-			return err
-		}
-		w.needSyncFlush = false
-		log.Debug("flush worker flushed", zap.Duration("duration", time.Since(start)))
+// flush flushes the producer if a resolved ts has been received. After that,
+// all the dispatched messages have been sent, so it is a barrier to use the
+// new numbers of partitions of the topics.
+func (w *flushWorker) flush(ctx context.Context) error {
+	if !w.needSyncFlush {
+		return nil
+	}
+	start := time.Now()
+	err := w.producer.Flush(ctx)
+	if err != nil {
+		return err
 	}
+	w.needSyncFlush = false
+	log.Debug("flush worker flushed", zap.Duration("duration", time.Since(start)))
 
+	if w.partitioner != nil {
+		return w.partitioner.barrier()
+	}
 	return nil
 }
 
@@ -209,11 +281,17 @@ func (w *flushWorker) run(ctx context.Context) (retErr error) {
 			return errors.Trace(err)
 		}
 		if endIndex == 0 {
+			// A resolved ts may be received without any row.
+			if err := w.flush(ctx); err != nil {
+				return errors.Trace(err)
+			}
 			continue
 		}
 		msgs := eventsBuf[:endIndex]
-		partitionedRows := w.group(msgs)
-		err = w.asyncSend(ctx, partitionedRows)
+		if err := w.partition(msgs); err != nil {
+			return errors.Trace(err)
+		}
+		err = w.send(ctx, msgs)
 		if err != nil {
 			return errors.Trace(err)
 		}
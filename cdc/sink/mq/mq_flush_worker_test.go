@@ -17,6 +17,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/util/timeutil"
@@ -25,11 +26,14 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/stretchr/testify/require"
 )
 
 type mockProducer struct {
 	mqEvent map[topicPartitionKey][]*codec.MQMessage
+	// sent keeps the messages of all the partitions in the sending order.
+	sent    []*codec.MQMessage
 	flushed bool
 
 	mockErr chan error
@@ -52,6 +56,7 @@ func (m *mockProducer) AsyncSendMessage(
 		m.mqEvent[key] = make([]*codec.MQMessage, 0)
 	}
 	m.mqEvent[key] = append(m.mqEvent[key], message)
+	m.sent = append(m.sent, message)
 	return nil
 }
 
@@ -95,7 +100,7 @@ func newTestWorker() (*flushWorker, *mockProducer) {
 	}
 	producer := NewMockProducer()
 	return newFlushWorker(encoder, producer,
-		metrics.NewStatistics(context.Background(), metrics.SinkTypeMQ)), producer
+		metrics.NewStatistics(context.Background(), metrics.SinkTypeMQ), nil), producer
 }
 
 func TestBatch(t *testing.T) {
@@ -349,6 +354,90 @@ func TestAsyncSend(t *testing.T) {
 	require.Len(t, producer.mqEvent[key3], 2)
 }
 
+func TestSendTxnMarkers(t *testing.T) {
+	t.Parallel()
+
+	rowKey := topicPartitionKey{topic: "test", partition: 1}
+	txnKey := topicPartitionKey{topic: "test_transaction"}
+	newRow := func(value string) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:  1,
+			CommitTs: 2,
+			Table:    &model.TableName{Schema: "a", Table: "b"},
+			Columns:  []*model.Column{{Name: "col1", Type: 1, Value: value}},
+		}
+	}
+	newMarker := func(status string) *codec.MQMessage {
+		return codec.NewMQMessage(config.ProtocolOpen, nil, []byte(status), 2, model.MqMessageTypeTxn, nil, nil)
+	}
+
+	worker, producer := newTestWorker()
+	err := worker.send(context.Background(), []mqEvent{
+		{key: txnKey, message: newMarker("BEGIN")},
+		{key: rowKey, row: newRow("aa")},
+		{key: rowKey, row: newRow("bb")},
+		{key: txnKey, message: newMarker("COMMIT")},
+	})
+	require.NoError(t, err)
+	require.Len(t, producer.mqEvent[txnKey], 2)
+
+	// The markers are sent before and after the rows of the transaction.
+	require.GreaterOrEqual(t, len(producer.sent), 3)
+	first, last := producer.sent[0], producer.sent[len(producer.sent)-1]
+	require.Equal(t, "BEGIN", string(first.Value))
+	require.Equal(t, "COMMIT", string(last.Value))
+	rows := 0
+	for _, message := range producer.sent[1 : len(producer.sent)-1] {
+		require.Equal(t, model.MqMessageTypeRow, message.Type)
+		rows += message.GetRowsCount()
+	}
+	require.Equal(t, 2, rows)
+}
+
+func TestAsyncSendTraceParent(t *testing.T) {
+	ctx := context.Background()
+	shutdown, err := tracing.Init(ctx, &config.TracingConfig{
+		Enable:     true,
+		Endpoint:   "127.0.0.1:0",
+		SampleRate: 1,
+	}, "")
+	require.Nil(t, err)
+	defer func() {
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_ = shutdown(ctx)
+	}()
+
+	key1 := topicPartitionKey{topic: "test", partition: 1}
+	key2 := topicPartitionKey{topic: "test", partition: 2}
+	traced := &model.RowChangedEvent{
+		StartTs:  1,
+		CommitTs: 2,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+		Trace:    tracing.NewTrace(1, 2, 1),
+	}
+	require.NotNil(t, traced.Trace)
+	untraced := &model.RowChangedEvent{
+		CommitTs: 2,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "bb"}},
+	}
+
+	worker, producer := newTestWorker()
+	err = worker.asyncSend(ctx, map[topicPartitionKey][]*model.RowChangedEvent{
+		key1: {untraced, traced},
+		key2: {untraced},
+	})
+	require.Nil(t, err)
+	require.Len(t, producer.mqEvent[key1], 2)
+	for _, message := range producer.mqEvent[key1] {
+		require.Equal(t, traced.Trace.Parent, message.TraceParent)
+	}
+	require.Len(t, producer.mqEvent[key2], 1)
+	require.Empty(t, producer.mqEvent[key2][0].TraceParent)
+}
+
 func TestFlush(t *testing.T) {
 	t.Parallel()
 
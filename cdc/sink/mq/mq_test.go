@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/check"
@@ -24,6 +25,8 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	kafkap "github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -346,3 +349,254 @@ func (s mqSinkSuite) TestFlushRowChangedEvents(c *check.C) {
 		c.Assert(errors.Cause(err), check.Equals, context.Canceled)
 	}
 }
+
+func (s mqSinkSuite) TestKafkaSinkStateProtocol(c *check.C) {
+	defer testleak.AfterTest(c)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	topic := kafka.DefaultMockTopicName
+	controlTopic := topic + "_control"
+	leader := sarama.NewMockBroker(c, 1)
+	defer leader.Close()
+
+	metadataResponse := new(sarama.MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition(topic, 0, leader.BrokerID(), nil, nil, nil, sarama.ErrNoError)
+	metadataResponse.AddTopicPartition(controlTopic, 0, leader.BrokerID(), nil, nil, nil, sarama.ErrNoError)
+	leader.Returns(metadataResponse)
+	leader.Returns(metadataResponse)
+
+	prodSuccess := new(sarama.ProduceResponse)
+	prodSuccess.AddTopicPartition(topic, 0, sarama.ErrNoError)
+	prodSuccess.AddTopicPartition(controlTopic, 0, sarama.ErrNoError)
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=true&protocol=state"
+	sinkURI, err := url.Parse(fmt.Sprintf(uriTemplate, leader.Addr(), topic))
+	c.Assert(err, check.IsNil)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	fr, err := filter.NewFilter(replicaConfig)
+	c.Assert(err, check.IsNil)
+	errCh := make(chan error, 1)
+
+	kafkap.NewAdminClientImpl = kafka.NewMockAdminClient
+	defer func() {
+		kafkap.NewAdminClientImpl = kafka.NewSaramaAdminClient
+	}()
+
+	sink, err := NewKafkaSaramaSink(ctx, sinkURI, fr, replicaConfig, map[string]string{}, errCh)
+	c.Assert(err, check.IsNil)
+	c.Assert(sink.controlTopic, check.Equals, controlTopic)
+	c.Assert(sink.encoderBuilder.Build(), check.FitsTypeOf, &codec.StateEventBatchEncoder{})
+	// The control topic is created with a single partition.
+	partitionNum, err := sink.topicManager.Partitions(controlTopic)
+	c.Assert(err, check.IsNil)
+	c.Assert(partitionNum, check.Equals, int32(1))
+
+	// mock kafka broker processes 1 checkpoint ts event and 1 ddl event
+	leader.Returns(prodSuccess)
+	err = sink.EmitCheckpointTs(ctx, uint64(120), nil)
+	c.Assert(err, check.IsNil)
+	leader.Returns(prodSuccess)
+	err = sink.EmitDDLEvent(ctx, &model.DDLEvent{
+		StartTs:   130,
+		CommitTs:  140,
+		TableInfo: &model.SimpleTableInfo{Schema: "a", Table: "b"},
+		Query:     "create table a.b(id int primary key)",
+		Type:      3,
+	})
+	c.Assert(err, check.IsNil)
+
+	err = sink.Close(ctx)
+	if err != nil {
+		c.Assert(errors.Cause(err), check.Equals, context.Canceled)
+	}
+}
+
+func (s mqSinkSuite) TestGetControlTopic(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/test?protocol=state")
+	c.Assert(err, check.IsNil)
+	controlTopic, err := getControlTopic(sinkURI, config.ProtocolState, "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(controlTopic, check.Equals, "test_control")
+	// The other protocols don't have the control topic.
+	controlTopic, err = getControlTopic(sinkURI, config.ProtocolOpen, "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(controlTopic, check.Equals, "")
+
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/test?protocol=state&control-topic=ctl")
+	c.Assert(err, check.IsNil)
+	controlTopic, err = getControlTopic(sinkURI, config.ProtocolState, "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(controlTopic, check.Equals, "ctl")
+
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/test?protocol=state&control-topic=test")
+	c.Assert(err, check.IsNil)
+	_, err = getControlTopic(sinkURI, config.ProtocolState, "test")
+	c.Assert(err, check.ErrorMatches, ".*can't be the same as the topic of the rows.*")
+}
+
+func (s mqSinkSuite) TestSplitHandleKeyUpdate(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	buildColumns := func(id int, name string) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: 3, Flag: model.HandleKeyFlag, Value: id},
+			{Name: "name", Type: 15, Value: name},
+		}
+	}
+	row := &model.RowChangedEvent{
+		Table:      &model.TableName{Schema: "test", Table: "t1"},
+		PreColumns: buildColumns(1, "a"),
+		Columns:    buildColumns(1, "b"),
+	}
+	c.Assert(shouldSplitHandleKeyUpdate(row), check.IsFalse)
+
+	row.Columns = buildColumns(2, "b")
+	c.Assert(shouldSplitHandleKeyUpdate(row), check.IsTrue)
+	rows := splitHandleKeyUpdate(row)
+	c.Assert(rows, check.HasLen, 2)
+	c.Assert(rows[0].IsDelete(), check.IsTrue)
+	c.Assert(rows[0].PreColumns, check.DeepEquals, row.PreColumns)
+	c.Assert(rows[1].IsInsert(), check.IsTrue)
+	c.Assert(rows[1].Columns, check.DeepEquals, row.Columns)
+
+	// The insert and delete events are never split.
+	c.Assert(shouldSplitHandleKeyUpdate(rows[0]), check.IsFalse)
+	c.Assert(shouldSplitHandleKeyUpdate(rows[1]), check.IsFalse)
+}
+
+func (s mqSinkSuite) TestPulsarSinkStateProtocol(c *check.C) {
+	defer testleak.AfterTest(c)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sinkURI, err := url.Parse("pulsar://127.0.0.1:1234/kafka-test?protocol=state")
+	c.Assert(err, check.IsNil)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	fr, err := filter.NewFilter(replicaConfig)
+	c.Assert(err, check.IsNil)
+	_, err = NewPulsarSink(ctx, sinkURI, fr, replicaConfig, map[string]string{}, make(chan error, 1))
+	c.Assert(err, check.ErrorMatches, ".*the state protocol is only supported by the Kafka sink.*")
+}
+
+func (s mqSinkSuite) TestEmitTxnMetadata(c *check.C) {
+	defer testleak.AfterTest(c)()
+	ctx := context.Background()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	fr, err := filter.NewFilter(replicaConfig)
+	c.Assert(err, check.IsNil)
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, "test")
+	c.Assert(err, check.IsNil)
+	worker, producer := newTestWorker()
+	// The rows are buffered instead of being sent by the worker.
+	worker.msgChan = make(chan mqEvent, 8)
+	sink := &mqSink{
+		mqProducer:  producer,
+		eventRouter: eventRouter,
+		filter:      fr,
+		protocol:    config.ProtocolOpen,
+		txnTopic:    "test_transaction",
+		txnTracker:  newTxnTracker(),
+		topicManager: &mockTopicManager{partitions: map[string]int32{
+			"test": 1, "test_transaction": 1,
+		}},
+		flushWorker:    worker,
+		resolvedBuffer: make(chan resolvedTsEvent, 8),
+		statistics:     metrics.NewStatistics(ctx, metrics.SinkTypeMQ),
+	}
+	c.Assert(sink.Init(1), check.IsNil)
+	c.Assert(sink.Init(2), check.IsNil)
+
+	// The rows of different tables are emitted separately.
+	t1 := &model.TableName{Schema: "a", Table: "t1", TableID: 1}
+	t2 := &model.TableName{Schema: "a", Table: "t2", TableID: 2}
+	err = sink.EmitRowChangedEvents(ctx,
+		&model.RowChangedEvent{StartTs: 1, CommitTs: 2, Table: t1},
+		&model.RowChangedEvent{StartTs: 1, CommitTs: 2, Table: t1},
+		&model.RowChangedEvent{StartTs: 3, CommitTs: 4, Table: t1},
+	)
+	c.Assert(err, check.IsNil)
+	_, err = sink.FlushRowChangedEvents(ctx, 1, 4)
+	c.Assert(err, check.IsNil)
+	err = sink.EmitRowChangedEvents(ctx,
+		&model.RowChangedEvent{StartTs: 1, CommitTs: 2, Table: t2},
+	)
+	c.Assert(err, check.IsNil)
+	// The transactions are committed once all the tables are resolved.
+	_, err = sink.FlushRowChangedEvents(ctx, 2, 3)
+	c.Assert(err, check.IsNil)
+
+	// The markers are added to the worker in order with the rows, and the
+	// rows carry their positions in the transactions.
+	c.Assert(producer.mqEvent, check.HasLen, 0)
+	expected := []string{
+		`{"status":"BEGIN","start-ts":1,"commit-ts":2}`,
+		"1",
+		"2",
+		`{"status":"BEGIN","start-ts":3,"commit-ts":4}`,
+		"1",
+		"3",
+		`{"status":"COMMIT","start-ts":1,"commit-ts":2,"row-count":3,"table-row-counts":{"a.t1":2,"a.t2":1}}`,
+	}
+	c.Assert(worker.msgChan, check.HasLen, len(expected))
+	for _, value := range expected {
+		event := <-worker.msgChan
+		if event.row != nil {
+			c.Assert(strconv.FormatInt(event.row.TxnIndex, 10), check.Equals, value)
+			continue
+		}
+		c.Assert(event.key, check.Equals, topicPartitionKey{topic: "test_transaction"})
+		c.Assert(string(event.message.Value), check.Equals, value)
+		c.Assert(event.message.Type, check.Equals, model.MqMessageTypeTxn)
+	}
+
+	// The pending transaction is committed after the table is removed.
+	c.Assert(sink.Barrier(ctx, 2), check.IsNil)
+	_, err = sink.FlushRowChangedEvents(ctx, 1, 5)
+	c.Assert(err, check.IsNil)
+	c.Assert(worker.msgChan, check.HasLen, 1)
+	event := <-worker.msgChan
+	c.Assert(string(event.message.Value), check.Equals,
+		`{"status":"COMMIT","start-ts":3,"commit-ts":4,"row-count":1,"table-row-counts":{"a.t1":1}}`)
+}
+
+func (s mqSinkSuite) TestGetTxnTopic(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	newEncoderConfig := func(uri string) (*url.URL, *codec.Config) {
+		sinkURI, err := url.Parse(uri)
+		c.Assert(err, check.IsNil)
+		encoderConfig := codec.NewConfig(config.ProtocolOpen, nil)
+		c.Assert(encoderConfig.Apply(sinkURI, map[string]string{}), check.IsNil)
+		return sinkURI, encoderConfig
+	}
+
+	sinkURI, encoderConfig := newEncoderConfig("kafka://127.0.0.1:9092/test")
+	txnTopic, err := getTxnTopic(sinkURI, encoderConfig, "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(txnTopic, check.Equals, "")
+
+	sinkURI, encoderConfig = newEncoderConfig("kafka://127.0.0.1:9092/test?enable-txn-metadata=true")
+	txnTopic, err = getTxnTopic(sinkURI, encoderConfig, "test")
+	c.Assert(err, check.IsNil)
+	c.Assert(txnTopic, check.Equals, "test_transaction")
+	// The pulsar sink requires the transaction topic to be set.
+	_, err = getTxnTopic(sinkURI, encoderConfig, "")
+	c.Assert(err, check.ErrorMatches, ".*transaction-topic is required by enable-txn-metadata.*")
+
+	sinkURI, encoderConfig = newEncoderConfig(
+		"pulsar://127.0.0.1:6650/test?enable-txn-metadata=true&transaction-topic=txn")
+	txnTopic, err = getTxnTopic(sinkURI, encoderConfig, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(txnTopic, check.Equals, "txn")
+
+	sinkURI, encoderConfig = newEncoderConfig(
+		"kafka://127.0.0.1:9092/test?enable-txn-metadata=true&transaction-topic=test")
+	_, err = getTxnTopic(sinkURI, encoderConfig, "test")
+	c.Assert(err, check.ErrorMatches, ".*can't be the same as the topic of the rows.*")
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
+	"github.com/pingcap/tiflow/cdc/sink/mq/manager"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// partitionTopicTTL is how long a topic which no row is dispatched to is kept
+// by the row partitioner, it is the same as the idle time of the column topics
+// of the event router.
+const partitionTopicTTL = 10 * time.Minute
+
+// rowPartitioner decides the partitions of the rows by the numbers of
+// partitions of their topics. The operators may add partitions to a topic,
+// after which the rows with the same key may be dispatched to another
+// partition. To keep the messages with the same key in order, the new number
+// of partitions is only used after a resolved ts barrier, when all the
+// messages dispatched by the old one have been flushed.
+// It is only used by the flush worker, so it is not thread-safe.
+type rowPartitioner struct {
+	eventRouter  *dispatcher.EventRouter
+	topicManager manager.TopicManager
+	// partitionNums are the numbers of partitions used to dispatch the rows.
+	// The topics which have been idle for partitionTopicTTL are removed at
+	// the barrier, so that the topics of the column dispatchers which are no
+	// longer used are not kept forever.
+	partitionNums map[string]*topicPartitionNum
+	// fixedPartitionNums is set by the state protocol, the states of the rows
+	// in a compacted topic are stale if the rows are dispatched to another
+	// partition, so the partitions of the topics must not be changed.
+	fixedPartitionNums bool
+	// We use a `clock.Clock` here to make time mockable in unit tests.
+	clock clock.Clock
+}
+
+type topicPartitionNum struct {
+	partitionNum int32
+	// used is set if a row is dispatched to the topic since the last barrier.
+	used     bool
+	lastUsed time.Time
+}
+
+func newRowPartitioner(
+	eventRouter *dispatcher.EventRouter, topicManager manager.TopicManager, fixedPartitionNums bool,
+) *rowPartitioner {
+	return &rowPartitioner{
+		eventRouter:        eventRouter,
+		topicManager:       topicManager,
+		partitionNums:      make(map[string]*topicPartitionNum),
+		fixedPartitionNums: fixedPartitionNums,
+		clock:              clock.New(),
+	}
+}
+
+// partition sets the partition of the event and routes the table name of its
+// row, the dispatch rule is matched by the source table name.
+func (p *rowPartitioner) partition(event *mqEvent) error {
+	topic := event.key.topic
+	num, ok := p.partitionNums[topic]
+	if !ok {
+		// The topic is created here if it does not exist.
+		partitionNum, err := p.topicManager.Partitions(topic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		num = &topicPartitionNum{partitionNum: partitionNum}
+		p.partitionNums[topic] = num
+	}
+	num.used = true
+	event.row, event.key.partition = p.eventRouter.RouteRowChangeToPartition(event.row, num.partitionNum)
+	return nil
+}
+
+// barrier updates the numbers of partitions used to dispatch the rows, and
+// removes the idle topics. It must be called after all the dispatched
+// messages have been flushed.
+func (p *rowPartitioner) barrier() error {
+	now := p.clock.Now()
+	for topic, num := range p.partitionNums {
+		if num.used {
+			num.used = false
+			num.lastUsed = now
+		} else if now.Sub(num.lastUsed) >= partitionTopicTTL {
+			delete(p.partitionNums, topic)
+			continue
+		}
+		partitionNum, err := p.topicManager.Partitions(topic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if partitionNum != num.partitionNum {
+			if p.fixedPartitionNums {
+				return cerror.ErrKafkaInvalidPartitionNum.GenWithStack(
+					"the number of partitions of topic %s is changed from %d to %d, "+
+						"which is not supported by the state protocol", topic, num.partitionNum, partitionNum)
+			}
+			log.Info("the number of partitions of the topic is changed, "+
+				"the rows are dispatched by the new one after the resolved ts barrier",
+				zap.String("topic", topic),
+				zap.Int32("oldPartitionNumber", num.partitionNum),
+				zap.Int32("newPartitionNumber", partitionNum))
+			num.partitionNum = partitionNum
+		}
+	}
+	return nil
+}
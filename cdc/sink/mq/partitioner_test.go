@@ -0,0 +1,190 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTopicManager struct {
+	partitions map[string]int32
+}
+
+func (m *mockTopicManager) Partitions(topic string) (int32, error) {
+	partitionNum, ok := m.partitions[topic]
+	if !ok {
+		return 0, errors.Errorf("topic %s not found", topic)
+	}
+	return partitionNum, nil
+}
+
+func (m *mockTopicManager) CreateTopic(topic string) (int32, error) {
+	return m.Partitions(topic)
+}
+
+func newTestPartitioner(t *testing.T) (*rowPartitioner, *mockTopicManager) {
+	eventRouter, err := dispatcher.NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			// The rows are dispatched by commitTs % partitionNum.
+			DispatchRules: []*config.DispatchRule{{Matcher: []string{"*.*"}, PartitionRule: "ts"}},
+			Routes:        []*config.RouteRule{{Matcher: []string{"a.*"}, TargetSchema: "routed"}},
+		},
+	}, "test")
+	require.Nil(t, err)
+	topicManager := &mockTopicManager{partitions: map[string]int32{"test": 4}}
+	return newRowPartitioner(eventRouter, topicManager, false), topicManager
+}
+
+func TestRowPartitioner(t *testing.T) {
+	t.Parallel()
+
+	partitioner, topicManager := newTestPartitioner(t)
+	newEvent := func(commitTs uint64) *mqEvent {
+		return &mqEvent{
+			row: &model.RowChangedEvent{
+				CommitTs: commitTs,
+				Table:    &model.TableName{Schema: "a", Table: "b"},
+			},
+			key: topicPartitionKey{topic: "test"},
+		}
+	}
+
+	event := newEvent(5)
+	require.Nil(t, partitioner.partition(event))
+	require.Equal(t, int32(1), event.key.partition)
+	require.Equal(t, "routed", event.row.Table.Schema)
+
+	// The new number of partitions is used after the barrier.
+	topicManager.partitions["test"] = 8
+	event = newEvent(5)
+	require.Nil(t, partitioner.partition(event))
+	require.Equal(t, int32(1), event.key.partition)
+	require.Nil(t, partitioner.barrier())
+	event = newEvent(5)
+	require.Nil(t, partitioner.partition(event))
+	require.Equal(t, int32(5), event.key.partition)
+
+	event = newEvent(5)
+	event.key.topic = "unknown"
+	require.Regexp(t, ".*topic unknown not found.*", partitioner.partition(event))
+}
+
+func TestRowPartitionerFixedPartitionNums(t *testing.T) {
+	t.Parallel()
+
+	partitioner, topicManager := newTestPartitioner(t)
+	partitioner.fixedPartitionNums = true
+	event := &mqEvent{
+		row: &model.RowChangedEvent{
+			CommitTs: 5,
+			Table:    &model.TableName{Schema: "a", Table: "b"},
+		},
+		key: topicPartitionKey{topic: "test"},
+	}
+	require.Nil(t, partitioner.partition(event))
+	require.Nil(t, partitioner.barrier())
+
+	topicManager.partitions["test"] = 8
+	require.Regexp(t, ".*partitions of topic test is changed from 4 to 8.*state protocol.*",
+		partitioner.barrier())
+}
+
+func TestRowPartitionerRemoveIdleTopics(t *testing.T) {
+	t.Parallel()
+
+	partitioner, topicManager := newTestPartitioner(t)
+	mockClock := clock.NewMock()
+	partitioner.clock = mockClock
+	topicManager.partitions["idle"] = 2
+	newEvent := func(topic string) *mqEvent {
+		return &mqEvent{
+			row: &model.RowChangedEvent{
+				CommitTs: 5,
+				Table:    &model.TableName{Schema: "a", Table: "b"},
+			},
+			key: topicPartitionKey{topic: topic},
+		}
+	}
+
+	require.Nil(t, partitioner.partition(newEvent("test")))
+	require.Nil(t, partitioner.partition(newEvent("idle")))
+	require.Nil(t, partitioner.barrier())
+	require.Len(t, partitioner.partitionNums, 2)
+
+	// The topic which is still used is kept, the idle one is removed.
+	mockClock.Add(partitionTopicTTL / 2)
+	require.Nil(t, partitioner.partition(newEvent("test")))
+	require.Nil(t, partitioner.barrier())
+	require.Len(t, partitioner.partitionNums, 2)
+	mockClock.Add(partitionTopicTTL / 2)
+	require.Nil(t, partitioner.barrier())
+	require.Len(t, partitioner.partitionNums, 1)
+	require.Contains(t, partitioner.partitionNums, "test")
+
+	// A removed topic is added back with its current number of partitions.
+	topicManager.partitions["idle"] = 4
+	event := newEvent("idle")
+	require.Nil(t, partitioner.partition(event))
+	require.Equal(t, int32(1), event.key.partition)
+	require.Equal(t, int32(4), partitioner.partitionNums["idle"].partitionNum)
+
+	// The idle topics are removed without querying their partitions.
+	mockClock.Add(partitionTopicTTL)
+	require.Nil(t, partitioner.barrier())
+	require.Len(t, partitioner.partitionNums, 1)
+	delete(topicManager.partitions, "idle")
+	mockClock.Add(partitionTopicTTL)
+	require.Nil(t, partitioner.barrier())
+	require.Len(t, partitioner.partitionNums, 0)
+}
+
+func TestFlushWorkerPartitionAtBarrier(t *testing.T) {
+	t.Parallel()
+
+	worker, producer := newTestWorker()
+	partitioner, topicManager := newTestPartitioner(t)
+	worker.partitioner = partitioner
+	ctx := context.Background()
+	send := func(commitTs uint64, resolved bool) {
+		events := []mqEvent{{
+			row: &model.RowChangedEvent{
+				CommitTs: commitTs,
+				Table:    &model.TableName{Schema: "a", Table: "b"},
+				Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+			},
+			key: topicPartitionKey{topic: "test"},
+		}}
+		require.Nil(t, worker.partition(events))
+		worker.needSyncFlush = resolved
+		require.Nil(t, worker.asyncSend(ctx, worker.group(events)))
+	}
+
+	send(5, false)
+	topicManager.partitions["test"] = 8
+	// The rows are dispatched by the old number of partitions until all the
+	// messages dispatched by it are flushed at a resolved ts barrier.
+	send(5, true)
+	require.True(t, producer.flushed)
+	send(5, false)
+	require.Len(t, producer.mqEvent[topicPartitionKey{topic: "test", partition: 1}], 2)
+	require.Len(t, producer.mqEvent[topicPartitionKey{topic: "test", partition: 5}], 1)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/kafka"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
@@ -46,6 +47,11 @@ type Config struct {
 	SASL            *security.SASL
 	// control whether to create topic
 	AutoCreate bool
+	// The topic-level configurations of the created topics,
+	// the ones of the broker are used if they are not set.
+	RetentionMs       int64
+	CleanupPolicy     string
+	MinInsyncReplicas int
 
 	// Timeout for sarama `config.Net` configurations, default to `10s`
 	DialTimeout  time.Duration
@@ -167,6 +173,36 @@ func (c *Config) Apply(sinkURI *url.URL) error {
 		c.AutoCreate = autoCreate
 	}
 
+	s = params.Get("retention-ms")
+	if s != "" {
+		a, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		c.RetentionMs = a
+	}
+
+	c.CleanupPolicy = params.Get("cleanup-policy")
+
+	s = params.Get("min-insync-replicas")
+	if s != "" {
+		a, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		c.MinInsyncReplicas = a
+	}
+
+	topicConfig := &config.TopicConfig{
+		ReplicationFactor: c.ReplicationFactor,
+		RetentionMs:       c.RetentionMs,
+		CleanupPolicy:     c.CleanupPolicy,
+		MinInsyncReplicas: c.MinInsyncReplicas,
+	}
+	if err := topicConfig.Validate(); err != nil {
+		return err
+	}
+
 	s = params.Get("dial-timeout")
 	if s != "" {
 		a, err := time.ParseDuration(s)
@@ -278,6 +314,12 @@ type AutoCreateTopicConfig struct {
 	AutoCreate        bool
 	PartitionNum      int32
 	ReplicationFactor int16
+	RetentionMs       int64
+	CleanupPolicy     string
+	MinInsyncReplicas int
+	// TopicConfigs returns the configurations which override the above
+	// ones for a topic, it may be nil, and so may the returned value.
+	TopicConfigs func(topic string) *config.TopicConfig
 }
 
 // DeriveTopicConfig derive a `topicConfig` from the `Config`
@@ -286,7 +328,63 @@ func (c *Config) DeriveTopicConfig() *AutoCreateTopicConfig {
 		AutoCreate:        c.AutoCreate,
 		PartitionNum:      c.PartitionNum,
 		ReplicationFactor: c.ReplicationFactor,
+		RetentionMs:       c.RetentionMs,
+		CleanupPolicy:     c.CleanupPolicy,
+		MinInsyncReplicas: c.MinInsyncReplicas,
+	}
+}
+
+// ForTopic returns the configuration used to create a topic.
+func (c *AutoCreateTopicConfig) ForTopic(topic string) *AutoCreateTopicConfig {
+	if c.TopicConfigs == nil {
+		return c
+	}
+	topicConfig := c.TopicConfigs(topic)
+	if topicConfig == nil {
+		return c
+	}
+	result := *c
+	if topicConfig.PartitionNum != 0 {
+		result.PartitionNum = topicConfig.PartitionNum
+	}
+	if topicConfig.ReplicationFactor != 0 {
+		result.ReplicationFactor = topicConfig.ReplicationFactor
+	}
+	if topicConfig.RetentionMs != 0 {
+		result.RetentionMs = topicConfig.RetentionMs
+	}
+	if topicConfig.CleanupPolicy != "" {
+		result.CleanupPolicy = topicConfig.CleanupPolicy
+	}
+	if topicConfig.MinInsyncReplicas != 0 {
+		result.MinInsyncReplicas = topicConfig.MinInsyncReplicas
+	}
+	return &result
+}
+
+// TopicDetail returns the detail used to create a topic with the configuration.
+func (c *AutoCreateTopicConfig) TopicDetail() *sarama.TopicDetail {
+	configEntries := make(map[string]*string)
+	if c.RetentionMs != 0 {
+		retentionMs := strconv.FormatInt(c.RetentionMs, 10)
+		configEntries[kafka.RetentionMsConfigName] = &retentionMs
+	}
+	if c.CleanupPolicy != "" {
+		cleanupPolicy := c.CleanupPolicy
+		configEntries[kafka.CleanupPolicyConfigName] = &cleanupPolicy
+	}
+	if c.MinInsyncReplicas != 0 {
+		minInsyncReplicas := strconv.Itoa(c.MinInsyncReplicas)
+		configEntries[kafka.MinInsyncReplicasConfigName] = &minInsyncReplicas
+	}
+	detail := &sarama.TopicDetail{
+		NumPartitions:     c.PartitionNum,
+		ReplicationFactor: c.ReplicationFactor,
+	}
+	if len(configEntries) != 0 {
+		detail.ConfigEntries = configEntries
 	}
+	return detail
 }
 
 // NewSaramaConfig return the default config and set the according version and metrics
@@ -177,6 +177,75 @@ func TestCompleteConfigByOpts(t *testing.T) {
 	cfg = NewConfig()
 	err = cfg.Apply(sinkURI)
 	require.Regexp(t, ".*invalid partition num.*", errors.Cause(err))
+
+	// The topic-level configurations.
+	uri = "kafka://127.0.0.1:9092/abc?replication-factor=3&retention-ms=86400000" +
+		"&cleanup-policy=compact&min-insync-replicas=2"
+	sinkURI, err = url.Parse(uri)
+	require.Nil(t, err)
+	cfg = NewConfig()
+	err = cfg.Apply(sinkURI)
+	require.Nil(t, err)
+	require.Equal(t, int64(86400000), cfg.RetentionMs)
+	require.Equal(t, "compact", cfg.CleanupPolicy)
+	require.Equal(t, 2, cfg.MinInsyncReplicas)
+
+	// Illegal topic-level configurations.
+	for _, tc := range []struct {
+		uri     string
+		wantErr string
+	}{
+		{"kafka://127.0.0.1:9092/abc?retention-ms=a", ".*invalid syntax.*"},
+		{"kafka://127.0.0.1:9092/abc?cleanup-policy=drop", ".*cleanup-policy drop is not one of.*"},
+		{
+			"kafka://127.0.0.1:9092/abc?min-insync-replicas=2",
+			".*min-insync-replicas 2 is greater than replication-factor 1.*",
+		},
+	} {
+		sinkURI, err = url.Parse(tc.uri)
+		require.Nil(t, err)
+		cfg = NewConfig()
+		err = cfg.Apply(sinkURI)
+		require.Regexp(t, tc.wantErr, errors.Cause(err))
+	}
+}
+
+func TestAutoCreateTopicConfig(t *testing.T) {
+	cfg := NewConfig()
+	cfg.PartitionNum = 3
+	cfg.RetentionMs = 3600000
+	topicConfig := cfg.DeriveTopicConfig()
+	require.Same(t, topicConfig, topicConfig.ForTopic("test"))
+	retentionMs := "3600000"
+	require.Equal(t, &sarama.TopicDetail{
+		NumPartitions:     3,
+		ReplicationFactor: 1,
+		ConfigEntries:     map[string]*string{kafka.RetentionMsConfigName: &retentionMs},
+	}, topicConfig.TopicDetail())
+
+	topicConfig.TopicConfigs = func(topic string) *config.TopicConfig {
+		if topic == "compacted" {
+			return &config.TopicConfig{
+				PartitionNum: 6, ReplicationFactor: 3, CleanupPolicy: "compact", MinInsyncReplicas: 2,
+			}
+		}
+		return nil
+	}
+	require.Same(t, topicConfig, topicConfig.ForTopic("test"))
+	compacted := topicConfig.ForTopic("compacted")
+	cleanupPolicy := "compact"
+	minInsyncReplicas := "2"
+	require.Equal(t, &sarama.TopicDetail{
+		NumPartitions:     6,
+		ReplicationFactor: 3,
+		ConfigEntries: map[string]*string{
+			kafka.RetentionMsConfigName:       &retentionMs,
+			kafka.CleanupPolicyConfigName:     &cleanupPolicy,
+			kafka.MinInsyncReplicasConfigName: &minInsyncReplicas,
+		},
+	}, compacted.TopicDetail())
+	// The original configuration is not changed.
+	require.Equal(t, int32(3), topicConfig.PartitionNum)
 }
 
 func TestSetPartitionNum(t *testing.T) {
@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/kafka"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
@@ -113,6 +114,7 @@ func (k *kafkaSaramaProducer) AsyncSendMessage(
 		Key:       sarama.ByteEncoder(message.Key),
 		Value:     sarama.ByteEncoder(message.Value),
 		Partition: partition,
+		Headers:   k.recordHeaders(message),
 	}
 	k.mu.Lock()
 	k.mu.inflight++
@@ -129,11 +131,35 @@ func (k *kafkaSaramaProducer) AsyncSendMessage(
 	return nil
 }
 
+// recordHeaders converts the headers of the message to Kafka record headers.
+// The traceparent header is dropped if the message would be too large with it.
+func (k *kafkaSaramaProducer) recordHeaders(message *codec.MQMessage) []sarama.RecordHeader {
+	if len(message.Headers) == 0 && message.TraceParent == "" {
+		return nil
+	}
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+1)
+	for _, header := range message.Headers {
+		headers = append(headers, sarama.RecordHeader{
+			Key:   []byte(header.Key),
+			Value: []byte(header.Value),
+		})
+	}
+	if message.TraceParent != "" &&
+		message.Length() <= k.client.Config().Producer.MaxMessageBytes {
+		headers = append(headers, sarama.RecordHeader{
+			Key:   []byte(tracing.TraceParentHeader),
+			Value: []byte(message.TraceParent),
+		})
+	}
+	return headers
+}
+
 func (k *kafkaSaramaProducer) SyncBroadcastMessage(
 	ctx context.Context, topic string, partitionsNum int32, message *codec.MQMessage,
 ) error {
 	k.clientLock.RLock()
 	defer k.clientLock.RUnlock()
+	headers := k.recordHeaders(message)
 	msgs := make([]*sarama.ProducerMessage, partitionsNum)
 	for i := 0; i < int(partitionsNum); i++ {
 		msgs[i] = &sarama.ProducerMessage{
@@ -141,6 +167,7 @@ func (k *kafkaSaramaProducer) SyncBroadcastMessage(
 			Key:       sarama.ByteEncoder(message.Key),
 			Value:     sarama.ByteEncoder(message.Value),
 			Partition: int32(i),
+			Headers:   headers,
 		}
 	}
 	select {
@@ -387,14 +414,18 @@ func AdjustConfig(
 		return cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
 	}
 
-	err = validateMinInsyncReplicas(admin, topics, topic, int(config.ReplicationFactor))
-	if err != nil {
-		return cerror.ErrKafkaInvalidConfig.Wrap(err).GenWithStack(
-			"because TiCDC Kafka producer's `request.required.acks` defaults to -1, " +
-				"TiCDC cannot deliver messages when the `replication-factor` is less than `min.insync.replicas`")
+	info, exists := topics[topic]
+	// The topic is created with the `min-insync-replicas` in the sink-uri if it is set,
+	// which has been checked against the `replication-factor` in `Apply`.
+	if exists || config.MinInsyncReplicas == 0 {
+		err = validateMinInsyncReplicas(admin, topics, topic, int(config.ReplicationFactor))
+		if err != nil {
+			return cerror.ErrKafkaInvalidConfig.Wrap(err).GenWithStack(
+				"because TiCDC Kafka producer's `request.required.acks` defaults to -1, " +
+					"TiCDC cannot deliver messages when the `replication-factor` is less than `min.insync.replicas`")
+		}
 	}
 
-	info, exists := topics[topic]
 	// once we have found the topic, no matter `auto-create-topic`, make sure user input parameters are valid.
 	if exists {
 		// make sure that producer's `MaxMessageBytes` smaller than topic's `max.message.bytes`
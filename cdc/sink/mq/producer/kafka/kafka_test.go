@@ -320,6 +320,19 @@ func TestAdjustConfigMinInsyncReplicas(t *testing.T) {
 		".*`replication-factor` cannot be smaller than the `min.insync.replicas` of topic.*",
 		errors.Cause(err),
 	)
+
+	// The min.insync.replicas of the broker is not used if the topic
+	// is created with the one in the sink-uri.
+	config.MinInsyncReplicas = 1
+	saramaConfig, err = NewSaramaConfig(context.Background(), config)
+	require.Nil(t, err)
+	err = AdjustConfig(adminClient, config, saramaConfig, "create-new-with-min-insync-replicas")
+	require.Nil(t, err)
+	err = AdjustConfig(adminClient, config, saramaConfig, adminClient.GetDefaultMockTopicName())
+	require.Regexp(t,
+		".*`replication-factor` cannot be smaller than the `min.insync.replicas` of topic.*",
+		errors.Cause(err),
+	)
 }
 
 func TestCreateProducerFailed(t *testing.T) {
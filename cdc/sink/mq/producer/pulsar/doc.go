@@ -29,4 +29,19 @@
 //
 // For example:
 // pulsar://{host}/{topic}?auth=token&auth.token={token}
+//
+// JWT and OAuth2 authentication can also be configured by:
+// 1. `authenticationToken={token}` or `tokenFromFile={path}` for JWT.
+// 2. `oauth2IssuerURL`, `oauth2PrivateKey`, `oauth2Audience` and `oauth2ClientID` for OAuth2,
+// the issuer URL and the private key are required.
+//
+// The producer batching and compression are tuned by `disableBatching`, `batchingMaxPublishDelay`,
+// `batchingMaxMessages`, `batchingMaxSize`, `compressionType` (LZ4, ZLib or ZSTD) and
+// `compressionLevel` (Default, Faster or Better).
+//
+// The messages are sent to the topics and partitions decided by the dispatch rules, the topics
+// without a namespace belong to the namespace of the topic in the sink URI. With `routingMode=key`,
+// the messages with a key are routed to the partitions by the hash of the key instead, the resolved
+// ts and DDL messages are still broadcast to all the partitions. The key routing mode can't be used
+// with the open protocol and maxwell, whose message keys contain the commit ts.
 package pulsar
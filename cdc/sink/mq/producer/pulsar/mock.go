@@ -0,0 +1,164 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+)
+
+// MockMessage is a message sent by the producers of MockClient.
+type MockMessage struct {
+	Topic     string
+	Partition int
+	*pulsar.ProducerMessage
+}
+
+// MockClient is an in-process pulsar.Client for tests. All the topics have
+// the same number of partitions unless set by SetTopicPartitions, and the
+// messages sent by its producers are recorded instead of being sent.
+type MockClient struct {
+	mu           sync.Mutex
+	partitionNum int
+	topics       map[string]int
+	messages     []*MockMessage
+	sendErr      error
+}
+
+// NewMockClient creates a MockClient.
+func NewMockClient(partitionNum int) *MockClient {
+	return &MockClient{
+		partitionNum: partitionNum,
+		topics:       make(map[string]int),
+	}
+}
+
+// SetTopicPartitions sets the number of partitions of a topic.
+func (c *MockClient) SetTopicPartitions(topic string, partitionNum int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.topics[topic] = partitionNum
+}
+
+// SetSendError makes the producers fail to send messages with err.
+func (c *MockClient) SetSendError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sendErr = err
+}
+
+// Messages returns the messages sent by the producers.
+func (c *MockClient) Messages() []*MockMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*MockMessage{}, c.messages...)
+}
+
+func (c *MockClient) numPartitions(topic string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if partitionNum, ok := c.topics[topic]; ok {
+		return partitionNum
+	}
+	return c.partitionNum
+}
+
+// CreateProducer implements pulsar.Client.
+func (c *MockClient) CreateProducer(options pulsar.ProducerOptions) (pulsar.Producer, error) {
+	if options.Topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	return &mockProducer{client: c, options: options}, nil
+}
+
+// Subscribe implements pulsar.Client.
+func (c *MockClient) Subscribe(pulsar.ConsumerOptions) (pulsar.Consumer, error) {
+	return nil, errors.New("not supported by mock client")
+}
+
+// CreateReader implements pulsar.Client.
+func (c *MockClient) CreateReader(pulsar.ReaderOptions) (pulsar.Reader, error) {
+	return nil, errors.New("not supported by mock client")
+}
+
+// TopicPartitions implements pulsar.Client.
+func (c *MockClient) TopicPartitions(topic string) ([]string, error) {
+	partitions := make([]string, c.numPartitions(topic))
+	for i := range partitions {
+		partitions[i] = topic
+	}
+	return partitions, nil
+}
+
+// Close implements pulsar.Client.
+func (c *MockClient) Close() {}
+
+type mockTopicMetadata int
+
+func (m mockTopicMetadata) NumPartitions() uint32 {
+	return uint32(m)
+}
+
+type mockProducer struct {
+	client  *MockClient
+	options pulsar.ProducerOptions
+}
+
+func (p *mockProducer) Topic() string {
+	return p.options.Topic
+}
+
+func (p *mockProducer) Name() string {
+	return p.options.Name
+}
+
+func (p *mockProducer) Send(_ context.Context, msg *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	partition := 0
+	if p.options.MessageRouter != nil {
+		metadata := mockTopicMetadata(p.client.numPartitions(p.options.Topic))
+		partition = p.options.MessageRouter(msg, metadata)
+	}
+	p.client.mu.Lock()
+	defer p.client.mu.Unlock()
+	if p.client.sendErr != nil {
+		return nil, p.client.sendErr
+	}
+	p.client.messages = append(p.client.messages, &MockMessage{
+		Topic:           p.options.Topic,
+		Partition:       partition,
+		ProducerMessage: msg,
+	})
+	return nil, nil
+}
+
+func (p *mockProducer) SendAsync(
+	ctx context.Context, msg *pulsar.ProducerMessage,
+	callback func(pulsar.MessageID, *pulsar.ProducerMessage, error),
+) {
+	id, err := p.Send(ctx, msg)
+	callback(id, msg, err)
+}
+
+func (p *mockProducer) LastSequenceID() int64 {
+	return 0
+}
+
+func (p *mockProducer) Flush() error {
+	return nil
+}
+
+func (p *mockProducer) Close() {}
@@ -15,6 +15,7 @@ package pulsar
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -22,15 +23,38 @@ import (
 	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/spaolacci/murmur3"
 )
 
 // Option is pulsar producer's option.
 type Option struct {
 	clientOptions   *pulsar.ClientOptions
 	producerOptions *pulsar.ProducerOptions
+	// routingMode decides how the messages are routed to the partitions.
+	routingMode routingMode
 }
 
-const route = "$route"
+const (
+	route = "$route"
+	// broadcast marks the messages sent to every partition by
+	// SyncBroadcastMessage, they are never routed by their keys.
+	broadcast = "$broadcast"
+)
+
+// routingMode is the way to route the messages to the partitions of a topic.
+type routingMode string
+
+const (
+	// routingModePartition routes the messages to the partitions calculated
+	// by the partition dispatchers of TiCDC.
+	routingModePartition routingMode = "partition"
+	// routingModeKey routes the messages by the hash of their keys, the
+	// messages without a key and the broadcast messages are routed like
+	// routingModePartition. It's used with the protocols whose message key
+	// identifies a row.
+	routingModeKey routingMode = "key"
+)
 
 func parseSinkOptions(u *url.URL) (opt *Option, err error) {
 	switch u.Scheme {
@@ -42,20 +66,57 @@ func parseSinkOptions(u *url.URL) (opt *Option, err error) {
 	if err != nil {
 		return nil, err
 	}
-	p := parseProducerOptions(u)
+	p, err := parseProducerOptions(u)
+	if err != nil {
+		return nil, err
+	}
 	opt = &Option{
 		clientOptions:   c,
 		producerOptions: p,
+		routingMode:     routingModePartition,
 	}
 
+	vs := values(u.Query())
+	switch mode := routingMode(vs.Str("routingMode")); mode {
+	case "", routingModePartition:
+	case routingModeKey:
+		opt.routingMode = mode
+	default:
+		return nil, fmt.Errorf("unsupported pulsar routing mode: %s", mode)
+	}
+	hash := javaStringHash
+	if p.HashingScheme == pulsar.Murmur3_32Hash {
+		hash = murmur3Hash
+	}
 	p.MessageRouter = func(message *pulsar.ProducerMessage, metadata pulsar.TopicMetadata) int {
 		partition, _ := strconv.Atoi(message.Properties[route])
+		_, isBroadcast := message.Properties[broadcast]
 		delete(message.Properties, route)
+		delete(message.Properties, broadcast)
+		if opt.routingMode == routingModeKey && message.Key != "" && !isBroadcast {
+			return int(hash(message.Key) % metadata.NumPartitions())
+		}
 		return partition
 	}
 	return
 }
 
+// CheckRoutingMode checks whether the routing mode of the sink URI works with
+// the protocol. The key routing mode requires the message key to identify a
+// row, the keys of the open protocol and maxwell contain the commit ts, so the
+// versions of a row would be scattered across the partitions.
+func CheckRoutingMode(u *url.URL, protocol config.Protocol) error {
+	if routingMode(values(u.Query()).Str("routingMode")) != routingModeKey {
+		return nil
+	}
+	switch protocol {
+	case config.ProtocolOpen, config.ProtocolMaxwell:
+		return fmt.Errorf("pulsar routing mode %s is not supported by protocol %s",
+			routingModeKey, protocol)
+	}
+	return nil
+}
+
 func parseClientOption(u *url.URL) (opt *pulsar.ClientOptions, err error) {
 	vs := values(u.Query())
 	opt = &pulsar.ClientOptions{
@@ -68,31 +129,73 @@ func parseClientOption(u *url.URL) (opt *pulsar.ClientOptions, err error) {
 		MaxConnectionsPerBroker:    vs.Int("maxConnectionsPerBroker"),
 	}
 	auth := vs.Str("auth")
-	if auth == "" {
-		if u.User.Username() == "" {
-			// no auth
-			return opt, nil
+	if auth != "" {
+		param := jsonStr(vs.SubPathKV("auth"))
+		opt.Authentication, err = pulsar.NewAuthentication(auth, param)
+		if err != nil {
+			return nil, err
 		}
-		// use token provider by default
-		opt.Authentication = pulsar.NewAuthenticationToken(u.User.Username())
 		return opt, nil
 	}
-	param := jsonStr(vs.SubPathKV("auth"))
-	opt.Authentication, err = pulsar.NewAuthentication(auth, param)
+	opt.Authentication, err = parseAuthentication(vs)
 	if err != nil {
 		return nil, err
 	}
+	if opt.Authentication == nil && u.User.Username() != "" {
+		// use token provider by default
+		opt.Authentication = pulsar.NewAuthenticationToken(u.User.Username())
+	}
 	return opt, nil
 }
 
-func parseProducerOptions(u *url.URL) (opt *pulsar.ProducerOptions) {
+// parseAuthentication parses the JWT or OAuth2 authentication, it returns
+// nil if neither is configured.
+func parseAuthentication(vs values) (pulsar.Authentication, error) {
+	token, tokenFile := vs.Str("authenticationToken"), vs.Str("tokenFromFile")
+	oauth2 := map[string]string{
+		"type":       "client_credentials",
+		"issuerUrl":  vs.Str("oauth2IssuerURL"),
+		"audience":   vs.Str("oauth2Audience"),
+		"privateKey": vs.Str("oauth2PrivateKey"),
+		"clientId":   vs.Str("oauth2ClientID"),
+	}
+	useOAuth2 := oauth2["issuerUrl"] != "" || oauth2["privateKey"] != "" ||
+		oauth2["audience"] != "" || oauth2["clientId"] != ""
+	configured := 0
+	for _, ok := range []bool{token != "", tokenFile != "", useOAuth2} {
+		if ok {
+			configured++
+		}
+	}
+	if configured > 1 {
+		return nil, errors.New("only one of authenticationToken, tokenFromFile and oauth2 can be configured")
+	}
+	switch {
+	case token != "":
+		return pulsar.NewAuthenticationToken(token), nil
+	case tokenFile != "":
+		return pulsar.NewAuthenticationTokenFromFile(tokenFile), nil
+	case useOAuth2:
+		if oauth2["issuerUrl"] == "" || oauth2["privateKey"] == "" {
+			return nil, errors.New("oauth2IssuerURL and oauth2PrivateKey are required by oauth2")
+		}
+		// NewAuthenticationOAuth2 ignores the error of authorization.
+		return pulsar.NewAuthentication("oauth2", jsonStr(oauth2))
+	}
+	return nil, nil
+}
+
+func parseProducerOptions(u *url.URL) (opt *pulsar.ProducerOptions, err error) {
 	vs := values(u.Query())
 	opt = &pulsar.ProducerOptions{
 		Name:                    vs.Str("name"),
 		MaxPendingMessages:      vs.Int("maxPendingMessages"),
+		SendTimeout:             vs.Duration("sendTimeout"),
+		DisableBlockIfQueueFull: vs.Bool("disableBlockIfQueueFull"),
 		DisableBatching:         vs.Bool("disableBatching"),
 		BatchingMaxPublishDelay: vs.Duration("batchingMaxPublishDelay"),
-		BatchingMaxMessages:     uint(vs.Int("tlsAllowInsecureConnection")),
+		BatchingMaxMessages:     uint(vs.Int("batchingMaxMessages")),
+		BatchingMaxSize:         uint(vs.Int("batchingMaxSize")),
 		Properties:              vs.SubPathKV("properties"),
 	}
 	hashingScheme := vs.Str("hashingScheme")
@@ -101,15 +204,31 @@ func parseProducerOptions(u *url.URL) (opt *pulsar.ProducerOptions) {
 		opt.HashingScheme = pulsar.JavaStringHash
 	case "Murmur3_32Hash":
 		opt.HashingScheme = pulsar.Murmur3_32Hash
+	default:
+		return nil, fmt.Errorf("unsupported pulsar hashing scheme: %s", hashingScheme)
 	}
 	compressionType := vs.Str("compressionType")
 	switch compressionType {
+	case "":
 	case "LZ4":
 		opt.CompressionType = pulsar.LZ4
 	case "ZLib":
 		opt.CompressionType = pulsar.ZLib
 	case "ZSTD":
 		opt.CompressionType = pulsar.ZSTD
+	default:
+		return nil, fmt.Errorf("unsupported pulsar compression type: %s", compressionType)
+	}
+	compressionLevel := vs.Str("compressionLevel")
+	switch compressionLevel {
+	case "Default", "":
+		opt.CompressionLevel = pulsar.Default
+	case "Faster":
+		opt.CompressionLevel = pulsar.Faster
+	case "Better":
+		opt.CompressionLevel = pulsar.Better
+	default:
+		return nil, fmt.Errorf("unsupported pulsar compression level: %s", compressionLevel)
 	}
 	switch u.Path {
 	case "", "/":
@@ -117,7 +236,21 @@ func parseProducerOptions(u *url.URL) (opt *pulsar.ProducerOptions) {
 	default:
 		opt.Topic = strings.Trim(u.Path, "/")
 	}
-	return opt
+	return opt, nil
+}
+
+// javaStringHash is the Java String.hashCode() equivalent used by Pulsar.
+func javaStringHash(s string) uint32 {
+	var h uint32
+	for i := 0; i < len(s); i++ {
+		h = 31*h + uint32(s[i])
+	}
+	return h
+}
+
+// murmur3Hash is the Murmur3 hash compatible with the Java client of Pulsar.
+func murmur3Hash(s string) uint32 {
+	return murmur3.Sum32([]byte(s)) & 0x7fffffff
 }
 
 type values url.Values
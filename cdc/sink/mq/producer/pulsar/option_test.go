@@ -0,0 +1,136 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSinkOptions(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse("pulsar://127.0.0.1:6650/persistent://public/app/test?" +
+		"maxPendingMessages=100&sendTimeout=10s&disableBlockIfQueueFull=true" +
+		"&batchingMaxPublishDelay=5ms&batchingMaxMessages=500&batchingMaxSize=1048576" +
+		"&compressionType=ZSTD&compressionLevel=Better&hashingScheme=Murmur3_32Hash" +
+		"&routingMode=key&maxConnectionsPerBroker=2")
+	require.Nil(t, err)
+	opt, err := parseSinkOptions(u)
+	require.Nil(t, err)
+	require.Equal(t, "pulsar://127.0.0.1:6650", opt.clientOptions.URL)
+	require.Equal(t, 2, opt.clientOptions.MaxConnectionsPerBroker)
+	require.Nil(t, opt.clientOptions.Authentication)
+
+	p := opt.producerOptions
+	require.Equal(t, "persistent://public/app/test", p.Topic)
+	require.Equal(t, 100, p.MaxPendingMessages)
+	require.Equal(t, 10*time.Second, p.SendTimeout)
+	require.True(t, p.DisableBlockIfQueueFull)
+	require.False(t, p.DisableBatching)
+	require.Equal(t, 5*time.Millisecond, p.BatchingMaxPublishDelay)
+	require.Equal(t, uint(500), p.BatchingMaxMessages)
+	require.Equal(t, uint(1048576), p.BatchingMaxSize)
+	require.Equal(t, pulsar.ZSTD, p.CompressionType)
+	require.Equal(t, pulsar.Better, p.CompressionLevel)
+	require.Equal(t, pulsar.Murmur3_32Hash, p.HashingScheme)
+	require.Equal(t, routingModeKey, opt.routingMode)
+
+	u, err = url.Parse("pulsar://127.0.0.1:6650?topic=test")
+	require.Nil(t, err)
+	opt, err = parseSinkOptions(u)
+	require.Nil(t, err)
+	require.Equal(t, "test", opt.producerOptions.Topic)
+	require.Equal(t, routingModePartition, opt.routingMode)
+	require.Equal(t, pulsar.NoCompression, opt.producerOptions.CompressionType)
+	require.Equal(t, pulsar.JavaStringHash, opt.producerOptions.HashingScheme)
+
+	for _, uri := range []string{
+		"kafka://127.0.0.1:6650/test",
+		"pulsar://127.0.0.1:6650/test?compressionType=GZIP",
+		"pulsar://127.0.0.1:6650/test?compressionLevel=Best",
+		"pulsar://127.0.0.1:6650/test?hashingScheme=CRC32",
+		"pulsar://127.0.0.1:6650/test?routingMode=random",
+	} {
+		u, err := url.Parse(uri)
+		require.Nil(t, err)
+		_, err = parseSinkOptions(u)
+		require.Error(t, err, uri)
+	}
+}
+
+func TestCheckRoutingMode(t *testing.T) {
+	t.Parallel()
+
+	keyURI, err := url.Parse("pulsar://127.0.0.1:6650/test?routingMode=key")
+	require.Nil(t, err)
+	partitionURI, err := url.Parse("pulsar://127.0.0.1:6650/test")
+	require.Nil(t, err)
+	for _, protocol := range []config.Protocol{config.ProtocolOpen, config.ProtocolMaxwell} {
+		require.Error(t, CheckRoutingMode(keyURI, protocol))
+		require.Nil(t, CheckRoutingMode(partitionURI, protocol))
+	}
+	for _, protocol := range []config.Protocol{config.ProtocolAvro, config.ProtocolCanalJSON} {
+		require.Nil(t, CheckRoutingMode(keyURI, protocol))
+	}
+}
+
+func TestParseAuthentication(t *testing.T) {
+	t.Parallel()
+
+	for _, uri := range []string{
+		"pulsar://token@127.0.0.1:6650/test",
+		"pulsar://127.0.0.1:6650/test?authenticationToken=token",
+		"pulsar://127.0.0.1:6650/test?tokenFromFile=/tmp/token",
+		"pulsar://127.0.0.1:6650/test?auth=token&auth.token=token",
+	} {
+		u, err := url.Parse(uri)
+		require.Nil(t, err)
+		opt, err := parseClientOption(u)
+		require.Nil(t, err, uri)
+		require.NotNil(t, opt.Authentication, uri)
+	}
+
+	for _, tc := range []struct {
+		uri     string
+		wantErr string
+	}{
+		{
+			uri:     "pulsar://127.0.0.1:6650/test?authenticationToken=token&tokenFromFile=/tmp/token",
+			wantErr: "only one of authenticationToken, tokenFromFile and oauth2 can be configured",
+		},
+		{
+			uri:     "pulsar://127.0.0.1:6650/test?authenticationToken=token&oauth2ClientID=id",
+			wantErr: "only one of authenticationToken, tokenFromFile and oauth2 can be configured",
+		},
+		{
+			uri:     "pulsar://127.0.0.1:6650/test?oauth2IssuerURL=https://auth.example.com",
+			wantErr: "oauth2IssuerURL and oauth2PrivateKey are required by oauth2",
+		},
+		{
+			uri:     "pulsar://127.0.0.1:6650/test?auth=unknown",
+			wantErr: "invalid auth provider",
+		},
+	} {
+		u, err := url.Parse(tc.uri)
+		require.Nil(t, err)
+		_, err = parseClientOption(u)
+		require.Regexp(t, tc.wantErr, err, tc.uri)
+	}
+}
@@ -17,58 +17,111 @@ import (
 	"context"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"go.uber.org/zap"
 )
 
 // NewProducer create a pulsar producer.
 func NewProducer(u *url.URL, errCh chan error) (*Producer, error) {
-	failpoint.Inject("MockPulsar", func() {
-		failpoint.Return(&Producer{
-			errCh:        errCh,
-			partitionNum: 4,
-		}, nil)
-	})
-
 	opt, err := parseSinkOptions(u)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
 	}
+	failpoint.Inject("MockPulsar", func() {
+		failpoint.Return(newProducer(NewMockClient(4), opt, errCh))
+	})
+
 	client, err := pulsar.NewClient(*opt.clientOptions)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
 	}
-	producer, err := client.CreateProducer(*opt.producerOptions)
+	producer, err := newProducer(client, opt, errCh)
 	if err != nil {
 		client.Close()
-		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+		return nil, err
+	}
+	return producer, nil
+}
+
+func newProducer(client pulsar.Client, opt *Option, errCh chan error) (*Producer, error) {
+	p := &Producer{
+		errCh:     errCh,
+		opt:       *opt,
+		client:    client,
+		producers: make(map[string]pulsar.Producer),
+	}
+	if _, err := p.getProducer(""); err != nil {
+		return nil, err
 	}
-	partitions, err := client.TopicPartitions(opt.producerOptions.Topic)
+	partitionNum, err := p.TopicPartitions("")
 	if err != nil {
-		client.Close()
-		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+		p.closeProducers()
+		return nil, err
 	}
-	return &Producer{
-		errCh:        errCh,
-		opt:          *opt,
-		client:       client,
-		producer:     producer,
-		partitionNum: len(partitions),
-	}, nil
+	p.partitionNum = int(partitionNum)
+	return p, nil
 }
 
 // Producer provide a way to send msg to pulsar.
+// The messages can be sent to multiple topics, the producer of each topic
+// is created on its first message.
 type Producer struct {
 	opt          Option
 	client       pulsar.Client
-	producer     pulsar.Producer
 	errCh        chan error
 	partitionNum int
+
+	mu        sync.Mutex
+	producers map[string]pulsar.Producer
+}
+
+// fullTopicName returns the full name of a topic. The topics without a
+// namespace belong to the namespace of the default topic, and the empty
+// topic is the default topic.
+func (p *Producer) fullTopicName(topic string) string {
+	defaultTopic := p.opt.producerOptions.Topic
+	if topic == "" {
+		return defaultTopic
+	}
+	if strings.Contains(topic, "://") || !strings.Contains(defaultTopic, "://") {
+		return topic
+	}
+	return defaultTopic[:strings.LastIndex(defaultTopic, "/")+1] + topic
+}
+
+func (p *Producer) getProducer(topic string) (pulsar.Producer, error) {
+	topic = p.fullTopicName(topic)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if producer, ok := p.producers[topic]; ok {
+		return producer, nil
+	}
+	options := *p.opt.producerOptions
+	options.Topic = topic
+	producer, err := p.client.CreateProducer(options)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	p.producers[topic] = producer
+	log.Info("pulsar producer created", zap.String("topic", topic))
+	return producer, nil
+}
+
+// TopicPartitions returns the number of partitions of a topic.
+func (p *Producer) TopicPartitions(topic string) (int32, error) {
+	partitions, err := p.client.TopicPartitions(p.fullTopicName(topic))
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	return int32(len(partitions)), nil
 }
 
 func createProperties(message *codec.MQMessage, partition int32) map[string]string {
@@ -82,14 +135,24 @@ func createProperties(message *codec.MQMessage, partition int32) map[string]stri
 	if message.Table != nil {
 		properties["table"] = *message.Table
 	}
+	for _, header := range message.Headers {
+		properties[header.Key] = header.Value
+	}
+	if message.TraceParent != "" {
+		properties[tracing.TraceParentHeader] = message.TraceParent
+	}
 	return properties
 }
 
 // AsyncSendMessage send key-value msg to target partition.
 func (p *Producer) AsyncSendMessage(
-	ctx context.Context, _ string, partition int32, message *codec.MQMessage,
+	ctx context.Context, topic string, partition int32, message *codec.MQMessage,
 ) error {
-	p.producer.SendAsync(ctx, &pulsar.ProducerMessage{
+	producer, err := p.getProducer(topic)
+	if err != nil {
+		return err
+	}
+	producer.SendAsync(ctx, &pulsar.ProducerMessage{
 		Payload:    message.Value,
 		Key:        string(message.Key),
 		Properties: createProperties(message, partition),
@@ -108,19 +171,25 @@ func (p *Producer) errors(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err err
 	}
 }
 
-// SyncBroadcastMessage send key-value msg to all partition.
+// SyncBroadcastMessage sends the message to all the partitions of the topic.
 func (p *Producer) SyncBroadcastMessage(
-	ctx context.Context, _ string, _ int32, message *codec.MQMessage,
+	ctx context.Context, topic string, partitionsNum int32, message *codec.MQMessage,
 ) error {
-	for partition := 0; partition < p.partitionNum; partition++ {
-		_, err := p.producer.Send(ctx, &pulsar.ProducerMessage{
+	producer, err := p.getProducer(topic)
+	if err != nil {
+		return err
+	}
+	for partition := int32(0); partition < partitionsNum; partition++ {
+		properties := createProperties(message, partition)
+		properties[broadcast] = ""
+		_, err := producer.Send(ctx, &pulsar.ProducerMessage{
 			Payload:    message.Value,
 			Key:        string(message.Key),
-			Properties: createProperties(message, int32(partition)),
+			Properties: properties,
 			EventTime:  message.PhysicalTime(),
 		})
 		if err != nil {
-			return cerror.WrapError(cerror.ErrPulsarSendMessage, p.producer.Flush())
+			return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
 		}
 	}
 	return nil
@@ -128,7 +197,14 @@ func (p *Producer) SyncBroadcastMessage(
 
 // Flush flushes all in memory msgs to server.
 func (p *Producer) Flush(_ context.Context) error {
-	return cerror.WrapError(cerror.ErrPulsarSendMessage, p.producer.Flush())
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, producer := range p.producers {
+		if err := producer.Flush(); err != nil {
+			return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+		}
+	}
+	return nil
 }
 
 // GetPartitionNum got current topic's partition size.
@@ -136,13 +212,21 @@ func (p *Producer) GetPartitionNum() int32 {
 	return int32(p.partitionNum)
 }
 
-// Close closes the producer and client.
+// Close flushes and closes the producers of all the topics.
 func (p *Producer) Close() error {
-	err := p.producer.Flush()
-	if err != nil {
-		return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+	if err := p.Flush(context.Background()); err != nil {
+		return err
 	}
-	p.producer.Close()
+	p.closeProducers()
 	p.client.Close()
 	return nil
 }
+
+func (p *Producer) closeProducers() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for topic, producer := range p.producers {
+		producer.Close()
+		delete(p.producers, topic)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProducer(t *testing.T, uri string) (*Producer, *MockClient, chan error) {
+	u, err := url.Parse(uri)
+	require.Nil(t, err)
+	opt, err := parseSinkOptions(u)
+	require.Nil(t, err)
+	client := NewMockClient(4)
+	errCh := make(chan error, 1)
+	producer, err := newProducer(client, opt, errCh)
+	require.Nil(t, err)
+	return producer, client, errCh
+}
+
+func TestProducerSendMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	producer, client, errCh := newTestProducer(t,
+		"pulsar://127.0.0.1:6650/persistent://public/app/test")
+	client.SetTopicPartitions("persistent://public/app/db_t1", 2)
+	require.Equal(t, int32(4), producer.GetPartitionNum())
+	partitions, err := producer.TopicPartitions("db_t1")
+	require.Nil(t, err)
+	require.Equal(t, int32(2), partitions)
+
+	message := codec.NewMQMessage(config.ProtocolCanalJSON, []byte("key"), []byte("value"),
+		1, model.MqMessageTypeRow, nil, nil)
+	require.Nil(t, producer.AsyncSendMessage(ctx, "", 3, message))
+	require.Nil(t, producer.AsyncSendMessage(ctx, "db_t1", 1, message))
+	require.Nil(t, producer.AsyncSendMessage(ctx, "persistent://public/other/t2", 0, message))
+	require.Nil(t, producer.SyncBroadcastMessage(ctx, "db_t1", 2, message))
+	require.Nil(t, producer.Flush(ctx))
+
+	messages := client.Messages()
+	require.Len(t, messages, 5)
+	expected := []struct {
+		topic     string
+		partition int
+	}{
+		{"persistent://public/app/test", 3},
+		{"persistent://public/app/db_t1", 1},
+		{"persistent://public/other/t2", 0},
+		{"persistent://public/app/db_t1", 0},
+		{"persistent://public/app/db_t1", 1},
+	}
+	for i, msg := range messages {
+		require.Equal(t, expected[i].topic, msg.Topic)
+		require.Equal(t, expected[i].partition, msg.Partition)
+		require.Equal(t, "key", msg.Key)
+		require.Equal(t, []byte("value"), msg.Payload)
+		require.NotContains(t, msg.Properties, route)
+	}
+
+	// The errors of the asynchronous messages are sent to the error channel.
+	client.SetSendError(errors.New("send failed"))
+	require.Nil(t, producer.AsyncSendMessage(ctx, "", 0, message))
+	err = <-errCh
+	require.Regexp(t, ".*ErrPulsarSendMessage.*send failed.*", err)
+	err = producer.SyncBroadcastMessage(ctx, "", 4, message)
+	require.Regexp(t, ".*ErrPulsarSendMessage.*send failed.*", err)
+	require.Nil(t, producer.Close())
+}
+
+func TestProducerKeyRouting(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	producer, client, _ := newTestProducer(t, "pulsar://127.0.0.1:6650/test?routingMode=key")
+	keyMessage := codec.NewMQMessage(config.ProtocolOpen, []byte("row-1"), []byte("value"),
+		1, model.MqMessageTypeRow, nil, nil)
+	noKeyMessage := codec.NewMQMessage(config.ProtocolCanalJSON, nil, []byte("value"),
+		1, model.MqMessageTypeRow, nil, nil)
+	for partition := int32(0); partition < 4; partition++ {
+		require.Nil(t, producer.AsyncSendMessage(ctx, "", partition, keyMessage))
+		require.Nil(t, producer.AsyncSendMessage(ctx, "", partition, noKeyMessage))
+	}
+
+	// The broadcast messages are sent to all the partitions even if they
+	// have a key.
+	require.Nil(t, producer.SyncBroadcastMessage(ctx, "", 4, keyMessage))
+
+	keyPartition := int(javaStringHash("row-1") % 4)
+	messages := client.Messages()
+	require.Len(t, messages, 12)
+	for i, msg := range messages[:8] {
+		if i%2 == 0 {
+			// The messages with the same key are routed to the same partition.
+			require.Equal(t, keyPartition, msg.Partition)
+		} else {
+			require.Equal(t, i/2, msg.Partition)
+		}
+	}
+	for i, msg := range messages[8:] {
+		require.Equal(t, i, msg.Partition)
+		require.NotContains(t, msg.Properties, broadcast)
+	}
+	require.Nil(t, producer.Close())
+}
+
+func TestHash(t *testing.T) {
+	t.Parallel()
+
+	// The hashes are the same as the ones of the Java client.
+	require.Equal(t, uint32(99162322), javaStringHash("hello"))
+	require.Equal(t, uint32(0x248bfa47), murmur3Hash("hello"))
+}
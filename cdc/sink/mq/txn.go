@@ -0,0 +1,142 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+type txnKey struct {
+	startTs  model.Ts
+	commitTs model.Ts
+}
+
+type pendingTxn struct {
+	txnKey
+	rowCount       int64
+	tableRowCounts map[string]int64
+}
+
+// txnTracker aggregates the rows of the transactions emitted by the MQ sink
+// by their start ts and commit ts, so that a transaction which changes several
+// tables is enclosed by one pair of BEGIN and COMMIT markers.
+// The rows of different tables are emitted separately, so a transaction is
+// only committed once all the tables replicated by the sink have been
+// resolved to its commit ts, after which no more row of it can be emitted.
+// txnTracker is thread-safe.
+type txnTracker struct {
+	mu sync.Mutex
+	// resolvedTs are the resolved ts of the tables replicated by the sink.
+	resolvedTs map[model.TableID]model.Ts
+	txns       map[txnKey]*pendingTxn
+}
+
+func newTxnTracker() *txnTracker {
+	return &txnTracker{
+		resolvedTs: make(map[model.TableID]model.Ts),
+		txns:       make(map[txnKey]*pendingTxn),
+	}
+}
+
+// addTable starts tracking the resolved ts of a table.
+func (t *txnTracker) addTable(tableID model.TableID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resolvedTs[tableID] = 0
+}
+
+// removeTable stops tracking the resolved ts of a table. The transactions
+// which only wait for the table are committed at the next resolve.
+func (t *txnTracker) removeTable(tableID model.TableID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.resolvedTs, tableID)
+}
+
+// addRows sets the positions of the rows of a table in their transaction,
+// and returns the BEGIN marker if they are the first rows of the transaction.
+// All the rows must belong to the same transaction and table.
+func (t *txnTracker) addRows(table string, rows []*model.RowChangedEvent) *model.TxnEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := txnKey{startTs: rows[0].StartTs, commitTs: rows[0].CommitTs}
+	txn, ok := t.txns[key]
+	if !ok {
+		txn = &pendingTxn{txnKey: key, tableRowCounts: make(map[string]int64)}
+		t.txns[key] = txn
+	}
+	for _, row := range rows {
+		txn.rowCount++
+		row.TxnIndex = txn.rowCount
+	}
+	txn.tableRowCounts[table] += int64(len(rows))
+	if ok {
+		return nil
+	}
+	return &model.TxnEvent{
+		Type:     model.TxnEventTypeBegin,
+		StartTs:  key.startTs,
+		CommitTs: key.commitTs,
+	}
+}
+
+// resolve updates the resolved ts of a table, and returns the COMMIT markers
+// of the transactions which no more row can be emitted for, in the order of
+// their commit ts.
+func (t *txnTracker) resolve(tableID model.TableID, resolvedTs model.Ts) []*model.TxnEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.resolvedTs[tableID]; ok {
+		t.resolvedTs[tableID] = resolvedTs
+	}
+	if len(t.txns) == 0 {
+		return nil
+	}
+
+	// All the transactions are committed if all the tables are removed.
+	minResolvedTs := uint64(math.MaxUint64)
+	for _, ts := range t.resolvedTs {
+		if ts < minResolvedTs {
+			minResolvedTs = ts
+		}
+	}
+	var committed []*pendingTxn
+	for key, txn := range t.txns {
+		if key.commitTs <= minResolvedTs {
+			committed = append(committed, txn)
+			delete(t.txns, key)
+		}
+	}
+	sort.Slice(committed, func(i, j int) bool {
+		if committed[i].commitTs != committed[j].commitTs {
+			return committed[i].commitTs < committed[j].commitTs
+		}
+		return committed[i].startTs < committed[j].startTs
+	})
+	events := make([]*model.TxnEvent, 0, len(committed))
+	for _, txn := range committed {
+		events = append(events, &model.TxnEvent{
+			Type:           model.TxnEventTypeCommit,
+			StartTs:        txn.startTs,
+			CommitTs:       txn.commitTs,
+			RowCount:       txn.rowCount,
+			TableRowCounts: txn.tableRowCounts,
+		})
+	}
+	return events
+}
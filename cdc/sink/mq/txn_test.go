@@ -0,0 +1,72 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTxnTracker()
+	tracker.addTable(1)
+	tracker.addTable(2)
+	newRows := func(startTs, commitTs model.Ts, n int) []*model.RowChangedEvent {
+		rows := make([]*model.RowChangedEvent, 0, n)
+		for i := 0; i < n; i++ {
+			rows = append(rows, &model.RowChangedEvent{StartTs: startTs, CommitTs: commitTs})
+		}
+		return rows
+	}
+
+	// The BEGIN marker is only returned for the first rows of a transaction,
+	// and the rows are counted across the tables.
+	rows := newRows(3, 5, 2)
+	begin := tracker.addRows("a.t1", rows)
+	require.Equal(t, &model.TxnEvent{Type: model.TxnEventTypeBegin, StartTs: 3, CommitTs: 5}, begin)
+	require.Equal(t, int64(2), rows[1].TxnIndex)
+	require.NotNil(t, tracker.addRows("a.t1", newRows(1, 5, 1)))
+	rows = newRows(3, 5, 1)
+	require.Nil(t, tracker.addRows("a.t2", rows))
+	require.Equal(t, int64(3), rows[0].TxnIndex)
+
+	// The transactions wait for all the tables.
+	require.Len(t, tracker.resolve(1, 10), 0)
+	// A table which is not tracked is ignored.
+	require.Len(t, tracker.resolve(3, 10), 0)
+	commits := tracker.resolve(2, 5)
+	require.Equal(t, []*model.TxnEvent{
+		{
+			Type: model.TxnEventTypeCommit, StartTs: 1, CommitTs: 5, RowCount: 1,
+			TableRowCounts: map[string]int64{"a.t1": 1},
+		},
+		{
+			Type: model.TxnEventTypeCommit, StartTs: 3, CommitTs: 5, RowCount: 3,
+			TableRowCounts: map[string]int64{"a.t1": 2, "a.t2": 1},
+		},
+	}, commits)
+
+	// All the transactions are committed once all the tables are removed.
+	require.NotNil(t, tracker.addRows("a.t1", newRows(6, 20, 1)))
+	require.Len(t, tracker.resolve(1, 15), 0)
+	tracker.removeTable(1)
+	tracker.removeTable(2)
+	commits = tracker.resolve(1, 15)
+	require.Len(t, commits, 1)
+	require.Equal(t, uint64(20), commits[0].CommitTs)
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"sort"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
+	"go.uber.org/zap"
+)
+
+// compactChanges merges the successive changes of the same row in a flush
+// window, so that only the latest state of each row is written.
+// INSERT + INSERT => INSERT     (the UPDATE is sent as INSERT if old value is disabled)
+// UPDATE + INSERT => X
+// DELETE + INSERT => INSERT in safe mode (REPLACE)
+// INSERT + DELETE => DELETE     ‾|
+// UPDATE + DELETE => DELETE      |=> anything + DELETE => DELETE
+// DELETE + DELETE => X          _|
+// INSERT + UPDATE => INSERT
+// UPDATE + UPDATE => UPDATE
+// DELETE + UPDATE => X
+// An UPDATE of the handle key is split into a DELETE and an INSERT first.
+//
+// Only the changes of the tables whose unique key is the handle key are
+// compacted. The changes of different rows of such tables never conflict,
+// so the DELETEs of a table are moved before the other changes to merge
+// more changes into multi-row statements. Changes of different tables are
+// grouped by table, the order of the changes of other tables is kept.
+func compactChanges(changes []*dmlChange) []*dmlChange {
+	buffer := make([]*dmlChange, 0, len(changes))
+	keyMap := make(map[string]map[string]int)
+	compact := func(change *dmlChange) {
+		tableID := change.TargetTableID()
+		tableKeyMap, ok := keyMap[tableID]
+		if !ok {
+			tableKeyMap = make(map[string]int)
+			keyMap[tableID] = tableKeyMap
+		}
+		key := change.IdentityKey()
+		prevPos, ok := tableKeyMap[key]
+		if !ok {
+			tableKeyMap[key] = len(buffer)
+			buffer = append(buffer, change)
+			return
+		}
+
+		prev := buffer[prevPos]
+		change.compacted = true
+		log.Debug("compact row change",
+			zap.Stringer("previous", prev.RowChange), zap.Stringer("current", change.RowChange))
+		adjustSafeMode(change, prev)
+		if !shouldSkipReduce(change, prev) {
+			change.Reduce(prev.RowChange)
+		}
+		buffer[prevPos] = nil
+		tableKeyMap[key] = len(buffer)
+		buffer = append(buffer, change)
+	}
+
+	for _, change := range changes {
+		if !change.compactable {
+			buffer = append(buffer, change)
+			continue
+		}
+		if change.IsIdentityUpdated() {
+			del, ins := change.SplitUpdate()
+			delChange, insChange := *change, *change
+			delChange.RowChange, insChange.RowChange = del, ins
+			compact(&delChange)
+			compact(&insChange)
+			continue
+		}
+		compact(change)
+	}
+
+	tables := make(map[string][]*dmlChange)
+	order := make([]string, 0)
+	for _, change := range buffer {
+		if change == nil {
+			continue
+		}
+		tableID := change.TargetTableID()
+		if _, ok := tables[tableID]; !ok {
+			order = append(order, tableID)
+		}
+		tables[tableID] = append(tables[tableID], change)
+	}
+	compacted := make([]*dmlChange, 0, len(buffer))
+	for _, tableID := range order {
+		tableChanges := tables[tableID]
+		if allCompactable(tableChanges) {
+			sort.SliceStable(tableChanges, func(i, j int) bool {
+				return tableChanges[i].Type() == sqlmodel.RowChangeDelete &&
+					tableChanges[j].Type() != sqlmodel.RowChangeDelete
+			})
+		}
+		compacted = append(compacted, tableChanges...)
+	}
+	return compacted
+}
+
+func allCompactable(changes []*dmlChange) bool {
+	for _, change := range changes {
+		if !change.compactable {
+			return false
+		}
+	}
+	return true
+}
+
+// shouldSkipReduce returns true for DELETE + INSERT, the INSERT is written in
+// safe mode instead.
+func shouldSkipReduce(change, prev *dmlChange) bool {
+	return change.Type() == sqlmodel.RowChangeInsert &&
+		prev.Type() == sqlmodel.RowChangeDelete
+}
+
+func adjustSafeMode(change, prev *dmlChange) {
+	switch change.Type() {
+	case sqlmodel.RowChangeUpdate:
+		if prev.Type() == sqlmodel.RowChangeInsert {
+			// DELETE + INSERT + UPDATE => INSERT in safe mode
+			change.safeMode = prev.safeMode
+		}
+	case sqlmodel.RowChangeInsert:
+		if prev.Type() == sqlmodel.RowChangeDelete {
+			change.safeMode = true
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactChanges(t *testing.T) {
+	testCases := []struct {
+		rows           []*model.RowChangedEvent
+		expectedSQLs   []string
+		expectedValues [][]interface{}
+	}{
+		// INSERT + UPDATE => INSERT
+		{
+			rows: []*model.RowChangedEvent{
+				newTestRow("t1", nil, []interface{}{1, "a"}),
+				newTestRow("t1", []interface{}{1, "a"}, []interface{}{1, "b"}),
+			},
+			expectedSQLs:   []string{"INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)"},
+			expectedValues: [][]interface{}{{1, "b"}},
+		},
+		// UPDATE + UPDATE => UPDATE
+		{
+			rows: []*model.RowChangedEvent{
+				newTestRow("t1", []interface{}{1, "a"}, []interface{}{1, "b"}),
+				newTestRow("t1", []interface{}{1, "b"}, []interface{}{1, "c"}),
+				newTestRow("t1", []interface{}{2, "a"}, []interface{}{2, "b"}),
+			},
+			expectedSQLs: []string{
+				"INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?) " +
+					"ON DUPLICATE KEY UPDATE `a`=VALUES(`a`),`b`=VALUES(`b`)",
+				"UPDATE `s1`.`t1` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1",
+			},
+			expectedValues: [][]interface{}{{1, "c"}, {2, "b", 2}},
+		},
+		// a single UPDATE isn't merged, it is written as is
+		{
+			rows: []*model.RowChangedEvent{
+				newTestRow("t1", []interface{}{1, "a"}, []interface{}{1, "b"}),
+			},
+			expectedSQLs:   []string{"UPDATE `s1`.`t1` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1"},
+			expectedValues: [][]interface{}{{1, "b", 1}},
+		},
+		// INSERT + DELETE => DELETE, DELETE + INSERT => REPLACE
+		{
+			rows: []*model.RowChangedEvent{
+				newTestRow("t1", nil, []interface{}{1, "a"}),
+				newTestRow("t1", []interface{}{1, "a"}, nil),
+				newTestRow("t1", []interface{}{2, "a"}, nil),
+				newTestRow("t1", nil, []interface{}{2, "b"}),
+			},
+			expectedSQLs: []string{
+				"DELETE FROM `s1`.`t1` WHERE `a` = ? LIMIT 1",
+				"REPLACE INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)",
+			},
+			expectedValues: [][]interface{}{{1}, {2, "b"}},
+		},
+		// the UPDATE of handle key is split, and the DELETEs are moved first
+		{
+			rows: []*model.RowChangedEvent{
+				newTestRow("t1", nil, []interface{}{3, "c"}),
+				newTestRow("t1", []interface{}{1, "a"}, []interface{}{2, "a"}),
+				newTestRow("t1", []interface{}{4, "d"}, nil),
+			},
+			expectedSQLs: []string{
+				"DELETE FROM `s1`.`t1` WHERE (`a`) IN ((?),(?))",
+				"INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?),(?,?)",
+			},
+			expectedValues: [][]interface{}{{1, 4}, {3, "c", 2, "a"}},
+		},
+		// the changes are grouped by table
+		{
+			rows: []*model.RowChangedEvent{
+				newTestRow("t1", nil, []interface{}{1, "a"}),
+				newTestRow("t2", nil, []interface{}{1, "a"}),
+				newTestRow("t1", nil, []interface{}{2, "b"}),
+			},
+			expectedSQLs: []string{
+				"INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?),(?,?)",
+				"INSERT INTO `s1`.`t2` (`a`,`b`) VALUES (?,?)",
+			},
+			expectedValues: [][]interface{}{{1, "a", 2, "b"}, {1, "a"}},
+		},
+	}
+	for _, tc := range testCases {
+		sqls, values := genTestDMLs(tc.rows, false, true, 10)
+		require.Equal(t, tc.expectedSQLs, sqls)
+		require.Equal(t, tc.expectedValues, values)
+	}
+
+	// the changes of a table with other unique keys are not compacted
+	rows := []*model.RowChangedEvent{
+		newTestRow("t1", nil, []interface{}{1, "a"}),
+		newTestRow("t1", []interface{}{1, "a"}, nil),
+		newTestRow("t1", nil, []interface{}{2, "a"}),
+	}
+	for _, row := range rows {
+		row.IndexColumns = [][]int{{0}, {1}}
+	}
+	sqls, values := genTestDMLs(rows, false, true, 10)
+	require.Equal(t, []string{
+		"INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)",
+		"DELETE FROM `s1`.`t1` WHERE `a` = ? LIMIT 1",
+		"INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)",
+	}, sqls)
+	require.Equal(t, [][]interface{}{{1, "a"}, {1}, {2, "a"}}, values)
+}
@@ -0,0 +1,306 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/charset"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
+)
+
+// omittedColumnExpr is the generated expression of the placeholder columns
+// whose values are not sent by TiCDC. Generated columns are never written to
+// the downstream, so are the placeholders.
+const omittedColumnExpr = "omitted"
+
+// dmlChange is a row change to be written to the downstream.
+type dmlChange struct {
+	*sqlmodel.RowChange
+	// safeMode writes the change idempotently, INSERT is written as REPLACE
+	// and UPDATE is written as DELETE + REPLACE.
+	safeMode bool
+	// compactable is true if the row can be identified by its handle key in
+	// the downstream and the table has no other unique key, so the changes of
+	// different rows never conflict with each other.
+	compactable bool
+	// compacted is true if the change is merged with the other changes of
+	// the same row by the compactor, see compactChanges.
+	compacted bool
+	// columnsKey consists of the names of the written columns. The changes of
+	// the same table and columnsKey can be merged into one statement.
+	columnsKey string
+}
+
+// newDMLChanges converts a row changed event to the changes to be written.
+// An UPDATE in safe mode is converted to a REPLACE, or a DELETE and a
+// REPLACE if the handle key is updated.
+func newDMLChanges(row *model.RowChangedEvent, forceReplicate, safeMode bool) []*dmlChange {
+	columns := row.Columns
+	if len(columns) == 0 {
+		columns = row.PreColumns
+	}
+	tableInfo, hasHandle, compactable := buildTableInfo(row.Table, columns, row.IndexColumns)
+	base := dmlChange{
+		safeMode:    safeMode,
+		compactable: compactable,
+		columnsKey:  buildColumnsKey(columns),
+	}
+	newChange := func(rowChange *sqlmodel.RowChange) *dmlChange {
+		change := base
+		change.RowChange = rowChange
+		return &change
+	}
+
+	preValues, postValues := columnValues(row.PreColumns), columnValues(row.Columns)
+	// Without a handle key, a row can only be identified by all of its
+	// columns, which is only allowed for the force-replicate changefeeds.
+	if !hasHandle && !forceReplicate && preValues != nil {
+		if !safeMode || postValues == nil {
+			return nil
+		}
+		preValues = nil
+	}
+	if preValues == nil && postValues == nil {
+		return nil
+	}
+	change := sqlmodel.NewRowChange(row.Table, nil, preValues, postValues, tableInfo, nil, nil)
+	if !safeMode || change.Type() != sqlmodel.RowChangeUpdate {
+		return []*dmlChange{newChange(change)}
+	}
+	del, ins := change.SplitUpdate()
+	if !change.IsIdentityUpdated() {
+		return []*dmlChange{newChange(ins)}
+	}
+	return []*dmlChange{newChange(del), newChange(ins)}
+}
+
+// dmlType returns the type of the statement the change is written as.
+func (c *dmlChange) dmlType() sqlmodel.DMLType {
+	switch c.Type() {
+	case sqlmodel.RowChangeInsert:
+		if c.safeMode {
+			return sqlmodel.DMLReplace
+		}
+		return sqlmodel.DMLInsert
+	case sqlmodel.RowChangeUpdate:
+		// A compacted UPDATE which keeps the handle key can be merged with
+		// others as INSERT ... ON DUPLICATE KEY UPDATE. The handle key is the
+		// only unique key of the table, so the upsert can't overwrite another
+		// row. Otherwise, the UPDATE is written as is, so that an UPDATE of a
+		// row missing in the downstream isn't turned into an INSERT.
+		if !c.safeMode && c.compacted && c.HasNotNullUniqueIdx() && !c.IsIdentityUpdated() {
+			return sqlmodel.DMLInsertOnDuplicateUpdate
+		}
+		return sqlmodel.DMLUpdate
+	case sqlmodel.RowChangeDelete:
+		return sqlmodel.DMLDelete
+	}
+	return sqlmodel.DMLNull
+}
+
+// canMergeWith checks whether two changes can be merged into one statement.
+func (c *dmlChange) canMergeWith(other *dmlChange) bool {
+	tp := c.dmlType()
+	switch tp {
+	case sqlmodel.DMLInsert, sqlmodel.DMLReplace, sqlmodel.DMLInsertOnDuplicateUpdate:
+	case sqlmodel.DMLDelete:
+		// The multi-row DELETE can't match NULL values.
+		if !c.HasNotNullUniqueIdx() || !other.HasNotNullUniqueIdx() {
+			return false
+		}
+	default:
+		return false
+	}
+	return tp == other.dmlType() &&
+		c.TargetTableID() == other.TargetTableID() &&
+		c.columnsKey == other.columnsKey
+}
+
+// genDMLs generates the statements of the changes in order, successive
+// changes of the same type and table are merged into multi-row statements
+// of at most batchSize rows.
+func genDMLs(changes []*dmlChange, batchSize int) ([]string, [][]interface{}) {
+	sqls := make([]string, 0, len(changes))
+	values := make([][]interface{}, 0, len(changes))
+	for i := 0; i < len(changes); {
+		first := changes[i]
+		j := i + 1
+		for j < len(changes) && j-i < batchSize && first.canMergeWith(changes[j]) {
+			j++
+		}
+		tp := first.dmlType()
+		switch {
+		case tp == sqlmodel.DMLUpdate && first.safeMode:
+			query, args := first.GenSQL(sqlmodel.DMLDelete)
+			sqls = append(sqls, query)
+			values = append(values, args)
+			query, args = first.GenSQL(sqlmodel.DMLReplace)
+			sqls = append(sqls, query)
+			values = append(values, args)
+		case j-i == 1:
+			query, args := first.GenSQL(tp)
+			sqls = append(sqls, query)
+			values = append(values, args)
+		case tp == sqlmodel.DMLDelete:
+			query, args := sqlmodel.GenDeleteSQL(rowChanges(changes[i:j])...)
+			sqls = append(sqls, query)
+			values = append(values, args)
+		default:
+			query, args := sqlmodel.GenInsertSQL(tp, rowChanges(changes[i:j])...)
+			sqls = append(sqls, query)
+			values = append(values, args)
+		}
+		i = j
+	}
+	return sqls, values
+}
+
+func rowChanges(changes []*dmlChange) []*sqlmodel.RowChange {
+	ret := make([]*sqlmodel.RowChange, 0, len(changes))
+	for _, change := range changes {
+		ret = append(ret, change.RowChange)
+	}
+	return ret
+}
+
+// buildTableInfo builds a TiDB TableInfo from the columns of a row, which is
+// required by sqlmodel. The handle key is built as the primary key so that
+// it's used to identify the row in the downstream, and the other unique
+// indexes are built as unique keys.
+func buildTableInfo(
+	table *model.TableName, columns []*model.Column, indexColumns [][]int,
+) (tableInfo *timodel.TableInfo, hasHandle bool, compactable bool) {
+	tableInfo = &timodel.TableInfo{Name: timodel.NewCIStr(table.Table)}
+	var handle []int
+	for i, col := range columns {
+		colInfo := &timodel.ColumnInfo{
+			ID:     int64(i + 1),
+			Offset: i,
+			State:  timodel.StatePublic,
+		}
+		tableInfo.Columns = append(tableInfo.Columns, colInfo)
+		if col == nil {
+			colInfo.Name = timodel.NewCIStr(fmt.Sprintf("_omitted_%d", i))
+			colInfo.GeneratedExprString = omittedColumnExpr
+			continue
+		}
+		colInfo.Name = timodel.NewCIStr(col.Name)
+		colInfo.Tp = col.Type
+		if col.Flag.IsGeneratedColumn() {
+			colInfo.GeneratedExprString = omittedColumnExpr
+			colInfo.GeneratedStored = true
+		}
+		if !col.Flag.IsNullable() {
+			colInfo.Flag |= mysql.NotNullFlag
+		}
+		if col.Flag.IsHandleKey() {
+			handle = append(handle, i)
+		}
+	}
+	if len(handle) > 0 {
+		tableInfo.Indices = append(tableInfo.Indices, buildIndexInfo(tableInfo, "primary", handle, true))
+	}
+	for i, offsets := range indexColumns {
+		if equalOffsets(offsets, handle) {
+			continue
+		}
+		tableInfo.Indices = append(tableInfo.Indices,
+			buildIndexInfo(tableInfo, fmt.Sprintf("uk_%d", i), offsets, false))
+	}
+	hasHandle = len(handle) > 0
+	return tableInfo, hasHandle, hasHandle && len(tableInfo.Indices) == 1
+}
+
+func buildIndexInfo(
+	tableInfo *timodel.TableInfo, name string, offsets []int, primary bool,
+) *timodel.IndexInfo {
+	indexInfo := &timodel.IndexInfo{
+		Name:    timodel.NewCIStr(name),
+		Table:   tableInfo.Name,
+		Unique:  true,
+		Primary: primary,
+		State:   timodel.StatePublic,
+	}
+	for _, offset := range offsets {
+		indexInfo.Columns = append(indexInfo.Columns, &timodel.IndexColumn{
+			Name:   tableInfo.Columns[offset].Name,
+			Offset: offset,
+		})
+	}
+	return indexInfo
+}
+
+func equalOffsets(lhs, rhs []int) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+	for i := range lhs {
+		if lhs[i] != rhs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func buildColumnsKey(columns []*model.Column) string {
+	var builder strings.Builder
+	for _, col := range columns {
+		if col == nil || col.Flag.IsGeneratedColumn() {
+			builder.WriteByte(',')
+			continue
+		}
+		builder.WriteString(col.Name)
+		builder.WriteByte(',')
+	}
+	return builder.String()
+}
+
+func columnValues(columns []*model.Column) []interface{} {
+	if len(columns) == 0 {
+		return nil
+	}
+	values := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		if col == nil {
+			values = append(values, nil)
+			continue
+		}
+		values = appendQueryArgs(values, col)
+	}
+	return values
+}
+
+// if the column value type is []byte and charset is not binary, we get its string
+// representation. Because if we use the byte array respresentation, the go-sql-driver
+// will automatically set `_binary` charset for that column, which is not expected.
+// See https://github.com/go-sql-driver/mysql/blob/ce134bfc/connection.go#L267
+func appendQueryArgs(args []interface{}, col *model.Column) []interface{} {
+	if col.Charset != "" && col.Charset != charset.CharsetBin {
+		colValBytes, ok := col.Value.([]byte)
+		if ok {
+			args = append(args, string(colValBytes))
+		} else {
+			args = append(args, col.Value)
+		}
+	} else {
+		args = append(args, col.Value)
+	}
+
+	return args
+}
@@ -0,0 +1,186 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRow(table string, pre, post []interface{}) *model.RowChangedEvent {
+	buildColumns := func(values []interface{}) []*model.Column {
+		if values == nil {
+			return nil
+		}
+		return []*model.Column{
+			{
+				Name:  "a",
+				Type:  mysql.TypeLong,
+				Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+				Value: values[0],
+			},
+			{
+				Name:  "b",
+				Type:  mysql.TypeVarchar,
+				Flag:  model.NullableFlag,
+				Value: values[1],
+			},
+		}
+	}
+	return &model.RowChangedEvent{
+		Table:        &model.TableName{Schema: "s1", Table: table},
+		PreColumns:   buildColumns(pre),
+		Columns:      buildColumns(post),
+		IndexColumns: [][]int{{0}},
+	}
+}
+
+func genTestDMLs(
+	rows []*model.RowChangedEvent, safeMode, compact bool, batchSize int,
+) ([]string, [][]interface{}) {
+	var changes []*dmlChange
+	for _, row := range rows {
+		changes = append(changes, newDMLChanges(row, false, safeMode)...)
+	}
+	if compact {
+		changes = compactChanges(changes)
+	}
+	return genDMLs(changes, batchSize)
+}
+
+func TestNewDMLChanges(t *testing.T) {
+	// update in safe mode is written as REPLACE
+	changes := newDMLChanges(newTestRow("t1", []interface{}{1, "a"}, []interface{}{1, "b"}), false, true)
+	require.Len(t, changes, 1)
+	require.True(t, changes[0].compactable)
+	sqls, values := genDMLs(changes, 1)
+	require.Equal(t, []string{"REPLACE INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)"}, sqls)
+	require.Equal(t, [][]interface{}{{1, "b"}}, values)
+
+	// update of the handle key in safe mode is written as DELETE + REPLACE
+	changes = newDMLChanges(newTestRow("t1", []interface{}{1, "a"}, []interface{}{2, "b"}), false, true)
+	require.Len(t, changes, 2)
+	sqls, values = genDMLs(changes, 1)
+	require.Equal(t, []string{
+		"DELETE FROM `s1`.`t1` WHERE `a` = ? LIMIT 1",
+		"REPLACE INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)",
+	}, sqls)
+	require.Equal(t, [][]interface{}{{1}, {2, "b"}}, values)
+
+	// a table without handle key can only be replicated with force-replicate
+	row := newTestRow("t1", []interface{}{1, "a"}, nil)
+	for _, col := range row.PreColumns {
+		col.Flag = 0
+	}
+	row.IndexColumns = nil
+	require.Nil(t, newDMLChanges(row, false, true))
+	changes = newDMLChanges(row, true, true)
+	require.Len(t, changes, 1)
+	require.False(t, changes[0].compactable)
+	sqls, values = genDMLs(changes, 1)
+	require.Equal(t, []string{"DELETE FROM `s1`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1"}, sqls)
+	require.Equal(t, [][]interface{}{{1, "a"}}, values)
+
+	// a table with another unique key is not compactable
+	row = newTestRow("t1", nil, []interface{}{1, "a"})
+	row.IndexColumns = [][]int{{0}, {1}}
+	changes = newDMLChanges(row, false, false)
+	require.Len(t, changes, 1)
+	require.False(t, changes[0].compactable)
+}
+
+func TestGenMultiRowDMLs(t *testing.T) {
+	rows := []*model.RowChangedEvent{
+		newTestRow("t1", nil, []interface{}{1, "a"}),
+		newTestRow("t1", nil, []interface{}{2, "b"}),
+		newTestRow("t1", nil, []interface{}{3, "c"}),
+		newTestRow("t2", nil, []interface{}{1, "a"}),
+		newTestRow("t2", []interface{}{2, "b"}, []interface{}{2, "bb"}),
+		newTestRow("t2", []interface{}{3, "c"}, []interface{}{3, "cc"}),
+		newTestRow("t2", []interface{}{4, "d"}, nil),
+		newTestRow("t2", []interface{}{5, "e"}, nil),
+	}
+	sqls, values := genTestDMLs(rows, false, false, 2)
+	require.Equal(t, []string{
+		"INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?),(?,?)",
+		"INSERT INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)",
+		"INSERT INTO `s1`.`t2` (`a`,`b`) VALUES (?,?)",
+		"UPDATE `s1`.`t2` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1",
+		"UPDATE `s1`.`t2` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1",
+		"DELETE FROM `s1`.`t2` WHERE (`a`) IN ((?),(?))",
+	}, sqls)
+	require.Equal(t, [][]interface{}{
+		{1, "a", 2, "b"},
+		{3, "c"},
+		{1, "a"},
+		{2, "bb", 2},
+		{3, "cc", 3},
+		{4, 5},
+	}, values)
+
+	// an UPDATE which isn't merged with another change is written as is,
+	// even if the compaction is enabled
+	sqls, values = genTestDMLs(rows[4:5], false, true, 2)
+	require.Equal(t, []string{
+		"UPDATE `s1`.`t2` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1",
+	}, sqls)
+	require.Equal(t, [][]interface{}{{2, "bb", 2}}, values)
+	sqls, _ = genTestDMLs(rows[4:6], false, true, 2)
+	require.Equal(t, []string{
+		"UPDATE `s1`.`t2` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1",
+		"UPDATE `s1`.`t2` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1",
+	}, sqls)
+
+	// the compacted UPDATEs are merged as an upsert
+	updates := []*model.RowChangedEvent{
+		newTestRow("t2", []interface{}{2, "b"}, []interface{}{2, "bb"}),
+		newTestRow("t2", []interface{}{3, "c"}, []interface{}{3, "cc"}),
+		newTestRow("t2", []interface{}{2, "bb"}, []interface{}{2, "bbb"}),
+		newTestRow("t2", []interface{}{3, "cc"}, []interface{}{3, "ccc"}),
+	}
+	sqls, values = genTestDMLs(updates, false, true, 2)
+	require.Equal(t, []string{
+		"INSERT INTO `s1`.`t2` (`a`,`b`) VALUES (?,?),(?,?) " +
+			"ON DUPLICATE KEY UPDATE `a`=VALUES(`a`),`b`=VALUES(`b`)",
+	}, sqls)
+	require.Equal(t, [][]interface{}{{2, "bbb", 3, "ccc"}}, values)
+
+	// the compacted UPDATEs of a table with another unique key are not
+	// merged, the upsert may overwrite another row
+	for _, row := range updates {
+		row.IndexColumns = [][]int{{0}, {1}}
+	}
+	sqls, _ = genTestDMLs(updates, false, true, 2)
+	require.Len(t, sqls, len(updates))
+	for _, sql := range sqls {
+		require.Equal(t, "UPDATE `s1`.`t2` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1", sql)
+	}
+
+	// the statements are not merged if batch size is 1
+	sqls, _ = genTestDMLs(rows, false, false, 1)
+	require.Len(t, sqls, len(rows))
+
+	// the rows with different written columns are not merged
+	rows[1].Columns[1].Flag |= model.GeneratedColumnFlag
+	sqls, values = genTestDMLs(rows[:3], true, false, 10)
+	require.Equal(t, []string{
+		"REPLACE INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)",
+		"REPLACE INTO `s1`.`t1` (`a`) VALUES (?)",
+		"REPLACE INTO `s1`.`t1` (`a`,`b`) VALUES (?,?)",
+	}, sqls)
+	require.Equal(t, [][]interface{}{{1, "a"}, {2}, {3, "c"}}, values)
+}
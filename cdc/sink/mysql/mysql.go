@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -95,6 +94,15 @@ func NewMySQLSink(
 	}
 
 	params.enableOldValue = replicaConfig.EnableOldValue
+	// The MySQL sink writes whole rows to the downstream.
+	if replicaConfig.HasPartialRowImage() {
+		return nil, cerror.ErrRowImageRuleInvalid.GenWithStackByArgs(
+			"only the full row image is supported by the MySQL sink")
+	}
+	if replicaConfig.Sink.SchemaBootstrap {
+		return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			errors.New("the schema bootstrap is not supported by the MySQL sink"))
+	}
 
 	// dsn format of the driver:
 	// [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
@@ -679,86 +687,27 @@ type preparedDMLs struct {
 
 // prepareDMLs converts model.RowChangedEvent list to query string list and args list
 func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, replicaID uint64, bucket int) *preparedDMLs {
-	sqls := make([]string, 0, len(rows))
-	values := make([][]interface{}, 0, len(rows))
-	replaces := make(map[string][][]interface{})
-	rowCount := 0
 	// translateToInsert control the update and insert behavior
 	translateToInsert := s.params.enableOldValue && !s.params.safeMode
 
-	// flush cached batch replace or insert, to keep the sequence of DMLs
-	flushCacheDMLs := func() {
-		if s.params.batchReplaceEnabled && len(replaces) > 0 {
-			replaceSqls, replaceValues := reduceReplace(replaces, s.params.batchReplaceSize)
-			sqls = append(sqls, replaceSqls...)
-			values = append(values, replaceValues...)
-			replaces = make(map[string][][]interface{})
-		}
-	}
-
+	changes := make([]*dmlChange, 0, len(rows))
+	rowCount := 0
 	for _, row := range rows {
-		var query string
-		var args []interface{}
-		quoteTable := quotes.QuoteSchema(row.Table.Schema, row.Table.Table)
-
-		// If the old value is enabled, is not in safe mode and is an update event, then translate to UPDATE.
-		// NOTICE: Only update events with the old value feature enabled will have both columns and preColumns.
-		if translateToInsert && len(row.PreColumns) != 0 && len(row.Columns) != 0 {
-			flushCacheDMLs()
-			query, args = prepareUpdate(quoteTable, row.PreColumns, row.Columns, s.forceReplicate)
-			if query != "" {
-				sqls = append(sqls, query)
-				values = append(values, args)
-				rowCount++
-			}
+		rowChanges := newDMLChanges(row, s.forceReplicate, !translateToInsert)
+		if len(rowChanges) == 0 {
 			continue
 		}
-
-		// Case for update event or delete event.
-		// For update event:
-		// If old value is disabled or in safe mode, update will be translated to DELETE + REPLACE SQL.
-		// So we will prepare a DELETE SQL here.
-		// For delete event:
-		// It will be translated directly into a DELETE SQL.
-		if len(row.PreColumns) != 0 {
-			flushCacheDMLs()
-			query, args = prepareDelete(quoteTable, row.PreColumns, s.forceReplicate)
-			if query != "" {
-				sqls = append(sqls, query)
-				values = append(values, args)
-				rowCount++
-			}
-		}
-
-		// Case for update event or insert event.
-		// For update event:
-		// If old value is disabled or in safe mode, update will be translated to DELETE + REPLACE SQL.
-		// So we will prepare a REPLACE SQL here.
-		// For insert event:
-		// It will be translated directly into a
-		// INSERT(old value is enabled and not in safe mode)
-		// or REPLACE(old value is disabled or in safe mode) SQL.
-		if len(row.Columns) != 0 {
-			if s.params.batchReplaceEnabled {
-				query, args = prepareReplace(quoteTable, row.Columns, false /* appendPlaceHolder */, translateToInsert)
-				if query != "" {
-					if _, ok := replaces[query]; !ok {
-						replaces[query] = make([][]interface{}, 0)
-					}
-					replaces[query] = append(replaces[query], args)
-					rowCount++
-				}
-			} else {
-				query, args = prepareReplace(quoteTable, row.Columns, true /* appendPlaceHolder */, translateToInsert)
-				if query != "" {
-					sqls = append(sqls, query)
-					values = append(values, args)
-					rowCount++
-				}
-			}
-		}
+		changes = append(changes, rowChanges...)
+		rowCount++
+	}
+	if s.params.compactionEnabled {
+		changes = compactChanges(changes)
+	}
+	batchSize := 1
+	if s.params.batchReplaceEnabled {
+		batchSize = s.params.batchReplaceSize
 	}
-	flushCacheDMLs()
+	sqls, values := genDMLs(changes, batchSize)
 
 	dmls := &preparedDMLs{
 		sqls:   sqls,
@@ -798,187 +747,6 @@ func (s *mysqlSink) execDMLs(ctx context.Context, rows []*model.RowChangedEvent,
 	return nil
 }
 
-// if the column value type is []byte and charset is not binary, we get its string
-// representation. Because if we use the byte array respresentation, the go-sql-driver
-// will automatically set `_binary` charset for that column, which is not expected.
-// See https://github.com/go-sql-driver/mysql/blob/ce134bfc/connection.go#L267
-func appendQueryArgs(args []interface{}, col *model.Column) []interface{} {
-	if col.Charset != "" && col.Charset != charset.CharsetBin {
-		colValBytes, ok := col.Value.([]byte)
-		if ok {
-			args = append(args, string(colValBytes))
-		} else {
-			args = append(args, col.Value)
-		}
-	} else {
-		args = append(args, col.Value)
-	}
-
-	return args
-}
-
-func prepareReplace(
-	quoteTable string,
-	cols []*model.Column,
-	appendPlaceHolder bool,
-	translateToInsert bool,
-) (string, []interface{}) {
-	var builder strings.Builder
-	columnNames := make([]string, 0, len(cols))
-	args := make([]interface{}, 0, len(cols))
-	for _, col := range cols {
-		if col == nil || col.Flag.IsGeneratedColumn() {
-			continue
-		}
-		columnNames = append(columnNames, col.Name)
-		args = appendQueryArgs(args, col)
-	}
-	if len(args) == 0 {
-		return "", nil
-	}
-
-	colList := "(" + buildColumnList(columnNames) + ")"
-	if translateToInsert {
-		builder.WriteString("INSERT INTO " + quoteTable + colList + " VALUES ")
-	} else {
-		builder.WriteString("REPLACE INTO " + quoteTable + colList + " VALUES ")
-	}
-	if appendPlaceHolder {
-		builder.WriteString("(" + model.HolderString(len(columnNames)) + ");")
-	}
-
-	return builder.String(), args
-}
-
-// reduceReplace groups SQLs with the same replace statement format, as following
-// sql: `REPLACE INTO `test`.`t` (`a`,`b`) VALUES (?,?,?,?,?,?)`
-// args: (1,"",2,"2",3,"")
-func reduceReplace(replaces map[string][][]interface{}, batchSize int) ([]string, [][]interface{}) {
-	nextHolderString := func(query string, valueNum int, last bool) string {
-		query += "(" + model.HolderString(valueNum) + ")"
-		if !last {
-			query += ","
-		}
-		return query
-	}
-	sqls := make([]string, 0)
-	args := make([][]interface{}, 0)
-	for replace, vals := range replaces {
-		query := replace
-		cacheCount := 0
-		cacheArgs := make([]interface{}, 0)
-		last := false
-		for i, val := range vals {
-			cacheCount++
-			if i == len(vals)-1 || cacheCount >= batchSize {
-				last = true
-			}
-			query = nextHolderString(query, len(val), last)
-			cacheArgs = append(cacheArgs, val...)
-			if last {
-				sqls = append(sqls, query)
-				args = append(args, cacheArgs)
-				query = replace
-				cacheCount = 0
-				cacheArgs = make([]interface{}, 0, len(cacheArgs))
-				last = false
-			}
-		}
-	}
-	return sqls, args
-}
-
-func prepareUpdate(quoteTable string, preCols, cols []*model.Column, forceReplicate bool) (string, []interface{}) {
-	var builder strings.Builder
-	builder.WriteString("UPDATE " + quoteTable + " SET ")
-
-	columnNames := make([]string, 0, len(cols))
-	args := make([]interface{}, 0, len(cols)+len(preCols))
-	for _, col := range cols {
-		if col == nil || col.Flag.IsGeneratedColumn() {
-			continue
-		}
-		columnNames = append(columnNames, col.Name)
-		args = appendQueryArgs(args, col)
-	}
-	if len(args) == 0 {
-		return "", nil
-	}
-	for i, column := range columnNames {
-		if i == len(columnNames)-1 {
-			builder.WriteString("`" + quotes.EscapeName(column) + "`=?")
-		} else {
-			builder.WriteString("`" + quotes.EscapeName(column) + "`=?,")
-		}
-	}
-
-	builder.WriteString(" WHERE ")
-	colNames, wargs := whereSlice(preCols, forceReplicate)
-	if len(wargs) == 0 {
-		return "", nil
-	}
-	for i := 0; i < len(colNames); i++ {
-		if i > 0 {
-			builder.WriteString(" AND ")
-		}
-		if wargs[i] == nil {
-			builder.WriteString(quotes.QuoteName(colNames[i]) + " IS NULL")
-		} else {
-			builder.WriteString(quotes.QuoteName(colNames[i]) + "=?")
-			args = append(args, wargs[i])
-		}
-	}
-	builder.WriteString(" LIMIT 1;")
-	sql := builder.String()
-	return sql, args
-}
-
-func prepareDelete(quoteTable string, cols []*model.Column, forceReplicate bool) (string, []interface{}) {
-	var builder strings.Builder
-	builder.WriteString("DELETE FROM " + quoteTable + " WHERE ")
-
-	colNames, wargs := whereSlice(cols, forceReplicate)
-	if len(wargs) == 0 {
-		return "", nil
-	}
-	args := make([]interface{}, 0, len(wargs))
-	for i := 0; i < len(colNames); i++ {
-		if i > 0 {
-			builder.WriteString(" AND ")
-		}
-		if wargs[i] == nil {
-			builder.WriteString(quotes.QuoteName(colNames[i]) + " IS NULL")
-		} else {
-			builder.WriteString(quotes.QuoteName(colNames[i]) + " = ?")
-			args = append(args, wargs[i])
-		}
-	}
-	builder.WriteString(" LIMIT 1;")
-	sql := builder.String()
-	return sql, args
-}
-
-func whereSlice(cols []*model.Column, forceReplicate bool) (colNames []string, args []interface{}) {
-	// Try to use unique key values when available
-	for _, col := range cols {
-		if col == nil || !col.Flag.IsHandleKey() {
-			continue
-		}
-		colNames = append(colNames, col.Name)
-		args = appendQueryArgs(args, col)
-	}
-	// if no explicit row id but force replicate, use all key-values in where condition
-	if len(colNames) == 0 && forceReplicate {
-		colNames = make([]string, 0, len(cols))
-		args = make([]interface{}, 0, len(cols))
-		for _, col := range cols {
-			colNames = append(colNames, col.Name)
-			args = appendQueryArgs(args, col)
-		}
-	}
-	return
-}
-
 func getSQLErrCode(err error) (errors.ErrCode, bool) {
 	mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError)
 	if !ok {
@@ -988,19 +756,6 @@ func getSQLErrCode(err error) (errors.ErrCode, bool) {
 	return errors.ErrCode(mysqlErr.Number), true
 }
 
-func buildColumnList(names []string) string {
-	var b strings.Builder
-	for i, name := range names {
-		if i > 0 {
-			b.WriteString(",")
-		}
-		b.WriteString(quotes.QuoteName(name))
-
-	}
-
-	return b.String()
-}
-
 // GetDBConnImpl is the implement holder to get db connection. Export it for tests
 var GetDBConnImpl = getDBConn
 
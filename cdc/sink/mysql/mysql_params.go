@@ -46,6 +46,7 @@ const (
 	defaultFlushInterval       = time.Millisecond * 50
 	defaultBatchReplaceEnabled = true
 	defaultBatchReplaceSize    = 20
+	defaultCompactionEnabled   = true
 	defaultReadTimeout         = "2m"
 	defaultWriteTimeout        = "2m"
 	defaultDialTimeout         = "2m"
@@ -65,6 +66,7 @@ var defaultParams = &sinkParams{
 	tidbTxnMode:         defaultTiDBTxnMode,
 	batchReplaceEnabled: defaultBatchReplaceEnabled,
 	batchReplaceSize:    defaultBatchReplaceSize,
+	compactionEnabled:   defaultCompactionEnabled,
 	readTimeout:         defaultReadTimeout,
 	writeTimeout:        defaultWriteTimeout,
 	dialTimeout:         defaultDialTimeout,
@@ -86,6 +88,7 @@ type sinkParams struct {
 	captureAddr         string
 	batchReplaceEnabled bool
 	batchReplaceSize    int
+	compactionEnabled   bool
 	readTimeout         string
 	writeTimeout        string
 	dialTimeout         string
@@ -193,6 +196,15 @@ func parseSinkURIToParams(ctx context.Context, sinkURI *url.URL, opts map[string
 		params.batchReplaceSize = size
 	}
 
+	s = sinkURI.Query().Get("compaction-enable")
+	if s != "" {
+		enable, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		params.compactionEnabled = enable
+	}
+
 	// TODO: force safe mode in startup phase
 	s = sinkURI.Query().Get("safe-mode")
 	if s != "" {
@@ -39,6 +39,7 @@ func TestSinkParamsClone(t *testing.T) {
 		tidbTxnMode:         defaultTiDBTxnMode,
 		batchReplaceEnabled: defaultBatchReplaceEnabled,
 		batchReplaceSize:    defaultBatchReplaceSize,
+		compactionEnabled:   defaultCompactionEnabled,
 		readTimeout:         defaultReadTimeout,
 		writeTimeout:        defaultWriteTimeout,
 		dialTimeout:         defaultDialTimeout,
@@ -51,6 +52,7 @@ func TestSinkParamsClone(t *testing.T) {
 		tidbTxnMode:         defaultTiDBTxnMode,
 		batchReplaceEnabled: false,
 		batchReplaceSize:    defaultBatchReplaceSize,
+		compactionEnabled:   defaultCompactionEnabled,
 		readTimeout:         defaultReadTimeout,
 		writeTimeout:        defaultWriteTimeout,
 		dialTimeout:         defaultDialTimeout,
@@ -200,6 +202,7 @@ func TestParseSinkURIToParams(t *testing.T) {
 	expected.maxTxnRow = 20
 	expected.batchReplaceEnabled = true
 	expected.batchReplaceSize = 50
+	expected.compactionEnabled = false
 	expected.safeMode = true
 	expected.timezone = `"UTC"`
 	expected.changefeedID = "cf-id"
@@ -207,7 +210,7 @@ func TestParseSinkURIToParams(t *testing.T) {
 	expected.tidbTxnMode = "pessimistic"
 	uriStr := "mysql://127.0.0.1:3306/?worker-count=64&max-txn-row=20" +
 		"&batch-replace-enable=true&batch-replace-size=50&safe-mode=true" +
-		"&tidb-txn-mode=pessimistic"
+		"&tidb-txn-mode=pessimistic&compaction-enable=false"
 	opts := map[string]string{
 		metrics.OptChangefeedID: expected.changefeedID,
 		metrics.OptCaptureAddr:  expected.captureAddr,
@@ -295,6 +298,7 @@ func TestParseSinkURIBadQueryString(t *testing.T) {
 		"mysql://127.0.0.1:3306/?batch-replace-enable=not-bool",
 		"mysql://127.0.0.1:3306/?batch-replace-enable=true&batch-replace-size=not-number",
 		"mysql://127.0.0.1:3306/?safe-mode=not-bool",
+		"mysql://127.0.0.1:3306/?compaction-enable=not-bool",
 		"mysql://127.0.0.1:3306/?time-zone=badtz",
 		"mysql://127.0.0.1:3306/?write-timeout=badduration",
 		"mysql://127.0.0.1:3306/?read-timeout=badduration",
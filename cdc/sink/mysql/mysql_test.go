@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"net"
 	"net/url"
-	"sort"
 	"sync"
 	"testing"
 
@@ -86,7 +85,7 @@ func TestPrepareDML(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				sqls:     []string{"DELETE FROM `common_1`.`uk_without_pk` WHERE `a1` = ? AND `a3` = ? LIMIT 1;"},
+				sqls:     []string{"DELETE FROM `common_1`.`uk_without_pk` WHERE `a1` = ? AND `a3` = ? LIMIT 1"},
 				values:   [][]interface{}{{1, 1}},
 				rowCount: 1,
 			},
@@ -111,7 +110,7 @@ func TestPrepareDML(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				sqls:     []string{"REPLACE INTO `common_1`.`uk_without_pk`(`a1`,`a3`) VALUES (?,?);"},
+				sqls:     []string{"REPLACE INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?)"},
 				values:   [][]interface{}{{2, 2}},
 				rowCount: 1,
 			},
@@ -126,23 +125,37 @@ func TestPrepareDML(t *testing.T) {
 	}
 }
 
+// genRowDML generates the statement of a row change of `test`.`t1`.
+func genRowDML(
+	t *testing.T, preCols, cols []*model.Column, safeMode bool,
+) (string, []interface{}) {
+	row := &model.RowChangedEvent{
+		Table:      &model.TableName{Schema: "test", Table: "t1"},
+		PreColumns: preCols,
+		Columns:    cols,
+	}
+	sqls, values := genDMLs(newDMLChanges(row, false, safeMode), 1)
+	if len(sqls) == 0 {
+		return "", nil
+	}
+	require.Len(t, sqls, 1)
+	return sqls[0], values[0]
+}
+
 func TestPrepareUpdate(t *testing.T) {
 	testCases := []struct {
-		quoteTable   string
 		preCols      []*model.Column
 		cols         []*model.Column
 		expectedSQL  string
 		expectedArgs []interface{}
 	}{
 		{
-			quoteTable:   "`test`.`t1`",
 			preCols:      []*model.Column{},
 			cols:         []*model.Column{},
 			expectedSQL:  "",
 			expectedArgs: nil,
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -166,11 +179,11 @@ func TestPrepareUpdate(t *testing.T) {
 				},
 				{Name: "b", Type: mysql.TypeVarchar, Flag: 0, Value: "test2"},
 			},
-			expectedSQL:  "UPDATE `test`.`t1` SET `a`=?,`b`=? WHERE `a`=? LIMIT 1;",
+			// the change isn't compacted, written as an UPDATE
+			expectedSQL:  "UPDATE `test`.`t1` SET `a` = ?, `b` = ? WHERE `a` = ? LIMIT 1",
 			expectedArgs: []interface{}{1, "test2", 1},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -210,11 +223,10 @@ func TestPrepareUpdate(t *testing.T) {
 					Value: 100,
 				},
 			},
-			expectedSQL:  "UPDATE `test`.`t1` SET `a`=?,`b`=? WHERE `a`=? AND `b`=? LIMIT 1;",
+			expectedSQL:  "UPDATE `test`.`t1` SET `a` = ?, `b` = ? WHERE `a` = ? AND `b` = ? LIMIT 1",
 			expectedArgs: []interface{}{2, "test2", 1, "test"},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -254,11 +266,10 @@ func TestPrepareUpdate(t *testing.T) {
 					Value: 100,
 				},
 			},
-			expectedSQL:  "UPDATE `test`.`t1` SET `a`=?,`b`=? WHERE `a`=? AND `b`=? LIMIT 1;",
+			expectedSQL:  "UPDATE `test`.`t1` SET `a` = ?, `b` = ? WHERE `a` = ? AND `b` = ? LIMIT 1",
 			expectedArgs: []interface{}{2, []byte("世界"), 1, []byte("你好")},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -301,11 +312,10 @@ func TestPrepareUpdate(t *testing.T) {
 					Value: 100,
 				},
 			},
-			expectedSQL:  "UPDATE `test`.`t1` SET `a`=?,`b`=? WHERE `a`=? AND `b`=? LIMIT 1;",
+			expectedSQL:  "UPDATE `test`.`t1` SET `a` = ?, `b` = ? WHERE `a` = ? AND `b` = ? LIMIT 1",
 			expectedArgs: []interface{}{2, []byte("世界"), 1, []byte("你好")},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -348,13 +358,12 @@ func TestPrepareUpdate(t *testing.T) {
 					Value: 100,
 				},
 			},
-			expectedSQL:  "UPDATE `test`.`t1` SET `a`=?,`b`=? WHERE `a`=? AND `b`=? LIMIT 1;",
+			expectedSQL:  "UPDATE `test`.`t1` SET `a` = ?, `b` = ? WHERE `a` = ? AND `b` = ? LIMIT 1",
 			expectedArgs: []interface{}{2, "世界", 1, "你好"},
 		},
 	}
 	for _, tc := range testCases {
-		query, args := prepareUpdate(tc.quoteTable, tc.preCols, tc.cols, false)
-		fmt.Println(query)
+		query, args := genRowDML(t, tc.preCols, tc.cols, false)
 		require.Equal(t, tc.expectedSQL, query)
 		require.Equal(t, tc.expectedArgs, args)
 	}
@@ -362,19 +371,16 @@ func TestPrepareUpdate(t *testing.T) {
 
 func TestPrepareDelete(t *testing.T) {
 	testCases := []struct {
-		quoteTable   string
 		preCols      []*model.Column
 		expectedSQL  string
 		expectedArgs []interface{}
 	}{
 		{
-			quoteTable:   "`test`.`t1`",
 			preCols:      []*model.Column{},
 			expectedSQL:  "",
 			expectedArgs: nil,
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -389,11 +395,10 @@ func TestPrepareDelete(t *testing.T) {
 					Value: "test",
 				},
 			},
-			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? LIMIT 1;",
+			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? LIMIT 1",
 			expectedArgs: []interface{}{1},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -414,11 +419,10 @@ func TestPrepareDelete(t *testing.T) {
 					Value: 100,
 				},
 			},
-			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1;",
+			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1",
 			expectedArgs: []interface{}{1, "test"},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -438,11 +442,10 @@ func TestPrepareDelete(t *testing.T) {
 					Value: 100,
 				},
 			},
-			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1;",
+			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1",
 			expectedArgs: []interface{}{1, []byte("你好")},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -464,11 +467,10 @@ func TestPrepareDelete(t *testing.T) {
 					Value: 100,
 				},
 			},
-			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1;",
+			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1",
 			expectedArgs: []interface{}{1, []byte("你好")},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			preCols: []*model.Column{
 				{
 					Name:  "a",
@@ -490,12 +492,12 @@ func TestPrepareDelete(t *testing.T) {
 					Value: 100,
 				},
 			},
-			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1;",
+			expectedSQL:  "DELETE FROM `test`.`t1` WHERE `a` = ? AND `b` = ? LIMIT 1",
 			expectedArgs: []interface{}{1, "你好"},
 		},
 	}
 	for _, tc := range testCases {
-		query, args := prepareDelete(tc.quoteTable, tc.preCols, false)
+		query, args := genRowDML(t, tc.preCols, nil, false)
 		require.Equal(t, tc.expectedSQL, query)
 		require.Equal(t, tc.expectedArgs, args)
 	}
@@ -712,13 +714,11 @@ func TestWhereSlice(t *testing.T) {
 
 func TestMapReplace(t *testing.T) {
 	testCases := []struct {
-		quoteTable    string
 		cols          []*model.Column
 		expectedQuery string
 		expectedArgs  []interface{}
 	}{
 		{
-			quoteTable: "`test`.`t1`",
 			cols: []*model.Column{
 				{
 					Name:  "a",
@@ -742,11 +742,10 @@ func TestMapReplace(t *testing.T) {
 					Value: uint8(255),
 				},
 			},
-			expectedQuery: "REPLACE INTO `test`.`t1`(`a`,`b`,`d`) VALUES ",
+			expectedQuery: "REPLACE INTO `test`.`t1` (`a`,`b`,`d`) VALUES (?,?,?)",
 			expectedArgs:  []interface{}{1, "varchar", uint8(255)},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			cols: []*model.Column{
 				{
 					Name:  "a",
@@ -769,11 +768,10 @@ func TestMapReplace(t *testing.T) {
 					Value: uint8(255),
 				},
 			},
-			expectedQuery: "REPLACE INTO `test`.`t1`(`a`,`b`,`c`,`d`) VALUES ",
+			expectedQuery: "REPLACE INTO `test`.`t1` (`a`,`b`,`c`,`d`) VALUES (?,?,?,?)",
 			expectedArgs:  []interface{}{1, "varchar", 1, uint8(255)},
 		},
 		{
-			quoteTable: "`test`.`t1`",
 			cols: []*model.Column{
 				{
 					Name:  "a",
@@ -804,7 +802,7 @@ func TestMapReplace(t *testing.T) {
 					Value: []byte("你好,世界"),
 				},
 			},
-			expectedQuery: "REPLACE INTO `test`.`t1`(`a`,`b`,`c`,`d`,`e`) VALUES ",
+			expectedQuery: "REPLACE INTO `test`.`t1` (`a`,`b`,`c`,`d`,`e`) VALUES (?,?,?,?,?)",
 			expectedArgs: []interface{}{
 				1, "你好", "世界", []byte("你好,世界"),
 				[]byte("你好,世界"),
@@ -814,129 +812,13 @@ func TestMapReplace(t *testing.T) {
 	for _, tc := range testCases {
 		// multiple times to verify the stability of column sequence in query string
 		for i := 0; i < 10; i++ {
-			query, args := prepareReplace(tc.quoteTable, tc.cols, false, false)
+			query, args := genRowDML(t, nil, tc.cols, true)
 			require.Equal(t, tc.expectedQuery, query)
 			require.Equal(t, tc.expectedArgs, args)
 		}
 	}
 }
 
-type sqlArgs [][]interface{}
-
-func (a sqlArgs) Len() int           { return len(a) }
-func (a sqlArgs) Less(i, j int) bool { return fmt.Sprintf("%s", a[i]) < fmt.Sprintf("%s", a[j]) }
-func (a sqlArgs) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-
-func TestReduceReplace(t *testing.T) {
-	testCases := []struct {
-		replaces   map[string][][]interface{}
-		batchSize  int
-		sort       bool
-		expectSQLs []string
-		expectArgs [][]interface{}
-	}{
-		{
-			replaces: map[string][][]interface{}{
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES ": {
-					[]interface{}{1, "1"},
-					[]interface{}{2, "2"},
-					[]interface{}{3, "3"},
-				},
-			},
-			batchSize: 1,
-			sort:      false,
-			expectSQLs: []string{
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES (?,?)",
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES (?,?)",
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES (?,?)",
-			},
-			expectArgs: [][]interface{}{
-				{1, "1"},
-				{2, "2"},
-				{3, "3"},
-			},
-		},
-		{
-			replaces: map[string][][]interface{}{
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES ": {
-					[]interface{}{1, "1"},
-					[]interface{}{2, "2"},
-					[]interface{}{3, "3"},
-					[]interface{}{4, "3"},
-					[]interface{}{5, "5"},
-				},
-			},
-			batchSize: 3,
-			sort:      false,
-			expectSQLs: []string{
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES (?,?),(?,?),(?,?)",
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES (?,?),(?,?)",
-			},
-			expectArgs: [][]interface{}{
-				{1, "1", 2, "2", 3, "3"},
-				{4, "3", 5, "5"},
-			},
-		},
-		{
-			replaces: map[string][][]interface{}{
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES ": {
-					[]interface{}{1, "1"},
-					[]interface{}{2, "2"},
-					[]interface{}{3, "3"},
-					[]interface{}{4, "3"},
-					[]interface{}{5, "5"},
-				},
-			},
-			batchSize: 10,
-			sort:      false,
-			expectSQLs: []string{
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES (?,?),(?,?),(?,?),(?,?),(?,?)",
-			},
-			expectArgs: [][]interface{}{
-				{1, "1", 2, "2", 3, "3", 4, "3", 5, "5"},
-			},
-		},
-		{
-			replaces: map[string][][]interface{}{
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES ": {
-					[]interface{}{1, "1"},
-					[]interface{}{2, "2"},
-					[]interface{}{3, "3"},
-					[]interface{}{4, "3"},
-					[]interface{}{5, "5"},
-					[]interface{}{6, "6"},
-				},
-				"REPLACE INTO `test`.`t2`(`a`,`b`) VALUES ": {
-					[]interface{}{7, ""},
-					[]interface{}{8, ""},
-					[]interface{}{9, ""},
-				},
-			},
-			batchSize: 3,
-			sort:      true,
-			expectSQLs: []string{
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES (?,?),(?,?),(?,?)",
-				"REPLACE INTO `test`.`t1`(`a`,`b`) VALUES (?,?),(?,?),(?,?)",
-				"REPLACE INTO `test`.`t2`(`a`,`b`) VALUES (?,?),(?,?),(?,?)",
-			},
-			expectArgs: [][]interface{}{
-				{1, "1", 2, "2", 3, "3"},
-				{4, "3", 5, "5", 6, "6"},
-				{7, "", 8, "", 9, ""},
-			},
-		},
-	}
-	for _, tc := range testCases {
-		sqls, args := reduceReplace(tc.replaces, tc.batchSize)
-		if tc.sort {
-			sort.Strings(sqls)
-			sort.Sort(sqlArgs(args))
-		}
-		require.Equal(t, tc.expectSQLs, sqls)
-		require.Equal(t, tc.expectArgs, args)
-	}
-}
-
 func mockTestDB(adjustSQLMode bool) (*sql.DB, error) {
 	// mock for test db, which is used querying TiDB session variable
 	db, mock, err := sqlmock.New()
@@ -1088,6 +970,35 @@ func TestNewMySQLTimeout(t *testing.T) {
 	require.Equal(t, driver.ErrBadConn, errors.Cause(err))
 }
 
+func TestNewMySQLSinkPartialRowImage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=4")
+	require.Nil(t, err)
+	rc := config.GetDefaultReplicaConfig()
+	rc.RowImages = []*config.RowImageRule{{
+		Matcher: []string{"test.*"},
+		Image:   config.RowImageMinimal,
+	}}
+	f, err := filter.NewFilter(rc)
+	require.Nil(t, err)
+	_, err = NewMySQLSink(ctx, "test-changefeed", sinkURI, f, rc, map[string]string{})
+	require.Regexp(t, ".*only the full row image is supported by the MySQL sink.*", err)
+}
+
+func TestNewMySQLSinkSchemaBootstrap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=4")
+	require.Nil(t, err)
+	rc := config.GetDefaultReplicaConfig()
+	rc.Sink.SchemaBootstrap = true
+	f, err := filter.NewFilter(rc)
+	require.Nil(t, err)
+	_, err = NewMySQLSink(ctx, "test-changefeed", sinkURI, f, rc, map[string]string{})
+	require.Regexp(t, ".*the schema bootstrap is not supported by the MySQL sink.*", err)
+}
+
 func TestNewMySQLSinkExecDML(t *testing.T) {
 	dbIndex := 0
 	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
@@ -1104,12 +1015,12 @@ func TestNewMySQLSinkExecDML(t *testing.T) {
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		require.Nil(t, err)
 		mock.ExpectBegin()
-		mock.ExpectExec("REPLACE INTO `s1`.`t1`(`a`,`b`) VALUES (?,?),(?,?)").
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`,`b`) VALUES (?,?),(?,?)").
 			WithArgs(1, "test", 2, "test").
 			WillReturnResult(sqlmock.NewResult(2, 2))
 		mock.ExpectCommit()
 		mock.ExpectBegin()
-		mock.ExpectExec("REPLACE INTO `s1`.`t2`(`a`,`b`) VALUES (?,?),(?,?)").
+		mock.ExpectExec("REPLACE INTO `s1`.`t2` (`a`,`b`) VALUES (?,?),(?,?)").
 			WithArgs(1, "test", 2, "test").
 			WillReturnResult(sqlmock.NewResult(2, 2))
 		mock.ExpectCommit()
@@ -1314,7 +1225,7 @@ func TestExecDMLRollbackErrDatabaseNotExists(t *testing.T) {
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		require.Nil(t, err)
 		mock.ExpectBegin()
-		mock.ExpectExec("REPLACE INTO `s1`.`t1`(`a`) VALUES (?),(?)").
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?),(?)").
 			WithArgs(1, 2).
 			WillReturnError(errDatabaseNotExists)
 		mock.ExpectRollback()
@@ -1390,7 +1301,7 @@ func TestExecDMLRollbackErrTableNotExists(t *testing.T) {
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		require.Nil(t, err)
 		mock.ExpectBegin()
-		mock.ExpectExec("REPLACE INTO `s1`.`t1`(`a`) VALUES (?),(?)").
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?),(?)").
 			WithArgs(1, 2).
 			WillReturnError(errTableNotExists)
 		mock.ExpectRollback()
@@ -1467,7 +1378,7 @@ func TestExecDMLRollbackErrRetryable(t *testing.T) {
 		require.Nil(t, err)
 		for i := 0; i < int(defaultDMLMaxRetryTime); i++ {
 			mock.ExpectBegin()
-			mock.ExpectExec("REPLACE INTO `s1`.`t1`(`a`) VALUES (?),(?)").
+			mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?),(?)").
 				WithArgs(1, 2).
 				WillReturnError(errLockDeadlock)
 			mock.ExpectRollback()
@@ -1726,12 +1637,12 @@ func TestMySQLSinkFlushResolvedTs(t *testing.T) {
 		// normal db
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		mock.ExpectBegin()
-		mock.ExpectExec("REPLACE INTO `s1`.`t1`(`a`) VALUES (?)").
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
 			WithArgs(1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 		mock.ExpectBegin()
-		mock.ExpectExec("REPLACE INTO `s1`.`t2`(`a`) VALUES (?)").
+		mock.ExpectExec("REPLACE INTO `s1`.`t2` (`a`) VALUES (?)").
 			WithArgs(1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
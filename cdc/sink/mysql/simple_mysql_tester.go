@@ -116,45 +116,16 @@ func (s *simpleMySQLSink) EmitRowChangedEvents(ctx context.Context, rows ...*mod
 }
 
 func (s *simpleMySQLSink) executeRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
-	var sql string
-	var args []interface{}
-	if s.enableOldValue {
-		for _, row := range rows {
-			if len(row.PreColumns) != 0 && len(row.Columns) != 0 {
-				// update
-				if s.enableCheckOldValue {
-					err := s.checkOldValue(ctx, row)
-					if err != nil {
-						return errors.Trace(err)
-					}
-				}
-				sql, args = prepareReplace(row.Table.QuoteString(), row.Columns, true, false /* translateToInsert */)
-			} else if len(row.PreColumns) == 0 {
-				// insert
-				sql, args = prepareReplace(row.Table.QuoteString(), row.Columns, true, false /* translateToInsert */)
-			} else if len(row.Columns) == 0 {
-				// delete
-				if s.enableCheckOldValue {
-					err := s.checkOldValue(ctx, row)
-					if err != nil {
-						return errors.Trace(err)
-					}
-				}
-				sql, args = prepareDelete(row.Table.QuoteString(), row.PreColumns, true)
-			}
-			_, err := s.db.ExecContext(ctx, sql, args...)
+	for _, row := range rows {
+		if s.enableOldValue && s.enableCheckOldValue && len(row.PreColumns) != 0 {
+			err := s.checkOldValue(ctx, row)
 			if err != nil {
 				return errors.Trace(err)
 			}
 		}
-	} else {
-		for _, row := range rows {
-			if row.IsDelete() {
-				sql, args = prepareDelete(row.Table.QuoteString(), row.PreColumns, true)
-			} else {
-				sql, args = prepareReplace(row.Table.QuoteString(), row.Columns, true, false)
-			}
-			_, err := s.db.ExecContext(ctx, sql, args...)
+		sqls, values := genDMLs(newDMLChanges(row, true /* forceReplicate */, true /* safeMode */), 1)
+		for i, sql := range sqls {
+			_, err := s.db.ExecContext(ctx, sql, values[i]...)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -242,6 +213,27 @@ func prepareCheckSQL(quoteTable string, cols []*model.Column) (string, []interfa
 	return sql, args
 }
 
+func whereSlice(cols []*model.Column, forceReplicate bool) (colNames []string, args []interface{}) {
+	// Try to use unique key values when available
+	for _, col := range cols {
+		if col == nil || !col.Flag.IsHandleKey() {
+			continue
+		}
+		colNames = append(colNames, col.Name)
+		args = appendQueryArgs(args, col)
+	}
+	// if no explicit row id but force replicate, use all key-values in where condition
+	if len(colNames) == 0 && forceReplicate {
+		colNames = make([]string, 0, len(cols))
+		args = make([]interface{}, 0, len(cols))
+		for _, col := range cols {
+			colNames = append(colNames, col.Name)
+			args = appendQueryArgs(args, col)
+		}
+	}
+	return
+}
+
 func (s *simpleMySQLSink) checkOldValue(ctx context.Context, row *model.RowChangedEvent) error {
 	sql, args := prepareCheckSQL(row.Table.QuoteString(), row.PreColumns)
 	result, err := s.db.QueryContext(ctx, sql, args...)
@@ -152,7 +152,7 @@ func New(
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
 	if newSink, ok := sinkIniterMap[strings.ToLower(sinkURI.Scheme)]; ok {
-		return newSink(ctx, changefeedID, sinkURI, filter, config, opts, errCh)
+		return newTransformSink(ctx, changefeedID, sinkURI, filter, config, opts, errCh, newSink)
 	}
 	return nil, cerror.ErrSinkURIInvalid.GenWithStack("the sink scheme (%s) is not supported", sinkURI.Scheme)
 }
@@ -0,0 +1,413 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	timodel "github.com/pingcap/tidb/parser/model"
+	_ "github.com/pingcap/tidb/types/parser_driver" // for parser driver
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Names of the built-in DDL rewriters.
+const (
+	// RemoveAutoRandom removes the AUTO_RANDOM column attribute
+	// and the AUTO_RANDOM_BASE table option.
+	RemoveAutoRandom = "remove-auto-random"
+	// RemoveShardRowIDBits removes the SHARD_ROW_ID_BITS and
+	// PRE_SPLIT_REGIONS table options.
+	RemoveShardRowIDBits = "remove-shard-row-id-bits"
+	// RemoveClusteredIndex removes the CLUSTERED and NONCLUSTERED
+	// primary key attributes.
+	RemoveClusteredIndex = "remove-clustered-index"
+	// RemovePlacementPolicy removes the placement options and skips the
+	// placement policy statements.
+	RemovePlacementPolicy = "remove-placement-policy"
+)
+
+// DDLRewriter rewrites a DDL statement in place.
+type DDLRewriter interface {
+	// Rewrite returns true if the statement is changed.
+	Rewrite(stmt ast.StmtNode) bool
+}
+
+// DDLRewriterFunc is an adapter to allow the use of ordinary functions
+// as DDL rewriters.
+type DDLRewriterFunc func(stmt ast.StmtNode) bool
+
+// Rewrite implements DDLRewriter.
+func (f DDLRewriterFunc) Rewrite(stmt ast.StmtNode) bool {
+	return f(stmt)
+}
+
+var ddlRewriters = struct {
+	sync.RWMutex
+	m map[string]DDLRewriter
+}{
+	m: map[string]DDLRewriter{
+		RemoveAutoRandom: &optionRemover{
+			columnOptions: []ast.ColumnOptionType{ast.ColumnOptionAutoRandom},
+			tableOptions:  []ast.TableOptionType{ast.TableOptionAutoRandomBase},
+		},
+		RemoveShardRowIDBits: &optionRemover{
+			tableOptions: []ast.TableOptionType{ast.TableOptionShardRowID, ast.TableOptionPreSplitRegion},
+		},
+		RemoveClusteredIndex: &optionRemover{
+			clusteredIndex: true,
+		},
+		RemovePlacementPolicy: &optionRemover{
+			tableOptions:    []ast.TableOptionType{ast.TableOptionPlacementPolicy},
+			databaseOptions: []ast.DatabaseOptionType{ast.DatabaseOptionPlacementPolicy},
+			placementPolicy: true,
+		},
+	},
+}
+
+// RegisterDDLRewriter registers a DDL rewriter, so that it can be
+// referred by name in the `ddl-rewriters` of the sink config.
+// It overrides the rewriter registered with the same name.
+func RegisterDDLRewriter(name string, rewriter DDLRewriter) {
+	ddlRewriters.Lock()
+	defer ddlRewriters.Unlock()
+	ddlRewriters.m[name] = rewriter
+}
+
+func getDDLRewriter(name string) (DDLRewriter, bool) {
+	ddlRewriters.RLock()
+	defer ddlRewriters.RUnlock()
+	rewriter, ok := ddlRewriters.m[name]
+	return rewriter, ok
+}
+
+// DDLTransformer routes the table names in DDL events and rewrites
+// the DDL queries with the configured rewriters.
+type DDLTransformer struct {
+	router    *Router
+	rewriters []DDLRewriter
+}
+
+// NewDDLTransformer creates a DDLTransformer.
+// It returns nil if neither router nor rewriter is set.
+func NewDDLTransformer(router *Router, rewriterNames []string) (*DDLTransformer, error) {
+	if router == nil && len(rewriterNames) == 0 {
+		return nil, nil
+	}
+	rewriters := make([]DDLRewriter, 0, len(rewriterNames))
+	for _, name := range rewriterNames {
+		rewriter, ok := getDDLRewriter(name)
+		if !ok {
+			return nil, cerror.ErrDDLRewriterNotFound.GenWithStackByArgs(name)
+		}
+		rewriters = append(rewriters, rewriter)
+	}
+	return &DDLTransformer{router: router, rewriters: rewriters}, nil
+}
+
+// Transform returns a transformed copy of the DDL event, the given event
+// is left untouched. The second return value is true if the whole DDL
+// should be skipped, e.g. all the options of an `ALTER TABLE` are removed.
+func (t *DDLTransformer) Transform(ddl *model.DDLEvent) (*model.DDLEvent, bool, error) {
+	if t == nil {
+		return ddl, false, nil
+	}
+	stmt, err := parser.New().ParseOneStmt(ddl.Query, "", "")
+	if err != nil {
+		return nil, false, cerror.WrapError(cerror.ErrRewriteDDLFailed, err, ddl.Query)
+	}
+
+	changed := false
+	for _, rewriter := range t.rewriters {
+		if rewriter.Rewrite(stmt) {
+			changed = true
+		}
+	}
+	if changed && isEmptyDDL(stmt) {
+		return nil, true, nil
+	}
+
+	result := *ddl
+	if t.router != nil {
+		defaultSchema := ""
+		if ddl.TableInfo != nil {
+			defaultSchema = ddl.TableInfo.Schema
+			result.TableInfo = t.routeTableInfo(ddl.TableInfo)
+		}
+		if ddl.PreTableInfo != nil {
+			result.PreTableInfo = t.routeTableInfo(ddl.PreTableInfo)
+		}
+		if t.routeStmt(stmt, defaultSchema) {
+			changed = true
+		}
+	}
+	if !changed {
+		return &result, false, nil
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreTiDBSpecialComment |
+		format.RestoreNameBackQuotes |
+		format.RestoreKeyWordUppercase |
+		format.RestoreStringSingleQuotes
+	if err := stmt.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return nil, false, cerror.WrapError(cerror.ErrRewriteDDLFailed, errors.Trace(err), ddl.Query)
+	}
+	result.Query = sb.String()
+	return &result, false, nil
+}
+
+func (t *DDLTransformer) routeTableInfo(info *model.SimpleTableInfo) *model.SimpleTableInfo {
+	routed := *info
+	routed.Schema, routed.Table = t.router.Route(info.Schema, info.Table)
+	return &routed
+}
+
+// routeStmt routes the schema and table names in the statement,
+// it returns true if any name is changed.
+func (t *DDLTransformer) routeStmt(stmt ast.StmtNode, defaultSchema string) bool {
+	routeSchema := func(name string) (string, bool) {
+		target, _ := t.router.Route(name, "")
+		return target, target != name
+	}
+	switch v := stmt.(type) {
+	case *ast.CreateDatabaseStmt:
+		var changed bool
+		v.Name, changed = routeSchema(v.Name)
+		return changed
+	case *ast.DropDatabaseStmt:
+		var changed bool
+		v.Name, changed = routeSchema(v.Name)
+		return changed
+	case *ast.AlterDatabaseStmt:
+		if v.AlterDefaultDatabase {
+			return false
+		}
+		var changed bool
+		v.Name, changed = routeSchema(v.Name)
+		return changed
+	}
+	visitor := &tableNameRouter{router: t.router, defaultSchema: defaultSchema}
+	stmt.Accept(visitor)
+	return visitor.changed
+}
+
+type tableNameRouter struct {
+	router        *Router
+	defaultSchema string
+	changed       bool
+}
+
+// Enter implements ast.Visitor.
+func (v *tableNameRouter) Enter(in ast.Node) (ast.Node, bool) {
+	t, ok := in.(*ast.TableName)
+	if !ok {
+		return in, false
+	}
+	schema := t.Schema.O
+	if schema == "" {
+		schema = v.defaultSchema
+	}
+	targetSchema, targetTable := v.router.Route(schema, t.Name.O)
+	if targetSchema != schema || targetTable != t.Name.O {
+		// Always qualify the routed table name, since the current
+		// database of the downstream session is the routed schema of
+		// the DDL, which may differ from the routed schema of this table.
+		t.Schema = timodel.NewCIStr(targetSchema)
+		t.Name = timodel.NewCIStr(targetTable)
+		v.changed = true
+	}
+	return in, true
+}
+
+// Leave implements ast.Visitor.
+func (v *tableNameRouter) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// optionRemover removes the specified options from a DDL statement.
+type optionRemover struct {
+	columnOptions   []ast.ColumnOptionType
+	tableOptions    []ast.TableOptionType
+	databaseOptions []ast.DatabaseOptionType
+	// clusteredIndex indicates whether to remove the CLUSTERED and
+	// NONCLUSTERED attributes of primary keys.
+	clusteredIndex bool
+	// placementPolicy indicates whether to skip the placement policy statements.
+	placementPolicy bool
+}
+
+// Rewrite implements DDLRewriter.
+func (r *optionRemover) Rewrite(stmt ast.StmtNode) bool {
+	v := &optionRemoveVisitor{optionRemover: r}
+	stmt.Accept(v)
+	return v.changed
+}
+
+type optionRemoveVisitor struct {
+	*optionRemover
+	changed bool
+}
+
+// Enter implements ast.Visitor.
+func (v *optionRemoveVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	switch n := in.(type) {
+	case *ast.CreatePlacementPolicyStmt, *ast.AlterPlacementPolicyStmt, *ast.DropPlacementPolicyStmt:
+		if v.placementPolicy {
+			v.changed = true
+		}
+	case *ast.ColumnDef:
+		n.Options = v.removeColumnOptions(n.Options)
+	case *ast.ColumnOption:
+		if v.clusteredIndex && n.PrimaryKeyTp != timodel.PrimaryKeyTypeDefault {
+			n.PrimaryKeyTp = timodel.PrimaryKeyTypeDefault
+			v.changed = true
+		}
+	case *ast.Constraint:
+		if v.clusteredIndex && n.Option != nil && n.Option.PrimaryKeyTp != timodel.PrimaryKeyTypeDefault {
+			n.Option.PrimaryKeyTp = timodel.PrimaryKeyTypeDefault
+			if isEmptyIndexOption(n.Option) {
+				n.Option = nil
+			}
+			v.changed = true
+		}
+	case *ast.IndexOption:
+		if v.clusteredIndex && n.PrimaryKeyTp != timodel.PrimaryKeyTypeDefault {
+			n.PrimaryKeyTp = timodel.PrimaryKeyTypeDefault
+			v.changed = true
+		}
+	case *ast.CreateTableStmt:
+		n.Options = v.removeTableOptions(n.Options)
+	case *ast.AlterTableSpec:
+		n.Options = v.removeTableOptions(n.Options)
+		v.removePartitionOptions(n.PartDefinitions)
+	case *ast.PartitionOptions:
+		v.removePartitionOptions(n.Definitions)
+	case *ast.CreateDatabaseStmt:
+		n.Options = v.removeDatabaseOptions(n.Options)
+	case *ast.AlterDatabaseStmt:
+		n.Options = v.removeDatabaseOptions(n.Options)
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor.
+func (v *optionRemoveVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *optionRemoveVisitor) removeColumnOptions(options []*ast.ColumnOption) []*ast.ColumnOption {
+	result := options[:0]
+	for _, opt := range options {
+		if containsColumnOption(v.columnOptions, opt.Tp) {
+			v.changed = true
+			continue
+		}
+		result = append(result, opt)
+	}
+	return result
+}
+
+func (v *optionRemoveVisitor) removeTableOptions(options []*ast.TableOption) []*ast.TableOption {
+	result := options[:0]
+	for _, opt := range options {
+		if containsTableOption(v.tableOptions, opt.Tp) {
+			v.changed = true
+			continue
+		}
+		result = append(result, opt)
+	}
+	return result
+}
+
+func (v *optionRemoveVisitor) removePartitionOptions(defs []*ast.PartitionDefinition) {
+	for _, def := range defs {
+		def.Options = v.removeTableOptions(def.Options)
+	}
+}
+
+func (v *optionRemoveVisitor) removeDatabaseOptions(options []*ast.DatabaseOption) []*ast.DatabaseOption {
+	result := options[:0]
+	for _, opt := range options {
+		if containsDatabaseOption(v.databaseOptions, opt.Tp) {
+			v.changed = true
+			continue
+		}
+		result = append(result, opt)
+	}
+	return result
+}
+
+func isEmptyIndexOption(opt *ast.IndexOption) bool {
+	return opt.KeyBlockSize == 0 && opt.Tp == timodel.IndexTypeInvalid && opt.Comment == "" &&
+		opt.ParserName.O == "" && opt.Visibility == ast.IndexVisibilityDefault &&
+		opt.PrimaryKeyTp == timodel.PrimaryKeyTypeDefault
+}
+
+func containsColumnOption(types []ast.ColumnOptionType, tp ast.ColumnOptionType) bool {
+	for _, t := range types {
+		if t == tp {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTableOption(types []ast.TableOptionType, tp ast.TableOptionType) bool {
+	for _, t := range types {
+		if t == tp {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDatabaseOption(types []ast.DatabaseOptionType, tp ast.DatabaseOptionType) bool {
+	for _, t := range types {
+		if t == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyDDL drops the alter specs that have nothing left after rewriting,
+// and returns true if the statement does nothing at all.
+func isEmptyDDL(stmt ast.StmtNode) bool {
+	switch v := stmt.(type) {
+	case *ast.CreatePlacementPolicyStmt, *ast.AlterPlacementPolicyStmt, *ast.DropPlacementPolicyStmt:
+		return true
+	case *ast.AlterDatabaseStmt:
+		return len(v.Options) == 0
+	case *ast.AlterTableStmt:
+		specs := v.Specs[:0]
+		for _, spec := range v.Specs {
+			switch spec.Tp {
+			case ast.AlterTableOption, ast.AlterTablePartitionOptions:
+				if len(spec.Options) == 0 {
+					continue
+				}
+			}
+			specs = append(specs, spec)
+		}
+		v.Specs = specs
+		return len(v.Specs) == 0
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDDLRewriters(t *testing.T) {
+	t.Parallel()
+
+	transformer, err := NewDDLTransformer(nil, nil)
+	require.Nil(t, err)
+	require.Nil(t, transformer)
+
+	_, err = NewDDLTransformer(nil, []string{"not-exist"})
+	require.Regexp(t, ".*ErrDDLRewriterNotFound.*", err)
+
+	transformer, err = NewDDLTransformer(nil, []string{
+		RemoveAutoRandom, RemoveShardRowIDBits, RemoveClusteredIndex, RemovePlacementPolicy,
+	})
+	require.Nil(t, err)
+
+	testCases := []struct {
+		query    string
+		expected string
+		skip     bool
+	}{
+		{
+			query: "CREATE TABLE `t1` (`id` BIGINT /*T![auto_rand] AUTO_RANDOM(5) */ " +
+				"PRIMARY KEY /*T![clustered_index] CLUSTERED */,`a` INT)",
+			expected: "CREATE TABLE `t1` (`id` BIGINT PRIMARY KEY,`a` INT)",
+		},
+		{
+			query: "CREATE TABLE `t1` (`id` INT,`a` INT,PRIMARY KEY(`id`) /*T![clustered_index] NONCLUSTERED */) " +
+				"/*T! SHARD_ROW_ID_BITS = 4 */ /*T! PRE_SPLIT_REGIONS = 2 */",
+			expected: "CREATE TABLE `t1` (`id` INT,`a` INT,PRIMARY KEY(`id`))",
+		},
+		{
+			query:    "CREATE TABLE `t1` (`id` INT PRIMARY KEY) /*T![placement] PLACEMENT POLICY=`p1` */",
+			expected: "CREATE TABLE `t1` (`id` INT PRIMARY KEY)",
+		},
+		{
+			query:    "CREATE DATABASE `db1` /*T![placement] PLACEMENT POLICY = `p1` */",
+			expected: "CREATE DATABASE `db1`",
+		},
+		{
+			query: "ALTER TABLE `t1` ADD COLUMN `b` INT",
+			// The query is not changed, so it is not restored.
+			expected: "ALTER TABLE `t1` ADD COLUMN `b` INT",
+		},
+		{
+			query: "ALTER TABLE `t1` /*T![placement] PLACEMENT POLICY = `p1` */",
+			skip:  true,
+		},
+		{
+			query: "ALTER DATABASE `db1` /*T![placement] PLACEMENT POLICY = `p1` */",
+			skip:  true,
+		},
+		{
+			query: "CREATE PLACEMENT POLICY `p1` FOLLOWERS=4",
+			skip:  true,
+		},
+	}
+	for _, tc := range testCases {
+		ddl := &model.DDLEvent{
+			Query:     tc.query,
+			TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+		}
+		transformed, skip, err := transformer.Transform(ddl)
+		require.Nil(t, err)
+		require.Equal(t, tc.skip, skip, tc.query)
+		if tc.skip {
+			continue
+		}
+		require.Equal(t, tc.expected, transformed.Query)
+		// The original DDL event should not be changed.
+		require.Equal(t, tc.query, ddl.Query)
+	}
+
+	_, _, err = transformer.Transform(&model.DDLEvent{Query: "CREATE TABLE"})
+	require.Regexp(t, ".*ErrRewriteDDLFailed.*", err)
+}
+
+func TestRegisterDDLRewriter(t *testing.T) {
+	t.Parallel()
+
+	RegisterDDLRewriter("remove-comment", DDLRewriterFunc(func(stmt ast.StmtNode) bool {
+		create, ok := stmt.(*ast.CreateTableStmt)
+		if !ok {
+			return false
+		}
+		options := create.Options[:0]
+		for _, opt := range create.Options {
+			if opt.Tp != ast.TableOptionComment {
+				options = append(options, opt)
+			}
+		}
+		changed := len(options) != len(create.Options)
+		create.Options = options
+		return changed
+	}))
+	transformer, err := NewDDLTransformer(nil, []string{"remove-comment"})
+	require.Nil(t, err)
+	transformed, skip, err := transformer.Transform(&model.DDLEvent{
+		Query:     "CREATE TABLE `t1` (`id` INT PRIMARY KEY) COMMENT = 'test'",
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+	})
+	require.Nil(t, err)
+	require.False(t, skip)
+	require.Equal(t, "CREATE TABLE `t1` (`id` INT PRIMARY KEY)", transformed.Query)
+}
+
+func TestRouteDDL(t *testing.T) {
+	t.Parallel()
+
+	router, err := NewRouter(true, []*config.RouteRule{
+		{Matcher: []string{"test.t1"}, TargetTable: "t1_routed"},
+		{Matcher: []string{"test.*"}, TargetSchema: "test_routed"},
+	})
+	require.Nil(t, err)
+	transformer, err := NewDDLTransformer(router, []string{RemoveAutoRandom})
+	require.Nil(t, err)
+
+	testCases := []struct {
+		ddl      *model.DDLEvent
+		expected *model.DDLEvent
+	}{
+		{
+			ddl: &model.DDLEvent{
+				Query:     "CREATE TABLE `t1` (`id` BIGINT PRIMARY KEY /*T![auto_rand] AUTO_RANDOM(5) */)",
+				Type:      timodel.ActionCreateTable,
+				TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+			},
+			expected: &model.DDLEvent{
+				Query:     "CREATE TABLE `test`.`t1_routed` (`id` BIGINT PRIMARY KEY)",
+				Type:      timodel.ActionCreateTable,
+				TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1_routed"},
+			},
+		},
+		{
+			ddl: &model.DDLEvent{
+				Query:        "RENAME TABLE `test`.`t2` TO `test`.`t3`",
+				Type:         timodel.ActionRenameTable,
+				TableInfo:    &model.SimpleTableInfo{Schema: "test", Table: "t3"},
+				PreTableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t2"},
+			},
+			expected: &model.DDLEvent{
+				Query:        "RENAME TABLE `test_routed`.`t2` TO `test_routed`.`t3`",
+				Type:         timodel.ActionRenameTable,
+				TableInfo:    &model.SimpleTableInfo{Schema: "test_routed", Table: "t3"},
+				PreTableInfo: &model.SimpleTableInfo{Schema: "test_routed", Table: "t2"},
+			},
+		},
+		{
+			ddl: &model.DDLEvent{
+				Query:     "CREATE DATABASE `test`",
+				Type:      timodel.ActionCreateSchema,
+				TableInfo: &model.SimpleTableInfo{Schema: "test"},
+			},
+			expected: &model.DDLEvent{
+				Query:     "CREATE DATABASE `test_routed`",
+				Type:      timodel.ActionCreateSchema,
+				TableInfo: &model.SimpleTableInfo{Schema: "test_routed"},
+			},
+		},
+		{
+			ddl: &model.DDLEvent{
+				Query:     "CREATE TABLE `other`.`t1` (`id` INT PRIMARY KEY)",
+				Type:      timodel.ActionCreateTable,
+				TableInfo: &model.SimpleTableInfo{Schema: "other", Table: "t1"},
+			},
+			expected: &model.DDLEvent{
+				Query:     "CREATE TABLE `other`.`t1` (`id` INT PRIMARY KEY)",
+				Type:      timodel.ActionCreateTable,
+				TableInfo: &model.SimpleTableInfo{Schema: "other", Table: "t1"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		transformed, skip, err := transformer.Transform(tc.ddl)
+		require.Nil(t, err)
+		require.False(t, skip)
+		require.Equal(t, tc.expected, transformed)
+	}
+}
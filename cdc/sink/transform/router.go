@@ -0,0 +1,148 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"strings"
+	"sync"
+
+	filter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	schemaPlaceholder = "{schema}"
+	tablePlaceholder  = "{table}"
+)
+
+type routeRule struct {
+	filter.Filter
+	targetSchema string
+	targetTable  string
+}
+
+// Router routes the source schema and table names to the target ones
+// according to the route rules of a changefeed.
+// Router is thread-safe.
+type Router struct {
+	rules []*routeRule
+
+	// routedTables caches the routed table names, tableKey -> *tableKey,
+	// nil if the table is not routed. The cache is keyed by the names only,
+	// so the entries are reused after the table is truncated or recreated.
+	routedTables sync.Map
+}
+
+type tableKey struct {
+	schema string
+	table  string
+}
+
+// NewRouter creates a Router from the route rules.
+// It returns nil if there is no route rule at all.
+func NewRouter(caseSensitive bool, ruleConfigs []*config.RouteRule) (*Router, error) {
+	if len(ruleConfigs) == 0 {
+		return nil, nil
+	}
+	rules := make([]*routeRule, 0, len(ruleConfigs))
+	for _, ruleConfig := range ruleConfigs {
+		f, err := filter.Parse(ruleConfig.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+		}
+		if !caseSensitive {
+			f = filter.CaseInsensitive(f)
+		}
+		rules = append(rules, &routeRule{
+			Filter:       f,
+			targetSchema: ruleConfig.TargetSchema,
+			targetTable:  ruleConfig.TargetTable,
+		})
+	}
+	return &Router{rules: rules}, nil
+}
+
+// Route returns the target schema and table name of the given source table.
+// The first matched rule wins, and the names are returned unchanged
+// if no rule matches.
+// NOTICE: Set `table` to an empty string to route the whole schema, in which
+// case only the rules that match all the tables of the schema take effect.
+func (r *Router) Route(schema, table string) (string, string) {
+	if r == nil {
+		return schema, table
+	}
+	for _, rule := range r.rules {
+		if !rule.MatchTable(schema, table) {
+			continue
+		}
+		targetSchema, targetTable := schema, table
+		if rule.targetSchema != "" {
+			targetSchema = substitute(rule.targetSchema, schema, table)
+		}
+		if rule.targetTable != "" && table != "" {
+			targetTable = substitute(rule.targetTable, schema, table)
+		}
+		return targetSchema, targetTable
+	}
+	return schema, table
+}
+
+// RouteTableName returns the routed table name, or nil if the table is not
+// routed. The results are cached, so it is cheap to call it for every event.
+func (r *Router) RouteTableName(table *model.TableName) *model.TableName {
+	if r == nil {
+		return nil
+	}
+	key := tableKey{schema: table.Schema, table: table.Table}
+	cached, ok := r.routedTables.Load(key)
+	if !ok {
+		var routed *tableKey
+		schema, name := r.Route(table.Schema, table.Table)
+		if schema != table.Schema || name != table.Table {
+			routed = &tableKey{schema: schema, table: name}
+		}
+		cached, _ = r.routedTables.LoadOrStore(key, routed)
+	}
+	routed := cached.(*tableKey)
+	if routed == nil {
+		return nil
+	}
+	return &model.TableName{
+		Schema:      routed.schema,
+		Table:       routed.table,
+		TableID:     table.TableID,
+		IsPartition: table.IsPartition,
+	}
+}
+
+// RouteRowChangedEvent returns a copy of the row with the routed table name,
+// or the row itself if the table is not routed. The given row is left
+// untouched since it may be shared with other components, e.g. the redo
+// log manager.
+func (r *Router) RouteRowChangedEvent(row *model.RowChangedEvent) *model.RowChangedEvent {
+	table := r.RouteTableName(row.Table)
+	if table == nil {
+		return row
+	}
+	routed := *row
+	routed.Table = table
+	return &routed
+}
+
+func substitute(target, schema, table string) string {
+	target = strings.ReplaceAll(target, schemaPlaceholder, schema)
+	return strings.ReplaceAll(target, tablePlaceholder, table)
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	router, err := NewRouter(true, nil)
+	require.Nil(t, err)
+	require.Nil(t, router)
+	schema, table := router.Route("test", "t1")
+	require.Equal(t, "test", schema)
+	require.Equal(t, "t1", table)
+
+	router, err = NewRouter(true, []*config.RouteRule{
+		{Matcher: []string{"test.t1"}, TargetTable: "t1_routed"},
+		{Matcher: []string{"test.*"}, TargetSchema: "test_routed"},
+		{Matcher: []string{"sharding_*.t_*"}, TargetSchema: "merged", TargetTable: "{schema}_{table}"},
+		{Matcher: []string{"Upper.*"}, TargetSchema: "upper"},
+	})
+	require.Nil(t, err)
+
+	testCases := []struct {
+		schema         string
+		table          string
+		expectedSchema string
+		expectedTable  string
+	}{
+		{schema: "test", table: "t1", expectedSchema: "test", expectedTable: "t1_routed"},
+		{schema: "test", table: "t2", expectedSchema: "test_routed", expectedTable: "t2"},
+		{schema: "test", table: "", expectedSchema: "test_routed", expectedTable: ""},
+		{schema: "sharding_1", table: "t_1", expectedSchema: "merged", expectedTable: "sharding_1_t_1"},
+		// `sharding_*.t_*` does not match all the tables of the schema.
+		{schema: "sharding_1", table: "", expectedSchema: "sharding_1", expectedTable: ""},
+		{schema: "sharding_1", table: "t1", expectedSchema: "sharding_1", expectedTable: "t1"},
+		{schema: "upper", table: "t1", expectedSchema: "upper", expectedTable: "t1"},
+		{schema: "Upper", table: "t1", expectedSchema: "upper", expectedTable: "t1"},
+	}
+	for _, tc := range testCases {
+		schema, table := router.Route(tc.schema, tc.table)
+		require.Equal(t, tc.expectedSchema, schema, "%v", tc)
+		require.Equal(t, tc.expectedTable, table, "%v", tc)
+	}
+
+	router, err = NewRouter(false, []*config.RouteRule{
+		{Matcher: []string{"Upper.*"}, TargetSchema: "upper"},
+	})
+	require.Nil(t, err)
+	schema, table = router.Route("uPPer", "T1")
+	require.Equal(t, "upper", schema)
+	require.Equal(t, "T1", table)
+
+	_, err = NewRouter(true, []*config.RouteRule{
+		{Matcher: []string{"test.t1("}, TargetSchema: "test"},
+	})
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", err)
+}
+
+func TestRouteTableName(t *testing.T) {
+	router, err := NewRouter(true, []*config.RouteRule{
+		{Matcher: []string{"test.*"}, TargetSchema: "test_routed"},
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, router.RouteTableName(&model.TableName{Schema: "other", Table: "t1", TableID: 1}))
+	routed := router.RouteTableName(&model.TableName{Schema: "test", Table: "t1", TableID: 2})
+	require.Equal(t, &model.TableName{Schema: "test_routed", Table: "t1", TableID: 2}, routed)
+
+	// The table is truncated, the cached name is reused with the new table ID.
+	routed = router.RouteTableName(&model.TableName{Schema: "test", Table: "t1", TableID: 3})
+	require.Equal(t, &model.TableName{Schema: "test_routed", Table: "t1", TableID: 3}, routed)
+	count := 0
+	router.routedTables.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	require.Equal(t, 2, count)
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/transform"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"go.uber.org/zap"
+)
+
+// transformSink routes the table names of the events and rewrites the DDL
+// queries before sending them to the backend sink.
+// Note that it is a thread-safe Sink implementation if the backend sink is.
+type transformSink struct {
+	Sink
+	// filter matches the source table names if the events are routed,
+	// since the backend sink is created with a filter that accepts all
+	// the tables. It is nil if no route rule is configured.
+	filter *filter.Filter
+	// router is nil if the backend sink routes the events by itself.
+	router         *transform.Router
+	ddlTransformer *transform.DDLTransformer
+}
+
+var _ Sink = (*transformSink)(nil)
+
+// selfRoutingSink is implemented by the sinks which route the table names
+// of the events by themselves. For example, the MQ sinks match the dispatch
+// rules against the source table names and fill the topic expressions and
+// the encoded messages with the routed ones.
+type selfRoutingSink interface {
+	Sink
+	RoutesTableNames() bool
+}
+
+// newTransformSink creates the backend sink with `newSink`, and wraps it
+// with a transformSink if any route rule or DDL rewriter is configured.
+func newTransformSink(
+	ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+	sinkFilter *filter.Filter, cfg *config.ReplicaConfig, opts map[string]string,
+	errCh chan error, newSink sinkInitFunc,
+) (Sink, error) {
+	router, err := transform.NewRouter(cfg.CaseSensitive, cfg.Sink.Routes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if router == nil && len(cfg.Sink.DDLRewriters) == 0 {
+		return newSink(ctx, changefeedID, sinkURI, sinkFilter, cfg, opts, errCh)
+	}
+
+	ddlTransformer, err := transform.NewDDLTransformer(router, cfg.Sink.DDLRewriters)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	backendFilter := sinkFilter
+	if router != nil {
+		// The events have been filtered by their source table names before
+		// routing, so the backend sink must not filter them again.
+		backendCfg := cfg.Clone()
+		backendCfg.Filter = &config.FilterConfig{Rules: []string{"*.*"}}
+		backendFilter, err = filter.NewFilter(backendCfg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	backendSink, err := newSink(ctx, changefeedID, sinkURI, backendFilter, cfg, opts, errCh)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if s, ok := backendSink.(selfRoutingSink); ok && s.RoutesTableNames() {
+		router = nil
+		ddlTransformer, err = transform.NewDDLTransformer(nil, cfg.Sink.DDLRewriters)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	log.Info("sink events will be transformed",
+		zap.String("changefeed", changefeedID),
+		zap.Any("routes", cfg.Sink.Routes),
+		zap.Strings("ddlRewriters", cfg.Sink.DDLRewriters),
+		zap.Bool("routedByBackend", router == nil && len(cfg.Sink.Routes) != 0))
+	s := &transformSink{
+		Sink:           backendSink,
+		router:         router,
+		ddlTransformer: ddlTransformer,
+	}
+	if len(cfg.Sink.Routes) != 0 {
+		s.filter = sinkFilter
+	}
+	return s, nil
+}
+
+func (s *transformSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	return s.Sink.TryEmitRowChangedEvents(ctx, s.routeRows(rows)...)
+}
+
+func (s *transformSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	return s.Sink.EmitRowChangedEvents(ctx, s.routeRows(rows)...)
+}
+
+func (s *transformSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	if s.filter != nil && s.filter.ShouldIgnoreDDLEvent(
+		ddl.StartTs, ddl.Type, ddl.TableInfo.Schema, ddl.TableInfo.Table) {
+		log.Info("DDL event ignored",
+			zap.String("query", ddl.Query),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.Uint64("commitTs", ddl.CommitTs))
+		return cerror.ErrDDLEventIgnored.GenWithStackByArgs()
+	}
+	transformed, skip, err := s.ddlTransformer.Transform(ddl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if skip {
+		log.Info("DDL event skipped since nothing is left after rewriting",
+			zap.String("query", ddl.Query),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.Uint64("commitTs", ddl.CommitTs))
+		return cerror.ErrDDLEventIgnored.GenWithStackByArgs()
+	}
+	if transformed.Query != ddl.Query {
+		log.Info("DDL query is transformed",
+			zap.String("query", ddl.Query),
+			zap.String("transformedQuery", transformed.Query),
+			zap.Uint64("commitTs", ddl.CommitTs))
+	}
+	return s.Sink.EmitDDLEvent(ctx, transformed)
+}
+
+func (s *transformSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model.TableName) error {
+	if s.router == nil || len(tables) == 0 {
+		return s.Sink.EmitCheckpointTs(ctx, ts, tables)
+	}
+	routed := make([]model.TableName, 0, len(tables))
+	for i := range tables {
+		if table := s.router.RouteTableName(&tables[i]); table != nil {
+			routed = append(routed, *table)
+		} else {
+			routed = append(routed, tables[i])
+		}
+	}
+	return s.Sink.EmitCheckpointTs(ctx, ts, routed)
+}
+
+// routeRows filters the rows by their source table names and routes them.
+func (s *transformSink) routeRows(rows []*model.RowChangedEvent) []*model.RowChangedEvent {
+	if s.filter == nil || len(rows) == 0 {
+		return rows
+	}
+	routed := make([]*model.RowChangedEvent, 0, len(rows))
+	for _, row := range rows {
+		if s.filter.ShouldIgnoreDMLEvent(row.StartTs, row.Table.Schema, row.Table.Table) {
+			log.Info("Row changed event ignored", zap.Uint64("start-ts", row.StartTs))
+			continue
+		}
+		routed = append(routed, s.router.RouteRowChangedEvent(row))
+	}
+	return routed
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/stretchr/testify/require"
+)
+
+type recorderSink struct {
+	*blackHoleSink
+	filter *filter.Filter
+	rows   []*model.RowChangedEvent
+	ddls   []*model.DDLEvent
+	tables []model.TableName
+}
+
+func (r *recorderSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	for _, row := range rows {
+		if !r.filter.ShouldIgnoreDMLEvent(row.StartTs, row.Table.Schema, row.Table.Table) {
+			r.rows = append(r.rows, row)
+		}
+	}
+	return nil
+}
+
+func (r *recorderSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	r.ddls = append(r.ddls, ddl)
+	return nil
+}
+
+func (r *recorderSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model.TableName) error {
+	r.tables = tables
+	return nil
+}
+
+func newRecorderSinkForTest(
+	t *testing.T, cfg *config.ReplicaConfig,
+) (Sink, *recorderSink) {
+	ctx := context.Background()
+	f, err := filter.NewFilter(cfg)
+	require.Nil(t, err)
+	recorder := &recorderSink{blackHoleSink: newBlackHoleSink(ctx)}
+	s, err := newTransformSink(ctx, "test", &url.URL{}, f, cfg, map[string]string{}, nil,
+		func(
+			ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+			filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
+			errCh chan error,
+		) (Sink, error) {
+			recorder.filter = filter
+			return recorder, nil
+		})
+	require.Nil(t, err)
+	return s, recorder
+}
+
+func TestTransformSinkNotEnabled(t *testing.T) {
+	t.Parallel()
+
+	s, recorder := newRecorderSinkForTest(t, config.GetDefaultReplicaConfig())
+	require.Equal(t, recorder, s)
+}
+
+func TestTransformSinkRoute(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = []string{"test.*"}
+	cfg.Sink.Routes = []*config.RouteRule{
+		{Matcher: []string{"test.t1"}, TargetSchema: "routed", TargetTable: "{table}_routed"},
+	}
+	s, recorder := newRecorderSinkForTest(t, cfg)
+	require.IsType(t, &transformSink{}, s)
+
+	t1 := &model.TableName{Schema: "test", Table: "t1", TableID: 1}
+	t2 := &model.TableName{Schema: "test", Table: "t2", TableID: 2}
+	rows := []*model.RowChangedEvent{
+		{CommitTs: 1, Table: t1},
+		{CommitTs: 1, Table: t2},
+		{CommitTs: 1, Table: &model.TableName{Schema: "other", Table: "t1", TableID: 3}},
+		{CommitTs: 2, Table: t1},
+	}
+	require.Nil(t, s.EmitRowChangedEvents(ctx, rows...))
+	require.Len(t, recorder.rows, 3)
+	routed := &model.TableName{Schema: "routed", Table: "t1_routed", TableID: 1}
+	require.Equal(t, routed, recorder.rows[0].Table)
+	require.Equal(t, t2, recorder.rows[1].Table)
+	require.Equal(t, routed, recorder.rows[2].Table)
+	require.Equal(t, uint64(2), recorder.rows[2].CommitTs)
+	// The emitted rows should not be changed.
+	require.Equal(t, t1, rows[0].Table)
+	require.Equal(t, "test", t1.Schema)
+
+	require.Nil(t, s.EmitCheckpointTs(ctx, 1, []model.TableName{*t1, *t2}))
+	require.Equal(t, []model.TableName{*routed, *t2}, recorder.tables)
+
+	err := s.EmitDDLEvent(ctx, &model.DDLEvent{
+		Query:     "CREATE TABLE `other`.`t1` (`id` INT PRIMARY KEY)",
+		Type:      timodel.ActionCreateTable,
+		TableInfo: &model.SimpleTableInfo{Schema: "other", Table: "t1"},
+	})
+	require.True(t, cerror.ErrDDLEventIgnored.Equal(err))
+	require.Nil(t, s.EmitDDLEvent(ctx, &model.DDLEvent{
+		Query:     "ALTER TABLE `t1` ADD COLUMN `a` INT",
+		Type:      timodel.ActionAddColumn,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+	}))
+	require.Len(t, recorder.ddls, 1)
+	require.Equal(t, "ALTER TABLE `routed`.`t1_routed` ADD COLUMN `a` INT", recorder.ddls[0].Query)
+	require.Equal(t, "routed", recorder.ddls[0].TableInfo.Schema)
+	require.Equal(t, "t1_routed", recorder.ddls[0].TableInfo.Table)
+}
+
+func TestTransformSinkRewriteDDL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.DDLRewriters = []string{"remove-shard-row-id-bits"}
+	s, recorder := newRecorderSinkForTest(t, cfg)
+
+	require.Nil(t, s.EmitDDLEvent(ctx, &model.DDLEvent{
+		Query:     "CREATE TABLE `t1` (`id` INT) /*T! SHARD_ROW_ID_BITS = 4 */",
+		Type:      timodel.ActionCreateTable,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+	}))
+	err := s.EmitDDLEvent(ctx, &model.DDLEvent{
+		Query:     "ALTER TABLE `t1` /*T! SHARD_ROW_ID_BITS = 4 */",
+		Type:      timodel.ActionShardRowID,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+	})
+	require.True(t, cerror.ErrDDLEventIgnored.Equal(err))
+	require.Len(t, recorder.ddls, 1)
+	require.Equal(t, "CREATE TABLE `t1` (`id` INT)", recorder.ddls[0].Query)
+
+	cfg.Sink.DDLRewriters = []string{"not-exist"}
+	_, err = newTransformSink(ctx, "test", &url.URL{}, nil, cfg, map[string]string{}, nil, nil)
+	require.Regexp(t, ".*ErrDDLRewriterNotFound.*", err)
+}
+
+type selfRoutingRecorderSink struct {
+	*recorderSink
+}
+
+func (s *selfRoutingRecorderSink) RoutesTableNames() bool {
+	return true
+}
+
+func TestTransformSinkSelfRoutingBackend(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = []string{"test.*"}
+	cfg.Sink.Routes = []*config.RouteRule{
+		{Matcher: []string{"test.t1"}, TargetSchema: "routed"},
+	}
+	f, err := filter.NewFilter(cfg)
+	require.Nil(t, err)
+	recorder := &recorderSink{blackHoleSink: newBlackHoleSink(ctx)}
+	s, err := newTransformSink(ctx, "test", &url.URL{}, f, cfg, map[string]string{}, nil,
+		func(
+			ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+			filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
+			errCh chan error,
+		) (Sink, error) {
+			recorder.filter = filter
+			return &selfRoutingRecorderSink{recorderSink: recorder}, nil
+		})
+	require.Nil(t, err)
+
+	// The events are filtered by their source table names,
+	// but the table names are left to the backend sink.
+	t1 := &model.TableName{Schema: "test", Table: "t1", TableID: 1}
+	require.Nil(t, s.EmitRowChangedEvents(ctx,
+		&model.RowChangedEvent{CommitTs: 1, Table: t1},
+		&model.RowChangedEvent{CommitTs: 1, Table: &model.TableName{Schema: "other", Table: "t1"}},
+	))
+	require.Len(t, recorder.rows, 1)
+	require.Equal(t, t1, recorder.rows[0].Table)
+
+	require.Nil(t, s.EmitCheckpointTs(ctx, 1, []model.TableName{*t1}))
+	require.Equal(t, []model.TableName{*t1}, recorder.tables)
+
+	require.Nil(t, s.EmitDDLEvent(ctx, &model.DDLEvent{
+		Query:     "ALTER TABLE `t1` ADD COLUMN `a` INT",
+		Type:      timodel.ActionAddColumn,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+	}))
+	require.Len(t, recorder.ddls, 1)
+	require.Equal(t, "ALTER TABLE `t1` ADD COLUMN `a` INT", recorder.ddls[0].Query)
+}
@@ -0,0 +1,195 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Codec is the compression algorithm of sorter data.
+type Codec byte
+
+// Codecs, the value is written to disk, do not change them.
+const (
+	CodecNone   Codec = 0
+	CodecSnappy Codec = 1
+	CodecZstd   Codec = 2
+)
+
+// ParseCodec returns the codec of the given config value.
+func ParseCodec(s string) (Codec, error) {
+	switch s {
+	case "", config.SorterCompressionNone:
+		return CodecNone, nil
+	case config.SorterCompressionSnappy:
+		return CodecSnappy, nil
+	case config.SorterCompressionZstd:
+		return CodecZstd, nil
+	default:
+		return CodecNone, cerror.ErrIllegalSorterParameter.GenWithStackByArgs(
+			"unknown compression " + s)
+	}
+}
+
+// String implements fmt.Stringer.
+func (c Codec) String() string {
+	switch c {
+	case CodecNone:
+		return config.SorterCompressionNone
+	case CodecSnappy:
+		return config.SorterCompressionSnappy
+	case CodecZstd:
+		return config.SorterCompressionZstd
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+func initZstd() {
+	zstdOnce.Do(func() {
+		var err error
+		// Sorter data is short-lived, trade ratio for speed.
+		zstdEncoder, err = zstd.NewWriter(nil,
+			zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			panic(err)
+		}
+		zstdDecoder, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+		if err != nil {
+			panic(err)
+		}
+	})
+}
+
+// Compressor compresses sorter data and records the compression ratio.
+// A compressed block starts with the codec, so it can be decompressed
+// without knowing the config it was written with.
+type Compressor struct {
+	codec Codec
+
+	uncompressedBytes prometheus.Counter
+	compressedBytes   prometheus.Counter
+}
+
+// NewCompressor returns a compressor, it returns nil if compression is
+// disabled.
+func NewCompressor(codec Codec, changefeedID string) *Compressor {
+	if codec == CodecNone {
+		return nil
+	}
+	if codec == CodecZstd {
+		initZstd()
+	}
+	return &Compressor{
+		codec: codec,
+		uncompressedBytes: compressionBytes.WithLabelValues(
+			changefeedID, codec.String(), "uncompressed"),
+		compressedBytes: compressionBytes.WithLabelValues(
+			changefeedID, codec.String(), "compressed"),
+	}
+}
+
+// Codec returns the codec of the compressor.
+func (c *Compressor) Codec() Codec {
+	return c.codec
+}
+
+// Compress appends the compressed src to dst.
+func (c *Compressor) Compress(dst, src []byte) []byte {
+	start := len(dst)
+	dst = append(dst, byte(c.codec))
+	switch c.codec {
+	case CodecSnappy:
+		buf := snappy.Encode(nil, src)
+		dst = append(dst, buf...)
+	case CodecZstd:
+		dst = zstdEncoder.EncodeAll(src, dst)
+	default:
+		dst = append(dst, src...)
+	}
+	c.uncompressedBytes.Add(float64(len(src)))
+	c.compressedBytes.Add(float64(len(dst) - start))
+	return dst
+}
+
+// Decompress appends the decompressed src to dst. src must be
+// returned by Compressor.Compress.
+func Decompress(dst, src []byte) ([]byte, error) {
+	if len(src) == 0 {
+		return nil, cerror.ErrSorterDecompressFailed.GenWithStackByArgs("empty")
+	}
+	codec := Codec(src[0])
+	src = src[1:]
+	switch codec {
+	case CodecNone:
+		return append(dst, src...), nil
+	case CodecSnappy:
+		buf, err := snappy.Decode(nil, src)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrSorterDecompressFailed, err, codec)
+		}
+		return append(dst, buf...), nil
+	case CodecZstd:
+		initZstd()
+		out, err := zstdDecoder.DecodeAll(src, dst)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrSorterDecompressFailed, err, codec)
+		}
+		return out, nil
+	default:
+		return nil, cerror.ErrSorterDecompressFailed.GenWithStackByArgs(codec)
+	}
+}
+
+// CompressedSerde compresses bytes encoded by the wrapped SerializerDeserializer.
+type CompressedSerde struct {
+	SerializerDeserializer
+	Compressor *Compressor
+}
+
+// Marshal encodes model.PolymorphicEvent into compressed bytes.
+func (c *CompressedSerde) Marshal(event *model.PolymorphicEvent, bytes []byte) ([]byte, error) {
+	raw, err := c.SerializerDeserializer.Marshal(event, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c.Compressor.Compress(bytes[:0], raw), nil
+}
+
+// Unmarshal decodes model.PolymorphicEvent from compressed bytes.
+func (c *CompressedSerde) Unmarshal(event *model.PolymorphicEvent, bytes []byte) ([]byte, error) {
+	raw, err := Decompress(nil, bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	_, err = c.SerializerDeserializer.Unmarshal(event, raw)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCodec(t *testing.T) {
+	t.Parallel()
+
+	for _, cs := range []struct {
+		compression string
+		codec       Codec
+	}{
+		{"", CodecNone},
+		{"none", CodecNone},
+		{"snappy", CodecSnappy},
+		{"zstd", CodecZstd},
+	} {
+		codec, err := ParseCodec(cs.compression)
+		require.Nil(t, err)
+		require.Equal(t, cs.codec, codec)
+	}
+	_, err := ParseCodec("lz4")
+	require.True(t, cerror.ErrIllegalSorterParameter.Equal(err))
+}
+
+func TestCompressor(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewCompressor(CodecNone, "test-compressor"))
+
+	src := bytes.Repeat([]byte("compressible"), 128)
+	for _, codec := range []Codec{CodecSnappy, CodecZstd} {
+		c := NewCompressor(codec, "test-compressor")
+		require.Equal(t, codec, c.Codec())
+
+		compressed := c.Compress([]byte("prefix"), src)
+		require.Equal(t, []byte("prefix"), compressed[:6])
+		require.Less(t, len(compressed), len(src)/4)
+		require.EqualValues(t, len(src), testutil.ToFloat64(c.uncompressedBytes))
+		require.EqualValues(t, len(compressed)-6, testutil.ToFloat64(c.compressedBytes))
+
+		decompressed, err := Decompress(nil, compressed[6:])
+		require.Nil(t, err)
+		require.Equal(t, src, decompressed)
+
+		// Corrupted data.
+		_, err = Decompress(nil, compressed[6:len(compressed)-4])
+		require.Regexp(t, ".*ErrSorterDecompressFailed.*", err)
+	}
+
+	_, err := Decompress(nil, []byte{})
+	require.True(t, cerror.ErrSorterDecompressFailed.Equal(err))
+	_, err = Decompress(nil, []byte{0xff, 1, 2})
+	require.True(t, cerror.ErrSorterDecompressFailed.Equal(err))
+}
+
+func TestDeleteMetrics(t *testing.T) {
+	t.Parallel()
+
+	for _, codec := range []Codec{CodecSnappy, CodecZstd} {
+		NewCompressor(codec, "test-delete-metrics").Compress(nil, []byte("data"))
+	}
+	DeleteMetrics("test-delete-metrics")
+	for _, codec := range []Codec{CodecSnappy, CodecZstd} {
+		// The label values are deleted already.
+		require.False(t, compressionBytes.DeleteLabelValues(
+			"test-delete-metrics", codec.String(), "uncompressed"))
+		require.False(t, compressionBytes.DeleteLabelValues(
+			"test-delete-metrics", codec.String(), "compressed"))
+	}
+}
+
+func TestCompressedSerde(t *testing.T) {
+	t.Parallel()
+
+	serde := &CompressedSerde{
+		SerializerDeserializer: &MsgPackGenSerde{},
+		Compressor:             NewCompressor(CodecZstd, "test-compressed-serde"),
+	}
+	event := model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte("key"),
+		Value:   bytes.Repeat([]byte("value"), 128),
+		StartTs: 1,
+		CRTs:    2,
+	})
+	value, err := serde.Marshal(event, []byte{})
+	require.Nil(t, err)
+	require.Less(t, len(value), len(event.RawKV.Value))
+
+	decoded := new(model.PolymorphicEvent)
+	_, err = serde.Unmarshal(decoded, value)
+	require.Nil(t, err)
+	require.Equal(t, event.RawKV, decoded.RawKV)
+	require.EqualValues(t, 1, decoded.StartTs)
+	require.EqualValues(t, 2, decoded.CRTs)
+}
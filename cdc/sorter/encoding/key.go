@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	"go.uber.org/zap"
 )
 
@@ -56,11 +57,30 @@ func EncodeTsKey(uniqueID uint32, tableID uint64, ts uint64) []byte {
 // EncodeKey encodes a key according to event.
 // Format: uniqueID, tableID, CRTs, startTs, Put/Delete, Key.
 func EncodeKey(uniqueID uint32, tableID uint64, event *model.PolymorphicEvent) []byte {
+	buf := encodeKeyPrefix(uniqueID, tableID, event, len(event.RawKV.Key))
+	// key
+	return append(buf, event.RawKV.Key...)
+}
+
+// EncodeHashedKey encodes a key like EncodeKey, except that the key of the
+// event is replaced by its keyed hash, so that row data is not stored in
+// plaintext.
+// Format: uniqueID, tableID, CRTs, startTs, Put/Delete, Hash(Key).
+func EncodeHashedKey(
+	uniqueID uint32, tableID uint64, event *model.PolymorphicEvent, cipher *encrypt.Cipher,
+) []byte {
+	buf := encodeKeyPrefix(uniqueID, tableID, event, encrypt.HashSize)
+	return cipher.Hash(buf, event.RawKV.Key)
+}
+
+func encodeKeyPrefix(
+	uniqueID uint32, tableID uint64, event *model.PolymorphicEvent, keyLen int,
+) []byte {
 	if event.RawKV == nil {
 		log.Panic("rawkv must not be nil", zap.Any("event", event))
 	}
 	// uniqueID, tableID, CRTs, startTs, Put/Delete, Key
-	length := 4 + 8 + 8 + 8 + 2 + len(event.RawKV.Key)
+	length := 4 + 8 + 8 + 8 + 2 + keyLen
 	buf := make([]byte, 0, length)
 	uint64Buf := [8]byte{}
 	// uniqueID
@@ -77,7 +97,5 @@ func EncodeKey(uniqueID uint32, tableID uint64, event *model.PolymorphicEvent) [
 	buf = append(buf, uint64Buf[:]...)
 	// Let Delete < Put
 	binary.BigEndian.PutUint16(uint64Buf[:], ^uint16(event.RawKV.OpType))
-	buf = append(buf, uint64Buf[:2]...)
-	// key
-	return append(buf, event.RawKV.Key...)
+	return append(buf, uint64Buf[:2]...)
 }
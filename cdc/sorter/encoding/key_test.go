@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	"github.com/stretchr/testify/require"
 )
 
@@ -203,3 +204,38 @@ func TestDecodeKey(t *testing.T) {
 	require.EqualValues(t, 0, startTs)
 	require.EqualValues(t, 3, CRTs)
 }
+
+func TestEncodeHashedKey(t *testing.T) {
+	t.Parallel()
+
+	cipher, err := encrypt.NewCipher(bytes.Repeat([]byte{1}, 16))
+	require.Nil(t, err)
+	put := model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte("sensitive-key"),
+		StartTs: 1,
+		CRTs:    2,
+	})
+	del := model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypeDelete,
+		Key:     []byte("sensitive-key"),
+		StartTs: 1,
+		CRTs:    2,
+	})
+
+	key := EncodeHashedKey(1, 2, put, cipher)
+	require.Equal(t, len(key), cap(key))
+	require.False(t, bytes.Contains(key, put.RawKV.Key))
+	// The prefix is the same as plaintext keys.
+	plainKey := EncodeKey(1, 2, put)
+	require.Equal(t, plainKey[:len(plainKey)-len(put.RawKV.Key)], key[:len(key)-encrypt.HashSize])
+	require.Equal(t, key, EncodeHashedKey(1, 2, put, cipher))
+	// Delete < Put is kept.
+	require.Equal(t, -1, bytes.Compare(EncodeHashedKey(1, 2, del, cipher), key))
+
+	uniqueID, tableID, startTs, CRTs := DecodeKey(key)
+	require.EqualValues(t, 1, uniqueID)
+	require.EqualValues(t, 2, tableID)
+	require.EqualValues(t, 1, startTs)
+	require.EqualValues(t, 2, CRTs)
+}
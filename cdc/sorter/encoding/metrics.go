@@ -0,0 +1,39 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var compressionBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "ticdc",
+	Subsystem: "sorter",
+	Name:      "compression_bytes_total",
+	Help:      "The number of bytes before and after compressed by the sorter",
+}, []string{"changefeed", "codec", "type"})
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(compressionBytes)
+}
+
+// DeleteMetrics deletes the compression metrics of the changefeed,
+// it must be called after all the sorters of the changefeed are closed.
+func DeleteMetrics(changefeedID string) {
+	for _, codec := range []Codec{CodecSnappy, CodecZstd} {
+		compressionBytes.DeleteLabelValues(changefeedID, codec.String(), "uncompressed")
+		compressionBytes.DeleteLabelValues(changefeedID, codec.String(), "compressed")
+	}
+}
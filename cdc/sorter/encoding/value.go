@@ -16,6 +16,7 @@ package encoding
 import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 )
 
 // SerializerDeserializer is the interface encodes and decodes model.PolymorphicEvent.
@@ -50,3 +51,31 @@ func (m *MsgPackGenSerde) Unmarshal(event *model.PolymorphicEvent, bytes []byte)
 
 	return bytes, nil
 }
+
+// EncryptedSerde encrypts bytes encoded by the wrapped SerializerDeserializer.
+type EncryptedSerde struct {
+	SerializerDeserializer
+	Cipher *encrypt.Cipher
+}
+
+// Marshal encodes model.PolymorphicEvent into encrypted bytes.
+func (e *EncryptedSerde) Marshal(event *model.PolymorphicEvent, bytes []byte) ([]byte, error) {
+	plaintext, err := e.SerializerDeserializer.Marshal(event, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return e.Cipher.Seal(bytes[:0], plaintext), nil
+}
+
+// Unmarshal decodes model.PolymorphicEvent from encrypted bytes.
+func (e *EncryptedSerde) Unmarshal(event *model.PolymorphicEvent, bytes []byte) ([]byte, error) {
+	plaintext, err := e.Cipher.Open(nil, bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	_, err = e.SerializerDeserializer.Unmarshal(event, plaintext)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return nil, nil
+}
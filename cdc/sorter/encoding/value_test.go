@@ -0,0 +1,56 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/encrypt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedSerde(t *testing.T) {
+	t.Parallel()
+
+	cipher, err := encrypt.NewCipher(bytes.Repeat([]byte{1}, 16))
+	require.Nil(t, err)
+	serde := &EncryptedSerde{SerializerDeserializer: &MsgPackGenSerde{}, Cipher: cipher}
+
+	event := model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte("sensitive-key"),
+		Value:   []byte("sensitive-value"),
+		StartTs: 1,
+		CRTs:    2,
+	})
+	value, err := serde.Marshal(event, []byte{})
+	require.Nil(t, err)
+	require.False(t, bytes.Contains(value, event.RawKV.Key))
+	require.False(t, bytes.Contains(value, event.RawKV.Value))
+
+	decoded := new(model.PolymorphicEvent)
+	_, err = serde.Unmarshal(decoded, value)
+	require.Nil(t, err)
+	require.Equal(t, event.RawKV, decoded.RawKV)
+	require.EqualValues(t, 1, decoded.StartTs)
+	require.EqualValues(t, 2, decoded.CRTs)
+
+	// Plaintext values can not be decoded.
+	value, err = (&MsgPackGenSerde{}).Marshal(event, []byte{})
+	require.Nil(t, err)
+	_, err = serde.Unmarshal(decoded, value)
+	require.Error(t, err)
+}
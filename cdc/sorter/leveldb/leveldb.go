@@ -89,8 +89,9 @@ type DBActor struct {
 	stopped  bool
 	closedWg *sync.WaitGroup
 
-	metricWriteDuration prometheus.Observer
-	metricWriteBytes    prometheus.Observer
+	metricWriteDuration       prometheus.Observer
+	metricWriteBytes          prometheus.Observer
+	metricDeleteRangeDuration prometheus.Observer
 }
 
 var _ actor.Actor[message.Task] = (*DBActor)(nil)
@@ -129,8 +130,9 @@ func NewDBActor(
 
 		closedWg: wg,
 
-		metricWriteDuration: sorterWriteDurationHistogram.WithLabelValues(idTag),
-		metricWriteBytes:    sorterWriteBytesHistogram.WithLabelValues(idTag),
+		metricWriteDuration:       sorterWriteDurationHistogram.WithLabelValues(idTag),
+		metricWriteBytes:          sorterWriteBytesHistogram.WithLabelValues(idTag),
+		metricDeleteRangeDuration: sorterDeleteRangeDurationHistogram.WithLabelValues(idTag),
 	}, mb, nil
 }
 
@@ -230,10 +232,12 @@ func (ldb *DBActor) Poll(ctx context.Context, tasks []actormsg.Message[message.T
 						zap.Error(err), zap.Uint64("tableID", task.TableID))
 				}
 				start, end := task.DeleteReq.Range[0], task.DeleteReq.Range[1]
+				startTime := time.Now()
 				if err := ldb.db.DeleteRange(start, end); err != nil {
 					log.Panic("db error",
 						zap.Error(err), zap.Uint64("tableID", task.TableID))
 				}
+				ldb.metricDeleteRangeDuration.Observe(time.Since(startTime).Seconds())
 				ldb.tryScheduleCompact()
 			}
 		}
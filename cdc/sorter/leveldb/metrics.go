@@ -42,6 +42,14 @@ var (
 		Buckets:   prometheus.ExponentialBuckets(0.004, 2.0, 20),
 	}, []string{"id"})
 
+	sorterDeleteRangeDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "db_delete_range_duration_seconds",
+		Help:      "Bucketed histogram of sorter delete range duration",
+		Buckets:   prometheus.ExponentialBuckets(0.004, 2.0, 20),
+	}, []string{"id"})
+
 	sorterIterReadDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "ticdc",
 		Subsystem: "sorter",
@@ -57,4 +65,5 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(sorterCompactDurationHistogram)
 	registry.MustRegister(sorterWriteBytesHistogram)
 	registry.MustRegister(sorterIterReadDurationHistogram)
+	registry.MustRegister(sorterDeleteRangeDurationHistogram)
 }
@@ -115,7 +115,7 @@ func (r *reader) outputBufferedResolvedEvents(buffer *outputBuffer) {
 		lastCommitTs = event.CRTs
 
 		// Delete sent events.
-		key := encoding.EncodeKey(r.uid, r.tableID, event)
+		key := r.encodeKey(event)
 		buffer.appendDeleteKey(message.Key(key))
 		remainIdx = idx + 1
 	}
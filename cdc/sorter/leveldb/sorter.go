@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/actor"
 	actormsg "github.com/pingcap/tiflow/pkg/actor/message"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
@@ -51,13 +52,24 @@ type common struct {
 	dbActorID actor.ID
 	dbRouter  *actor.Router[message.Task]
 
-	uid      uint32
-	tableID  uint64
-	serde    *encoding.MsgPackGenSerde
+	uid     uint32
+	tableID uint64
+	serde   encoding.SerializerDeserializer
+	// cipher is not nil if spill-to-disk encryption is enabled.
+	cipher   *encrypt.Cipher
 	errCh    chan error
 	closedWg *sync.WaitGroup
 }
 
+// encodeKey encodes the key of an event, the key of raw kv is hashed if
+// encryption is enabled.
+func (c *common) encodeKey(event *model.PolymorphicEvent) []byte {
+	if c.cipher != nil {
+		return encoding.EncodeHashedKey(c.uid, c.tableID, event, c.cipher)
+	}
+	return encoding.EncodeKey(c.uid, c.tableID, event)
+}
+
 // reportError notifies Sorter to return an error and close.
 func (c *common) reportError(msg string, err error) {
 	if errors.Cause(err) != context.Canceled {
@@ -92,9 +104,10 @@ func NewSorter(
 	dbRouter *actor.Router[message.Task], dbActorID actor.ID,
 	writerSystem *actor.System[message.Task], writerRouter *actor.Router[message.Task],
 	readerSystem *actor.System[message.Task], readerRouter *actor.Router[message.Task],
-	compact *CompactScheduler, cfg *config.DBConfig,
+	compact *CompactScheduler, cfg *config.DBConfig, codec encoding.Codec,
 ) (*Sorter, error) {
 	changefeedID := util.ChangefeedIDFromCtx(ctx)
+	cfPriority := util.ChangefeedPriorityFromCtx(ctx)
 	metricIterDuration := sorterIterReadDurationHistogram.MustCurryWith(
 		prometheus.Labels{"id": changefeedID})
 	metricTotalEventsKV := sorter.EventCount.WithLabelValues(changefeedID, "kv")
@@ -103,12 +116,22 @@ func NewSorter(
 	// TODO: test capture the same table multiple times.
 	uid := allocID()
 	actorID := actor.ID(uid)
+	var serde encoding.SerializerDeserializer = &encoding.MsgPackGenSerde{}
+	// Values are compressed before encrypted, ciphertext is incompressible.
+	if compressor := encoding.NewCompressor(codec, changefeedID); compressor != nil {
+		serde = &encoding.CompressedSerde{SerializerDeserializer: serde, Compressor: compressor}
+	}
+	cipher := encrypt.GetGlobalCipher()
+	if cipher != nil {
+		serde = &encoding.EncryptedSerde{SerializerDeserializer: serde, Cipher: cipher}
+	}
 	c := common{
 		dbActorID: dbActorID,
 		dbRouter:  dbRouter,
 		uid:       uid,
 		tableID:   uint64(tableID),
-		serde:     &encoding.MsgPackGenSerde{},
+		serde:     serde,
+		cipher:    cipher,
 		errCh:     make(chan error, 1),
 		closedWg:  &sync.WaitGroup{},
 	}
@@ -122,7 +145,7 @@ func NewSorter(
 		metricTotalEventsResolvedTs: metricTotalEventsResolvedTs,
 	}
 	wmb := actor.NewMailbox[message.Task](actorID, sorterInputCap)
-	err := writerSystem.Spawn(wmb, w)
+	err := writerSystem.SpawnWithPriority(wmb, w, cfPriority)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -163,7 +186,7 @@ func NewSorter(
 		metricIterNextDuration: metricIterDuration.WithLabelValues("next"),
 	}
 	rmb := actor.NewMailbox[message.Task](actorID, sorterInputCap)
-	err = readerSystem.Spawn(rmb, r)
+	err = readerSystem.SpawnWithPriority(rmb, r, cfPriority)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
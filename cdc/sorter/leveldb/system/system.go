@@ -47,6 +47,7 @@ const (
 // System manages db sorter resource.
 type System struct {
 	dbs           []db.DB
+	cache         *db.BlockCache
 	dbSystem      *actor.System[message.Task]
 	DBRouter      *actor.Router[message.Task]
 	WriterSystem  *actor.System[message.Task]
@@ -135,10 +136,16 @@ func (s *System) Start(ctx context.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	memInBytePerDB := float64(totalMemory) * s.memPercentage / float64(s.cfg.Count)
+	// All dbs share one block cache, so that hot tables can use more memory
+	// than cold tables.
+	s.cache = db.NewBlockCache(int64(float64(totalMemory) * s.memPercentage))
+	log.Info("start db sorter system",
+		zap.String("backend", s.cfg.Backend),
+		zap.String("dir", s.dir),
+		zap.Int("count", s.cfg.Count))
 	for id := 0; id < s.cfg.Count; id++ {
 		// Open db.
-		db, err := db.OpenPebble(ctx, id, s.dir, int(memInBytePerDB), s.cfg)
+		db, err := db.Open(ctx, id, s.dir, s.cache, s.cfg)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -212,6 +219,9 @@ func (s *System) Stop() error {
 			log.Warn("db close error", zap.Int("ID", i), zap.Error(err))
 		}
 	}
+	if s.cache != nil {
+		s.cache.Release()
+	}
 	// Wait actors and metrics goroutine.
 	s.closedWg.Wait()
 	return nil
@@ -18,6 +18,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb"
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb/message"
 	"github.com/pingcap/tiflow/pkg/actor"
@@ -29,17 +30,20 @@ import (
 func TestSystemStartStop(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
-	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
-	cfg.Count = 1
-
-	sys := NewSystem(t.TempDir(), 1, cfg)
-	require.Nil(t, sys.Start(ctx))
-	require.Nil(t, sys.Stop())
-
-	// Close it again.
-	require.Nil(t, sys.Stop())
-	// Start a closed system.
-	require.Error(t, sys.Start(ctx))
+	for _, backend := range []string{config.DBBackendPebble, config.DBBackendLevelDB} {
+		cfg := config.GetDefaultServerConfig().Clone().Debug.DB
+		cfg.Count = 1
+		cfg.Backend = backend
+
+		sys := NewSystem(t.TempDir(), 1, cfg)
+		require.Nil(t, sys.Start(ctx))
+		require.Nil(t, sys.Stop())
+
+		// Close it again.
+		require.Nil(t, sys.Stop())
+		// Start a closed system.
+		require.Error(t, sys.Start(ctx))
+	}
 }
 
 func TestSystemStopUnstarted(t *testing.T) {
@@ -130,7 +134,7 @@ func TestSystemStopWithManyTablesAndFewStragglers(t *testing.T) {
 			ctx, int64(i), i, sys.DBRouter, dbActorID,
 			sys.WriterSystem, sys.WriterRouter,
 			sys.ReaderSystem, sys.ReaderRouter,
-			sys.CompactScheduler(), cfg)
+			sys.CompactScheduler(), cfg, encoding.CodecNone)
 		require.Nil(t, err)
 		ss = append(ss, s)
 		sctx, scancel := context.WithCancel(ctx)
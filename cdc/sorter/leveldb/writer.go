@@ -18,7 +18,6 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
-	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb/message"
 	"github.com/pingcap/tiflow/pkg/actor"
 	actormsg "github.com/pingcap/tiflow/pkg/actor/message"
@@ -69,7 +68,7 @@ func (w *writer) Poll(ctx context.Context, msgs []actormsg.Message[message.Task]
 		}
 		kvEventCount++
 
-		key := encoding.EncodeKey(w.uid, w.tableID, ev)
+		key := w.encodeKey(ev)
 		value := []byte{}
 		var err error
 		value, err = w.serde.Marshal(ev, value)
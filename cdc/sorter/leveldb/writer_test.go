@@ -78,7 +78,7 @@ func TestWriterPoll(t *testing.T) {
 	dbID := actor.ID(2)
 	dbMB := actor.NewMailbox[message.Task](dbID, capacity)
 	router.InsertMailbox4Test(dbID, dbMB)
-	c := common{dbActorID: dbID, dbRouter: router}
+	c := common{dbActorID: dbID, dbRouter: router, serde: &encoding.MsgPackGenSerde{}}
 	writer := newTestWriter(c, router, readerID)
 
 	// We need to poll twice to read resolved events, so we need a slice of
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/fsutil"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
@@ -164,10 +165,15 @@ func newBackEndPool(dir string) (*backEndPool, error) {
 
 func (p *backEndPool) alloc(ctx context.Context) (backEnd, error) {
 	sorterConfig := config.GetGlobalServerConfig().Sorter
+	// The sorter spills to disk if the memory governor of the capture
+	// is running out of memory.
+	quota := memquota.QuotaFromCtx(ctx, memquota.ComponentSorter)
 	if p.sorterMemoryUsage() < int64(sorterConfig.MaxMemoryConsumption) &&
-		p.memoryPressure() < int32(sorterConfig.MaxMemoryPercentage) {
+		p.memoryPressure() < int32(sorterConfig.MaxMemoryPercentage) &&
+		!quota.ShouldSpill() {
 
 		ret := newMemoryBackEnd()
+		ret.quota = quota
 		return ret, nil
 	}
 
@@ -182,7 +188,9 @@ func (p *backEndPool) alloc(ctx context.Context) (backEnd, error) {
 		ptr := &p.cache[i]
 		ret := atomic.SwapPointer(ptr, nil)
 		if ret != nil {
-			return (*fileBackEnd)(ret), nil
+			backEnd := (*fileBackEnd)(ret)
+			backEnd.compressor = compressorFromCtx(ctx)
+			return backEnd, nil
 		}
 	}
 
@@ -201,6 +209,7 @@ func (p *backEndPool) alloc(ctx context.Context) (backEnd, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	ret.compressor = compressorFromCtx(ctx)
 
 	return ret, nil
 }
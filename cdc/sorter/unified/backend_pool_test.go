@@ -276,6 +276,22 @@ func TestCleanUpStaleBasic(t *testing.T) {
 	mockP.assertFilesNotExist(t)
 }
 
+// TestCleanUpStaleFiles verifies that stale temporary files can be removed
+// without starting a backendPool, and files locked by others are kept.
+func TestCleanUpStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	prefix := dir + "/sort-1-"
+
+	mockP := newMockOtherProcess(t, dir, prefix)
+	mockP.writeMockFiles(t, 100)
+	require.Error(t, CleanUpStaleFiles(dir))
+	mockP.assertFilesExist(t)
+
+	mockP.unlock(t)
+	require.Nil(t, CleanUpStaleFiles(dir))
+	mockP.assertFilesNotExist(t)
+}
+
 // TestFileLockConflict tests that if two backEndPools were to use the same sort-dir,
 // and error would be returned by one of them.
 func TestFileLockConflict(t *testing.T) {
@@ -25,15 +25,23 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter/encoding"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 )
 
+// A file starts with fileMagic and the number of entries, an encrypted file
+// starts with encryptedFileMagic, the number of entries and the key id.
+// Each block consists of blockMagic, the size and the (encrypted) event,
+// a compressed event is written in a block starting with compressedBlockMagic
+// and is compressed before encrypted.
 const (
 	fileBufferSize       = 4 * 1024 // 4KB
 	fileMagic            = 0x12345678
+	encryptedFileMagic   = 0x12345679
 	numFileEntriesOffset = 4
 	blockMagic           = 0xbeefbeef
+	compressedBlockMagic = 0xbeefbeee
 )
 
 var openFDCount int64
@@ -41,8 +49,13 @@ var openFDCount int64
 type fileBackEnd struct {
 	fileName string
 	serde    encoding.SerializerDeserializer
-	borrowed int32
-	size     int64
+	// cipher is not nil if spill-to-disk encryption is enabled.
+	cipher *encrypt.Cipher
+	// compressor is set by the sorter that allocates the file, it is nil
+	// if compression is disabled.
+	compressor *encoding.Compressor
+	borrowed   int32
+	size       int64
 }
 
 func newFileBackEnd(fileName string, serde encoding.SerializerDeserializer) (*fileBackEnd, error) {
@@ -60,6 +73,7 @@ func newFileBackEnd(fileName string, serde encoding.SerializerDeserializer) (*fi
 	return &fileBackEnd{
 		fileName: fileName,
 		serde:    serde,
+		cipher:   encrypt.GetGlobalCipher(),
 		borrowed: 0,
 	}, nil
 }
@@ -165,6 +179,8 @@ type fileBackEndReader struct {
 	f       *os.File
 	reader  *bufio.Reader
 	isEOF   bool
+	// cipher is not nil if the file is encrypted.
+	cipher *encrypt.Cipher
 
 	// to prevent truncation-like corruption
 	totalEvents uint64
@@ -192,7 +208,7 @@ func (r *fileBackEndReader) readHeader() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if m != fileMagic {
+	if m != fileMagic && m != encryptedFileMagic {
 		log.Panic("fileSorterBackEnd: wrong fileMagic. Damaged file or bug?", zap.Uint32("actual", m))
 	}
 
@@ -201,6 +217,18 @@ func (r *fileBackEndReader) readHeader() error {
 		return errors.Trace(err)
 	}
 
+	if m == encryptedFileMagic {
+		var keyID uint32
+		err = binary.Read(r.reader, binary.LittleEndian, &keyID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := encrypt.VerifyKeyID(r.backEnd.cipher, keyID); err != nil {
+			return errors.Trace(err)
+		}
+		r.cipher = r.backEnd.cipher
+	}
+
 	return nil
 }
 
@@ -227,7 +255,7 @@ func (r *fileBackEndReader) readNext() (*model.PolymorphicEvent, error) {
 		return nil, errors.Trace(wrapIOError(err))
 	}
 
-	if m != blockMagic {
+	if m != blockMagic && m != compressedBlockMagic {
 		log.Panic("fileSorterBackEnd: wrong blockMagic. Damaged file or bug?", zap.Uint32("actual", m))
 	}
 
@@ -250,6 +278,19 @@ func (r *fileBackEndReader) readNext() (*model.PolymorphicEvent, error) {
 		return nil, errors.Errorf("fileSorterBackEnd: expected %d bytes, actually read %d bytes", size, n)
 	}
 
+	if r.cipher != nil {
+		rawBytesBuf, err = r.cipher.Open(nil, rawBytesBuf)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if m == compressedBlockMagic {
+		rawBytesBuf, err = encoding.Decompress(nil, rawBytesBuf)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	event := new(model.PolymorphicEvent)
 	_, err = r.backEnd.serde.Unmarshal(event, rawBytesBuf)
 	if err != nil {
@@ -329,7 +370,11 @@ type fileBackEndWriter struct {
 }
 
 func (w *fileBackEndWriter) writeFileHeader() error {
-	err := binary.Write(w.writer, binary.LittleEndian, uint32(fileMagic))
+	magic := uint32(fileMagic)
+	if w.backEnd.cipher != nil {
+		magic = encryptedFileMagic
+	}
+	err := binary.Write(w.writer, binary.LittleEndian, magic)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -340,6 +385,13 @@ func (w *fileBackEndWriter) writeFileHeader() error {
 		return errors.Trace(err)
 	}
 
+	if w.backEnd.cipher != nil {
+		err = binary.Write(w.writer, binary.LittleEndian, w.backEnd.cipher.KeyID())
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	return nil
 }
 
@@ -351,13 +403,21 @@ func (w *fileBackEndWriter) writeNext(event *model.PolymorphicEvent) error {
 	if err != nil {
 		return errors.Trace(wrapIOError(err))
 	}
+	magic := uint32(blockMagic)
+	if w.backEnd.compressor != nil {
+		rawBytesBuf = w.backEnd.compressor.Compress(nil, rawBytesBuf)
+		magic = compressedBlockMagic
+	}
+	if w.backEnd.cipher != nil {
+		rawBytesBuf = w.backEnd.cipher.Seal(nil, rawBytesBuf)
+	}
 
 	size := len(rawBytesBuf)
 	if size == 0 {
 		log.Panic("fileSorterBackEnd: serialized to empty byte array. Bug?")
 	}
 
-	err = binary.Write(w.writer, binary.LittleEndian, uint32(blockMagic))
+	err = binary.Write(w.writer, binary.LittleEndian, magic)
 	if err != nil {
 		return errors.Trace(wrapIOError(err))
 	}
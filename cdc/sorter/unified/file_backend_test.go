@@ -14,13 +14,17 @@
 package unified
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter/encoding"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
@@ -89,3 +93,110 @@ func TestDataSize(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, uint64(71), w.dataSize())
 }
+
+func TestEncryptedFile(t *testing.T) {
+	// flushAndClose updates the stats of the global pool.
+	pool = &backEndPool{}
+	defer func() {
+		pool = nil
+	}()
+
+	cipher, err := encrypt.NewCipher(bytes.Repeat([]byte{1}, 32))
+	require.Nil(t, err)
+	writeAndRead := func(writeCipher, readCipher *encrypt.Cipher) ([]byte, error) {
+		fileName := filepath.Join(t.TempDir(), "sort-test")
+		fb, err := newFileBackEnd(fileName, &encoding.MsgPackGenSerde{})
+		require.Nil(t, err)
+		fb.cipher = writeCipher
+		w, err := fb.writer()
+		require.Nil(t, err)
+		for i := uint64(10); i < 12; i++ {
+			rawKV := generateMockRawKV(i)
+			rawKV.Key = []byte("sensitive-key")
+			require.Nil(t, w.writeNext(model.NewPolymorphicEvent(rawKV)))
+		}
+		require.Nil(t, w.flushAndClose())
+		content, err := os.ReadFile(fileName)
+		require.Nil(t, err)
+
+		fb.cipher = readCipher
+		r, err := fb.reader()
+		if err != nil {
+			return content, err
+		}
+		for i := uint64(10); i < 12; i++ {
+			event, err := r.readNext()
+			require.Nil(t, err)
+			require.Equal(t, i, event.CRTs)
+			require.Equal(t, []byte("sensitive-key"), event.RawKV.Key)
+		}
+		event, err := r.readNext()
+		require.Nil(t, err)
+		require.Nil(t, event)
+		require.Nil(t, r.resetAndClose())
+		return content, nil
+	}
+
+	content, err := writeAndRead(cipher, cipher)
+	require.Nil(t, err)
+	require.False(t, bytes.Contains(content, []byte("sensitive-key")))
+
+	// Files written without encryption can still be read.
+	content, err = writeAndRead(nil, cipher)
+	require.Nil(t, err)
+	require.True(t, bytes.Contains(content, []byte("sensitive-key")))
+
+	_, err = writeAndRead(cipher, nil)
+	require.True(t, cerrors.ErrEncryptionKeyMissing.Equal(errors.Cause(err)))
+}
+
+func TestCompressedFile(t *testing.T) {
+	// flushAndClose updates the stats of the global pool.
+	pool = &backEndPool{}
+	defer func() {
+		pool = nil
+	}()
+
+	cipher, err := encrypt.NewCipher(bytes.Repeat([]byte{1}, 32))
+	require.Nil(t, err)
+	value := bytes.Repeat([]byte("compressible"), 1024)
+	writeAndRead := func(codec encoding.Codec, cipher *encrypt.Cipher) int {
+		fileName := filepath.Join(t.TempDir(), "sort-test")
+		fb, err := newFileBackEnd(fileName, &encoding.MsgPackGenSerde{})
+		require.Nil(t, err)
+		fb.cipher = cipher
+		fb.compressor = encoding.NewCompressor(codec, "test-cf")
+		w, err := fb.writer()
+		require.Nil(t, err)
+		for i := uint64(10); i < 12; i++ {
+			rawKV := generateMockRawKV(i)
+			rawKV.Value = value
+			require.Nil(t, w.writeNext(model.NewPolymorphicEvent(rawKV)))
+		}
+		require.Nil(t, w.flushAndClose())
+		info, err := os.Stat(fileName)
+		require.Nil(t, err)
+
+		// Compression is not required to read compressed blocks.
+		fb.compressor = nil
+		r, err := fb.reader()
+		require.Nil(t, err)
+		for i := uint64(10); i < 12; i++ {
+			event, err := r.readNext()
+			require.Nil(t, err)
+			require.Equal(t, i, event.CRTs)
+			require.Equal(t, value, event.RawKV.Value)
+		}
+		event, err := r.readNext()
+		require.Nil(t, err)
+		require.Nil(t, event)
+		require.Nil(t, r.resetAndClose())
+		return int(info.Size())
+	}
+
+	uncompressed := writeAndRead(encoding.CodecNone, nil)
+	require.Greater(t, uncompressed, 2*len(value))
+	require.Less(t, writeAndRead(encoding.CodecSnappy, nil), uncompressed/4)
+	require.Less(t, writeAndRead(encoding.CodecZstd, nil), uncompressed/4)
+	require.Less(t, writeAndRead(encoding.CodecZstd, cipher), uncompressed/4)
+}
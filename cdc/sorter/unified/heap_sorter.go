@@ -298,7 +298,9 @@ func (h *heapSorter) init(ctx context.Context, onError func(err error)) {
 		sorterConfig: config.GetGlobalServerConfig().Sorter,
 	}
 
-	poolHandle := heapSorterPool.RegisterEvent(func(ctx context.Context, eventI interface{}) error {
+	// Heap sorters of changefeeds with higher priorities are preferred by
+	// the shared workers.
+	poolHandle := heapSorterPool.RegisterEventWithPriority(func(ctx context.Context, eventI interface{}) error {
 		event := eventI.(*model.PolymorphicEvent)
 		heap.Push(&h.heap, &sortItem{entry: event})
 		isResolvedEvent := event.RawKV != nil && event.RawKV.OpType == model.OpTypeResolved
@@ -330,7 +332,7 @@ func (h *heapSorter) init(ctx context.Context, onError func(err error)) {
 		}
 
 		return nil
-	}).SetTimer(ctx, 1*time.Second, func(ctx context.Context) error {
+	}, util.ChangefeedPriorityFromCtx(ctx)).SetTimer(ctx, 1*time.Second, func(ctx context.Context) error {
 		state.rateCounter = 0
 		state.timerMultiplier = (state.timerMultiplier + 1) % 5
 		if state.timerMultiplier == 0 && state.rateCounter < flushRateLimitPerSecond {
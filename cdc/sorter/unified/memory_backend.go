@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"go.uber.org/zap"
 )
 
@@ -26,6 +27,8 @@ type memoryBackEnd struct {
 	events        []*model.PolymorphicEvent
 	estimatedSize int64
 	borrowed      int32
+	// quota accounts estimatedSize in the memory governor of the capture.
+	quota *memquota.Quota
 }
 
 func newMemoryBackEnd() *memoryBackEnd {
@@ -65,6 +68,7 @@ func (m *memoryBackEnd) free() error {
 	if pool != nil {
 		atomic.AddInt64(&pool.memoryUseEstimate, -m.estimatedSize)
 	}
+	m.quota.Release(uint64(m.estimatedSize))
 
 	return nil
 }
@@ -95,6 +99,7 @@ func (r *memoryBackEndReader) resetAndClose() error {
 	if pool != nil {
 		atomic.AddInt64(&pool.memoryUseEstimate, -r.backEnd.estimatedSize)
 	}
+	r.backEnd.quota.Release(uint64(r.backEnd.estimatedSize))
 	r.backEnd.estimatedSize = 0
 
 	return nil
@@ -141,6 +146,7 @@ func (w *memoryBackEndWriter) flushAndClose() error {
 	if pool != nil {
 		atomic.AddInt64(&pool.memoryUseEstimate, w.bytesWritten)
 	}
+	w.backEnd.quota.ForceReserve(uint64(w.bytesWritten))
 
 	return nil
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter"
+	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -68,7 +69,7 @@ func TestSorterBasic(t *testing.T) {
 
 	err := os.MkdirAll(conf.Sorter.SortDir, 0o755)
 	require.Nil(t, err)
-	sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0)
+	sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0, encoding.CodecNone)
 	require.Nil(t, err)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
@@ -95,7 +96,7 @@ func TestSorterCancel(t *testing.T) {
 
 	err := os.MkdirAll(conf.Sorter.SortDir, 0o755)
 	require.Nil(t, err)
-	sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0)
+	sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0, encoding.CodecNone)
 	require.Nil(t, err)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -234,7 +235,7 @@ func TestSortDirConfigChangeFeed(t *testing.T) {
 	config.GetGlobalServerConfig().Sorter.SortDir = ""
 
 	_, err := NewUnifiedSorter(dir, /* the changefeed setting */
-		"test-cf", "test", 0)
+		"test-cf", "test", 0, encoding.CodecNone)
 	require.Nil(t, err)
 
 	poolMu.Lock()
@@ -280,7 +281,7 @@ func TestSorterCancelRestart(t *testing.T) {
 	}()
 
 	for i := 0; i < 5; i++ {
-		sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0)
+		sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0, encoding.CodecNone)
 		require.Nil(t, err)
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		err = testSorter(ctx, t, sorter, 100000000)
@@ -307,7 +308,7 @@ func TestSorterIOError(t *testing.T) {
 
 	err := os.MkdirAll(conf.Sorter.SortDir, 0o755)
 	require.Nil(t, err)
-	sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0)
+	sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0, encoding.CodecNone)
 	require.Nil(t, err)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -344,7 +345,7 @@ func TestSorterIOError(t *testing.T) {
 	}()
 
 	// recreate the sorter
-	sorter, err = NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0)
+	sorter, err = NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0, encoding.CodecNone)
 	require.Nil(t, err)
 
 	finishedCh = make(chan struct{})
@@ -383,7 +384,7 @@ func TestSorterErrorReportCorrect(t *testing.T) {
 
 	err := os.MkdirAll(conf.Sorter.SortDir, 0o755)
 	require.Nil(t, err)
-	sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0)
+	sorter, err := NewUnifiedSorter(conf.Sorter.SortDir, "test-cf", "test", 0, encoding.CodecNone)
 	require.Nil(t, err)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -420,7 +421,7 @@ func TestSorterErrorReportCorrect(t *testing.T) {
 func TestSortClosedAddEntry(t *testing.T) {
 	defer CleanUp()
 
-	sorter, err := NewUnifiedSorter(t.TempDir(), "test-cf", "test", 0)
+	sorter, err := NewUnifiedSorter(t.TempDir(), "test-cf", "test", 0, encoding.CodecNone)
 	require.Nil(t, err)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
@@ -452,6 +453,6 @@ func TestUnifiedSorterFileLockConflict(t *testing.T) {
 
 	// GlobalServerConfig overrides dir parameter in NewUnifiedSorter.
 	config.GetGlobalServerConfig().Sorter.SortDir = dir
-	_, err = NewUnifiedSorter(dir, "test-cf", "test", 0)
+	_, err = NewUnifiedSorter(dir, "test-cf", "test", 0, encoding.CodecNone)
 	require.Regexp(t, ".*file lock conflict.*", err)
 }
@@ -21,9 +21,11 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -39,6 +41,8 @@ type Sorter struct {
 	outputCh    chan *model.PolymorphicEvent
 	dir         string
 	metricsInfo *metricsInfo
+	// compressor is nil if compression is disabled.
+	compressor *encoding.Compressor
 
 	closeCh chan struct{}
 }
@@ -51,9 +55,18 @@ type metricsInfo struct {
 
 type ctxKey struct{}
 
+// compressorFromCtx returns the compressor of the sorter that runs with ctx.
+func compressorFromCtx(ctx context.Context) *encoding.Compressor {
+	if s, ok := ctx.Value(ctxKey{}).(*Sorter); ok {
+		return s.compressor
+	}
+	return nil
+}
+
 // NewUnifiedSorter creates a new Sorter
 func NewUnifiedSorter(
 	dir string, changeFeedID model.ChangeFeedID, tableName string, tableID model.TableID,
+	codec encoding.Codec,
 ) (*Sorter, error) {
 	poolMu.Lock()
 	defer poolMu.Unlock()
@@ -76,7 +89,8 @@ func NewUnifiedSorter(
 			tableName:    tableName,
 			tableID:      tableID,
 		},
-		closeCh: make(chan struct{}, 1),
+		compressor: encoding.NewCompressor(codec, changeFeedID),
+		closeCh:    make(chan struct{}, 1),
 	}, nil
 }
 
@@ -92,6 +106,29 @@ func CleanUp() {
 	}
 }
 
+// CleanUpStaleFiles removes temporary files left in dir by previous
+// unified sorters. It is used to migrate a sort dir to another sort engine.
+func CleanUpStaleFiles(dir string) error {
+	poolMu.Lock()
+	defer poolMu.Unlock()
+
+	if pool != nil {
+		// Files are still in use by the running unified sorter.
+		return nil
+	}
+	p := &backEndPool{dir: dir}
+	if err := p.lockSortDir(); err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err := p.unlockSortDir(); err != nil {
+			log.Warn("failed to unlock sort dir",
+				zap.String("dir", dir), zap.Error(err))
+		}
+	}()
+	return p.cleanUpStaleFiles()
+}
+
 // ResetGlobalPoolWithoutCleanup reset the pool without cleaning up files.
 // Note that it is used in tests only.
 func ResetGlobalPoolWithoutCleanup() {
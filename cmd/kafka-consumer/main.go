@@ -63,6 +63,10 @@ var (
 	protocol            config.Protocol
 	enableTiDBExtension bool
 
+	// changefeedID filters messages by the `ticdc-changefeed-id` header,
+	// messages without the header are always consumed.
+	changefeedID string
+
 	// eventRouterReplicaConfig only used to initialize the consumer's eventRouter
 	// which then can be used to check RowChangedEvent dispatched correctness
 	eventRouterReplicaConfig *config.ReplicaConfig
@@ -73,7 +77,9 @@ var (
 	ca, cert, key string
 )
 
-func init() {
+// initConfig parses the flags and the upstream URI, it's called by main
+// instead of init so that the consumer can be tested without a Kafka cluster.
+func initConfig() {
 	var (
 		upstreamURIStr string
 		configFile     string
@@ -182,6 +188,11 @@ func init() {
 		enableTiDBExtension = b
 	}
 
+	changefeedID = upstreamURI.Query().Get("changefeed-id")
+	if changefeedID != "" {
+		log.Info("Setting changefeed-id", zap.String("changefeed-id", changefeedID))
+	}
+
 	if configFile != "" {
 		eventRouterReplicaConfig = config.GetDefaultReplicaConfig()
 		eventRouterReplicaConfig.Sink.Protocol = protocol.String()
@@ -273,6 +284,7 @@ func newSaramaConfig() (*sarama.Config, error) {
 }
 
 func main() {
+	initConfig()
 	/**
 	 * Construct a new Sarama configuration.
 	 * The Kafka cluster version has to be defined before the consumer/producer is initialized.
@@ -356,7 +368,10 @@ type Consumer struct {
 
 	ddlList          []*model.DDLEvent
 	maxDDLReceivedTs uint64
-	ddlListMu        sync.Mutex
+	// maxTsDDLs are the DDLs received at maxDDLReceivedTs, the DDLs of a
+	// schema bootstrap share the same commit ts.
+	maxTsDDLs []*model.DDLEvent
+	ddlListMu sync.Mutex
 
 	sinks   []*partitionSink
 	sinksMu sync.Mutex
@@ -369,6 +384,7 @@ type Consumer struct {
 
 	protocol            config.Protocol
 	enableTiDBExtension bool
+	changefeedID        string
 
 	eventRouter *dispatcher.EventRouter
 }
@@ -392,6 +408,7 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 	}
 	c.protocol = protocol
 	c.enableTiDBExtension = enableTiDBExtension
+	c.changefeedID = changefeedID
 
 	// this means user has input config file to enable dispatcher check
 	// some protocol does not provide enough information to check the
@@ -495,17 +512,36 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 
 	eventGroups := make(map[int64]*eventsGroup)
 	for message := range claim.Messages() {
+		headers := parseHeaders(message.Headers)
+		if len(headers) != 0 {
+			log.Debug("message headers received",
+				zap.Int32("partition", partition), zap.Any("headers", headers))
+		}
+		if id, ok := headers[config.HeaderPrefix+config.HeaderChangefeedID]; ok &&
+			c.changefeedID != "" && id != c.changefeedID {
+			session.MarkMessage(message, "")
+			continue
+		}
+		// Prefer the protocol carried by the message, so that one topic can
+		// be shared by changefeeds using different protocols.
+		msgProtocol := c.protocol
+		if s, ok := headers[config.HeaderPrefix+config.HeaderProtocol]; ok {
+			if err := msgProtocol.FromString(s); err != nil {
+				log.Panic("invalid protocol header", zap.Error(err), zap.String("protocol", s))
+			}
+		}
+
 		var (
 			decoder codec.EventBatchDecoder
 			err     error
 		)
-		switch c.protocol {
+		switch msgProtocol {
 		case config.ProtocolOpen, config.ProtocolDefault:
 			decoder, err = codec.NewJSONEventBatchDecoder(message.Key, message.Value)
 		case config.ProtocolCanalJSON:
 			decoder = codec.NewCanalFlatEventBatchDecoder(message.Value, c.enableTiDBExtension)
 		default:
-			log.Panic("Protocol not supported", zap.Any("Protocol", c.protocol))
+			log.Panic("Protocol not supported", zap.Any("Protocol", msgProtocol))
 		}
 		if err != nil {
 			return errors.Trace(err)
@@ -638,8 +674,18 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 	return nil
 }
 
+// parseHeaders converts the Kafka record headers to a map.
+func parseHeaders(recordHeaders []*sarama.RecordHeader) map[string]string {
+	headers := make(map[string]string, len(recordHeaders))
+	for _, header := range recordHeaders {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	return headers
+}
+
 // append DDL wait to be handled, only consider the constraint among DDLs.
-// for DDL a / b received in the order, a.CommitTs < b.CommitTs should be true.
+// for DDL a / b received in the order, a.CommitTs <= b.CommitTs should be true,
+// the DDLs with the same CommitTs are redundant only if they are identical.
 func (c *Consumer) appendDDL(ddl *model.DDLEvent) {
 	c.ddlListMu.Lock()
 	defer c.ddlListMu.Unlock()
@@ -652,16 +698,33 @@ func (c *Consumer) appendDDL(ddl *model.DDLEvent) {
 	}
 
 	if ddl.CommitTs == c.maxDDLReceivedTs {
-		log.Info("ignore redundant DDL, CommitTs = maxDDLReceivedTs",
-			zap.Any("DDL", ddl))
-		return
+		for _, received := range c.maxTsDDLs {
+			if isSameDDL(received, ddl) {
+				log.Info("ignore redundant DDL, CommitTs = maxDDLReceivedTs",
+					zap.Any("DDL", ddl))
+				return
+			}
+		}
+	} else {
+		c.maxTsDDLs = c.maxTsDDLs[:0]
 	}
 
 	c.ddlList = append(c.ddlList, ddl)
+	c.maxTsDDLs = append(c.maxTsDDLs, ddl)
 	log.Info("DDL event received", zap.Any("DDL", ddl))
 	c.maxDDLReceivedTs = ddl.CommitTs
 }
 
+func isSameDDL(a, b *model.DDLEvent) bool {
+	if a.Query != b.Query || a.Type != b.Type {
+		return false
+	}
+	if a.TableInfo == nil || b.TableInfo == nil {
+		return a.TableInfo == b.TableInfo
+	}
+	return a.TableInfo.Schema == b.TableInfo.Schema && a.TableInfo.Table == b.TableInfo.Table
+}
+
 func (c *Consumer) getFrontDDL() *model.DDLEvent {
 	c.ddlListMu.Lock()
 	defer c.ddlListMu.Unlock()
@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/stretchr/testify/require"
+)
+
+// receiveDDL encodes the DDL by the open protocol and appends the decoded DDL
+// like the consumer does.
+func receiveDDL(t *testing.T, c *Consumer, ddl *model.DDLEvent) {
+	message, err := codec.NewJSONEventBatchEncoder().EncodeDDLEvent(ddl)
+	require.Nil(t, err)
+	decoder, err := codec.NewJSONEventBatchDecoder(message.Key, message.Value)
+	require.Nil(t, err)
+	tp, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MqMessageTypeDDL, tp)
+	decoded, err := decoder.NextDDLEvent()
+	require.Nil(t, err)
+	c.appendDDL(decoded)
+}
+
+func TestAppendSchemaBootstrapDDL(t *testing.T) {
+	t.Parallel()
+
+	newCreateTable := func(ts uint64, table string) *model.DDLEvent {
+		return &model.DDLEvent{
+			StartTs:   ts,
+			CommitTs:  ts,
+			TableInfo: &model.SimpleTableInfo{Schema: "test", Table: table},
+			Query:     "CREATE TABLE IF NOT EXISTS `" + table + "` (`id` int)",
+			Type:      timodel.ActionCreateTable,
+		}
+	}
+	c := &Consumer{}
+	receiveDDL(t, c, &model.DDLEvent{
+		StartTs:   90,
+		CommitTs:  100,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+		Query:     "ALTER TABLE `t1` ADD COLUMN `c` int",
+		Type:      timodel.ActionAddColumn,
+	})
+	// The schema bootstrap shares the commit ts of the last DDL.
+	bootstrap := []*model.DDLEvent{
+		newCreateTable(100, "t1"),
+		newCreateTable(100, "t2"),
+		newCreateTable(100, "t3"),
+	}
+	for _, ddl := range bootstrap {
+		receiveDDL(t, c, ddl)
+	}
+	// The redundant DDLs are ignored.
+	receiveDDL(t, c, bootstrap[1])
+	require.Len(t, c.ddlList, 4)
+	for i, ddl := range bootstrap {
+		require.Equal(t, ddl.Query, c.ddlList[i+1].Query)
+		require.Equal(t, uint64(100), c.ddlList[i+1].CommitTs)
+	}
+
+	receiveDDL(t, c, newCreateTable(200, "t4"))
+	receiveDDL(t, c, newCreateTable(200, "t1"))
+	receiveDDL(t, c, newCreateTable(200, "t4"))
+	require.Len(t, c.ddlList, 6)
+	require.Equal(t, uint64(200), c.maxDDLReceivedTs)
+}
@@ -397,6 +397,91 @@ var doc = `{
                 }
             }
         },
+        "/api/v1/changefeeds/{changefeed_id}/schema_bootstrap": {
+            "post": {
+                "description": "publish a CREATE TABLE DDL event for every table of a changefeed at its checkpoint, the events are published once the changefeed is running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Publish schema bootstrap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/changefeeds/{changefeed_id}/tables": {
+            "get": {
+                "description": "list the replication status of all tables of a changefeed, including the owning capture, the resolved ts of every stage in the table pipeline, the checkpoint ts and the lag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "List tables of a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TableStatusList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/changefeeds/{changefeed_id}/tables/move_table": {
             "post": {
                 "description": "move one table to the target capture",
@@ -699,97 +784,1905 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "config.ColumnSelector": {
-            "type": "object",
-            "properties": {
-                "columns": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        },
+        "/api/v2/captures": {
+            "get": {
+                "description": "list the captures in cdc cluster page by page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "capture"
+                ],
+                "summary": "List captures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 100 by default",
+                        "name": "page_size",
+                        "in": "query"
                     }
-                },
-                "matcher": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CaptureListV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
                     }
                 }
             }
         },
-        "config.DispatchRule": {
-            "type": "object",
-            "properties": {
-                "dispatcher": {
-                    "type": "string"
-                },
-                "matcher": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/api/v2/captures/{capture_id}/drain": {
+            "put": {
+                "description": "move all tables off a capture and keep new tables off it, send the request repeatedly until no table is left, then the capture is safe to stop",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "capture"
+                ],
+                "summary": "Drain a capture",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "capture_id",
+                        "name": "capture_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DrainCaptureResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
                     }
-                },
-                "topic": {
-                    "type": "string"
                 }
             }
         },
-        "config.SinkConfig": {
-            "type": "object",
-            "properties": {
-                "column-selectors": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/config.ColumnSelector"
+        "/api/v2/changefeed_batch/pause": {
+            "post": {
+                "description": "pause all the changefeeds whose labels match the selector",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Pause changefeeds by labels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector, such as env=prod,team!=dba",
+                        "name": "label_selector",
+                        "in": "query",
+                        "required": true
                     }
-                },
-                "dispatchers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/config.DispatchRule"
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchChangefeedResultV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
                     }
-                },
-                "protocol": {
-                    "type": "string"
                 }
             }
         },
-        "model.Capture": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "is_owner": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "model.CaptureTaskStatus": {
-            "type": "object",
-            "properties": {
-                "capture_id": {
-                    "type": "string"
-                },
-                "table_ids": {
-                    "description": "Table list, containing tables that processor should process",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                },
-                "table_operations": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/model.TableOperation"
-                    }
+        "/api/v2/changefeed_batch/remove": {
+            "post": {
+                "description": "remove all the changefeeds whose labels match the selector",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Remove changefeeds by labels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector, such as env=prod,team!=dba",
+                        "name": "label_selector",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "remove all information of the changefeeds",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchChangefeedResultV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeed_batch/resume": {
+            "post": {
+                "description": "resume all the changefeeds whose labels match the selector",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Resume changefeeds by labels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "label selector, such as env=prod,team!=dba",
+                        "name": "label_selector",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchChangefeedResultV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeed_templates": {
+            "get": {
+                "description": "list the changefeed templates page by page, ordered by the name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "List changefeed templates",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 100 by default",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedTemplateListV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a named changefeed config that changefeeds can be created from",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Create changefeed template",
+                "parameters": [
+                    {
+                        "description": "changefeed template",
+                        "name": "template",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedTemplate"
+                        },
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeed_templates/{template_name}": {
+            "get": {
+                "description": "get a changefeed template by its name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Get changefeed template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "template_name",
+                        "name": "template_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "replace a changefeed template, the changefeeds created from it are not affected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Update changefeed template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "template_name",
+                        "name": "template_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "changefeed template",
+                        "name": "template",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedTemplate"
+                        },
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a changefeed template, the changefeeds created from it are not affected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Delete changefeed template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "template_name",
+                        "name": "template_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds": {
+            "get": {
+                "description": "list the changefeeds in cdc cluster page by page, ordered by the changefeed id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "List changefeeds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "state",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only list the changefeeds whose labels match the selector, such as env=prod,team!=dba",
+                        "name": "label_selector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 100 by default",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedListV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a new changefeed, or only verify the config if dry_run is true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Create changefeed",
+                "parameters": [
+                    {
+                        "description": "changefeed config",
+                        "name": "changefeed",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedConfigV2"
+                        },
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only verify the changefeed config",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedDetailV2"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedDetailV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}": {
+            "get": {
+                "description": "get the info, status and task status of a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Get changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedDetailV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update a stopped changefeed, or only verify the config if dry_run is true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Update a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "changefeed config",
+                        "name": "changefeed",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedConfigV2"
+                        },
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only verify the changefeed config",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedDetailV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "remove a changefeed, all the information of it is removed if force is true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Remove a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "remove all information of the changefeed",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/pause": {
+            "post": {
+                "description": "Pause a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Pause a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/redo_meta": {
+            "get": {
+                "description": "get the checkpoint ts and resolved ts stored in the redo log meta of a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Get redo log meta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RedoMetaV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/resume": {
+            "post": {
+                "description": "Resume a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Resume a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/schema_bootstrap": {
+            "post": {
+                "description": "publish a CREATE TABLE DDL event for every table of a changefeed at its checkpoint, the events are published once the changefeed is running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Publish schema bootstrap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/statistics": {
+            "get": {
+                "description": "get the replication statistics of a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Get changefeed statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedStatisticsV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/tables": {
+            "get": {
+                "description": "list the replication status of all tables of a changefeed, including the owning capture, the resolved ts of every stage in the table pipeline, the checkpoint ts and the lag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "List tables of a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TableStatusList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/tables/move_table": {
+            "post": {
+                "description": "move one table to the target capture",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "move table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "table_id",
+                        "name": "table_id",
+                        "in": "body",
+                        "schema": {
+                            "type": "integer"
+                        },
+                        "required": true
+                    },
+                    {
+                        "description": "capture_id",
+                        "name": "capture_id",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/tables/rebalance_table": {
+            "post": {
+                "description": "rebalance all tables of a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "rebalance tables",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/health": {
+            "get": {
+                "description": "check if CDC cluster is health",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common"
+                ],
+                "summary": "Check if CDC cluster is health",
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/log": {
+            "post": {
+                "description": "change TiCDC log level dynamically",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common"
+                ],
+                "summary": "Change TiCDC log level",
+                "parameters": [
+                    {
+                        "description": "log level",
+                        "name": "log_level",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/owner/resign": {
+            "post": {
+                "description": "notify the current owner to resign",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owner"
+                ],
+                "summary": "notify the owner to resign",
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/processors": {
+            "get": {
+                "description": "list the processors in the TiCDC cluster page by page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processor"
+                ],
+                "summary": "List processors",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number, starting from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 100 by default",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProcessorListV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/processors/{changefeed_id}/{capture_id}": {
+            "get": {
+                "description": "get the detail information of a processor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processor"
+                ],
+                "summary": "Get processor detail information",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProcessorDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/status": {
+            "get": {
+                "description": "get the status of a server(capture)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common"
+                ],
+                "summary": "Get server status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ServerStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/tso": {
+            "get": {
+                "description": "get a timestamp from PD",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common"
+                ],
+                "summary": "Get tso",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TSOV2"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/unsafe/metadata": {
+            "get": {
+                "description": "show all the metadata of TiCDC stored in etcd",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unsafe"
+                ],
+                "summary": "Show metadata",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.EtcdDataV2"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/unsafe/reset": {
+            "post": {
+                "description": "delete all the metadata in etcd and the service GC safepoint of TiCDC, use it at your own risk. All the captures, including the one serving the request, lose their sessions and re-register themselves",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unsafe"
+                ],
+                "summary": "Reset the TiCDC cluster",
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/unsafe/resolve_lock": {
+            "post": {
+                "description": "resolve the locks in a region before a timestamp, which is 1 minute ago from now by default",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unsafe"
+                ],
+                "summary": "Resolve locks",
+                "parameters": [
+                    {
+                        "description": "region id and timestamp",
+                        "name": "resolve_lock",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResolveLockReqV2"
+                        },
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/unsafe/service_gc_safepoint": {
+            "delete": {
+                "description": "delete the service GC safepoint of TiCDC in PD, use it at your own risk",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "unsafe"
+                ],
+                "summary": "Delete service GC safepoint",
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "config.ColumnSelector": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.ConsistentConfig": {
+            "properties": {
+                "flush-interval": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "max-log-size": {
+                    "type": "integer"
+                },
+                "storage": {
+                    "type": "string"
+                }
+            },
+            "type": "object"
+        },
+        "config.CyclicConfig": {
+            "properties": {
+                "enable": {
+                    "type": "boolean"
+                },
+                "filter-replica-ids": {
+                    "items": {
+                        "type": "integer"
+                    },
+                    "type": "array"
+                },
+                "id-buckets": {
+                    "type": "integer"
+                },
+                "replica-id": {
+                    "type": "integer"
+                },
+                "sync-ddl": {
+                    "type": "boolean"
+                }
+            },
+            "type": "object"
+        },
+        "config.DispatchRule": {
+            "type": "object",
+            "properties": {
+                "dispatcher": {
+                    "type": "string"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "topic": {
+                    "type": "string"
+                },
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "topic-config": {
+                    "$ref": "#/definitions/config.TopicConfig"
+                }
+            }
+        },
+        "config.FilterConfig": {
+            "properties": {
+                "ddl-allow-list": {
+                    "items": {
+                        "type": "integer"
+                    },
+                    "type": "array"
+                },
+                "do-dbs": {
+                    "items": {
+                        "type": "string"
+                    },
+                    "type": "array"
+                },
+                "do-tables": {
+                    "items": {
+                        "$ref": "#/definitions/filter.Table"
+                    },
+                    "type": "array"
+                },
+                "ignore-dbs": {
+                    "items": {
+                        "type": "string"
+                    },
+                    "type": "array"
+                },
+                "ignore-tables": {
+                    "items": {
+                        "$ref": "#/definitions/filter.Table"
+                    },
+                    "type": "array"
+                },
+                "ignore-txn-start-ts": {
+                    "items": {
+                        "type": "integer"
+                    },
+                    "type": "array"
+                },
+                "rules": {
+                    "items": {
+                        "type": "string"
+                    },
+                    "type": "array"
+                }
+            },
+            "type": "object"
+        },
+        "config.MounterConfig": {
+            "properties": {
+                "worker-num": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
+        },
+        "config.ReplicaConfig": {
+            "properties": {
+                "case-sensitive": {
+                    "type": "boolean"
+                },
+                "check-gc-safe-point": {
+                    "type": "boolean"
+                },
+                "consistent": {
+                    "$ref": "#/definitions/config.ConsistentConfig"
+                },
+                "cyclic-replication": {
+                    "$ref": "#/definitions/config.CyclicConfig"
+                },
+                "enable-old-value": {
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/config.FilterConfig"
+                },
+                "force-replicate": {
+                    "type": "boolean"
+                },
+                "mounter": {
+                    "$ref": "#/definitions/config.MounterConfig"
+                },
+                "sink": {
+                    "$ref": "#/definitions/config.SinkConfig"
+                }
+            },
+            "type": "object"
+        },
+        "config.RouteRule": {
+            "type": "object",
+            "properties": {
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "target-schema": {
+                    "type": "string"
+                },
+                "target-table": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.SinkConfig": {
+            "type": "object",
+            "properties": {
+                "column-selectors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ColumnSelector"
+                    }
+                },
+                "ddl-rewriters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dispatchers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.DispatchRule"
+                    }
+                },
+                "protocol": {
+                    "type": "string"
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.RouteRule"
+                    }
+                }
+            }
+        },
+        "config.TopicConfig": {
+            "type": "object",
+            "properties": {
+                "cleanup-policy": {
+                    "type": "string"
+                },
+                "min-insync-replicas": {
+                    "type": "integer"
+                },
+                "partition-num": {
+                    "type": "integer"
+                },
+                "replication-factor": {
+                    "type": "integer"
+                },
+                "retention-ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "filter.Table": {
+            "properties": {
+                "db-name": {
+                    "type": "string"
+                },
+                "tbl-name": {
+                    "type": "string"
+                }
+            },
+            "type": "object"
+        },
+        "model.BatchChangefeedResultV2": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "type": "object"
+                },
+                "succeeded": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.Capture": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_owner": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.CaptureListV2": {
+            "properties": {
+                "items": {
+                    "items": {
+                        "$ref": "#/definitions/model.Capture"
+                    },
+                    "type": "array"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
+        },
+        "model.CaptureError": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CaptureTaskStatus": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
+                    "type": "string"
+                },
+                "table_ids": {
+                    "description": "Table list, containing tables that processor should process",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "table_operations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.TableOperation"
+                    }
+                }
+            }
+        },
+        "model.ChangeFeedInfo": {
+            "properties": {
+                "admin-job-type": {
+                    "type": "integer"
+                },
+                "config": {
+                    "$ref": "#/definitions/config.ReplicaConfig"
+                },
+                "create-time": {
+                    "type": "string"
+                },
+                "creator-version": {
+                    "type": "string"
+                },
+                "error": {
+                    "$ref": "#/definitions/model.RunningError"
+                },
+                "opts": {
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "type": "object"
+                },
+                "sink-uri": {
+                    "type": "string"
+                },
+                "sort-dir": {
+                    "type": "string"
+                },
+                "sort-engine": {
+                    "type": "string"
+                },
+                "start-ts": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "sync-point-enabled": {
+                    "type": "boolean"
+                },
+                "sync-point-interval": {
+                    "type": "integer"
+                },
+                "target-ts": {
+                    "type": "integer"
                 }
-            }
+            },
+            "type": "object"
+        },
+        "model.ChangeFeedStatus": {
+            "properties": {
+                "admin-job-type": {
+                    "type": "integer"
+                },
+                "checkpoint-ts": {
+                    "type": "integer"
+                },
+                "resolved-ts": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
         },
         "model.ChangefeedCommonInfo": {
             "type": "object",
@@ -806,6 +2699,12 @@ var doc = `{
                 "id": {
                     "type": "string"
                 },
+                "labels": {
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "type": "object"
+                },
                 "state": {
                     "type": "string"
                 }
@@ -861,6 +2760,62 @@ var doc = `{
                 }
             }
         },
+        "model.ChangefeedConfigV2": {
+            "properties": {
+                "changefeed_id": {
+                    "type": "string"
+                },
+                "disable_gc_check": {
+                    "type": "boolean"
+                },
+                "ignore_ineligible_table": {
+                    "type": "boolean"
+                },
+                "labels": {
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "type": "object"
+                },
+                "opts": {
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "type": "object"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "replica_config": {
+                    "$ref": "#/definitions/config.ReplicaConfig"
+                },
+                "sink_uri": {
+                    "type": "string"
+                },
+                "sort_engine": {
+                    "type": "string"
+                },
+                "start_ts": {
+                    "type": "integer"
+                },
+                "sync_point_enabled": {
+                    "type": "boolean"
+                },
+                "sync_point_interval": {
+                    "type": "integer"
+                },
+                "target_ts": {
+                    "type": "integer"
+                },
+                "template": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            },
+            "type": "object"
+        },
         "model.ChangefeedDetail": {
             "type": "object",
             "properties": {
@@ -914,6 +2869,144 @@ var doc = `{
                 }
             }
         },
+        "model.ChangefeedDetailV2": {
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ineligible_tables": {
+                    "items": {
+                        "$ref": "#/definitions/model.TableName"
+                    },
+                    "type": "array"
+                },
+                "info": {
+                    "$ref": "#/definitions/model.ChangeFeedInfo"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ChangeFeedStatus"
+                },
+                "task_status": {
+                    "items": {
+                        "$ref": "#/definitions/model.CaptureTaskStatus"
+                    },
+                    "type": "array"
+                }
+            },
+            "type": "object"
+        },
+        "model.ChangefeedListV2": {
+            "properties": {
+                "items": {
+                    "items": {
+                        "$ref": "#/definitions/model.ChangefeedCommonInfo"
+                    },
+                    "type": "array"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
+        },
+        "model.ChangefeedStatisticsV2": {
+            "properties": {
+                "checkpoint_ts": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "current_ts": {
+                    "type": "integer"
+                },
+                "replication_gap": {
+                    "type": "integer"
+                },
+                "resolved_ts": {
+                    "type": "integer"
+                },
+                "sink_gap": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
+        },
+        "model.ChangefeedTemplate": {
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "type": "object"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opts": {
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "type": "object"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "replica_config": {
+                    "$ref": "#/definitions/config.ReplicaConfig"
+                },
+                "sink_uri": {
+                    "type": "string"
+                },
+                "sort_engine": {
+                    "type": "string"
+                },
+                "sync_point_enabled": {
+                    "type": "boolean"
+                },
+                "sync_point_interval": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ChangefeedTemplateListV2": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChangefeedTemplate"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DrainCaptureResp": {
+            "type": "object",
+            "properties": {
+                "current_table_count": {
+                    "description": "The number of tables still on the capture, it is safe to stop the capture when it is zero.",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.EtcdDataV2": {
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            },
+            "type": "object"
+        },
         "model.HTTPError": {
             "type": "object",
             "properties": {
@@ -964,6 +3057,42 @@ var doc = `{
                 }
             }
         },
+        "model.ProcessorListV2": {
+            "properties": {
+                "items": {
+                    "items": {
+                        "$ref": "#/definitions/model.ProcessorCommonInfo"
+                    },
+                    "type": "array"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
+        },
+        "model.RedoMetaV2": {
+            "properties": {
+                "checkpoint_ts": {
+                    "type": "integer"
+                },
+                "resolved_ts": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
+        },
+        "model.ResolveLockReqV2": {
+            "properties": {
+                "region_id": {
+                    "type": "integer"
+                },
+                "ts": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
+        },
         "model.RunningError": {
             "type": "object",
             "properties": {
@@ -998,6 +3127,37 @@ var doc = `{
                 }
             }
         },
+        "model.TSOV2": {
+            "properties": {
+                "logical_time": {
+                    "type": "integer"
+                },
+                "physical_time": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            },
+            "type": "object"
+        },
+        "model.TableName": {
+            "properties": {
+                "db-name": {
+                    "type": "string"
+                },
+                "is-partition": {
+                    "type": "boolean"
+                },
+                "tbl-id": {
+                    "type": "integer"
+                },
+                "tbl-name": {
+                    "type": "string"
+                }
+            },
+            "type": "object"
+        },
         "model.TableOperation": {
             "type": "object",
             "properties": {
@@ -1015,6 +3175,71 @@ var doc = `{
                     "type": "integer"
                 }
             }
+        },
+        "model.TableStatus": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
+                    "description": "CaptureID is the capture that replicates the table.",
+                    "type": "string"
+                },
+                "checkpoint_ts": {
+                    "description": "CheckpointTs is the ts that has been flushed to the downstream.",
+                    "type": "integer"
+                },
+                "emitted_events": {
+                    "description": "EmittedEvents is the number of events that have been sent to the sink.",
+                    "type": "integer"
+                },
+                "lag": {
+                    "description": "Lag is the gap between the current ts and the checkpoint ts in milliseconds.",
+                    "type": "integer"
+                },
+                "puller_resolved_ts": {
+                    "description": "PullerResolvedTs is the resolved ts that has been pulled from TiKV.",
+                    "type": "integer"
+                },
+                "received_events": {
+                    "description": "ReceivedEvents is the number of events that have been pulled.",
+                    "type": "integer"
+                },
+                "sink_resolved_ts": {
+                    "description": "SinkResolvedTs is the resolved ts that has been sent to the sink.",
+                    "type": "integer"
+                },
+                "sorter_resolved_ts": {
+                    "description": "SorterResolvedTs is the resolved ts that has been sorted.",
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "State is the state of the table pipeline.",
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "integer"
+                },
+                "table_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TableStatusList": {
+            "type": "object",
+            "properties": {
+                "capture_errors": {
+                    "description": "CaptureErrors holds the captures whose tables can not be queried,\nthe tables replicated by them are missing in Tables.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CaptureError"
+                    }
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TableStatus"
+                    }
+                }
+            }
         }
     }
 }`
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/pkg/actor/message"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -72,9 +73,10 @@ const (
 
 // proc is wrapper of a running actor.
 type proc[T any] struct {
-	state uint64
-	mb    Mailbox[T]
-	actor Actor[T]
+	state    uint64
+	mb       Mailbox[T]
+	actor    Actor[T]
+	priority priority.Priority
 }
 
 // batchReceiveMsgs receives messages into batchMsg.
@@ -153,7 +155,9 @@ type ready[T any] struct {
 
 	// TODO: replace with a memory efficient queue,
 	// e.g., an array based queue to save allocation.
-	queue list.List
+	// Queues of ready procs, indexed by priority.Priority.Index().
+	queues [priority.Num]list.List
+	picker priority.Picker
 	// In the set, an actor is either polling by system
 	// or is pending to be polled.
 	procs map[ID]struct{}
@@ -185,7 +189,7 @@ func (rd *ready[T]) enqueueLocked(p *proc[T], force bool) error {
 	}
 	id := p.mb.ID()
 	if _, ok := rd.procs[id]; !ok || force {
-		rd.queue.PushBack(p)
+		rd.queues[p.priority.Index()].PushBack(p)
 		rd.procs[id] = struct{}{}
 	}
 	return nil
@@ -216,16 +220,24 @@ func (rd *ready[T]) scheduleN(procs []*proc[T]) {
 }
 
 // batchReceiveProcs receives ready procs into batchP.
+// Procs of higher priorities are preferred.
 func (rd *ready[T]) batchReceiveProcs(batchP []*proc[T]) int {
 	n := 0
 	max := len(batchP)
 	for i := 0; i < max; i++ {
-		if rd.queue.Len() == 0 {
+		var queue *list.List
+		for _, idx := range rd.picker.Next() {
+			if rd.queues[idx].Len() != 0 {
+				queue = &rd.queues[idx]
+				break
+			}
+		}
+		if queue == nil {
 			// Stop receive if there is no more ready procs.
 			break
 		}
-		element := rd.queue.Front()
-		rd.queue.Remove(element)
+		element := queue.Front()
+		queue.Remove(element)
 		p := element.Value.(*proc[T])
 		batchP[i] = p
 		n++
@@ -248,7 +260,9 @@ func NewRouter[T any](name string) *Router[T] {
 	}
 	r.rd.cond = sync.NewCond(&r.rd.Mutex)
 	r.rd.procs = make(map[ID]struct{})
-	r.rd.queue.Init()
+	for i := range r.rd.queues {
+		r.rd.queues[i].Init()
+	}
 	r.rd.metricDropMessage = dropMsgCount.WithLabelValues(name)
 	return r
 }
@@ -488,8 +502,17 @@ func (s *System[T]) Stop() {
 // Spawn spawns an actor in the system.
 // Spawn is threadsafe.
 func (s *System[T]) Spawn(mb Mailbox[T], actor Actor[T]) error {
+	return s.SpawnWithPriority(mb, actor, priority.Normal)
+}
+
+// SpawnWithPriority spawns an actor with the priority in the system.
+// Actors of higher priorities are polled first when the system is busy.
+// SpawnWithPriority is threadsafe.
+func (s *System[T]) SpawnWithPriority(
+	mb Mailbox[T], actor Actor[T], pri priority.Priority,
+) error {
 	id := mb.ID()
-	p := &proc[T]{mb: mb, actor: actor}
+	p := &proc[T]{mb: mb, actor: actor, priority: pri}
 	return s.router.insert(id, p)
 }
 
@@ -25,6 +25,7 @@ import (
 
 	"github.com/pingcap/tiflow/pkg/actor/message"
 	"github.com/pingcap/tiflow/pkg/leakutil"
+	"github.com/pingcap/tiflow/pkg/priority"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 )
@@ -762,3 +763,35 @@ func BenchmarkPollActor(b *testing.B) {
 
 	sys.Stop()
 }
+
+func TestReadyPriority(t *testing.T) {
+	t.Parallel()
+
+	rd := NewRouter[any](t.Name()).rd
+	newProc := func(id ID, pri priority.Priority) *proc[any] {
+		return &proc[any]{mb: NewMailbox[any](id, 1), actor: &closedActor{}, priority: pri}
+	}
+	for i := 0; i < 4; i++ {
+		require.Nil(t, rd.schedule(newProc(ID(i), priority.Low)))
+	}
+	for i := 4; i < 16; i++ {
+		require.Nil(t, rd.schedule(newProc(ID(i), priority.High)))
+	}
+
+	batchP := make([]*proc[any], 16)
+	require.Equal(t, 16, rd.batchReceiveProcs(batchP))
+	var order []priority.Priority
+	for _, p := range batchP {
+		order = append(order, p.priority)
+	}
+	// High priority procs are preferred, one of every 7 picks prefers low
+	// priority procs.
+	require.Equal(t, []priority.Priority{
+		priority.High, priority.High, priority.High, priority.High,
+		priority.High, priority.High, priority.Low,
+		priority.High, priority.High, priority.High, priority.High,
+		priority.High, priority.High, priority.Low,
+		priority.Low, priority.Low,
+	}, order)
+	require.Equal(t, 0, rd.batchReceiveProcs(batchP))
+}
@@ -33,6 +33,8 @@ type Config struct {
 	Credential *security.Credential
 	// API verion
 	Version string
+	// Auth is the credential of the API authentication, it can be nil.
+	Auth *httputil.Auth
 }
 
 // defaultServerURLFromConfig is used to build base URL and api path.
@@ -73,6 +75,7 @@ func CDCRESTClientFromConfig(config *Config) (*CDCRESTClient, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	httpClient.SetAuth(config.Auth)
 
 	baseURL, versionedAPIPath, err := defaultServerURLFromConfig(config)
 	if err != nil {
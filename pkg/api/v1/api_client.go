@@ -15,6 +15,7 @@ package v1
 
 import (
 	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 )
 
@@ -57,12 +58,13 @@ func (c *APIV1Client) RESTClient() rest.CDCRESTInterface {
 }
 
 // NewAPIClient creates a new APIV1Client.
-func NewAPIClient(ownerAddr string, credential *security.Credential) (*APIV1Client, error) {
+func NewAPIClient(ownerAddr string, credential *security.Credential, auth *httputil.Auth) (*APIV1Client, error) {
 	c := &rest.Config{}
 	c.APIPath = "/api"
 	c.Version = "v1"
 	c.Host = ownerAddr
 	c.Credential = credential
+	c.Auth = auth
 	client, err := rest.CDCRESTClientFromConfig(c)
 	if err != nil {
 		return nil, err
@@ -31,6 +31,7 @@ type ChangefeedsGetter interface {
 type ChangefeedInterface interface {
 	Get(ctx context.Context, name string) (*model.ChangefeedDetail, error)
 	List(ctx context.Context) (*[]model.ChangeFeedInfo, error)
+	Tables(ctx context.Context, name string) (*[]model.TableStatus, error)
 }
 
 // changefeeds implements ChangefeedInterface
@@ -66,3 +67,14 @@ func (c *changefeeds) List(ctx context.Context) (*[]model.ChangeFeedInfo, error)
 		Into(result)
 	return result, err
 }
+
+// Tables returns the replication status of all tables of a changefeed
+func (c *changefeeds) Tables(ctx context.Context, name string) (*[]model.TableStatus, error) {
+	result := new([]model.TableStatus)
+	u := fmt.Sprintf("changefeeds/%s/tables", name)
+	err := c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/security"
+)
+
+// APIV2Interface is an abstraction for TiCDC capture/changefeed/changefeed template/processor/tso/unsafe operations.
+// We can create a fake api client which mocks TiCDC operations by implement this interface.
+type APIV2Interface interface {
+	RESTClient() rest.CDCRESTInterface
+	CapturesGetter
+	ChangefeedsGetter
+	ChangefeedTemplatesGetter
+	ProcessorsGetter
+	TsoGetter
+	UnsafeGetter
+}
+
+// APIV2Client implements APIV2Interface and it is used to interact with cdc v2 open api.
+type APIV2Client struct {
+	restClient rest.CDCRESTInterface
+}
+
+// Captures returns a CaptureInterface which abstracts capture operations.
+func (c *APIV2Client) Captures() CaptureInterface {
+	return newCaptures(c)
+}
+
+// Changefeeds returns a ChangefeedInterface which abstracts changefeed operations.
+func (c *APIV2Client) Changefeeds() ChangefeedInterface {
+	return newChangefeeds(c)
+}
+
+// ChangefeedTemplates returns a ChangefeedTemplateInterface which abstracts
+// changefeed template operations.
+func (c *APIV2Client) ChangefeedTemplates() ChangefeedTemplateInterface {
+	return newChangefeedTemplates(c)
+}
+
+// Processors returns a ProcessorInterface which abstracts processor operations.
+func (c *APIV2Client) Processors() ProcessorInterface {
+	return newProcessors(c)
+}
+
+// Tso returns a TsoInterface which abstracts tso operations.
+func (c *APIV2Client) Tso() TsoInterface {
+	return newTso(c)
+}
+
+// Unsafe returns an UnsafeInterface which abstracts unsafe operations.
+func (c *APIV2Client) Unsafe() UnsafeInterface {
+	return newUnsafe(c)
+}
+
+// RESTClient returns a RESTClient that is used to communicate with the cdc server
+// by this client implementation.
+func (c *APIV2Client) RESTClient() rest.CDCRESTInterface {
+	if c == nil {
+		return nil
+	}
+	return c.restClient
+}
+
+// NewAPIClient creates a new APIV2Client.
+// Unlike the v1 client, serverAddr can be the address of any capture,
+// the requests are forwarded to the owner by the server if necessary.
+func NewAPIClient(serverAddr string, credential *security.Credential, auth *httputil.Auth) (*APIV2Client, error) {
+	c := &rest.Config{}
+	c.APIPath = "/api"
+	c.Version = "v2"
+	c.Host = serverAddr
+	c.Credential = credential
+	c.Auth = auth
+	client, err := rest.CDCRESTClientFromConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIV2Client{client}, nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// ChangefeedsGetter has a method to return a ChangefeedInterface.
+type ChangefeedsGetter interface {
+	Changefeeds() ChangefeedInterface
+}
+
+// ChangefeedInterface has methods to work with Changefeed items.
+// We can also mock the changefeed operations by implement this interface.
+type ChangefeedInterface interface {
+	// List returns all the changefeeds in the given state,
+	// "all" returns the changefeeds in any state.
+	List(ctx context.Context, state string) ([]model.ChangefeedCommonInfo, error)
+	// Get returns the detail of a changefeed.
+	Get(ctx context.Context, id string) (*model.ChangefeedDetailV2, error)
+	// Create creates a changefeed, the config is only verified if dryRun is true.
+	Create(ctx context.Context, cfg *model.ChangefeedConfigV2, dryRun bool) (*model.ChangefeedDetailV2, error)
+	// Update updates a stopped changefeed, the config is only verified if dryRun is true.
+	Update(ctx context.Context, id string, cfg *model.ChangefeedConfigV2, dryRun bool) (*model.ChangefeedDetailV2, error)
+	// Pause pauses a changefeed.
+	Pause(ctx context.Context, id string) error
+	// Resume resumes a changefeed.
+	Resume(ctx context.Context, id string) error
+	// Remove removes a changefeed, all the information of it is removed if force is true.
+	Remove(ctx context.Context, id string, force bool) error
+	// Statistics returns the replication statistics of a changefeed.
+	Statistics(ctx context.Context, id string) (*model.ChangefeedStatisticsV2, error)
+	// RedoMeta returns the meta of the redo logs of a changefeed.
+	RedoMeta(ctx context.Context, id string) (*model.RedoMetaV2, error)
+	// Tables returns the replication status of all tables of a changefeed.
+	Tables(ctx context.Context, id string) ([]model.TableStatus, error)
+	// BatchPause pauses the changefeeds whose labels match the selector.
+	BatchPause(ctx context.Context, selector string) (*model.BatchChangefeedResultV2, error)
+	// BatchResume resumes the changefeeds whose labels match the selector.
+	BatchResume(ctx context.Context, selector string) (*model.BatchChangefeedResultV2, error)
+	// BatchRemove removes the changefeeds whose labels match the selector,
+	// all the information of them is removed if force is true.
+	BatchRemove(ctx context.Context, selector string, force bool) (*model.BatchChangefeedResultV2, error)
+}
+
+// changefeeds implements ChangefeedInterface
+type changefeeds struct {
+	client rest.CDCRESTInterface
+}
+
+// newChangefeeds returns changefeeds
+func newChangefeeds(c *APIV2Client) *changefeeds {
+	return &changefeeds{
+		client: c.RESTClient(),
+	}
+}
+
+// List implements ChangefeedInterface.List
+func (c *changefeeds) List(ctx context.Context, state string) ([]model.ChangefeedCommonInfo, error) {
+	var items []model.ChangefeedCommonInfo
+	err := listAll(func(page int) (int, int, error) {
+		result := new(model.ChangefeedListV2)
+		err := withPage(c.client.Get().WithURI("changefeeds"), page).
+			WithParam("state", state).
+			Do(ctx).
+			Into(result)
+		items = append(items, result.Items...)
+		return result.Total, len(result.Items), err
+	})
+	return items, err
+}
+
+// Get implements ChangefeedInterface.Get
+func (c *changefeeds) Get(ctx context.Context, id string) (*model.ChangefeedDetailV2, error) {
+	result := new(model.ChangefeedDetailV2)
+	err := c.client.Get().
+		WithURI("changefeeds/" + id).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Create implements ChangefeedInterface.Create
+func (c *changefeeds) Create(
+	ctx context.Context, cfg *model.ChangefeedConfigV2, dryRun bool,
+) (*model.ChangefeedDetailV2, error) {
+	result := new(model.ChangefeedDetailV2)
+	err := c.client.Post().
+		WithURI("changefeeds").
+		WithParam("dry_run", strconv.FormatBool(dryRun)).
+		WithBody(cfg).
+		// creating a changefeed is not idempotent
+		WithMaxRetries(1).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Update implements ChangefeedInterface.Update
+func (c *changefeeds) Update(
+	ctx context.Context, id string, cfg *model.ChangefeedConfigV2, dryRun bool,
+) (*model.ChangefeedDetailV2, error) {
+	result := new(model.ChangefeedDetailV2)
+	err := c.client.Put().
+		WithURI("changefeeds/"+id).
+		WithParam("dry_run", strconv.FormatBool(dryRun)).
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Pause implements ChangefeedInterface.Pause
+func (c *changefeeds) Pause(ctx context.Context, id string) error {
+	return c.client.Post().
+		WithURI(fmt.Sprintf("changefeeds/%s/pause", id)).
+		Do(ctx).
+		Error()
+}
+
+// Resume implements ChangefeedInterface.Resume
+func (c *changefeeds) Resume(ctx context.Context, id string) error {
+	return c.client.Post().
+		WithURI(fmt.Sprintf("changefeeds/%s/resume", id)).
+		Do(ctx).
+		Error()
+}
+
+// Remove implements ChangefeedInterface.Remove
+func (c *changefeeds) Remove(ctx context.Context, id string, force bool) error {
+	return c.client.Delete().
+		WithURI("changefeeds/"+id).
+		WithParam("force", strconv.FormatBool(force)).
+		Do(ctx).
+		Error()
+}
+
+// Statistics implements ChangefeedInterface.Statistics
+func (c *changefeeds) Statistics(ctx context.Context, id string) (*model.ChangefeedStatisticsV2, error) {
+	result := new(model.ChangefeedStatisticsV2)
+	err := c.client.Get().
+		WithURI(fmt.Sprintf("changefeeds/%s/statistics", id)).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// RedoMeta implements ChangefeedInterface.RedoMeta
+func (c *changefeeds) RedoMeta(ctx context.Context, id string) (*model.RedoMetaV2, error) {
+	result := new(model.RedoMetaV2)
+	err := c.client.Get().
+		WithURI(fmt.Sprintf("changefeeds/%s/redo_meta", id)).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Tables implements ChangefeedInterface.Tables
+func (c *changefeeds) Tables(ctx context.Context, id string) ([]model.TableStatus, error) {
+	var result []model.TableStatus
+	err := c.client.Get().
+		WithURI(fmt.Sprintf("changefeeds/%s/tables", id)).
+		Do(ctx).
+		Into(&result)
+	return result, err
+}
+
+// BatchPause implements ChangefeedInterface.BatchPause
+func (c *changefeeds) BatchPause(
+	ctx context.Context, selector string,
+) (*model.BatchChangefeedResultV2, error) {
+	return c.batch(ctx, "pause", selector, false)
+}
+
+// BatchResume implements ChangefeedInterface.BatchResume
+func (c *changefeeds) BatchResume(
+	ctx context.Context, selector string,
+) (*model.BatchChangefeedResultV2, error) {
+	return c.batch(ctx, "resume", selector, false)
+}
+
+// BatchRemove implements ChangefeedInterface.BatchRemove
+func (c *changefeeds) BatchRemove(
+	ctx context.Context, selector string, force bool,
+) (*model.BatchChangefeedResultV2, error) {
+	return c.batch(ctx, "remove", selector, force)
+}
+
+func (c *changefeeds) batch(
+	ctx context.Context, op, selector string, force bool,
+) (*model.BatchChangefeedResultV2, error) {
+	result := new(model.BatchChangefeedResultV2)
+	err := c.client.Post().
+		WithURI("changefeed_batch/"+op).
+		WithParam("label_selector", selector).
+		WithParam("force", strconv.FormatBool(force)).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// ChangefeedTemplatesGetter has a method to return a ChangefeedTemplateInterface.
+type ChangefeedTemplatesGetter interface {
+	ChangefeedTemplates() ChangefeedTemplateInterface
+}
+
+// ChangefeedTemplateInterface has methods to work with ChangefeedTemplate items.
+// We can also mock the changefeed template operations by implement this interface.
+type ChangefeedTemplateInterface interface {
+	// List returns all the changefeed templates.
+	List(ctx context.Context) ([]model.ChangefeedTemplate, error)
+	// Get returns a changefeed template.
+	Get(ctx context.Context, name string) (*model.ChangefeedTemplate, error)
+	// Create creates a changefeed template.
+	Create(ctx context.Context, tpl *model.ChangefeedTemplate) (*model.ChangefeedTemplate, error)
+	// Update replaces an existing changefeed template.
+	Update(ctx context.Context, tpl *model.ChangefeedTemplate) (*model.ChangefeedTemplate, error)
+	// Delete deletes a changefeed template.
+	Delete(ctx context.Context, name string) error
+}
+
+// changefeedTemplates implements ChangefeedTemplateInterface
+type changefeedTemplates struct {
+	client rest.CDCRESTInterface
+}
+
+// newChangefeedTemplates returns changefeedTemplates
+func newChangefeedTemplates(c *APIV2Client) *changefeedTemplates {
+	return &changefeedTemplates{
+		client: c.RESTClient(),
+	}
+}
+
+// List implements ChangefeedTemplateInterface.List
+func (c *changefeedTemplates) List(ctx context.Context) ([]model.ChangefeedTemplate, error) {
+	var items []model.ChangefeedTemplate
+	err := listAll(func(page int) (int, int, error) {
+		result := new(model.ChangefeedTemplateListV2)
+		err := withPage(c.client.Get().WithURI("changefeed_templates"), page).
+			Do(ctx).
+			Into(result)
+		items = append(items, result.Items...)
+		return result.Total, len(result.Items), err
+	})
+	return items, err
+}
+
+// Get implements ChangefeedTemplateInterface.Get
+func (c *changefeedTemplates) Get(ctx context.Context, name string) (*model.ChangefeedTemplate, error) {
+	result := new(model.ChangefeedTemplate)
+	err := c.client.Get().
+		WithURI("changefeed_templates/" + name).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Create implements ChangefeedTemplateInterface.Create
+func (c *changefeedTemplates) Create(
+	ctx context.Context, tpl *model.ChangefeedTemplate,
+) (*model.ChangefeedTemplate, error) {
+	result := new(model.ChangefeedTemplate)
+	err := c.client.Post().
+		WithURI("changefeed_templates").
+		WithBody(tpl).
+		// creating a template is not idempotent
+		WithMaxRetries(1).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Update implements ChangefeedTemplateInterface.Update
+func (c *changefeedTemplates) Update(
+	ctx context.Context, tpl *model.ChangefeedTemplate,
+) (*model.ChangefeedTemplate, error) {
+	result := new(model.ChangefeedTemplate)
+	err := c.client.Put().
+		WithURI("changefeed_templates/" + tpl.Name).
+		WithBody(tpl).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Delete implements ChangefeedTemplateInterface.Delete
+func (c *changefeedTemplates) Delete(ctx context.Context, name string) error {
+	return c.client.Delete().
+		WithURI("changefeed_templates/" + name).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// TsoGetter has a method to return a TsoInterface.
+type TsoGetter interface {
+	Tso() TsoInterface
+}
+
+// TsoInterface has methods to query the tso.
+// We can also mock the tso operations by implement this interface.
+type TsoInterface interface {
+	Query(ctx context.Context) (*model.TSOV2, error)
+}
+
+// tso implements TsoInterface
+type tso struct {
+	client rest.CDCRESTInterface
+}
+
+// newTso returns tso
+func newTso(c *APIV2Client) *tso {
+	return &tso{
+		client: c.RESTClient(),
+	}
+}
+
+// Query returns a timestamp allocated by PD.
+func (c *tso) Query(ctx context.Context) (*model.TSOV2, error) {
+	result := new(model.TSOV2)
+	err := c.client.Get().
+		WithURI("tso").
+		Do(ctx).
+		Into(result)
+	return result, err
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// UnsafeGetter has a method to return an UnsafeInterface.
+type UnsafeGetter interface {
+	Unsafe() UnsafeInterface
+}
+
+// UnsafeInterface has methods to work with the unsafe operations.
+// We can also mock the unsafe operations by implement this interface.
+type UnsafeInterface interface {
+	Metadata(ctx context.Context) ([]model.EtcdDataV2, error)
+	Reset(ctx context.Context) error
+	DeleteServiceGCSafepoint(ctx context.Context) error
+	ResolveLock(ctx context.Context, req *model.ResolveLockReqV2) error
+}
+
+// unsafe implements UnsafeInterface
+type unsafe struct {
+	client rest.CDCRESTInterface
+}
+
+// newUnsafe returns unsafe
+func newUnsafe(c *APIV2Client) *unsafe {
+	return &unsafe{
+		client: c.RESTClient(),
+	}
+}
+
+// Metadata returns all the metadata of TiCDC stored in etcd.
+func (c *unsafe) Metadata(ctx context.Context) ([]model.EtcdDataV2, error) {
+	var result []model.EtcdDataV2
+	err := c.client.Get().
+		WithURI("unsafe/metadata").
+		Do(ctx).
+		Into(&result)
+	return result, err
+}
+
+// Reset deletes all the metadata and the service GC safepoint of TiCDC.
+func (c *unsafe) Reset(ctx context.Context) error {
+	return c.client.Post().
+		WithURI("unsafe/reset").
+		WithMaxRetries(1).
+		Do(ctx).
+		Error()
+}
+
+// DeleteServiceGCSafepoint deletes the service GC safepoint of TiCDC.
+func (c *unsafe) DeleteServiceGCSafepoint(ctx context.Context) error {
+	return c.client.Delete().
+		WithURI("unsafe/service_gc_safepoint").
+		Do(ctx).
+		Error()
+}
+
+// ResolveLock resolves the locks in a region.
+func (c *unsafe) ResolveLock(ctx context.Context, req *model.ResolveLockReqV2) error {
+	return c.client.Post().
+		WithURI("unsafe/resolve_lock").
+		WithBody(req).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"strconv"
+
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// listPageSize is the page size used to list all the items,
+// it is the max page size allowed by the server.
+const listPageSize = 1000
+
+// withPage sets the pagination parameters of a list request.
+func withPage(req *rest.Request, page int) *rest.Request {
+	return req.
+		WithParam("page", strconv.Itoa(page)).
+		WithParam("page_size", strconv.Itoa(listPageSize))
+}
+
+// listAll fetches the pages one by one until all the items are fetched.
+// fetch returns the total number of items and the number of items in the page.
+func listAll(fetch func(page int) (int, int, error)) error {
+	fetched := 0
+	for page := 1; ; page++ {
+		total, n, err := fetch(page)
+		if err != nil {
+			return err
+		}
+		fetched += n
+		if n == 0 || fetched >= total {
+			return nil
+		}
+	}
+}
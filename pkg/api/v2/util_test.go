@@ -0,0 +1,55 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAll(t *testing.T) {
+	t.Parallel()
+
+	// fetch all the pages until the number of fetched items reaches the total
+	var pages []int
+	err := listAll(func(page int) (int, int, error) {
+		pages = append(pages, page)
+		if page < 3 {
+			return 2500, listPageSize, nil
+		}
+		return 2500, 500, nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []int{1, 2, 3}, pages)
+
+	// stop if the page is empty, the items may be removed during listing
+	pages = pages[:0]
+	err = listAll(func(page int) (int, int, error) {
+		pages = append(pages, page)
+		if page == 1 {
+			return 2000, listPageSize, nil
+		}
+		return 1000, 0, nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []int{1, 2}, pages)
+
+	// stop on error
+	err = listAll(func(page int) (int, int, error) {
+		return 0, 0, errors.New("test")
+	})
+	require.Error(t, err)
+}
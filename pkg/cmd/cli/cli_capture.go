@@ -73,6 +73,7 @@ func newCmdCapture(f factory.Factory) *cobra.Command {
 	}
 	cmds.AddCommand(
 		newCmdListCapture(f),
+		newCmdDrainCapture(f),
 		// TODO: add resign owner command
 	)
 
@@ -0,0 +1,127 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"time"
+
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/spf13/cobra"
+)
+
+// drainCaptureOptions defines flags for the `cli capture drain` command.
+type drainCaptureOptions struct {
+	apiClient apiv2client.CaptureInterface
+
+	captureID string
+	interval  time.Duration
+}
+
+// newDrainCaptureOptions creates new options for the `cli capture drain` command.
+func newDrainCaptureOptions() *drainCaptureOptions {
+	return &drainCaptureOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *drainCaptureOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&o.captureID, "capture-id", "", "the ID of the capture to drain")
+	cmd.PersistentFlags().DurationVar(&o.interval, "interval", time.Second, "the interval to check the progress of draining")
+	_ = cmd.MarkPersistentFlagRequired("capture-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *drainCaptureOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiClient = apiClient.Captures()
+		return nil
+	}
+
+	etcdClient, err := f.EtcdClient()
+	if err != nil {
+		return err
+	}
+	owner, err := getOwnerCapture(cmdcontext.GetDefaultContext(), etcdClient)
+	if err != nil {
+		return err
+	}
+	apiClient, err := apiv2client.NewAPIClient(owner.AdvertiseAddr, f.GetCredential(), f.GetAuth())
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient.Captures()
+	return nil
+}
+
+// run the `cli capture drain` command.
+func (o *drainCaptureOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+	if err := o.drain(ctx, cmd); err != nil {
+		return err
+	}
+	cmd.Printf("Capture %s is drained, it is safe to stop it now\n", o.captureID)
+	return nil
+}
+
+// drain keeps asking the owner to move tables off the capture until
+// the capture holds no table.
+func (o *drainCaptureOptions) drain(ctx context.Context, cmd *cobra.Command) error {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	for {
+		resp, err := o.apiClient.Drain(ctx, o.captureID)
+		if err != nil {
+			return err
+		}
+		if resp.CurrentTableCount == 0 {
+			return nil
+		}
+		cmd.Printf("Draining capture %s, %d tables left\n", o.captureID, resp.CurrentTableCount)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// newCmdDrainCapture creates the `cli capture drain` command.
+func newCmdDrainCapture(f factory.Factory) *cobra.Command {
+	o := newDrainCaptureOptions()
+
+	command := &cobra.Command{
+		Use:   "drain",
+		Short: "Move all tables off a capture, so that it can be stopped gracefully",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/pingcap/check"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/util/testleak"
+	"github.com/spf13/cobra"
+)
+
+type captureDrainSuite struct{}
+
+var _ = check.Suite(&captureDrainSuite{})
+
+// mockCaptures is a CaptureInterface that reports fewer and fewer tables
+// on every Drain call.
+type mockCaptures struct {
+	tableCounts []int
+	drained     []string
+}
+
+func (m *mockCaptures) List(ctx context.Context) ([]model.Capture, error) {
+	return nil, nil
+}
+
+func (m *mockCaptures) Drain(
+	ctx context.Context, captureID string,
+) (*model.DrainCaptureResp, error) {
+	m.drained = append(m.drained, captureID)
+	count := m.tableCounts[0]
+	m.tableCounts = m.tableCounts[1:]
+	return &model.DrainCaptureResp{CurrentTableCount: count}, nil
+}
+
+func (s *captureDrainSuite) TestDrainCapture(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	captures := &mockCaptures{tableCounts: []int{3, 1, 0}}
+	o := &drainCaptureOptions{
+		apiClient: captures,
+		captureID: "capture-1",
+		interval:  time.Millisecond,
+	}
+	cmd := &cobra.Command{}
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	c.Assert(o.drain(context.Background(), cmd), check.IsNil)
+	c.Assert(captures.drained, check.DeepEquals, []string{"capture-1", "capture-1", "capture-1"})
+	c.Assert(out.String(), check.Equals,
+		"Draining capture capture-1, 3 tables left\n"+
+			"Draining capture capture-1, 1 tables left\n")
+}
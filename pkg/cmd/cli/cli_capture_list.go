@@ -20,6 +20,7 @@ import (
 	"github.com/spf13/cobra"
 	"go.etcd.io/etcd/client/v3/concurrency"
 
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
@@ -29,14 +30,16 @@ import (
 
 // capture holds capture information.
 type capture struct {
-	ID            string `json:"id"`
-	IsOwner       bool   `json:"is-owner"`
-	AdvertiseAddr string `json:"address"`
+	ID            string            `json:"id"`
+	IsOwner       bool              `json:"is-owner"`
+	AdvertiseAddr string            `json:"address"`
+	Labels        map[string]string `json:"labels,omitempty"`
 }
 
 // listCaptureOptions defines flags for the `cli capture list` command.
 type listCaptureOptions struct {
-	etcdClient *etcd.CDCEtcdClient
+	etcdClient  *etcd.CDCEtcdClient
+	apiV2Client apiv2client.APIV2Interface
 }
 
 // newListCaptureOptions creates new listCaptureOptions for the `cli capture list` command.
@@ -46,6 +49,15 @@ func newListCaptureOptions() *listCaptureOptions {
 
 // complete adapts from the command line args to the data and client required.
 func (o *listCaptureOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -60,6 +72,21 @@ func (o *listCaptureOptions) complete(f factory.Factory) error {
 func (o *listCaptureOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
 
+	if o.apiV2Client != nil {
+		raw, err := o.apiV2Client.Captures().List(ctx)
+		if err != nil {
+			return err
+		}
+		captures := make([]*capture, 0, len(raw))
+		for _, c := range raw {
+			captures = append(captures,
+				&capture{
+					ID: c.ID, IsOwner: c.IsOwner, AdvertiseAddr: c.AdvertiseAddr, Labels: c.Labels,
+				})
+		}
+		return util.JSONPrint(cmd, captures)
+	}
+
 	captures, err := listCaptures(ctx, o.etcdClient)
 	if err != nil {
 		return err
@@ -105,7 +132,9 @@ func listCaptures(ctx context.Context, etcdClient *etcd.CDCEtcdClient) ([]*captu
 	for _, c := range raw {
 		isOwner := c.ID == ownerID
 		captures = append(captures,
-			&capture{ID: c.ID, IsOwner: isOwner, AdvertiseAddr: c.AdvertiseAddr})
+			&capture{
+				ID: c.ID, IsOwner: isOwner, AdvertiseAddr: c.AdvertiseAddr, Labels: c.Labels,
+			})
 	}
 
 	return captures, nil
@@ -76,11 +76,15 @@ func newCmdChangefeed(f factory.Factory) *cobra.Command {
 	cmds.AddCommand(newCmdUpdateChangefeed(f))
 	cmds.AddCommand(newCmdStatisticsChangefeed(f))
 	cmds.AddCommand(newCmdCyclicChangefeed(f))
+	cmds.AddCommand(newCmdExportChangefeed(f))
+	cmds.AddCommand(newCmdImportChangefeed(f))
 	cmds.AddCommand(newCmdListChangefeed(f))
 	cmds.AddCommand(newCmdPauseChangefeed(f))
 	cmds.AddCommand(newCmdQueryChangefeed(f))
 	cmds.AddCommand(newCmdRemoveChangefeed(f))
 	cmds.AddCommand(newCmdResumeChangefeed(f))
+	cmds.AddCommand(newCmdTablesChangefeed(f))
+	cmds.AddCommand(newCmdTemplateChangefeed(f))
 
 	o.addFlags(cmds)
 
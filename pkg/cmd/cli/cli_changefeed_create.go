@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
@@ -33,11 +34,13 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	ticdcutil "github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/tikv/client-go/v2/oracle"
 	pd "github.com/tikv/pd/client"
 	"go.uber.org/zap"
@@ -57,6 +60,8 @@ type changefeedCommonOptions struct {
 	cyclicSyncDDL          bool
 	syncPointEnabled       bool
 	syncPointInterval      time.Duration
+	priority               string
+	labels                 []string
 }
 
 // newChangefeedCommonOptions creates new changefeed common options.
@@ -83,6 +88,8 @@ func (o *changefeedCommonOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVar(&o.cyclicSyncDDL, "cyclic-sync-ddl", true, "(Experimental) Cyclic replication sync DDL of changefeed")
 	cmd.PersistentFlags().BoolVar(&o.syncPointEnabled, "sync-point", false, "(Experimental) Set and Record syncpoint in replication(default off)")
 	cmd.PersistentFlags().DurationVar(&o.syncPointInterval, "sync-interval", 10*time.Minute, "(Experimental) Set the interval for syncpoint in replication(default 10min)")
+	cmd.PersistentFlags().StringVar(&o.priority, "priority", "normal", "Priority of changefeed, one of high, normal and low")
+	cmd.PersistentFlags().StringSliceVar(&o.labels, "labels", nil, "Labels of changefeed used to select changefeeds in batch operations, in the `key=value` format")
 	_ = cmd.PersistentFlags().MarkHidden("sort-dir")
 }
 
@@ -102,8 +109,9 @@ func (o *changefeedCommonOptions) strictDecodeConfig(component string, cfg *conf
 type createChangefeedOptions struct {
 	commonChangefeedOptions *changefeedCommonOptions
 
-	etcdClient *etcd.CDCEtcdClient
-	pdClient   pd.Client
+	etcdClient  *etcd.CDCEtcdClient
+	pdClient    pd.Client
+	apiV2Client apiv2client.APIV2Interface
 
 	pdAddr     string
 	credential *security.Credential
@@ -112,6 +120,7 @@ type createChangefeedOptions struct {
 	disableGCSafePointCheck bool
 	startTs                 uint64
 	timezone                string
+	template                string
 
 	cfg *config.ReplicaConfig
 }
@@ -135,10 +144,25 @@ func (o *createChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&o.disableGCSafePointCheck, "disable-gc-check", "", false, "Disable GC safe point check")
 	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0, "Start ts of changefeed")
 	cmd.PersistentFlags().StringVar(&o.timezone, "tz", "SYSTEM", "timezone used when checking sink uri (changefeed timezone is determined by cdc server)")
+	cmd.PersistentFlags().StringVar(&o.template, "template", "", "Name of the changefeed template to create the changefeed from, the flags set explicitly override the template")
 }
 
 // complete adapts from the command line args to the data and client required.
 func (o *createChangefeedOptions) complete(ctx context.Context, f factory.Factory, cmd *cobra.Command) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		// The version related defaults and the start ts are
+		// decided by the server, take the cluster as the latest version here.
+		return o.completeCfg(cmd, nil)
+	}
+	if o.template != "" {
+		return errors.New("the changefeed templates are only supported by the open API, please specify --server")
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -232,6 +256,7 @@ func (o *createChangefeedOptions) completeCfg(
 	case model.SortInMemory:
 	case model.SortInFile:
 	case model.SortUnified:
+	case model.SortInDB:
 	default:
 		log.Warn("invalid sort-engine, use Unified Sorter by default",
 			zap.String("invalidSortEngine", o.commonChangefeedOptions.sortEngine))
@@ -275,7 +300,8 @@ func (o *createChangefeedOptions) completeCfg(
 
 // validate checks that the provided attach options are specified.
 func (o *createChangefeedOptions) validate(ctx context.Context, cmd *cobra.Command) error {
-	if o.commonChangefeedOptions.sinkURI == "" {
+	// The sink uri may be inherited from the template.
+	if o.commonChangefeedOptions.sinkURI == "" && o.template == "" {
 		return errors.New("Creating changefeed without a sink-uri")
 	}
 
@@ -284,8 +310,11 @@ func (o *createChangefeedOptions) validate(ctx context.Context, cmd *cobra.Comma
 		return err
 	}
 
-	if err := o.validateStartTs(ctx); err != nil {
-		return err
+	// The start ts is verified by the server if the open API is used.
+	if o.apiV2Client == nil {
+		if err := o.validateStartTs(ctx); err != nil {
+			return err
+		}
 	}
 
 	if err := o.validateTargetTs(); err != nil {
@@ -300,6 +329,14 @@ func (o *createChangefeedOptions) validate(ctx context.Context, cmd *cobra.Comma
 		return errors.New("Creating changefeed with `--sort-dir`, it's invalid")
 	}
 
+	if _, err := priority.Parse(o.commonChangefeedOptions.priority); err != nil {
+		return err
+	}
+
+	if _, err := parseLabels(o.commonChangefeedOptions.labels); err != nil {
+		return err
+	}
+
 	switch o.commonChangefeedOptions.sortEngine {
 	case model.SortUnified, model.SortInMemory:
 	case model.SortInFile:
@@ -328,6 +365,11 @@ func (o *createChangefeedOptions) getInfo(cmd *cobra.Command) *model.ChangeFeedI
 		SyncPointInterval: o.commonChangefeedOptions.syncPointInterval,
 		CreatorVersion:    version.ReleaseVersion,
 	}
+	// The priority and the labels have been checked in validate.
+	info.Priority, _ = priority.Parse(o.commonChangefeedOptions.priority)
+	if labels, _ := parseLabels(o.commonChangefeedOptions.labels); len(labels) != 0 {
+		info.Labels = labels
+	}
 
 	if info.Engine == model.SortInFile {
 		cmd.Printf("[WARN] file sorter is deprecated. " +
@@ -383,6 +425,10 @@ func (o *createChangefeedOptions) validateSink(
 
 // run the `cli changefeed create` command.
 func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) error {
+	if o.apiV2Client != nil {
+		return o.runWithAPIV2Client(ctx, cmd)
+	}
+
 	id := o.changefeedID
 	if id == "" {
 		id = uuid.New().String()
@@ -453,6 +499,136 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 	return nil
 }
 
+// runWithAPIV2Client creates the changefeed by the open API v2.
+func (o *createChangefeedOptions) runWithAPIV2Client(ctx context.Context, cmd *cobra.Command) error {
+	if o.changefeedID != "" {
+		if err := model.ValidateChangefeedID(o.changefeedID); err != nil {
+			return err
+		}
+	}
+
+	info := o.getInfo(cmd)
+	var cfg *model.ChangefeedConfigV2
+	if o.template != "" {
+		cfg = o.newTemplateChangefeedConfig(cmd, info)
+	} else {
+		cfg = &model.ChangefeedConfigV2{
+			ID:                o.changefeedID,
+			StartTs:           o.startTs,
+			TargetTs:          info.TargetTs,
+			SinkURI:           info.SinkURI,
+			Engine:            info.Engine,
+			Opts:              info.Opts,
+			ReplicaConfig:     info.Config,
+			SyncPointEnabled:  &info.SyncPointEnabled,
+			SyncPointInterval: info.SyncPointInterval,
+			Priority:          &info.Priority,
+			Labels:            info.Labels,
+			TimeZone:          o.timezone,
+			DisableGCCheck:    o.disableGCSafePointCheck,
+			// The ineligible tables are confirmed by the user below.
+			IgnoreIneligibleTable: true,
+		}
+	}
+
+	// Verify the config first, the server fills the changefeed id and
+	// the start ts if they are not specified.
+	detail, err := o.apiV2Client.Changefeeds().Create(ctx, cfg, true)
+	if err != nil {
+		return err
+	}
+	cfg.ID = detail.ID
+	cfg.StartTs = detail.Info.StartTs
+	if cfg.Template != "" {
+		// Pin the config verified by the dry run,
+		// the template may be changed before the changefeed is created.
+		cfg.Template = ""
+		cfg.SinkURI = detail.Info.SinkURI
+		cfg.Engine = detail.Info.Engine
+		cfg.Opts = detail.Info.Opts
+		cfg.ReplicaConfig = detail.Info.Config
+		cfg.SyncPointEnabled = &detail.Info.SyncPointEnabled
+		cfg.SyncPointInterval = detail.Info.SyncPointInterval
+		cfg.Priority = &detail.Info.Priority
+		cfg.Labels = detail.Info.Labels
+	}
+
+	if !o.commonChangefeedOptions.noConfirm {
+		tso, err := o.apiV2Client.Tso().Query(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := confirmLargeDataGap(cmd, tso.PhysicalTime, cfg.StartTs); err != nil {
+			return err
+		}
+	}
+
+	if len(detail.IneligibleTables) != 0 {
+		if detail.Info.Config.ForceReplicate {
+			cmd.Printf("[WARN] force to replicate some ineligible tables, %#v\n", detail.IneligibleTables)
+		} else {
+			cmd.Printf("[WARN] some tables are not eligible to replicate, %#v\n", detail.IneligibleTables)
+			if !o.commonChangefeedOptions.noConfirm {
+				if err := confirmIgnoreIneligibleTables(cmd); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	detail, err = o.apiV2Client.Changefeeds().Create(ctx, cfg, false)
+	if err != nil {
+		return err
+	}
+	infoStr, err := detail.Info.Marshal()
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Create changefeed successfully!\nID: %s\nInfo: %s\n", detail.ID, infoStr)
+
+	return nil
+}
+
+// newTemplateChangefeedConfig returns the config of a changefeed created from
+// a template, only the flags set explicitly override the template.
+func (o *createChangefeedOptions) newTemplateChangefeedConfig(
+	cmd *cobra.Command, info *model.ChangeFeedInfo,
+) *model.ChangefeedConfigV2 {
+	cfg := &model.ChangefeedConfigV2{
+		ID:             o.changefeedID,
+		StartTs:        o.startTs,
+		TargetTs:       info.TargetTs,
+		TimeZone:       o.timezone,
+		DisableGCCheck: o.disableGCSafePointCheck,
+		// The ineligible tables are confirmed by the user.
+		IgnoreIneligibleTable: true,
+		Template:              o.template,
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		switch flag.Name {
+		case "sink-uri":
+			cfg.SinkURI = info.SinkURI
+		case "sort-engine":
+			cfg.Engine = info.Engine
+		case "opts":
+			cfg.Opts = info.Opts
+		case "config", "cyclic-replica-id", "cyclic-filter-replica-ids", "cyclic-sync-ddl":
+			cfg.ReplicaConfig = info.Config
+		case "sync-point":
+			cfg.SyncPointEnabled = &info.SyncPointEnabled
+		case "sync-interval":
+			cfg.SyncPointInterval = info.SyncPointInterval
+		case "priority":
+			cfg.Priority = &info.Priority
+		case "labels":
+			cfg.Labels = info.Labels
+		}
+	})
+	return cfg
+}
+
 // newCmdCreateChangefeed creates the `cli changefeed create` command.
 func newCmdCreateChangefeed(f factory.Factory) *cobra.Command {
 	commonChangefeedOptions := newChangefeedCommonOptions()
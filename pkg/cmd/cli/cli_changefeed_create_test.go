@@ -14,6 +14,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,6 +23,7 @@ import (
 	"github.com/pingcap/check"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/pingcap/tiflow/pkg/util/testleak"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/spf13/cobra"
@@ -99,3 +101,40 @@ func (s *changefeedSuite) TestInvalidSortEngine(c *check.C) {
 		c.Assert(opt.commonChangefeedOptions.sortEngine, check.Equals, cs.expect)
 	}
 }
+
+func (s *changefeedSuite) TestNewTemplateChangefeedConfig(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	cmd := new(cobra.Command)
+	o := newCreateChangefeedOptions(newChangefeedCommonOptions())
+	o.addFlags(cmd)
+	c.Assert(cmd.ParseFlags([]string{
+		"--template=kafka", "--changefeed-id=test", "--sink-uri=blackhole://",
+		"--labels=env=test", "--disable-gc-check",
+	}), check.IsNil)
+	c.Assert(o.completeCfg(cmd, nil), check.IsNil)
+	c.Assert(o.validate(context.Background(), cmd), check.IsNil)
+
+	cfg := o.newTemplateChangefeedConfig(cmd, o.getInfo(cmd))
+	c.Assert(cfg.Template, check.Equals, "kafka")
+	c.Assert(cfg.ID, check.Equals, "test")
+	c.Assert(cfg.SinkURI, check.Equals, "blackhole://")
+	c.Assert(cfg.Labels, check.DeepEquals, map[string]string{"env": "test"})
+	// the flags not set explicitly are inherited from the template
+	c.Assert(cfg.Engine, check.Equals, "")
+	c.Assert(cfg.Opts, check.IsNil)
+	c.Assert(cfg.ReplicaConfig, check.IsNil)
+	c.Assert(cfg.SyncPointEnabled, check.IsNil)
+	c.Assert(cfg.Priority, check.IsNil)
+
+	// the sink uri can be inherited from the template
+	cmd = new(cobra.Command)
+	o = newCreateChangefeedOptions(newChangefeedCommonOptions())
+	o.addFlags(cmd)
+	c.Assert(cmd.ParseFlags([]string{"--template=kafka", "--priority=high", "--disable-gc-check"}), check.IsNil)
+	c.Assert(o.completeCfg(cmd, nil), check.IsNil)
+	c.Assert(o.validate(context.Background(), cmd), check.IsNil)
+	cfg = o.newTemplateChangefeedConfig(cmd, o.getInfo(cmd))
+	c.Assert(cfg.SinkURI, check.Equals, "")
+	c.Assert(*cfg.Priority, check.Equals, priority.High)
+}
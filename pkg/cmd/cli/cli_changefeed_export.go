@@ -0,0 +1,211 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/spf13/cobra"
+)
+
+// exportChangefeedOptions defines flags for the `cli changefeed export` command.
+type exportChangefeedOptions struct {
+	etcdClient  *etcd.CDCEtcdClient
+	apiV2Client apiv2client.APIV2Interface
+
+	changefeedIDs []string
+	selector      string
+	output        string
+}
+
+// newExportChangefeedOptions creates new options for the `cli changefeed export` command.
+func newExportChangefeedOptions() *exportChangefeedOptions {
+	return &exportChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *exportChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSliceVar(&o.changefeedIDs, "changefeed-ids", nil, "IDs of the changefeeds to export, all the changefeeds are exported if it is not specified")
+	cmd.PersistentFlags().StringVarP(&o.selector, "selector", "l", "", "Only export the changefeeds whose labels match the selector, such as env=prod,team!=dba")
+	cmd.PersistentFlags().StringVar(&o.output, "output", "", "Path of the file to write the changefeed bundle to, the bundle is printed if it is not specified")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *exportChangefeedOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
+	etcdClient, err := f.EtcdClient()
+	if err != nil {
+		return err
+	}
+	o.etcdClient = etcdClient
+	return nil
+}
+
+// run the `cli changefeed export` command.
+func (o *exportChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	var selector model.LabelSelector
+	if o.selector != "" {
+		var err error
+		if selector, err = model.ParseLabelSelector(o.selector); err != nil {
+			return err
+		}
+	}
+
+	var (
+		infos    map[model.ChangeFeedID]*model.ChangeFeedInfo
+		statuses map[model.ChangeFeedID]*model.ChangeFeedStatus
+		err      error
+	)
+	if o.apiV2Client != nil {
+		infos, statuses, err = o.getChangefeedsWithAPIV2Client(ctx)
+	} else {
+		infos, err = o.etcdClient.GetAllChangeFeedInfo(ctx)
+		if err == nil {
+			statuses, err = o.etcdClient.GetAllChangeFeedStatus(ctx)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	bundle, err := o.newChangefeedBundle(cmd, selector, infos, statuses)
+	if err != nil {
+		return err
+	}
+	data, err := bundle.Marshal()
+	if err != nil {
+		return err
+	}
+	if o.output == "" {
+		cmd.Println(string(data))
+		return nil
+	}
+	// The bundle contains the sink URIs, which may carry the credentials.
+	if err := os.WriteFile(o.output, data, 0o600); err != nil {
+		return errors.Trace(err)
+	}
+	cmd.Printf("Export %d changefeeds to %s\n", len(bundle.Changefeeds), o.output)
+	return nil
+}
+
+// getChangefeedsWithAPIV2Client gets the info and the status of
+// all the changefeeds by the open API v2.
+func (o *exportChangefeedOptions) getChangefeedsWithAPIV2Client(ctx context.Context) (
+	map[model.ChangeFeedID]*model.ChangeFeedInfo, map[model.ChangeFeedID]*model.ChangeFeedStatus, error,
+) {
+	items, err := o.apiV2Client.Changefeeds().List(ctx, "all")
+	if err != nil {
+		return nil, nil, err
+	}
+	infos := make(map[model.ChangeFeedID]*model.ChangeFeedInfo, len(items))
+	statuses := make(map[model.ChangeFeedID]*model.ChangeFeedStatus, len(items))
+	for _, item := range items {
+		detail, err := o.apiV2Client.Changefeeds().Get(ctx, item.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		infos[item.ID] = detail.Info
+		statuses[item.ID] = detail.Status
+	}
+	return infos, statuses, nil
+}
+
+// newChangefeedBundle bundles the changefeeds to export, ordered by the changefeed ID.
+// The removed, finished and failed changefeeds are skipped unless they are specified
+// explicitly, in which case an error is returned since they can not be resumed.
+func (o *exportChangefeedOptions) newChangefeedBundle(
+	cmd *cobra.Command,
+	selector model.LabelSelector,
+	infos map[model.ChangeFeedID]*model.ChangeFeedInfo,
+	statuses map[model.ChangeFeedID]*model.ChangeFeedStatus,
+) (*model.ChangefeedBundle, error) {
+	ids := append([]model.ChangeFeedID(nil), o.changefeedIDs...)
+	if len(ids) == 0 {
+		for id := range infos {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	bundle := model.NewChangefeedBundle()
+	for i, id := range ids {
+		if i > 0 && ids[i-1] == id {
+			continue
+		}
+		info, ok := infos[id]
+		if !ok {
+			return nil, cerror.ErrChangeFeedNotExists.GenWithStackByArgs(id)
+		}
+		if !selector.Matches(info.Labels) {
+			continue
+		}
+		switch info.State {
+		case model.StateNormal, model.StateStopped, model.StateError:
+		default:
+			if len(o.changefeedIDs) != 0 {
+				return nil, errors.Errorf("changefeed %s can not be exported in the %s state", id, info.State)
+			}
+			continue
+		}
+		if info.State != model.StateStopped {
+			cmd.PrintErrf("[WARN] changefeed %s is not stopped, please pause it before importing, "+
+				"otherwise the changefeeds in both clusters replicate to the same downstream\n", id)
+		}
+		bundle.Add(id, info, statuses[id])
+	}
+	return bundle, nil
+}
+
+// newCmdExportChangefeed creates the `cli changefeed export` command.
+func newCmdExportChangefeed(f factory.Factory) *cobra.Command {
+	o := newExportChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "export",
+		Short: "Export changefeeds as a bundle, which can be imported into another TiCDC cluster",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+
+	"github.com/pingcap/check"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util/testleak"
+	"github.com/spf13/cobra"
+)
+
+type changefeedExportSuite struct{}
+
+var _ = check.Suite(&changefeedExportSuite{})
+
+func (s *changefeedExportSuite) TestNewChangefeedBundle(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	newInfo := func(state model.FeedState, env string) *model.ChangeFeedInfo {
+		return &model.ChangeFeedInfo{
+			StartTs: 100,
+			Config:  config.GetDefaultReplicaConfig(),
+			State:   state,
+			Labels:  map[string]string{"env": env},
+		}
+	}
+	infos := map[model.ChangeFeedID]*model.ChangeFeedInfo{
+		"normal":   newInfo(model.StateNormal, "prod"),
+		"stopped":  newInfo(model.StateStopped, "test"),
+		"error":    newInfo(model.StateError, "prod"),
+		"removed":  newInfo(model.StateRemoved, "prod"),
+		"finished": newInfo(model.StateFinished, "prod"),
+		"failed":   newInfo(model.StateFailed, "prod"),
+	}
+	statuses := map[model.ChangeFeedID]*model.ChangeFeedStatus{
+		"normal":  {CheckpointTs: 200},
+		"stopped": {CheckpointTs: 300},
+	}
+	bundleIDs := func(bundle *model.ChangefeedBundle) []string {
+		ids := make([]string, 0, len(bundle.Changefeeds))
+		for _, cf := range bundle.Changefeeds {
+			ids = append(ids, cf.ID)
+		}
+		return ids
+	}
+
+	// The changefeeds that can not be resumed are skipped.
+	cmd := new(cobra.Command)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	o := newExportChangefeedOptions()
+	bundle, err := o.newChangefeedBundle(cmd, nil, infos, statuses)
+	c.Assert(err, check.IsNil)
+	// The warnings are not mixed with the bundle printed to stdout.
+	c.Assert(stdout.Len(), check.Equals, 0)
+	c.Assert(stderr.String(), check.Matches, "(?s).*changefeed error is not stopped.*changefeed normal is not stopped.*")
+	c.Assert(bundleIDs(bundle), check.DeepEquals, []string{"error", "normal", "stopped"})
+	c.Assert(bundle.Changefeeds[0].CheckpointTs, check.Equals, uint64(100))
+	c.Assert(bundle.Changefeeds[1].CheckpointTs, check.Equals, uint64(200))
+	c.Assert(bundle.Changefeeds[2].CheckpointTs, check.Equals, uint64(300))
+
+	// Filter the changefeeds by labels.
+	selector, err := model.ParseLabelSelector("env=prod")
+	c.Assert(err, check.IsNil)
+	bundle, err = o.newChangefeedBundle(cmd, selector, infos, statuses)
+	c.Assert(err, check.IsNil)
+	c.Assert(bundleIDs(bundle), check.DeepEquals, []string{"error", "normal"})
+
+	// Export the specified changefeeds.
+	o.changefeedIDs = []string{"stopped", "normal", "stopped"}
+	bundle, err = o.newChangefeedBundle(cmd, nil, infos, statuses)
+	c.Assert(err, check.IsNil)
+	c.Assert(bundleIDs(bundle), check.DeepEquals, []string{"normal", "stopped"})
+
+	o.changefeedIDs = []string{"finished"}
+	_, err = o.newChangefeedBundle(cmd, nil, infos, statuses)
+	c.Assert(err, check.ErrorMatches, ".*can not be exported in the finished state.*")
+
+	o.changefeedIDs = []string{"not-exist"}
+	_, err = o.newChangefeedBundle(cmd, nil, infos, statuses)
+	c.Assert(cerror.ErrChangeFeedNotExists.Equal(err), check.IsTrue)
+}
@@ -77,7 +77,8 @@ func confirmIgnoreIneligibleTables(cmd *cobra.Command) error {
 	return nil
 }
 
-// getTables returns ineligibleTables and eligibleTables by filter.
+// getTables returns ineligibleTables and eligibleTables by filter,
+// the columns used by the dispatch rules are verified as well.
 func getTables(cliPdAddr string, credential *security.Credential, cfg *config.ReplicaConfig, startTs uint64) (ineligibleTables, eligibleTables []model.TableName, err error) {
 	kvStore, err := kv.CreateTiStore(cliPdAddr, credential)
 	if err != nil {
@@ -87,9 +88,78 @@ func getTables(cliPdAddr string, credential *security.Credential, cfg *config.Re
 	return api.VerifyTables(cfg, kvStore, startTs)
 }
 
+// verifyDispatchColumns checks whether the columns used by the dispatch rules
+// exist in the tables at the current version.
+func verifyDispatchColumns(cliPdAddr string, credential *security.Credential, cfg *config.ReplicaConfig) error {
+	kvStore, err := kv.CreateTiStore(cliPdAddr, credential)
+	if err != nil {
+		return err
+	}
+
+	return api.VerifyDispatchColumns(cfg, kvStore)
+}
+
+// newChangefeedResp builds the simplified status of a changefeed returned by
+// the open API v2, it is the same as the one returned by the owner.
+func newChangefeedResp(
+	state model.FeedState, status *model.ChangeFeedStatus, runningError *model.RunningError,
+) *api.ChangefeedResp {
+	resp := &api.ChangefeedResp{
+		FeedState:    string(state),
+		RunningError: runningError,
+	}
+	if status != nil {
+		resp.TSO = status.CheckpointTs
+		tm := oracle.GetTimeFromTS(status.CheckpointTs)
+		resp.Checkpoint = tm.Format("2006-01-02 15:04:05.000")
+	}
+	return resp
+}
+
+// parseLabels parses the labels in the `key=value` format.
+func parseLabels(pairs []string) (map[string]string, error) {
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid label %s, it must be in the `key=value` format", pair)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	if err := model.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// checkChangefeedOrSelector checks that exactly one of the changefeed ID and
+// the label selector is specified, the selector is only supported by the open API.
+func checkChangefeedOrSelector(changefeedID, selector, serverAddr string) error {
+	if (changefeedID == "") == (selector == "") {
+		return errors.New("exactly one of --changefeed-id and --selector must be specified")
+	}
+	if selector != "" && serverAddr == "" {
+		return errors.New("the label selector is only supported by the open API, please specify --server")
+	}
+	return nil
+}
+
+// printBatchChangefeedResult prints the result of a batch changefeed
+// operation, an error is returned if the operation fails on any changefeed.
+func printBatchChangefeedResult(cmd *cobra.Command, result *model.BatchChangefeedResultV2) error {
+	if err := util.JSONPrint(cmd, result); err != nil {
+		return err
+	}
+	if len(result.Failed) != 0 {
+		return errors.Errorf("failed on %d of %d changefeeds",
+			len(result.Failed), len(result.Failed)+len(result.Succeeded))
+	}
+	return nil
+}
+
 // sendOwnerChangefeedQuery sends owner changefeed query request.
 func sendOwnerChangefeedQuery(ctx context.Context, etcdClient *etcd.CDCEtcdClient,
-	id model.ChangeFeedID, credential *security.Credential,
+	id model.ChangeFeedID, credential *security.Credential, auth *httputil.Auth,
 ) (string, error) {
 	owner, err := getOwnerCapture(ctx, etcdClient)
 	if err != nil {
@@ -106,6 +176,7 @@ func sendOwnerChangefeedQuery(ctx context.Context, etcdClient *etcd.CDCEtcdClien
 	if err != nil {
 		return "", err
 	}
+	httpClient.SetAuth(auth)
 
 	resp, err := httpClient.PostForm(url, map[string][]string{
 		api.OpVarChangefeedID: {id},
@@ -127,7 +198,9 @@ func sendOwnerChangefeedQuery(ctx context.Context, etcdClient *etcd.CDCEtcdClien
 }
 
 // sendOwnerAdminChangeQuery sends owner admin query request.
-func sendOwnerAdminChangeQuery(ctx context.Context, etcdClient *etcd.CDCEtcdClient, job model.AdminJob, credential *security.Credential) error {
+func sendOwnerAdminChangeQuery(ctx context.Context, etcdClient *etcd.CDCEtcdClient,
+	job model.AdminJob, credential *security.Credential, auth *httputil.Auth,
+) error {
 	owner, err := getOwnerCapture(ctx, etcdClient)
 	if err != nil {
 		return err
@@ -143,6 +216,7 @@ func sendOwnerAdminChangeQuery(ctx context.Context, etcdClient *etcd.CDCEtcdClie
 	if err != nil {
 		return err
 	}
+	httpClient.SetAuth(auth)
 
 	forceRemoveOpt := "false"
 	if job.Opts != nil && job.Opts.ForceRemove {
@@ -89,3 +89,22 @@ func (s *changefeedHelperSuite) TestConfirmIgnoreIneligibleTables(c *check.C) {
 	err = confirmIgnoreIneligibleTables(cmd)
 	c.Assert(err, check.IsNil)
 }
+
+func (s *changefeedHelperSuite) TestParseLabels(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	labels, err := parseLabels(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(labels, check.HasLen, 0)
+
+	labels, err = parseLabels([]string{"env=prod", "team=dba=1", "tier="})
+	c.Assert(err, check.ErrorMatches, ".*invalid changefeed label.*")
+	c.Assert(labels, check.IsNil)
+
+	labels, err = parseLabels([]string{"env=prod", "tier="})
+	c.Assert(err, check.IsNil)
+	c.Assert(labels, check.DeepEquals, map[string]string{"env": "prod", "tier": ""})
+
+	_, err = parseLabels([]string{"env"})
+	c.Assert(err, check.ErrorMatches, "invalid label env.*")
+}
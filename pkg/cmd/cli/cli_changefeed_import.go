@@ -0,0 +1,192 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	ticdcutil "github.com/pingcap/tiflow/pkg/util"
+	"github.com/spf13/cobra"
+	pd "github.com/tikv/pd/client"
+	"go.uber.org/zap"
+)
+
+// importChangefeedOptions defines flags for the `cli changefeed import` command.
+type importChangefeedOptions struct {
+	etcdClient  *etcd.CDCEtcdClient
+	pdClient    pd.Client
+	apiV2Client apiv2client.APIV2Interface
+
+	input    string
+	timezone string
+	dryRun   bool
+}
+
+// newImportChangefeedOptions creates new options for the `cli changefeed import` command.
+func newImportChangefeedOptions() *importChangefeedOptions {
+	return &importChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *importChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&o.input, "input", "", "Path of the changefeed bundle exported by `cdc cli changefeed export`")
+	cmd.PersistentFlags().StringVar(&o.timezone, "tz", "SYSTEM", "timezone used when checking sink uri (changefeed timezone is determined by cdc server)")
+	cmd.PersistentFlags().BoolVar(&o.dryRun, "dry-run", false, "Only check the changefeeds can be imported without creating them")
+	_ = cmd.MarkPersistentFlagRequired("input")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *importChangefeedOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
+	etcdClient, err := f.EtcdClient()
+	if err != nil {
+		return err
+	}
+	o.etcdClient = etcdClient
+
+	pdClient, err := f.PdClient()
+	if err != nil {
+		return err
+	}
+	o.pdClient = pdClient
+	return nil
+}
+
+// run the `cli changefeed import` command.
+func (o *importChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	data, err := os.ReadFile(o.input)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	bundle := new(model.ChangefeedBundle)
+	if err := bundle.Unmarshal(data); err != nil {
+		return err
+	}
+
+	// The changefeeds are imported one by one, a failure does not stop the others.
+	result := &model.BatchChangefeedResultV2{Succeeded: make([]string, 0, len(bundle.Changefeeds))}
+	for _, cf := range bundle.Changefeeds {
+		if o.apiV2Client != nil {
+			err = o.importWithAPIV2Client(ctx, cf)
+		} else {
+			err = o.importChangefeed(ctx, cf)
+		}
+		if err != nil {
+			log.Warn("import changefeed failed",
+				zap.String("changefeed", cf.ID), zap.Error(err))
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[cf.ID] = err.Error()
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, cf.ID)
+	}
+	if !o.dryRun && len(result.Succeeded) != 0 {
+		cmd.Printf("The imported changefeeds are paused, " +
+			"resume them by `cdc cli changefeed resume` after the changefeeds in the old cluster are stopped\n")
+	}
+	return printBatchChangefeedResult(cmd, result)
+}
+
+// importChangefeed checks that the GC safepoint of the new cluster does not
+// exceed the checkpoint ts and that the sink is reachable, then creates
+// the changefeed in the stopped state.
+func (o *importChangefeedOptions) importChangefeed(ctx context.Context, cf *model.ExportedChangefeed) error {
+	info, err := cf.ImportInfo()
+	if err != nil {
+		return err
+	}
+	if info.TargetTs > 0 && info.TargetTs <= info.StartTs {
+		return cerror.ErrTargetTsBeforeStartTs.GenWithStackByArgs(info.TargetTs, info.StartTs)
+	}
+	if o.dryRun {
+		// Only read the GC safepoint, nothing is created to protect.
+		err = gc.CheckChangefeedStartTsSafety(ctx, o.pdClient, cf.ID, info.StartTs)
+	} else {
+		// Ensure the checkpoint ts is valid in the next 1 hour,
+		// the owner takes over the GC safepoint after the changefeed is created.
+		const ensureTTL = 60 * 60
+		err = gc.EnsureChangefeedStartTsSafety(ctx, o.pdClient, cf.ID, ensureTTL, info.StartTs)
+	}
+	if err != nil {
+		return err
+	}
+
+	tz, err := ticdcutil.GetTimezone(o.timezone)
+	if err != nil {
+		return errors.Annotate(err, "can not load timezone, Please specify the time zone through environment variable `TZ` or command line parameters `--tz`")
+	}
+	if err := sink.Validate(ticdcutil.PutTimezoneInCtx(ctx, tz), info.SinkURI, info.Config, info.Opts); err != nil {
+		return err
+	}
+	if o.dryRun {
+		return nil
+	}
+	return o.etcdClient.CreateChangefeedInfo(ctx, info, cf.ID)
+}
+
+// importWithAPIV2Client imports a changefeed by the open API v2,
+// the server checks the GC safepoint and the sink as importChangefeed does.
+func (o *importChangefeedOptions) importWithAPIV2Client(ctx context.Context, cf *model.ExportedChangefeed) error {
+	cfg := cf.ImportConfigV2()
+	cfg.TimeZone = o.timezone
+	_, err := o.apiV2Client.Changefeeds().Create(ctx, cfg, o.dryRun)
+	return err
+}
+
+// newCmdImportChangefeed creates the `cli changefeed import` command.
+func newCmdImportChangefeed(f factory.Factory) *cobra.Command {
+	o := newImportChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "import",
+		Short: "Import the changefeeds exported from another TiCDC cluster, the changefeeds are created in the paused state",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
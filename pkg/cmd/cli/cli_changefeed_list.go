@@ -14,14 +14,18 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/api"
-	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -35,9 +39,11 @@ type changefeedCommonInfo struct {
 
 // listChangefeedOptions defines flags for the `cli changefeed list` command.
 type listChangefeedOptions struct {
-	etcdClient *etcd.CDCEtcdClient
+	etcdClient  *etcd.CDCEtcdClient
+	apiV2Client apiv2client.APIV2Interface
 
 	credential *security.Credential
+	auth       *httputil.Auth
 
 	listAll bool
 }
@@ -55,6 +61,15 @@ func (o *listChangefeedOptions) addFlags(cmd *cobra.Command) {
 
 // complete adapts from the command line args to the data and client required.
 func (o *listChangefeedOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -63,13 +78,18 @@ func (o *listChangefeedOptions) complete(f factory.Factory) error {
 	o.etcdClient = etcdClient
 
 	o.credential = f.GetCredential()
+	o.auth = f.GetAuth()
 
 	return nil
 }
 
 // run the `cli changefeed list` command.
 func (o *listChangefeedOptions) run(cmd *cobra.Command) error {
-	ctx := context.GetDefaultContext()
+	ctx := cmdcontext.GetDefaultContext()
+
+	if o.apiV2Client != nil {
+		return o.runWithAPIV2Client(ctx, cmd)
+	}
 
 	_, raw, err := o.etcdClient.GetChangeFeeds(ctx)
 	if err != nil {
@@ -96,7 +116,7 @@ func (o *listChangefeedOptions) run(cmd *cobra.Command) error {
 	for id := range changefeedIDs {
 		cfci := &changefeedCommonInfo{ID: id}
 
-		resp, err := sendOwnerChangefeedQuery(ctx, o.etcdClient, id, o.credential)
+		resp, err := sendOwnerChangefeedQuery(ctx, o.etcdClient, id, o.credential, o.auth)
 		if err != nil {
 			// if no capture is available, the query will fail, just add a warning here
 			log.Warn("query changefeed info failed", zap.String("error", err.Error()))
@@ -115,6 +135,28 @@ func (o *listChangefeedOptions) run(cmd *cobra.Command) error {
 	return util.JSONPrint(cmd, cfs)
 }
 
+// runWithAPIV2Client lists the changefeeds by the open API v2.
+func (o *listChangefeedOptions) runWithAPIV2Client(ctx context.Context, cmd *cobra.Command) error {
+	state := ""
+	if o.listAll {
+		state = "all"
+	}
+	items, err := o.apiV2Client.Changefeeds().List(ctx, state)
+	if err != nil {
+		return err
+	}
+
+	cfs := make([]*changefeedCommonInfo, 0, len(items))
+	for _, item := range items {
+		status := &model.ChangeFeedStatus{CheckpointTs: item.CheckpointTSO}
+		cfs = append(cfs, &changefeedCommonInfo{
+			ID:      item.ID,
+			Summary: newChangefeedResp(item.FeedState, status, item.RunningError),
+		})
+	}
+	return util.JSONPrint(cmd, cfs)
+}
+
 // newCmdListChangefeed creates the `cli changefeed list` command.
 func newCmdListChangefeed(f factory.Factory) *cobra.Command {
 	o := newListChangefeedOptions()
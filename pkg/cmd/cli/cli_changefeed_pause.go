@@ -15,20 +15,25 @@ package cli
 
 import (
 	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	"github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/spf13/cobra"
 )
 
 // pauseChangefeedOptions defines flags for the `cli changefeed pause` command.
 type pauseChangefeedOptions struct {
-	etcdClient *etcd.CDCEtcdClient
+	etcdClient  *etcd.CDCEtcdClient
+	apiV2Client apiv2client.APIV2Interface
 
 	credential *security.Credential
+	auth       *httputil.Auth
 
 	changefeedID string
+	selector     string
 }
 
 // newPauseChangefeedOptions creates new options for the `cli changefeed pause` command.
@@ -40,11 +45,23 @@ func newPauseChangefeedOptions() *pauseChangefeedOptions {
 // flags related to template printing to it.
 func (o *pauseChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
-	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	cmd.PersistentFlags().StringVarP(&o.selector, "selector", "l", "", "Pause all the changefeeds whose labels match the selector, such as env=prod,team!=dba")
 }
 
 // complete adapts from the command line args to the data and client required.
 func (o *pauseChangefeedOptions) complete(f factory.Factory) error {
+	if err := checkChangefeedOrSelector(o.changefeedID, o.selector, f.GetServerAddr()); err != nil {
+		return err
+	}
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -53,12 +70,24 @@ func (o *pauseChangefeedOptions) complete(f factory.Factory) error {
 	o.etcdClient = etcdClient
 
 	o.credential = f.GetCredential()
+	o.auth = f.GetAuth()
 
 	return nil
 }
 
 // run the `cli changefeed pause` command.
-func (o *pauseChangefeedOptions) run() error {
+func (o *pauseChangefeedOptions) run(cmd *cobra.Command) error {
+	if o.selector != "" {
+		result, err := o.apiV2Client.Changefeeds().BatchPause(context.GetDefaultContext(), o.selector)
+		if err != nil {
+			return err
+		}
+		return printBatchChangefeedResult(cmd, result)
+	}
+	if o.apiV2Client != nil {
+		return o.apiV2Client.Changefeeds().Pause(context.GetDefaultContext(), o.changefeedID)
+	}
+
 	job := model.AdminJob{
 		CfID: o.changefeedID,
 		Type: model.AdminStop,
@@ -66,7 +95,7 @@ func (o *pauseChangefeedOptions) run() error {
 
 	ctx := context.GetDefaultContext()
 
-	return sendOwnerAdminChangeQuery(ctx, o.etcdClient, job, o.credential)
+	return sendOwnerAdminChangeQuery(ctx, o.etcdClient, job, o.credential, o.auth)
 }
 
 // newCmdPauseChangefeed creates the `cli changefeed pause` command.
@@ -75,7 +104,7 @@ func newCmdPauseChangefeed(f factory.Factory) *cobra.Command {
 
 	command := &cobra.Command{
 		Use:   "pause",
-		Short: "Pause a replication task (changefeed)",
+		Short: "Pause a replication task (changefeed), or the ones selected by labels",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.complete(f)
@@ -83,7 +112,7 @@ func newCmdPauseChangefeed(f factory.Factory) *cobra.Command {
 				return err
 			}
 
-			return o.run()
+			return o.run(cmd)
 		},
 	}
 
@@ -14,13 +14,17 @@
 package cli
 
 import (
+	"context"
+
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
-	"github.com/pingcap/tiflow/pkg/cmd/context"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -42,9 +46,11 @@ type cfMeta struct {
 
 // queryChangefeedOptions defines flags for the `cli changefeed query` command.
 type queryChangefeedOptions struct {
-	etcdClient *etcd.CDCEtcdClient
+	etcdClient  *etcd.CDCEtcdClient
+	apiV2Client apiv2client.APIV2Interface
 
 	credential *security.Credential
+	auth       *httputil.Auth
 
 	changefeedID string
 	simplified   bool
@@ -65,6 +71,15 @@ func (o *queryChangefeedOptions) addFlags(cmd *cobra.Command) {
 
 // complete adapts from the command line args to the data and client required.
 func (o *queryChangefeedOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -73,16 +88,21 @@ func (o *queryChangefeedOptions) complete(f factory.Factory) error {
 	o.etcdClient = etcdClient
 
 	o.credential = f.GetCredential()
+	o.auth = f.GetAuth()
 
 	return nil
 }
 
 // run the `cli changefeed query` command.
 func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
-	ctx := context.GetDefaultContext()
+	ctx := cmdcontext.GetDefaultContext()
+
+	if o.apiV2Client != nil {
+		return o.runWithAPIV2Client(ctx, cmd)
+	}
 
 	if o.simplified {
-		resp, err := sendOwnerChangefeedQuery(ctx, o.etcdClient, o.changefeedID, o.credential)
+		resp, err := sendOwnerChangefeedQuery(ctx, o.etcdClient, o.changefeedID, o.credential, o.auth)
 		if err != nil {
 			return err
 		}
@@ -135,6 +155,41 @@ func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 	return util.JSONPrint(cmd, meta)
 }
 
+// runWithAPIV2Client queries the changefeed by the open API v2.
+func (o *queryChangefeedOptions) runWithAPIV2Client(ctx context.Context, cmd *cobra.Command) error {
+	detail, err := o.apiV2Client.Changefeeds().Get(ctx, o.changefeedID)
+	if err != nil {
+		return err
+	}
+
+	if o.simplified {
+		return util.JSONPrint(cmd, newChangefeedResp(detail.Info.State, detail.Status, detail.Info.Error))
+	}
+
+	taskStatus := make([]captureTaskStatus, 0, len(detail.TaskStatus))
+	for _, status := range detail.TaskStatus {
+		tables := make(map[model.TableID]*model.TableReplicaInfo, len(status.Tables))
+		for _, tableID := range status.Tables {
+			tables[tableID] = &model.TableReplicaInfo{}
+		}
+		taskStatus = append(taskStatus, captureTaskStatus{
+			CaptureID: status.CaptureID,
+			TaskStatus: &model.TaskStatus{
+				Tables:    tables,
+				Operation: status.Operation,
+			},
+		})
+	}
+
+	meta := &cfMeta{
+		Info:       detail.Info,
+		Status:     detail.Status,
+		Count:      detail.Count,
+		TaskStatus: taskStatus,
+	}
+	return util.JSONPrint(cmd, meta)
+}
+
 // newCmdQueryChangefeed creates the `cli changefeed query` command.
 func newCmdQueryChangefeed(f factory.Factory) *cobra.Command {
 	o := newQueryChangefeedOptions()
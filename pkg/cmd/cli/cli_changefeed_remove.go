@@ -15,20 +15,25 @@ package cli
 
 import (
 	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	"github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/spf13/cobra"
 )
 
 // removeChangefeedOptions defines flags for the `cli changefeed remove` command.
 type removeChangefeedOptions struct {
-	etcdClient *etcd.CDCEtcdClient
+	etcdClient  *etcd.CDCEtcdClient
+	apiV2Client apiv2client.APIV2Interface
 
 	credential *security.Credential
+	auth       *httputil.Auth
 
 	changefeedID   string
+	selector       string
 	optForceRemove bool
 }
 
@@ -41,12 +46,24 @@ func newRemoveChangefeedOptions() *removeChangefeedOptions {
 // flags related to template printing to it.
 func (o *removeChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVarP(&o.selector, "selector", "l", "", "Remove all the changefeeds whose labels match the selector, such as env=prod,team!=dba")
 	cmd.PersistentFlags().BoolVarP(&o.optForceRemove, "force", "f", false, "remove all information of the changefeed")
-	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
 
 // complete adapts from the command line args to the data and client required.
 func (o *removeChangefeedOptions) complete(f factory.Factory) error {
+	if err := checkChangefeedOrSelector(o.changefeedID, o.selector, f.GetServerAddr()); err != nil {
+		return err
+	}
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -55,12 +72,26 @@ func (o *removeChangefeedOptions) complete(f factory.Factory) error {
 	o.etcdClient = etcdClient
 
 	o.credential = f.GetCredential()
+	o.auth = f.GetAuth()
 
 	return nil
 }
 
 // run the `cli changefeed remove` command.
-func (o *removeChangefeedOptions) run() error {
+func (o *removeChangefeedOptions) run(cmd *cobra.Command) error {
+	if o.selector != "" {
+		result, err := o.apiV2Client.Changefeeds().BatchRemove(
+			context.GetDefaultContext(), o.selector, o.optForceRemove)
+		if err != nil {
+			return err
+		}
+		return printBatchChangefeedResult(cmd, result)
+	}
+	if o.apiV2Client != nil {
+		return o.apiV2Client.Changefeeds().Remove(
+			context.GetDefaultContext(), o.changefeedID, o.optForceRemove)
+	}
+
 	job := model.AdminJob{
 		CfID: o.changefeedID,
 		Type: model.AdminRemove,
@@ -71,7 +102,7 @@ func (o *removeChangefeedOptions) run() error {
 
 	ctx := context.GetDefaultContext()
 
-	return sendOwnerAdminChangeQuery(ctx, o.etcdClient, job, o.credential)
+	return sendOwnerAdminChangeQuery(ctx, o.etcdClient, job, o.credential, o.auth)
 }
 
 // newCmdRemoveChangefeed creates the `cli changefeed remove` command.
@@ -80,7 +111,7 @@ func newCmdRemoveChangefeed(f factory.Factory) *cobra.Command {
 
 	command := &cobra.Command{
 		Use:   "remove",
-		Short: "Remove a replication task (changefeed)",
+		Short: "Remove a replication task (changefeed), or the ones selected by labels",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.complete(f)
@@ -88,7 +119,7 @@ func newCmdRemoveChangefeed(f factory.Factory) *cobra.Command {
 				return err
 			}
 
-			return o.run()
+			return o.run(cmd)
 		},
 	}
 
@@ -19,9 +19,11 @@ import (
 
 	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/spf13/cobra"
 	pd "github.com/tikv/pd/client"
@@ -29,12 +31,15 @@ import (
 
 // resumeChangefeedOptions defines flags for the `cli changefeed resume` command.
 type resumeChangefeedOptions struct {
-	etcdClient *etcd.CDCEtcdClient
-	pdClient   pd.Client
+	etcdClient  *etcd.CDCEtcdClient
+	pdClient    pd.Client
+	apiV2Client apiv2client.APIV2Interface
 
 	credential *security.Credential
+	auth       *httputil.Auth
 
 	changefeedID string
+	selector     string
 	noConfirm    bool
 }
 
@@ -47,12 +52,24 @@ func newResumeChangefeedOptions() *resumeChangefeedOptions {
 // flags related to template printing to it.
 func (o *resumeChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVarP(&o.selector, "selector", "l", "", "Resume all the changefeeds whose labels match the selector, such as env=prod,team!=dba")
 	cmd.PersistentFlags().BoolVar(&o.noConfirm, "no-confirm", false, "Don't ask user whether to ignore ineligible table")
-	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
 
 // complete adapts from the command line args to the data and client required.
 func (o *resumeChangefeedOptions) complete(f factory.Factory) error {
+	if err := checkChangefeedOrSelector(o.changefeedID, o.selector, f.GetServerAddr()); err != nil {
+		return err
+	}
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -68,13 +85,29 @@ func (o *resumeChangefeedOptions) complete(f factory.Factory) error {
 	o.pdClient = pdClient
 
 	o.credential = f.GetCredential()
+	o.auth = f.GetAuth()
 
 	return nil
 }
 
 // confirmResumeChangefeedCheck prompts the user to confirm the use of a large data gap when noConfirm is turned off.
 func (o *resumeChangefeedOptions) confirmResumeChangefeedCheck(ctx context.Context, cmd *cobra.Command) error {
-	resp, err := sendOwnerChangefeedQuery(ctx, o.etcdClient, o.changefeedID, o.credential)
+	if o.apiV2Client != nil {
+		detail, err := o.apiV2Client.Changefeeds().Get(ctx, o.changefeedID)
+		if err != nil {
+			return err
+		}
+		if o.noConfirm {
+			return nil
+		}
+		tso, err := o.apiV2Client.Tso().Query(ctx)
+		if err != nil {
+			return err
+		}
+		return confirmLargeDataGap(cmd, tso.PhysicalTime, detail.Status.CheckpointTs)
+	}
+
+	resp, err := sendOwnerChangefeedQuery(ctx, o.etcdClient, o.changefeedID, o.credential, o.auth)
 	if err != nil {
 		return err
 	}
@@ -101,16 +134,29 @@ func (o *resumeChangefeedOptions) confirmResumeChangefeedCheck(ctx context.Conte
 func (o *resumeChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
 
+	if o.selector != "" {
+		// The lag of every changefeed is not confirmed in batch.
+		result, err := o.apiV2Client.Changefeeds().BatchResume(ctx, o.selector)
+		if err != nil {
+			return err
+		}
+		return printBatchChangefeedResult(cmd, result)
+	}
+
 	if err := o.confirmResumeChangefeedCheck(ctx, cmd); err != nil {
 		return err
 	}
 
+	if o.apiV2Client != nil {
+		return o.apiV2Client.Changefeeds().Resume(ctx, o.changefeedID)
+	}
+
 	job := model.AdminJob{
 		CfID: o.changefeedID,
 		Type: model.AdminResume,
 	}
 
-	return sendOwnerAdminChangeQuery(ctx, o.etcdClient, job, o.credential)
+	return sendOwnerAdminChangeQuery(ctx, o.etcdClient, job, o.credential, o.auth)
 }
 
 // newCmdResumeChangefeed creates the `cli changefeed resume` command.
@@ -119,7 +165,7 @@ func newCmdResumeChangefeed(f factory.Factory) *cobra.Command {
 
 	command := &cobra.Command{
 		Use:   "resume",
-		Short: "Resume a paused replication task (changefeed)",
+		Short: "Resume a paused replication task (changefeed), or the ones selected by labels",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.complete(f)
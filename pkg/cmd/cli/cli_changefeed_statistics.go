@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	apiv1client "github.com/pingcap/tiflow/pkg/api/v1"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
@@ -43,9 +44,10 @@ type status struct {
 
 // statisticsChangefeedOptions defines flags for the `cli changefeed statistics` command.
 type statisticsChangefeedOptions struct {
-	etcdClient *etcd.CDCEtcdClient
-	pdClient   pd.Client
-	apiClient  apiv1client.APIV1Interface
+	etcdClient  *etcd.CDCEtcdClient
+	pdClient    pd.Client
+	apiClient   apiv1client.APIV1Interface
+	apiV2Client apiv2client.APIV2Interface
 
 	changefeedID     string
 	interval         uint
@@ -67,6 +69,15 @@ func (o *statisticsChangefeedOptions) addFlags(cmd *cobra.Command) {
 
 // complete adapts from the command line args to the data and client required.
 func (o *statisticsChangefeedOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -80,7 +91,7 @@ func (o *statisticsChangefeedOptions) complete(f factory.Factory) error {
 		return err
 	}
 
-	o.apiClient, err = apiv1client.NewAPIClient(owner.AdvertiseAddr, nil)
+	o.apiClient, err = apiv1client.NewAPIClient(owner.AdvertiseAddr, nil, f.GetAuth())
 	if err != nil {
 		return err
 	}
@@ -189,6 +200,27 @@ func (o *statisticsChangefeedOptions) runCliWithAPIClient(ctx context.Context, c
 	return util.JSONPrint(cmd, statistics)
 }
 
+// run cli command with api v2 client
+func (o *statisticsChangefeedOptions) runCliWithAPIV2Client(ctx context.Context, cmd *cobra.Command, lastCount *uint64, lastTime *time.Time) error {
+	now := time.Now()
+	changefeedStatistics, err := o.apiV2Client.Changefeeds().Statistics(ctx, o.changefeedID)
+	if err != nil {
+		return err
+	}
+
+	count := changefeedStatistics.Count
+	statistics := status{
+		OPS:            (count - (*lastCount)) / uint64(now.Unix()-lastTime.Unix()),
+		SinkGap:        fmt.Sprintf("%dms", changefeedStatistics.SinkGap),
+		ReplicationGap: fmt.Sprintf("%dms", changefeedStatistics.ReplicationGap),
+		Count:          count,
+	}
+
+	*lastCount = count
+	*lastTime = now
+	return util.JSONPrint(cmd, statistics)
+}
+
 // run the `cli changefeed statistics` command.
 func (o *statisticsChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
@@ -204,7 +236,9 @@ func (o *statisticsChangefeedOptions) run(cmd *cobra.Command) error {
 				return err
 			}
 		case <-tick.C:
-			if o.runWithAPIClient {
+			if o.apiV2Client != nil {
+				_ = o.runCliWithAPIV2Client(ctx, cmd, &lastCount, &lastTime)
+			} else if o.runWithAPIClient {
 				_ = o.runCliWithAPIClient(ctx, cmd, &lastCount, &lastTime)
 			} else {
 				_ = o.runCliWithEtcdClient(ctx, cmd, &lastCount, &lastTime)
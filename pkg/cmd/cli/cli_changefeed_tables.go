@@ -0,0 +1,179 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv1client "github.com/pingcap/tiflow/pkg/api/v1"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// tableStatus holds the replication status of a table.
+type tableStatus struct {
+	TableID          int64  `json:"table-id"`
+	TableName        string `json:"table-name"`
+	CaptureID        string `json:"capture-id"`
+	State            string `json:"state"`
+	PullerResolvedTs uint64 `json:"puller-resolved-ts"`
+	SorterResolvedTs uint64 `json:"sorter-resolved-ts"`
+	SinkResolvedTs   uint64 `json:"sink-resolved-ts"`
+	CheckpointTs     uint64 `json:"checkpoint-ts"`
+	Lag              string `json:"lag"`
+	// Bottleneck is the stage of the table pipeline that contributes
+	// the most to the lag.
+	Bottleneck     string `json:"bottleneck"`
+	ReceivedEvents uint64 `json:"received-events"`
+	EmittedEvents  uint64 `json:"emitted-events"`
+}
+
+// newTableStatus converts the table status returned by the open API.
+func newTableStatus(status *model.TableStatus) *tableStatus {
+	return &tableStatus{
+		TableID:          status.TableID,
+		TableName:        status.TableName,
+		CaptureID:        status.CaptureID,
+		State:            status.State,
+		PullerResolvedTs: status.PullerResolvedTs,
+		SorterResolvedTs: status.SorterResolvedTs,
+		SinkResolvedTs:   status.SinkResolvedTs,
+		CheckpointTs:     status.CheckpointTs,
+		Lag:              fmt.Sprintf("%dms", status.Lag),
+		Bottleneck:       tableBottleneck(status),
+		ReceivedEvents:   status.ReceivedEvents,
+		EmittedEvents:    status.EmittedEvents,
+	}
+}
+
+// tableBottleneck returns the stage with the largest gap between its input
+// and its output, the lag of a table is the sum of the gaps of all stages.
+func tableBottleneck(status *model.TableStatus) string {
+	currentTs := oracle.ExtractPhysical(status.CheckpointTs) + status.Lag
+	stages := []struct {
+		name   string
+		input  int64
+		output uint64
+	}{
+		{"puller", currentTs, status.PullerResolvedTs},
+		{"sorter", oracle.ExtractPhysical(status.PullerResolvedTs), status.SorterResolvedTs},
+		{"mounter", oracle.ExtractPhysical(status.SorterResolvedTs), status.SinkResolvedTs},
+		{"sink", oracle.ExtractPhysical(status.SinkResolvedTs), status.CheckpointTs},
+	}
+	bottleneck, maxGap := "none", int64(0)
+	for _, stage := range stages {
+		if gap := stage.input - oracle.ExtractPhysical(stage.output); gap > maxGap {
+			bottleneck, maxGap = stage.name, gap
+		}
+	}
+	return bottleneck
+}
+
+// tablesChangefeedOptions defines flags for the `cli changefeed tables` command.
+type tablesChangefeedOptions struct {
+	apiClient   apiv1client.APIV1Interface
+	apiV2Client apiv2client.APIV2Interface
+
+	changefeedID string
+}
+
+// newTablesChangefeedOptions creates new options for the `cli changefeed tables` command.
+func newTablesChangefeedOptions() *tablesChangefeedOptions {
+	return &tablesChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *tablesChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *tablesChangefeedOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
+	etcdClient, err := f.EtcdClient()
+	if err != nil {
+		return err
+	}
+	owner, err := getOwnerCapture(cmdcontext.GetDefaultContext(), etcdClient)
+	if err != nil {
+		return err
+	}
+	o.apiClient, err = apiv1client.NewAPIClient(owner.AdvertiseAddr, f.GetCredential(), f.GetAuth())
+	return err
+}
+
+// run the `cli changefeed tables` command.
+func (o *tablesChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	statuses, err := o.listTables(ctx)
+	if err != nil {
+		return err
+	}
+	tables := make([]*tableStatus, 0, len(statuses))
+	for i := range statuses {
+		tables = append(tables, newTableStatus(&statuses[i]))
+	}
+	return util.JSONPrint(cmd, tables)
+}
+
+func (o *tablesChangefeedOptions) listTables(ctx context.Context) ([]model.TableStatus, error) {
+	if o.apiV2Client != nil {
+		return o.apiV2Client.Changefeeds().Tables(ctx, o.changefeedID)
+	}
+	statuses, err := o.apiClient.Changefeeds().Tables(ctx, o.changefeedID)
+	if err != nil {
+		return nil, err
+	}
+	return *statuses, nil
+}
+
+// newCmdTablesChangefeed creates the `cli changefeed tables` command.
+func newCmdTablesChangefeed(f factory.Factory) *cobra.Command {
+	o := newTablesChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "tables",
+		Short: "Show the replication status and the lag of every table of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
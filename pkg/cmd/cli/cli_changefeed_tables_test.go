@@ -0,0 +1,48 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/check"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/util/testleak"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+type changefeedTablesSuite struct{}
+
+var _ = check.Suite(&changefeedTablesSuite{})
+
+func (s *changefeedTablesSuite) TestTableBottleneck(c *check.C) {
+	defer testleak.AfterTest(c)()
+	newStatus := func(puller, sorter, sink, checkpoint int64) *model.TableStatus {
+		return &model.TableStatus{
+			PullerResolvedTs: oracle.ComposeTS(puller, 0),
+			SorterResolvedTs: oracle.ComposeTS(sorter, 0),
+			SinkResolvedTs:   oracle.ComposeTS(sink, 0),
+			CheckpointTs:     oracle.ComposeTS(checkpoint, 0),
+			Lag:              1000 - checkpoint,
+		}
+	}
+
+	c.Assert(tableBottleneck(newStatus(1000, 1000, 1000, 1000)), check.Equals, "none")
+	c.Assert(tableBottleneck(newStatus(100, 90, 80, 70)), check.Equals, "puller")
+	c.Assert(tableBottleneck(newStatus(990, 100, 90, 80)), check.Equals, "sorter")
+	c.Assert(tableBottleneck(newStatus(990, 980, 100, 90)), check.Equals, "mounter")
+	c.Assert(tableBottleneck(newStatus(990, 980, 970, 100)), check.Equals, "sink")
+
+	status := newTableStatus(newStatus(990, 980, 970, 100))
+	c.Assert(status.Lag, check.Equals, "900ms")
+	c.Assert(status.Bottleneck, check.Equals, "sink")
+}
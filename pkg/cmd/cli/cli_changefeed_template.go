@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/spf13/cobra"
+)
+
+// newCmdTemplateChangefeed creates the `cli changefeed template` command.
+func newCmdTemplateChangefeed(f factory.Factory) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:   "template",
+		Short: "Manage changefeed templates",
+		Args:  cobra.NoArgs,
+	}
+
+	cmds.AddCommand(newCmdCreateChangefeedTemplate(f))
+	cmds.AddCommand(newCmdUpdateChangefeedTemplate(f))
+	cmds.AddCommand(newCmdListChangefeedTemplate(f))
+	cmds.AddCommand(newCmdQueryChangefeedTemplate(f))
+	cmds.AddCommand(newCmdRemoveChangefeedTemplate(f))
+
+	return cmds
+}
+
+// newChangefeedTemplateClient returns the client of the changefeed templates,
+// the templates are only supported by the open API.
+func newChangefeedTemplateClient(f factory.Factory) (apiv2client.ChangefeedTemplateInterface, error) {
+	if f.GetServerAddr() == "" {
+		return nil, errors.New("the changefeed templates are only supported by the open API, please specify --server")
+	}
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return nil, err
+	}
+	return apiClient.ChangefeedTemplates(), nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/priority"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// changefeedTemplateOptions defines flags for the `cli changefeed template create`
+// and the `cli changefeed template update` commands.
+type changefeedTemplateOptions struct {
+	apiClient apiv2client.ChangefeedTemplateInterface
+
+	name              string
+	sinkURI           string
+	configFile        string
+	opts              []string
+	sortEngine        string
+	syncPointEnabled  bool
+	syncPointInterval time.Duration
+	priority          string
+	labels            []string
+}
+
+// newChangefeedTemplateOptions creates new changefeed template options.
+func newChangefeedTemplateOptions() *changefeedTemplateOptions {
+	return &changefeedTemplateOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *changefeedTemplateOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&o.name, "name", "", "Name of the changefeed template")
+	cmd.PersistentFlags().StringVar(&o.sinkURI, "sink-uri", "", "sink uri")
+	cmd.PersistentFlags().StringVar(&o.configFile, "config", "", "Path of the configuration file")
+	cmd.PersistentFlags().StringSliceVar(&o.opts, "opts", nil, "Extra options, in the `key=value` format")
+	cmd.PersistentFlags().StringVar(&o.sortEngine, "sort-engine", model.SortUnified, "sort engine used for data sort")
+	cmd.PersistentFlags().BoolVar(&o.syncPointEnabled, "sync-point", false, "(Experimental) Set and Record syncpoint in replication(default off)")
+	cmd.PersistentFlags().DurationVar(&o.syncPointInterval, "sync-interval", 10*time.Minute, "(Experimental) Set the interval for syncpoint in replication(default 10min)")
+	cmd.PersistentFlags().StringVar(&o.priority, "priority", "normal", "Priority of changefeed, one of high, normal and low")
+	cmd.PersistentFlags().StringSliceVar(&o.labels, "labels", nil, "Labels of changefeed used to select changefeeds in batch operations, in the `key=value` format")
+	_ = cmd.MarkPersistentFlagRequired("name")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *changefeedTemplateOptions) complete(f factory.Factory) error {
+	apiClient, err := newChangefeedTemplateClient(f)
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// applyFlags applies the flags set explicitly to the template.
+func (o *changefeedTemplateOptions) applyFlags(cmd *cobra.Command, tpl *model.ChangefeedTemplate) error {
+	var err error
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if err != nil {
+			return
+		}
+		switch flag.Name {
+		case "sink-uri":
+			tpl.SinkURI = o.sinkURI
+		case "config":
+			cfg := config.GetDefaultReplicaConfig()
+			if err = util.StrictDecodeFile(o.configFile, "TiCDC changefeed template", cfg); err != nil {
+				return
+			}
+			if _, err = filter.VerifyRules(cfg); err != nil {
+				return
+			}
+			tpl.ReplicaConfig = cfg
+		case "opts":
+			tpl.Opts = make(map[string]string, len(o.opts))
+			for _, opt := range o.opts {
+				kv := strings.SplitN(opt, "=", 2)
+				if len(kv) == 2 {
+					tpl.Opts[kv[0]] = kv[1]
+				} else {
+					tpl.Opts[kv[0]] = ""
+				}
+			}
+		case "sort-engine":
+			tpl.Engine = o.sortEngine
+		case "sync-point":
+			enabled := o.syncPointEnabled
+			tpl.SyncPointEnabled = &enabled
+		case "sync-interval":
+			tpl.SyncPointInterval = o.syncPointInterval
+		case "priority":
+			var pri priority.Priority
+			if pri, err = priority.Parse(o.priority); err != nil {
+				return
+			}
+			tpl.Priority = &pri
+		case "labels":
+			tpl.Labels, err = parseLabels(o.labels)
+		}
+	})
+	return errors.Trace(err)
+}
+
+// runCreate runs the `cli changefeed template create` command.
+func (o *changefeedTemplateOptions) runCreate(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+	tpl := &model.ChangefeedTemplate{Name: o.name}
+	if err := o.applyFlags(cmd, tpl); err != nil {
+		return err
+	}
+	tpl, err := o.apiClient.Create(ctx, tpl)
+	if err != nil {
+		return err
+	}
+	return util.JSONPrint(cmd, tpl)
+}
+
+// runUpdate runs the `cli changefeed template update` command,
+// only the flags set explicitly are changed.
+func (o *changefeedTemplateOptions) runUpdate(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+	tpl, err := o.apiClient.Get(ctx, o.name)
+	if err != nil {
+		return err
+	}
+	if err := o.applyFlags(cmd, tpl); err != nil {
+		return err
+	}
+	tpl, err = o.apiClient.Update(ctx, tpl)
+	if err != nil {
+		return err
+	}
+	return util.JSONPrint(cmd, tpl)
+}
+
+// newCmdCreateChangefeedTemplate creates the `cli changefeed template create` command.
+func newCmdCreateChangefeedTemplate(f factory.Factory) *cobra.Command {
+	o := newChangefeedTemplateOptions()
+
+	command := &cobra.Command{
+		Use:   "create",
+		Short: "Create a changefeed template",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.runCreate(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
+
+// newCmdUpdateChangefeedTemplate creates the `cli changefeed template update` command.
+func newCmdUpdateChangefeedTemplate(f factory.Factory) *cobra.Command {
+	o := newChangefeedTemplateOptions()
+
+	command := &cobra.Command{
+		Use:   "update",
+		Short: "Update a changefeed template",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.runUpdate(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pingcap/check"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/priority"
+	"github.com/pingcap/tiflow/pkg/util/testleak"
+	"github.com/spf13/cobra"
+)
+
+type changefeedTemplateSuite struct{}
+
+var _ = check.Suite(&changefeedTemplateSuite{})
+
+func (s *changefeedTemplateSuite) TestApplyFlags(c *check.C) {
+	defer testleak.AfterTest(c)()
+
+	dir := c.MkDir()
+	path := filepath.Join(dir, "config.toml")
+	content := `
+case-sensitive = false
+[filter]
+rules = ['*.*', '!test.*']
+`
+	c.Assert(os.WriteFile(path, []byte(content), 0o644), check.IsNil)
+
+	// Only the flags set explicitly are applied.
+	cmd := new(cobra.Command)
+	o := newChangefeedTemplateOptions()
+	o.addFlags(cmd)
+	c.Assert(cmd.ParseFlags([]string{
+		"--name=tpl", "--sink-uri=kafka://127.0.0.1:9092/topic",
+		"--config=" + path, "--opts=a=b", "--priority=high", "--labels=env=prod",
+	}), check.IsNil)
+	tpl := &model.ChangefeedTemplate{Name: "tpl", SyncPointInterval: time.Minute}
+	c.Assert(o.applyFlags(cmd, tpl), check.IsNil)
+	c.Assert(tpl.SinkURI, check.Equals, "kafka://127.0.0.1:9092/topic")
+	c.Assert(tpl.ReplicaConfig.CaseSensitive, check.IsFalse)
+	c.Assert(tpl.ReplicaConfig.Filter.Rules, check.DeepEquals, []string{"*.*", "!test.*"})
+	c.Assert(tpl.Opts, check.DeepEquals, map[string]string{"a": "b"})
+	c.Assert(*tpl.Priority, check.Equals, priority.High)
+	c.Assert(tpl.Labels, check.DeepEquals, map[string]string{"env": "prod"})
+	c.Assert(tpl.Engine, check.Equals, "")
+	c.Assert(tpl.SyncPointEnabled, check.IsNil)
+	c.Assert(tpl.SyncPointInterval, check.Equals, time.Minute)
+
+	// Invalid flags are rejected.
+	cmd = new(cobra.Command)
+	o = newChangefeedTemplateOptions()
+	o.addFlags(cmd)
+	c.Assert(cmd.ParseFlags([]string{"--priority=urgent"}), check.IsNil)
+	c.Assert(o.applyFlags(cmd, &model.ChangefeedTemplate{}), check.NotNil)
+
+	cmd = new(cobra.Command)
+	o = newChangefeedTemplateOptions()
+	o.addFlags(cmd)
+	c.Assert(cmd.ParseFlags([]string{"--labels=env"}), check.IsNil)
+	c.Assert(o.applyFlags(cmd, &model.ChangefeedTemplate{}), check.NotNil)
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// listChangefeedTemplateOptions defines flags for the `cli changefeed template list` command.
+type listChangefeedTemplateOptions struct {
+	apiClient apiv2client.ChangefeedTemplateInterface
+}
+
+// newListChangefeedTemplateOptions creates new options for the `cli changefeed template list` command.
+func newListChangefeedTemplateOptions() *listChangefeedTemplateOptions {
+	return &listChangefeedTemplateOptions{}
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *listChangefeedTemplateOptions) complete(f factory.Factory) error {
+	apiClient, err := newChangefeedTemplateClient(f)
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed template list` command.
+func (o *listChangefeedTemplateOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+	templates, err := o.apiClient.List(ctx)
+	if err != nil {
+		return err
+	}
+	return util.JSONPrint(cmd, templates)
+}
+
+// newCmdListChangefeedTemplate creates the `cli changefeed template list` command.
+func newCmdListChangefeedTemplate(f factory.Factory) *cobra.Command {
+	o := newListChangefeedTemplateOptions()
+
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List all changefeed templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	return command
+}
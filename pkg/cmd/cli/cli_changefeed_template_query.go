@@ -0,0 +1,84 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// queryChangefeedTemplateOptions defines flags for the `cli changefeed template query` command.
+type queryChangefeedTemplateOptions struct {
+	apiClient apiv2client.ChangefeedTemplateInterface
+
+	name string
+}
+
+// newQueryChangefeedTemplateOptions creates new options for the `cli changefeed template query` command.
+func newQueryChangefeedTemplateOptions() *queryChangefeedTemplateOptions {
+	return &queryChangefeedTemplateOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *queryChangefeedTemplateOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&o.name, "name", "", "Name of the changefeed template")
+	_ = cmd.MarkPersistentFlagRequired("name")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *queryChangefeedTemplateOptions) complete(f factory.Factory) error {
+	apiClient, err := newChangefeedTemplateClient(f)
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed template query` command.
+func (o *queryChangefeedTemplateOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+	tpl, err := o.apiClient.Get(ctx, o.name)
+	if err != nil {
+		return err
+	}
+	return util.JSONPrint(cmd, tpl)
+}
+
+// newCmdQueryChangefeedTemplate creates the `cli changefeed template query` command.
+func newCmdQueryChangefeedTemplate(f factory.Factory) *cobra.Command {
+	o := newQueryChangefeedTemplateOptions()
+
+	command := &cobra.Command{
+		Use:   "query",
+		Short: "Query a changefeed template",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/spf13/cobra"
+)
+
+// removeChangefeedTemplateOptions defines flags for the `cli changefeed template remove` command.
+type removeChangefeedTemplateOptions struct {
+	apiClient apiv2client.ChangefeedTemplateInterface
+
+	name string
+}
+
+// newRemoveChangefeedTemplateOptions creates new options for the `cli changefeed template remove` command.
+func newRemoveChangefeedTemplateOptions() *removeChangefeedTemplateOptions {
+	return &removeChangefeedTemplateOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *removeChangefeedTemplateOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&o.name, "name", "", "Name of the changefeed template")
+	_ = cmd.MarkPersistentFlagRequired("name")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *removeChangefeedTemplateOptions) complete(f factory.Factory) error {
+	apiClient, err := newChangefeedTemplateClient(f)
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed template remove` command.
+func (o *removeChangefeedTemplateOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+	if err := o.apiClient.Delete(ctx, o.name); err != nil {
+		return err
+	}
+	cmd.Printf("Changefeed template %s is removed\n", o.name)
+	return nil
+}
+
+// newCmdRemoveChangefeedTemplate creates the `cli changefeed template remove` command.
+func newCmdRemoveChangefeedTemplate(f factory.Factory) *cobra.Command {
+	o := newRemoveChangefeedTemplateOptions()
+
+	command := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a changefeed template",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -14,7 +14,9 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/pingcap/tiflow/pkg/etcd"
@@ -22,9 +24,12 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/r3labs/diff"
 	"github.com/spf13/cobra"
@@ -34,9 +39,12 @@ import (
 
 // updateChangefeedOptions defines common flags for the `cli changefeed update` command.
 type updateChangefeedOptions struct {
-	etcdClient *etcd.CDCEtcdClient
+	etcdClient  *etcd.CDCEtcdClient
+	apiV2Client apiv2client.APIV2Interface
 
+	pdAddr     string
 	credential *security.Credential
+	auth       *httputil.Auth
 
 	commonChangefeedOptions *changefeedCommonOptions
 	changefeedID            string
@@ -63,6 +71,15 @@ func (o *updateChangefeedOptions) addFlags(cmd *cobra.Command) {
 
 // complete adapts from the command line args to the data and client required.
 func (o *updateChangefeedOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -70,7 +87,9 @@ func (o *updateChangefeedOptions) complete(f factory.Factory) error {
 
 	o.etcdClient = etcdClient
 
+	o.pdAddr = f.GetPdAddr()
 	o.credential = f.GetCredential()
+	o.auth = f.GetAuth()
 
 	return nil
 }
@@ -79,7 +98,11 @@ func (o *updateChangefeedOptions) complete(f factory.Factory) error {
 func (o *updateChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
 
-	resp, err := sendOwnerChangefeedQuery(ctx, o.etcdClient, o.changefeedID, o.credential)
+	if o.apiV2Client != nil {
+		return o.runWithAPIV2Client(ctx, cmd)
+	}
+
+	resp, err := sendOwnerChangefeedQuery(ctx, o.etcdClient, o.changefeedID, o.credential, o.auth)
 	// if no cdc owner exists, allow user to update changefeed config
 	if err != nil && errors.Cause(err) != cerror.ErrOwnerNotFound {
 		return err
@@ -99,14 +122,95 @@ func (o *updateChangefeedOptions) run(cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
+	// The columns used by the dispatch rules must exist in the tables,
+	// the open API verifies them on the server.
+	if !reflect.DeepEqual(old.Config.Sink, newInfo.Config.Sink) {
+		if err := verifyDispatchColumns(o.pdAddr, o.credential, newInfo.Config); err != nil {
+			return err
+		}
+	}
+
+	confirmed, err := o.confirmChanges(old, newInfo, cmd)
+	if err != nil || !confirmed {
+		return err
+	}
 
-	changelog, err := diff.Diff(old, newInfo)
+	err = o.etcdClient.SaveChangeFeedInfo(ctx, newInfo, o.changefeedID)
 	if err != nil {
 		return err
 	}
+	infoStr, err := newInfo.Marshal()
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Update changefeed config successfully! "+
+		"\nID: %s\nInfo: %s\n", o.changefeedID, infoStr)
+
+	return nil
+}
+
+// runWithAPIV2Client updates the changefeed by the open API v2.
+func (o *updateChangefeedOptions) runWithAPIV2Client(ctx context.Context, cmd *cobra.Command) error {
+	detail, err := o.apiV2Client.Changefeeds().Get(ctx, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	old := detail.Info
+	if old.State != model.StateStopped {
+		return errors.Errorf("can only update changefeed config when it is stopped\nstate: %s", old.State)
+	}
+
+	newInfo, err := o.applyChanges(old, cmd)
+	if err != nil {
+		return err
+	}
+	confirmed, err := o.confirmChanges(old, newInfo, cmd)
+	if err != nil || !confirmed {
+		return err
+	}
+
+	// The labels are replaced as a whole if they are not nil,
+	// send an empty map to remove all the labels.
+	labels := newInfo.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	detail, err = o.apiV2Client.Changefeeds().Update(ctx, o.changefeedID, &model.ChangefeedConfigV2{
+		TargetTs:          newInfo.TargetTs,
+		SinkURI:           newInfo.SinkURI,
+		Engine:            newInfo.Engine,
+		Opts:              newInfo.Opts,
+		ReplicaConfig:     newInfo.Config,
+		SyncPointEnabled:  &newInfo.SyncPointEnabled,
+		SyncPointInterval: newInfo.SyncPointInterval,
+		Priority:          &newInfo.Priority,
+		Labels:            labels,
+	}, false)
+	if err != nil {
+		return err
+	}
+	infoStr, err := detail.Info.Marshal()
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Update changefeed config successfully! "+
+		"\nID: %s\nInfo: %s\n", o.changefeedID, infoStr)
+
+	return nil
+}
+
+// confirmChanges prints the diff of the changefeed config and asks the user
+// whether to apply it, false is returned if nothing should be updated.
+func (o *updateChangefeedOptions) confirmChanges(
+	oldInfo, newInfo *model.ChangeFeedInfo, cmd *cobra.Command,
+) (bool, error) {
+	changelog, err := diff.Diff(oldInfo, newInfo)
+	if err != nil {
+		return false, err
+	}
 	if len(changelog) == 0 {
 		cmd.Printf("changefeed config is the same with the old one, do nothing\n")
-		return nil
+		return false, nil
 	}
 	cmd.Printf("Diff of changefeed config:\n")
 	for _, change := range changelog {
@@ -118,26 +222,15 @@ func (o *updateChangefeedOptions) run(cmd *cobra.Command) error {
 		var yOrN string
 		_, err = fmt.Scan(&yOrN)
 		if err != nil {
-			return err
+			return false, err
 		}
 		if strings.ToLower(strings.TrimSpace(yOrN)) != "y" {
 			cmd.Printf("No update to changefeed.\n")
-			return nil
+			return false, nil
 		}
 	}
 
-	err = o.etcdClient.SaveChangeFeedInfo(ctx, newInfo, o.changefeedID)
-	if err != nil {
-		return err
-	}
-	infoStr, err := newInfo.Marshal()
-	if err != nil {
-		return err
-	}
-	cmd.Printf("Update changefeed config successfully! "+
-		"\nID: %s\nInfo: %s\n", o.changefeedID, infoStr)
-
-	return nil
+	return true, nil
 }
 
 // applyChanges applies the new changes to the old changefeed.
@@ -189,6 +282,19 @@ func (o *updateChangefeedOptions) applyChanges(oldInfo *model.ChangeFeedInfo, cm
 			newInfo.SyncPointEnabled = o.commonChangefeedOptions.syncPointEnabled
 		case "sync-interval":
 			newInfo.SyncPointInterval = o.commonChangefeedOptions.syncPointInterval
+		case "priority":
+			var pri priority.Priority
+			if pri, err = priority.Parse(o.commonChangefeedOptions.priority); err == nil {
+				newInfo.Priority = pri
+			}
+		case "labels":
+			var labels map[string]string
+			if labels, err = parseLabels(o.commonChangefeedOptions.labels); err == nil {
+				newInfo.Labels = nil
+				if len(labels) != 0 {
+					newInfo.Labels = labels
+				}
+			}
 		case "sort-dir":
 			log.Warn("this flag cannot be updated and will be ignored", zap.String("flagName", flag.Name))
 		case "changefeed-id", "no-confirm", "cyclic-filter-replica-ids":
@@ -197,7 +303,7 @@ func (o *updateChangefeedOptions) applyChanges(oldInfo *model.ChangeFeedInfo, cm
 		case "interact":
 			// Do nothing, this is a flags from the cli command
 			// we don't use it to update.
-		case "pd", "log-level", "key", "cert", "ca":
+		case "pd", "server", "log-level", "key", "cert", "ca":
 			// Do nothing, this is a flags from the cli command
 			// we don't use it to update, but we do use these flags.
 		default:
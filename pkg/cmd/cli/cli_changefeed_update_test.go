@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/check"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/pingcap/tiflow/pkg/util/testleak"
 )
 
@@ -43,6 +44,18 @@ func (s *changefeedUpdateSuite) TestApplyChanges(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(newInfo.SinkURI, check.Equals, "mysql://root@downstream-tidb:4000")
 
+	// Test update priority.
+	c.Assert(cmd.ParseFlags([]string{"--priority=high"}), check.IsNil)
+	newInfo, err = o.applyChanges(oldInfo, cmd)
+	c.Assert(err, check.IsNil)
+	c.Assert(newInfo.Priority, check.Equals, priority.High)
+
+	// Test update labels.
+	c.Assert(cmd.ParseFlags([]string{"--labels=env=prod,team=dba"}), check.IsNil)
+	newInfo, err = o.applyChanges(oldInfo, cmd)
+	c.Assert(err, check.IsNil)
+	c.Assert(newInfo.Labels, check.DeepEquals, map[string]string{"env": "prod", "team": "dba"})
+
 	// Test for cli command flags that should be ignored.
 	oldInfo = &model.ChangeFeedInfo{SortDir: "."}
 	c.Assert(cmd.ParseFlags([]string{"--interact"}), check.IsNil)
@@ -69,6 +82,11 @@ func (s *changefeedUpdateSuite) TestApplyChanges(c *check.C) {
 	file, err := os.ReadFile(filename)
 	c.Assert(err, check.IsNil)
 	c.Assert(strings.Contains(string(file), "this flag cannot be updated and will be ignored"), check.IsTrue)
+
+	// Test for invalid priority.
+	c.Assert(cmd.ParseFlags([]string{"--priority=urgent"}), check.IsNil)
+	_, err = o.applyChanges(oldInfo, cmd)
+	c.Assert(err, check.ErrorMatches, ".*invalid changefeed priority: urgent.*")
 }
 
 func initTestLogger(filename string) (func(), error) {
@@ -18,6 +18,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	apiv1client "github.com/pingcap/tiflow/pkg/api/v1"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
@@ -31,6 +32,7 @@ import (
 type listProcessorOptions struct {
 	etcdClient       *etcd.CDCEtcdClient
 	apiClient        apiv1client.APIV1Interface
+	apiV2Client      apiv2client.APIV2Interface
 	runWithAPIClient bool
 }
 
@@ -41,6 +43,15 @@ func newListProcessorOptions() *listProcessorOptions {
 
 // complete adapts from the command line args to the data and client required.
 func (o *listProcessorOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -53,7 +64,7 @@ func (o *listProcessorOptions) complete(f factory.Factory) error {
 		return err
 	}
 
-	o.apiClient, err = apiv1client.NewAPIClient(owner.AdvertiseAddr, nil)
+	o.apiClient, err = apiv1client.NewAPIClient(owner.AdvertiseAddr, nil, f.GetAuth())
 	if err != nil {
 		return err
 	}
@@ -80,6 +91,14 @@ func (o *listProcessorOptions) complete(f factory.Factory) error {
 // run runs the `cli processor list` command.
 func (o *listProcessorOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
+	if o.apiV2Client != nil {
+		processors, err := o.apiV2Client.Processors().List(ctx)
+		if err != nil {
+			return err
+		}
+		return util.JSONPrint(cmd, processors)
+	}
+
 	if o.runWithAPIClient {
 		processors, err := o.apiClient.Processors().List(ctx)
 		if err != nil {
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	apiv1client "github.com/pingcap/tiflow/pkg/api/v1"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
@@ -37,8 +38,9 @@ type processorMeta struct {
 
 // queryProcessorOptions defines flags for the `cli processor query` command.
 type queryProcessorOptions struct {
-	etcdClient *etcd.CDCEtcdClient
-	apiClient  apiv1client.APIV1Interface
+	etcdClient  *etcd.CDCEtcdClient
+	apiClient   apiv1client.APIV1Interface
+	apiV2Client apiv2client.APIV2Interface
 
 	changefeedID     string
 	captureID        string
@@ -52,6 +54,15 @@ func newQueryProcessorOptions() *queryProcessorOptions {
 
 // complete adapts from the command line args to the data and client required.
 func (o *queryProcessorOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -64,7 +75,7 @@ func (o *queryProcessorOptions) complete(f factory.Factory) error {
 		return err
 	}
 
-	o.apiClient, err = apiv1client.NewAPIClient(owner.AdvertiseAddr, nil)
+	o.apiClient, err = apiv1client.NewAPIClient(owner.AdvertiseAddr, nil, f.GetAuth())
 	if err != nil {
 		return err
 	}
@@ -116,7 +127,13 @@ func (o *queryProcessorOptions) runCliWithEtcdClient(ctx context.Context, cmd *c
 
 // run cli cmd with api client
 func (o *queryProcessorOptions) runCliWithAPIClient(ctx context.Context, cmd *cobra.Command) error {
-	processor, err := o.apiClient.Processors().Get(ctx, o.changefeedID, o.captureID)
+	var processor *model.ProcessorDetail
+	var err error
+	if o.apiV2Client != nil {
+		processor, err = o.apiV2Client.Processors().Get(ctx, o.changefeedID, o.captureID)
+	} else {
+		processor, err = o.apiClient.Processors().Get(ctx, o.changefeedID, o.captureID)
+	}
 	if err != nil {
 		return err
 	}
@@ -149,7 +166,7 @@ func (o *queryProcessorOptions) runCliWithAPIClient(ctx context.Context, cmd *co
 // run runs the `cli processor query` command.
 func (o *queryProcessorOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
-	if o.runWithAPIClient {
+	if o.runWithAPIClient || o.apiV2Client != nil {
 		return o.runCliWithAPIClient(ctx, cmd)
 	}
 
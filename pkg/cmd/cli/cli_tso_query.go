@@ -14,6 +14,7 @@
 package cli
 
 import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	"github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/spf13/cobra"
@@ -23,7 +24,8 @@ import (
 
 // queryTsoOptions defines flags for the `cli tso query` command.
 type queryTsoOptions struct {
-	pdClient pd.Client
+	pdClient    pd.Client
+	apiV2Client apiv2client.APIV2Interface
 }
 
 // newQueryTsoOptions creates new queryTsoOptions for the `cli tso query` command.
@@ -33,6 +35,15 @@ func newQueryTsoOptions() *queryTsoOptions {
 
 // complete adapts from the command line args to the data and client required.
 func (o *queryTsoOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	pdClient, err := f.PdClient()
 	if err != nil {
 		return err
@@ -46,6 +57,15 @@ func (o *queryTsoOptions) complete(f factory.Factory) error {
 func (o *queryTsoOptions) run(cmd *cobra.Command) error {
 	ctx := context.GetDefaultContext()
 
+	if o.apiV2Client != nil {
+		tso, err := o.apiV2Client.Tso().Query(ctx)
+		if err != nil {
+			return err
+		}
+		cmd.Println(tso.Timestamp)
+		return nil
+	}
+
 	ts, logic, err := o.pdClient.GetTS(ctx)
 	if err != nil {
 		return err
@@ -15,6 +15,7 @@ package cli
 
 import (
 	"github.com/pingcap/errors"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	"github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
@@ -25,7 +26,8 @@ import (
 // unsafeDeleteServiceGcSafepointOptions defines flags
 // for the `cli unsafe delete-service-gc-safepoint` command.
 type unsafeDeleteServiceGcSafepointOptions struct {
-	pdClient pd.Client
+	pdClient    pd.Client
+	apiV2Client apiv2client.APIV2Interface
 }
 
 // newUnsafeDeleteServiceGcSafepointOptions creates new unsafeDeleteServiceGcSafepointOptions
@@ -36,6 +38,15 @@ func newUnsafeDeleteServiceGcSafepointOptions() *unsafeDeleteServiceGcSafepointO
 
 // complete adapts from the command line args to the data and client required.
 func (o *unsafeDeleteServiceGcSafepointOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	pdClient, err := f.PdClient()
 	if err != nil {
 		return err
@@ -50,7 +61,12 @@ func (o *unsafeDeleteServiceGcSafepointOptions) complete(f factory.Factory) erro
 func (o *unsafeDeleteServiceGcSafepointOptions) run(cmd *cobra.Command) error {
 	ctx := context.GetDefaultContext()
 
-	err := gc.RemoveServiceGCSafepoint(ctx, o.pdClient, gc.CDCServiceSafePointID)
+	var err error
+	if o.apiV2Client != nil {
+		err = o.apiV2Client.Unsafe().DeleteServiceGCSafepoint(ctx)
+	} else {
+		err = gc.RemoveServiceGCSafepoint(ctx, o.pdClient, gc.CDCServiceSafePointID)
+	}
 	if err == nil {
 		cmd.Println("CDC service GC safepoint truncated in PD!")
 	}
@@ -15,6 +15,7 @@ package cli
 
 import (
 	"github.com/pingcap/errors"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	"github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/etcd"
@@ -25,8 +26,9 @@ import (
 
 // unsafeResetOptions defines flags for the `cli unsafe reset` command.
 type unsafeResetOptions struct {
-	etcdClient *etcd.CDCEtcdClient
-	pdClient   pd.Client
+	etcdClient  *etcd.CDCEtcdClient
+	pdClient    pd.Client
+	apiV2Client apiv2client.APIV2Interface
 }
 
 // newUnsafeResetOptions creates new unsafeResetOptions for the `cli unsafe reset` command.
@@ -36,6 +38,15 @@ func newUnsafeResetOptions() *unsafeResetOptions {
 
 // complete adapts from the command line args to the data and client required.
 func (o *unsafeResetOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -57,6 +68,14 @@ func (o *unsafeResetOptions) complete(f factory.Factory) error {
 func (o *unsafeResetOptions) run(cmd *cobra.Command) error {
 	ctx := context.GetDefaultContext()
 
+	if o.apiV2Client != nil {
+		if err := o.apiV2Client.Unsafe().Reset(ctx); err != nil {
+			return errors.Trace(err)
+		}
+		cmd.Println("reset and all metadata truncated in PD!")
+		return nil
+	}
+
 	leases, err := o.etcdClient.GetCaptureLeases(ctx)
 	if err != nil {
 		return errors.Trace(err)
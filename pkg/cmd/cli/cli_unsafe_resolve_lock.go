@@ -18,6 +18,8 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	"github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/txnutil"
@@ -29,7 +31,8 @@ import (
 
 // unsafeResolveLockOptions defines flags for the `cli unsafe show-metadata` command.
 type unsafeResolveLockOptions struct {
-	kvStorage kv.Storage
+	kvStorage   kv.Storage
+	apiV2Client apiv2client.APIV2Interface
 
 	regionID uint64
 	ts       uint64
@@ -43,6 +46,16 @@ func newUnsafeResolveLockOptions() *unsafeResolveLockOptions {
 
 // complete adapts from the command line args to the data and client required.
 func (o *unsafeResolveLockOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		// the server resolves locks 1 minute ago from now if ts is 0
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	ctx := context.GetDefaultContext()
 
 	pdClient, err := f.PdClient()
@@ -67,6 +80,13 @@ func (o *unsafeResolveLockOptions) complete(f factory.Factory) error {
 func (o *unsafeResolveLockOptions) run() error {
 	ctx := context.GetDefaultContext()
 
+	if o.apiV2Client != nil {
+		return o.apiV2Client.Unsafe().ResolveLock(ctx, &model.ResolveLockReqV2{
+			RegionID: o.regionID,
+			Ts:       o.ts,
+		})
+	}
+
 	conf := &log.Config{Level: "info", File: log.FileLogConfig{}}
 	lg, p, e := log.InitLogger(conf)
 	if e != nil {
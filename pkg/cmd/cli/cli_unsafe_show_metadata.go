@@ -15,6 +15,7 @@ package cli
 
 import (
 	"github.com/pingcap/errors"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	"github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/etcd"
@@ -23,7 +24,8 @@ import (
 
 // unsafeShowMetadataOptions defines flags for the `cli unsafe show-metadata` command.
 type unsafeShowMetadataOptions struct {
-	etcdClient *etcd.CDCEtcdClient
+	etcdClient  *etcd.CDCEtcdClient
+	apiV2Client apiv2client.APIV2Interface
 }
 
 // newUnsafeShowMetadataOptions creates new unsafeShowMetadataOptions
@@ -34,6 +36,15 @@ func newUnsafeShowMetadataOptions() *unsafeShowMetadataOptions {
 
 // complete adapts from the command line args to the data and client required.
 func (o *unsafeShowMetadataOptions) complete(f factory.Factory) error {
+	if f.GetServerAddr() != "" {
+		apiClient, err := f.APIV2Client()
+		if err != nil {
+			return err
+		}
+		o.apiV2Client = apiClient
+		return nil
+	}
+
 	etcdClient, err := f.EtcdClient()
 	if err != nil {
 		return err
@@ -48,6 +59,18 @@ func (o *unsafeShowMetadataOptions) complete(f factory.Factory) error {
 func (o *unsafeShowMetadataOptions) run(cmd *cobra.Command) error {
 	ctx := context.GetDefaultContext()
 
+	if o.apiV2Client != nil {
+		kvs, err := o.apiV2Client.Unsafe().Metadata(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, kv := range kvs {
+			cmd.Printf("Key: %s, Value: %s\n", kv.Key, kv.Value)
+		}
+		cmd.Printf("Show %d KVs\n", len(kvs))
+		return nil
+	}
+
 	kvs, err := o.etcdClient.GetAllCDCInfo(ctx)
 	if err != nil {
 		return errors.Trace(err)
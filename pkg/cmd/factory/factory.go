@@ -15,11 +15,14 @@ package factory
 
 import (
 	"crypto/tls"
+	"os"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/spf13/cobra"
 	pd "github.com/tikv/pd/client"
@@ -32,6 +35,7 @@ type Factory interface {
 	EtcdClient() (*etcd.CDCEtcdClient, error)
 	PdClient() (pd.Client, error)
 	KvStorage() (kv.Storage, error)
+	APIV2Client() (apiv2client.APIV2Interface, error)
 }
 
 // ClientGetter defines the client getter.
@@ -39,19 +43,34 @@ type ClientGetter interface {
 	ToTLSConfig() (*tls.Config, error)
 	ToGRPCDialOption() (grpc.DialOption, error)
 	GetPdAddr() string
+	GetServerAddr() string
 	GetLogLevel() string
 	GetCredential() *security.Credential
+	GetAuth() *httputil.Auth
 }
 
 // ClientFlags specifies the parameters needed to construct the client.
 type ClientFlags struct {
-	pdAddr   string
-	logLevel string
-	caPath   string
-	certPath string
-	keyPath  string
+	pdAddr     string
+	serverAddr string
+	logLevel   string
+	caPath     string
+	certPath   string
+	keyPath    string
+
+	authToken    string
+	authUser     string
+	authPassword string
 }
 
+// The environment variables that set the credential of the API
+// authentication if the flags are not set.
+const (
+	authTokenEnv    = "TICDC_AUTH_TOKEN"
+	authUserEnv     = "TICDC_AUTH_USER"
+	authPasswordEnv = "TICDC_AUTH_PASSWORD"
+)
+
 var _ ClientGetter = &ClientFlags{}
 
 // ToTLSConfig returns the configuration of tls.
@@ -80,6 +99,11 @@ func (c *ClientFlags) GetPdAddr() string {
 	return c.pdAddr
 }
 
+// GetServerAddr returns cdc server address.
+func (c *ClientFlags) GetServerAddr() string {
+	return c.serverAddr
+}
+
 // GetLogLevel returns log level.
 func (c *ClientFlags) GetLogLevel() string {
 	return c.logLevel
@@ -94,10 +118,18 @@ func NewClientFlags() *ClientFlags {
 // flags related to template printing to it.
 func (c *ClientFlags) AddFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVar(&c.pdAddr, "pd", "http://127.0.0.1:2379", "PD address, use ',' to separate multiple PDs")
+	cmd.PersistentFlags().StringVar(&c.serverAddr, "server", "",
+		"CDC server address, the cli talks to the open API of the server instead of PD if it is set")
 	cmd.PersistentFlags().StringVar(&c.caPath, "ca", "", "CA certificate path for TLS connection")
 	cmd.PersistentFlags().StringVar(&c.certPath, "cert", "", "Certificate path for TLS connection")
 	cmd.PersistentFlags().StringVar(&c.keyPath, "key", "", "Private key path for TLS connection")
 	cmd.PersistentFlags().StringVar(&c.logLevel, "log-level", "warn", "log level (etc: debug|info|warn|error)")
+	cmd.PersistentFlags().StringVar(&c.authToken, "auth-token", "",
+		"Bearer token for the API authentication of the CDC server, $"+authTokenEnv+" is used if it is not set")
+	cmd.PersistentFlags().StringVar(&c.authUser, "auth-user", "",
+		"User for the API authentication of the CDC server, $"+authUserEnv+" is used if it is not set")
+	cmd.PersistentFlags().StringVar(&c.authPassword, "auth-password", "",
+		"Password for the API authentication of the CDC server, $"+authPasswordEnv+" is used if it is not set")
 }
 
 // Validate makes sure provided values for ClientFlags are valid.
@@ -125,3 +157,23 @@ func (c *ClientFlags) GetCredential() *security.Credential {
 		CertAllowedCN: certAllowedCN,
 	}
 }
+
+// GetAuth returns the credential of the API authentication, the flags take
+// precedence over the environment variables.
+func (c *ClientFlags) GetAuth() *httputil.Auth {
+	auth := &httputil.Auth{
+		Token:    c.authToken,
+		User:     c.authUser,
+		Password: c.authPassword,
+	}
+	if auth.Token == "" {
+		auth.Token = os.Getenv(authTokenEnv)
+	}
+	if auth.User == "" {
+		auth.User = os.Getenv(authUserEnv)
+	}
+	if auth.Password == "" {
+		auth.Password = os.Getenv(authPasswordEnv)
+	}
+	return auth
+}
@@ -28,8 +28,10 @@ import (
 	"google.golang.org/grpc/backoff"
 
 	"github.com/pingcap/tiflow/cdc/kv"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
 	cmdconetxt "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/version"
 )
@@ -65,6 +67,11 @@ func (f *factoryImpl) GetPdAddr() string {
 	return f.clientGetter.GetPdAddr()
 }
 
+// GetServerAddr returns cdc server address.
+func (f *factoryImpl) GetServerAddr() string {
+	return f.clientGetter.GetServerAddr()
+}
+
 // GetLogLevel returns log level.
 func (f *factoryImpl) GetLogLevel() string {
 	return f.clientGetter.GetLogLevel()
@@ -75,6 +82,11 @@ func (f *factoryImpl) GetCredential() *security.Credential {
 	return f.clientGetter.GetCredential()
 }
 
+// GetAuth returns the credential of the API authentication.
+func (f *factoryImpl) GetAuth() *httputil.Auth {
+	return f.clientGetter.GetAuth()
+}
+
 // EtcdClient creates new cdc etcd client.
 func (f *factoryImpl) EtcdClient() (*etcd.CDCEtcdClient, error) {
 	ctx := cmdconetxt.GetDefaultContext()
@@ -183,3 +195,17 @@ func (f factoryImpl) KvStorage() (tidbkv.Storage, error) {
 	}
 	return kvStore, nil
 }
+
+// APIV2Client creates a new cdc open API v2 client.
+func (f *factoryImpl) APIV2Client() (apiv2client.APIV2Interface, error) {
+	serverAddr := f.GetServerAddr()
+	if serverAddr == "" {
+		return nil, errors.New("the cdc server address is not specified, please use --server to specify it")
+	}
+	client, err := apiv2client.NewAPIClient(serverAddr, f.GetCredential(), f.GetAuth())
+	if err != nil {
+		return nil, errors.Annotatef(err,
+			"fail to open API client, please check server address \"%s\"", serverAddr)
+	}
+	return client, nil
+}
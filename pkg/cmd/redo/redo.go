@@ -15,6 +15,8 @@ package redo
 
 import (
 	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encrypt"
 	"github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/spf13/cobra"
 )
@@ -24,6 +26,9 @@ type options struct {
 	storage  string
 	dir      string
 	logLevel string
+
+	encryptionKeyFile string
+	encryptionKeyEnv  string
 }
 
 // newOptions creates new options for the `server` command.
@@ -37,6 +42,8 @@ func (o *options) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVar(&o.storage, "storage", "", "storage of redo log, specify the url where backup redo logs will store, eg, \"s3://bucket/path/prefix\"")
 	cmd.PersistentFlags().StringVar(&o.dir, "tmp-dir", "", "temporary path used to download redo log with S3 backend")
 	cmd.PersistentFlags().StringVar(&o.logLevel, "log-level", "info", "log level (etc: debug|info|warn|error)")
+	cmd.PersistentFlags().StringVar(&o.encryptionKeyFile, "encryption-key-file", "", "path of the file that contains the hex encoded key of encrypted redo logs")
+	cmd.PersistentFlags().StringVar(&o.encryptionKeyEnv, "encryption-key-env", "", "environment variable that contains the hex encoded key of encrypted redo logs")
 	// the possible error returned from MarkFlagRequired is `no such flag`
 	cmd.MarkFlagRequired("storage") //nolint:errcheck
 }
@@ -53,7 +60,13 @@ func NewCmdRedo() *cobra.Command {
 			util.InitCmd(cmd, &logutil.Config{Level: o.logLevel})
 			util.LogHTTPProxies()
 
-			return nil
+			// Redo logs written by an encryption enabled server need the
+			// same key to read.
+			return encrypt.InitGlobalCipher(&config.EncryptionConfig{
+				Enable:  o.encryptionKeyFile != "" || o.encryptionKeyEnv != "",
+				KeyFile: o.encryptionKeyFile,
+				KeyEnv:  o.encryptionKeyEnv,
+			})
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 		},
@@ -64,6 +64,7 @@ func (o *options) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.serverConfig.Addr, "addr", o.serverConfig.Addr, "Set the listening address")
 	cmd.Flags().StringVar(&o.serverConfig.AdvertiseAddr, "advertise-addr", o.serverConfig.AdvertiseAddr, "Set the advertise listening address for client communication")
 
+	cmd.Flags().StringToStringVar(&o.serverConfig.Labels, "labels", o.serverConfig.Labels, "Set the labels of the capture, in the `key=value` format, use ',' to separate multiple labels")
 	cmd.Flags().StringVar(&o.serverConfig.TZ, "tz", o.serverConfig.TZ, "Specify time zone of TiCDC cluster")
 	cmd.Flags().Int64Var(&o.serverConfig.GcTTL, "gc-ttl", o.serverConfig.GcTTL, "CDC GC safepoint TTL duration, specified in seconds")
 
@@ -186,6 +187,8 @@ func (o *options) complete(cmd *cobra.Command) error {
 			cfg.Addr = o.serverConfig.Addr
 		case "advertise-addr":
 			cfg.AdvertiseAddr = o.serverConfig.AdvertiseAddr
+		case "labels":
+			cfg.Labels = o.serverConfig.Labels
 		case "tz":
 			cfg.TZ = o.serverConfig.TZ
 		case "gc-ttl":
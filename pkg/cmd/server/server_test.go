@@ -114,6 +114,7 @@ func TestParseCfg(t *testing.T) {
 	require.Nil(t, cmd.ParseFlags([]string{
 		"--addr", "127.5.5.1:8833",
 		"--advertise-addr", "127.5.5.1:7777",
+		"--labels", "zone=us-east-1a,rack=r1",
 		"--log-file", "/root/cdc.log",
 		"--log-level", "debug",
 		"--data-dir", dataDir,
@@ -139,6 +140,7 @@ func TestParseCfg(t *testing.T) {
 	require.Equal(t, &config.ServerConfig{
 		Addr:          "127.5.5.1:8833",
 		AdvertiseAddr: "127.5.5.1:7777",
+		Labels:        map[string]string{"zone": "us-east-1a", "rack": "r1"},
 		LogFile:       "/root/cdc.log",
 		LogLevel:      "debug",
 		Log: &config.LogConfig{
@@ -156,23 +158,33 @@ func TestParseCfg(t *testing.T) {
 		OwnerFlushInterval:     config.TomlDuration(150 * time.Millisecond),
 		ProcessorFlushInterval: config.TomlDuration(150 * time.Millisecond),
 		Sorter: &config.SorterConfig{
+			Engine:                 config.SorterEngineDB,
 			NumConcurrentWorker:    80,
 			ChunkSizeLimit:         50000000,
 			MaxMemoryPercentage:    70,
 			MaxMemoryConsumption:   60000,
 			NumWorkerPoolGoroutine: 90,
 			SortDir:                config.DefaultSortDir,
+			Compression:            config.SorterCompressionNone,
 		},
 		Security: &config.SecurityConfig{
 			CertPath:      "bb",
 			KeyPath:       "cc",
 			CertAllowedCN: []string{"dd", "ee"},
 		},
+		Encryption: &config.EncryptionConfig{},
+		APIAuth: &config.APIAuthConfig{
+			TiDBDefaultRole: config.APIRoleReadOnly,
+		},
 		PerTableMemoryQuota: 10 * 1024 * 1024, // 10M
 		KVClient: &config.KVClientConfig{
-			WorkerConcurrent: 8,
-			WorkerPoolSize:   0,
-			RegionScanLimit:  40,
+			WorkerConcurrent:     8,
+			WorkerPoolSize:       0,
+			RegionScanLimit:      40,
+			StoreRegionScanLimit: 512,
+		},
+		Tracing: &config.TracingConfig{
+			SampleRate: 0.001,
 		},
 		Debug: &config.DebugConfig{
 			EnableTableActor: true,
@@ -182,6 +194,7 @@ func TestParseCfg(t *testing.T) {
 			EnableDBSorter:     true,
 			EnableNewScheduler: true,
 			DB: &config.DBConfig{
+				Backend:                     config.DBBackendPebble,
 				Count:                       8,
 				Concurrency:                 128,
 				MaxOpenFiles:                10000,
@@ -219,6 +232,7 @@ func TestDecodeCfg(t *testing.T) {
 	configContent := fmt.Sprintf(`
 addr = "128.0.0.1:1234"
 advertise-addr = "127.0.0.1:1111"
+labels = { zone = "us-east-1b" }
 
 log-file = "/root/cdc1.log"
 log-level = "warn"
@@ -227,6 +241,7 @@ data-dir = "%+v"
 gc-ttl = 500
 tz = "US"
 capture-session-ttl = 10
+memory-quota = 1073741824
 
 owner-flush-interval = "600ms"
 processor-flush-interval = "600ms"
@@ -243,10 +258,12 @@ max-memory-percentage = 3
 num-concurrent-worker = 4
 num-workerpool-goroutine = 5
 sort-dir = "/tmp/just_a_test"
+compression = "snappy"
 
 [debug]
 enable-db-sorter = false
 [debug.db]
+backend = "leveldb"
 count = 5
 concurrency = 6
 max-open-files = 7
@@ -286,6 +303,7 @@ server-worker-pool-size = 16
 	require.Equal(t, &config.ServerConfig{
 		Addr:          "128.0.0.1:1234",
 		AdvertiseAddr: "127.0.0.1:1111",
+		Labels:        map[string]string{"zone": "us-east-1b"},
 		LogFile:       "/root/cdc1.log",
 		LogLevel:      "warn",
 		Log: &config.LogConfig{
@@ -303,19 +321,30 @@ server-worker-pool-size = 16
 		OwnerFlushInterval:     config.TomlDuration(600 * time.Millisecond),
 		ProcessorFlushInterval: config.TomlDuration(600 * time.Millisecond),
 		Sorter: &config.SorterConfig{
+			Engine:                 config.SorterEngineUnified,
 			NumConcurrentWorker:    4,
 			ChunkSizeLimit:         10000000,
 			MaxMemoryPercentage:    3,
 			MaxMemoryConsumption:   2000000,
 			NumWorkerPoolGoroutine: 5,
 			SortDir:                config.DefaultSortDir,
+			Compression:            config.SorterCompressionSnappy,
+		},
+		Security:   &config.SecurityConfig{},
+		Encryption: &config.EncryptionConfig{},
+		APIAuth: &config.APIAuthConfig{
+			TiDBDefaultRole: config.APIRoleReadOnly,
 		},
-		Security:            &config.SecurityConfig{},
 		PerTableMemoryQuota: 10 * 1024 * 1024, // 10M
+		MemoryQuota:         1024 * 1024 * 1024,
 		KVClient: &config.KVClientConfig{
-			WorkerConcurrent: 8,
-			WorkerPoolSize:   0,
-			RegionScanLimit:  40,
+			WorkerConcurrent:     8,
+			WorkerPoolSize:       0,
+			RegionScanLimit:      40,
+			StoreRegionScanLimit: 512,
+		},
+		Tracing: &config.TracingConfig{
+			SampleRate: 0.001,
 		},
 		Debug: &config.DebugConfig{
 			EnableTableActor: true,
@@ -325,6 +354,7 @@ server-worker-pool-size = 16
 			EnableDBSorter:     false,
 			EnableNewScheduler: true,
 			DB: &config.DBConfig{
+				Backend:                     config.DBBackendLevelDB,
 				Count:                       5,
 				Concurrency:                 6,
 				MaxOpenFiles:                7,
@@ -440,23 +470,33 @@ cert-allowed-cn = ["dd","ee"]
 		OwnerFlushInterval:     config.TomlDuration(150 * time.Millisecond),
 		ProcessorFlushInterval: config.TomlDuration(150 * time.Millisecond),
 		Sorter: &config.SorterConfig{
+			Engine:                 config.SorterEngineDB,
 			NumConcurrentWorker:    3,
 			ChunkSizeLimit:         50000000,
 			MaxMemoryPercentage:    70,
 			MaxMemoryConsumption:   60000000,
 			NumWorkerPoolGoroutine: 5,
 			SortDir:                config.DefaultSortDir,
+			Compression:            config.SorterCompressionNone,
 		},
 		Security: &config.SecurityConfig{
 			CertPath:      "bb",
 			KeyPath:       "cc",
 			CertAllowedCN: []string{"dd", "ee"},
 		},
+		Encryption: &config.EncryptionConfig{},
+		APIAuth: &config.APIAuthConfig{
+			TiDBDefaultRole: config.APIRoleReadOnly,
+		},
 		PerTableMemoryQuota: 10 * 1024 * 1024, // 10M
 		KVClient: &config.KVClientConfig{
-			WorkerConcurrent: 8,
-			WorkerPoolSize:   0,
-			RegionScanLimit:  40,
+			WorkerConcurrent:     8,
+			WorkerPoolSize:       0,
+			RegionScanLimit:      40,
+			StoreRegionScanLimit: 512,
+		},
+		Tracing: &config.TracingConfig{
+			SampleRate: 0.001,
 		},
 		Debug: &config.DebugConfig{
 			EnableTableActor: true,
@@ -466,6 +506,7 @@ cert-allowed-cn = ["dd","ee"]
 			EnableDBSorter:     true,
 			EnableNewScheduler: true,
 			DB: &config.DBConfig{
+				Backend:                     config.DBBackendPebble,
 				Count:                       8,
 				Concurrency:                 128,
 				MaxOpenFiles:                10000,
@@ -526,6 +567,7 @@ unknown3 = 3
 		EnableDBSorter:     true,
 		EnableNewScheduler: true,
 		DB: &config.DBConfig{
+			Backend:                     config.DBBackendPebble,
 			Count:                       8,
 			Concurrency:                 128,
 			MaxOpenFiles:                10000,
@@ -186,14 +186,30 @@ func (s *utilsSuite) TestAndWriteExampleReplicaTOML(c *check.C) {
 	})
 	c.Assert(cfg.Sink, check.DeepEquals, &config.SinkConfig{
 		DispatchRules: []*config.DispatchRule{
-			{PartitionRule: "ts", TopicRule: "hello_{schema}", Matcher: []string{"test1.*", "test2.*"}},
+			{
+				PartitionRule: "ts", TopicRule: "hello_{schema}", Matcher: []string{"test1.*", "test2.*"},
+				TopicConfig: &config.TopicConfig{PartitionNum: 6, CleanupPolicy: "compact"},
+			},
 			{PartitionRule: "rowid", TopicRule: "{schema}_world", Matcher: []string{"test3.*", "test4.*"}},
+			{
+				PartitionRule: "columns", TopicRule: "{schema}_{col:region}",
+				Matcher: []string{"tenant.*"}, Columns: []string{"tenant_id"},
+			},
 		},
 		ColumnSelectors: []*config.ColumnSelector{
 			{Matcher: []string{"test1.*", "test2.*"}, Columns: []string{"column1", "column2"}},
 			{Matcher: []string{"test3.*", "test4.*"}, Columns: []string{"!a", "column3"}},
 		},
 		Protocol: "open-protocol",
+		Routes: []*config.RouteRule{
+			{Matcher: []string{"sharding_*.t_*"}, TargetSchema: "merged", TargetTable: "{schema}_{table}"},
+		},
+		DDLRewriters:    []string{"remove-auto-random", "remove-shard-row-id-bits"},
+		SchemaBootstrap: true,
+		Headers: &config.HeadersConfig{
+			Include: []string{"commit-ts", "table", "changefeed-id"},
+			Static:  map[string]string{"env": "prod"},
+		},
 	})
 	c.Assert(cfg.Cyclic, check.DeepEquals, &config.CyclicConfig{
 		Enable:          false,
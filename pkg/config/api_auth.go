@@ -0,0 +1,115 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// The roles of the HTTP API users, each role has all the permissions of the
+// roles before it.
+const (
+	// APIRoleReadOnly can only query the cluster.
+	APIRoleReadOnly = "read-only"
+	// APIRoleOperator can manage changefeeds, tables and the owner.
+	APIRoleOperator = "operator"
+	// APIRoleAdmin can additionally change the log level, profile the server
+	// and run the unsafe operations.
+	APIRoleAdmin = "admin"
+)
+
+// APIAuthConfig represents the authentication config of the HTTP API.
+type APIAuthConfig struct {
+	// Enable enables the authentication, the requests except health checks
+	// and metrics are rejected if the caller can not be authenticated.
+	Enable bool `toml:"enable" json:"enable"`
+	// Tokens are the static tokens in the `Authorization: Bearer` header.
+	Tokens []*APITokenConfig `toml:"tokens" json:"tokens"`
+	// CertCNRoles maps the common names of client certificates to roles.
+	// The requests forwarded to the owner are authorized by the capture which
+	// receives them, so the common name of the captures needs no role.
+	CertCNRoles map[string]string `toml:"cert-cn-roles" json:"cert-cn-roles"`
+	// TiDBAddr is the address of the TiDB used to verify the user and
+	// password in the `Authorization: Basic` header, disabled if empty.
+	TiDBAddr string `toml:"tidb-addr" json:"tidb-addr"`
+	// TiDBUserRoles maps TiDB users to roles.
+	TiDBUserRoles map[string]string `toml:"tidb-user-roles" json:"tidb-user-roles"`
+	// TiDBDefaultRole is the role of the TiDB users not in TiDBUserRoles.
+	TiDBDefaultRole string `toml:"tidb-default-role" json:"tidb-default-role"`
+}
+
+// APITokenConfig represents a static token of the HTTP API.
+type APITokenConfig struct {
+	Token string `toml:"token" json:"token"`
+	User  string `toml:"user" json:"user"`
+	Role  string `toml:"role" json:"role"`
+}
+
+// ValidateAndAdjust validates the API authentication config.
+func (c *APIAuthConfig) ValidateAndAdjust() error {
+	if c.TiDBDefaultRole == "" {
+		c.TiDBDefaultRole = APIRoleReadOnly
+	}
+	if !c.Enable {
+		return nil
+	}
+
+	if len(c.Tokens) == 0 && len(c.CertCNRoles) == 0 && c.TiDBAddr == "" {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"api-auth is enabled but none of tokens, cert-cn-roles and tidb-addr is set")
+	}
+	tokens := make(map[string]struct{}, len(c.Tokens))
+	for _, token := range c.Tokens {
+		if token.Token == "" || token.User == "" {
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"both token and user must be set for api-auth tokens")
+		}
+		if _, ok := tokens[token.Token]; ok {
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"duplicated api-auth token of user %s", token.User)
+		}
+		tokens[token.Token] = struct{}{}
+		if err := validateAPIRole(token.Role); err != nil {
+			return err
+		}
+	}
+	for _, role := range c.CertCNRoles {
+		if err := validateAPIRole(role); err != nil {
+			return err
+		}
+	}
+	for _, role := range c.TiDBUserRoles {
+		if err := validateAPIRole(role); err != nil {
+			return err
+		}
+	}
+	return validateAPIRole(c.TiDBDefaultRole)
+}
+
+// redact hides the tokens, so that the config can be logged.
+func (c *APIAuthConfig) redact() {
+	for _, token := range c.Tokens {
+		token.Token = "******"
+	}
+}
+
+func validateAPIRole(role string) error {
+	switch role {
+	case APIRoleReadOnly, APIRoleOperator, APIRoleAdmin:
+		return nil
+	}
+	return cerror.ErrInvalidServerOption.GenWithStack(
+		"invalid api-auth role %q, it must be one of %s, %s and %s",
+		role, APIRoleReadOnly, APIRoleOperator, APIRoleAdmin)
+}
@@ -67,6 +67,7 @@ const (
 	testCfgTestServerConfigMarshal = `{
   "addr": "192.155.22.33:8887",
   "advertise-addr": "",
+  "labels": null,
   "log-file": "",
   "log-level": "info",
   "log": {
@@ -84,12 +85,14 @@ const (
   "owner-flush-interval": 200000000,
   "processor-flush-interval": 100000000,
   "sorter": {
+    "engine": "db",
     "num-concurrent-worker": 4,
     "chunk-size-limit": 999,
     "max-memory-percentage": 30,
     "max-memory-consumption": 17179869184,
     "num-workerpool-goroutine": 16,
-    "sort-dir": "/tmp/sorter"
+    "sort-dir": "/tmp/sorter",
+    "compression": "none"
   },
   "security": {
     "ca-path": "",
@@ -97,11 +100,32 @@ const (
     "key-path": "",
     "cert-allowed-cn": null
   },
+  "encryption": {
+    "enable": false,
+    "key-file": "",
+    "key-env": ""
+  },
+  "api-auth": {
+    "enable": false,
+    "tokens": null,
+    "cert-cn-roles": null,
+    "tidb-addr": "",
+    "tidb-user-roles": null,
+    "tidb-default-role": "read-only"
+  },
   "per-table-memory-quota": 10485760,
+  "memory-quota": 0,
   "kv-client": {
     "worker-concurrent": 8,
     "worker-pool-size": 0,
-    "region-scan-limit": 40
+    "region-scan-limit": 40,
+    "store-region-scan-limit": 512,
+    "enable-multiplexing": false
+  },
+  "tracing": {
+    "enable": false,
+    "endpoint": "",
+    "sample-rate": 0.001
   },
   "debug": {
     "enable-table-actor": true,
@@ -110,6 +134,7 @@ const (
     },
     "enable-db-sorter": true,
     "db": {
+      "backend": "pebble",
       "count": 8,
       "concurrency": 128,
       "max-open-files": 10000,
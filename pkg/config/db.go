@@ -17,8 +17,20 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
+const (
+	// DBBackendPebble stores sorter data in pebble.
+	DBBackendPebble = "pebble"
+	// DBBackendLevelDB stores sorter data in goleveldb.
+	DBBackendLevelDB = "leveldb"
+)
+
 // DBConfig represents leveldb sorter config.
 type DBConfig struct {
+	// Backend is the storage engine of db sorter.
+	// Valid values are "pebble" or "leveldb".
+	//
+	// The default value is "pebble".
+	Backend string `toml:"backend" json:"backend"`
 	// Count is the number of leveldb count.
 	//
 	// The default value is 16.
@@ -36,6 +48,8 @@ type DBConfig struct {
 	// The default value is 65536, 64KB.
 	BlockSize int `toml:"block-size" json:"block-size"`
 	// BlockCacheSize is the capacity of leveldb block cache.
+	// Pebble uses a block cache shared by all dbs instead, its capacity is
+	// limited by sorter max-memory-percentage.
 	//
 	// The default value is 4294967296, 4GB.
 	BlockCacheSize int `toml:"block-cache-size" json:"block-cache-size"`
@@ -49,6 +63,8 @@ type DBConfig struct {
 	// The default value is "snappy".
 	Compression string `toml:"compression" json:"compression"`
 	// TargetFileSizeBase limits size of leveldb sst file that compaction generates.
+	// For pebble, it limits the size of level-0 sst files, files of the
+	// following levels are doubled level by level.
 	//
 	// The default value is 8388608, 8MB.
 	TargetFileSizeBase int `toml:"target-file-size-base" json:"target-file-size-base"`
@@ -94,6 +110,12 @@ type DBConfig struct {
 
 // ValidateAndAdjust validates and adjusts the db configuration
 func (c *DBConfig) ValidateAndAdjust() error {
+	if c.Backend == "" {
+		c.Backend = DBBackendPebble
+	}
+	if c.Backend != DBBackendPebble && c.Backend != DBBackendLevelDB {
+		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("debug.db.backend must be \"pebble\" or \"leveldb\"")
+	}
 	if c.Compression != "none" && c.Compression != "snappy" {
 		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("sorter.leveldb.compression must be \"none\" or \"snappy\"")
 	}
@@ -24,6 +24,8 @@ type DebugConfig struct {
 	// EnableDBSorter enables db sorter.
 	//
 	// The default value is true.
+	// Deprecated: use SorterConfig.Engine instead, setting it to false is
+	// equivalent to setting sorter engine to "unified".
 	EnableDBSorter bool      `toml:"enable-db-sorter" json:"enable-db-sorter"`
 	DB             *DBConfig `toml:"db" json:"db"`
 
@@ -0,0 +1,47 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import cerror "github.com/pingcap/tiflow/pkg/errors"
+
+// EncryptionConfig represents the config of at-rest encryption for data that
+// TiCDC spills to local disk, i.e. sorter files and redo logs.
+type EncryptionConfig struct {
+	// Enable encrypts spilled data with AES-GCM.
+	//
+	// The default value is false.
+	Enable bool `toml:"enable" json:"enable"`
+	// KeyFile is the path of a file that contains a hex encoded AES key,
+	// the key must be 16, 24 or 32 bytes long.
+	KeyFile string `toml:"key-file" json:"key-file"`
+	// KeyEnv is the name of an environment variable that contains a hex
+	// encoded AES key. It is used when KeyFile is empty.
+	KeyEnv string `toml:"key-env" json:"key-env"`
+}
+
+// ValidateAndAdjust validates the encryption configuration.
+func (c *EncryptionConfig) ValidateAndAdjust() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.KeyFile == "" && c.KeyEnv == "" {
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
+			"encryption is enabled but neither key-file nor key-env is set")
+	}
+	if c.KeyFile != "" && c.KeyEnv != "" {
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
+			"only one of encryption key-file and key-env can be set")
+	}
+	return nil
+}
@@ -21,4 +21,10 @@ type KVClientConfig struct {
 	WorkerPoolSize int `toml:"worker-pool-size" json:"worker-pool-size"`
 	// region incremental scan limit for one table in a single store
 	RegionScanLimit int `toml:"region-scan-limit" json:"region-scan-limit"`
+	// region incremental scan limit for all tables in a single store, the
+	// effective limit adapts to the errors and the response latency of the store
+	StoreRegionScanLimit int `toml:"store-region-scan-limit" json:"store-region-scan-limit"`
+	// whether to multiplex the region requests of all tables over the shared
+	// gRPC streams to each store
+	EnableMultiplexing bool `toml:"enable-multiplexing" json:"enable-multiplexing"`
 }
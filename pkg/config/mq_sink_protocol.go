@@ -36,6 +36,7 @@ const (
 	ProtocolCanalJSON
 	ProtocolCraft
 	ProtocolOpen
+	ProtocolState
 )
 
 // FromString converts the protocol from string to Protocol enum type.
@@ -55,6 +56,8 @@ func (p *Protocol) FromString(protocol string) error {
 		*p = ProtocolCraft
 	case "open-protocol":
 		*p = ProtocolOpen
+	case "state":
+		*p = ProtocolState
 	default:
 		return cerror.ErrMQSinkUnknownProtocol.GenWithStackByArgs(protocol)
 	}
@@ -79,6 +82,8 @@ func (p Protocol) String() string {
 		return "craft"
 	case ProtocolOpen:
 		return "open-protocol"
+	case ProtocolState:
+		return "state"
 	default:
 		panic("unreachable")
 	}
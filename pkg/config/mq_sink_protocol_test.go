@@ -58,6 +58,10 @@ func TestFromString(t *testing.T) {
 			protocol:             "open-protocol",
 			expectedProtocolEnum: ProtocolOpen,
 		},
+		{
+			protocol:             "state",
+			expectedProtocolEnum: ProtocolState,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -105,6 +109,10 @@ func TestString(t *testing.T) {
 			protocolEnum:     ProtocolOpen,
 			expectedProtocol: "open-protocol",
 		},
+		{
+			protocolEnum:     ProtocolState,
+			expectedProtocol: "state",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,47 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// PlacementConfig represents the constraints on which captures the tables
+// of a changefeed are placed. A capture matches the labels if it has all
+// of them.
+type PlacementConfig struct {
+	// CaptureLabels are the labels of the captures that the tables of the
+	// changefeed are placed on, any capture can be used if it is empty.
+	CaptureLabels map[string]string `toml:"capture-labels" json:"capture-labels"`
+	// Rules override CaptureLabels for the tables they match, the first
+	// matched rule takes effect.
+	Rules []*PlacementRule `toml:"rules" json:"rules"`
+}
+
+// PlacementRule places the tables matched by Matcher on the captures with
+// the given labels.
+type PlacementRule struct {
+	Matcher       []string          `toml:"matcher" json:"matcher"`
+	CaptureLabels map[string]string `toml:"capture-labels" json:"capture-labels"`
+}
+
+func (c *PlacementConfig) validate() error {
+	for _, rule := range c.Rules {
+		if _, err := filter.Parse(rule.Matcher); err != nil {
+			return cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+		}
+	}
+	return nil
+}
@@ -61,6 +61,14 @@ type replicaConfig struct {
 	Sink             *SinkConfig       `toml:"sink" json:"sink"`
 	Cyclic           *CyclicConfig     `toml:"cyclic-replication" json:"cyclic-replication"`
 	Consistent       *ConsistentConfig `toml:"consistent" json:"consistent"`
+	// SortCompression overrides the sorter compression of the server if
+	// it is not empty.
+	SortCompression string `toml:"sort-compression" json:"sort-compression,omitempty"`
+	// Placement constrains the captures that the tables are placed on.
+	Placement *PlacementConfig `toml:"placement" json:"placement,omitempty"`
+	// RowImages set the row image modes of the tables, the first matched
+	// rule takes effect.
+	RowImages []*RowImageRule `toml:"row-images" json:"row-images,omitempty"`
 }
 
 // Marshal returns the json marshal format of a ReplicationConfig
@@ -131,6 +139,19 @@ func (c *ReplicaConfig) Validate() error {
 			return err
 		}
 	}
+	if c.SortCompression != "" {
+		if err := ValidateSorterCompression(c.SortCompression); err != nil {
+			return err
+		}
+	}
+	if c.Placement != nil {
+		if err := c.Placement.validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateRowImageRules(c.RowImages, c.EnableOldValue, c.Sink); err != nil {
+		return err
+	}
 	return nil
 }
 
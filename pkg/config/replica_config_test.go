@@ -94,4 +94,58 @@ func TestReplicaConfigValidate(t *testing.T) {
 	conf.Sink.Protocol = "canal"
 	conf.EnableOldValue = false
 	require.Regexp(t, ".*canal protocol requires old value to be enabled.*", conf.Validate())
+
+	// Incorrect sort compression.
+	conf = GetDefaultReplicaConfig()
+	conf.SortCompression = SorterCompressionSnappy
+	require.Nil(t, conf.Validate())
+	conf.SortCompression = "lz4"
+	require.Regexp(t, ".*compression must be.*", conf.Validate())
+
+	// Incorrect placement rule.
+	conf = GetDefaultReplicaConfig()
+	conf.Placement = &PlacementConfig{
+		CaptureLabels: map[string]string{"zone": "z1"},
+		Rules: []*PlacementRule{{
+			Matcher:       []string{"test.*"},
+			CaptureLabels: map[string]string{"zone": "z2"},
+		}},
+	}
+	require.Nil(t, conf.Validate())
+	conf.Placement.Rules[0].Matcher = []string{"["}
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", conf.Validate())
+
+	// Incorrect row image rule.
+	conf = GetDefaultReplicaConfig()
+	conf.RowImages = []*RowImageRule{{
+		Matcher: []string{"test.*"},
+		Image:   RowImageMinimal,
+	}}
+	require.Nil(t, conf.Validate())
+	conf.RowImages[0].Image = "compact"
+	require.Regexp(t, ".*unknown row image compact.*", conf.Validate())
+	conf.RowImages[0].Image = RowImageNoBlob
+	conf.RowImages[0].Matcher = []string{"["}
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", conf.Validate())
+	conf.RowImages[0].Matcher = []string{"test.*"}
+	conf.EnableOldValue = false
+	require.Regexp(t, ".*requires old value to be enabled.*", conf.Validate())
+	conf.EnableOldValue = true
+	conf.Sink.Protocol = "avro"
+	require.Regexp(t, ".*noblob row image.*is not supported by the avro protocol.*", conf.Validate())
+	conf.Sink.Protocol = "canal-json"
+	conf.Sink.DispatchRules = []*DispatchRule{{
+		Matcher:       []string{"test.*"},
+		PartitionRule: "columns",
+		Columns:       []string{"id"},
+	}}
+	require.Regexp(t, ".*noblob row image.*column based dispatch rule.*", conf.Validate())
+	conf.Sink.DispatchRules[0] = &DispatchRule{
+		Matcher:   []string{"test.*"},
+		TopicRule: "t_{col:region}",
+	}
+	require.Regexp(t, ".*noblob row image.*column based dispatch rule.*", conf.Validate())
+	conf.RowImages[0].Image = RowImageFull
+	require.Nil(t, conf.Validate())
+	require.False(t, conf.HasPartialRowImage())
 }
@@ -0,0 +1,110 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// RowImage is the mode of the row images of a table, the modes match the
+// `binlog_row_image` system variable of MySQL.
+type RowImage string
+
+const (
+	// RowImageFull outputs all the columns in both the before and the after
+	// images.
+	RowImageFull RowImage = "full"
+	// RowImageMinimal outputs the handle key columns in the before image,
+	// and the handle key and the changed columns in the after image of an
+	// update. The after image of an insert is always full.
+	RowImageMinimal RowImage = "minimal"
+	// RowImageNoBlob is like RowImageFull, except that the BLOB and TEXT
+	// columns are omitted from the before image, and from the after image
+	// of an update if they are not changed. Handle key columns are never
+	// omitted.
+	RowImageNoBlob RowImage = "noblob"
+)
+
+// The row image modes only trim the rows that are sent to the sinks. The
+// whole old value of a row is still read from TiKV, which returns the old
+// values as whole rows, and the changed columns are computed from them.
+
+// RowImageRule sets the row image mode of the tables matched by Matcher.
+type RowImageRule struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	Image   RowImage `toml:"image" json:"image"`
+}
+
+// validateRowImageRules checks the row image rules, the tables matched by no rule use
+// RowImageFull if old value is enabled.
+func validateRowImageRules(rules []*RowImageRule, enableOldValue bool, sink *SinkConfig) error {
+	protocol := ""
+	var dispatchRules []*DispatchRule
+	if sink != nil {
+		protocol = sink.Protocol
+		dispatchRules = sink.DispatchRules
+	}
+	for _, rule := range rules {
+		if _, err := filter.Parse(rule.Matcher); err != nil {
+			return cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+		}
+		switch rule.Image {
+		case RowImageFull, RowImageMinimal, RowImageNoBlob:
+		default:
+			return cerror.ErrRowImageRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("unknown row image %s of %v, must be one of %s, %s and %s",
+					rule.Image, rule.Matcher, RowImageFull, RowImageMinimal, RowImageNoBlob))
+		}
+		if !enableOldValue {
+			return cerror.ErrRowImageRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("the row image of %v requires old value to be enabled", rule.Matcher))
+		}
+		// The avro and the state protocols output the whole row in every
+		// message, so the after image of a row can not be partial.
+		if rule.Image != RowImageFull &&
+			(protocol == ProtocolAvro.String() || protocol == ProtocolState.String()) {
+			return cerror.ErrRowImageRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("the %s row image of %v is not supported by the %s protocol",
+					rule.Image, rule.Matcher, protocol))
+		}
+		// The unchanged columns are omitted from the after image of an
+		// update, the columns dispatcher and the column topics would not
+		// find their values and send the update elsewhere than the insert.
+		if rule.Image == RowImageFull {
+			continue
+		}
+		for _, dispatchRule := range dispatchRules {
+			if dispatchRule.dispatchesByColumns() {
+				return cerror.ErrRowImageRuleInvalid.GenWithStackByArgs(
+					fmt.Sprintf("the %s row image of %v is not supported together with "+
+						"the column based dispatch rule of %v", rule.Image, rule.Matcher, dispatchRule.Matcher))
+			}
+		}
+	}
+	return nil
+}
+
+// HasPartialRowImage returns true if some tables may output partial rows,
+// that is the rows of the tables are not in RowImageFull mode.
+func (c *ReplicaConfig) HasPartialRowImage() bool {
+	for _, rule := range c.RowImages {
+		if rule.Image != RowImageFull {
+			return true
+		}
+	}
+	return false
+}
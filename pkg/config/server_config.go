@@ -83,19 +83,33 @@ var defaultServerConfig = &ServerConfig{
 	OwnerFlushInterval:     TomlDuration(200 * time.Millisecond),
 	ProcessorFlushInterval: TomlDuration(100 * time.Millisecond),
 	Sorter: &SorterConfig{
+		Engine:                 SorterEngineDB,
 		NumConcurrentWorker:    4,
 		ChunkSizeLimit:         128 * 1024 * 1024,       // 128MB
 		MaxMemoryPercentage:    30,                      // 30% is safe on machines with memory capacity <= 16GB
 		MaxMemoryConsumption:   16 * 1024 * 1024 * 1024, // 16GB
 		NumWorkerPoolGoroutine: 16,
 		SortDir:                DefaultSortDir,
+		Compression:            SorterCompressionNone,
+	},
+	Security:   &SecurityConfig{},
+	Encryption: &EncryptionConfig{},
+	APIAuth: &APIAuthConfig{
+		Enable:          false,
+		TiDBDefaultRole: APIRoleReadOnly,
 	},
-	Security:            &SecurityConfig{},
 	PerTableMemoryQuota: 10 * 1024 * 1024, // 10MB
 	KVClient: &KVClientConfig{
-		WorkerConcurrent: 8,
-		WorkerPoolSize:   0, // 0 will use NumCPU() * 2
-		RegionScanLimit:  40,
+		WorkerConcurrent:     8,
+		WorkerPoolSize:       0, // 0 will use NumCPU() * 2
+		RegionScanLimit:      40,
+		StoreRegionScanLimit: 512,
+		EnableMultiplexing:   false,
+	},
+	Tracing: &TracingConfig{
+		Enable:     false,
+		Endpoint:   "",
+		SampleRate: 0.001,
 	},
 	Debug: &DebugConfig{
 		EnableTableActor: true,
@@ -106,7 +120,8 @@ var defaultServerConfig = &ServerConfig{
 		// Default leveldb sorter config
 		EnableDBSorter: true,
 		DB: &DBConfig{
-			Count: 8,
+			Count:   8,
+			Backend: DBBackendPebble,
 			// Following configs are optimized for write/read throughput.
 			// Users should not change them.
 			Concurrency:                 128,
@@ -132,6 +147,9 @@ var defaultServerConfig = &ServerConfig{
 type ServerConfig struct {
 	Addr          string `toml:"addr" json:"addr"`
 	AdvertiseAddr string `toml:"advertise-addr" json:"advertise-addr"`
+	// Labels are persisted in the capture info, changefeeds can be placed
+	// on the captures with specific labels, such as `zone = "us-east-1a"`.
+	Labels map[string]string `toml:"labels" json:"labels"`
 
 	LogFile  string     `toml:"log-file" json:"log-file"`
 	LogLevel string     `toml:"log-level" json:"log-level"`
@@ -147,11 +165,15 @@ type ServerConfig struct {
 	OwnerFlushInterval     TomlDuration `toml:"owner-flush-interval" json:"owner-flush-interval"`
 	ProcessorFlushInterval TomlDuration `toml:"processor-flush-interval" json:"processor-flush-interval"`
 
-	Sorter              *SorterConfig   `toml:"sorter" json:"sorter"`
-	Security            *SecurityConfig `toml:"security" json:"security"`
-	PerTableMemoryQuota uint64          `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
-	KVClient            *KVClientConfig `toml:"kv-client" json:"kv-client"`
-	Debug               *DebugConfig    `toml:"debug" json:"debug"`
+	Sorter              *SorterConfig     `toml:"sorter" json:"sorter"`
+	Security            *SecurityConfig   `toml:"security" json:"security"`
+	Encryption          *EncryptionConfig `toml:"encryption" json:"encryption"`
+	APIAuth             *APIAuthConfig    `toml:"api-auth" json:"api-auth"`
+	PerTableMemoryQuota uint64            `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
+	MemoryQuota         uint64            `toml:"memory-quota" json:"memory-quota"`
+	KVClient            *KVClientConfig   `toml:"kv-client" json:"kv-client"`
+	Tracing             *TracingConfig    `toml:"tracing" json:"tracing"`
+	Debug               *DebugConfig      `toml:"debug" json:"debug"`
 }
 
 // Marshal returns the json marshal format of a ServerConfig
@@ -172,9 +194,13 @@ func (c *ServerConfig) Unmarshal(data []byte) error {
 	return nil
 }
 
-// String implements the Stringer interface
+// String implements the Stringer interface, the secrets are hidden.
 func (c *ServerConfig) String() string {
-	s, _ := c.Marshal()
+	clone := c.Clone()
+	if clone.APIAuth != nil {
+		clone.APIAuth.redact()
+	}
+	s, _ := clone.Marshal()
 	return s
 }
 
@@ -212,6 +238,11 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 	} else {
 		return cerror.ErrInvalidServerOption.GenWithStack("advertise address or address does not contain a port")
 	}
+	for key := range c.Labels {
+		if key == "" {
+			return cerror.ErrInvalidServerOption.GenWithStack("empty label key is not allowed")
+		}
+	}
 	if c.GcTTL == 0 {
 		return cerror.ErrInvalidServerOption.GenWithStack("empty GC TTL is not allowed")
 	}
@@ -234,6 +265,19 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 	}
 
 	defaultCfg := GetDefaultServerConfig()
+	if c.Encryption == nil {
+		c.Encryption = defaultCfg.Encryption
+	}
+	if err := c.Encryption.ValidateAndAdjust(); err != nil {
+		return err
+	}
+	if c.APIAuth == nil {
+		c.APIAuth = defaultCfg.APIAuth
+	}
+	if err := c.APIAuth.ValidateAndAdjust(); err != nil {
+		return err
+	}
+
 	if c.Sorter == nil {
 		c.Sorter = defaultCfg.Sorter
 	}
@@ -256,6 +300,16 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 	if c.KVClient.RegionScanLimit <= 0 {
 		return cerror.ErrInvalidServerOption.GenWithStackByArgs("region-scan-limit should be at least 1")
 	}
+	if c.KVClient.StoreRegionScanLimit <= 0 {
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs("store-region-scan-limit should be at least 1")
+	}
+
+	if c.Tracing == nil {
+		c.Tracing = defaultCfg.Tracing
+	}
+	if err := c.Tracing.ValidateAndAdjust(); err != nil {
+		return err
+	}
 
 	if c.Debug == nil {
 		c.Debug = defaultCfg.Debug
@@ -263,6 +317,13 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 	if err = c.Debug.ValidateAndAdjust(); err != nil {
 		return errors.Trace(err)
 	}
+	// `debug.enable-db-sorter = false` is kept for backward compatibility,
+	// it is equivalent to `sorter.engine = "unified"`.
+	if !c.Debug.EnableDBSorter && c.Sorter.Engine == SorterEngineDB {
+		log.Warn("debug.enable-db-sorter is deprecated, " +
+			"please use sorter.engine instead")
+		c.Sorter.Engine = SorterEngineUnified
+	}
 
 	return nil
 }
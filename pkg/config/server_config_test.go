@@ -90,4 +90,99 @@ func TestDBConfigValidateAndAdjust(t *testing.T) {
 	require.Nil(t, conf.ValidateAndAdjust())
 	conf.Compression = "invalid"
 	require.Error(t, conf.ValidateAndAdjust())
+	conf.Compression = "snappy"
+
+	conf.Backend = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, DBBackendPebble, conf.Backend)
+	conf.Backend = DBBackendLevelDB
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.Backend = "rocksdb"
+	require.Regexp(t, ".*debug.db.backend must be.*", conf.ValidateAndAdjust())
+}
+
+func TestSorterEngineValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone()
+	conf.Addr = "cdc:1234"
+
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, SorterEngineDB, conf.Sorter.Engine)
+	conf.Sorter.Engine = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, SorterEngineDB, conf.Sorter.Engine)
+	conf.Sorter.Engine = "invalid"
+	require.Regexp(t, ".*engine must be.*", conf.ValidateAndAdjust())
+
+	// Disabling db sorter in debug config falls back to unified sorter.
+	conf.Sorter.Engine = SorterEngineDB
+	conf.Debug.EnableDBSorter = false
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, SorterEngineUnified, conf.Sorter.Engine)
+}
+
+func TestSorterCompressionValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone()
+	conf.Addr = "cdc:1234"
+
+	conf.Sorter.Compression = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, SorterCompressionNone, conf.Sorter.Compression)
+	conf.Sorter.Compression = SorterCompressionZstd
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.Sorter.Compression = "lz4"
+	require.Regexp(t, ".*compression must be.*", conf.ValidateAndAdjust())
+}
+
+func TestAPIAuthConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+
+	conf := &APIAuthConfig{}
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, APIRoleReadOnly, conf.TiDBDefaultRole)
+
+	conf.Enable = true
+	require.Regexp(t, ".*none of tokens, cert-cn-roles and tidb-addr is set.*",
+		conf.ValidateAndAdjust())
+
+	conf.Tokens = []*APITokenConfig{{Token: "t1", User: "u1", Role: APIRoleAdmin}}
+	require.Nil(t, conf.ValidateAndAdjust())
+
+	conf.Tokens = append(conf.Tokens, &APITokenConfig{Token: "t1", User: "u2", Role: APIRoleOperator})
+	require.Regexp(t, ".*duplicated api-auth token of user u2.*", conf.ValidateAndAdjust())
+
+	conf.Tokens[1] = &APITokenConfig{Token: "t2", User: "u2", Role: "root"}
+	require.Regexp(t, ".*invalid api-auth role \"root\".*", conf.ValidateAndAdjust())
+
+	conf.Tokens = conf.Tokens[:1]
+	conf.CertCNRoles = map[string]string{"cdc": APIRoleAdmin}
+	conf.TiDBUserRoles = map[string]string{"root": "writer"}
+	require.Regexp(t, ".*invalid api-auth role \"writer\".*", conf.ValidateAndAdjust())
+
+	// the tokens must not be logged
+	serverConf := GetDefaultServerConfig()
+	serverConf.APIAuth = &APIAuthConfig{
+		Enable: true,
+		Tokens: []*APITokenConfig{{Token: "secret", User: "u1", Role: APIRoleAdmin}},
+	}
+	require.NotContains(t, serverConf.String(), "secret")
+	require.Equal(t, "secret", serverConf.APIAuth.Tokens[0].Token)
+}
+
+func TestEncryptionConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+
+	conf := &EncryptionConfig{}
+	require.Nil(t, conf.ValidateAndAdjust())
+
+	conf.Enable = true
+	require.Regexp(t, ".*neither key-file nor key-env is set.*", conf.ValidateAndAdjust())
+	conf.KeyFile = "/path/to/key"
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.KeyEnv = "TICDC_KEY"
+	require.Regexp(t, ".*only one of encryption key-file and key-env can be set.*",
+		conf.ValidateAndAdjust())
+	conf.KeyFile = ""
+	require.Nil(t, conf.ValidateAndAdjust())
 }
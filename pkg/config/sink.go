@@ -15,6 +15,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
@@ -36,6 +37,14 @@ type SinkConfig struct {
 	DispatchRules   []*DispatchRule   `toml:"dispatchers" json:"dispatchers"`
 	Protocol        string            `toml:"protocol" json:"protocol"`
 	ColumnSelectors []*ColumnSelector `toml:"column-selectors" json:"column-selectors"`
+	Routes          []*RouteRule      `toml:"routes" json:"routes,omitempty"`
+	DDLRewriters    []string          `toml:"ddl-rewriters" json:"ddl-rewriters,omitempty"`
+	Headers         *HeadersConfig    `toml:"headers" json:"headers,omitempty"`
+	// SchemaBootstrap publishes a CREATE TABLE DDL event for every
+	// replicated table when the changefeed is created, so that the
+	// consumers can learn the schemas of the existing tables. The events
+	// share the same commit ts.
+	SchemaBootstrap bool `toml:"schema-bootstrap" json:"schema-bootstrap,omitempty"`
 }
 
 // DispatchRule represents partition rule for a table
@@ -43,14 +52,134 @@ type DispatchRule struct {
 	Matcher       []string `toml:"matcher" json:"matcher"`
 	PartitionRule string   `toml:"dispatcher" json:"dispatcher"`
 	TopicRule     string   `toml:"topic" json:"topic"`
+	// Columns are the columns whose values are hashed by the `columns`
+	// partition dispatcher.
+	Columns []string `toml:"columns" json:"columns,omitempty"`
+	// TopicConfig is used to create the topics which the rows of the matched
+	// tables are dispatched to, if the topics don't exist.
+	TopicConfig *TopicConfig `toml:"topic-config" json:"topic-config,omitempty"`
 }
 
+// TopicConfig represents the configurations of a topic created by the MQ
+// sinks. The zero values mean the ones of the sink URI or the broker are used.
+type TopicConfig struct {
+	PartitionNum      int32  `toml:"partition-num" json:"partition-num,omitempty"`
+	ReplicationFactor int16  `toml:"replication-factor" json:"replication-factor,omitempty"`
+	RetentionMs       int64  `toml:"retention-ms" json:"retention-ms,omitempty"`
+	CleanupPolicy     string `toml:"cleanup-policy" json:"cleanup-policy,omitempty"`
+	MinInsyncReplicas int    `toml:"min-insync-replicas" json:"min-insync-replicas,omitempty"`
+}
+
+// Validate checks whether the topic configurations are valid.
+func (c *TopicConfig) Validate() error {
+	if c.PartitionNum < 0 {
+		return cerror.ErrTopicConfigInvalid.GenWithStackByArgs(
+			fmt.Sprintf("partition-num %d is negative", c.PartitionNum))
+	}
+	if c.ReplicationFactor < 0 {
+		return cerror.ErrTopicConfigInvalid.GenWithStackByArgs(
+			fmt.Sprintf("replication-factor %d is negative", c.ReplicationFactor))
+	}
+	// -1 means the messages are retained forever.
+	if c.RetentionMs < -1 {
+		return cerror.ErrTopicConfigInvalid.GenWithStackByArgs(
+			fmt.Sprintf("retention-ms %d is less than -1", c.RetentionMs))
+	}
+	switch c.CleanupPolicy {
+	case "", "delete", "compact", "compact,delete", "delete,compact":
+	default:
+		return cerror.ErrTopicConfigInvalid.GenWithStackByArgs(
+			fmt.Sprintf("cleanup-policy %s is not one of delete, compact and compact,delete",
+				c.CleanupPolicy))
+	}
+	if c.MinInsyncReplicas < 0 {
+		return cerror.ErrTopicConfigInvalid.GenWithStackByArgs(
+			fmt.Sprintf("min-insync-replicas %d is negative", c.MinInsyncReplicas))
+	}
+	// TiCDC cannot deliver messages if there are fewer replicas than
+	// the ones required to acknowledge a write.
+	if c.ReplicationFactor > 0 && c.MinInsyncReplicas > int(c.ReplicationFactor) {
+		return cerror.ErrTopicConfigInvalid.GenWithStackByArgs(
+			fmt.Sprintf("min-insync-replicas %d is greater than replication-factor %d",
+				c.MinInsyncReplicas, c.ReplicationFactor))
+	}
+	return nil
+}
+
+// PartitionRuleColumns is the partition rule which dispatches the rows by
+// the hash of the values of DispatchRule.Columns.
+const PartitionRuleColumns = "columns"
+
 // ColumnSelector represents a column selector for a table.
 type ColumnSelector struct {
 	Matcher []string `toml:"matcher" json:"matcher"`
 	Columns []string `toml:"columns" json:"columns"`
 }
 
+// RouteRule represents a rule which routes the events of the matched tables
+// to another schema or table in the downstream.
+// `{schema}` and `{table}` in the target names are replaced by the source
+// schema and table name, an empty target keeps the source name unchanged.
+type RouteRule struct {
+	Matcher      []string `toml:"matcher" json:"matcher"`
+	TargetSchema string   `toml:"target-schema" json:"target-schema"`
+	TargetTable  string   `toml:"target-table" json:"target-table"`
+}
+
+// Built-in headers which can be attached to the messages sent to MQ sinks.
+const (
+	HeaderCommitTs     = "commit-ts"
+	HeaderSchema       = "schema"
+	HeaderTable        = "table"
+	HeaderProtocol     = "protocol"
+	HeaderType         = "type"
+	HeaderChangefeedID = "changefeed-id"
+	HeaderClusterID    = "cluster-id"
+)
+
+// HeaderPrefix is prepended to the names of built-in headers, so that they
+// don't collide with the headers set by other producers.
+const HeaderPrefix = "ticdc-"
+
+var builtinHeaders = map[string]struct{}{
+	HeaderCommitTs:     {},
+	HeaderSchema:       {},
+	HeaderTable:        {},
+	HeaderProtocol:     {},
+	HeaderType:         {},
+	HeaderChangefeedID: {},
+	HeaderClusterID:    {},
+}
+
+// HeadersConfig represents the Kafka record headers or Pulsar message
+// properties attached to every message sent to MQ sinks.
+type HeadersConfig struct {
+	// Include are the names of the built-in headers to attach, such as
+	// `commit-ts` and `table`. They are sent with the `ticdc-` prefix.
+	Include []string `toml:"include" json:"include"`
+	// Static are user-defined headers attached to every message as is.
+	Static map[string]string `toml:"static" json:"static"`
+}
+
+func (c *HeadersConfig) validate() error {
+	for _, name := range c.Include {
+		if _, ok := builtinHeaders[name]; !ok {
+			return cerror.ErrHeaderConfigInvalid.GenWithStackByArgs(
+				fmt.Sprintf("unknown built-in header %s", name))
+		}
+	}
+	for name := range c.Static {
+		if name == "" {
+			return cerror.ErrHeaderConfigInvalid.GenWithStackByArgs("static header name is empty")
+		}
+		if strings.HasPrefix(name, HeaderPrefix) {
+			return cerror.ErrHeaderConfigInvalid.GenWithStackByArgs(
+				fmt.Sprintf("static header %s uses the reserved prefix %s", name, HeaderPrefix))
+		}
+	}
+	return nil
+}
+
 func (s *SinkConfig) validate(enableOldValue bool) error {
 	if !enableOldValue {
 		for _, protocolStr := range ForceEnableOldValueProtocols {
@@ -63,5 +192,73 @@ func (s *SinkConfig) validate(enableOldValue bool) error {
 		}
 	}
 
+	for _, rule := range s.Routes {
+		if len(rule.Matcher) == 0 {
+			return cerror.ErrRouteRuleInvalid.GenWithStackByArgs("matcher is empty")
+		}
+		if rule.TargetSchema == "" && rule.TargetTable == "" {
+			return cerror.ErrRouteRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("neither target schema nor target table is set for %v", rule.Matcher))
+		}
+	}
+
+	for _, rule := range s.DispatchRules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+		// The state protocol requires all the changes of a row to be sent to
+		// the same partition, or the compacted topic may keep a stale state.
+		// The values of the columns may change, so the rows can not be
+		// dispatched by them either.
+		if s.Protocol == ProtocolState.String() {
+			if strings.EqualFold(rule.PartitionRule, "ts") {
+				return cerror.ErrDispatchRuleInvalid.GenWithStackByArgs(
+					fmt.Sprintf("the ts dispatcher of %v is not supported by the state protocol", rule.Matcher))
+			}
+			if rule.dispatchesByColumns() {
+				return cerror.ErrDispatchRuleInvalid.GenWithStackByArgs(
+					fmt.Sprintf("dispatching the rows of %v by the values of the columns "+
+						"is not supported by the state protocol", rule.Matcher))
+			}
+		}
+	}
+
+	if s.Headers != nil {
+		if err := s.Headers.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dispatchesByColumns returns true if the rule dispatches the rows by the
+// values of their columns, either by the columns dispatcher or by a topic
+// expression with '{col:name}'.
+func (r *DispatchRule) dispatchesByColumns() bool {
+	return strings.EqualFold(r.PartitionRule, PartitionRuleColumns) ||
+		strings.Contains(r.TopicRule, "{col:")
+}
+
+func (r *DispatchRule) validate() error {
+	isColumnsRule := strings.EqualFold(r.PartitionRule, PartitionRuleColumns)
+	if isColumnsRule && len(r.Columns) == 0 {
+		return cerror.ErrDispatchRuleInvalid.GenWithStackByArgs(
+			fmt.Sprintf("no column is set for the columns dispatcher of %v", r.Matcher))
+	}
+	if !isColumnsRule && len(r.Columns) != 0 {
+		return cerror.ErrDispatchRuleInvalid.GenWithStackByArgs(
+			fmt.Sprintf("columns are only allowed by the columns dispatcher, but got %s of %v",
+				r.PartitionRule, r.Matcher))
+	}
+	for _, col := range r.Columns {
+		if col == "" {
+			return cerror.ErrDispatchRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("empty column name in the dispatch rule of %v", r.Matcher))
+		}
+	}
+	if r.TopicConfig != nil {
+		return r.TopicConfig.Validate()
+	}
 	return nil
 }
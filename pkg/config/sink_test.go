@@ -79,3 +79,115 @@ func TestValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRoutes(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Routes: []*RouteRule{
+			{Matcher: []string{"test.*"}, TargetSchema: "test_routed"},
+			{Matcher: []string{"test.t1"}, TargetTable: "{table}_routed"},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.Routes = []*RouteRule{{TargetSchema: "test_routed"}}
+	require.Regexp(t, ".*matcher is empty.*", cfg.validate(true))
+
+	cfg.Routes = []*RouteRule{{Matcher: []string{"test.*"}}}
+	require.Regexp(t, ".*neither target schema nor target table is set.*", cfg.validate(true))
+}
+
+func TestValidateHeaders(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Headers: &HeadersConfig{
+			Include: []string{HeaderCommitTs, HeaderTable, HeaderClusterID},
+			Static:  map[string]string{"env": "prod"},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.Headers.Include = []string{"commit_ts"}
+	require.Regexp(t, ".*unknown built-in header commit_ts.*", cfg.validate(true))
+
+	cfg.Headers.Include = nil
+	cfg.Headers.Static = map[string]string{"": "prod"}
+	require.Regexp(t, ".*static header name is empty.*", cfg.validate(true))
+
+	cfg.Headers.Static = map[string]string{"ticdc-table": "t1"}
+	require.Regexp(t, ".*uses the reserved prefix ticdc-.*", cfg.validate(true))
+}
+
+func TestValidateDispatchRules(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		DispatchRules: []*DispatchRule{
+			{Matcher: []string{"test.*"}, PartitionRule: "columns", Columns: []string{"tenant"}},
+			{Matcher: []string{"*.*"}, PartitionRule: "ts"},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.DispatchRules[0].Columns = nil
+	require.Regexp(t, ".*no column is set for the columns dispatcher.*", cfg.validate(true))
+
+	cfg.DispatchRules[0].Columns = []string{""}
+	require.Regexp(t, ".*empty column name.*", cfg.validate(true))
+
+	cfg.DispatchRules[0].Columns = []string{"tenant"}
+	cfg.DispatchRules[0].PartitionRule = "table"
+	require.Regexp(t, ".*columns are only allowed by the columns dispatcher.*", cfg.validate(true))
+
+	cfg.DispatchRules[0].PartitionRule = "columns"
+	cfg.Protocol = ProtocolState.String()
+	require.Regexp(t, ".*dispatching the rows of \\[test\\.\\*\\] by the values of the columns "+
+		"is not supported by the state protocol.*", cfg.validate(true))
+	cfg.DispatchRules[0].PartitionRule = "table"
+	cfg.DispatchRules[0].Columns = nil
+	require.Regexp(t, ".*the ts dispatcher of \\[\\*\\.\\*\\] is not supported by the state protocol.*",
+		cfg.validate(true))
+	cfg.DispatchRules[1].PartitionRule = "table"
+	require.Nil(t, cfg.validate(true))
+	cfg.DispatchRules[0].TopicRule = "{schema}_{col:region}"
+	require.Regexp(t, ".*by the values of the columns is not supported by the state protocol.*",
+		cfg.validate(true))
+}
+
+func TestValidateTopicConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		DispatchRules: []*DispatchRule{
+			{
+				Matcher: []string{"test.*"}, TopicRule: "hello_{schema}",
+				TopicConfig: &TopicConfig{
+					PartitionNum: 6, ReplicationFactor: 3, RetentionMs: -1,
+					CleanupPolicy: "compact", MinInsyncReplicas: 2,
+				},
+			},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	for _, tc := range []struct {
+		config  TopicConfig
+		wantErr string
+	}{
+		{TopicConfig{PartitionNum: -1}, ".*partition-num -1 is negative.*"},
+		{TopicConfig{ReplicationFactor: -1}, ".*replication-factor -1 is negative.*"},
+		{TopicConfig{RetentionMs: -2}, ".*retention-ms -2 is less than -1.*"},
+		{TopicConfig{CleanupPolicy: "drop"}, ".*cleanup-policy drop is not one of.*"},
+		{TopicConfig{MinInsyncReplicas: -1}, ".*min-insync-replicas -1 is negative.*"},
+		{
+			TopicConfig{ReplicationFactor: 1, MinInsyncReplicas: 2},
+			".*min-insync-replicas 2 is greater than replication-factor 1.*",
+		},
+	} {
+		config := tc.config
+		cfg.DispatchRules[0].TopicConfig = &config
+		require.Regexp(t, tc.wantErr, cfg.validate(true))
+	}
+}
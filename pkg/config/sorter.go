@@ -15,8 +15,37 @@ package config
 
 import cerror "github.com/pingcap/tiflow/pkg/errors"
 
+const (
+	// SorterEngineDB sorts events in db, see DBConfig.
+	SorterEngineDB = "db"
+	// SorterEngineUnified sorts events in memory and spills them to files
+	// under the sort dir.
+	SorterEngineUnified = "unified"
+
+	// SorterCompressionNone disables compression of sorter data.
+	SorterCompressionNone = "none"
+	// SorterCompressionSnappy compresses sorter data with snappy.
+	SorterCompressionSnappy = "snappy"
+	// SorterCompressionZstd compresses sorter data with zstd.
+	SorterCompressionZstd = "zstd"
+)
+
+// ValidateSorterCompression checks whether the compression is supported.
+func ValidateSorterCompression(compression string) error {
+	switch compression {
+	case SorterCompressionNone, SorterCompressionSnappy, SorterCompressionZstd:
+		return nil
+	default:
+		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs(
+			"compression must be \"none\", \"snappy\" or \"zstd\"")
+	}
+}
+
 // SorterConfig represents sorter config for a changefeed
 type SorterConfig struct {
+	// the sort engine used by changefeeds that choose a disk based sorter,
+	// either "db" or "unified"
+	Engine string `toml:"engine" json:"engine"`
 	// number of concurrent heap sorts
 	NumConcurrentWorker int `toml:"num-concurrent-worker" json:"num-concurrent-worker"`
 	// maximum size for a heap
@@ -29,10 +58,25 @@ type SorterConfig struct {
 	NumWorkerPoolGoroutine int `toml:"num-workerpool-goroutine" json:"num-workerpool-goroutine"`
 	// the directory used to store the temporary files generated by the sorter
 	SortDir string `toml:"sort-dir" json:"sort-dir"`
+	// the compression of events spilled to disk, "none", "snappy" or "zstd",
+	// it can be overridden by changefeeds
+	Compression string `toml:"compression" json:"compression"`
 }
 
 // ValidateAndAdjust validates and adjusts the sorter configuration
 func (c *SorterConfig) ValidateAndAdjust() error {
+	if c.Engine == "" {
+		c.Engine = SorterEngineDB
+	}
+	if c.Engine != SorterEngineDB && c.Engine != SorterEngineUnified {
+		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("engine must be \"db\" or \"unified\"")
+	}
+	if c.Compression == "" {
+		c.Compression = SorterCompressionNone
+	}
+	if err := ValidateSorterCompression(c.Compression); err != nil {
+		return err
+	}
 	if c.ChunkSizeLimit < 1*1024*1024 {
 		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("chunk-size-limit should be at least 1MB")
 	}
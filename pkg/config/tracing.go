@@ -0,0 +1,43 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// TracingConfig represents the config of pipeline tracing.
+type TracingConfig struct {
+	// Enable enables tracing the sampled transactions through the pipeline
+	// of the processor.
+	Enable bool `toml:"enable" json:"enable"`
+	// Endpoint is the address of the OTLP gRPC collector the spans are
+	// exported to, such as `127.0.0.1:4317`.
+	Endpoint string `toml:"endpoint" json:"endpoint"`
+	// SampleRate is the fraction of transactions to trace, in [0, 1].
+	SampleRate float64 `toml:"sample-rate" json:"sample-rate"`
+}
+
+// ValidateAndAdjust validates the tracing config.
+func (c *TracingConfig) ValidateAndAdjust() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"tracing sample-rate must be in [0, 1], got %v", c.SampleRate)
+	}
+	if c.Enable && c.Endpoint == "" {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"tracing is enabled but endpoint is not set")
+	}
+	return nil
+}
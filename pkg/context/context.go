@@ -25,6 +25,7 @@ import (
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/leveldb/system"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/memquota"
 	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/pdtime"
 	"github.com/pingcap/tiflow/pkg/version"
@@ -47,6 +48,7 @@ type GlobalVars struct {
 	PDClock          pdtime.Clock
 	TableActorSystem *system.System
 	SorterSystem     *ssystem.System
+	MemoryGovernor   *memquota.Governor
 
 	// OwnerRevision is the Etcd revision when the owner got elected.
 	OwnerRevision int64
@@ -13,6 +13,13 @@
 
 package db
 
+import (
+	"context"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/tiflow/pkg/config"
+)
+
 // DB is an interface of a leveldb-like database.
 type DB interface {
 	Iterator(lowerBound, upperBound []byte) Iterator
@@ -43,3 +50,30 @@ type Iterator interface {
 	Error() error
 	Release() error
 }
+
+// BlockCache is a block cache that can be shared by multiple DBs.
+// Only pebble DBs use it, leveldb DBs have their own block caches.
+type BlockCache struct {
+	cache *pebble.Cache
+}
+
+// NewBlockCache returns a block cache with the given capacity in bytes.
+func NewBlockCache(capacity int64) *BlockCache {
+	return &BlockCache{cache: pebble.NewCache(capacity)}
+}
+
+// Release releases the reference held by the block cache. Memory is freed
+// after all DBs sharing the cache are closed.
+func (c *BlockCache) Release() {
+	c.cache.Unref()
+}
+
+// Open opens a DB of the backend specified by cfg.
+func Open(
+	ctx context.Context, id int, path string, cache *BlockCache, cfg *config.DBConfig,
+) (DB, error) {
+	if cfg.Backend == config.DBBackendLevelDB {
+		return OpenLevelDB(ctx, id, path, cfg)
+	}
+	return openPebble(ctx, id, path, cache.cache, cfg)
+}
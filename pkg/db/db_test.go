@@ -48,6 +48,32 @@ func TestDB(t *testing.T) {
 	testDB(t, db)
 }
 
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
+	cfg.Count = 2
+	cache := NewBlockCache(1024)
+	defer cache.Release()
+
+	dir := t.TempDir()
+	cfg.Backend = config.DBBackendPebble
+	db1, err := Open(ctx, 0, dir, cache, cfg)
+	require.Nil(t, err)
+	require.IsType(t, &pebbleDB{}, db1)
+	db2, err := Open(ctx, 1, dir, cache, cfg)
+	require.Nil(t, err)
+	require.Nil(t, db1.Close())
+	require.Nil(t, db2.Close())
+
+	cfg.Backend = config.DBBackendLevelDB
+	db3, err := Open(ctx, 0, dir, cache, cfg)
+	require.Nil(t, err)
+	require.IsType(t, &levelDB{}, db3)
+	require.Nil(t, db3.Close())
+}
+
 func testDB(t *testing.T, db DB) {
 	ldb, err := leveldb.Open(storage.NewMemStorage(), &opt.Options{})
 	require.Nil(t, err)
@@ -110,6 +136,19 @@ func testDB(t *testing.T, db DB) {
 	liter.Release()
 	require.Nil(t, iter.Release())
 
+	// DeleteRange
+	batch.Reset()
+	for i := 0; i < 2*deleteRangeBatchSize+1; i++ {
+		batch.Put([]byte(fmt.Sprintf("k%06d", i)), []byte("v"))
+	}
+	require.Nil(t, batch.Commit())
+	require.Nil(t, db.DeleteRange([]byte("k000001"), []byte("k4")))
+	iter = db.Iterator([]byte(""), []byte("k4"))
+	require.True(t, iter.Seek([]byte{}))
+	require.Equal(t, []byte("k000000"), iter.Key())
+	require.False(t, iter.Next())
+	require.Nil(t, iter.Release())
+
 	// Compact
 	require.Nil(t, db.Compact([]byte{0x00}, []byte{0xff}))
 
@@ -125,7 +164,7 @@ func TestPebbleMetrics(t *testing.T) {
 	cfg.Count = 1
 
 	id := 1
-	option, ws := buildPebbleOption(id, 0, cfg)
+	option, ws := buildPebbleOption(id, pebble.NewCache(0), cfg)
 	db, err := pebble.Open(t.TempDir(), &option)
 	require.Nil(t, err)
 	pdb := &pebbleDB{
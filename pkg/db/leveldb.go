@@ -23,7 +23,6 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/sorter"
 	"github.com/pingcap/tiflow/pkg/config"
-	"github.com/pingcap/tiflow/pkg/errors"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -101,8 +100,32 @@ func (p *levelDB) Batch(cap int) Batch {
 	}
 }
 
+// leveldb does not support range tombstones, so DeleteRange deletes keys
+// one by one and commits them in batches of deleteRangeBatchSize.
+const deleteRangeBatchSize = 1024
+
 func (p *levelDB) DeleteRange(start, end []byte) error {
-	return errors.ErrUnimplemented.FastGenByArgs("leveldb.DeleteRange")
+	iter := p.db.NewIterator(&util.Range{Start: start, Limit: end}, nil)
+	defer iter.Release()
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Delete(iter.Key())
+		if batch.Len() >= deleteRangeBatchSize {
+			if err := p.db.Write(batch, nil); err != nil {
+				return cerrors.ErrLevelDBSorterError.GenWithStackByArgs(err)
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return cerrors.ErrLevelDBSorterError.GenWithStackByArgs(err)
+	}
+	if batch.Len() != 0 {
+		if err := p.db.Write(batch, nil); err != nil {
+			return cerrors.ErrLevelDBSorterError.GenWithStackByArgs(err)
+		}
+	}
+	return nil
 }
 
 func (p *levelDB) Compact(start, end []byte) error {
@@ -47,12 +47,14 @@ func (logger *pebbleLogger) Fatalf(format string, args ...interface{}) {
 
 // TODO: Update DB config once we switch to pebble,
 //       as some configs are not applicable to pebble.
-func buildPebbleOption(id int, memInByte int, cfg *config.DBConfig) (pebble.Options, *writeStall) {
+func buildPebbleOption(
+	id int, cache *pebble.Cache, cfg *config.DBConfig,
+) (pebble.Options, *writeStall) {
 	var option pebble.Options
 	option.ErrorIfExists = true
 	option.DisableWAL = false // Delete range requires WAL.
 	option.MaxOpenFiles = cfg.MaxOpenFiles / cfg.Count
-	option.Cache = pebble.NewCache(int64(memInByte))
+	option.Cache = cache
 	option.MaxConcurrentCompactions = 6
 	option.L0CompactionThreshold = cfg.CompactionL0Trigger
 	option.L0StopWritesThreshold = cfg.WriteL0PauseTrigger
@@ -67,7 +69,7 @@ func buildPebbleOption(id int, memInByte int, cfg *config.DBConfig) (pebble.Opti
 		l.FilterPolicy = bloom.FilterPolicy(10)
 		l.FilterType = pebble.TableFilter
 		if i == 0 {
-			l.TargetFileSize = 8 << 20 // 8 MB
+			l.TargetFileSize = int64(cfg.TargetFileSizeBase)
 		} else if i < 4 {
 			l.TargetFileSize = option.Levels[i-1].TargetFileSize * 2
 		}
@@ -101,7 +103,16 @@ func buildPebbleOption(id int, memInByte int, cfg *config.DBConfig) (pebble.Opti
 func OpenPebble(
 	ctx context.Context, id int, path string, memInByte int, cfg *config.DBConfig,
 ) (DB, error) {
-	option, ws := buildPebbleOption(id, memInByte, cfg)
+	cache := pebble.NewCache(int64(memInByte))
+	// The opened db holds its own reference.
+	defer cache.Unref()
+	return openPebble(ctx, id, path, cache, cfg)
+}
+
+func openPebble(
+	ctx context.Context, id int, path string, cache *pebble.Cache, cfg *config.DBConfig,
+) (DB, error) {
+	option, ws := buildPebbleOption(id, cache, cfg)
 	dbDir := filepath.Join(path, fmt.Sprintf("%04d", id))
 	err := retry.Do(ctx, func() error {
 		err1 := os.RemoveAll(dbDir)
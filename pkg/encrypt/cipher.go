@@ -0,0 +1,217 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// sealedVersion is the first byte of sealed data.
+	sealedVersion byte = 1
+	// sealedHeaderSize is the size of version and key id.
+	sealedHeaderSize = 1 + 4
+	// HashSize is the size of keyed hash returned by Cipher.Hash.
+	HashSize = 16
+)
+
+// Cipher encrypts and decrypts data with AES-GCM.
+//
+// Sealed data is formatted as:
+//
+//	[version, 1 byte][key id, 4 bytes][nonce, 12 bytes][ciphertext and tag]
+type Cipher struct {
+	keyID   uint32
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+// NewCipher returns a cipher with the given AES key.
+// The key must be 16, 24 or 32 bytes long.
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, cerror.ErrInvalidEncryptionKey.GenWithStackByArgs(err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, cerror.ErrInvalidEncryptionKey.GenWithStackByArgs(err.Error())
+	}
+	sum := sha256.Sum256(key)
+	hashKey := sha256.Sum256(append([]byte("ticdc-hash-key"), key...))
+	return &Cipher{
+		keyID:   binary.BigEndian.Uint32(sum[:4]),
+		aead:    aead,
+		hashKey: hashKey[:],
+	}, nil
+}
+
+// KeyID returns the id of the key, which is derived from the key itself.
+func (c *Cipher) KeyID() uint32 {
+	return c.keyID
+}
+
+// Overhead returns the difference between the lengths of sealed data and
+// plaintext.
+func (c *Cipher) Overhead() int {
+	return sealedHeaderSize + c.aead.NonceSize() + c.aead.Overhead()
+}
+
+// Seal encrypts and authenticates plaintext, appends the result to dst and
+// returns the updated slice.
+func (c *Cipher) Seal(dst, plaintext []byte) []byte {
+	var header [sealedHeaderSize]byte
+	header[0] = sealedVersion
+	binary.BigEndian.PutUint32(header[1:], c.keyID)
+	dst = append(dst, header[:]...)
+
+	// Every message uses a random 12-byte nonce, since the same key is shared
+	// by the processes and kept across restarts. The probability of a nonce
+	// collision is negligible as long as a key seals less than 2^32 messages,
+	// rotate the key before that.
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		log.Panic("failed to generate nonce", zap.Error(err))
+	}
+	dst = append(dst, nonce...)
+	return c.aead.Seal(dst, nonce, plaintext, header[:])
+}
+
+// Open decrypts and authenticates sealed data, appends the result to dst
+// and returns the updated slice.
+func (c *Cipher) Open(dst, sealed []byte) ([]byte, error) {
+	keyID, ok := SealedKeyID(sealed)
+	if !ok {
+		return nil, cerror.ErrDecryptFailed.GenWithStackByArgs()
+	}
+	if err := VerifyKeyID(c, keyID); err != nil {
+		return nil, err
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < sealedHeaderSize+nonceSize {
+		return nil, cerror.ErrDecryptFailed.GenWithStackByArgs()
+	}
+	header := sealed[:sealedHeaderSize]
+	nonce := sealed[sealedHeaderSize : sealedHeaderSize+nonceSize]
+	dst, err := c.aead.Open(dst, nonce, sealed[sealedHeaderSize+nonceSize:], header)
+	if err != nil {
+		// The error of AEAD only says authentication failed.
+		return nil, cerror.ErrDecryptFailed.GenWithStackByArgs()
+	}
+	return dst, nil
+}
+
+// Hash appends a keyed hash of data to dst and returns the updated slice.
+// It hides data while keeping equality, so that it can be used in keys
+// that must be stored in plaintext.
+func (c *Cipher) Hash(dst, data []byte) []byte {
+	h := hmac.New(sha256.New, c.hashKey)
+	h.Write(data)
+	return append(dst, h.Sum(nil)[:HashSize]...)
+}
+
+// SealedKeyID returns the key id of sealed data.
+func SealedKeyID(sealed []byte) (uint32, bool) {
+	if len(sealed) < sealedHeaderSize || sealed[0] != sealedVersion {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(sealed[1:sealedHeaderSize]), true
+}
+
+// VerifyKeyID checks whether data encrypted by keyID can be decrypted by c.
+// c can be nil, which means encryption is disabled.
+func VerifyKeyID(c *Cipher, keyID uint32) error {
+	if c == nil {
+		return cerror.ErrEncryptionKeyMissing.GenWithStackByArgs(keyID)
+	}
+	if c.keyID != keyID {
+		return cerror.ErrEncryptionKeyMismatch.GenWithStackByArgs(keyID, c.keyID)
+	}
+	return nil
+}
+
+// LoadCipher loads the key specified by cfg and returns a cipher.
+// It returns nil if encryption is disabled.
+func LoadCipher(cfg *config.EncryptionConfig) (*Cipher, error) {
+	if cfg == nil || !cfg.Enable {
+		return nil, nil
+	}
+	var hexKey string
+	if cfg.KeyFile != "" {
+		content, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, cerror.ErrInvalidEncryptionKey.GenWithStackByArgs(err.Error())
+		}
+		hexKey = string(content)
+	} else {
+		var ok bool
+		hexKey, ok = os.LookupEnv(cfg.KeyEnv)
+		if !ok {
+			return nil, cerror.ErrInvalidEncryptionKey.GenWithStackByArgs(
+				"environment variable " + cfg.KeyEnv + " is not set")
+		}
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(hexKey))
+	if err != nil {
+		return nil, cerror.ErrInvalidEncryptionKey.GenWithStackByArgs(err.Error())
+	}
+	return NewCipher(key)
+}
+
+var globalCipher atomic.Value // *Cipher
+
+// InitGlobalCipher loads the cipher specified by cfg and stores it globally.
+func InitGlobalCipher(cfg *config.EncryptionConfig) error {
+	c, err := LoadCipher(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c != nil {
+		log.Info("spill-to-disk encryption is enabled",
+			zap.String("keyID", fmt.Sprintf("%08x", c.keyID)))
+	}
+	StoreGlobalCipher(c)
+	return nil
+}
+
+// StoreGlobalCipher stores the global cipher, nil disables encryption.
+func StoreGlobalCipher(c *Cipher) {
+	globalCipher.Store(&c)
+}
+
+// GetGlobalCipher returns the global cipher, it returns nil if encryption
+// is disabled.
+func GetGlobalCipher() *Cipher {
+	c, ok := globalCipher.Load().(**Cipher)
+	if !ok {
+		return nil
+	}
+	return *c
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipherSealOpen(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCipher(bytes.Repeat([]byte{1}, 32))
+	require.Nil(t, err)
+
+	plaintext := []byte("sensitive row data")
+	sealed := c.Seal(nil, plaintext)
+	require.Len(t, sealed, len(plaintext)+c.Overhead())
+	require.False(t, bytes.Contains(sealed, plaintext))
+	keyID, ok := SealedKeyID(sealed)
+	require.True(t, ok)
+	require.Equal(t, c.KeyID(), keyID)
+
+	// Nonces must not be reused, even by the ciphers of the same key.
+	require.NotEqual(t, sealed, c.Seal(nil, plaintext))
+	c2, err := NewCipher(bytes.Repeat([]byte{1}, 32))
+	require.Nil(t, err)
+	require.NotEqual(t, sealed, c2.Seal(nil, plaintext))
+
+	opened, err := c.Open(nil, sealed)
+	require.Nil(t, err)
+	require.Equal(t, plaintext, opened)
+
+	// Tampered data.
+	sealed[len(sealed)-1] ^= 0xff
+	_, err = c.Open(nil, sealed)
+	require.True(t, cerror.ErrDecryptFailed.Equal(err))
+
+	// Data encrypted by another key.
+	c1, err := NewCipher(bytes.Repeat([]byte{2}, 16))
+	require.Nil(t, err)
+	_, err = c1.Open(nil, c.Seal(nil, plaintext))
+	require.True(t, cerror.ErrEncryptionKeyMismatch.Equal(err))
+	require.True(t, cerror.ErrEncryptionKeyMissing.Equal(VerifyKeyID(nil, c.KeyID())))
+
+	// Plaintext is not sealed data.
+	_, ok = SealedKeyID(plaintext)
+	require.False(t, ok)
+
+	_, err = NewCipher([]byte("short"))
+	require.True(t, cerror.ErrInvalidEncryptionKey.Equal(err))
+}
+
+func TestCipherHash(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCipher(bytes.Repeat([]byte{1}, 32))
+	require.Nil(t, err)
+	h1 := c.Hash(nil, []byte("k1"))
+	require.Len(t, h1, HashSize)
+	require.Equal(t, h1, c.Hash(nil, []byte("k1")))
+	require.NotEqual(t, h1, c.Hash(nil, []byte("k2")))
+	require.Equal(t, append([]byte("p"), h1...), c.Hash([]byte("p"), []byte("k1")))
+}
+
+func TestLoadCipher(t *testing.T) {
+	t.Parallel()
+
+	c, err := LoadCipher(&config.EncryptionConfig{})
+	require.Nil(t, err)
+	require.Nil(t, c)
+
+	hexKey := "000102030405060708090a0b0c0d0e0f"
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.Nil(t, os.WriteFile(keyFile, []byte(hexKey+"\n"), 0o600))
+	c, err = LoadCipher(&config.EncryptionConfig{Enable: true, KeyFile: keyFile})
+	require.Nil(t, err)
+	require.NotNil(t, c)
+
+	c1, err := NewCipher([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15})
+	require.Nil(t, err)
+	require.Equal(t, c1.KeyID(), c.KeyID())
+
+	_, err = LoadCipher(&config.EncryptionConfig{
+		Enable: true, KeyFile: filepath.Join(t.TempDir(), "not-exist"),
+	})
+	require.True(t, cerror.ErrInvalidEncryptionKey.Equal(err))
+	_, err = LoadCipher(&config.EncryptionConfig{
+		Enable: true, KeyEnv: "TICDC_TEST_ENCRYPTION_KEY_NOT_EXIST",
+	})
+	require.True(t, cerror.ErrInvalidEncryptionKey.Equal(err))
+	require.Nil(t, os.WriteFile(keyFile, []byte("not hex"), 0o600))
+	_, err = LoadCipher(&config.EncryptionConfig{Enable: true, KeyFile: keyFile})
+	require.True(t, cerror.ErrInvalidEncryptionKey.Equal(err))
+}
+
+func TestLoadCipherFromEnv(t *testing.T) {
+	t.Setenv("TICDC_TEST_ENCRYPTION_KEY", "000102030405060708090a0b0c0d0e0f")
+	c, err := LoadCipher(&config.EncryptionConfig{
+		Enable: true, KeyEnv: "TICDC_TEST_ENCRYPTION_KEY",
+	})
+	require.Nil(t, err)
+	require.NotNil(t, c)
+
+	require.Nil(t, GetGlobalCipher())
+	StoreGlobalCipher(c)
+	require.Equal(t, c, GetGlobalCipher())
+	StoreGlobalCipher(nil)
+	require.Nil(t, GetGlobalCipher())
+}
@@ -175,6 +175,34 @@ var (
 		"filter rule is invalid",
 		errors.RFCCodeText("CDC:ErrFilterRuleInvalid"),
 	)
+	ErrRouteRuleInvalid = errors.Normalize(
+		"route rule is invalid: %s",
+		errors.RFCCodeText("CDC:ErrRouteRuleInvalid"),
+	)
+	ErrRowImageRuleInvalid = errors.Normalize(
+		"row image rule is invalid: %s",
+		errors.RFCCodeText("CDC:ErrRowImageRuleInvalid"),
+	)
+	ErrHeaderConfigInvalid = errors.Normalize(
+		"header config is invalid: %s",
+		errors.RFCCodeText("CDC:ErrHeaderConfigInvalid"),
+	)
+	ErrDispatchRuleInvalid = errors.Normalize(
+		"dispatch rule is invalid: %s",
+		errors.RFCCodeText("CDC:ErrDispatchRuleInvalid"),
+	)
+	ErrTopicConfigInvalid = errors.Normalize(
+		"topic config is invalid: %s",
+		errors.RFCCodeText("CDC:ErrTopicConfigInvalid"),
+	)
+	ErrDDLRewriterNotFound = errors.Normalize(
+		"ddl rewriter %s not found",
+		errors.RFCCodeText("CDC:ErrDDLRewriterNotFound"),
+	)
+	ErrRewriteDDLFailed = errors.Normalize(
+		"rewrite ddl failed, query: %s",
+		errors.RFCCodeText("CDC:ErrRewriteDDLFailed"),
+	)
 
 	// internal errors
 	ErrAdminStopProcessor = errors.Normalize(
@@ -244,6 +272,10 @@ var (
 		"invalid topic expression",
 		errors.RFCCodeText("CDC:ErrKafkaTopicExprInvalid"),
 	)
+	ErrKafkaInvalidTopicName = errors.Normalize(
+		"invalid topic name %s: %s",
+		errors.RFCCodeText("CDC:ErrKafkaInvalidTopicName"),
+	)
 	ErrPulsarNewProducer = errors.Normalize(
 		"new pulsar producer",
 		errors.RFCCodeText("CDC:ErrPulsarNewProducer"),
@@ -354,6 +386,10 @@ var (
 		"maxwell encode failed",
 		errors.RFCCodeText("CDC:ErrMaxwellEncodeFailed"),
 	)
+	ErrStateEncodeFailed = errors.Normalize(
+		"state encode failed",
+		errors.RFCCodeText("CDC:ErrStateEncodeFailed"),
+	)
 	ErrMaxwellDecodeFailed = errors.Normalize(
 		"maxwell decode failed",
 		errors.RFCCodeText("CDC:ErrMaxwellDecodeFailed"),
@@ -486,6 +522,10 @@ var (
 			`eg, "simple-changefeed-task"`),
 		errors.RFCCodeText("CDC:ErrInvalidChangefeedID"),
 	)
+	ErrInvalidCaptureID = errors.Normalize(
+		"bad capture id %s, it must be a UUID",
+		errors.RFCCodeText("CDC:ErrInvalidCaptureID"),
+	)
 	ErrInvalidEtcdKey = errors.Normalize(
 		"invalid key: %s",
 		errors.RFCCodeText("CDC:ErrInvalidEtcdKey"),
@@ -624,6 +664,18 @@ var (
 		"internal server error",
 		errors.RFCCodeText("CDC:ErrInternalServerError"),
 	)
+	ErrQueryTableStatus = errors.Normalize(
+		"query table status from capture %s failed: %s",
+		errors.RFCCodeText("CDC:ErrQueryTableStatus"),
+	)
+	ErrAPIUnauthorized = errors.Normalize(
+		"unauthorized: %s",
+		errors.RFCCodeText("CDC:ErrAPIUnauthorized"),
+	)
+	ErrAPIForbidden = errors.Normalize(
+		"user %s with role %s is not allowed to access %s %s",
+		errors.RFCCodeText("CDC:ErrAPIForbidden"),
+	)
 	ErrOwnerSortDir = errors.Normalize(
 		"owner sort dir",
 		errors.RFCCodeText("CDC:ErrOwnerSortDir"),
@@ -636,6 +688,34 @@ var (
 		"changefeed update error: %s",
 		errors.RFCCodeText("CDC:ErrChangefeedUpdateRefused"),
 	)
+	ErrInvalidChangefeedPriority = errors.Normalize(
+		"invalid changefeed priority: %s, must be one of high, normal and low",
+		errors.RFCCodeText("CDC:ErrInvalidChangefeedPriority"),
+	)
+	ErrDrainCaptureRefused = errors.Normalize(
+		"drain capture %s refused: %s",
+		errors.RFCCodeText("CDC:ErrDrainCaptureRefused"),
+	)
+	ErrChangefeedTemplateNotExists = errors.Normalize(
+		"changefeed template not exists, %s",
+		errors.RFCCodeText("CDC:ErrChangefeedTemplateNotExists"),
+	)
+	ErrChangefeedTemplateAlreadyExists = errors.Normalize(
+		"changefeed template already exists, %s",
+		errors.RFCCodeText("CDC:ErrChangefeedTemplateAlreadyExists"),
+	)
+	ErrInvalidChangefeedLabel = errors.Normalize(
+		"invalid changefeed label: %s",
+		errors.RFCCodeText("CDC:ErrInvalidChangefeedLabel"),
+	)
+	ErrInvalidLabelSelector = errors.Normalize(
+		"invalid label selector %s: %s",
+		errors.RFCCodeText("CDC:ErrInvalidLabelSelector"),
+	)
+	ErrInvalidChangefeedBundle = errors.Normalize(
+		"invalid changefeed bundle: %s",
+		errors.RFCCodeText("CDC:ErrInvalidChangefeedBundle"),
+	)
 	ErrChangefeedAbnormalState = errors.Normalize(
 		"changefeed in abnormal state: %s, replication status: %+v",
 		errors.RFCCodeText("CDC:ErrChangefeedAbnormalState"),
@@ -849,6 +929,10 @@ var (
 		"sorter is closed",
 		errors.RFCCodeText("CDC:ErrSorterClosed"),
 	)
+	ErrSorterDecompressFailed = errors.Normalize(
+		"sorter decompress data failed, codec: %s",
+		errors.RFCCodeText("CDC:ErrSorterDecompressFailed"),
+	)
 
 	// processor errors
 	ErrProcessorDuplicateOperations = errors.Normalize(
@@ -884,6 +968,10 @@ var (
 		"event is larger than the total memory quota, size: %d, quota: %d",
 		errors.RFCCodeText("CDC:ErrFlowControllerEventLargerThanQuota"),
 	)
+	ErrMemoryQuotaClosed = errors.Normalize(
+		"memory quota of %s is closed",
+		errors.RFCCodeText("CDC:ErrMemoryQuotaClosed"),
+	)
 
 	// retry error
 	ErrReachMaxTry = errors.Normalize("reach maximum try: %d",
@@ -966,6 +1054,30 @@ var (
 		errors.RFCCodeText("CDC:ErrPeerMessageInjectedServerRestart"),
 	)
 
+	// encryption related errors
+	ErrInvalidEncryptionKey = errors.Normalize(
+		"invalid encryption key: %s",
+		errors.RFCCodeText("CDC:ErrInvalidEncryptionKey"),
+	)
+	ErrEncryptionKeyMismatch = errors.Normalize(
+		"data is encrypted by key %08x, but the current key is %08x",
+		errors.RFCCodeText("CDC:ErrEncryptionKeyMismatch"),
+	)
+	ErrEncryptionKeyMissing = errors.Normalize(
+		"data is encrypted by key %08x, but encryption is not enabled",
+		errors.RFCCodeText("CDC:ErrEncryptionKeyMissing"),
+	)
+	ErrDecryptFailed = errors.Normalize(
+		"decrypt data failed",
+		errors.RFCCodeText("CDC:ErrDecryptFailed"),
+	)
+
+	// tracing related errors
+	ErrInitTracing = errors.Normalize(
+		"init tracing failed",
+		errors.RFCCodeText("CDC:ErrInitTracing"),
+	)
+
 	// RESTful client error
 	ErrRewindRequestBodyError = errors.Normalize(
 		"failed to seek to the beginning of request body",
@@ -48,6 +48,11 @@ const (
 	TaskPositionKeyPrefix = TaskKeyPrefix + "/position"
 	// JobKeyPrefix is the prefix of job keys
 	JobKeyPrefix = EtcdKeyBase + "/job"
+	// ChangefeedTemplateKeyPrefix is the prefix of changefeed template keys.
+	// The templates are only read by the open API, they are kept out of
+	// EtcdKeyBase, which is watched by the captures as a byte prefix, so that
+	// the captures that don't know the templates never receive them.
+	ChangefeedTemplateKeyPrefix = "/tidb/changefeed-template"
 )
 
 // GetEtcdKeyChangeFeedList returns the prefix key of all changefeed config
@@ -60,6 +65,16 @@ func GetEtcdKeyChangeFeedInfo(changefeedID string) string {
 	return fmt.Sprintf("%s/%s", GetEtcdKeyChangeFeedList(), changefeedID)
 }
 
+// GetEtcdKeyChangefeedTemplateList returns the prefix key of all changefeed templates
+func GetEtcdKeyChangefeedTemplateList() string {
+	return ChangefeedTemplateKeyPrefix
+}
+
+// GetEtcdKeyChangefeedTemplate returns the key of a changefeed template
+func GetEtcdKeyChangefeedTemplate(name string) string {
+	return GetEtcdKeyChangefeedTemplateList() + "/" + name
+}
+
 // GetEtcdKeyTaskPosition returns the key of a task position
 func GetEtcdKeyTaskPosition(changefeedID, captureID string) string {
 	return TaskPositionKeyPrefix + "/" + captureID + "/" + changefeedID
@@ -106,6 +121,10 @@ func (c CDCEtcdClient) Close() error {
 // ClearAllCDCInfo delete all keys created by CDC
 func (c CDCEtcdClient) ClearAllCDCInfo(ctx context.Context) error {
 	_, err := c.Client.Delete(ctx, EtcdKeyBase, clientv3.WithPrefix())
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	_, err = c.Client.Delete(ctx, ChangefeedTemplateKeyPrefix+"/", clientv3.WithPrefix())
 	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
 }
 
@@ -334,6 +353,89 @@ func (c CDCEtcdClient) SaveChangeFeedInfo(ctx context.Context, info *model.Chang
 	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
 }
 
+// CreateChangefeedTemplate stores a new changefeed template into etcd,
+// it fails if a template with the same name exists.
+func (c CDCEtcdClient) CreateChangefeedTemplate(ctx context.Context, tpl *model.ChangefeedTemplate) error {
+	key := GetEtcdKeyChangefeedTemplate(tpl.Name)
+	value, err := tpl.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := c.Client.Txn(ctx,
+		[]clientv3.Cmp{clientv3.Compare(clientv3.ModRevision(key), "=", 0)},
+		[]clientv3.Op{clientv3.OpPut(key, value)}, TxnEmptyOpsElse)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if !resp.Succeeded {
+		return cerror.ErrChangefeedTemplateAlreadyExists.GenWithStackByArgs(tpl.Name)
+	}
+	return nil
+}
+
+// UpdateChangefeedTemplate replaces an existing changefeed template in etcd.
+func (c CDCEtcdClient) UpdateChangefeedTemplate(ctx context.Context, tpl *model.ChangefeedTemplate) error {
+	key := GetEtcdKeyChangefeedTemplate(tpl.Name)
+	value, err := tpl.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := c.Client.Txn(ctx,
+		[]clientv3.Cmp{clientv3.Compare(clientv3.ModRevision(key), ">", 0)},
+		[]clientv3.Op{clientv3.OpPut(key, value)}, TxnEmptyOpsElse)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if !resp.Succeeded {
+		return cerror.ErrChangefeedTemplateNotExists.GenWithStackByArgs(tpl.Name)
+	}
+	return nil
+}
+
+// GetChangefeedTemplate queries a changefeed template by its name
+func (c CDCEtcdClient) GetChangefeedTemplate(ctx context.Context, name string) (*model.ChangefeedTemplate, error) {
+	resp, err := c.Client.Get(ctx, GetEtcdKeyChangefeedTemplate(name))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if resp.Count == 0 {
+		return nil, cerror.ErrChangefeedTemplateNotExists.GenWithStackByArgs(name)
+	}
+	tpl := &model.ChangefeedTemplate{}
+	err = tpl.Unmarshal(resp.Kvs[0].Value)
+	return tpl, errors.Trace(err)
+}
+
+// GetChangefeedTemplates queries all changefeed templates, ordered by name
+func (c CDCEtcdClient) GetChangefeedTemplates(ctx context.Context) ([]*model.ChangefeedTemplate, error) {
+	resp, err := c.Client.Get(ctx, GetEtcdKeyChangefeedTemplateList()+"/",
+		clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	templates := make([]*model.ChangefeedTemplate, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		tpl := &model.ChangefeedTemplate{}
+		if err := tpl.Unmarshal(kv.Value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		templates = append(templates, tpl)
+	}
+	return templates, nil
+}
+
+// DeleteChangefeedTemplate deletes a changefeed template from etcd
+func (c CDCEtcdClient) DeleteChangefeedTemplate(ctx context.Context, name string) error {
+	resp, err := c.Client.Delete(ctx, GetEtcdKeyChangefeedTemplate(name))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if resp.Deleted == 0 {
+		return cerror.ErrChangefeedTemplateNotExists.GenWithStackByArgs(name)
+	}
+	return nil
+}
+
 // GetProcessors queries all processors of the cdc cluster,
 // and returns a slice of ProcInfoSnap(without table info)
 func (c CDCEtcdClient) GetProcessors(ctx context.Context) ([]*model.ProcInfoSnap, error) {
@@ -547,6 +649,49 @@ func (c CDCEtcdClient) PutCaptureInfo(ctx context.Context, info *model.CaptureIn
 	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
 }
 
+// SetCaptureDraining marks the capture as draining in its capture info.
+// The info is written with the lease of the capture, so that it is still
+// removed once the capture is gone.
+func (c CDCEtcdClient) SetCaptureDraining(ctx context.Context, id string) error {
+	key := GetEtcdKeyCaptureInfo(id)
+	for {
+		resp, err := c.Client.Get(ctx, key)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if len(resp.Kvs) == 0 {
+			return cerror.ErrCaptureNotExist.GenWithStackByArgs(key)
+		}
+		kv := resp.Kvs[0]
+		info := new(model.CaptureInfo)
+		if err := info.Unmarshal(kv.Value); err != nil {
+			return errors.Trace(err)
+		}
+		if info.Draining {
+			return nil
+		}
+		info.Draining = true
+		value, err := info.Marshal()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cmps := []clientv3.Cmp{
+			clientv3.Compare(clientv3.ModRevision(key), "=", kv.ModRevision),
+		}
+		opsThen := []clientv3.Op{
+			clientv3.OpPut(key, string(value), clientv3.WithLease(clientv3.LeaseID(kv.Lease))),
+		}
+		txnResp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if txnResp.Succeeded {
+			return nil
+		}
+		// The capture info is changed concurrently, retry with the new one.
+	}
+}
+
 // DeleteCaptureInfo delete capture info from etcd.
 func (c CDCEtcdClient) DeleteCaptureInfo(ctx context.Context, id string) error {
 	key := GetEtcdKeyCaptureInfo(id)
@@ -326,6 +326,52 @@ func TestCreateChangefeed(t *testing.T) {
 	require.True(t, cerror.ErrChangeFeedAlreadyExists.Equal(err))
 }
 
+func TestOpChangefeedTemplate(t *testing.T) {
+	s := &etcdTester{}
+	s.setUpTest(t)
+	defer s.tearDownTest(t)
+	ctx := context.Background()
+
+	tpl := &model.ChangefeedTemplate{
+		Name:    "kafka",
+		SinkURI: "kafka://127.0.0.1:9092/topic",
+		Labels:  map[string]string{"env": "prod"},
+	}
+	require.NoError(t, s.client.CreateChangefeedTemplate(ctx, tpl))
+	err := s.client.CreateChangefeedTemplate(ctx, tpl)
+	require.True(t, cerror.ErrChangefeedTemplateAlreadyExists.Equal(err))
+	require.NoError(t, s.client.CreateChangefeedTemplate(ctx,
+		&model.ChangefeedTemplate{Name: "blackhole", SinkURI: "blackhole://"}))
+	// The templates are out of the prefix watched by the captures.
+	kvs, err := s.client.GetAllCDCInfo(ctx)
+	require.NoError(t, err)
+	require.Empty(t, kvs)
+
+	tpl.SinkURI = "kafka://127.0.0.1:9093/topic"
+	require.NoError(t, s.client.UpdateChangefeedTemplate(ctx, tpl))
+	err = s.client.UpdateChangefeedTemplate(ctx, &model.ChangefeedTemplate{Name: "mysql"})
+	require.True(t, cerror.ErrChangefeedTemplateNotExists.Equal(err))
+
+	obtained, err := s.client.GetChangefeedTemplate(ctx, "kafka")
+	require.NoError(t, err)
+	require.Equal(t, tpl, obtained)
+	_, err = s.client.GetChangefeedTemplate(ctx, "mysql")
+	require.True(t, cerror.ErrChangefeedTemplateNotExists.Equal(err))
+
+	templates, err := s.client.GetChangefeedTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	require.Equal(t, "blackhole", templates[0].Name)
+	require.Equal(t, "kafka", templates[1].Name)
+
+	require.NoError(t, s.client.DeleteChangefeedTemplate(ctx, "kafka"))
+	err = s.client.DeleteChangefeedTemplate(ctx, "kafka")
+	require.True(t, cerror.ErrChangefeedTemplateNotExists.Equal(err))
+	templates, err = s.client.GetChangefeedTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+}
+
 func TestGetAllCaptureLeases(t *testing.T) {
 	s := &etcdTester{}
 	s.setUpTest(t)
@@ -377,6 +423,36 @@ func TestGetAllCaptureLeases(t *testing.T) {
 	require.Equal(t, queryLeases, map[string]int64{})
 }
 
+func TestSetCaptureDraining(t *testing.T) {
+	s := &etcdTester{}
+	s.setUpTest(t)
+	defer s.tearDownTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	info := &model.CaptureInfo{
+		ID:            "a3f41a6a-3c31-44f4-aa27-344c1b8cd658",
+		AdvertiseAddr: "127.0.0.1:8301",
+	}
+	err := s.client.SetCaptureDraining(ctx, info.ID)
+	require.True(t, cerror.ErrCaptureNotExist.Equal(err))
+
+	sess, err := concurrency.NewSession(s.client.Client.Unwrap(),
+		concurrency.WithTTL(10), concurrency.WithContext(ctx))
+	require.NoError(t, err)
+	require.NoError(t, s.client.PutCaptureInfo(ctx, info, sess.Lease()))
+	require.NoError(t, s.client.SetCaptureDraining(ctx, info.ID))
+	require.NoError(t, s.client.SetCaptureDraining(ctx, info.ID))
+	obtained, err := s.client.GetCaptureInfo(ctx, info.ID)
+	require.NoError(t, err)
+	require.True(t, obtained.Draining)
+
+	// The capture info is still attached to the lease of the capture.
+	leases, err := s.client.GetCaptureLeases(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{info.ID: int64(sess.Lease())}, leases)
+}
+
 const (
 	testOwnerRevisionForMaxEpochs = 16
 )
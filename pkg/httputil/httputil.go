@@ -43,3 +43,49 @@ func NewClient(credential *security.Credential) (*Client, error) {
 		Client: http.Client{Transport: transport},
 	}, nil
 }
+
+// Auth is the credential sent to the HTTP API of TiCDC when the API
+// authentication is enabled, Token takes precedence over User and Password.
+type Auth struct {
+	Token    string
+	User     string
+	Password string
+}
+
+// IsEmpty returns true if neither a token nor a user is set.
+func (a *Auth) IsEmpty() bool {
+	return a == nil || (a.Token == "" && a.User == "")
+}
+
+// SetAuth makes the client send the credential in the Authorization header
+// of every request.
+func (c *Client) SetAuth(auth *Auth) {
+	if auth.IsEmpty() {
+		return
+	}
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c.Transport = &authTransport{auth: *auth, next: transport}
+}
+
+type authTransport struct {
+	auth Auth
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" {
+		return t.next.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the request.
+	req = req.Clone(req.Context())
+	if t.auth.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.auth.Token)
+	} else {
+		req.SetBasicAuth(t.auth.User, t.auth.Password)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -70,6 +71,30 @@ func TestHttputilNewClient(t *testing.T) {
 	require.Equal(t, httputilServerMsg, string(body))
 }
 
+func TestClientSetAuth(t *testing.T) {
+	t.Parallel()
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	get := func(auth *Auth) string {
+		cli, err := NewClient(nil)
+		require.Nil(t, err)
+		cli.SetAuth(auth)
+		resp, err := cli.Get(server.URL)
+		require.Nil(t, err)
+		require.Nil(t, resp.Body.Close())
+		return authorization
+	}
+	require.Equal(t, "", get(nil))
+	require.Equal(t, "", get(&Auth{}))
+	require.Equal(t, "Bearer token", get(&Auth{Token: "token", User: "root"}))
+	require.Equal(t, "Basic cm9vdDoxMjM0NTY=", get(&Auth{User: "root", Password: "123456"}))
+}
+
 func handler(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
 	//nolint:errcheck
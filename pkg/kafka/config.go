@@ -27,4 +27,10 @@ const (
 	// See: https://kafka.apache.org/documentation/#brokerconfigs_min.insync.replicas and
 	// https://kafka.apache.org/documentation/#topicconfigs_min.insync.replicas
 	MinInsyncReplicasConfigName = "min.insync.replicas"
+	// RetentionMsConfigName specifies the maximum time a topic retains a log before discarding it.
+	// See: https://kafka.apache.org/documentation/#topicconfigs_retention.ms
+	RetentionMsConfigName = "retention.ms"
+	// CleanupPolicyConfigName specifies the retention policy of the old log segments of a topic.
+	// See: https://kafka.apache.org/documentation/#topicconfigs_cleanup.policy
+	CleanupPolicyConfigName = "cleanup.policy"
 )
@@ -0,0 +1,456 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memquota
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/priority"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Component is a component of a changefeed that buffers events in memory.
+type Component string
+
+// Components that reserve memory from the governor.
+const (
+	// ComponentKVClient is the unmatched prewrite rows cached by the kv client.
+	ComponentKVClient Component = "kv-client"
+	// ComponentPuller is the puller, it reserves nothing but is throttled
+	// when the memory is tight.
+	ComponentPuller Component = "puller"
+	// ComponentSorter is the events sorted in memory by the unified sorter.
+	ComponentSorter Component = "sorter"
+	// ComponentMounter is the events being mounted, they are decoded from
+	// the raw kv entries output by the sorter.
+	ComponentMounter Component = "mounter"
+	// ComponentSink is the mounted events waiting to be flushed by the sink.
+	ComponentSink Component = "sink"
+)
+
+// Priority decides when a component is throttled, a component with a lower
+// priority is throttled or spills to disk earlier. The priority of the
+// changefeed is also taken into account, see Governor.NewQuotaWithPriority.
+type Priority int
+
+// Priorities of components.
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+// Priority returns the priority of the component.
+func (c Component) Priority() Priority {
+	switch c {
+	case ComponentSorter:
+		// The sorter spills to disk first, it is the cheapest way to
+		// reclaim memory.
+		return PriorityLow
+	case ComponentPuller:
+		return PriorityNormal
+	default:
+		// Memory held by kv client, mounter and sink is freed only if they
+		// make progress, so they are throttled last.
+		return PriorityHigh
+	}
+}
+
+// threshold returns the ratio of the limit that components of the priority
+// can use.
+func (p Priority) threshold() float64 {
+	switch p {
+	case PriorityLow:
+		return 0.6
+	case PriorityNormal:
+		return 0.8
+	default:
+		return 1
+	}
+}
+
+type usageKey struct {
+	changefeedID string
+	component    Component
+}
+
+type usage struct {
+	used   uint64
+	quotas int
+}
+
+// Governor is a capture wide memory manager. Components of all changefeeds
+// reserve memory from it through quotas, and they are throttled or spill to
+// disk according to their priorities when the memory is tight.
+type Governor struct {
+	// limit is the total memory that can be reserved, 0 means unlimited,
+	// memory is only accounted in this case.
+	limit uint64
+
+	// used is updated under mu, but it can be read atomically without mu.
+	used uint64
+
+	mu     sync.Mutex
+	usages map[usageKey]*usage
+	// released is closed and replaced once some memory is released and
+	// there are waiters.
+	released chan struct{}
+	waiters  int
+}
+
+// NewGovernor creates a Governor.
+func NewGovernor(limit uint64) *Governor {
+	memoryLimitGauge.Set(float64(limit))
+	return &Governor{
+		limit:    limit,
+		usages:   make(map[usageKey]*usage),
+		released: make(chan struct{}),
+	}
+}
+
+// NewQuota creates a quota of the component of a changefeed with the normal
+// priority. Quotas must be closed after use. It returns nil if the governor
+// is nil, a nil quota reserves nothing and is never throttled.
+func (g *Governor) NewQuota(changefeedID string, component Component) *Quota {
+	return g.NewQuotaWithPriority(changefeedID, component, priority.Normal)
+}
+
+// changefeedPriorityShare is the share of the limit a changefeed gains or
+// loses per priority level.
+const changefeedPriorityShare = 0.1
+
+// NewQuotaWithPriority creates a quota of the component of the changefeed.
+// Components of a changefeed with a higher priority can use more memory
+// before they are throttled, so they keep going while components of lower
+// priorities are throttled or spill to disk.
+func (g *Governor) NewQuotaWithPriority(
+	changefeedID string, component Component, cfPriority priority.Priority,
+) *Quota {
+	if g == nil {
+		return nil
+	}
+	threshold := component.Priority().threshold() +
+		float64(cfPriority)*changefeedPriorityShare
+	if threshold > 1 {
+		threshold = 1
+	}
+	key := usageKey{changefeedID: changefeedID, component: component}
+	g.mu.Lock()
+	u, ok := g.usages[key]
+	if !ok {
+		u = &usage{}
+		g.usages[key] = u
+	}
+	u.quotas++
+	g.mu.Unlock()
+	return &Quota{
+		g:          g,
+		key:        key,
+		usage:      u,
+		threshold:  threshold,
+		closed:     make(chan struct{}),
+		metricUsed: memoryUsedGauge.WithLabelValues(changefeedID, string(component)),
+		metricThrottled: memoryThrottledDuration.WithLabelValues(
+			changefeedID, string(component)),
+	}
+}
+
+// Limit returns the memory limit of the governor.
+func (g *Governor) Limit() uint64 {
+	return g.limit
+}
+
+// Used returns the memory reserved by all quotas.
+func (g *Governor) Used() uint64 {
+	return atomic.LoadUint64(&g.used)
+}
+
+// WriteDebugInfo writes the memory usage of all changefeeds into w, the usage
+// is also exposed by the used_bytes metric of each changefeed and component.
+func (g *Governor) WriteDebugInfo(w io.Writer) {
+	g.mu.Lock()
+	keys := make([]usageKey, 0, len(g.usages))
+	used := make(map[usageKey]uint64, len(g.usages))
+	for key, u := range g.usages {
+		keys = append(keys, key)
+		used[key] = u.used
+	}
+	total := atomic.LoadUint64(&g.used)
+	g.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].changefeedID != keys[j].changefeedID {
+			return keys[i].changefeedID < keys[j].changefeedID
+		}
+		return keys[i].component < keys[j].component
+	})
+	fmt.Fprintf(w, "used: %d, limit: %d\n", total, g.limit)
+	for _, key := range keys {
+		fmt.Fprintf(w, "changefeed: %s, component: %s, used: %d\n",
+			key.changefeedID, key.component, used[key])
+	}
+}
+
+// fits checks whether n bytes can be reserved below the threshold.
+func (g *Governor) fits(n uint64, threshold float64) bool {
+	if g.limit == 0 {
+		return true
+	}
+	used := atomic.LoadUint64(&g.used)
+	return float64(used+n) <= float64(g.limit)*threshold
+}
+
+func (g *Governor) notifyLocked() {
+	if g.waiters > 0 {
+		close(g.released)
+		g.released = make(chan struct{})
+	}
+}
+
+// Quota reserves memory of a component of a changefeed from the governor.
+// All methods are thread-safe and can be called on a nil quota.
+type Quota struct {
+	g     *Governor
+	key   usageKey
+	usage *usage
+	// threshold is the ratio of the limit that the quota can use.
+	threshold float64
+
+	// used is protected by g.mu.
+	used      uint64
+	isClosed  bool
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	metricUsed      prometheus.Gauge
+	metricThrottled prometheus.Counter
+}
+
+// Reserve reserves n bytes, it blocks until the memory is enough, the quota
+// is closed or ctx is done. blockCallBack is called once before blocking.
+// To make sure every owner of quotas can make progress, it never blocks if
+// the quota holds nothing.
+func (q *Quota) Reserve(
+	ctx context.Context, n uint64, blockCallBack func() error,
+) error {
+	if q == nil {
+		return nil
+	}
+	var start time.Time
+	for {
+		q.g.mu.Lock()
+		if q.isClosed {
+			q.g.mu.Unlock()
+			return cerror.ErrMemoryQuotaClosed.GenWithStackByArgs(q.key.component)
+		}
+		if q.used == 0 || q.g.fits(n, q.threshold) {
+			q.reserveLocked(n)
+			q.g.mu.Unlock()
+			if !start.IsZero() {
+				q.metricThrottled.Add(time.Since(start).Seconds())
+			}
+			return nil
+		}
+		released := q.g.released
+		q.g.waiters++
+		q.g.mu.Unlock()
+
+		var err error
+		if start.IsZero() {
+			start = time.Now()
+			if blockCallBack != nil {
+				err = blockCallBack()
+			}
+		}
+		if err == nil {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-q.closed:
+			case <-released:
+			}
+		}
+
+		q.g.mu.Lock()
+		q.g.waiters--
+		q.g.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ForceReserve reserves n bytes without blocking, the limit can be
+// exceeded. It is used when blocking may cause a deadlock.
+func (q *Quota) ForceReserve(n uint64) {
+	if q == nil {
+		return
+	}
+	q.g.mu.Lock()
+	defer q.g.mu.Unlock()
+	if q.isClosed {
+		return
+	}
+	q.reserveLocked(n)
+}
+
+func (q *Quota) reserveLocked(n uint64) {
+	q.used += n
+	q.usage.used += n
+	atomic.AddUint64(&q.g.used, n)
+	q.metricUsed.Add(float64(n))
+}
+
+// Release releases n bytes, releasing more than reserved is ignored.
+func (q *Quota) Release(n uint64) {
+	if q == nil {
+		return
+	}
+	q.g.mu.Lock()
+	defer q.g.mu.Unlock()
+	q.releaseLocked(n)
+}
+
+func (q *Quota) releaseLocked(n uint64) {
+	if n > q.used {
+		// Memory may be released after the quota is closed.
+		n = q.used
+	}
+	if n == 0 {
+		return
+	}
+	q.used -= n
+	q.usage.used -= n
+	atomic.AddUint64(&q.g.used, ^(n - 1))
+	q.metricUsed.Sub(float64(n))
+	q.g.notifyLocked()
+}
+
+// Throttle blocks until the memory usage is below the threshold of the
+// priority of the component, at most timeout. It is used by components that
+// reserve nothing but produce events that consume memory.
+func (q *Quota) Throttle(ctx context.Context, timeout time.Duration) error {
+	if q == nil || q.g.fits(0, q.threshold) {
+		return nil
+	}
+	var timer *time.Timer
+	start := time.Now()
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+			q.metricThrottled.Add(time.Since(start).Seconds())
+		}
+	}()
+	for {
+		q.g.mu.Lock()
+		if q.isClosed || q.g.fits(0, q.threshold) {
+			q.g.mu.Unlock()
+			return nil
+		}
+		released := q.g.released
+		q.g.waiters++
+		q.g.mu.Unlock()
+
+		if timer == nil {
+			timer = time.NewTimer(timeout)
+		}
+		var err error
+		timeouted := false
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-q.closed:
+		case <-timer.C:
+			timeouted = true
+		case <-released:
+		}
+
+		q.g.mu.Lock()
+		q.g.waiters--
+		q.g.mu.Unlock()
+		if err != nil || timeouted {
+			return err
+		}
+	}
+}
+
+// ShouldSpill returns true if the memory usage exceeds the threshold of the
+// priority of the component, components should spill new data to disk.
+func (q *Quota) ShouldSpill() bool {
+	if q == nil {
+		return false
+	}
+	return !q.g.fits(0, q.threshold)
+}
+
+// Used returns the memory reserved by the quota.
+func (q *Quota) Used() uint64 {
+	if q == nil {
+		return 0
+	}
+	q.g.mu.Lock()
+	defer q.g.mu.Unlock()
+	return q.used
+}
+
+// Close releases all memory reserved by the quota and interrupts ongoing
+// Reserve calls.
+func (q *Quota) Close() {
+	if q == nil {
+		return
+	}
+	q.closeOnce.Do(func() {
+		q.g.mu.Lock()
+		defer q.g.mu.Unlock()
+		q.releaseLocked(q.used)
+		q.isClosed = true
+		close(q.closed)
+
+		q.usage.quotas--
+		if q.usage.quotas == 0 {
+			delete(q.g.usages, q.key)
+			memoryUsedGauge.DeleteLabelValues(q.key.changefeedID, string(q.key.component))
+			memoryThrottledDuration.DeleteLabelValues(
+				q.key.changefeedID, string(q.key.component))
+		}
+	})
+}
+
+type ctxKey Component
+
+// PutQuotaInCtx returns a new child context with the quota stored, it can be
+// retrieved by the component of the quota.
+func PutQuotaInCtx(ctx context.Context, q *Quota) context.Context {
+	if q == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey(q.key.component), q)
+}
+
+// QuotaFromCtx returns the quota of the component stored in ctx, it returns
+// nil if there is no quota.
+func QuotaFromCtx(ctx context.Context, component Component) *Quota {
+	q, ok := ctx.Value(ctxKey(component)).(*Quota)
+	if !ok {
+		return nil
+	}
+	return q
+}
@@ -0,0 +1,220 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memquota
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/priority"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGovernorUnlimited(t *testing.T) {
+	t.Parallel()
+	g := NewGovernor(0)
+	q := g.NewQuota("cf-unlimited", ComponentSink)
+	require.Nil(t, q.Reserve(context.Background(), 1<<40, nil))
+	require.False(t, q.ShouldSpill())
+	require.Equal(t, uint64(1<<40), g.Used())
+	q.Close()
+	require.Equal(t, uint64(0), g.Used())
+}
+
+func TestQuotaReserveBlock(t *testing.T) {
+	t.Parallel()
+	g := NewGovernor(100)
+	q1 := g.NewQuota("cf-block", ComponentSink)
+	defer q1.Close()
+	q2 := g.NewQuota("cf-block", ComponentSink)
+	defer q2.Close()
+
+	ctx := context.Background()
+	require.Nil(t, q1.Reserve(ctx, 80, nil))
+	// A quota holding nothing never blocks.
+	require.Nil(t, q2.Reserve(ctx, 30, nil))
+	require.Equal(t, uint64(110), g.Used())
+
+	blocked := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- q2.Reserve(ctx, 10, func() error {
+			close(blocked)
+			return nil
+		})
+	}()
+	<-blocked
+	select {
+	case <-done:
+		t.Fatal("reserve must be blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	q1.Release(80)
+	require.Nil(t, <-done)
+	require.Equal(t, uint64(40), g.Used())
+	require.Equal(t, float64(40),
+		testutil.ToFloat64(memoryUsedGauge.WithLabelValues("cf-block", string(ComponentSink))))
+
+	// Releasing more than reserved is ignored.
+	q2.Release(100)
+	require.Equal(t, uint64(0), q2.Used())
+	require.Equal(t, uint64(0), g.Used())
+}
+
+func TestQuotaReserveClosedOrCanceled(t *testing.T) {
+	t.Parallel()
+	g := NewGovernor(10)
+	q := g.NewQuota("cf-closed", ComponentSink)
+	require.Nil(t, q.Reserve(context.Background(), 10, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := q.Reserve(ctx, 10, nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- q.Reserve(context.Background(), 10, nil)
+	}()
+	q.Close()
+	err = <-done
+	require.Regexp(t, ".*ErrMemoryQuotaClosed.*", err)
+	require.Equal(t, uint64(0), g.Used())
+	require.Empty(t, g.usages)
+
+	// Methods of a closed quota are no-op.
+	q.ForceReserve(10)
+	q.Release(10)
+	require.Equal(t, uint64(0), g.Used())
+}
+
+func TestQuotaPriority(t *testing.T) {
+	t.Parallel()
+	g := NewGovernor(100)
+	sink := g.NewQuota("cf-priority", ComponentSink)
+	defer sink.Close()
+	sorter := g.NewQuota("cf-priority", ComponentSorter)
+	defer sorter.Close()
+	puller := g.NewQuota("cf-priority", ComponentPuller)
+	defer puller.Close()
+
+	sink.ForceReserve(70)
+	require.True(t, sorter.ShouldSpill())
+	require.False(t, puller.ShouldSpill())
+	require.False(t, sink.ShouldSpill())
+
+	ctx := context.Background()
+	// Not throttled below the threshold.
+	require.Nil(t, puller.Throttle(ctx, time.Hour))
+	sink.ForceReserve(20)
+	require.True(t, puller.ShouldSpill())
+
+	// Throttled until timeout.
+	start := time.Now()
+	require.Nil(t, puller.Throttle(ctx, 50*time.Millisecond))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// Throttled until memory is released.
+	done := make(chan error, 1)
+	go func() {
+		done <- puller.Throttle(ctx, time.Hour)
+	}()
+	sink.Release(20)
+	require.Nil(t, <-done)
+}
+
+func TestNilQuota(t *testing.T) {
+	t.Parallel()
+	var g *Governor
+	q := g.NewQuota("cf-nil", ComponentSink)
+	require.Nil(t, q)
+	require.Nil(t, q.Reserve(context.Background(), 10, nil))
+	require.Nil(t, q.Throttle(context.Background(), time.Hour))
+	q.ForceReserve(10)
+	q.Release(10)
+	require.False(t, q.ShouldSpill())
+	require.Equal(t, uint64(0), q.Used())
+	q.Close()
+
+	ctx := PutQuotaInCtx(context.Background(), q)
+	require.Nil(t, QuotaFromCtx(ctx, ComponentSink))
+}
+
+func TestQuotaInCtx(t *testing.T) {
+	t.Parallel()
+	g := NewGovernor(0)
+	sink := g.NewQuota("cf-ctx", ComponentSink)
+	defer sink.Close()
+	sorter := g.NewQuota("cf-ctx", ComponentSorter)
+	defer sorter.Close()
+
+	ctx := PutQuotaInCtx(context.Background(), sink)
+	ctx = PutQuotaInCtx(ctx, sorter)
+	require.Equal(t, sink, QuotaFromCtx(ctx, ComponentSink))
+	require.Equal(t, sorter, QuotaFromCtx(ctx, ComponentSorter))
+	require.Nil(t, QuotaFromCtx(ctx, ComponentPuller))
+}
+
+func TestWriteDebugInfo(t *testing.T) {
+	t.Parallel()
+	g := NewGovernor(1024)
+	q1 := g.NewQuota("cf-b", ComponentSink)
+	defer q1.Close()
+	q2 := g.NewQuota("cf-a", ComponentSorter)
+	defer q2.Close()
+	q1.ForceReserve(10)
+	q2.ForceReserve(20)
+
+	buf := &bytes.Buffer{}
+	g.WriteDebugInfo(buf)
+	require.Equal(t, "used: 30, limit: 1024\n"+
+		"changefeed: cf-a, component: sorter, used: 20\n"+
+		"changefeed: cf-b, component: sink, used: 10\n", buf.String())
+}
+
+func TestQuotaChangefeedPriority(t *testing.T) {
+	t.Parallel()
+	g := NewGovernor(100)
+	other := g.NewQuota("cf-other", ComponentSink)
+	defer other.Close()
+	low := g.NewQuotaWithPriority("cf-low", ComponentSorter, priority.Low)
+	defer low.Close()
+	normal := g.NewQuota("cf-normal", ComponentSorter)
+	defer normal.Close()
+	high := g.NewQuotaWithPriority("cf-high", ComponentSorter, priority.High)
+	defer high.Close()
+
+	other.ForceReserve(55)
+	require.True(t, low.ShouldSpill())
+	require.False(t, normal.ShouldSpill())
+	require.False(t, high.ShouldSpill())
+
+	other.ForceReserve(10)
+	require.True(t, normal.ShouldSpill())
+	require.False(t, high.ShouldSpill())
+
+	other.ForceReserve(10)
+	require.True(t, high.ShouldSpill())
+
+	// The threshold never exceeds the limit.
+	sink := g.NewQuotaWithPriority("cf-high", ComponentSink, priority.High)
+	defer sink.Close()
+	other.ForceReserve(25)
+	require.False(t, sink.ShouldSpill())
+	other.ForceReserve(1)
+	require.True(t, sink.ShouldSpill())
+}
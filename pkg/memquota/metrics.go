@@ -0,0 +1,48 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memquota
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	memoryLimitGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "memory_quota",
+		Name:      "limit_bytes",
+		Help:      "The memory limit of the capture, 0 means unlimited",
+	})
+
+	memoryUsedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "memory_quota",
+		Name:      "used_bytes",
+		Help:      "The memory reserved by components of changefeeds",
+	}, []string{"changefeed", "component"})
+
+	memoryThrottledDuration = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "memory_quota",
+		Name:      "throttled_seconds_total",
+		Help:      "The time components of changefeeds are throttled by the memory quota",
+	}, []string{"changefeed", "component"})
+)
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(memoryLimitGauge)
+	registry.MustRegister(memoryUsedGauge)
+	registry.MustRegister(memoryThrottledDuration)
+}
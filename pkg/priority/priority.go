@@ -0,0 +1,131 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priority
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Priority is the priority of a changefeed. Changefeeds on a capture share
+// workers, memory and captures, a changefeed with a higher priority gets
+// more of them when they are contended.
+type Priority int
+
+// Priorities of changefeeds, the zero value is Normal.
+const (
+	Low    Priority = -1
+	Normal Priority = 0
+	High   Priority = 1
+)
+
+// Num is the number of priorities.
+const Num = 3
+
+const (
+	lowName    = "low"
+	normalName = "normal"
+	highName   = "high"
+)
+
+// Parse returns the priority of the given name, an empty name is Normal.
+func Parse(s string) (Priority, error) {
+	switch s {
+	case "", normalName:
+		return Normal, nil
+	case highName:
+		return High, nil
+	case lowName:
+		return Low, nil
+	default:
+		return Normal, cerror.ErrInvalidChangefeedPriority.GenWithStackByArgs(s)
+	}
+}
+
+// String implements fmt.Stringer.
+func (p Priority) String() string {
+	switch p {
+	case High:
+		return highName
+	case Low:
+		return lowName
+	default:
+		return normalName
+	}
+}
+
+// Index returns the index of the priority in [0, Num), a higher priority
+// has a smaller index.
+func (p Priority) Index() int {
+	switch p {
+	case High:
+		return 0
+	case Low:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *Priority) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// rounds decides which priority is preferred in each pick of a round, the
+// number of picks of a priority is its weight.
+var rounds = [...]int{0, 0, 0, 0, 1, 1, 2}
+
+// orders are the orders of indexes tried in each pick of a round, the
+// preferred one goes first, the others follow from high to low.
+var orders = func() (ret [len(rounds)][Num]int) {
+	for i, preferred := range rounds {
+		ret[i][0] = preferred
+		n := 1
+		for idx := 0; idx < Num; idx++ {
+			if idx != preferred {
+				ret[i][n] = idx
+				n++
+			}
+		}
+	}
+	return
+}()
+
+// Picker decides the order in which queues of different priorities are
+// polled. Higher priorities are preferred, but a lower priority is still
+// polled first from time to time, so it is never starved.
+// Picker is not thread-safe.
+type Picker struct {
+	n int
+}
+
+// Next returns the indexes of priorities in the order they should be polled.
+func (p *Picker) Next() [Num]int {
+	order := orders[p.n]
+	p.n++
+	if p.n == len(rounds) {
+		p.n = 0
+	}
+	return order
+}
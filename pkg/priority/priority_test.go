@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priority
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		expected Priority
+	}{
+		{"", Normal},
+		{"normal", Normal},
+		{"high", High},
+		{"low", Low},
+	} {
+		p, err := Parse(tc.name)
+		require.Nil(t, err)
+		require.Equal(t, tc.expected, p)
+	}
+	_, err := Parse("urgent")
+	require.Regexp(t, "invalid changefeed priority: urgent", err)
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	type info struct {
+		Priority Priority `json:"priority,omitempty"`
+	}
+	data, err := json.Marshal(&info{Priority: High})
+	require.Nil(t, err)
+	require.Equal(t, `{"priority":"high"}`, string(data))
+
+	// The zero value is omitted, so the old changefeed info is unchanged.
+	data, err = json.Marshal(&info{})
+	require.Nil(t, err)
+	require.Equal(t, `{}`, string(data))
+
+	var decoded info
+	require.Nil(t, json.Unmarshal([]byte(`{"priority":"low"}`), &decoded))
+	require.Equal(t, Low, decoded.Priority)
+	decoded = info{}
+	require.Nil(t, json.Unmarshal([]byte(`{}`), &decoded))
+	require.Equal(t, Normal, decoded.Priority)
+	require.Error(t, json.Unmarshal([]byte(`{"priority":"urgent"}`), &decoded))
+}
+
+func TestIndex(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 0, High.Index())
+	require.Equal(t, 1, Normal.Index())
+	require.Equal(t, 2, Low.Index())
+}
+
+func TestPicker(t *testing.T) {
+	t.Parallel()
+
+	var picker Picker
+	var firsts [Num]int
+	for i := 0; i < len(rounds)*10; i++ {
+		order := picker.Next()
+		firsts[order[0]]++
+
+		// Every index is polled once in each pick.
+		var seen [Num]bool
+		for _, idx := range order {
+			require.False(t, seen[idx])
+			seen[idx] = true
+		}
+	}
+	require.Equal(t, [Num]int{40, 20, 10}, firsts)
+}
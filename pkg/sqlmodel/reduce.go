@@ -71,7 +71,7 @@ func (r *RowChange) IsIdentityUpdated() bool {
 		return true
 	}
 	for i := range pre {
-		if pre[i] != post[i] {
+		if !valueEqual(pre[i], post[i]) {
 			return true
 		}
 	}
@@ -61,6 +61,18 @@ func TestSplit(t *testing.T) {
 	require.NotEqual(t, delIDKey, insIDKey)
 }
 
+func TestIdentityUpdatedWithBytes(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb1"}
+	sourceTI := mockTableInfo(t, "CREATE TABLE tb1 (c VARBINARY(10) PRIMARY KEY, c2 INT)")
+
+	change := NewRowChange(source, nil, []interface{}{[]byte("a"), 2}, []interface{}{[]byte("a"), 3}, sourceTI, nil, nil)
+	require.False(t, change.IsIdentityUpdated())
+	change = NewRowChange(source, nil, []interface{}{[]byte("a"), 2}, []interface{}{[]byte("b"), 3}, sourceTI, nil, nil)
+	require.True(t, change.IsIdentityUpdated())
+}
+
 func (s *dpanicSuite) TestReduce() {
 	source := &cdcmodel.TableName{Schema: "db", Table: "tb1"}
 	sourceTI := mockTableInfo(s.T(), "CREATE TABLE tb1 (c INT PRIMARY KEY, c2 INT)")
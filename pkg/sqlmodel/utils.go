@@ -14,6 +14,7 @@
 package sqlmodel
 
 import (
+	"bytes"
 	"strings"
 
 	timodel "github.com/pingcap/tidb/parser/model"
@@ -57,3 +58,14 @@ func isGenerated(columns []*timodel.ColumnInfo, name timodel.CIStr) bool {
 	}
 	return false
 }
+
+// valueEqual checks whether two column values are equal. []byte values are
+// compared by content because they are not comparable.
+func valueEqual(lhs, rhs interface{}) bool {
+	lhsBytes, lhsOK := lhs.([]byte)
+	rhsBytes, rhsOK := rhs.([]byte)
+	if lhsOK || rhsOK {
+		return lhsOK && rhsOK && bytes.Equal(lhsBytes, rhsBytes)
+	}
+	return lhs == rhs
+}
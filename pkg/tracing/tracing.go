@@ -0,0 +1,271 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing traces sampled transactions through the pipeline of the
+// processor.
+//
+// A transaction is sampled by the kv client, and every row of it carries a
+// model.TraceContext from the kv client to the sink, each stage of the
+// pipeline records a span covering the time from the handoff of the previous
+// stage to its own handoff. The trace ID is derived from the start ts and the
+// commit ts, so the rows of a transaction are in the same trace even if they
+// are replicated by different tables or captures.
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	instrumentationName = "github.com/pingcap/tiflow"
+	serviceName         = "ticdc"
+
+	// TraceParentHeader is the header of MQ messages carrying the W3C
+	// traceparent of the sampled rows in them.
+	TraceParentHeader = "traceparent"
+)
+
+// Stages of the pipeline.
+const (
+	StageKVClient   = "kv-client"
+	StagePuller     = "puller"
+	StageSorter     = "sorter"
+	StageMounter    = "mounter"
+	StageCyclicMark = "cyclic_mark"
+	StageSink       = "sink"
+	StageMQSink     = "mq-sink"
+)
+
+type tracer struct {
+	tracer trace.Tracer
+	// bound is the upper bound of the sampled hashes of transactions.
+	bound uint64
+}
+
+// globalTracer stores a *tracer, nil means tracing is disabled.
+var globalTracer atomic.Value
+
+func getTracer() *tracer {
+	t, _ := globalTracer.Load().(*tracer)
+	return t
+}
+
+// Init starts exporting spans to the OTLP collector if tracing is enabled,
+// the returned function flushes the pending spans and stops tracing.
+func Init(
+	ctx context.Context, cfg *config.TracingConfig, captureAddr string,
+) (func(context.Context) error, error) {
+	if cfg == nil || !cfg.Enable {
+		return func(context.Context) error { return nil }, nil
+	}
+	driver := otlpgrpc.NewDriver(
+		otlpgrpc.WithInsecure(),
+		otlpgrpc.WithEndpoint(cfg.Endpoint),
+	)
+	exporter, err := otlp.NewExporter(ctx, driver)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrInitTracing, err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithIDGenerator(newIDGenerator()),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceInstanceIDKey.String(captureAddr),
+		)),
+	)
+	globalTracer.Store(&tracer{
+		tracer: provider.Tracer(instrumentationName),
+		bound:  sampleBound(cfg.SampleRate),
+	})
+	log.Info("pipeline tracing is enabled",
+		zap.String("endpoint", cfg.Endpoint),
+		zap.Float64("sampleRate", cfg.SampleRate))
+	return func(ctx context.Context) error {
+		globalTracer.Store((*tracer)(nil))
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+func sampleBound(rate float64) uint64 {
+	if rate >= 1 {
+		return math.MaxUint64
+	}
+	return uint64(rate * math.MaxUint64)
+}
+
+// txnTraceID derives the trace ID of a transaction.
+func txnTraceID(startTs, commitTs uint64) trace.TraceID {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], startTs)
+	binary.BigEndian.PutUint64(buf[8:], commitTs)
+	h := fnv.New128a()
+	_, _ = h.Write(buf[:])
+	var id trace.TraceID
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+func (t *tracer) sampled(id trace.TraceID) bool {
+	if t.bound == math.MaxUint64 {
+		return true
+	}
+	return binary.BigEndian.Uint64(id[8:]) < t.bound
+}
+
+// NewTrace starts the trace of a row if its transaction is sampled, it
+// returns nil if the transaction is not sampled or tracing is disabled.
+func NewTrace(startTs, commitTs uint64, regionID uint64) *model.TraceContext {
+	t := getTracer()
+	if t == nil {
+		return nil
+	}
+	id := txnTraceID(startTs, commitTs)
+	if !t.sampled(id) {
+		return nil
+	}
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), traceIDKey{}, id)
+	_, span := t.tracer.Start(ctx, StageKVClient,
+		trace.WithTimestamp(now),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.Int64("start_ts", int64(startTs)),
+			attribute.Int64("commit_ts", int64(commitTs)),
+			attribute.Int64("region_id", int64(regionID)),
+		))
+	span.End(trace.WithTimestamp(now))
+	return &model.TraceContext{
+		Parent:      inject(span.SpanContext()),
+		HandoffTime: now.UnixNano(),
+	}
+}
+
+// Stage records the span of a stage of the pipeline, which starts from the
+// handoff of the previous stage and ends now. It returns the trace context
+// to be handed off to the next stage, or nil if tc is nil.
+func Stage(tc *model.TraceContext, stage string, attrs ...attribute.KeyValue) *model.TraceContext {
+	if tc == nil {
+		return nil
+	}
+	t := getTracer()
+	if t == nil {
+		return nil
+	}
+	parent := extract(tc.Parent)
+	if !parent.IsValid() {
+		return nil
+	}
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
+	_, span := t.tracer.Start(ctx, stage,
+		trace.WithTimestamp(time.Unix(0, tc.HandoffTime)),
+		trace.WithAttributes(attrs...))
+	now := time.Now()
+	span.End(trace.WithTimestamp(now))
+	return &model.TraceContext{
+		Parent:      inject(span.SpanContext()),
+		HandoffTime: now.UnixNano(),
+	}
+}
+
+var propagator = propagation.TraceContext{}
+
+// carrier is a propagation.TextMapCarrier holding a traceparent only.
+type carrier struct {
+	traceParent string
+}
+
+func (c *carrier) Get(key string) string {
+	if key == TraceParentHeader {
+		return c.traceParent
+	}
+	return ""
+}
+
+func (c *carrier) Set(key string, value string) {
+	if key == TraceParentHeader {
+		c.traceParent = value
+	}
+}
+
+func (c *carrier) Keys() []string {
+	return []string{TraceParentHeader}
+}
+
+func inject(sc trace.SpanContext) string {
+	c := &carrier{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), c)
+	return c.traceParent
+}
+
+func extract(traceParent string) trace.SpanContext {
+	ctx := propagator.Extract(context.Background(), &carrier{traceParent: traceParent})
+	return trace.SpanContextFromContext(ctx)
+}
+
+// traceIDKey is the context key of the trace ID of a new root span.
+type traceIDKey struct{}
+
+// idGenerator generates random IDs, except that the trace ID of a root span
+// is taken from the context if it is set.
+type idGenerator struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newIDGenerator() *idGenerator {
+	return &idGenerator{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// NewIDs implements sdktrace.IDGenerator.
+func (g *idGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	tid, ok := ctx.Value(traceIDKey{}).(trace.TraceID)
+	if !ok {
+		g.rand.Read(tid[:])
+	}
+	var sid trace.SpanID
+	g.rand.Read(sid[:])
+	return tid, sid
+}
+
+// NewSpanID implements sdktrace.IDGenerator.
+func (g *idGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var sid trace.SpanID
+	g.rand.Read(sid[:])
+	return sid
+}
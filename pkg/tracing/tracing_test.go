@@ -0,0 +1,142 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// mockCollector is an in-process OTLP trace collector.
+type mockCollector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+func (c *mockCollector) Export(
+	_ context.Context, req *collectortrace.ExportTraceServiceRequest,
+) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			c.spans = append(c.spans, ils.Spans...)
+		}
+	}
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func (c *mockCollector) getSpans() []*tracepb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*tracepb.Span(nil), c.spans...)
+}
+
+func startMockCollector(t *testing.T) (*mockCollector, string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	server := grpc.NewServer()
+	collector := &mockCollector{}
+	collectortrace.RegisterTraceServiceServer(server, collector)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+	return collector, lis.Addr().String()
+}
+
+func TestSampling(t *testing.T) {
+	t.Parallel()
+
+	never := &tracer{bound: sampleBound(0)}
+	always := &tracer{bound: sampleBound(1)}
+	half := &tracer{bound: sampleBound(0.5)}
+	sampled := 0
+	for i := uint64(0); i < 10000; i++ {
+		id := txnTraceID(i*10, i*10+5)
+		require.Equal(t, id, txnTraceID(i*10, i*10+5))
+		require.False(t, never.sampled(id))
+		require.True(t, always.sampled(id))
+		if half.sampled(id) {
+			sampled++
+		}
+	}
+	require.Greater(t, sampled, 4500)
+	require.Less(t, sampled, 5500)
+}
+
+func TestTraceDisabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), &config.TracingConfig{}, "")
+	require.Nil(t, err)
+	require.Nil(t, NewTrace(1, 2, 3))
+	require.Nil(t, Stage(nil, StagePuller))
+	require.Nil(t, shutdown(context.Background()))
+}
+
+func TestTracePipeline(t *testing.T) {
+	collector, addr := startMockCollector(t)
+	ctx := context.Background()
+	shutdown, err := Init(ctx, &config.TracingConfig{
+		Enable:     true,
+		Endpoint:   addr,
+		SampleRate: 1,
+	}, "127.0.0.1:8300")
+	require.Nil(t, err)
+
+	row1 := NewTrace(100, 110, 1)
+	require.NotNil(t, row1)
+	row2 := NewTrace(100, 110, 2)
+	require.NotNil(t, row2)
+	time.Sleep(time.Millisecond)
+	puller := Stage(row1, StagePuller)
+	sorter := Stage(puller, StageSorter)
+	require.Greater(t, sorter.HandoffTime, row1.HandoffTime)
+
+	// the rows of a transaction are in the same trace.
+	traceID := txnTraceID(100, 110)
+	require.True(t, strings.Contains(sorter.Parent, hex.EncodeToString(traceID[:])))
+	require.True(t, strings.Contains(row2.Parent, hex.EncodeToString(traceID[:])))
+
+	require.Nil(t, shutdown(ctx))
+	require.Nil(t, NewTrace(100, 110, 1))
+	require.Nil(t, Stage(sorter, StageMounter))
+
+	spans := collector.getSpans()
+	require.Len(t, spans, 4)
+	byName := make(map[string][]*tracepb.Span)
+	for _, span := range spans {
+		require.Equal(t, traceID[:], span.TraceId)
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+	require.Len(t, byName[StageKVClient], 2)
+	pullerSpan, sorterSpan := byName[StagePuller][0], byName[StageSorter][0]
+	require.Equal(t, extract(row1.Parent).SpanID().String(),
+		hex.EncodeToString(pullerSpan.ParentSpanId))
+	require.Equal(t, pullerSpan.SpanId, sorterSpan.ParentSpanId)
+	require.Equal(t, uint64(row1.HandoffTime), pullerSpan.StartTimeUnixNano)
+	require.Equal(t, uint64(puller.HandoffTime), pullerSpan.EndTimeUnixNano)
+}
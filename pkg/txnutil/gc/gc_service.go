@@ -28,6 +28,9 @@ import (
 const (
 	// cdcChangefeedCreatingServiceGCSafePointID is service GC safe point ID
 	cdcChangefeedCreatingServiceGCSafePointID = "ticdc-creating-"
+	// cdcChangefeedCheckingServiceGCSafePointID is the service GC safe point
+	// ID used to read the minimum service GC safepoint, it is never set.
+	cdcChangefeedCheckingServiceGCSafePointID = "ticdc-checking-"
 )
 
 // EnsureChangefeedStartTsSafety checks if the startTs less than the minimum of
@@ -46,6 +49,25 @@ func EnsureChangefeedStartTsSafety(
 	return nil
 }
 
+// CheckChangefeedStartTsSafety checks if the startTs less than the minimum of
+// service GC safepoint, unlike EnsureChangefeedStartTsSafety, it doesn't set
+// a service GC safepoint, so the startTs may be GCed after the check.
+func CheckChangefeedStartTsSafety(
+	ctx context.Context, pdCli pd.Client, changefeedID string, startTs uint64,
+) error {
+	// PD removes the service GC safepoint whose TTL is not positive and
+	// returns the minimum of the others, so that nothing is set.
+	minServiceGCTs, err := setServiceGCSafepoint(
+		ctx, pdCli, cdcChangefeedCheckingServiceGCSafePointID+changefeedID, 0, math.MaxUint64)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if startTs < minServiceGCTs {
+		return cerrors.ErrStartTsBeforeGC.GenWithStackByArgs(startTs, minServiceGCTs)
+	}
+	return nil
+}
+
 // PD leader switch may happen, so just gcServiceMaxRetries it.
 // The default PD election timeout is 3 seconds. Triple the timeout as
 // retry time to make sure PD leader can be elected during retry.
@@ -70,6 +70,19 @@ func (s *gcServiceSuite) TestCheckSafetyOfStartTs(c *check.C) {
 	c.Assert(err.Error(), check.Equals, "[CDC:ErrStartTsBeforeGC]fail to create changefeed because start-ts 50 is earlier than GC safepoint at 60")
 }
 
+func (s *gcServiceSuite) TestCheckChangefeedStartTsSafety(c *check.C) {
+	defer testleak.AfterTest(c)()
+	ctx := context.Background()
+
+	pdCli := &mockPdClientForServiceGCSafePoint{serviceSafePoint: map[string]uint64{"service1": 60}}
+	err := CheckChangefeedStartTsSafety(ctx, pdCli, "changefeed1", 50)
+	c.Assert(err.Error(), check.Equals, "[CDC:ErrStartTsBeforeGC]fail to create changefeed because start-ts 50 is earlier than GC safepoint at 60")
+	err = CheckChangefeedStartTsSafety(ctx, pdCli, "changefeed1", 65)
+	c.Assert(err, check.IsNil)
+	// No service GC safepoint is set by the check.
+	c.Assert(pdCli.serviceSafePoint, check.DeepEquals, map[string]uint64{"service1": 60})
+}
+
 type mockPdClientForServiceGCSafePoint struct {
 	pd.Client
 	serviceSafePoint   map[string]uint64
@@ -86,11 +99,17 @@ func (m *mockPdClientForServiceGCSafePoint) UpdateServiceGCSafePoint(ctx context
 		return minSafePoint, errors.New("not pd leader")
 	}
 
+	if ttl <= 0 {
+		delete(m.serviceSafePoint, serviceID)
+	}
 	for _, safePoint := range m.serviceSafePoint {
 		if minSafePoint > safePoint {
 			minSafePoint = safePoint
 		}
 	}
+	if ttl <= 0 {
+		return minSafePoint, nil
+	}
 	if safePoint < minSafePoint && len(m.serviceSafePoint) != 0 {
 		return minSafePoint, nil
 	}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"go.uber.org/zap"
 )
 
@@ -32,6 +33,8 @@ const (
 	ctxKeyTimezone     = ctxKey("timezone")
 	ctxKeyKVStorage    = ctxKey("kvStorage")
 	ctxKeyRole         = ctxKey("role")
+	ctxKeyPriority     = ctxKey("priority")
+	ctxKeyClusterID    = ctxKey("clusterID")
 )
 
 // CaptureAddrFromCtx returns a capture ID stored in the specified context.
@@ -122,6 +125,36 @@ func PutChangefeedIDInCtx(ctx context.Context, changefeedID string) context.Cont
 	return context.WithValue(ctx, ctxKeyChangefeedID, changefeedID)
 }
 
+// ChangefeedPriorityFromCtx returns a changefeed priority stored in the
+// specified context. It returns priority.Normal if there's no valid priority found.
+func ChangefeedPriorityFromCtx(ctx context.Context) priority.Priority {
+	p, ok := ctx.Value(ctxKeyPriority).(priority.Priority)
+	if !ok {
+		return priority.Normal
+	}
+	return p
+}
+
+// PutChangefeedPriorityInCtx returns a new child context with the specified changefeed priority stored.
+func PutChangefeedPriorityInCtx(ctx context.Context, p priority.Priority) context.Context {
+	return context.WithValue(ctx, ctxKeyPriority, p)
+}
+
+// ClusterIDFromCtx returns the upstream cluster ID stored in the specified context.
+// It returns 0 if there's no valid cluster ID found.
+func ClusterIDFromCtx(ctx context.Context) uint64 {
+	clusterID, ok := ctx.Value(ctxKeyClusterID).(uint64)
+	if !ok {
+		return 0
+	}
+	return clusterID
+}
+
+// PutClusterIDInCtx returns a new child context with the specified upstream cluster ID stored.
+func PutClusterIDInCtx(ctx context.Context, clusterID uint64) context.Context {
+	return context.WithValue(ctx, ctxKeyClusterID, clusterID)
+}
+
 // RoleFromCtx returns a role stored in the specified context.
 // It returns RoleUnknown if there's no valid role found
 func RoleFromCtx(ctx context.Context) Role {
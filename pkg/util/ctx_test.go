@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -57,6 +58,18 @@ func TestChangefeedIDNotSet(t *testing.T) {
 	require.Equal(t, "", changefeedID)
 }
 
+func TestChangefeedPriority(t *testing.T) {
+	require.Equal(t, priority.Normal, ChangefeedPriorityFromCtx(context.Background()))
+	ctx := PutChangefeedPriorityInCtx(context.Background(), priority.High)
+	require.Equal(t, priority.High, ChangefeedPriorityFromCtx(ctx))
+}
+
+func TestClusterID(t *testing.T) {
+	require.Equal(t, uint64(0), ClusterIDFromCtx(context.Background()))
+	ctx := PutClusterIDInCtx(context.Background(), 7071458326478937088)
+	require.Equal(t, uint64(7071458326478937088), ClusterIDFromCtx(ctx))
+}
+
 func TestShouldReturnTimezone(t *testing.T) {
 	tz, _ := getTimezoneFromZonefile("UTC")
 	ctx := PutTimezoneInCtx(context.Background(), tz)
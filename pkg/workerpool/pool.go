@@ -16,6 +16,8 @@ package workerpool
 import (
 	"context"
 	"time"
+
+	"github.com/pingcap/tiflow/pkg/priority"
 )
 
 // WorkerPool runs a number of Goroutines that process the submitted events.
@@ -26,6 +28,13 @@ type WorkerPool interface {
 	// TODO more reasonable usage of contexts, potentially involving context merging.
 	RegisterEvent(f func(ctx context.Context, event interface{}) error) EventHandle
 
+	// RegisterEventWithPriority is like RegisterEvent, but events of the
+	// returned handle are executed according to the priority. Events of
+	// higher priorities are preferred by workers when they are busy.
+	RegisterEventWithPriority(
+		f func(ctx context.Context, event interface{}) error, p priority.Priority,
+	) EventHandle
+
 	// Run runs the WorkerPool.
 	// Internally several Goroutines are spawned.
 	Run(ctx context.Context) error
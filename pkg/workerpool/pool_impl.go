@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/log"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/notify"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
@@ -76,6 +77,12 @@ func (p *defaultPoolImpl) Run(ctx context.Context) error {
 }
 
 func (p *defaultPoolImpl) RegisterEvent(f func(ctx context.Context, event interface{}) error) EventHandle {
+	return p.RegisterEventWithPriority(f, priority.Normal)
+}
+
+func (p *defaultPoolImpl) RegisterEventWithPriority(
+	f func(ctx context.Context, event interface{}) error, pri priority.Priority,
+) EventHandle {
 	handler := &defaultEventHandle{
 		f:     f,
 		errCh: make(chan error, 1),
@@ -85,6 +92,8 @@ func (p *defaultPoolImpl) RegisterEvent(f func(ctx context.Context, event interf
 	workerID := p.hasher.Hash(handler) % int64(len(p.workers))
 	p.workers[workerID].addHandle(handler)
 	handler.worker = p.workers[workerID]
+	// All tasks of a handle go through the same channel to keep them in order.
+	handler.taskCh = handler.worker.taskChs[pri.Index()]
 
 	return handler
 }
@@ -106,6 +115,9 @@ type defaultEventHandle struct {
 	errCh chan error
 	// the worker that the handle is associated with
 	worker *worker
+	// the channel of the worker that tasks are sent to, it is decided by
+	// the priority of the handle
+	taskCh chan task
 	// identifier for this handle. No significant usage for now.
 	// Might be used to support consistent hashing in the future,
 	// so that the pool can be resized efficiently.
@@ -144,7 +156,7 @@ func (h *defaultEventHandle) AddEvent(ctx context.Context, event interface{}) er
 	select {
 	case <-ctx.Done():
 		return errors.Trace(ctx.Err())
-	case h.worker.taskCh <- task:
+	case h.taskCh <- task:
 	}
 	return nil
 }
@@ -208,7 +220,7 @@ func (h *defaultEventHandle) GracefulUnregister(ctx context.Context, timeout tim
 	select {
 	case <-ctx.Done():
 		return cerrors.ErrWorkerPoolGracefulUnregisterTimedOut.GenWithStackByArgs()
-	case h.worker.taskCh <- task{
+	case h.taskCh <- task{
 		handle: h,
 		doneCh: doneCh,
 	}:
@@ -292,7 +304,9 @@ type task struct {
 }
 
 type worker struct {
-	taskCh       chan task
+	// task channels of each priority, indexed by priority.Priority.Index()
+	taskChs      [priority.Num]chan task
+	picker       priority.Picker
 	handles      map[*defaultEventHandle]struct{}
 	handleRWLock sync.RWMutex
 	// A message is passed to handleCancelCh when we need to wait for the
@@ -308,14 +322,17 @@ type worker struct {
 }
 
 func newWorker() *worker {
-	return &worker{
-		taskCh:         make(chan task, 128),
+	w := &worker{
 		handles:        make(map[*defaultEventHandle]struct{}),
 		handleCancelCh: make(chan struct{}), // this channel must be unbuffered, i.e. blocking
 
 		slowSynchronizeThreshold: 10 * time.Second,
 		slowSynchronizeLimiter:   rate.NewLimiter(rate.Every(time.Second*5), 1),
 	}
+	for i := range w.taskChs {
+		w.taskChs[i] = make(chan task, 128)
+	}
+	return w
 }
 
 func (w *worker) run(ctx context.Context) error {
@@ -328,56 +345,99 @@ func (w *worker) run(ctx context.Context) error {
 	}()
 
 	for {
+		// Serve the clock and synchronization first, they must not be
+		// starved by tasks.
 		select {
 		case <-ctx.Done():
 			return errors.Trace(ctx.Err())
-		case task := <-w.taskCh:
-			if atomic.LoadInt32(&task.handle.status) == handleCancelled {
-				// ignored cancelled handle
-				continue
-			}
+		case <-ticker.C:
+			w.doTimers(ctx)
+			continue
+		case <-w.handleCancelCh:
+			continue
+		default:
+		}
 
-			if task.doneCh != nil {
-				close(task.doneCh)
-				if task.f != nil {
-					log.L().DPanic("unexpected message handler func in cancellation task", zap.Stack("stack"))
-				}
-				continue
+		// Poll pending tasks according to priorities.
+		polled := false
+		for _, idx := range w.picker.Next() {
+			select {
+			case task := <-w.taskChs[idx]:
+				w.doTask(ctx, task)
+				polled = true
+			default:
 			}
-
-			err := task.f(ctx)
-			if err != nil {
-				task.handle.cancelWithErr(err)
+			if polled {
+				break
 			}
+		}
+		if polled {
+			continue
+		}
+
+		// There is no pending task, wait for anything to happen.
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case task := <-w.taskChs[0]:
+			w.doTask(ctx, task)
+		case task := <-w.taskChs[1]:
+			w.doTask(ctx, task)
+		case task := <-w.taskChs[2]:
+			w.doTask(ctx, task)
 		case <-ticker.C:
-			var handleErrs []struct {
-				h *defaultEventHandle
-				e error
-			}
+			w.doTimers(ctx)
+		case <-w.handleCancelCh:
+		}
+	}
+}
 
-			w.handleRWLock.RLock()
-			for handle := range w.handles {
-				if atomic.LoadInt32(&handle.status) == handleCancelled {
-					// ignored cancelled handle
-					continue
-				}
-				err := handle.doTimer(ctx)
-				if err != nil {
-					handleErrs = append(handleErrs, struct {
-						h *defaultEventHandle
-						e error
-					}{handle, err})
-				}
-			}
-			w.handleRWLock.RUnlock()
+func (w *worker) doTask(ctx context.Context, task task) {
+	if atomic.LoadInt32(&task.handle.status) == handleCancelled {
+		// ignored cancelled handle
+		return
+	}
 
-			// cancelWithErr must be called out side of the loop above,
-			// to avoid deadlock.
-			for _, handleErr := range handleErrs {
-				handleErr.h.cancelWithErr(handleErr.e)
-			}
-		case <-w.handleCancelCh:
+	if task.doneCh != nil {
+		close(task.doneCh)
+		if task.f != nil {
+			log.L().DPanic("unexpected message handler func in cancellation task", zap.Stack("stack"))
 		}
+		return
+	}
+
+	err := task.f(ctx)
+	if err != nil {
+		task.handle.cancelWithErr(err)
+	}
+}
+
+func (w *worker) doTimers(ctx context.Context) {
+	var handleErrs []struct {
+		h *defaultEventHandle
+		e error
+	}
+
+	w.handleRWLock.RLock()
+	for handle := range w.handles {
+		if atomic.LoadInt32(&handle.status) == handleCancelled {
+			// ignored cancelled handle
+			continue
+		}
+		err := handle.doTimer(ctx)
+		if err != nil {
+			handleErrs = append(handleErrs, struct {
+				h *defaultEventHandle
+				e error
+			}{handle, err})
+		}
+	}
+	w.handleRWLock.RUnlock()
+
+	// cancelWithErr must be called out side of the loop above,
+	// to avoid deadlock.
+	for _, handleErr := range handleErrs {
+		handleErr.h.cancelWithErr(handleErr.e)
 	}
 }
 
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/priority"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -594,3 +595,66 @@ func BenchmarkWorkerpool(b *testing.B) {
 		<-ch
 	}
 }
+
+func TestPriority(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	pool := newDefaultPoolImpl(&defaultHasher{}, 1)
+	errg, ctx := errgroup.WithContext(ctx)
+	errg.Go(func() error {
+		return pool.Run(ctx)
+	})
+
+	blockCh := make(chan struct{})
+	blocker := pool.RegisterEvent(func(ctx context.Context, event interface{}) error {
+		<-blockCh
+		return nil
+	})
+	var mu sync.Mutex
+	var executed []priority.Priority
+	newHandle := func(p priority.Priority) EventHandle {
+		return pool.RegisterEventWithPriority(func(ctx context.Context, event interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			executed = append(executed, p)
+			return nil
+		}, p)
+	}
+	low := newHandle(priority.Low)
+	high := newHandle(priority.High)
+
+	// Block the only worker, so that all following events are pending.
+	require.Nil(t, blocker.AddEvent(ctx, 0))
+	for i := 0; i < 10; i++ {
+		require.Nil(t, low.AddEvent(ctx, i))
+	}
+	for i := 0; i < 30; i++ {
+		require.Nil(t, high.AddEvent(ctx, i))
+	}
+	close(blockCh)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(executed) == 40
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// High priority events are preferred, but low priority events are not starved.
+	highCount, lastLow := 0, 0
+	for i, p := range executed {
+		if i < 14 && p == priority.High {
+			highCount++
+		}
+		if p == priority.Low {
+			lastLow = i
+		}
+	}
+	require.GreaterOrEqual(t, highCount, 12)
+	require.Less(t, highCount, 14)
+	require.Equal(t, 39, lastLow)
+
+	cancel()
+	err := errg.Wait()
+	require.Regexp(t, "context canceled", err)
+}
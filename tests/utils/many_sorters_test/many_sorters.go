@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter"
+	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
@@ -81,7 +82,7 @@ func main() {
 	var finishCount int32
 	for i := 0; i < *numSorters; i++ {
 		sorters[i], err = unified.NewUnifiedSorter(*sorterDir,
-			"test-cf", fmt.Sprintf("test-%d", i), model.TableID(i))
+			"test-cf", fmt.Sprintf("test-%d", i), model.TableID(i), encoding.CodecNone)
 		if err != nil {
 			log.Panic("many_sorters", zap.Error(err))
 		}
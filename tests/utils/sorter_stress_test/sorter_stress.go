@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter"
+	"github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
 	"github.com/pingcap/tiflow/pkg/config"
 	"go.uber.org/zap"
@@ -66,7 +67,7 @@ func main() {
 		log.Error("sorter_stress_test:", zap.Error(err))
 	}
 
-	sorter, err := unified.NewUnifiedSorter(*sorterDir, "test-cf", "test", 0)
+	sorter, err := unified.NewUnifiedSorter(*sorterDir, "test-cf", "test", 0, encoding.CodecNone)
 	if err != nil {
 		log.Panic("sorter_stress_test:", zap.Error(err))
 	}